@@ -4,15 +4,21 @@
 package echo
 
 import (
+	"bytes"
 	"encoding"
-	"encoding/xml"
+	"encoding/base64"
+	"encoding/gob"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Binder is the interface that wraps the Bind method.
@@ -20,8 +26,338 @@ type Binder interface {
 	Bind(i interface{}, c Context) error
 }
 
+// SingleSourceBinder is the interface implemented by Binders that are able to bind data from single source of the
+// request. It is used by `Context#BindQuery`, `Context#BindForm`, `Context#BindHeaders` and `Context#BindBody`. When
+// the configured `Echo#Binder` does not implement it these methods return ErrBinderNotSingleSource.
+type SingleSourceBinder interface {
+	BindPathParams(c Context, i interface{}) error
+	BindQueryParams(c Context, i interface{}) error
+	BindHeaders(c Context, i interface{}) error
+	BindForm(c Context, i interface{}) error
+	BindBody(c Context, i interface{}) error
+}
+
 // DefaultBinder is the default implementation of the Binder interface.
-type DefaultBinder struct{}
+//
+// Field with `rest` tag modifier and without name (ala `query:",rest"`) receives keys of the source
+// (query, form, headers, path or matrix params) that were not bound to other fields, so known keys can be validated
+// strictly while the rest is forwarded untouched. All present alternative names of the field and keys matched
+// case-insensitively count as bound. Values are not trimmed. Field type must be `map[string][]string` (ala
+// `url.Values` or `http.Header`).
+type DefaultBinder struct {
+	// FallbackToJSONTag makes binding of path params, query params, headers and form fields use the `json` tag name
+	// of the field when the source specific tag (`param`, `query`, `header`, `form`) is absent. Fields with `json:"-"`
+	// are not bound. Field names are resolved in order: explicit source tag > json tag. Each name is matched first
+	// exactly and then case-insensitively against the source keys.
+	FallbackToJSONTag bool
+
+	// DisableFallbackBinding turns off all binding fallbacks for path params, query params, headers and form fields so
+	// only fields with explicit source tag are bound from keys that match the tag exactly (allow-list binding).
+	// `FallbackToJSONTag` and case-insensitive matching of keys (except header names) are not applied.
+	DisableFallbackBinding bool
+
+	// DetailedFieldErrors makes message of "400 - Bad Request" errors, caused by request values that could not be
+	// converted to the field type, contain source, name and value of the parameter (ala
+	// `failed to bind path param "id" (value "abc"): strconv.ParseInt: parsing "abc": invalid syntax`).
+	// `BindFieldError` is always available in the `HTTPError.Internal` chain regardless of this option.
+	DetailedFieldErrors bool
+
+	// EnableGob makes BindBody decode `application/x-gob` bodies with `encoding/gob`. Gob decoder is not hardened
+	// against adversarial input, enable it only for endpoints that receive bodies from trusted clients. By default
+	// gob bodies result "415 - Unsupported Media Type" error.
+	EnableGob bool
+
+	// MaxRawBodySize is maximum number of bytes read when request body is bound to `[]byte` destination or read to
+	// find discriminators of polymorphic fields (see `RegisterPolymorphicBinding`). Bigger bodies result
+	// "413 - Request Entity Too Large" error. Zero value means 32 MB.
+	MaxRawBodySize int64
+
+	// NameTransform makes binding of path params, query params, headers and form fields use name of the struct field,
+	// transformed by this function, for fields without source specific tag (and without `json` tag when
+	// `FallbackToJSONTag` is set). Use `SnakeCaseName` to bind `UserID` field from `user_id` query param without tags.
+	// Explicit tags always win. Not applied when `DisableFallbackBinding` is set.
+	// Optional. Default value nil means that fields without tags are not bound.
+	NameTransform func(fieldName string) string
+
+	// TrimSpace makes binding of path params, query params, headers and form fields remove leading and trailing white
+	// space from values (each value for slices) before they are converted to the field type or passed to
+	// BindUnmarshaler. Fields can opt out with `notrim` tag modifier (ala `form:"password,notrim"`) or opt in, when
+	// this option is off, with `trim` modifier (ala `query:"id,trim"`). Body (JSON, XML) binding is not affected.
+	TrimSpace bool
+
+	// LenientBool makes binding of path params, query params, headers and form fields to bool fields (and pointers or
+	// slices of bools) accept `on`/`off`, `yes`/`no` and `y`/`n` (case-insensitively) in addition to values accepted by
+	// `strconv.ParseBool`, and treat key without value (ala `?flag` or `flag=`) as true. Absent key leaves the field
+	// false. Fields can opt in, when this option is off, with `lenient` tag modifier (ala `form:"subscribe,lenient"`).
+	LenientBool bool
+
+	// MaxSliceLength is maximum number of values bound to slice field (and to types implementing
+	// `UnmarshalParams`) from path params, query params, headers and form fields, including multipart files. Keys
+	// with more values result "400 - Bad Request" error before the slice is allocated. Fields can override it with
+	// `max` tag modifier (ala `query:"ids,max=50"`). Zero value means 1024. Negative value disables the limit.
+	MaxSliceLength int
+
+	// LenientXMLCharset makes binding of XML bodies accept documents declared as ISO-8859-1 (latin-1) or Windows-1252
+	// encoded (ala `<?xml version="1.0" encoding="windows-1252"?>`) by converting them to UTF-8. By default such
+	// documents result "400 - Bad Request" error.
+	LenientXMLCharset bool
+
+	// LegacyMapBinding restores binding of map destinations as it was before keys and values were converted: only
+	// `map[string]string`, `map[string][]string` and `map[string]interface{}` (and maps with named string keys of
+	// these value types) are bound and other maps are skipped silently.
+	LegacyMapBinding bool
+
+	// WildcardParamName is additional name the wildcard (`*`) path param value is bound from by `BindPathParams`, so
+	// the capture can be bound with `param:"path"` in addition to `param:"*"`. Named path param of the route with the
+	// same name takes precedence over the wildcard value.
+	// Optional. Default value "" means the wildcard is bound only with `param:"*"`.
+	WildcardParamName string
+
+	// AliasConflict defines how field with alternative names (ala `query:"q|query"`) is bound when more than one of
+	// the names is present with different values.
+	// Optional. Default value AliasFirstWins binds values of the first present name in tag order.
+	AliasConflict AliasConflictMode
+
+	// OnBindError is called when binding fails, once for every field that could not be bound (every missing field of
+	// `RequiredFieldsError`). Source is "path", "matrix", "query", "header" or "form" and field is the parameter name for field
+	// errors. Errors of decoding the body (ala malformed JSON) have source "body" and empty field. Use it to collect
+	// metrics of malformed input (see `middleware.InstrumentBinder`).
+	// Optional.
+	OnBindError func(c Context, source string, field string, err error)
+
+	// OnBindComplete is called when Bind, BindPathParams, BindMatrixParams, BindQueryParams, BindBody, BindForm or BindHeaders returns
+	// with time the binding took.
+	// Optional.
+	OnBindComplete func(c Context, duration time.Duration)
+
+	// polymorphic holds registrations of `RegisterPolymorphicBinding`. It is pointer so copies of the binder share
+	// registrations and can be copied freely.
+	polymorphic *polymorphicRegistry
+}
+
+// SnakeCaseName converts Go field name to snake_case (`UserID` -> `user_id`, `HTTPServer` -> `http_server`).
+// Can be used as `DefaultBinder.NameTransform`.
+func SnakeCaseName(fieldName string) string {
+	return separateWords(fieldName, '_')
+}
+
+// KebabCaseName converts Go field name to kebab-case (`UserID` -> `user-id`, `HTTPServer` -> `http-server`).
+// Can be used as `DefaultBinder.NameTransform`.
+func KebabCaseName(fieldName string) string {
+	return separateWords(fieldName, '-')
+}
+
+// separateWords lower cases name and puts separator between words. Word starts at upper case letter that follows lower
+// case letter or digit, and at last upper case letter of acronym that is followed by lower case letter.
+func separateWords(name string, separator byte) string {
+	var sb strings.Builder
+	sb.Grow(len(name) + 4)
+	for i := 0; i < len(name); i++ {
+		ch := name[i]
+		if ch < 'A' || ch > 'Z' {
+			sb.WriteByte(ch)
+			continue
+		}
+		if i > 0 {
+			prev := name[i-1]
+			prevIsUpper := prev >= 'A' && prev <= 'Z'
+			nextIsLower := i+1 < len(name) && name[i+1] >= 'a' && name[i+1] <= 'z'
+			if (!prevIsUpper && prev != '_') || (prevIsUpper && nextIsLower) {
+				sb.WriteByte(separator)
+			}
+		}
+		sb.WriteByte(ch + ('a' - 'A'))
+	}
+	return sb.String()
+}
+
+// defaultMaxRawBodySize is default value of `DefaultBinder.MaxRawBodySize`.
+const defaultMaxRawBodySize = 32 << 20
+
+// defaultMaxSliceLength is default value of `DefaultBinder.MaxSliceLength`.
+const defaultMaxSliceLength = 1024
+
+// maxBindFieldErrorValueLength is maximum length of the raw value stored in BindFieldError.
+const maxBindFieldErrorValueLength = 64
+
+// BindFieldError is returned (wrapped into `HTTPError.Internal`) when request value could not be converted to the
+// type of the struct field. Use `errors.As` to get it.
+type BindFieldError struct {
+	// Source is where the value came from: "path", "matrix", "query", "form" or "header".
+	Source string `json:"source"`
+	// Name is name of the parameter (field tag name).
+	Name string `json:"name"`
+	// Value is raw value of the parameter. Long values are truncated.
+	Value string `json:"value"`
+	// Err is the conversion error.
+	Err error `json:"-"`
+}
+
+func newBindFieldError(tag string, name string, value string, err error) *BindFieldError {
+	source := bindFieldSource(tag)
+	if len(value) > maxBindFieldErrorValueLength {
+		value = value[:maxBindFieldErrorValueLength] + "..."
+	}
+	return &BindFieldError{Source: source, Name: name, Value: value, Err: err}
+}
+
+// bindFieldSource returns BindFieldError source for the struct tag name.
+func bindFieldSource(tag string) string {
+	if tag == "param" {
+		return "path"
+	}
+	return tag
+}
+
+// Error returns message of the conversion error.
+func (e *BindFieldError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the conversion error.
+func (e *BindFieldError) Unwrap() error {
+	return e.Err
+}
+
+// ErrRequiredFieldMissing is the error of BindFieldError for field with `required` tag modifier (ala
+// `query:"page,required"`) when its key is absent from the request.
+var ErrRequiredFieldMissing = errors.New("required field is missing")
+
+// ErrAliasConflict is the error of BindFieldError when more than one of alternative names of the field is present
+// with different values and `DefaultBinder.AliasConflict` is AliasConflictError.
+var ErrAliasConflict = errors.New("conflicting values of alternative names")
+
+// AliasConflictMode defines how DefaultBinder binds field with alternative names (ala `query:"q|query"`) when more
+// than one of the names is present.
+type AliasConflictMode int
+
+const (
+	// AliasFirstWins binds values of the first present name in tag order.
+	AliasFirstWins AliasConflictMode = iota
+	// AliasConflictError results "400 - Bad Request" error with BindFieldError wrapping ErrAliasConflict when present
+	// names have different values. Names with equal values are not a conflict.
+	AliasConflictError
+)
+
+// ErrTooManyValues is the error of BindFieldError when key has more values than slice field allows (see
+// `DefaultBinder.MaxSliceLength`).
+var ErrTooManyValues = errors.New("too many values")
+
+// RequiredFieldsError is returned (wrapped into `HTTPError.Internal`) when keys of fields with `required` tag modifier
+// are absent from the request. Fields lists all missing fields, each with ErrRequiredFieldMissing as its error.
+type RequiredFieldsError struct {
+	// Fields are the missing fields in the order they were found.
+	Fields []*BindFieldError `json:"fields"`
+}
+
+// Error returns message naming source and key of each missing field.
+func (e *RequiredFieldsError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("missing required ")
+	for i, f := range e.Fields {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "%s param %q", f.Source, f.Name)
+	}
+	return sb.String()
+}
+
+// Unwrap returns the field errors so `errors.As` and `errors.Is` can match them.
+func (e *RequiredFieldsError) Unwrap() []error {
+	errs := make([]error, len(e.Fields))
+	for i, f := range e.Fields {
+		errs[i] = f
+	}
+	return errs
+}
+
+// missingField is field with `required` tag modifier whose key was absent from the bound source.
+type missingField struct {
+	err   *BindFieldError
+	field reflect.Value
+	// bodyBindable is true when the field has `json`, `xml` or `form` tag so it can still be bound from the body.
+	bodyBindable bool
+}
+
+func newRequiredFieldsError(missing []missingField) *RequiredFieldsError {
+	fields := make([]*BindFieldError, len(missing))
+	for i, m := range missing {
+		fields[i] = m.err
+	}
+	return &RequiredFieldsError{Fields: fields}
+}
+
+// UnsupportedMediaTypeError is returned (wrapped into `HTTPError.Internal`) when request body has content type that
+// the Binder does not support. It matches `ErrUnsupportedMediaType` with `errors.Is`.
+type UnsupportedMediaTypeError struct {
+	// ContentType is `Content-Type` header value of the request.
+	ContentType string `json:"content_type"`
+	// Supported is list of media types the Binder supports.
+	Supported []string `json:"supported"`
+}
+
+// Error returns error message.
+func (e *UnsupportedMediaTypeError) Error() string {
+	return fmt.Sprintf("unsupported media type %q", e.ContentType)
+}
+
+// Unwrap returns ErrUnsupportedMediaType.
+func (e *UnsupportedMediaTypeError) Unwrap() error {
+	return ErrUnsupportedMediaType
+}
+
+var (
+	bodyMediaTypes    = []string{MIMEApplicationJSON, MIMEApplicationXML, MIMETextXML, MIMEApplicationForm, MIMEMultipartForm}
+	bodyMediaTypesGob = append(append([]string(nil), bodyMediaTypes...), MIMEApplicationGob)
+	formMediaTypes    = []string{MIMEApplicationForm, MIMEMultipartForm}
+)
+
+// newUnsupportedMediaTypeError creates "415 - Unsupported Media Type" error with received and supported media types.
+func newUnsupportedMediaTypeError(contentType string, supported []string) *HTTPError {
+	message := fmt.Sprintf("%s: received %q, supported: %s", http.StatusText(http.StatusUnsupportedMediaType),
+		contentType, strings.Join(supported, ", "))
+	return NewHTTPError(http.StatusUnsupportedMediaType, message).
+		SetInternal(&UnsupportedMediaTypeError{ContentType: contentType, Supported: supported})
+}
+
+// bindDataError converts error returned by bindData to "400 - Bad Request" error.
+func (b *DefaultBinder) bindDataError(err error) *HTTPError {
+	message := err.Error()
+	var fieldErr *BindFieldError
+	var requiredErr *RequiredFieldsError
+	if b.DetailedFieldErrors && !errors.As(err, &requiredErr) && errors.As(err, &fieldErr) {
+		message = fmt.Sprintf("failed to bind %s param %q (value %q): %v", fieldErr.Source, fieldErr.Name, fieldErr.Value, fieldErr.Err)
+	}
+	return NewHTTPError(http.StatusBadRequest, message).SetInternal(err)
+}
+
+// observe calls OnBindError and OnBindComplete hooks with result of binding. Errors without field information are
+// reported with given source.
+func (b *DefaultBinder) observe(c Context, start time.Time, err *error, source string) {
+	if *err != nil && b.OnBindError != nil {
+		b.reportBindError(c, *err, source)
+	}
+	if b.OnBindComplete != nil {
+		b.OnBindComplete(c, time.Since(start))
+	}
+}
+
+func (b *DefaultBinder) reportBindError(c Context, err error, source string) {
+	var requiredErr *RequiredFieldsError
+	if errors.As(err, &requiredErr) {
+		for _, fieldErr := range requiredErr.Fields {
+			b.OnBindError(c, fieldErr.Source, fieldErr.Name, fieldErr)
+		}
+		return
+	}
+	var fieldErr *BindFieldError
+	if errors.As(err, &fieldErr) {
+		b.OnBindError(c, fieldErr.Source, fieldErr.Name, err)
+		return
+	}
+	b.OnBindError(c, source, "", err)
+}
 
 // BindUnmarshaler is the interface used to wrap the UnmarshalParam method.
 // Types that don't implement this, but do implement encoding.TextUnmarshaler
@@ -38,24 +374,63 @@ type bindMultipleUnmarshaler interface {
 	UnmarshalParams(params []string) error
 }
 
+// BindCompositeUnmarshaler is the interface implemented by types that are bound from multiple request keys (ala
+// `TimeRange` bound from `created_from` and `created_to` query params). Fields with explicit tag receive the tag name,
+// embedded fields without tag receive empty name and use their default keys. Modifiers are the rest of the tag after
+// the name (ala `layout=2006-01-02` in `query:"created,layout=2006-01-02"`) and values looks up values of given key.
+// Returned `*BindFieldError` is passed to the caller with Source filled, other errors are wrapped into one.
+type BindCompositeUnmarshaler interface {
+	UnmarshalParamsComposite(name string, modifiers string, values func(key string) ([]string, bool)) error
+}
+
 // BindPathParams binds path params to bindable object
-func (b *DefaultBinder) BindPathParams(c Context, i interface{}) error {
+func (b *DefaultBinder) BindPathParams(c Context, i interface{}) (err error) {
+	if b.OnBindError != nil || b.OnBindComplete != nil {
+		defer b.observe(c, time.Now(), &err, "path")
+	}
+	if err := b.bindData(i, b.pathParamsData(c), "param", nil); err != nil {
+		return b.bindDataError(err)
+	}
+	return nil
+}
+
+// BindMatrixParams binds matrix parameters of path segments (see `Echo#MatrixParams`) to bindable object. Fields are
+// bound with `matrix` tag (ala `matrix:"role"`). Parameters of the same name from different segments are bound as
+// multiple values.
+func (b *DefaultBinder) BindMatrixParams(c Context, i interface{}) (err error) {
+	if b.OnBindError != nil || b.OnBindComplete != nil {
+		defer b.observe(c, time.Now(), &err, "matrix")
+	}
+	if err := b.bindData(i, c.MatrixParams(), "matrix", nil); err != nil {
+		return b.bindDataError(err)
+	}
+	return nil
+}
+
+// pathParamsData returns path params of the request as binding data. Wildcard value is also available under
+// WildcardParamName.
+func (b *DefaultBinder) pathParamsData(c ParamReader) map[string][]string {
 	names := c.ParamNames()
 	values := c.ParamValues()
 	params := map[string][]string{}
 	for i, name := range names {
 		params[name] = []string{values[i]}
 	}
-	if err := b.bindData(i, params, "param", nil); err != nil {
-		return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	if wildcard, ok := params["*"]; ok && b.WildcardParamName != "" {
+		if _, exists := params[b.WildcardParamName]; !exists {
+			params[b.WildcardParamName] = wildcard
+		}
 	}
-	return nil
+	return params
 }
 
 // BindQueryParams binds query params to bindable object
-func (b *DefaultBinder) BindQueryParams(c Context, i interface{}) error {
+func (b *DefaultBinder) BindQueryParams(c Context, i interface{}) (err error) {
+	if b.OnBindError != nil || b.OnBindComplete != nil {
+		defer b.observe(c, time.Now(), &err, "query")
+	}
 	if err := b.bindData(i, c.QueryParams(), "query", nil); err != nil {
-		return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		return b.bindDataError(err)
 	}
 	return nil
 }
@@ -65,11 +440,23 @@ func (b *DefaultBinder) BindQueryParams(c Context, i interface{}) error {
 // which parses form data from BOTH URL and BODY if content type is not MIMEMultipartForm
 // See non-MIMEMultipartForm: https://golang.org/pkg/net/http/#Request.ParseForm
 // See MIMEMultipartForm: https://golang.org/pkg/net/http/#Request.ParseMultipartForm
+// When destination is `*[]byte` whole body is read into it regardless of the content type (see `MaxRawBodySize`).
+// `application/x-gob` bodies are decoded with `encoding/gob` when `EnableGob` is set.
 func (b *DefaultBinder) BindBody(c Context, i interface{}) (err error) {
+	if b.OnBindError != nil || b.OnBindComplete != nil {
+		defer b.observe(c, time.Now(), &err, "body")
+	}
+	return b.bindBody(c, i)
+}
+
+func (b *DefaultBinder) bindBody(c Context, i interface{}) (err error) {
 	req := c.Request()
 	if req.ContentLength == 0 {
 		return
 	}
+	if isRawBodyDestination(i) {
+		return b.bindRawBody(req, i)
+	}
 
 	// mediatype is found like `mime.ParseMediaType()` does it
 	base, _, _ := strings.Cut(req.Header.Get(HeaderContentType), ";")
@@ -77,6 +464,9 @@ func (b *DefaultBinder) BindBody(c Context, i interface{}) (err error) {
 
 	switch mediatype {
 	case MIMEApplicationJSON:
+		if err = b.bindPolymorphicFields(req, i); err != nil {
+			return err
+		}
 		if err = c.Echo().JSONSerializer.Deserialize(c, i); err != nil {
 			switch err.(type) {
 			case *HTTPError:
@@ -86,106 +476,861 @@ func (b *DefaultBinder) BindBody(c Context, i interface{}) (err error) {
 			}
 		}
 	case MIMEApplicationXML, MIMETextXML:
-		if err = xml.NewDecoder(req.Body).Decode(i); err != nil {
-			if ute, ok := err.(*xml.UnsupportedTypeError); ok {
-				return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported type error: type=%v, error=%v", ute.Type, ute.Error())).SetInternal(err)
-			} else if se, ok := err.(*xml.SyntaxError); ok {
-				return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Syntax error: line=%v, error=%v", se.Line, se.Error())).SetInternal(err)
+		if err = b.bindXML(req.Body, i); err != nil {
+			return err
+		}
+	case MIMEApplicationGob:
+		if !b.EnableGob {
+			return newUnsupportedMediaTypeError(req.Header.Get(HeaderContentType), bodyMediaTypes)
+		}
+		if err = gob.NewDecoder(req.Body).Decode(i); err != nil {
+			var he *HTTPError
+			if errors.As(err, &he) {
+				return he
 			}
 			return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
 	case MIMEApplicationForm:
 		params, err := c.FormParams()
 		if err != nil {
-			return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			return newFormParseError(err)
 		}
 		if err = b.bindData(i, params, "form", nil); err != nil {
-			return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			return b.bindDataError(err)
 		}
 	case MIMEMultipartForm:
 		params, err := c.MultipartForm()
 		if err != nil {
-			return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			return newFormParseError(err)
 		}
 		if err = b.bindData(i, params.Value, "form", params.File); err != nil {
-			return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+			return b.bindDataError(err)
+		}
+	default:
+		if b.EnableGob {
+			return newUnsupportedMediaTypeError(req.Header.Get(HeaderContentType), bodyMediaTypesGob)
+		}
+		return newUnsupportedMediaTypeError(req.Header.Get(HeaderContentType), bodyMediaTypes)
+	}
+	return nil
+}
+
+// isRawBodyDestination returns true when destination is pointer to `[]byte` or to type with `[]byte` underlying type.
+func isRawBodyDestination(i interface{}) bool {
+	v := reflect.ValueOf(i)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return false
+	}
+	t := v.Type().Elem()
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+// bindRawBody reads the whole request body into `[]byte` destination regardless of the content type. Request body is
+// replaced with the read bytes so the body can be read again.
+func (b *DefaultBinder) bindRawBody(req *http.Request, i interface{}) error {
+	data, err := b.readBody(req)
+	if err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(i).Elem()
+	v.Set(reflect.ValueOf(data).Convert(v.Type()))
+	return nil
+}
+
+// readBody reads the whole request body, up to MaxRawBodySize bytes, and replaces the request body with the read bytes
+// so the body can be read again.
+func (b *DefaultBinder) readBody(req *http.Request) ([]byte, error) {
+	limit := b.MaxRawBodySize
+	if limit <= 0 {
+		limit = defaultMaxRawBodySize
+	}
+	data, err := io.ReadAll(io.LimitReader(req.Body, limit+1))
+	if err != nil {
+		var he *HTTPError
+		if errors.As(err, &he) {
+			return nil, he
+		}
+		return nil, NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	if int64(len(data)) > limit {
+		return nil, ErrStatusRequestEntityTooLarge
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// BindForm binds form fields from the request body to bindable object. Unlike BindBody it binds only
+// `application/x-www-form-urlencoded` and `multipart/form-data` bodies and URL query parameters are never included.
+func (b *DefaultBinder) BindForm(c Context, i interface{}) (err error) {
+	if b.OnBindError != nil || b.OnBindComplete != nil {
+		defer b.observe(c, time.Now(), &err, "form")
+	}
+	req := c.Request()
+	base, _, _ := strings.Cut(req.Header.Get(HeaderContentType), ";")
+	mediatype := strings.TrimSpace(base)
+
+	switch mediatype {
+	case MIMEApplicationForm:
+		if _, err := c.FormParams(); err != nil {
+			return newFormParseError(err)
+		}
+		if err := b.bindData(i, req.PostForm, "form", nil); err != nil {
+			return b.bindDataError(err)
+		}
+	case MIMEMultipartForm:
+		params, err := c.MultipartForm()
+		if err != nil {
+			return newFormParseError(err)
+		}
+		if err = b.bindData(i, params.Value, "form", params.File); err != nil {
+			return b.bindDataError(err)
 		}
 	default:
-		return ErrUnsupportedMediaType
+		return newUnsupportedMediaTypeError(req.Header.Get(HeaderContentType), formMediaTypes)
 	}
 	return nil
 }
 
+// newFormParseError returns HTTPError of aborted form parsing (ala "408 - Request Timeout") as is and wraps other
+// parse errors into "400 - Bad Request" error.
+func newFormParseError(err error) error {
+	if he, ok := err.(*HTTPError); ok {
+		return he
+	}
+	return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+}
+
 // BindHeaders binds HTTP headers to a bindable object
-func (b *DefaultBinder) BindHeaders(c Context, i interface{}) error {
+func (b *DefaultBinder) BindHeaders(c Context, i interface{}) (err error) {
+	if b.OnBindError != nil || b.OnBindComplete != nil {
+		defer b.observe(c, time.Now(), &err, "header")
+	}
 	if err := b.bindData(i, c.Request().Header, "header", nil); err != nil {
-		return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		return b.bindDataError(err)
 	}
 	return nil
 }
 
 // Bind implements the `Binder#Bind` function.
-// Binding is done in following order: 1) path params; 2) query params; 3) request body. Each step COULD override previous
-// step binded values. For single source binding use their own methods BindBody, BindQueryParams, BindPathParams.
+// Binding is done in following order: 1) path params (and matrix params when `Echo#MatrixParams` is enabled); 2) query
+// params; 3) request body. Each step COULD override previous step binded values. For single source binding use their
+// own methods BindBody, BindQueryParams, BindPathParams.
+// Path and query fields with `required` tag modifier are checked before the body is read. A missing field that has also
+// `json`, `xml` or `form` tag is satisfied when the body sets it to non-zero value.
 func (b *DefaultBinder) Bind(i interface{}, c Context) (err error) {
-	if err := b.BindPathParams(c, i); err != nil {
-		return err
+	if b.OnBindError != nil || b.OnBindComplete != nil {
+		defer b.observe(c, time.Now(), &err, "body")
+	}
+	missing, err := b.bindDataMissing(i, b.pathParamsData(c), "param", nil)
+	if err != nil {
+		return b.bindDataError(err)
+	}
+	if e := c.Echo(); e != nil && e.MatrixParams {
+		matrixMissing, err := b.bindDataMissing(i, c.MatrixParams(), "matrix", nil)
+		if err != nil {
+			return b.bindDataError(err)
+		}
+		missing = append(missing, matrixMissing...)
 	}
 	// Only bind query parameters for GET/DELETE/HEAD to avoid unexpected behavior with destination struct binding from body.
 	// For example a request URL `&id=1&lang=en` with body `{"id":100,"lang":"de"}` would lead to precedence issues.
 	// The HTTP method check restores pre-v4.1.11 behavior to avoid these problems (see issue #1670)
 	method := c.Request().Method
 	if method == http.MethodGet || method == http.MethodDelete || method == http.MethodHead {
-		if err = b.BindQueryParams(c, i); err != nil {
-			return err
+		queryMissing, err := b.bindDataMissing(i, c.QueryParams(), "query", nil)
+		if err != nil {
+			return b.bindDataError(err)
 		}
+		missing = append(missing, queryMissing...)
 	}
-	return b.BindBody(c, i)
+
+	var deferred, unsatisfied []missingField
+	for _, m := range missing {
+		if m.bodyBindable {
+			deferred = append(deferred, m)
+		} else {
+			unsatisfied = append(unsatisfied, m)
+		}
+	}
+	if len(unsatisfied) > 0 {
+		return b.bindDataError(newRequiredFieldsError(unsatisfied))
+	}
+
+	if err := b.bindBody(c, i); err != nil {
+		return err
+	}
+	for _, m := range deferred {
+		if m.field.IsZero() {
+			unsatisfied = append(unsatisfied, m)
+		}
+	}
+	if len(unsatisfied) > 0 {
+		return b.bindDataError(newRequiredFieldsError(unsatisfied))
+	}
+	return nil
 }
 
-// bindData will bind data ONLY fields in destination struct that have EXPLICIT tag
-func (b *DefaultBinder) bindData(destination interface{}, data map[string][]string, tag string, dataFiles map[string][]*multipart.FileHeader) error {
-	if destination == nil || (len(data) == 0 && len(dataFiles) == 0) {
+// jsonTagName returns name from the `json` tag of the field. Skip is true for fields with `json:"-"` tag.
+func jsonTagName(field reflect.StructField) (name string, skip bool) {
+	name, _, _ = strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return "", true
+	}
+	return name, false
+}
+
+// shouldTrim returns true when values of the field with given tag modifiers (ala `trim` in `query:"id,trim"`) are
+// trimmed before binding.
+func (b *DefaultBinder) shouldTrim(tagModifiers string) bool {
+	trim := b.TrimSpace
+	for tagModifiers != "" {
+		var modifier string
+		modifier, tagModifiers, _ = strings.Cut(tagModifiers, ",")
+		switch strings.TrimSpace(modifier) {
+		case "trim":
+			trim = true
+		case "notrim":
+			return false
+		}
+	}
+	return trim
+}
+
+// isRequired returns true when tag modifiers contain `required` (ala `query:"page,required"`).
+func isRequired(tagModifiers string) bool {
+	return hasTagModifier(tagModifiers, "required")
+}
+
+// isRest returns true when tag modifiers contain `rest` (ala `query:",rest"`).
+func isRest(tagModifiers string) bool {
+	return hasTagModifier(tagModifiers, "rest")
+}
+
+func hasTagModifier(tagModifiers string, name string) bool {
+	for tagModifiers != "" {
+		var modifier string
+		modifier, tagModifiers, _ = strings.Cut(tagModifiers, ",")
+		if strings.TrimSpace(modifier) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// maxSliceLength returns maximum number of values of slice field with given tag modifiers. Value of `max` modifier (ala
+// `query:"ids,max=50"`) wins over `MaxSliceLength`. Zero or negative result means no limit.
+func (b *DefaultBinder) maxSliceLength(tagModifiers string) (int, error) {
+	for tagModifiers != "" {
+		var modifier string
+		modifier, tagModifiers, _ = strings.Cut(tagModifiers, ",")
+		if value, ok := strings.CutPrefix(strings.TrimSpace(modifier), "max="); ok {
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit <= 0 {
+				return 0, fmt.Errorf("invalid tag modifier %q", modifier)
+			}
+			return limit, nil
+		}
+	}
+	if b.MaxSliceLength == 0 {
+		return defaultMaxSliceLength, nil
+	}
+	return b.MaxSliceLength, nil
+}
+
+// checkSliceLength returns BindFieldError wrapping ErrTooManyValues when count of values exceeds limit of the field.
+// Value is the first value of the key so the error does not copy all of them.
+func (b *DefaultBinder) checkSliceLength(tag string, name string, tagModifiers string, count int, value string) error {
+	limit, err := b.maxSliceLength(tagModifiers)
+	if err != nil {
+		return fmt.Errorf("field %q: %w", name, err)
+	}
+	if limit > 0 && count > limit {
+		return newBindFieldError(tag, name, value, fmt.Errorf("%w for field %q: got %d, max %d", ErrTooManyValues, name, count, limit))
+	}
+	return nil
+}
+
+// isMultiValueBindType returns true when field of type t receives all values of the key: slices (and pointers to them)
+// and types implementing `UnmarshalParams`.
+func isMultiValueBindType(t reflect.Type) bool {
+	if isMultiValueUnmarshalerType(t) {
+		return true
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Slice
+}
+
+// isBodyBindableField returns true when field has `json`, `xml` or `form` tag and could be bound from the body.
+func isBodyBindableField(field reflect.StructField) bool {
+	for _, tag := range []string{"json", "xml", "form"} {
+		if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name != "" && name != "-" {
+			return true
+		}
+	}
+	return false
+}
+
+type modifierFieldsKey struct {
+	typ      reflect.Type
+	tag      string
+	modifier string
+}
+
+// modifierFieldsCache caches result of hasModifierFields for destination type, tag and modifier.
+var modifierFieldsCache sync.Map // modifierFieldsKey -> bool
+
+// hasRequiredFields returns true when struct type t (or struct nested in it) has field with `required` tag modifier
+// for the given tag. Destinations with such fields are walked even when the source has no data.
+func hasRequiredFields(t reflect.Type, tag string) bool {
+	return hasModifierFields(t, tag, "required")
+}
+
+// hasModifierFields returns true when struct type t (or struct nested in it) has field with given tag modifier for the
+// given tag.
+func hasModifierFields(t reflect.Type, tag string, modifier string) bool {
+	key := modifierFieldsKey{typ: t, tag: tag, modifier: modifier}
+	if v, ok := modifierFieldsCache.Load(key); ok {
+		return v.(bool)
+	}
+	result := hasModifierFieldsNested(t, tag, modifier, 0)
+	modifierFieldsCache.Store(key, result)
+	return result
+}
+
+func hasModifierFieldsNested(t reflect.Type, tag string, modifier string, depth int) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || depth > maxBindNestingDepth {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, tagModifiers, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			continue
+		}
+		if hasTagModifier(tagModifiers, modifier) {
+			return true
+		}
+		if name == "" && hasModifierFieldsNested(field.Type, tag, modifier, depth+1) {
+			return true
+		}
+	}
+	return false
+}
+
+// isLenientBool returns true when values of bool field with given tag modifiers are converted with lenientBoolValues.
+func (b *DefaultBinder) isLenientBool(tagModifiers string) bool {
+	if b.LenientBool {
+		return true
+	}
+	for tagModifiers != "" {
+		var modifier string
+		modifier, tagModifiers, _ = strings.Cut(tagModifiers, ",")
+		if strings.TrimSpace(modifier) == "lenient" {
+			return true
+		}
+	}
+	return false
+}
+
+// isBoolBindType returns true for bool, pointer to bool and slice of these types that are not bound by unmarshalers.
+func isBoolBindType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Slice && !isMultiValueUnmarshalerType(t) {
+		t = t.Elem()
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	if t.Kind() != reflect.Bool {
+		return false
+	}
+	ptr := reflect.PointerTo(t)
+	return !ptr.Implements(bindUnmarshalerType) && !ptr.Implements(textUnmarshalerType)
+}
+
+// lenientBoolValues returns copy of values where boolean-ish words (`on`, `yes`, `y`, empty value and their
+// negatives) are replaced with values understood by `strconv.ParseBool`. Other values are kept as they are.
+func lenientBoolValues(values []string) []string {
+	result := make([]string, len(values))
+	for i, v := range values {
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "", "on", "yes", "y":
+			result[i] = "true"
+		case "off", "no", "n":
+			result[i] = "false"
+		default:
+			result[i] = v
+		}
+	}
+	return result
+}
+
+// bytesEncoding returns encoding given with tag modifiers (`base64`, `base64url` or `hex` ala `query:"cursor,base64url"`)
+// that values of binary fields are decoded from. Returns empty string when values are bound as they are.
+func bytesEncoding(tagModifiers string) string {
+	for tagModifiers != "" {
+		var modifier string
+		modifier, tagModifiers, _ = strings.Cut(tagModifiers, ",")
+		switch modifier = strings.TrimSpace(modifier); modifier {
+		case "base64", "base64url", "hex":
+			return modifier
+		}
+	}
+	return ""
+}
+
+// decodeBytes decodes value with encoding returned by bytesEncoding. `base64url` values are accepted with and without
+// padding.
+func decodeBytes(encoding string, value string) ([]byte, error) {
+	switch encoding {
+	case "base64":
+		return base64.StdEncoding.DecodeString(value)
+	case "base64url":
+		return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	default:
+		return hex.DecodeString(value)
+	}
+}
+
+// setEncodedBytesField decodes values into `[]byte`, `[N]byte`, pointer to these or slice of these field.
+func setEncodedBytesField(field reflect.Value, encoding string, values []string) (string, error) {
+	t := field.Type()
+	if t.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(t.Elem()))
+		}
+		field = field.Elem()
+		t = t.Elem()
+	}
+	if isBytesType(t) {
+		return values[0], setDecodedBytes(field, encoding, values[0])
+	}
+	if t.Kind() == reflect.Slice && isBytesType(t.Elem()) {
+		slice := reflect.MakeSlice(t, len(values), len(values))
+		for i, v := range values {
+			if err := setDecodedBytes(slice.Index(i), encoding, v); err != nil {
+				return v, err
+			}
+		}
+		field.Set(slice)
+		return "", nil
+	}
+	return "", fmt.Errorf("%s tag modifier requires []byte or [N]byte field, got %s", encoding, t)
+}
+
+func isBytesType(t reflect.Type) bool {
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() == reflect.Uint8
+}
+
+func setDecodedBytes(field reflect.Value, encoding string, value string) error {
+	b, err := decodeBytes(encoding, value)
+	if err != nil {
+		return fmt.Errorf("invalid %s value: %w", encoding, err)
+	}
+	if field.Kind() == reflect.Array {
+		if len(b) != field.Len() {
+			return fmt.Errorf("invalid %s value: expected %d bytes, got %d", encoding, field.Len(), len(b))
+		}
+		reflect.Copy(field, reflect.ValueOf(b))
 		return nil
 	}
-	hasFiles := len(dataFiles) > 0
-	typ := reflect.TypeOf(destination).Elem()
-	val := reflect.ValueOf(destination).Elem()
+	field.SetBytes(b)
+	return nil
+}
 
-	// Support binding to limited Map destinations:
-	// - map[string][]string,
-	// - map[string]string <-- (binds first value from data slice)
-	// - map[string]interface{}
-	// You are better off binding to struct but there are user who want this map feature. Source of data for these cases are:
-	// params,query,header,form as these sources produce string values, most of the time slice of strings, actually.
-	if typ.Kind() == reflect.Map && typ.Key().Kind() == reflect.String {
-		k := typ.Elem().Kind()
-		isElemInterface := k == reflect.Interface
-		isElemString := k == reflect.String
-		isElemSliceOfStrings := k == reflect.Slice && typ.Elem().Elem().Kind() == reflect.String
-		if !(isElemSliceOfStrings || isElemString || isElemInterface) {
-			return nil
+// trimValues returns copy of values with leading and trailing white space removed from each value. Values are
+// copied as they belong to the request (ala `Request.Form`).
+func trimValues(values []string) []string {
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = strings.TrimSpace(v)
+	}
+	return result
+}
+
+// isBindableStruct returns true for struct (or pointer to struct) fields that are bound field by field instead of
+// from a single value.
+func isBindableStruct(field reflect.Value) bool {
+	t := field.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	ptr := reflect.New(t).Interface()
+	if _, ok := ptr.(BindUnmarshaler); ok {
+		return false
+	}
+	if _, ok := ptr.(encoding.TextUnmarshaler); ok {
+		return false
+	}
+	if _, ok := ptr.(BindCompositeUnmarshaler); ok {
+		return false
+	}
+	return true
+}
+
+// lookupValues returns values of the key for field with given tag name. Keys are matched exactly and then
+// case-insensitively (unless fallbacks are disabled). Values are trimmed when tag modifiers or binder say so.
+func (b *DefaultBinder) lookupValues(data map[string][]string, tag string, name string, tagModifiers string) ([]string, bool) {
+	key, exists := b.lookupKey(data, tag, name)
+	if !exists {
+		return nil, false
+	}
+	values := data[key]
+	if b.shouldTrim(tagModifiers) {
+		values = trimValues(values)
+	}
+	return values, true
+}
+
+// lookupKey returns key of data that holds values for field with given tag name.
+func (b *DefaultBinder) lookupKey(data map[string][]string, tag string, name string) (string, bool) {
+	if _, exists := data[name]; exists {
+		return name, true
+	}
+	if !b.DisableFallbackBinding || tag == "header" { // header names are case-insensitive by definition
+		// Go json.Unmarshal supports case-insensitive binding.  However the
+		// url params are bound case-sensitive which is inconsistent.  To
+		// fix this we must check all of the map values in a
+		// case-insensitive search.
+		for k := range data {
+			if strings.EqualFold(k, name) {
+				return k, true
+			}
 		}
-		if val.IsNil() {
-			val.Set(reflect.MakeMap(typ))
+	}
+	return "", false
+}
+
+// resolveFieldAlias returns the first of `|` separated alternative names that is present in data or dataFiles. The
+// first name is returned when none of them is present so missing required field is reported by its primary name.
+func (b *DefaultBinder) resolveFieldAlias(data map[string][]string, dataFiles map[string][]*multipart.FileHeader, tag string, names string, tagModifiers string) (string, error) {
+	chosen := ""
+	var chosenValues []string
+	for _, name := range strings.Split(names, "|") {
+		values, exists := b.lookupValues(data, tag, name, tagModifiers)
+		if !exists {
+			if _, ok := dataFiles[name]; !ok {
+				continue
+			}
 		}
-		for k, v := range data {
-			if isElemString {
-				val.SetMapIndex(reflect.ValueOf(k), reflect.ValueOf(v[0]))
-			} else if isElemInterface {
-				// To maintain backward compatibility, we always bind to the first string value
-				// and not the slice of strings when dealing with map[string]interface{}{}
-				val.SetMapIndex(reflect.ValueOf(k), reflect.ValueOf(v[0]))
-			} else {
-				val.SetMapIndex(reflect.ValueOf(k), reflect.ValueOf(v))
+		if chosen == "" {
+			chosen, chosenValues = name, values
+			if b.AliasConflict != AliasConflictError {
+				break
 			}
+			continue
+		}
+		if !equalStringSlices(values, chosenValues) {
+			err := fmt.Errorf("%w: %q and %q", ErrAliasConflict, chosen, name)
+			return "", newBindFieldError(tag, chosen, strings.Join(values, ","), err)
+		}
+	}
+	if chosen == "" {
+		chosen, _, _ = strings.Cut(names, "|")
+	}
+	return chosen, nil
+}
+
+func equalStringSlices(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// bindComposite binds field implementing BindCompositeUnmarshaler. Returns false when field does not implement it.
+// Nil pointer fields are allocated only when any of the keys the unmarshaler asked for exists.
+func (b *DefaultBinder) bindComposite(field reflect.Value, data map[string][]string, tag string, name string, tagModifiers string, state *bindState) (bool, error) {
+	target := field
+	if field.Kind() == reflect.Ptr {
+		target = reflect.New(field.Type().Elem()).Elem()
+		if !field.IsNil() {
+			target = field.Elem()
 		}
+	}
+	if !target.CanAddr() {
+		return false, nil
+	}
+	unmarshaler, ok := target.Addr().Interface().(BindCompositeUnmarshaler)
+	if !ok {
+		return false, nil
+	}
+
+	found := false
+	err := unmarshaler.UnmarshalParamsComposite(name, tagModifiers, func(key string) ([]string, bool) {
+		values, exists := b.lookupValues(data, tag, key, tagModifiers)
+		found = found || exists
+		if exists {
+			state.consume(data, tag, key)
+		}
+		return values, exists
+	})
+	if err != nil {
+		var fieldErr *BindFieldError
+		if !errors.As(err, &fieldErr) {
+			return true, newBindFieldError(tag, name, "", err)
+		}
+		if fieldErr.Source == "" {
+			fieldErr.Source = bindFieldSource(tag)
+		}
+		return true, err
+	}
+	if field.Kind() == reflect.Ptr && field.IsNil() && found {
+		field.Set(target.Addr())
+	}
+	return true, nil
+}
+
+// bindDataToMap binds data to map destination. Keys are converted with BindUnmarshaler, encoding.TextUnmarshaler or
+// as scalar kinds (string, int, etc.) and values the same way as struct fields are. For values that are not slices the
+// first value of the key wins. `map[string]interface{}` gets the first value as string. Maps with key or value types
+// that can not be bound from strings are skipped. Conversion errors are returned as BindFieldError.
+func (b *DefaultBinder) bindDataToMap(val reflect.Value, data map[string][]string, tag string) error {
+	typ := val.Type()
+	keyType, elemType := typ.Key(), typ.Elem()
+	elemIsInterface := elemType.Kind() == reflect.Interface && elemType.NumMethod() == 0
+	elemIsSlice := elemType.Kind() == reflect.Slice && !isMultiValueUnmarshalerType(elemType) && !isScalarBindableType(elemType)
+	if !isScalarBindableType(keyType) {
+		return nil
+	}
+	if !elemIsInterface && !isMultiValueUnmarshalerType(elemType) && !isScalarBindableType(elemType) &&
+		!(elemIsSlice && isScalarBindableType(elemType.Elem())) {
 		return nil
 	}
+	if val.IsNil() {
+		val.Set(reflect.MakeMap(typ))
+	}
+
+	for k, v := range data {
+		if len(v) == 0 {
+			continue
+		}
+		if elemIsSlice || isMultiValueUnmarshalerType(elemType) {
+			if err := b.checkSliceLength(tag, k, "", len(v), v[0]); err != nil {
+				return err
+			}
+		}
+		if b.TrimSpace {
+			v = trimValues(v)
+		}
+		if b.LenientBool && isBoolBindType(elemType) {
+			v = lenientBoolValues(v)
+		}
+		key := reflect.New(keyType).Elem()
+		if err := setWithProperType(keyType.Kind(), k, key); err != nil {
+			return newBindFieldError(tag, k, k, err)
+		}
+
+		elem := reflect.New(elemType).Elem()
+		switch {
+		case elemIsInterface:
+			// To maintain backward compatibility, we always bind to the first string value
+			// and not the slice of strings when dealing with map[string]interface{}{}
+			elem.Set(reflect.ValueOf(v[0]))
+		case isMultiValueUnmarshalerType(elemType):
+			if _, err := unmarshalInputsToField(elemType.Kind(), v, elem); err != nil {
+				return newBindFieldError(tag, k, strings.Join(v, ","), err)
+			}
+		case elemIsSlice:
+			elem.Set(reflect.MakeSlice(elemType, len(v), len(v)))
+			for j := range v {
+				if err := setWithProperType(elemType.Elem().Kind(), v[j], elem.Index(j)); err != nil {
+					return newBindFieldError(tag, k, v[j], err)
+				}
+			}
+		default:
+			if err := setWithProperType(elemType.Kind(), v[0], elem); err != nil {
+				return newBindFieldError(tag, k, v[0], err)
+			}
+		}
+		val.SetMapIndex(key, elem)
+	}
+	return nil
+}
+
+// bindDataToLegacyMap binds data to limited map destinations when `DefaultBinder.LegacyMapBinding` is set:
+// - map[string][]string,
+// - map[string]string <-- (binds first value from data slice)
+// - map[string]interface{}
+func (b *DefaultBinder) bindDataToLegacyMap(val reflect.Value, data map[string][]string) error {
+	typ := val.Type()
+	if typ.Key().Kind() != reflect.String {
+		return nil
+	}
+	k := typ.Elem().Kind()
+	isElemInterface := k == reflect.Interface
+	isElemString := k == reflect.String
+	isElemSliceOfStrings := k == reflect.Slice && stringSliceType.ConvertibleTo(typ.Elem())
+	if !(isElemSliceOfStrings || isElemString || isElemInterface) {
+		return nil
+	}
+	if val.IsNil() {
+		val.Set(reflect.MakeMap(typ))
+	}
+	for k, v := range data {
+		if b.TrimSpace {
+			v = trimValues(v)
+		}
+		key := reflect.ValueOf(k).Convert(typ.Key())
+		if isElemSliceOfStrings {
+			val.SetMapIndex(key, reflect.ValueOf(v).Convert(typ.Elem()))
+		} else {
+			val.SetMapIndex(key, reflect.ValueOf(v[0]).Convert(typ.Elem()))
+		}
+	}
+	return nil
+}
+
+var (
+	bindUnmarshalerType         = reflect.TypeOf((*BindUnmarshaler)(nil)).Elem()
+	textUnmarshalerType         = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	bindMultipleUnmarshalerType = reflect.TypeOf((*bindMultipleUnmarshaler)(nil)).Elem()
+	stringSliceType             = reflect.TypeOf([]string(nil))
+)
+
+// isScalarBindableType returns true for types that can be bound from single string value: types implementing
+// BindUnmarshaler or encoding.TextUnmarshaler (with pointer receiver), scalar kinds and pointers to them.
+func isScalarBindableType(t reflect.Type) bool {
+	ptr := reflect.PointerTo(t)
+	if ptr.Implements(bindUnmarshalerType) || ptr.Implements(textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return isScalarBindableType(t.Elem())
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isMultiValueUnmarshalerType returns true for types that unmarshal all values of the key at once.
+func isMultiValueUnmarshalerType(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(bindMultipleUnmarshalerType)
+}
+
+// maxBindNestingDepth is maximum depth of nested structs bound by bindData. It guards against self-referencing
+// types (ala `type Node struct { Next *Node }`) that would otherwise be allocated without end.
+const maxBindNestingDepth = 32
+
+// errBindNestingTooDeep is returned when destination struct nesting exceeds maxBindNestingDepth.
+var errBindNestingTooDeep = errors.New("binding element nesting is too deep")
+
+// bindData binds data to destination and returns RequiredFieldsError when keys of `required` fields are absent.
+func (b *DefaultBinder) bindData(destination interface{}, data map[string][]string, tag string, dataFiles map[string][]*multipart.FileHeader) error {
+	missing, err := b.bindDataMissing(destination, data, tag, dataFiles)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return newRequiredFieldsError(missing)
+	}
+	return nil
+}
+
+// bindDataMissing binds data to destination and returns `required` fields whose keys are absent from data.
+func (b *DefaultBinder) bindDataMissing(destination interface{}, data map[string][]string, tag string, dataFiles map[string][]*multipart.FileHeader) ([]missingField, error) {
+	state := &bindState{b: b}
+	if destination != nil && hasModifierFields(reflect.TypeOf(destination), tag, "rest") {
+		state.consumed = map[string]struct{}{}
+	}
+	if err := b.bindDataNested(destination, data, tag, dataFiles, 0, state); err != nil {
+		return nil, err
+	}
+	if err := validateSortFields(destination, tag, data); err != nil {
+		return nil, err
+	}
+	state.setRestFields(data)
+	return state.missing, nil
+}
+
+// bindState collects results of bindDataNested calls for single destination and source.
+type bindState struct {
+	b       *DefaultBinder
+	missing []missingField
+	// consumed holds keys of data bound to fields. It is nil unless destination has `rest` fields.
+	consumed map[string]struct{}
+	rest     []reflect.Value
+}
+
+// consume marks key of data that holds values of field with given tag name as bound.
+func (s *bindState) consume(data map[string][]string, tag string, name string) {
+	if s.consumed == nil {
+		return
+	}
+	if key, ok := s.b.lookupKey(data, tag, name); ok {
+		s.consumed[key] = struct{}{}
+	}
+}
+
+// setRestFields sets `rest` fields to keys of data that were not bound to other fields. Values are copied untouched
+// (not trimmed) in their original order.
+func (s *bindState) setRestFields(data map[string][]string) {
+	if len(s.rest) == 0 {
+		return
+	}
+	rest := map[string][]string{}
+	for k, v := range data {
+		if _, ok := s.consumed[k]; !ok {
+			rest[k] = append([]string(nil), v...)
+		}
+	}
+	if len(rest) == 0 {
+		return
+	}
+	for _, field := range s.rest {
+		field.Set(reflect.ValueOf(rest).Convert(field.Type()))
+	}
+}
+
+// isRestFieldType returns true when type can hold values of `rest` field (`map[string][]string`, `url.Values` or
+// `http.Header`).
+func isRestFieldType(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && t.Elem() == reflect.TypeOf([]string(nil))
+}
+
+// bindDataNested will bind data ONLY fields in destination struct that have EXPLICIT tag
+func (b *DefaultBinder) bindDataNested(destination interface{}, data map[string][]string, tag string, dataFiles map[string][]*multipart.FileHeader, depth int, state *bindState) error {
+	if depth > maxBindNestingDepth {
+		return errBindNestingTooDeep
+	}
+	if destination == nil {
+		return nil
+	}
+	if len(data) == 0 && len(dataFiles) == 0 && !hasRequiredFields(reflect.TypeOf(destination), tag) {
+		return nil
+	}
+	hasFiles := len(dataFiles) > 0
+	typ := reflect.TypeOf(destination).Elem()
+	val := reflect.ValueOf(destination).Elem()
+
+	if typ.Kind() == reflect.Map {
+		if b.LegacyMapBinding {
+			return b.bindDataToLegacyMap(val, data)
+		}
+		return b.bindDataToMap(val, data, tag)
+	}
 
 	// !struct
 	if typ.Kind() != reflect.Struct {
-		if tag == "param" || tag == "query" || tag == "header" {
+		if tag == "param" || tag == "query" || tag == "header" || tag == "matrix" {
 			// incompatible type, data is probably to be found in the body
 			return nil
 		}
@@ -204,25 +1349,65 @@ func (b *DefaultBinder) bindData(destination interface{}, data map[string][]stri
 			continue
 		}
 		structFieldKind := structField.Kind()
-		inputFieldName := typeField.Tag.Get(tag)
+		inputFieldName, tagModifiers, _ := strings.Cut(typeField.Tag.Get(tag), ",")
+		if inputFieldName == "-" {
+			// field is explicitly excluded from binding from this source
+			continue
+		}
 		if typeField.Anonymous && structFieldKind == reflect.Struct && inputFieldName != "" {
 			// if anonymous struct with query/param/form tags, report an error
 			return errors.New("query/param/form tags are not allowed with anonymous struct field")
 		}
 
+		if isRest(tagModifiers) {
+			if inputFieldName != "" || !isRestFieldType(structField.Type()) {
+				return fmt.Errorf("rest field %s must be map[string][]string (ala url.Values) without name in %s tag", typeField.Name, tag)
+			}
+			state.rest = append(state.rest, structField)
+			continue
+		}
+
+		if tag == "form" && (inputFieldName == formCatchAllTag || (inputFieldName == "" && typeField.Type == multipartFileHeaderMapType)) {
+			if err := setFormCatchAllField(structField, data, dataFiles); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if inputFieldName == "" && b.FallbackToJSONTag && !b.DisableFallbackBinding && !typeField.Anonymous && !isBindableStruct(structField) {
+			var skip bool
+			if inputFieldName, skip = jsonTagName(typeField); skip {
+				continue
+			}
+		}
+
+		if inputFieldName == "" && b.NameTransform != nil && !b.DisableFallbackBinding && !typeField.Anonymous && !isBindableStruct(structField) {
+			inputFieldName = b.NameTransform(typeField.Name)
+		}
+
+		if inputFieldName == "" && typeField.Anonymous {
+			// embedded composite types (ala `echo.TimeRange`) are bound from their default keys
+			if ok, err := b.bindComposite(structField, data, tag, "", tagModifiers, state); ok {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
 		if inputFieldName == "" {
 			// If tag is nil, we inspect if the field is a not BindUnmarshaler struct and try to bind data into it (might contain fields with tags).
 			// structs that implement BindUnmarshaler are bound only when they have explicit tag
 			if _, ok := structField.Addr().Interface().(BindUnmarshaler); !ok {
 				if structFieldKind == reflect.Struct {
-					if err := b.bindData(structField.Addr().Interface(), data, tag, dataFiles); err != nil {
+					if err := b.bindDataNested(structField.Addr().Interface(), data, tag, dataFiles, depth+1, state); err != nil {
 						return err
 					}
 				} else if structFieldKind == reflect.Ptr && structField.Type().Elem().Kind() == reflect.Struct {
 					if structField.IsNil() {
 						structField.Set(reflect.New(structField.Type().Elem()))
 					}
-					if err := b.bindData(structField.Interface(), data, tag, dataFiles); err != nil {
+					if err := b.bindDataNested(structField.Interface(), data, tag, dataFiles, depth+1, state); err != nil {
 						return err
 					}
 				}
@@ -231,32 +1416,67 @@ func (b *DefaultBinder) bindData(destination interface{}, data map[string][]stri
 			continue
 		}
 
+		if strings.IndexByte(inputFieldName, '|') != -1 {
+			name, err := b.resolveFieldAlias(data, dataFiles, tag, inputFieldName, tagModifiers)
+			if err != nil {
+				return err
+			}
+			// all present alternative names hold value of this field
+			for _, alias := range strings.Split(inputFieldName, "|") {
+				state.consume(data, tag, alias)
+			}
+			inputFieldName = name
+		}
+
+		if ok, err := b.bindComposite(structField, data, tag, inputFieldName, tagModifiers, state); ok {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
 		if hasFiles {
 			if ok, err := isFieldMultipartFile(structField.Type()); err != nil {
 				return err
 			} else if ok {
+				if files := dataFiles[inputFieldName]; structFieldKind == reflect.Slice {
+					if err := b.checkSliceLength(tag, inputFieldName, tagModifiers, len(files), ""); err != nil {
+						return err
+					}
+				}
 				if ok := setMultipartFileHeaderTypes(structField, inputFieldName, dataFiles); ok {
 					continue
 				}
 			}
 		}
 
-		inputValue, exists := data[inputFieldName]
+		inputValue, exists := b.lookupValues(data, tag, inputFieldName, tagModifiers)
 		if !exists {
-			// Go json.Unmarshal supports case-insensitive binding.  However the
-			// url params are bound case-sensitive which is inconsistent.  To
-			// fix this we must check all of the map values in a
-			// case-insensitive search.
-			for k, v := range data {
-				if strings.EqualFold(k, inputFieldName) {
-					inputValue = v
-					exists = true
-					break
-				}
+			if isRequired(tagModifiers) {
+				state.missing = append(state.missing, missingField{
+					err:          newBindFieldError(tag, inputFieldName, "", ErrRequiredFieldMissing),
+					field:        structField,
+					bodyBindable: isBodyBindableField(typeField),
+				})
 			}
+			continue
 		}
+		state.consume(data, tag, inputFieldName)
 
-		if !exists {
+		if isMultiValueBindType(typeField.Type) && len(inputValue) > 0 {
+			if err := b.checkSliceLength(tag, inputFieldName, tagModifiers, len(inputValue), inputValue[0]); err != nil {
+				return err
+			}
+		}
+
+		if isBoolBindType(structField.Type()) && b.isLenientBool(tagModifiers) {
+			inputValue = lenientBoolValues(inputValue)
+		}
+
+		if encoding := bytesEncoding(tagModifiers); encoding != "" {
+			if value, err := setEncodedBytesField(structField, encoding, inputValue); err != nil {
+				return newBindFieldError(tag, inputFieldName, value, fmt.Errorf("field %q: %w", inputFieldName, err))
+			}
 			continue
 		}
 
@@ -266,14 +1486,14 @@ func (b *DefaultBinder) bindData(destination interface{}, data map[string][]stri
 		// try unmarshalling first, in case we're dealing with an alias to an array type
 		if ok, err := unmarshalInputsToField(typeField.Type.Kind(), inputValue, structField); ok {
 			if err != nil {
-				return err
+				return newBindFieldError(tag, inputFieldName, strings.Join(inputValue, ","), err)
 			}
 			continue
 		}
 
 		if ok, err := unmarshalInputToField(typeField.Type.Kind(), inputValue[0], structField); ok {
 			if err != nil {
-				return err
+				return newBindFieldError(tag, inputFieldName, inputValue[0], err)
 			}
 			continue
 		}
@@ -291,7 +1511,7 @@ func (b *DefaultBinder) bindData(destination interface{}, data map[string][]stri
 			slice := reflect.MakeSlice(structField.Type(), numElems, numElems)
 			for j := 0; j < numElems; j++ {
 				if err := setWithProperType(sliceOf, inputValue[j], slice.Index(j)); err != nil {
-					return err
+					return newBindFieldError(tag, inputFieldName, inputValue[j], err)
 				}
 			}
 			structField.Set(slice)
@@ -299,7 +1519,7 @@ func (b *DefaultBinder) bindData(destination interface{}, data map[string][]stri
 		}
 
 		if err := setWithProperType(structFieldKind, inputValue[0], structField); err != nil {
-			return err
+			return newBindFieldError(tag, inputFieldName, inputValue[0], err)
 		}
 	}
 	return nil
@@ -434,8 +1654,43 @@ var (
 	multipartFileHeaderPointerType      = reflect.TypeOf(&multipart.FileHeader{})
 	multipartFileHeaderSliceType        = reflect.TypeOf([]multipart.FileHeader(nil))
 	multipartFileHeaderPointerSliceType = reflect.TypeOf([]*multipart.FileHeader(nil))
+	// catch-all destinations for form values and files
+	multipartFileHeaderMapType = reflect.TypeOf(map[string][]*multipart.FileHeader(nil))
+	stringSliceMapType         = reflect.TypeOf(map[string][]string(nil))
 )
 
+// formCatchAllTag is `form` tag value of the field that receives all form values or files.
+const formCatchAllTag = "*"
+
+// setFormCatchAllField binds all form files (to `map[string][]*multipart.FileHeader` field) or all non-file form
+// values (to `map[string][]string` field) keyed by form field name. Catch-all field receives every key, including
+// keys that are bound to explicitly tagged fields. Files are bound to field without tag too.
+func setFormCatchAllField(structField reflect.Value, data map[string][]string, files map[string][]*multipart.FileHeader) error {
+	switch structField.Type() {
+	case multipartFileHeaderMapType:
+		if len(files) == 0 {
+			return nil
+		}
+		result := make(map[string][]*multipart.FileHeader, len(files))
+		for k, v := range files {
+			result[k] = v
+		}
+		structField.Set(reflect.ValueOf(result))
+	case stringSliceMapType:
+		if len(data) == 0 {
+			return nil
+		}
+		result := make(map[string][]string, len(data))
+		for k, v := range data {
+			result[k] = v
+		}
+		structField.Set(reflect.ValueOf(result))
+	default:
+		return errors.New("form catch-all field must be map[string][]*multipart.FileHeader or map[string][]string")
+	}
+	return nil
+}
+
 func isFieldMultipartFile(field reflect.Type) (bool, error) {
 	switch field {
 	case multipartFileHeaderPointerType,
@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// polymorphicRegistry holds polymorphic bindings of registered interface types.
+type polymorphicRegistry struct {
+	mu       sync.RWMutex
+	bindings map[reflect.Type]polymorphicBinding
+}
+
+// polymorphicBinding holds factories for concrete implementations of registered interface type.
+type polymorphicBinding struct {
+	discriminator string
+	factories     map[string]func() interface{}
+	accepted      string
+}
+
+// RegisterPolymorphicBinding registers factories for interface type `T` to the binder so that JSON body binding is able to populate
+// struct fields of that interface type. Concrete type is chosen by value of `discriminatorField` which is sibling
+// field (in same JSON object) of the interface field. Factories must return pointers so decoded values can be stored
+// into them.
+//
+// Unknown discriminator values result in 400 error listing accepted values. Only JSON bodies are supported. Body of
+// requests bound to struct with registered interface field is read to memory, up to `DefaultBinder.MaxRawBodySize`.
+// Register bindings before the binder is used, copies of the binder made after the first registration share
+// registrations.
+//
+// Example:
+//
+//	type PaymentDetails interface{ Kind() string }
+//	type Payment struct {
+//		Type    string         `json:"type"`
+//		Details PaymentDetails `json:"details"`
+//	}
+//
+//	binder := &echo.DefaultBinder{}
+//	echo.RegisterPolymorphicBinding[PaymentDetails](binder, "type", map[string]func() PaymentDetails{
+//		"card": func() PaymentDetails { return &CardDetails{} },
+//		"bank": func() PaymentDetails { return &BankDetails{} },
+//	})
+//	e.Binder = binder
+func RegisterPolymorphicBinding[T any](b *DefaultBinder, discriminatorField string, factories map[string]func() T) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("echo: polymorphic binding type must be an interface, got %v", typ))
+	}
+	if discriminatorField == "" {
+		panic("echo: polymorphic binding requires discriminator field name")
+	}
+
+	binding := polymorphicBinding{
+		discriminator: discriminatorField,
+		factories:     make(map[string]func() interface{}, len(factories)),
+	}
+	accepted := make([]string, 0, len(factories))
+	for value, factory := range factories {
+		factory := factory
+		binding.factories[value] = func() interface{} { return factory() }
+		accepted = append(accepted, value)
+	}
+	sort.Strings(accepted)
+	binding.accepted = strings.Join(accepted, ", ")
+
+	if b.polymorphic == nil {
+		b.polymorphic = &polymorphicRegistry{}
+	}
+	r := b.polymorphic
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.bindings == nil {
+		r.bindings = make(map[reflect.Type]polymorphicBinding)
+	}
+	r.bindings[typ] = binding
+}
+
+type polymorphicField struct {
+	index   int
+	binding polymorphicBinding
+}
+
+// bindPolymorphicFields populates registered interface typed fields of destination struct with concrete instances
+// chosen by discriminator value found in the JSON body. Request body is restored so it can be decoded normally afterwards.
+func (b *DefaultBinder) bindPolymorphicFields(req *http.Request, destination interface{}) error {
+	val := reflect.ValueOf(destination)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	val = val.Elem()
+	fields := b.polymorphicFields(val)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	// body is read without holding the lock, slow client must not block registrations
+	body, err := b.readBody(req)
+	if err != nil {
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil // let the JSON serializer report the syntax error
+	}
+
+	for _, field := range fields {
+		binding := field.binding
+		rawDiscriminator, ok := raw[binding.discriminator]
+		if !ok {
+			continue
+		}
+		var discriminator string
+		if err := json.Unmarshal(rawDiscriminator, &discriminator); err != nil {
+			return NewHTTPError(
+				http.StatusBadRequest,
+				fmt.Sprintf("invalid value for '%s', accepted values: %s", binding.discriminator, binding.accepted),
+			).SetInternal(err)
+		}
+		factory, ok := binding.factories[discriminator]
+		if !ok {
+			return NewHTTPError(
+				http.StatusBadRequest,
+				fmt.Sprintf("unknown value '%s' for '%s', accepted values: %s", discriminator, binding.discriminator, binding.accepted),
+			)
+		}
+		if instance := factory(); instance != nil {
+			val.Field(field.index).Set(reflect.ValueOf(instance))
+		}
+	}
+	return nil
+}
+
+// polymorphicFields returns settable fields of the struct with registered interface type.
+func (b *DefaultBinder) polymorphicFields(val reflect.Value) []polymorphicField {
+	r := b.polymorphic
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.bindings) == 0 {
+		return nil
+	}
+
+	var fields []polymorphicField
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		if binding, ok := r.bindings[typ.Field(i).Type]; ok && val.Field(i).CanSet() {
+			fields = append(fields, polymorphicField{index: i, binding: binding})
+		}
+	}
+	return fields
+}
@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testPaymentDetails interface {
+	Kind() string
+}
+
+type testCardDetails struct {
+	Number string `json:"number"`
+}
+
+func (d *testCardDetails) Kind() string { return "card" }
+
+type testBankDetails struct {
+	IBAN string `json:"iban"`
+}
+
+func (d *testBankDetails) Kind() string { return "bank" }
+
+type testPayment struct {
+	Type    string             `json:"type"`
+	Amount  int                `json:"amount"`
+	Details testPaymentDetails `json:"details"`
+}
+
+func newPolymorphicTestBinder() *DefaultBinder {
+	binder := &DefaultBinder{}
+	RegisterPolymorphicBinding[testPaymentDetails](binder, "type", map[string]func() testPaymentDetails{
+		"card": func() testPaymentDetails { return &testCardDetails{} },
+		"bank": func() testPaymentDetails { return &testBankDetails{} },
+	})
+	return binder
+}
+
+func TestDefaultBinder_BindBody_polymorphic(t *testing.T) {
+	binder := newPolymorphicTestBinder()
+
+	var testCases = []struct {
+		name        string
+		givenBody   string
+		expect      testPayment
+		expectError string
+	}{
+		{
+			name:      "ok, card",
+			givenBody: `{"type":"card","amount":10,"details":{"number":"4111"}}`,
+			expect:    testPayment{Type: "card", Amount: 10, Details: &testCardDetails{Number: "4111"}},
+		},
+		{
+			name:      "ok, bank with discriminator after details",
+			givenBody: `{"details":{"iban":"EE12"},"type":"bank"}`,
+			expect:    testPayment{Type: "bank", Details: &testBankDetails{IBAN: "EE12"}},
+		},
+		{
+			name:      "ok, no discriminator and no details",
+			givenBody: `{"amount":5}`,
+			expect:    testPayment{Amount: 5},
+		},
+		{
+			name:        "nok, unknown discriminator",
+			givenBody:   `{"type":"cash","details":{}}`,
+			expectError: "code=400, message=unknown value 'cash' for 'type', accepted values: bank, card",
+		},
+		{
+			name:        "nok, discriminator is not string",
+			givenBody:   `{"type":1,"details":{}}`,
+			expectError: "code=400, message=invalid value for 'type', accepted values: bank, card, internal=json: cannot unmarshal number into Go value of type string",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.givenBody))
+			req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			result := testPayment{}
+			err := binder.BindBody(c, &result)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, result)
+		})
+	}
+}
+
+func TestRegisterPolymorphicBinding_panicsOnNonInterface(t *testing.T) {
+	assert.Panics(t, func() {
+		RegisterPolymorphicBinding[testCardDetails](&DefaultBinder{}, "type", map[string]func() testCardDetails{})
+	})
+}
+
+func TestDefaultBinder_BindBody_polymorphicPerBinder(t *testing.T) {
+	newPolymorphicTestBinder()
+
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"type":"card","details":{"number":"4111"}}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	// binder without registration does not know how to decode the interface field
+	err := new(DefaultBinder).BindBody(c, &testPayment{})
+
+	assert.Error(t, err)
+}
+
+func TestDefaultBinder_BindBody_polymorphicCopiedBinder(t *testing.T) {
+	copied := *newPolymorphicTestBinder()
+	copied.MaxRawBodySize = 1024
+
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"type":"card","details":{"number":"4111"}}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	payment := testPayment{}
+	err := copied.BindBody(c, &payment)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &testCardDetails{Number: "4111"}, payment.Details)
+}
+
+func TestDefaultBinder_BindBody_polymorphicBodyLimit(t *testing.T) {
+	binder := newPolymorphicTestBinder()
+	binder.MaxRawBodySize = 10
+
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"type":"card","details":{"number":"4111"}}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	err := binder.BindBody(c, &testPayment{})
+
+	assert.Equal(t, ErrStatusRequestEntityTooLarge, err)
+}
@@ -5,6 +5,7 @@ package echo
 
 import (
 	"bytes"
+	"encoding/gob"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
@@ -14,6 +15,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/http/httputil"
+	"net/netip"
 	"net/url"
 	"reflect"
 	"strconv"
@@ -275,6 +277,1155 @@ func TestBindQueryParamsCaseSensitivePrioritized(t *testing.T) {
 	}
 }
 
+func TestDefaultBinder_FallbackToJSONTag(t *testing.T) {
+	type dto struct {
+		UserID  int    `json:"user_id,omitempty"`
+		Name    string `json:"name" query:"nickname"`
+		Secret  string `json:"-"`
+		NoTags  string
+		Created time.Time `json:"created"`
+		Nested  struct {
+			Page int `json:"page"`
+		}
+	}
+
+	var testCases = []struct {
+		name      string
+		givenFlag bool
+		whenURL   string
+		expect    dto
+	}{
+		{
+			name:      "ok, json tag fallback",
+			givenFlag: true,
+			whenURL:   "/?user_id=1&name=ignored&nickname=jon&Secret=x&NoTags=y&created=2023-01-02T03:04:05Z&page=3",
+			expect: dto{
+				UserID:  1,
+				Name:    "jon",
+				Created: time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+				Nested: struct {
+					Page int `json:"page"`
+				}{Page: 3},
+			},
+		},
+		{
+			name:      "ok, json tag fallback is case insensitive",
+			givenFlag: true,
+			whenURL:   "/?USER_ID=2",
+			expect:    dto{UserID: 2},
+		},
+		{
+			name:      "ok, no fallback by default",
+			givenFlag: false,
+			whenURL:   "/?user_id=1&nickname=jon&page=3",
+			expect:    dto{Name: "jon"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			result := dto{}
+			err := (&DefaultBinder{FallbackToJSONTag: tc.givenFlag}).BindQueryParams(c, &result)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, result)
+		})
+	}
+}
+
+func TestDefaultBinder_NameTransform(t *testing.T) {
+	type dto struct {
+		UserID     int
+		HTTPServer string
+		Name       string `query:"nickname"`
+		Email      string `json:"mail"`
+		Secret     string `query:"-"`
+		Nested     struct {
+			PageSize int
+		}
+	}
+
+	var testCases = []struct {
+		name        string
+		givenBinder *DefaultBinder
+		whenURL     string
+		expect      dto
+	}{
+		{
+			name:        "ok, snake_case",
+			givenBinder: &DefaultBinder{NameTransform: SnakeCaseName},
+			whenURL:     "/?user_id=1&http_server=x&name=ignored&nickname=jon&email=e&Secret=s&page_size=10",
+			expect: dto{
+				UserID:     1,
+				HTTPServer: "x",
+				Name:       "jon",
+				Email:      "e",
+				Nested:     struct{ PageSize int }{PageSize: 10},
+			},
+		},
+		{
+			name:        "ok, kebab-case",
+			givenBinder: &DefaultBinder{NameTransform: KebabCaseName},
+			whenURL:     "/?user-id=2&user_id=3&http-server=y",
+			expect:      dto{UserID: 2, HTTPServer: "y"},
+		},
+		{
+			name:        "ok, transformed name is matched case-insensitively",
+			givenBinder: &DefaultBinder{NameTransform: SnakeCaseName},
+			whenURL:     "/?USER_ID=4",
+			expect:      dto{UserID: 4},
+		},
+		{
+			name:        "ok, json tag fallback wins over transformed name",
+			givenBinder: &DefaultBinder{NameTransform: SnakeCaseName, FallbackToJSONTag: true},
+			whenURL:     "/?email=ignored&mail=m&user_id=5",
+			expect:      dto{UserID: 5, Email: "m"},
+		},
+		{
+			name:        "ok, not applied when fallback binding is disabled",
+			givenBinder: &DefaultBinder{NameTransform: SnakeCaseName, DisableFallbackBinding: true},
+			whenURL:     "/?user_id=6&nickname=jon",
+			expect:      dto{Name: "jon"},
+		},
+		{
+			name:        "ok, untagged fields are not bound when unset",
+			givenBinder: &DefaultBinder{},
+			whenURL:     "/?user_id=7&UserID=7&userid=7&email=e&nickname=jon&page_size=10&PageSize=10",
+			expect:      dto{Name: "jon"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			result := dto{}
+			err := tc.givenBinder.BindQueryParams(c, &result)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, result)
+		})
+	}
+}
+
+type trimTestUnmarshaler string
+
+func (u *trimTestUnmarshaler) UnmarshalParam(param string) error {
+	*u = trimTestUnmarshaler("<" + param + ">")
+	return nil
+}
+
+func TestDefaultBinder_TrimSpace(t *testing.T) {
+	type dto struct {
+		ID       int                 `query:"id"`
+		Name     string              `query:"name"`
+		Password string              `query:"password,notrim"`
+		IDs      []int               `query:"ids"`
+		Tags     []string            `query:"tags"`
+		Custom   trimTestUnmarshaler `query:"custom"`
+		Code     string              `query:"code,trim"`
+	}
+	const query = "/?id=%2042%20&name=%20jon%09&password=%20secret%20&ids=%201&ids=2%20&tags=%20a&custom=%20x%20&code=%20c%20"
+
+	var testCases = []struct {
+		name        string
+		givenBinder *DefaultBinder
+		expect      dto
+		expectErr   string
+	}{
+		{
+			name:        "ok, values are trimmed",
+			givenBinder: &DefaultBinder{TrimSpace: true},
+			expect: dto{
+				ID:       42,
+				Name:     "jon",
+				Password: " secret ",
+				IDs:      []int{1, 2},
+				Tags:     []string{"a"},
+				Custom:   "<x>",
+				Code:     "c",
+			},
+		},
+		{
+			name:        "nok, values are not trimmed by default",
+			givenBinder: &DefaultBinder{},
+			expectErr:   `code=400, message=strconv.ParseInt: parsing " 42 ": invalid syntax, internal=strconv.ParseInt: parsing " 42 ": invalid syntax`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodGet, query, nil)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			result := dto{}
+			err := tc.givenBinder.BindQueryParams(c, &result)
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, result)
+			assert.Equal(t, " jon\t", c.QueryParam("name"), "request values must not be modified")
+		})
+	}
+}
+
+func TestDefaultBinder_TrimSpace_tagModifier(t *testing.T) {
+	type dto struct {
+		ID   int    `form:"id,trim"`
+		Name string `form:"name"`
+	}
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("id=+7+&name=+jon+"))
+	req.Header.Set(HeaderContentType, MIMEApplicationForm)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	result := dto{}
+	err := (&DefaultBinder{}).BindBody(c, &result)
+
+	assert.NoError(t, err)
+	assert.Equal(t, dto{ID: 7, Name: " jon "}, result)
+}
+
+func TestDefaultBinder_bytesEncodingTagModifier(t *testing.T) {
+	type dto struct {
+		Raw     []byte   `query:"raw"`
+		Std     []byte   `query:"std,base64"`
+		Cursor  *[]byte  `query:"cursor,base64url"`
+		Hash    [2]byte  `query:"hash,hex"`
+		Tokens  [][]byte `query:"tokens,trim,base64url"`
+		Missing []byte   `query:"missing,base64"`
+	}
+
+	var testCases = []struct {
+		name      string
+		whenQuery string
+		expect    dto
+		expectErr string
+	}{
+		{
+			name:      "ok, values are decoded",
+			whenQuery: "std=aGVsbG8%3D&cursor=-_8&hash=cafe&tokens=-_8%3D&tokens=%20aGk%20",
+			expect: dto{
+				Std:    []byte("hello"),
+				Cursor: &[]byte{0xfb, 0xff},
+				Hash:   [2]byte{0xca, 0xfe},
+				Tokens: [][]byte{{0xfb, 0xff}, []byte("hi")},
+			},
+		},
+		{
+			name:      "nok, field without modifier is not decoded",
+			whenQuery: "raw=aGk",
+			expectErr: `code=400, message=strconv.ParseUint: parsing "aGk": invalid syntax, internal=strconv.ParseUint: parsing "aGk": invalid syntax`,
+		},
+		{
+			name:      "nok, invalid base64",
+			whenQuery: "std=-_8",
+			expectErr: `code=400, message=field "std": invalid base64 value: illegal base64 data at input byte 0, internal=field "std": invalid base64 value: illegal base64 data at input byte 0`,
+		},
+		{
+			name:      "nok, invalid base64url in slice",
+			whenQuery: "tokens=aGk&tokens=a%2Bk",
+			expectErr: `code=400, message=field "tokens": invalid base64url value: illegal base64 data at input byte 1, internal=field "tokens": invalid base64url value: illegal base64 data at input byte 1`,
+		},
+		{
+			name:      "nok, array length mismatch",
+			whenQuery: "hash=cafebabe",
+			expectErr: `code=400, message=field "hash": invalid hex value: expected 2 bytes, got 4, internal=field "hash": invalid hex value: expected 2 bytes, got 4`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodGet, "/?"+tc.whenQuery, nil)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			result := dto{}
+			err := (&DefaultBinder{}).BindQueryParams(c, &result)
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				var fieldErr *BindFieldError
+				assert.True(t, errors.As(err, &fieldErr))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, result)
+		})
+	}
+}
+
+func TestDefaultBinder_bytesEncodingTagModifier_unsupportedField(t *testing.T) {
+	type dto struct {
+		ID int `query:"id,hex"`
+	}
+	e := New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/?id=ff", nil), httptest.NewRecorder())
+
+	err := (&DefaultBinder{}).BindQueryParams(c, &dto{})
+
+	assert.EqualError(t, err, `code=400, message=field "id": hex tag modifier requires []byte or [N]byte field, got int, internal=field "id": hex tag modifier requires []byte or [N]byte field, got int`)
+}
+
+func TestDefaultBinder_LenientBool(t *testing.T) {
+	type dto struct {
+		Subscribe bool    `form:"subscribe"`
+		Notify    *bool   `form:"notify"`
+		Flags     []bool  `form:"flags"`
+		Present   bool    `form:"present"`
+		Absent    bool    `form:"absent"`
+		Tagged    bool    `form:"tagged,lenient"`
+		Text      string  `form:"text"`
+		Number    float64 `form:"number"`
+	}
+
+	var testCases = []struct {
+		name        string
+		givenBinder *DefaultBinder
+		whenBody    string
+		expect      dto
+		expectErr   string
+	}{
+		{
+			name:        "ok, lenient values",
+			givenBinder: &DefaultBinder{LenientBool: true},
+			whenBody:    "subscribe=on&notify=No&flags=YES&flags=off&flags=y&flags=N&flags=1&present&text=on&number=1",
+			expect: dto{
+				Subscribe: true,
+				Notify:    new(bool),
+				Flags:     []bool{true, false, true, false, true},
+				Present:   true,
+				Text:      "on",
+				Number:    1,
+			},
+		},
+		{
+			name:        "ok, tag modifier",
+			givenBinder: &DefaultBinder{},
+			whenBody:    "tagged=on",
+			expect:      dto{Tagged: true},
+		},
+		{
+			name:        "nok, strict by default",
+			givenBinder: &DefaultBinder{},
+			whenBody:    "subscribe=on",
+			expectErr:   `code=400, message=strconv.ParseBool: parsing "on": invalid syntax, internal=strconv.ParseBool: parsing "on": invalid syntax`,
+		},
+		{
+			name:        "nok, unknown word",
+			givenBinder: &DefaultBinder{LenientBool: true},
+			whenBody:    "subscribe=maybe",
+			expectErr:   `code=400, message=strconv.ParseBool: parsing "maybe": invalid syntax, internal=strconv.ParseBool: parsing "maybe": invalid syntax`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.whenBody))
+			req.Header.Set(HeaderContentType, MIMEApplicationForm)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			result := dto{}
+			err := tc.givenBinder.BindBody(c, &result)
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, result)
+		})
+	}
+}
+
+func TestDefaultBinder_LenientBool_mapDestination(t *testing.T) {
+	e := New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/?a=on&b=no&c", nil), httptest.NewRecorder())
+
+	result := map[string]bool{}
+	err := (&DefaultBinder{LenientBool: true}).BindQueryParams(c, &result)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"a": true, "b": false, "c": true}, result)
+}
+
+func TestDefaultBinder_requiredTagModifier(t *testing.T) {
+	type nested struct {
+		Tenant string `header:"X-Tenant,required" query:"tenant"`
+	}
+	type dto struct {
+		ID     int    `param:"id,required"`
+		Page   int    `query:"page,required"`
+		Name   string `query:"name,required" json:"name"`
+		Sort   string `query:"sort"`
+		Filter string `query:"filter,required,trim"`
+	}
+
+	var testCases = []struct {
+		name             string
+		whenMethod       string
+		whenURL          string
+		whenBody         string
+		whenDetailed     bool
+		expect           dto
+		expectErr        string
+		expectMissing    []string
+		expectBodyUnread bool
+	}{
+		{
+			name:       "ok, all present",
+			whenMethod: http.MethodGet,
+			whenURL:    "/?page=2&name=joe&filter=%20a%20",
+			expect:     dto{ID: 1, Page: 2, Name: "joe", Filter: "a"},
+		},
+		{
+			name:       "ok, empty value is present",
+			whenMethod: http.MethodGet,
+			whenURL:    "/?page=0&name=&filter=",
+			expect:     dto{ID: 1},
+		},
+		{
+			name:       "ok, field bindable from body is satisfied by body",
+			whenMethod: http.MethodDelete,
+			whenURL:    "/?page=1&filter=x",
+			whenBody:   `{"name":"joe"}`,
+			expect:     dto{ID: 1, Page: 1, Name: "joe", Filter: "x"},
+		},
+		{
+			name:             "nok, all missing fields are reported before body is read",
+			whenMethod:       http.MethodGet,
+			whenURL:          "/?sort=asc&name=joe",
+			whenBody:         `{"name":"bob"}`,
+			expectErr:        `code=400, message=missing required query param "page", query param "filter", internal=missing required query param "page", query param "filter"`,
+			expectMissing:    []string{"query:page", "query:filter"},
+			expectBodyUnread: true,
+		},
+		{
+			name:          "nok, detailed field errors use the same message",
+			whenMethod:    http.MethodGet,
+			whenURL:       "/?page=1",
+			whenDetailed:  true,
+			expectErr:     `code=400, message=missing required query param "filter", internal=missing required query param "filter"`,
+			expectMissing: []string{"query:filter"},
+		},
+		{
+			name:          "nok, field bindable from body missing from both",
+			whenMethod:    http.MethodGet,
+			whenURL:       "/?page=1&filter=x",
+			whenBody:      `{"sort":"asc"}`,
+			expectErr:     `code=400, message=missing required query param "name", internal=missing required query param "name"`,
+			expectMissing: []string{"query:name"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			var body io.Reader
+			if tc.whenBody != "" {
+				body = strings.NewReader(tc.whenBody)
+			}
+			req := httptest.NewRequest(tc.whenMethod, tc.whenURL, body)
+			if tc.whenBody != "" {
+				req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+			c.SetParamNames("id")
+			c.SetParamValues("1")
+
+			result := dto{}
+			err := (&DefaultBinder{DetailedFieldErrors: tc.whenDetailed}).Bind(&result, c)
+
+			if tc.expectErr == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expect, result)
+				return
+			}
+			assert.EqualError(t, err, tc.expectErr)
+			assert.ErrorIs(t, err, ErrRequiredFieldMissing)
+
+			var requiredErr *RequiredFieldsError
+			if assert.ErrorAs(t, err, &requiredErr) {
+				missing := make([]string, 0, len(requiredErr.Fields))
+				for _, f := range requiredErr.Fields {
+					missing = append(missing, f.Source+":"+f.Name)
+				}
+				assert.Equal(t, tc.expectMissing, missing)
+			}
+			if tc.expectBodyUnread {
+				assert.Equal(t, "joe", result.Name)
+			}
+		})
+	}
+
+	t.Run("nok, nested struct without data", func(t *testing.T) {
+		e := New()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+		result := struct{ Meta nested }{}
+		err := new(DefaultBinder).BindHeaders(c, &result)
+
+		assert.EqualError(t, err, `code=400, message=missing required header param "X-Tenant", internal=missing required header param "X-Tenant"`)
+	})
+
+	t.Run("ok, path param missing", func(t *testing.T) {
+		e := New()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+		result := dto{}
+		err := new(DefaultBinder).BindPathParams(c, &result)
+
+		assert.EqualError(t, err, `code=400, message=missing required path param "id", internal=missing required path param "id"`)
+	})
+}
+
+func TestDefaultBinder_MaxSliceLength(t *testing.T) {
+	type dto struct {
+		IDs    []int     `query:"id" header:"X-Id"`
+		Tags   *[]string `query:"tag,max=2"`
+		Single int       `query:"single"`
+	}
+	repeat := func(key string, n int) string {
+		return strings.TrimSuffix(strings.Repeat(key+"=1&", n), "&")
+	}
+
+	var testCases = []struct {
+		name        string
+		givenBinder *DefaultBinder
+		whenQuery   string
+		expectIDs   int
+		expectErr   string
+	}{
+		{
+			name:        "ok, default limit",
+			givenBinder: &DefaultBinder{},
+			whenQuery:   repeat("id", 1024),
+			expectIDs:   1024,
+		},
+		{
+			name:        "nok, default limit exceeded",
+			givenBinder: &DefaultBinder{},
+			whenQuery:   repeat("id", 1025),
+			expectErr:   `code=400, message=too many values for field "id": got 1025, max 1024, internal=too many values for field "id": got 1025, max 1024`,
+		},
+		{
+			name:        "nok, configured limit exceeded",
+			givenBinder: &DefaultBinder{MaxSliceLength: 3, DetailedFieldErrors: true},
+			whenQuery:   repeat("id", 4),
+			expectErr:   `code=400, message=failed to bind query param "id" (value "1"): too many values for field "id": got 4, max 3, internal=too many values for field "id": got 4, max 3`,
+		},
+		{
+			name:        "ok, limit disabled",
+			givenBinder: &DefaultBinder{MaxSliceLength: -1},
+			whenQuery:   repeat("id", 2000),
+			expectIDs:   2000,
+		},
+		{
+			name:        "nok, tag modifier wins over disabled limit",
+			givenBinder: &DefaultBinder{MaxSliceLength: -1},
+			whenQuery:   repeat("tag", 3),
+			expectErr:   `code=400, message=too many values for field "tag": got 3, max 2, internal=too many values for field "tag": got 3, max 2`,
+		},
+		{
+			name:        "ok, scalar field takes first value",
+			givenBinder: &DefaultBinder{MaxSliceLength: 1},
+			whenQuery:   repeat("single", 5),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/?"+tc.whenQuery, nil), httptest.NewRecorder())
+
+			result := dto{}
+			err := tc.givenBinder.BindQueryParams(c, &result)
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				assert.ErrorIs(t, err, ErrTooManyValues)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, result.IDs, tc.expectIDs)
+		})
+	}
+
+	t.Run("nok, headers", func(t *testing.T) {
+		e := New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header["X-Id"] = []string{"1", "2", "3"}
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		err := (&DefaultBinder{MaxSliceLength: 2}).BindHeaders(c, &dto{})
+
+		assert.ErrorIs(t, err, ErrTooManyValues)
+	})
+
+	t.Run("nok, map destination", func(t *testing.T) {
+		e := New()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/?"+repeat("id", 3), nil), httptest.NewRecorder())
+
+		err := (&DefaultBinder{MaxSliceLength: 2}).BindQueryParams(c, &map[string][]int{})
+
+		assert.ErrorIs(t, err, ErrTooManyValues)
+	})
+
+	t.Run("nok, invalid tag modifier", func(t *testing.T) {
+		e := New()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/?id=1", nil), httptest.NewRecorder())
+
+		err := new(DefaultBinder).BindQueryParams(c, &struct {
+			IDs []int `query:"id,max=x"`
+		}{})
+
+		assert.EqualError(t, err, `code=400, message=field "id": invalid tag modifier "max=x", internal=field "id": invalid tag modifier "max=x"`)
+	})
+
+	t.Run("nok, multipart files", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		mw := multipart.NewWriter(buf)
+		for i := 0; i < 3; i++ {
+			w, err := mw.CreateFormFile("files", "file.txt")
+			assert.NoError(t, err)
+			_, _ = w.Write([]byte("x"))
+		}
+		assert.NoError(t, mw.Close())
+
+		e := New()
+		req := httptest.NewRequest(http.MethodPost, "/", buf)
+		req.Header.Set(HeaderContentType, mw.FormDataContentType())
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		result := struct {
+			Files []*multipart.FileHeader `form:"files,max=2"`
+		}{}
+		err := new(DefaultBinder).BindBody(c, &result)
+
+		assert.EqualError(t, err, `code=400, message=too many values for field "files": got 3, max 2, internal=too many values for field "files": got 3, max 2`)
+	})
+}
+
+func TestDefaultBinder_TrimSpace_mapDestination(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/?a=%201%20&b=2", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	result := map[string]string{}
+	err := (&DefaultBinder{TrimSpace: true}).BindQueryParams(c, &result)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "1", "b": "2"}, result)
+}
+
+func TestSnakeCaseName(t *testing.T) {
+	var testCases = []struct {
+		when        string
+		expectSnake string
+		expectKebab string
+	}{
+		{when: "", expectSnake: "", expectKebab: ""},
+		{when: "ID", expectSnake: "id", expectKebab: "id"},
+		{when: "Name", expectSnake: "name", expectKebab: "name"},
+		{when: "UserID", expectSnake: "user_id", expectKebab: "user-id"},
+		{when: "HTTPServer", expectSnake: "http_server", expectKebab: "http-server"},
+		{when: "PageSize2", expectSnake: "page_size2", expectKebab: "page-size2"},
+		{when: "Page2Size", expectSnake: "page2_size", expectKebab: "page2-size"},
+		{when: "lowerCase", expectSnake: "lower_case", expectKebab: "lower-case"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.when, func(t *testing.T) {
+			assert.Equal(t, tc.expectSnake, SnakeCaseName(tc.when))
+			assert.Equal(t, tc.expectKebab, KebabCaseName(tc.when))
+		})
+	}
+}
+
+type rawBody []byte
+
+func TestDefaultBinder_BindBodyToBytes(t *testing.T) {
+	var testCases = []struct {
+		name              string
+		givenContentType  string
+		givenMaxSize      int64
+		whenChunked       bool
+		whenBindTarget    interface{}
+		expect            interface{}
+		expectError       string
+		expectBodyRemains bool
+	}{
+		{
+			name:              "ok, []byte with any content type",
+			givenContentType:  "application/vnd.custom",
+			whenBindTarget:    new([]byte),
+			expect:            []byte("hello world"),
+			expectBodyRemains: true,
+		},
+		{
+			name:              "ok, named type with []byte underlying type",
+			givenContentType:  MIMEApplicationJSON,
+			whenBindTarget:    new(rawBody),
+			expect:            rawBody("hello world"),
+			expectBodyRemains: true,
+		},
+		{
+			name:              "ok, without content type and with chunked body",
+			whenChunked:       true,
+			whenBindTarget:    new([]byte),
+			expect:            []byte("hello world"),
+			expectBodyRemains: true,
+		},
+		{
+			name:           "nok, body is bigger than MaxRawBodySize",
+			givenMaxSize:   5,
+			whenBindTarget: new([]byte),
+			expect:         []byte(nil),
+			expectError:    "code=413, message=Request Entity Too Large",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hello world"))
+			if tc.givenContentType != "" {
+				req.Header.Set(HeaderContentType, tc.givenContentType)
+			}
+			if tc.whenChunked {
+				req.ContentLength = -1
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			b := &DefaultBinder{MaxRawBodySize: tc.givenMaxSize}
+			err := b.Bind(tc.whenBindTarget, c)
+
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expect, reflect.ValueOf(tc.whenBindTarget).Elem().Interface())
+			if tc.expectBodyRemains {
+				body, err := io.ReadAll(c.Request().Body)
+				assert.NoError(t, err)
+				assert.Equal(t, "hello world", string(body))
+			}
+		})
+	}
+}
+
+func TestDefaultBinder_BindBodyToBytes_respectsRouteBodyLimit(t *testing.T) {
+	e := New()
+	e.WithRouteOptions(RouteBodyLimit("5B")).POST("/", func(c Context) error {
+		var body []byte
+		return c.Bind(&body)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hello world"))
+	req.ContentLength = -1
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestDefaultBinder_BindBodyXML(t *testing.T) {
+	type attrNode struct {
+		ID   int    `xml:"id,attr"`
+		Node string `xml:"node,attr"`
+	}
+	type item struct {
+		Qty int `xml:"qty"`
+	}
+	type order struct {
+		Items []item `xml:"items>item"`
+	}
+	type items struct {
+		Items []item `xml:"item"`
+	}
+
+	var testCases = []struct {
+		name           string
+		givenBinder    *DefaultBinder
+		whenBody       string
+		whenBindTarget interface{}
+		expect         interface{}
+		expectError    string
+	}{
+		{
+			name:           "ok, attributes",
+			whenBody:       `<node id="1" node="yyy"/>`,
+			whenBindTarget: &attrNode{},
+			expect:         &attrNode{ID: 1, Node: "yyy"},
+		},
+		{
+			name:           "nok, attribute conversion error names element",
+			whenBody:       `<node id="x"/>`,
+			whenBindTarget: &attrNode{},
+			expectError:    `code=400, message=Unmarshal error: element=/node, error=strconv.ParseInt: parsing "x": invalid syntax, internal=strconv.ParseInt: parsing "x": invalid syntax`,
+		},
+		{
+			name:           "nok, element conversion error names element path",
+			whenBody:       `<order><items><item><qty>1</qty></item><item><qty>two</qty></item></items></order>`,
+			whenBindTarget: &order{},
+			expectError:    `code=400, message=Unmarshal error: element=/order/items/item[2]/qty, error=strconv.ParseInt: parsing "two": invalid syntax, internal=strconv.ParseInt: parsing "two": invalid syntax`,
+		},
+		{
+			name:           "nok, syntax error names element path",
+			whenBody:       "<order>\n<items><item></items></order>",
+			whenBindTarget: &order{},
+			expectError:    `code=400, message=Syntax error: line=2, element=/order/items/item, error=XML syntax error on line 2: element <item> closed by </items>, internal=XML syntax error on line 2: element <item> closed by </items>`,
+		},
+		{
+			name:           "ok, list bound to struct with slice field",
+			whenBody:       `<items><item><qty>1</qty></item><item><qty>2</qty></item></items>`,
+			whenBindTarget: &items{},
+			expect:         &items{Items: []item{{Qty: 1}, {Qty: 2}}},
+		},
+		{
+			name:           "nok, list bound to non-slice target",
+			whenBody:       `<items><item><qty>1</qty></item><item><qty>2</qty></item></items>`,
+			whenBindTarget: &item{},
+			expectError:    "code=400, message=XML document <items> is a list of <item> elements but bind target echo.item is not a slice, bind it to struct with slice field tagged `xml:\"item\"`, internal=XML document <items> is a list of <item> elements but bind target echo.item is not a slice, bind it to struct with slice field tagged `xml:\"item\"`",
+		},
+		{
+			name:           "ok, single element is not a list",
+			whenBody:       `<items><item><qty>1</qty></item></items>`,
+			whenBindTarget: &item{},
+			expect:         &item{},
+		},
+		{
+			name:           "nok, latin-1 without lenient charset",
+			whenBody:       `<?xml version="1.0" encoding="ISO-8859-1"?><node node="caf` + "\xe9" + `"/>`,
+			whenBindTarget: &attrNode{},
+			expectError:    `code=400, message=xml: encoding "ISO-8859-1" declared but Decoder.CharsetReader is nil, internal=xml: encoding "ISO-8859-1" declared but Decoder.CharsetReader is nil`,
+		},
+		{
+			name:           "ok, latin-1 with lenient charset",
+			givenBinder:    &DefaultBinder{LenientXMLCharset: true},
+			whenBody:       `<?xml version="1.0" encoding="ISO-8859-1"?><node node="caf` + "\xe9" + `"/>`,
+			whenBindTarget: &attrNode{},
+			expect:         &attrNode{Node: "café"},
+		},
+		{
+			name:           "ok, windows-1252 with lenient charset",
+			givenBinder:    &DefaultBinder{LenientXMLCharset: true},
+			whenBody:       `<?xml version="1.0" encoding="windows-1252"?><node node="` + "\x80\x93\xfc" + `"/>`,
+			whenBindTarget: &attrNode{},
+			expect:         &attrNode{Node: "€“ü"},
+		},
+		{
+			name:           "nok, unsupported charset with lenient charset",
+			givenBinder:    &DefaultBinder{LenientXMLCharset: true},
+			whenBody:       `<?xml version="1.0" encoding="koi8-r"?><node/>`,
+			whenBindTarget: &attrNode{},
+			expectError:    `code=400, message=xml: opening charset "koi8-r": xml: unsupported charset "koi8-r", internal=xml: opening charset "koi8-r": xml: unsupported charset "koi8-r"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.whenBody))
+			req.Header.Set(HeaderContentType, MIMEApplicationXML)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			binder := tc.givenBinder
+			if binder == nil {
+				binder = new(DefaultBinder)
+			}
+			err := binder.BindBody(c, tc.whenBindTarget)
+
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, tc.whenBindTarget)
+		})
+	}
+}
+
+func TestDefaultBinder_BindBodyGob(t *testing.T) {
+	type payload struct {
+		ID   int
+		Tags []string
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		assert.NoError(t, gob.NewEncoder(buf).Encode(payload{ID: 7, Tags: []string{"a", "b"}}))
+
+		e := New()
+		req := httptest.NewRequest(http.MethodPost, "/", buf)
+		req.Header.Set(HeaderContentType, MIMEApplicationGob)
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		result := payload{}
+		err := (&DefaultBinder{EnableGob: true}).BindBody(c, &result)
+
+		assert.NoError(t, err)
+		assert.Equal(t, payload{ID: 7, Tags: []string{"a", "b"}}, result)
+	})
+
+	t.Run("nok, gob is not enabled", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		assert.NoError(t, gob.NewEncoder(buf).Encode(payload{ID: 7}))
+
+		e := New()
+		req := httptest.NewRequest(http.MethodPost, "/", buf)
+		req.Header.Set(HeaderContentType, MIMEApplicationGob)
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		result := payload{}
+		err := new(DefaultBinder).BindBody(c, &result)
+
+		assert.EqualError(t, err, `code=415, message=Unsupported Media Type: received "application/x-gob", supported: application/json, application/xml, text/xml, application/x-www-form-urlencoded, multipart/form-data, internal=unsupported media type "application/x-gob"`)
+		assert.Equal(t, payload{}, result)
+	})
+
+	t.Run("nok, unsupported media type lists gob when enabled", func(t *testing.T) {
+		e := New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("a,b"))
+		req.Header.Set(HeaderContentType, "text/csv")
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		err := (&DefaultBinder{EnableGob: true}).BindBody(c, &payload{})
+
+		var he *HTTPError
+		if assert.ErrorAs(t, err, &he) {
+			assert.Equal(t, http.StatusUnsupportedMediaType, he.Code)
+			assert.Contains(t, he.Message, "multipart/form-data, application/x-gob")
+		}
+	})
+
+	t.Run("nok, invalid gob", func(t *testing.T) {
+		e := New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("not gob"))
+		req.Header.Set(HeaderContentType, MIMEApplicationGob)
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		err := (&DefaultBinder{EnableGob: true}).BindBody(c, &payload{})
+
+		var he *HTTPError
+		if assert.ErrorAs(t, err, &he) {
+			assert.Equal(t, http.StatusBadRequest, he.Code)
+			assert.Error(t, he.Internal)
+		}
+	})
+}
+
+func TestDefaultBinder_UnsupportedMediaTypeError(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		whenBind      func(b *DefaultBinder, c Context) error
+		expectMessage string
+		expectError   UnsupportedMediaTypeError
+	}{
+		{
+			name: "BindBody",
+			whenBind: func(b *DefaultBinder, c Context) error {
+				return b.BindBody(c, &struct{}{})
+			},
+			expectMessage: `Unsupported Media Type: received "text/csv", supported: application/json, application/xml, text/xml, application/x-www-form-urlencoded, multipart/form-data`,
+			expectError: UnsupportedMediaTypeError{
+				ContentType: "text/csv",
+				Supported:   []string{MIMEApplicationJSON, MIMEApplicationXML, MIMETextXML, MIMEApplicationForm, MIMEMultipartForm},
+			},
+		},
+		{
+			name: "BindForm",
+			whenBind: func(b *DefaultBinder, c Context) error {
+				return b.BindForm(c, &struct{}{})
+			},
+			expectMessage: `Unsupported Media Type: received "text/csv", supported: application/x-www-form-urlencoded, multipart/form-data`,
+			expectError: UnsupportedMediaTypeError{
+				ContentType: "text/csv",
+				Supported:   []string{MIMEApplicationForm, MIMEMultipartForm},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("a,b"))
+			req.Header.Set(HeaderContentType, "text/csv")
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			err := tc.whenBind(new(DefaultBinder), c)
+
+			assert.ErrorIs(t, err, ErrUnsupportedMediaType)
+			var he *HTTPError
+			if assert.ErrorAs(t, err, &he) {
+				assert.Equal(t, http.StatusUnsupportedMediaType, he.Code)
+				assert.Equal(t, tc.expectMessage, he.Message)
+			}
+			var mte *UnsupportedMediaTypeError
+			if assert.ErrorAs(t, err, &mte) {
+				assert.Equal(t, tc.expectError, *mte)
+			}
+		})
+	}
+}
+
+func TestDefaultBinder_BindFieldError(t *testing.T) {
+	type dto struct {
+		ID   int        `param:"id"`
+		Addr netip.Addr `param:"addr"`
+		Tags []uint8    `query:"tag"`
+	}
+
+	var testCases = []struct {
+		name          string
+		givenDetailed bool
+		whenAddr      string
+		whenURL       string
+		expectErr     string
+		expectField   BindFieldError
+	}{
+		{
+			name:        "nok, path param",
+			whenAddr:    "10.0.0.1",
+			whenURL:     "/?tag=1",
+			expectErr:   `code=400, message=strconv.ParseInt: parsing "abc": invalid syntax, internal=strconv.ParseInt: parsing "abc": invalid syntax`,
+			expectField: BindFieldError{Source: "path", Name: "id", Value: "abc"},
+		},
+		{
+			name:          "nok, path param with custom scalar type, detailed message",
+			givenDetailed: true,
+			whenAddr:      "10.0.0.300",
+			expectErr:     `code=400, message=failed to bind path param "addr" (value "10.0.0.300"): ParseAddr("10.0.0.300"): IPv4 field has value >255, internal=ParseAddr("10.0.0.300"): IPv4 field has value >255`,
+			expectField:   BindFieldError{Source: "path", Name: "addr", Value: "10.0.0.300"},
+		},
+		{
+			name:          "nok, query param slice, value is truncated",
+			givenDetailed: true,
+			whenAddr:      "10.0.0.1",
+			whenURL:       "/?tag=1&tag=" + strings.Repeat("9", 70),
+			expectErr:     `code=400, message=failed to bind query param "tag" (value "` + strings.Repeat("9", 64) + `..."): strconv.ParseUint: parsing "` + strings.Repeat("9", 70) + `": value out of range, internal=strconv.ParseUint: parsing "` + strings.Repeat("9", 70) + `": value out of range`,
+			expectField:   BindFieldError{Source: "query", Name: "tag", Value: strings.Repeat("9", 64) + "..."},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.Binder = &DefaultBinder{DetailedFieldErrors: tc.givenDetailed}
+			url := tc.whenURL
+			if url == "" {
+				url = "/"
+			}
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, url, nil), httptest.NewRecorder())
+			id := "1"
+			if tc.expectField.Name == "id" {
+				id = "abc"
+			}
+			c.SetParamNames("id", "addr")
+			c.SetParamValues(id, tc.whenAddr)
+
+			err := c.Bind(&dto{})
+
+			assert.EqualError(t, err, tc.expectErr)
+			var fieldErr *BindFieldError
+			if assert.ErrorAs(t, err, &fieldErr) {
+				assert.Equal(t, tc.expectField.Source, fieldErr.Source)
+				assert.Equal(t, tc.expectField.Name, fieldErr.Name)
+				assert.Equal(t, tc.expectField.Value, fieldErr.Value)
+			}
+		})
+	}
+}
+
+func TestDefaultBinder_excludedFields(t *testing.T) {
+	type account struct {
+		Name    string `json:"name" query:"name" form:"name"`
+		IsAdmin bool   `json:"is_admin" query:"-" form:"-" param:"-" header:"-"`
+		Role    string `json:"role"`
+	}
+
+	var testCases = []struct {
+		name        string
+		givenBinder *DefaultBinder
+		whenURL     string
+		expect      account
+	}{
+		{
+			name:        "ok, mass assignment with exact name is prevented",
+			givenBinder: &DefaultBinder{FallbackToJSONTag: true},
+			whenURL:     "/?name=jon&is_admin=true&IsAdmin=true&-=true&role=root",
+			expect:      account{Name: "jon", Role: "root"},
+		},
+		{
+			name:        "ok, fallback binding is disabled",
+			givenBinder: &DefaultBinder{FallbackToJSONTag: true, DisableFallbackBinding: true},
+			whenURL:     "/?name=jon&is_admin=true&role=root",
+			expect:      account{Name: "jon"},
+		},
+		{
+			name:        "ok, case-insensitive match is disabled with fallback binding",
+			givenBinder: &DefaultBinder{DisableFallbackBinding: true},
+			whenURL:     "/?NAME=jon",
+			expect:      account{},
+		},
+		{
+			name:        "ok, case-insensitive match",
+			givenBinder: &DefaultBinder{},
+			whenURL:     "/?NAME=jon",
+			expect:      account{Name: "jon"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			result := account{}
+			err := tc.givenBinder.BindQueryParams(c, &result)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, result)
+		})
+	}
+}
+
+func TestDefaultBinder_excludedFields_formAndHeaders(t *testing.T) {
+	type account struct {
+		Name    string `form:"name" header:"name"`
+		IsAdmin bool   `form:"-" header:"-"`
+	}
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=jon&IsAdmin=true&-=true"))
+	req.Header.Set(HeaderContentType, MIMEApplicationForm)
+	req.Header.Set("Name", "header-jon")
+	req.Header.Set("IsAdmin", "true")
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	binder := &DefaultBinder{DisableFallbackBinding: true}
+
+	result := account{}
+	assert.NoError(t, binder.BindForm(c, &result))
+	assert.Equal(t, account{Name: "jon"}, result)
+
+	result = account{}
+	assert.NoError(t, binder.BindHeaders(c, &result))
+	assert.Equal(t, account{Name: "header-jon"}, result)
+}
+
 func TestBindHeaderParam(t *testing.T) {
 	e := New()
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
@@ -440,20 +1591,120 @@ func TestBindUnmarshalTextPtr(t *testing.T) {
 	}
 }
 
-func TestBindMultipartForm(t *testing.T) {
-	bodyBuffer := new(bytes.Buffer)
-	mw := multipart.NewWriter(bodyBuffer)
-	mw.WriteField("id", "1")
-	mw.WriteField("name", "Jon Snow")
-	mw.Close()
-	body := bodyBuffer.Bytes()
-
-	testBindOkay(t, bytes.NewReader(body), nil, mw.FormDataContentType())
-	testBindOkay(t, bytes.NewReader(body), dummyQuery, mw.FormDataContentType())
-}
+func TestBindMultipartForm(t *testing.T) {
+	bodyBuffer := new(bytes.Buffer)
+	mw := multipart.NewWriter(bodyBuffer)
+	mw.WriteField("id", "1")
+	mw.WriteField("name", "Jon Snow")
+	mw.Close()
+	body := bodyBuffer.Bytes()
+
+	testBindOkay(t, bytes.NewReader(body), nil, mw.FormDataContentType())
+	testBindOkay(t, bytes.NewReader(body), dummyQuery, mw.FormDataContentType())
+}
+
+func TestBindUnsupportedMediaType(t *testing.T) {
+	testBindError(t, strings.NewReader(invalidContent), MIMEApplicationJSON, &json.SyntaxError{})
+}
+
+func TestDefaultBinder_BindPathParamsToMap(t *testing.T) {
+	e := New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/?q=1&q=2", nil), httptest.NewRecorder())
+	c.SetParamNames("id", "name")
+	c.SetParamValues("1", "jon")
+
+	params := map[string]string{}
+	assert.NoError(t, (&DefaultBinder{}).BindPathParams(c, &params))
+	assert.Equal(t, map[string]string{"id": "1", "name": "jon"}, params)
+
+	// path params and query params (first value) through Bind
+	all := map[string]string{}
+	assert.NoError(t, c.Bind(&all))
+	assert.Equal(t, map[string]string{"id": "1", "name": "jon", "q": "1"}, all)
+
+	allValues := map[string][]string{}
+	assert.NoError(t, c.Bind(&allValues))
+	assert.Equal(t, map[string][]string{"id": {"1"}, "name": {"jon"}, "q": {"1", "2"}}, allValues)
+}
+
+func TestDefaultBinder_BindPathParams_wildcard(t *testing.T) {
+	type target struct {
+		Star string `param:"*"`
+		Path string `param:"path"`
+		ID   int    `param:"id"`
+	}
+	var testCases = []struct {
+		name              string
+		givenWildcardName string
+		whenRoute         string
+		whenURL           string
+		expect            target
+	}{
+		{
+			name:      "ok, wildcard is bound only to * by default",
+			whenRoute: "/files/:id/*",
+			whenURL:   "/files/1/docs/readme.md",
+			expect:    target{Star: "docs/readme.md", ID: 1},
+		},
+		{
+			name:              "ok, wildcard is bound also to configured name",
+			givenWildcardName: "path",
+			whenRoute:         "/files/:id/*",
+			whenURL:           "/files/1/docs/readme.md",
+			expect:            target{Star: "docs/readme.md", Path: "docs/readme.md", ID: 1},
+		},
+		{
+			name:              "ok, named path param takes precedence over wildcard name",
+			givenWildcardName: "path",
+			whenRoute:         "/files/:path/*",
+			whenURL:           "/files/root/docs/readme.md",
+			expect:            target{Star: "docs/readme.md", Path: "root"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.Binder = &DefaultBinder{WildcardParamName: tc.givenWildcardName}
+			var result target
+			e.GET(tc.whenRoute, func(c Context) error {
+				return c.Bind(&result)
+			})
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.whenURL, nil))
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tc.expect, result)
+		})
+	}
+}
+
+func TestDefaultBinder_Bind_pathAndQueryParamWithSameName(t *testing.T) {
+	type target struct {
+		PathID  string `param:"id"`
+		QueryID string `query:"id"`
+	}
+	e := New()
+	var result target
+	e.GET("/users/:id", func(c Context) error {
+		return c.Bind(&result)
+	})
 
-func TestBindUnsupportedMediaType(t *testing.T) {
-	testBindError(t, strings.NewReader(invalidContent), MIMEApplicationJSON, &json.SyntaxError{})
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/path-1?id=query-1", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	// each field is bound only from the source of its tag
+	assert.Equal(t, target{PathID: "path-1", QueryID: "query-1"}, result)
+
+	// for map destination query params are bound after path params and win
+	all := map[string]string{}
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/?id=query-1", nil), httptest.NewRecorder())
+	c.SetParamNames("id")
+	c.SetParamValues("path-1")
+	assert.NoError(t, c.Bind(&all))
+	assert.Equal(t, map[string]string{"id": "query-1"}, all)
 }
 
 func TestDefaultBinder_bindDataToMap(t *testing.T) {
@@ -534,31 +1785,142 @@ func TestDefaultBinder_bindDataToMap(t *testing.T) {
 		)
 	})
 
-	t.Run("ok, bind to map[string]int skips", func(t *testing.T) {
+	t.Run("ok, bind to map[string]int skips with LegacyMapBinding", func(t *testing.T) {
 		dest := map[string]int{}
-		assert.NoError(t, new(DefaultBinder).bindData(&dest, exampleData, "param", nil))
+		assert.NoError(t, (&DefaultBinder{LegacyMapBinding: true}).bindData(&dest, exampleData, "param", nil))
 		assert.Equal(t, map[string]int{}, dest)
 	})
 
-	t.Run("ok, bind to map[string]int skips with nil map", func(t *testing.T) {
+	t.Run("ok, bind to map[string]int skips with LegacyMapBinding with nil map", func(t *testing.T) {
 		var dest map[string]int
-		assert.NoError(t, new(DefaultBinder).bindData(&dest, exampleData, "param", nil))
+		assert.NoError(t, (&DefaultBinder{LegacyMapBinding: true}).bindData(&dest, exampleData, "param", nil))
 		assert.Equal(t, map[string]int(nil), dest)
 	})
 
-	t.Run("ok, bind to map[string][]int skips", func(t *testing.T) {
+	t.Run("ok, bind to map[string][]int skips with LegacyMapBinding", func(t *testing.T) {
 		dest := map[string][]int{}
-		assert.NoError(t, new(DefaultBinder).bindData(&dest, exampleData, "param", nil))
+		assert.NoError(t, (&DefaultBinder{LegacyMapBinding: true}).bindData(&dest, exampleData, "param", nil))
 		assert.Equal(t, map[string][]int{}, dest)
 	})
 
-	t.Run("ok, bind to map[string][]int skips with nil map", func(t *testing.T) {
+	t.Run("ok, bind to map[string][]int skips with LegacyMapBinding with nil map", func(t *testing.T) {
 		var dest map[string][]int
-		assert.NoError(t, new(DefaultBinder).bindData(&dest, exampleData, "param", nil))
+		assert.NoError(t, (&DefaultBinder{LegacyMapBinding: true}).bindData(&dest, exampleData, "param", nil))
 		assert.Equal(t, map[string][]int(nil), dest)
 	})
 }
 
+type bindTestMapKey string
+
+func (k *bindTestMapKey) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return errors.New("empty key")
+	}
+	*k = bindTestMapKey(strings.ToUpper(string(text)))
+	return nil
+}
+
+type bindTestMapValue struct {
+	Value string
+}
+
+func (v *bindTestMapValue) UnmarshalParam(param string) error {
+	if param == "invalid" {
+		return errors.New("invalid value")
+	}
+	v.Value = "<" + param + ">"
+	return nil
+}
+
+func TestDefaultBinder_bindDataToMap_conversion(t *testing.T) {
+	exampleData := map[string][]string{
+		"multiple": {"1", "2"},
+		"single":   {"3"},
+	}
+
+	t.Run("ok, bind to map[string]int, first value wins", func(t *testing.T) {
+		dest := map[string]int{}
+		assert.NoError(t, new(DefaultBinder).bindData(&dest, exampleData, "param", nil))
+		assert.Equal(t, map[string]int{"multiple": 1, "single": 3}, dest)
+	})
+
+	t.Run("ok, bind to map[string]int with nil map", func(t *testing.T) {
+		var dest map[string]int
+		assert.NoError(t, new(DefaultBinder).bindData(&dest, exampleData, "param", nil))
+		assert.Equal(t, map[string]int{"multiple": 1, "single": 3}, dest)
+	})
+
+	t.Run("ok, bind to map[string][]int with nil map", func(t *testing.T) {
+		var dest map[string][]int
+		assert.NoError(t, new(DefaultBinder).bindData(&dest, exampleData, "param", nil))
+		assert.Equal(t, map[string][]int{"multiple": {1, 2}, "single": {3}}, dest)
+	})
+
+	t.Run("ok, bind to map[string]time.Duration as int64 kind", func(t *testing.T) {
+		var dest map[string]time.Duration
+		data := map[string][]string{"timeout": {"1000"}}
+		assert.NoError(t, new(DefaultBinder).bindData(&dest, data, "query", nil))
+		assert.Equal(t, map[string]time.Duration{"timeout": 1000}, dest)
+	})
+
+	t.Run("ok, bind to map[CustomKey]CustomValue", func(t *testing.T) {
+		var dest map[bindTestMapKey]bindTestMapValue
+		assert.NoError(t, new(DefaultBinder).bindData(&dest, exampleData, "query", nil))
+		assert.Equal(t,
+			map[bindTestMapKey]bindTestMapValue{
+				"MULTIPLE": {Value: "<1>"},
+				"SINGLE":   {Value: "<3>"},
+			},
+			dest,
+		)
+	})
+
+	t.Run("ok, bind to map[CustomKey][]*CustomValue", func(t *testing.T) {
+		var dest map[bindTestMapKey][]*bindTestMapValue
+		assert.NoError(t, new(DefaultBinder).bindData(&dest, map[string][]string{"a": {"1", "2"}}, "query", nil))
+		assert.Equal(t,
+			map[bindTestMapKey][]*bindTestMapValue{"A": {{Value: "<1>"}, {Value: "<2>"}}},
+			dest,
+		)
+	})
+
+	t.Run("ok, bind to map with unsupported value type skips", func(t *testing.T) {
+		var dest map[string]struct{ A int }
+		assert.NoError(t, new(DefaultBinder).bindData(&dest, exampleData, "query", nil))
+		assert.Nil(t, dest)
+	})
+
+	t.Run("nok, value conversion fails", func(t *testing.T) {
+		var dest map[string]int
+		err := new(DefaultBinder).bindData(&dest, map[string][]string{"id": {"abc"}}, "query", nil)
+
+		var fieldErr *BindFieldError
+		if assert.ErrorAs(t, err, &fieldErr) {
+			assert.Equal(t, BindFieldError{Source: "query", Name: "id", Value: "abc", Err: fieldErr.Err}, *fieldErr)
+		}
+	})
+
+	t.Run("nok, custom value conversion fails", func(t *testing.T) {
+		var dest map[bindTestMapKey]bindTestMapValue
+		err := new(DefaultBinder).bindData(&dest, map[string][]string{"id": {"invalid"}}, "query", nil)
+		assert.EqualError(t, err, "invalid value")
+	})
+
+	t.Run("nok, key conversion fails", func(t *testing.T) {
+		var dest map[int]string
+		err := new(DefaultBinder).bindData(&dest, map[string][]string{"x": {"1"}}, "query", nil)
+		assert.EqualError(t, err, `strconv.ParseInt: parsing "x": invalid syntax`)
+	})
+
+	t.Run("nok, 400 from BindQueryParams", func(t *testing.T) {
+		e := New()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/?limit=many", nil), httptest.NewRecorder())
+		var dest map[string]uint
+		err := new(DefaultBinder).BindQueryParams(c, &dest)
+		assert.EqualError(t, err, `code=400, message=strconv.ParseUint: parsing "many": invalid syntax, internal=strconv.ParseUint: parsing "many": invalid syntax`)
+	})
+}
+
 func TestBindbindData(t *testing.T) {
 	ts := new(bindTestStruct)
 	b := new(DefaultBinder)
@@ -763,7 +2125,8 @@ func testBindError(t *testing.T, r io.Reader, ctype string, expectedInternal err
 		}
 	default:
 		if assert.IsType(t, new(HTTPError), err) {
-			assert.Equal(t, ErrUnsupportedMediaType, err)
+			assert.ErrorIs(t, err, ErrUnsupportedMediaType)
+			assert.Equal(t, http.StatusUnsupportedMediaType, err.(*HTTPError).Code)
 			assert.IsType(t, expectedInternal, err.(*HTTPError).Internal)
 		}
 	}
@@ -1040,7 +2403,7 @@ func TestDefaultBinder_BindBody(t *testing.T) {
 			givenContentType: MIMEApplicationXML,
 			givenContent:     strings.NewReader(`<node><`),
 			expect:           &Node{ID: 0, Node: ""},
-			expectError:      "code=400, message=Syntax error: line=1, error=XML syntax error on line 1: unexpected EOF, internal=XML syntax error on line 1: unexpected EOF",
+			expectError:      "code=400, message=Syntax error: line=1, element=/node, error=XML syntax error on line 1: unexpected EOF, internal=XML syntax error on line 1: unexpected EOF",
 		},
 		{
 			name:             "ok, FORM POST bind to struct with: path + query + body",
@@ -1077,7 +2440,7 @@ func TestDefaultBinder_BindBody(t *testing.T) {
 			givenContentType: MIMETextPlain,
 			givenContent:     strings.NewReader(`<html></html>`),
 			expect:           &Node{ID: 0, Node: ""},
-			expectError:      "code=415, message=Unsupported Media Type",
+			expectError:      `code=415, message=Unsupported Media Type: received "", supported: application/json, application/xml, text/xml, application/x-www-form-urlencoded, multipart/form-data, internal=unsupported media type ""`,
 		},
 		{
 			name:             "nok, JSON POST with http.NoBody",
@@ -1533,6 +2896,85 @@ func TestBindMultipartFormFiles(t *testing.T) {
 	})
 }
 
+func TestBindMultipartFormCatchAll(t *testing.T) {
+	file1 := createTestFormFile("file", "file1.txt")
+	avatar := createTestFormFile("avatar", "avatar.png")
+	docA := createTestFormFile("docs", "docA.txt")
+	docB := createTestFormFile("docs", "docB.txt")
+
+	bind := func(t *testing.T, target interface{}) error {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		assert.NoError(t, mw.WriteField("name", "Jon"))
+		assert.NoError(t, mw.WriteField("color", "red"))
+		assert.NoError(t, mw.WriteField("color", "blue"))
+		for _, file := range []testFormFile{file1, avatar, docA, docB} {
+			fw, err := mw.CreateFormFile(file.Fieldname, file.Filename)
+			assert.NoError(t, err)
+			_, err = fw.Write(file.Content)
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, mw.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/", &body)
+		req.Header.Set(HeaderContentType, mw.FormDataContentType())
+		c := New().NewContext(req, httptest.NewRecorder())
+		return c.Bind(target)
+	}
+
+	t.Run("ok, tag-less file map receives all files", func(t *testing.T) {
+		var target struct {
+			Files map[string][]*multipart.FileHeader
+		}
+		err := bind(t, &target)
+
+		assert.NoError(t, err)
+		assert.Len(t, target.Files, 3)
+		assertMultipartFileHeader(t, target.Files["file"][0], file1)
+		assertMultipartFileHeader(t, target.Files["avatar"][0], avatar)
+		if assert.Len(t, target.Files["docs"], 2) {
+			assertMultipartFileHeader(t, target.Files["docs"][0], docA)
+			assertMultipartFileHeader(t, target.Files["docs"][1], docB)
+		}
+	})
+
+	t.Run("ok, explicit fields and catch-all fields both receive their keys", func(t *testing.T) {
+		var target struct {
+			Name   string                             `form:"name"`
+			Avatar *multipart.FileHeader              `form:"avatar"`
+			Files  map[string][]*multipart.FileHeader `form:"*"`
+			Values map[string][]string                `form:"*"`
+		}
+		err := bind(t, &target)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Jon", target.Name)
+		assertMultipartFileHeader(t, target.Avatar, avatar)
+		assert.Len(t, target.Files, 3)
+		assertMultipartFileHeader(t, target.Files["avatar"][0], avatar)
+		assert.Equal(t, map[string][]string{"name": {"Jon"}, "color": {"red", "blue"}}, target.Values)
+	})
+
+	t.Run("ok, tag-less string map is not catch-all", func(t *testing.T) {
+		var target struct {
+			Values map[string][]string
+		}
+		err := bind(t, &target)
+
+		assert.NoError(t, err)
+		assert.Nil(t, target.Values)
+	})
+
+	t.Run("nok, unsupported catch-all field type", func(t *testing.T) {
+		var target struct {
+			Values map[string]string `form:"*"`
+		}
+		err := bind(t, &target)
+
+		assert.EqualError(t, err, "code=400, message=form catch-all field must be map[string][]*multipart.FileHeader or map[string][]string, internal=form catch-all field must be map[string][]*multipart.FileHeader or map[string][]string")
+	})
+}
+
 type testFormFile struct {
 	Fieldname string
 	Filename  string
@@ -1585,3 +3027,445 @@ func assertMultipartFileHeader(t *testing.T, fh *multipart.FileHeader, file test
 	err = fl.Close()
 	assert.NoError(t, err)
 }
+
+type bindFuzzNode struct {
+	Name string `query:"name" param:"name" header:"name" form:"name"`
+	Next *bindFuzzNode
+}
+
+type BindFuzzEmbedded struct {
+	Embedded string `query:"embedded" form:"embedded"`
+}
+
+type bindFuzzTarget struct {
+	*BindFuzzEmbedded
+	Int       int               `query:"int" param:"int" header:"int" form:"int"`
+	Int8      int8              `query:"int8" form:"int8"`
+	Uint64    uint64            `query:"uint64" form:"uint64"`
+	Float32   float32           `query:"float32" form:"float32"`
+	Bool      bool              `query:"bool" form:"bool"`
+	String    string            `query:"string" param:"string" header:"string" form:"string"`
+	IntPtr    *int              `query:"intptr" form:"intptr"`
+	IntPtrPtr **int             `query:"intptrptr" form:"intptrptr"`
+	Ints      []int             `query:"ints" form:"ints"`
+	IntPtrs   []*int            `query:"intptrs" form:"intptrs"`
+	StringsP  *[]string         `query:"stringsp" form:"stringsp"`
+	Array     [2]int            `query:"array" form:"array"`
+	Map       map[string]string `query:"map" form:"map"`
+	Any       interface{}       `query:"any" form:"any"`
+	Time      time.Time         `query:"time" form:"time"`
+	TS        *Timestamp        `query:"ts" form:"ts"`
+	SA        StringArray       `query:"sa" form:"sa"`
+	IA        IntArrayA         `query:"ia" form:"ia"`
+	Nested    struct {
+		Value string `query:"nested" form:"nested" json:"nested"`
+	}
+	NoTag    string `json:"notag"`
+	unexport string `query:"unexport"`
+	Node     bindFuzzNode
+}
+
+var bindFuzzFieldNames = []string{"int", "int8", "uint64", "float32", "bool", "string", "intptr", "intptrptr", "ints",
+	"intptrs", "stringsp", "array", "map", "any", "time", "ts", "sa", "ia", "nested", "name", "notag", "unexport", "embedded"}
+
+func FuzzDefaultBinder_bindData(f *testing.F) {
+	f.Add(uint8(0), "int", "1", uint16(1))
+	f.Add(uint8(1), "ints", "99999999999999999999999999", uint16(300))
+	f.Add(uint8(2), "float32", "1e400", uint16(1))
+	f.Add(uint8(3), "string", "\xff\xfe\xfd", uint16(2))
+	f.Add(uint8(4), "a[b][c]", strings.Repeat("x", 4096), uint16(1))
+	f.Add(uint8(5), "INTPTRS", "-0x1", uint16(3))
+
+	tags := []string{"query", "param", "header", "form"}
+	f.Fuzz(func(t *testing.T, options uint8, key string, value string, repeat uint16) {
+		values := make([]string, int(repeat%512)+1)
+		for i := range values {
+			values[i] = value
+		}
+		data := map[string][]string{key: values}
+		for _, name := range bindFuzzFieldNames {
+			if _, ok := data[name]; !ok {
+				data[name] = []string{value}
+			}
+		}
+		b := &DefaultBinder{
+			FallbackToJSONTag:      options&4 != 0,
+			DisableFallbackBinding: options&8 != 0,
+			DetailedFieldErrors:    options&16 != 0,
+		}
+		if options&64 != 0 {
+			b.NameTransform = SnakeCaseName
+		}
+		tag := tags[int(options)%len(tags)]
+
+		target := bindFuzzTarget{}
+		if options&32 != 0 {
+			target.BindFuzzEmbedded = &BindFuzzEmbedded{}
+		}
+		if err := b.bindData(&target, data, tag, nil); err != nil {
+			if he := b.bindDataError(err); he.Code != http.StatusBadRequest {
+				t.Fatalf("unexpected error code %v for %v", he.Code, err)
+			}
+		}
+		if len(target.Ints) > len(values) || len(target.IntPtrs) > len(values) {
+			t.Fatalf("bound slice is longer than input: %v > %v", len(target.Ints), len(values))
+		}
+
+		m := map[string]string{}
+		_ = b.bindData(&m, data, tag, nil)
+		if len(m) > len(data) {
+			t.Fatalf("bound map is bigger than input: %v > %v", len(m), len(data))
+		}
+	})
+}
+
+func TestDefaultBinder_bindData_selfReferencingStruct(t *testing.T) {
+	target := bindFuzzNode{}
+	err := new(DefaultBinder).bindData(&target, map[string][]string{"name": {"x"}}, "query", nil)
+
+	assert.ErrorIs(t, err, errBindNestingTooDeep)
+	assert.Equal(t, "x", target.Name)
+}
+
+func TestDefaultBinder_hooks(t *testing.T) {
+	type target struct {
+		ID   int    `query:"id" json:"id"`
+		Page int    `query:"page,required"`
+		Name string `query:"name,required"`
+	}
+	type bindError struct {
+		source string
+		field  string
+	}
+	var testCases = []struct {
+		name         string
+		whenURL      string
+		whenBody     string
+		whenBind     func(b *DefaultBinder, c Context, dest *target) error
+		expectErrors []bindError
+	}{
+		{
+			name:     "ok, no errors",
+			whenURL:  "/?id=1&page=2&name=jon",
+			whenBody: `{"id":1}`,
+			whenBind: func(b *DefaultBinder, c Context, dest *target) error {
+				return b.Bind(dest, c)
+			},
+		},
+		{
+			name:    "nok, query param conversion",
+			whenURL: "/?id=abc",
+			whenBind: func(b *DefaultBinder, c Context, dest *target) error {
+				return b.BindQueryParams(c, dest)
+			},
+			expectErrors: []bindError{{source: "query", field: "id"}},
+		},
+		{
+			name:     "nok, malformed body",
+			whenURL:  "/",
+			whenBody: `{"id":`,
+			whenBind: func(b *DefaultBinder, c Context, dest *target) error {
+				return b.BindBody(c, dest)
+			},
+			expectErrors: []bindError{{source: "body"}},
+		},
+		{
+			name:     "nok, every missing required field is reported",
+			whenURL:  "/",
+			whenBody: `{}`,
+			whenBind: func(b *DefaultBinder, c Context, dest *target) error {
+				return b.Bind(dest, c)
+			},
+			expectErrors: []bindError{{source: "query", field: "page"}, {source: "query", field: "name"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var errs []bindError
+			completed := 0
+			b := &DefaultBinder{
+				OnBindError: func(c Context, source string, field string, err error) {
+					assert.Error(t, err)
+					errs = append(errs, bindError{source: source, field: field})
+				},
+				OnBindComplete: func(c Context, duration time.Duration) {
+					completed++
+				},
+			}
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, strings.NewReader(tc.whenBody))
+			req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+			c := New().NewContext(req, httptest.NewRecorder())
+
+			err := tc.whenBind(b, c, &target{})
+
+			assert.Equal(t, tc.expectErrors, errs)
+			assert.Equal(t, len(tc.expectErrors) > 0, err != nil)
+			assert.Equal(t, 1, completed)
+		})
+	}
+}
+
+func TestDefaultBinder_fieldAliases(t *testing.T) {
+	type target struct {
+		Query string   `query:"q|query|search" header:"X-Query|X-Search" form:"q|query"`
+		Tags  []string `query:"tag|tags"`
+		Page  int      `query:"page|p,required"`
+	}
+	var testCases = []struct {
+		name          string
+		givenConflict AliasConflictMode
+		whenURL       string
+		whenHeaders   map[string]string
+		whenForm      string
+		whenBind      func(b *DefaultBinder, c Context, dest *target) error
+		expect        target
+		expectError   string
+	}{
+		{
+			name:    "ok, primary name",
+			whenURL: "/?q=echo&tag=a&tag=b&page=1",
+			whenBind: func(b *DefaultBinder, c Context, dest *target) error {
+				return b.BindQueryParams(c, dest)
+			},
+			expect: target{Query: "echo", Tags: []string{"a", "b"}, Page: 1},
+		},
+		{
+			name:    "ok, alias",
+			whenURL: "/?search=echo&tags=a&p=2",
+			whenBind: func(b *DefaultBinder, c Context, dest *target) error {
+				return b.BindQueryParams(c, dest)
+			},
+			expect: target{Query: "echo", Tags: []string{"a"}, Page: 2},
+		},
+		{
+			name:    "ok, first wins in tag order",
+			whenURL: "/?search=third&query=second&page=1",
+			whenBind: func(b *DefaultBinder, c Context, dest *target) error {
+				return b.BindQueryParams(c, dest)
+			},
+			expect: target{Query: "second", Page: 1},
+		},
+		{
+			name:          "ok, conflict error with equal values",
+			givenConflict: AliasConflictError,
+			whenURL:       "/?q=echo&search=echo&page=1",
+			whenBind: func(b *DefaultBinder, c Context, dest *target) error {
+				return b.BindQueryParams(c, dest)
+			},
+			expect: target{Query: "echo", Page: 1},
+		},
+		{
+			name:          "nok, conflict error",
+			givenConflict: AliasConflictError,
+			whenURL:       "/?q=echo&search=other&page=1",
+			whenBind: func(b *DefaultBinder, c Context, dest *target) error {
+				return b.BindQueryParams(c, dest)
+			},
+			expectError: `code=400, message=conflicting values of alternative names: "q" and "search", internal=conflicting values of alternative names: "q" and "search"`,
+		},
+		{
+			name:    "nok, missing required reports primary name",
+			whenURL: "/?q=echo",
+			whenBind: func(b *DefaultBinder, c Context, dest *target) error {
+				return b.BindQueryParams(c, dest)
+			},
+			expectError: `code=400, message=missing required query param "page", internal=missing required query param "page"`,
+		},
+		{
+			name:        "ok, header alias",
+			whenURL:     "/",
+			whenHeaders: map[string]string{"X-Search": "echo"},
+			whenBind: func(b *DefaultBinder, c Context, dest *target) error {
+				return b.BindHeaders(c, dest)
+			},
+			expect: target{Query: "echo"},
+		},
+		{
+			name:     "ok, form alias",
+			whenURL:  "/",
+			whenForm: "query=echo",
+			whenBind: func(b *DefaultBinder, c Context, dest *target) error {
+				return b.BindBody(c, dest)
+			},
+			expect: target{Query: "echo"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var req *http.Request
+			if tc.whenForm != "" {
+				req = httptest.NewRequest(http.MethodPost, tc.whenURL, strings.NewReader(tc.whenForm))
+				req.Header.Set(HeaderContentType, MIMEApplicationForm)
+			} else {
+				req = httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			}
+			for k, v := range tc.whenHeaders {
+				req.Header.Set(k, v)
+			}
+			c := New().NewContext(req, httptest.NewRecorder())
+
+			b := &DefaultBinder{AliasConflict: tc.givenConflict}
+			var dest target
+			err := tc.whenBind(b, c, &dest)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, dest)
+		})
+	}
+}
+
+func TestDefaultBinder_restField(t *testing.T) {
+	type Nested struct {
+		Sort string `query:"sort"`
+	}
+	type target struct {
+		Query  string     `query:"q|search" form:"q"`
+		Page   int        `query:"page"`
+		Hidden string     `query:"-"`
+		Rest   url.Values `query:",rest" form:",rest"`
+		Nested
+		Headers http.Header `header:",rest"`
+		Agent   string      `header:"User-Agent"`
+	}
+	var testCases = []struct {
+		name        string
+		givenBinder *DefaultBinder
+		whenURL     string
+		whenHeaders map[string]string
+		whenForm    string
+		whenBind    func(b *DefaultBinder, c Context, dest *target) error
+		expect      target
+	}{
+		{
+			name:    "ok, query rest keeps multi-values",
+			whenURL: "/?q=echo&page=2&x=1&x=2&y=&sort=name",
+			whenBind: func(b *DefaultBinder, c Context, dest *target) error {
+				return b.BindQueryParams(c, dest)
+			},
+			expect: target{
+				Query:  "echo",
+				Page:   2,
+				Rest:   url.Values{"x": {"1", "2"}, "y": {""}},
+				Nested: Nested{Sort: "name"},
+			},
+		},
+		{
+			name:    "ok, all present aliases are consumed",
+			whenURL: "/?q=echo&search=other&x=1",
+			whenBind: func(b *DefaultBinder, c Context, dest *target) error {
+				return b.BindQueryParams(c, dest)
+			},
+			expect: target{Query: "echo", Rest: url.Values{"x": {"1"}}},
+		},
+		{
+			name:    "ok, case-insensitive fallback is consumed",
+			whenURL: "/?PAGE=3&x=1",
+			whenBind: func(b *DefaultBinder, c Context, dest *target) error {
+				return b.BindQueryParams(c, dest)
+			},
+			expect: target{Page: 3, Rest: url.Values{"x": {"1"}}},
+		},
+		{
+			name:        "ok, without fallback case differing key is rest",
+			givenBinder: &DefaultBinder{DisableFallbackBinding: true},
+			whenURL:     "/?PAGE=3&q=echo",
+			whenBind: func(b *DefaultBinder, c Context, dest *target) error {
+				return b.BindQueryParams(c, dest)
+			},
+			expect: target{Query: "echo", Rest: url.Values{"PAGE": {"3"}}},
+		},
+		{
+			name:        "ok, excluded field is rest and values are not trimmed",
+			givenBinder: &DefaultBinder{TrimSpace: true},
+			whenURL:     "/?Hidden=x&q=%20echo%20&other=%20a%20",
+			whenBind: func(b *DefaultBinder, c Context, dest *target) error {
+				return b.BindQueryParams(c, dest)
+			},
+			expect: target{Query: "echo", Rest: url.Values{"Hidden": {"x"}, "other": {" a "}}},
+		},
+		{
+			name:    "ok, nothing left",
+			whenURL: "/?q=echo",
+			whenBind: func(b *DefaultBinder, c Context, dest *target) error {
+				return b.BindQueryParams(c, dest)
+			},
+			expect: target{Query: "echo"},
+		},
+		{
+			name:     "ok, form rest",
+			whenURL:  "/",
+			whenForm: "q=echo&token=abc",
+			whenBind: func(b *DefaultBinder, c Context, dest *target) error {
+				return b.BindForm(c, dest)
+			},
+			expect: target{Query: "echo", Rest: url.Values{"token": {"abc"}}},
+		},
+		{
+			name:        "ok, header rest",
+			whenURL:     "/",
+			whenHeaders: map[string]string{"User-Agent": "test", "X-Trace": "1"},
+			whenBind: func(b *DefaultBinder, c Context, dest *target) error {
+				return b.BindHeaders(c, dest)
+			},
+			expect: target{Agent: "test", Headers: http.Header{"X-Trace": {"1"}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var req *http.Request
+			if tc.whenForm != "" {
+				req = httptest.NewRequest(http.MethodPost, tc.whenURL, strings.NewReader(tc.whenForm))
+				req.Header.Set(HeaderContentType, MIMEApplicationForm)
+			} else {
+				req = httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			}
+			for k, v := range tc.whenHeaders {
+				req.Header.Set(k, v)
+			}
+			c := New().NewContext(req, httptest.NewRecorder())
+
+			b := tc.givenBinder
+			if b == nil {
+				b = &DefaultBinder{}
+			}
+			var dest target
+			err := tc.whenBind(b, c, &dest)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, dest)
+		})
+	}
+}
+
+func TestDefaultBinder_restField_composite(t *testing.T) {
+	var dest struct {
+		Created TimeRange  `query:"created"`
+		Rest    url.Values `query:",rest"`
+	}
+	c := New().NewContext(httptest.NewRequest(http.MethodGet, "/?created_from=2024-01-01T00:00:00Z&created_to=2024-01-02T00:00:00Z&x=1", nil), httptest.NewRecorder())
+
+	assert.NoError(t, (&DefaultBinder{}).BindQueryParams(c, &dest))
+	assert.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), dest.Created.To)
+	assert.Equal(t, url.Values{"x": {"1"}}, dest.Rest)
+}
+
+func TestDefaultBinder_restField_invalid(t *testing.T) {
+	c := New().NewContext(httptest.NewRequest(http.MethodGet, "/?x=1", nil), httptest.NewRecorder())
+
+	var named struct {
+		Rest url.Values `query:"rest,rest"`
+	}
+	err := (&DefaultBinder{}).BindQueryParams(c, &named)
+	assert.EqualError(t, err, "code=400, message=rest field Rest must be map[string][]string (ala url.Values) without name in query tag, internal=rest field Rest must be map[string][]string (ala url.Values) without name in query tag")
+
+	var wrongType struct {
+		Rest map[string]string `query:",rest"`
+	}
+	err = (&DefaultBinder{}).BindQueryParams(c, &wrongType)
+	assert.Error(t, err)
+}
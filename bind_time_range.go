@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrTimeRangeOrder is returned (wrapped into BindFieldError) when start of TimeRange is after its end.
+	ErrTimeRangeOrder = errors.New("time range start is after its end")
+	// ErrTimeRangeTooLong is returned (wrapped into BindFieldError) when TimeRange is longer than its MaxSpan.
+	ErrTimeRangeTooLong = errors.New("time range is too long")
+)
+
+// TimeRange is time filter bound from two request keys. Field with tag `query:"created"` is bound from
+// `created_from` and `created_to` query params, embedded TimeRange without tag is bound from `from` and `to`. Either
+// end can be omitted for open ranges. Range is validated after binding so handlers do not have to check the ordering.
+//
+// Tag modifiers:
+//   - `layout=<layout>` sets time layout of the values (default: time.RFC3339). Layout can not contain commas.
+//   - `maxspan=<duration>` sets MaxSpan (ala `maxspan=720h`).
+//
+// Example:
+//
+//	type listOrders struct {
+//		Created echo.TimeRange `query:"created,layout=2006-01-02,maxspan=2160h"`
+//	}
+type TimeRange struct {
+	From time.Time
+	To   time.Time
+	// MaxSpan is maximum duration between From and To. Checked only when both are set. Zero means no limit.
+	MaxSpan time.Duration
+
+	fromKey string
+	toKey   string
+}
+
+// UnmarshalParamsComposite implements BindCompositeUnmarshaler interface.
+func (r *TimeRange) UnmarshalParamsComposite(name string, modifiers string, values func(key string) ([]string, bool)) error {
+	layout := time.RFC3339
+	for modifiers != "" {
+		var modifier string
+		modifier, modifiers, _ = strings.Cut(modifiers, ",")
+		key, value, _ := strings.Cut(strings.TrimSpace(modifier), "=")
+		switch key {
+		case "layout":
+			layout = value
+		case "maxspan":
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid maxspan tag modifier: %w", err)
+			}
+			r.MaxSpan = d
+		}
+	}
+
+	r.fromKey, r.toKey = "from", "to"
+	if name != "" {
+		r.fromKey, r.toKey = name+"_from", name+"_to"
+	}
+	if err := parseTimeRangeEnd(r.fromKey, layout, values, &r.From); err != nil {
+		return err
+	}
+	if err := parseTimeRangeEnd(r.toKey, layout, values, &r.To); err != nil {
+		return err
+	}
+	return r.Validate()
+}
+
+func parseTimeRangeEnd(key string, layout string, values func(key string) ([]string, bool), dst *time.Time) error {
+	v, ok := values(key)
+	if !ok || len(v) == 0 || v[0] == "" {
+		return nil
+	}
+	t, err := time.Parse(layout, v[0])
+	if err != nil {
+		return &BindFieldError{Name: key, Value: v[0], Err: err}
+	}
+	*dst = t
+	return nil
+}
+
+// Validate checks that From is not after To and that the range is not longer than MaxSpan. Returned error is
+// `*BindFieldError` naming the `to` key of the range.
+func (r TimeRange) Validate() error {
+	if r.From.IsZero() || r.To.IsZero() {
+		return nil
+	}
+	toKey := r.toKey
+	if toKey == "" {
+		toKey = "to"
+	}
+	if r.To.Before(r.From) {
+		return &BindFieldError{Name: toKey, Value: r.To.Format(time.RFC3339), Err: ErrTimeRangeOrder}
+	}
+	if r.MaxSpan > 0 && r.To.Sub(r.From) > r.MaxSpan {
+		return &BindFieldError{Name: toKey, Value: r.To.Format(time.RFC3339), Err: ErrTimeRangeTooLong}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeRange_bind(t *testing.T) {
+	type dto struct {
+		Created TimeRange  `query:"created"`
+		Shipped *TimeRange `query:"shipped,layout=2006-01-02,maxspan=48h"`
+	}
+	date := func(s string) time.Time {
+		d, _ := time.Parse(time.RFC3339, s)
+		return d
+	}
+
+	var testCases = []struct {
+		name          string
+		whenURL       string
+		expectCreated [2]time.Time
+		expectShipped *[2]time.Time
+		expectErr     string
+		expectField   string
+	}{
+		{
+			name:          "ok, both ends",
+			whenURL:       "/?created_from=2024-01-01T00:00:00Z&created_to=2024-02-01T00:00:00Z",
+			expectCreated: [2]time.Time{date("2024-01-01T00:00:00Z"), date("2024-02-01T00:00:00Z")},
+		},
+		{
+			name:          "ok, open range",
+			whenURL:       "/?created_from=2024-01-01T00:00:00Z",
+			expectCreated: [2]time.Time{date("2024-01-01T00:00:00Z"), {}},
+		},
+		{
+			name:          "ok, custom layout on pointer field",
+			whenURL:       "/?shipped_from=2024-01-01&shipped_to=2024-01-02",
+			expectShipped: &[2]time.Time{date("2024-01-01T00:00:00Z"), date("2024-01-02T00:00:00Z")},
+		},
+		{
+			name:        "nok, invalid time",
+			whenURL:     "/?created_from=yesterday",
+			expectErr:   `code=400, message=failed to bind query param "created_from" (value "yesterday"): parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006", internal=parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006"`,
+			expectField: "created_from",
+		},
+		{
+			name:        "nok, from after to",
+			whenURL:     "/?created_from=2024-02-01T00:00:00Z&created_to=2024-01-01T00:00:00Z",
+			expectErr:   `code=400, message=failed to bind query param "created_to" (value "2024-01-01T00:00:00Z"): time range start is after its end, internal=time range start is after its end`,
+			expectField: "created_to",
+		},
+		{
+			name:        "nok, range longer than maxspan",
+			whenURL:     "/?shipped_from=2024-01-01&shipped_to=2024-01-04",
+			expectErr:   `code=400, message=failed to bind query param "shipped_to" (value "2024-01-04T00:00:00Z"): time range is too long, internal=time range is too long`,
+			expectField: "shipped_to",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, tc.whenURL, nil), httptest.NewRecorder())
+
+			result := dto{}
+			err := (&DefaultBinder{DetailedFieldErrors: true}).BindQueryParams(c, &result)
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				var fieldErr *BindFieldError
+				if assert.True(t, errors.As(err, &fieldErr)) {
+					assert.Equal(t, "query", fieldErr.Source)
+					assert.Equal(t, tc.expectField, fieldErr.Name)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectCreated, [2]time.Time{result.Created.From, result.Created.To})
+			if tc.expectShipped == nil {
+				assert.Nil(t, result.Shipped)
+			} else if assert.NotNil(t, result.Shipped) {
+				assert.Equal(t, *tc.expectShipped, [2]time.Time{result.Shipped.From, result.Shipped.To})
+				assert.Equal(t, 48*time.Hour, result.Shipped.MaxSpan)
+			}
+		})
+	}
+}
+
+func TestTimeRange_bindEmbedded(t *testing.T) {
+	type dto struct {
+		TimeRange
+		Status string `query:"status"`
+	}
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/?from=2024-01-01T00:00:00Z&to=2024-01-02T00:00:00Z&status=paid", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	result := dto{}
+	err := c.Bind(&result)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "paid", result.Status)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), result.From)
+	assert.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), result.To)
+}
+
+func TestTimeRange_Validate(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, TimeRange{From: from}.Validate())
+	assert.NoError(t, TimeRange{From: from, To: from.Add(time.Hour), MaxSpan: time.Hour}.Validate())
+	assert.ErrorIs(t, TimeRange{From: from, To: from.Add(-time.Hour)}.Validate(), ErrTimeRangeOrder)
+	assert.ErrorIs(t, TimeRange{From: from, To: from.Add(2 * time.Hour), MaxSpan: time.Hour}.Validate(), ErrTimeRangeTooLong)
+}
@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// bindXML decodes XML body into i. Errors name the path of the element (ala `/order/items/item[2]/qty`) where decoding
+// failed. Document that is list of same elements (ala `<users><user/><user/></users>`) decoded into struct without
+// any value set results error as it is usually bound to wrong (non-slice) target.
+func (b *DefaultBinder) bindXML(body io.Reader, i interface{}) error {
+	source := xml.NewDecoder(body)
+	if b.LenientXMLCharset {
+		source.CharsetReader = lenientCharsetReader
+	}
+	tracker := &xmlPathTracker{source: source}
+	if err := xml.NewTokenDecoder(tracker).Decode(i); err != nil {
+		path := tracker.path()
+		element := ""
+		if path != "" {
+			element = ", element=" + path
+		}
+		if ute, ok := err.(*xml.UnsupportedTypeError); ok {
+			return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported type error: type=%v%s, error=%v", ute.Type, element, ute.Error())).SetInternal(err)
+		} else if se, ok := err.(*xml.SyntaxError); ok {
+			return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Syntax error: line=%v%s, error=%v", se.Line, element, se.Error())).SetInternal(err)
+		} else if path != "" && err != io.EOF {
+			return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unmarshal error: element=%s, error=%v", path, err)).SetInternal(err)
+		}
+		return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+
+	if listItem, ok := tracker.listItem(); ok {
+		target := reflect.ValueOf(i)
+		for target.Kind() == reflect.Ptr && !target.IsNil() {
+			target = target.Elem()
+		}
+		if target.Kind() == reflect.Struct && target.IsZero() {
+			err := fmt.Errorf("XML document <%s> is a list of <%s> elements but bind target %v is not a slice, "+
+				"bind it to struct with slice field tagged `xml:\"%s\"`", tracker.root, listItem, target.Type(), listItem)
+			return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+	}
+	return nil
+}
+
+// xmlPathTracker passes tokens of the source decoder through and keeps track of the currently open elements so
+// decoding errors can name the element where they happened.
+type xmlPathTracker struct {
+	source *xml.Decoder
+	// stack holds open elements, each with counts of its child elements by name
+	stack []xmlTrackedElement
+	// closed is the last closed element. Values of elements are converted when the element is closed.
+	closed string
+	// root is name of the document element and rootChildren counts its direct children by name
+	root         string
+	rootChildren map[string]int
+}
+
+type xmlTrackedElement struct {
+	label    string
+	children map[string]int
+}
+
+// Token returns next token of the source decoder.
+func (t *xmlPathTracker) Token() (xml.Token, error) {
+	token, err := t.source.Token()
+	switch el := token.(type) {
+	case xml.StartElement:
+		t.closed = ""
+		label := el.Name.Local
+		if n := len(t.stack); n > 0 {
+			parent := t.stack[n-1]
+			parent.children[label]++
+			if index := parent.children[label]; index > 1 {
+				label += "[" + strconv.Itoa(index) + "]"
+			}
+		} else if t.root == "" {
+			t.root = el.Name.Local
+		}
+		t.stack = append(t.stack, xmlTrackedElement{label: label, children: map[string]int{}})
+		if len(t.stack) == 1 {
+			t.rootChildren = t.stack[0].children
+		}
+	case xml.EndElement:
+		if n := len(t.stack); n > 0 {
+			t.closed = t.stack[n-1].label
+			t.stack = t.stack[:n-1]
+		}
+	}
+	return token, err
+}
+
+// path returns path of the element the decoder is at (ala `/order/items/item[2]/qty`).
+func (t *xmlPathTracker) path() string {
+	var sb strings.Builder
+	for _, el := range t.stack {
+		sb.WriteString("/")
+		sb.WriteString(el.label)
+	}
+	if t.closed != "" {
+		sb.WriteString("/")
+		sb.WriteString(t.closed)
+	}
+	return sb.String()
+}
+
+// listItem returns name of the repeated element when the document element has at least two children and all of them
+// have the same name.
+func (t *xmlPathTracker) listItem() (string, bool) {
+	if len(t.rootChildren) != 1 {
+		return "", false
+	}
+	for name, count := range t.rootChildren {
+		return name, count > 1
+	}
+	return "", false
+}
+
+// windows1252 maps bytes 0x80-0x9F of Windows-1252 to runes. Other bytes map to the same code point as in ISO-8859-1.
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
+
+// lenientCharsetReader is `xml.Decoder.CharsetReader` that converts ISO-8859-1 (latin-1), Windows-1252 and US-ASCII
+// input to UTF-8.
+func lenientCharsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "iso8859-1", "latin1", "latin-1", "l1":
+		return &singleByteReader{source: input}, nil
+	case "windows-1252", "cp1252", "x-cp1252":
+		return &singleByteReader{source: input, high: &windows1252}, nil
+	case "us-ascii", "ascii", "utf-8", "utf8":
+		return input, nil
+	}
+	return nil, fmt.Errorf("xml: unsupported charset %q", charset)
+}
+
+// singleByteReader converts single byte encoded input to UTF-8.
+type singleByteReader struct {
+	source io.Reader
+	// high maps bytes 0x80-0x9F to runes. Nil means ISO-8859-1 (same code point as the byte).
+	high    *[32]rune
+	pending []byte
+}
+
+func (r *singleByteReader) Read(p []byte) (int, error) {
+	if len(r.pending) > 0 {
+		n := copy(p, r.pending)
+		r.pending = r.pending[n:]
+		return n, nil
+	}
+	// every input byte results at most 3 bytes of UTF-8
+	in := make([]byte, len(p)/utf8.UTFMax+1)
+	n, err := r.source.Read(in)
+	out := make([]byte, 0, n*utf8.UTFMax)
+	for _, c := range in[:n] {
+		ch := rune(c)
+		if r.high != nil && c >= 0x80 && c <= 0x9F {
+			ch = r.high[c-0x80]
+		}
+		out = utf8.AppendRune(out, ch)
+	}
+	written := copy(p, out)
+	r.pending = out[written:]
+	if len(r.pending) > 0 {
+		return written, nil
+	}
+	return written, err
+}
@@ -106,7 +106,7 @@ type ValueBinder struct {
 }
 
 // QueryParamsBinder creates query parameter value binder
-func QueryParamsBinder(c Context) *ValueBinder {
+func QueryParamsBinder(c QueryReader) *ValueBinder {
 	return &ValueBinder{
 		failFast:  true,
 		ValueFunc: c.QueryParam,
@@ -122,7 +122,7 @@ func QueryParamsBinder(c Context) *ValueBinder {
 }
 
 // PathParamsBinder creates path parameter value binder
-func PathParamsBinder(c Context) *ValueBinder {
+func PathParamsBinder(c ParamReader) *ValueBinder {
 	return &ValueBinder{
 		failFast:  true,
 		ValueFunc: c.Param,
@@ -152,14 +152,14 @@ func FormFieldBinder(c Context) *ValueBinder {
 	vb := &ValueBinder{
 		failFast: true,
 		ValueFunc: func(sourceParam string) string {
-			return c.Request().FormValue(sourceParam)
+			return c.FormValue(sourceParam)
 		},
 		ErrorFunc: NewBindingError,
 	}
 	vb.ValuesFunc = func(sourceParam string) []string {
 		if c.Request().Form == nil {
-			// this is same as `Request().FormValue()` does internally
-			_ = c.Request().ParseMultipartForm(32 << 20)
+			// parses with configured multipart memory limit. Errors are ignored the same way as `FormValue()` does.
+			_, _ = c.FormParams()
 		}
 		values, ok := c.Request().Form[sourceParam]
 		if !ok {
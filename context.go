@@ -5,111 +5,118 @@ package echo
 
 import (
 	"bytes"
+	stdContext "context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
-	"net"
 	"net/http"
+	"net/netip"
 	"net/url"
+	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
-// Context represents the context of the current HTTP request. It holds request and
-// response objects, path, path parameters, data and registered handler.
-type Context interface {
+// RequestAccessor provides access to the request of the context.
+type RequestAccessor interface {
 	// Request returns `*http.Request`.
 	Request() *http.Request
 
 	// SetRequest sets `*http.Request`.
 	SetRequest(r *http.Request)
+}
 
-	// SetResponse sets `*Response`.
-	SetResponse(r *Response)
-
+// ResponseWriterAccessor provides access to the response writer of the context.
+type ResponseWriterAccessor interface {
 	// Response returns `*Response`.
 	Response() *Response
 
-	// IsTLS returns true if HTTP connection is TLS otherwise false.
-	IsTLS() bool
-
-	// IsWebSocket returns true if HTTP connection is WebSocket otherwise false.
-	IsWebSocket() bool
-
-	// Scheme returns the HTTP protocol scheme, `http` or `https`.
-	Scheme() string
-
-	// RealIP returns the client's network address based on `X-Forwarded-For`
-	// or `X-Real-IP` request header.
-	// The behavior can be configured using `Echo#IPExtractor`.
-	RealIP() string
+	// SetResponse sets `*Response`.
+	SetResponse(r *Response)
+}
 
+// ParamReader reads path parameters of the matched route.
+type ParamReader interface {
 	// Path returns the registered path for the handler.
 	Path() string
 
-	// SetPath sets the registered path for the handler.
-	SetPath(p string)
-
 	// Param returns path parameter by name.
 	Param(name string) string
 
+	// RawParam returns path parameter by name in its raw (URL-encoded) form as it was sent in request path. Useful
+	// for proxy-style handlers that need to forward captured value without `%2F` turning into a path separator.
+	RawParam(name string) string
+
+	// ParamSegments returns path parameter (ala wildcard `*` of `/files/*` route) split by `/` into decoded segments.
+	// Segments are split from the raw (URL-encoded) value so encoded slash (`%2F`) stays inside its segment. Empty
+	// segments (ala double or trailing slash) are kept. Returns nil when parameter is empty.
+	ParamSegments(name string) []string
+
 	// ParamNames returns path parameter names.
 	ParamNames() []string
 
-	// SetParamNames sets path parameter names.
-	SetParamNames(names ...string)
-
 	// ParamValues returns path parameter values.
 	ParamValues() []string
 
-	// SetParamValues sets path parameter values.
-	SetParamValues(values ...string)
+	// PathParamsMap returns path parameters as map of name to value.
+	PathParamsMap() map[string]string
+
+	// MatrixParam returns the matrix parameter (`/users;role=admin/42`) of path segment. Segment is either name of the
+	// route path param or the path segment itself (without matrix parameters). Requires `Echo#MatrixParams`.
+	MatrixParam(segment string, name string) string
 
+	// MatrixParams returns matrix parameters of all path segments. Requires `Echo#MatrixParams`.
+	MatrixParams() url.Values
+}
+
+// QueryReader reads query parameters of the request.
+type QueryReader interface {
 	// QueryParam returns the query param for the provided name.
 	QueryParam(name string) string
 
-	// QueryParams returns the query parameters as `url.Values`.
+	// QueryParams returns the query parameters as `url.Values`. Query is parsed according to `Echo#QueryParseMode`.
 	QueryParams() url.Values
 
+	// QueryParamIter calls fn for every query parameter in order they appear in the query string until fn returns
+	// false. Unlike `QueryParams` it does not allocate `url.Values`. Parameters are decoded the same way as
+	// `url.ParseQuery` does it (with `;` as separator in `QueryParseSemicolon` mode).
+	QueryParamIter(fn func(key, value string) bool)
+
 	// QueryString returns the URL query string.
 	QueryString() string
+}
 
-	// FormValue returns the form field value for the provided name.
-	FormValue(name string) string
-
-	// FormParams returns the form parameters as `url.Values`.
-	FormParams() (url.Values, error)
-
-	// FormFile returns the multipart form file for the provided name.
-	FormFile(name string) (*multipart.FileHeader, error)
-
-	// MultipartForm returns the multipart form.
-	MultipartForm() (*multipart.Form, error)
-
-	// Cookie returns the named cookie provided in the request.
-	Cookie(name string) (*http.Cookie, error)
-
-	// SetCookie adds a `Set-Cookie` header in HTTP response.
-	SetCookie(cookie *http.Cookie)
+// RequestBinder binds and validates request data with binder and validator of the Echo instance.
+type RequestBinder interface {
+	// Bind binds path params, query params and the request body into provided type `i`. The default binder
+	// binds body based on Content-Type header.
+	Bind(i interface{}) error
 
-	// Cookies returns the HTTP cookies sent with the request.
-	Cookies() []*http.Cookie
+	// BindQuery binds only query params into provided type `i`. Single source bind methods (BindQuery, BindForm,
+	// BindHeaders and BindBody) return ErrBinderNotSingleSource when `Echo#Binder` does not implement
+	// SingleSourceBinder.
+	BindQuery(i interface{}) error
 
-	// Get retrieves data from the context.
-	Get(key string) interface{}
+	// BindForm binds only form fields from request body into provided type `i`. URL query params are not included.
+	BindForm(i interface{}) error
 
-	// Set saves data in the context.
-	Set(key string, val interface{})
+	// BindHeaders binds only request headers into provided type `i`.
+	BindHeaders(i interface{}) error
 
-	// Bind binds path params, query params and the request body into provided type `i`. The default binder
-	// binds body based on Content-Type header.
-	Bind(i interface{}) error
+	// BindBody binds only request body into provided type `i` based on Content-Type header.
+	BindBody(i interface{}) error
 
 	// Validate validates provided `i`. It is usually called after `Context#Bind()`.
 	// Validator must be registered using `Echo#Validator`.
 	Validate(i interface{}) error
+}
 
+// ResponseRenderer sends response of the request. Response is committed by the first call.
+type ResponseRenderer interface {
 	// Render renders a template with data and sends a text/html response with status
 	// code. Renderer must be registered using `Echo.Renderer`.
 	Render(code int, name string, data interface{}) error
@@ -170,26 +177,194 @@ type Context interface {
 
 	// Redirect redirects the request to a provided URL with status code.
 	Redirect(code int, url string) error
+}
+
+// Store holds request scoped data.
+type Store interface {
+	// Get retrieves data from the context.
+	Get(key string) interface{}
+
+	// Set saves data in the context.
+	Set(key string, val interface{})
+}
+
+// Context represents the context of the current HTTP request. It holds request and
+// response objects, path, path parameters, data and registered handler.
+//
+// Code that needs only part of the context should depend on the capability interface it embeds (ala `ParamReader` or
+// `Store`). Wrap Context with `ContextDecorator` instead of implementing all of its methods.
+type Context interface {
+	RequestAccessor
+	ResponseWriterAccessor
+	ParamReader
+	QueryReader
+	RequestBinder
+	ResponseRenderer
+	Store
+
+	// IsTLS returns true if HTTP connection is TLS otherwise false.
+	IsTLS() bool
+
+	// ConnectionInfo returns information about connection the request arrived on (local and remote address, TLS
+	// state and protocol).
+	ConnectionInfo() ConnectionInfo
+
+	// IsWebSocket returns true if HTTP connection is WebSocket otherwise false.
+	IsWebSocket() bool
+
+	// IsInternal returns true if request was dispatched with `Echo#ServeInternal` instead of coming from the network.
+	IsInternal() bool
+
+	// ExpectsContinue returns true if client sent `Expect: 100-continue` header and waits for the server to accept
+	// the request before sending the body. Go HTTP server sends "100 Continue" automatically on first read of the
+	// request body.
+	ExpectsContinue() bool
+
+	// RejectContinue rejects request with given status code without reading the request body so the client that
+	// waits for "100 Continue" does not send the body. Returned error must be returned from the handler/middleware
+	// to be sent to the client by the error handler. Connection is closed after the response by Go HTTP server.
+	RejectContinue(code int, err error) error
+
+	// Scheme returns the HTTP protocol scheme, `http` or `https`.
+	Scheme() string
+
+	// RealIP returns the client's network address based on `X-Forwarded-For`
+	// or `X-Real-IP` request header.
+	// The behavior can be configured using `Echo#IPExtractor`.
+	// Returns empty string when address can not be parsed.
+	RealIP() string
+
+	// RealIPAddr returns the client's network address parsed as netip.Addr. Port and brackets around IPv6 address
+	// are stripped and IPv4-mapped IPv6 addresses are unmapped. Returns error wrapping ErrInvalidIPAddress when
+	// address can not be parsed. RealIP is the string form of this address.
+	RealIPAddr() (netip.Addr, error)
+
+	// SetPath sets the registered path for the handler.
+	SetPath(p string)
+
+	// RouteScopes returns access scopes declared for the matched route with `RequireScopes` route option. Returns nil
+	// when route has no scopes.
+	RouteScopes() []string
+
+	// RouteName returns name of the matched route (`Route.Name`). Returns empty string when no route matched or when
+	// called before routing (ala in middleware added with `Echo#Pre`).
+	RouteName() string
+
+	// SetParamNames sets path parameter names.
+	SetParamNames(names ...string)
+
+	// SetParamValues sets path parameter values.
+	SetParamValues(values ...string)
+
+	// FormValue returns the form field value for the provided name.
+	FormValue(name string) string
+
+	// FormParams returns the form parameters as `url.Values`. Parsing the body is aborted with "408 - Request Timeout"
+	// or "499 - Client Closed Request" error when the request context is done.
+	FormParams() (url.Values, error)
+
+	// FormFile returns the multipart form file for the provided name.
+	FormFile(name string) (*multipart.FileHeader, error)
+
+	// MultipartForm returns the multipart form. Parsing the body is aborted the same way as with FormParams when the
+	// request context is done.
+	MultipartForm() (*multipart.Form, error)
+
+	// SaveUploadedFile saves uploaded file to dst path. Checksum (sha256 by default) is computed during the copy and
+	// file is written to temporary file that is renamed to dst only when the copy has succeeded. See `SaveOption`.
+	SaveUploadedFile(fh *multipart.FileHeader, dst string, opts ...SaveOption) (SaveResult, error)
+
+	// SaveUploadedFileTo copies uploaded file to the writer computing its checksum.
+	SaveUploadedFileTo(fh *multipart.FileHeader, w io.Writer, opts ...SaveOption) (SaveResult, error)
+
+	// PutUploadedFile stores uploaded file with given name using the putter (ala S3 adapter) computing its checksum.
+	PutUploadedFile(fh *multipart.FileHeader, putter FilePutter, name string, opts ...SaveOption) (SaveResult, error)
+
+	// Cookie returns the named cookie provided in the request.
+	Cookie(name string) (*http.Cookie, error)
+
+	// SetCookie adds a `Set-Cookie` header in HTTP response.
+	SetCookie(cookie *http.Cookie)
+
+	// Cookies returns the HTTP cookies sent with the request.
+	Cookies() []*http.Cookie
+
+	// SetCookieValue adds `Set-Cookie` header with secure defaults (`Path=/`, `HttpOnly`, `SameSite=Lax` and `Secure`
+	// for https requests) to the response. Values with characters not allowed in cookies result ErrInvalidCookieValue.
+	SetCookieValue(name, value string, opts ...CookieOption) error
+
+	// DeleteCookie adds `Set-Cookie` header that deletes the cookie. Path and Domain options must match the cookie.
+	DeleteCookie(name string, opts ...CookieOption) error
+
+	// SignedCookie sets cookie with value signed with `Echo#CookieSigningKeys`. See `Context#ReadSignedCookie`.
+	SignedCookie(name, value string, opts ...CookieOption) error
+
+	// ReadSignedCookie returns value of cookie set with `Context#SignedCookie` after its signature has been verified.
+	ReadSignedCookie(name string) (string, error)
+
+	// MultipartStream starts streaming `multipart/x-mixed-replace` response (ala MJPEG camera stream). Parts are written
+	// with `PartWriter#NextPart` and stream is finished with `PartWriter#Close`. Empty boundary means random boundary.
+	MultipartStream(boundary string) *PartWriter
+
+	// ProgressStream starts streaming JSON lines response with progress updates of long-running operation. Records
+	// are written with `ProgressStream#Update` and stream is finished with `ProgressStream#Finish`.
+	ProgressStream(code int) *ProgressStream
+
+	// CachePolicy returns builder of `Cache-Control` header of the response. Header is written when the response is
+	// committed.
+	CachePolicy() *CachePolicy
+
+	// NotModified sends "304 - Not Modified" response keeping `ETag`, `Cache-Control` and `Vary` headers.
+	NotModified() error
+
+	// EvaluatePreconditions evaluates conditional request headers (`If-Match`, `If-Unmodified-Since`,
+	// `If-None-Match`, `If-Modified-Since` and `If-Range`) against current validators of the resource as described in
+	// RFC 9110 section 13.2.2.
+	EvaluatePreconditions(currentETag string, lastModified time.Time) (proceed bool, status int)
+
+	// PreconditionFailed sends "412 - Precondition Failed" response without body.
+	PreconditionFailed() error
 
 	// Error invokes the registered global HTTP error handler. Generally used by middleware.
 	// A side-effect of calling global error handler is that now Response has been committed (sent to the client) and
 	// middlewares up in chain can not change Response status code or Response body anymore.
 	//
 	// Avoid using this method in handlers as no middleware will be able to effectively handle errors after that.
+	//
+	// Only the first call invokes the error handler, it is safe to call concurrently (ala from handler goroutine and
+	// timeout path). Later calls with the same error (ala middleware calling `c.Error(err)` and returning the error)
+	// are no-op, other errors are logged and recorded as suppressed errors.
 	Error(err error)
 
+	// SuppressedErrors returns errors that were not sent to the client because error of the request had already been
+	// handled or response had already been committed.
+	SuppressedErrors() []error
+
 	// Handler returns the matched handler by router.
 	Handler() HandlerFunc
 
 	// SetHandler sets the matched handler by router.
 	SetHandler(h HandlerFunc)
 
-	// Logger returns the `Logger` instance.
+	// Logger returns the `Logger` instance. Unless logger has been set with `SetLogger` it is `Echo#Logger` or, when
+	// `Echo#Logger` implements `LoggerWithFields`, its child with request ID, route, remote IP and fields added by
+	// `AddLogFields` attached to every entry.
 	Logger() Logger
 
 	// SetLogger Set the logger
 	SetLogger(l Logger)
 
+	// Defer registers function to be run after the response has been written. See `Echo#DeferredWorkers`.
+	Defer(fn func(ctx stdContext.Context))
+
+	// Clone returns detached copy of the context that can be used after the request has ended (ala in goroutine
+	// that outlives the handler). Response of the detached context can not be written.
+	Clone() Context
+
+	// AddLogFields adds fields to request scoped logger returned by `Logger()`. Fields are attached only when
+	// `Echo#Logger` implements `LoggerWithFields` and are not added to logger set with `SetLogger`.
+	AddLogFields(fields map[string]interface{})
+
 	// Echo returns the `Echo` instance.
 	Echo() *Echo
 
@@ -200,15 +375,30 @@ type Context interface {
 }
 
 type context struct {
-	logger   Logger
+	logger    Logger
+	logFields map[string]interface{}
+	// deferred holds functions registered with Defer
+	deferred []func(ctx stdContext.Context)
+	// cachePolicy is created on first `CachePolicy()` call
+	cachePolicy *CachePolicy
+	// matrix holds matrix parameters of path segments when `Echo#MatrixParams` is enabled
+	matrix   []matrixSegment
 	request  *http.Request
 	response *Response
 	query    url.Values
+	// queryRaw is the raw query string that query was parsed from. It is used to detect that request URL has been
+	// changed since query was cached.
+	queryRaw string
 	echo     *Echo
 
 	store Map
 	lock  sync.RWMutex
 
+	// errorHandled is set by the first `Error` call. handledErr and suppressedErrs are guarded by lock.
+	errorHandled   atomic.Bool
+	handledErr     error
+	suppressedErrs []error
+
 	// following fields are set by Router
 	handler HandlerFunc
 
@@ -225,6 +415,19 @@ type context struct {
 
 	// pnames length is tied to param count for the matched route
 	pnames []string
+
+	// routeOptions are options of the matched route. Nil when route has no options.
+	routeOptions *routeOptions
+	// cancelRoute releases resources (timeout context) created when route options were applied.
+	cancelRoute func()
+	// bodyReadDepth is number of nested readBody calls. Only the outermost sets and restores the body read deadline.
+	bodyReadDepth int
+	// serveStart is time the request has started to be served. It is set only when body read timeout is configured.
+	serveStart time.Time
+
+	// rawPvalues holds path parameter values in their encoded form when Echo#UseEncodedPath is enabled and pvalues
+	// have been decoded. It is empty otherwise.
+	rawPvalues []string
 }
 
 const (
@@ -253,6 +456,7 @@ func (c *context) Request() *http.Request {
 
 func (c *context) SetRequest(r *http.Request) {
 	c.request = r
+	c.query = nil
 }
 
 func (c *context) Response() *Response {
@@ -272,6 +476,45 @@ func (c *context) IsWebSocket() bool {
 	return strings.EqualFold(upgrade, "websocket")
 }
 
+func (c *context) RouteScopes() []string {
+	if c.routeOptions == nil {
+		return nil
+	}
+	return c.routeOptions.scopes
+}
+
+func (c *context) RouteName() string {
+	if c.echo == nil {
+		return ""
+	}
+	if route := c.echo.findRouter(c.request.Host).matchedRoute(c); route != nil {
+		return route.Name
+	}
+	return ""
+}
+
+func (c *context) IsInternal() bool {
+	return internalRequestDepth(c.request.Context()) > 0
+}
+
+func (c *context) ExpectsContinue() bool {
+	return strings.EqualFold(c.request.Header.Get(HeaderExpect), "100-continue")
+}
+
+func (c *context) RejectContinue(code int, err error) error {
+	// replace body so nobody in the chain (binders, body dump etc.) triggers "100 Continue" by reading it
+	c.request.Body = http.NoBody
+	c.request.ContentLength = 0
+	if c.ExpectsContinue() {
+		c.response.Header().Set(HeaderConnection, "close")
+	}
+	he := NewHTTPError(code)
+	if err != nil {
+		he.Internal = err
+	}
+	return he
+}
+
 func (c *context) Scheme() string {
 	// Can't use `r.Request.URL.Scheme`
 	// See: https://groups.google.com/forum/#!topic/golang-nuts/pMUkBlQBDF0
@@ -294,27 +537,30 @@ func (c *context) Scheme() string {
 }
 
 func (c *context) RealIP() string {
+	addr, err := c.RealIPAddr()
+	if err != nil {
+		return ""
+	}
+	return addr.String()
+}
+
+func (c *context) RealIPAddr() (netip.Addr, error) {
 	if c.echo != nil && c.echo.IPExtractor != nil {
-		return c.echo.IPExtractor(c.request)
-	}
-	// Fall back to legacy behavior
-	if ip := c.request.Header.Get(HeaderXForwardedFor); ip != "" {
-		i := strings.IndexAny(ip, ",")
-		if i > 0 {
-			xffip := strings.TrimSpace(ip[:i])
-			xffip = strings.TrimPrefix(xffip, "[")
-			xffip = strings.TrimSuffix(xffip, "]")
-			return xffip
+		return parseIPAddr(c.echo.IPExtractor(c.request))
+	}
+	// Fall back to legacy behavior. Invalid header values are skipped.
+	if xff := c.request.Header.Get(HeaderXForwardedFor); xff != "" {
+		first, _, _ := strings.Cut(xff, ",")
+		if addr, err := parseIPAddr(first); err == nil {
+			return addr, nil
 		}
-		return ip
 	}
 	if ip := c.request.Header.Get(HeaderXRealIP); ip != "" {
-		ip = strings.TrimPrefix(ip, "[")
-		ip = strings.TrimSuffix(ip, "]")
-		return ip
+		if addr, err := parseIPAddr(ip); err == nil {
+			return addr, nil
+		}
 	}
-	ra, _, _ := net.SplitHostPort(c.request.RemoteAddr)
-	return ra
+	return parseIPAddr(c.request.RemoteAddr)
 }
 
 func (c *context) Path() string {
@@ -336,6 +582,36 @@ func (c *context) Param(name string) string {
 	return ""
 }
 
+func (c *context) RawParam(name string) string {
+	for i, n := range c.pnames {
+		if i < len(c.rawPvalues) && n == name {
+			return c.rawPvalues[i]
+		}
+	}
+	value := c.Param(name)
+	if value == "" || c.request == nil || c.request.URL.RawPath != "" {
+		// when request has RawPath the Router matched against it and values are already in raw form
+		return value
+	}
+	// Router matched against Path which means that request path was in its default encoding. We restore the raw
+	// form only when asked to avoid allocations for routes that do not need it.
+	return (&url.URL{Path: value}).EscapedPath()
+}
+
+func (c *context) ParamSegments(name string) []string {
+	raw := c.RawParam(name)
+	if raw == "" {
+		return nil
+	}
+	segments := strings.Split(raw, "/")
+	for i, segment := range segments {
+		if decoded, err := url.PathUnescape(segment); err == nil {
+			segments[i] = decoded
+		}
+	}
+	return segments
+}
+
 func (c *context) ParamNames() []string {
 	return c.pnames
 }
@@ -357,9 +633,20 @@ func (c *context) ParamValues() []string {
 	return c.pvalues[:len(c.pnames)]
 }
 
+func (c *context) PathParamsMap() map[string]string {
+	params := make(map[string]string, len(c.pnames))
+	for i, name := range c.pnames {
+		if i < len(c.pvalues) {
+			params[name] = c.pvalues[i]
+		}
+	}
+	return params
+}
+
 func (c *context) SetParamValues(values ...string) {
 	// NOTE: Don't just set c.pvalues = values, because it has to have length c.echo.maxParam (or bigger) at all times
 	// It will brake the Router#Find code
+	c.rawPvalues = c.rawPvalues[:0]
 	limit := len(values)
 	if limit > len(c.pvalues) {
 		c.pvalues = make([]string, limit)
@@ -369,16 +656,121 @@ func (c *context) SetParamValues(values ...string) {
 	}
 }
 
+// unescapePathParams decodes path parameter values matched by the Router and keeps their encoded form in rawPvalues.
+func (c *context) unescapePathParams() {
+	n := len(c.pnames)
+	if n > len(c.pvalues) {
+		n = len(c.pvalues)
+	}
+	if cap(c.rawPvalues) < n {
+		c.rawPvalues = make([]string, n)
+	}
+	c.rawPvalues = c.rawPvalues[:n]
+	for i := 0; i < n; i++ {
+		raw := c.pvalues[i]
+		c.rawPvalues[i] = raw
+		if value, err := url.PathUnescape(raw); err == nil {
+			c.pvalues[i] = value
+		}
+	}
+}
+
 func (c *context) QueryParam(name string) string {
-	if c.query == nil {
-		c.query = c.request.URL.Query()
+	if c.query != nil && c.queryRaw == c.request.URL.RawQuery {
+		return c.query.Get(name)
+	}
+	raw := c.request.URL.RawQuery
+	semicolon := c.semicolonQuery()
+	if countQueryParams(raw, semicolon) >= queryScanMaxParams {
+		return c.QueryParams().Get(name)
+	}
+	// scan raw query without building url.Values for the single parameter
+	value := ""
+	iterateQuery(raw, semicolon, func(k, v string) bool {
+		if k == name {
+			value = v
+			return false
+		}
+		return true
+	})
+	return value
+}
+
+func (c *context) QueryParamIter(fn func(key, value string) bool) {
+	raw := c.request.URL.RawQuery
+	semicolon := c.semicolonQuery()
+	if countQueryParams(raw, semicolon) >= queryScanMaxParams && len(c.QueryParams()) == 0 {
+		return // url.ParseQuery drops all parameters when there are too many of them
+	}
+	iterateQuery(raw, semicolon, fn)
+}
+
+// semicolonQuery returns true when semicolons separate query parameters (see `QueryParseSemicolon`).
+func (c *context) semicolonQuery() bool {
+	return c.echo != nil && c.echo.QueryParseMode == QueryParseSemicolon
+}
+
+func countQueryParams(query string, semicolon bool) int {
+	n := strings.Count(query, "&")
+	if semicolon {
+		n += strings.Count(query, ";")
+	}
+	return n
+}
+
+// queryScanMaxParams is number of query parameters up to which raw query is scanned instead of being parsed to
+// url.Values. `url.ParseQuery` limits number of parameters so bigger queries are left to it.
+const queryScanMaxParams = 100
+
+// iterateQuery calls fn for every parameter of raw query the same way as `url.ParseQuery` parses them. Key and value
+// are substrings of raw query unless they had to be unescaped. When semicolon is true both `&` and `;` separate
+// parameters, otherwise parameters containing `;` are skipped.
+func iterateQuery(query string, semicolon bool, fn func(key, value string) bool) {
+	for query != "" {
+		var pair string
+		i := strings.IndexByte(query, '&')
+		if semicolon {
+			i = strings.IndexAny(query, "&;")
+		}
+		if i != -1 {
+			pair, query = query[:i], query[i+1:]
+		} else {
+			pair, query = query, ""
+		}
+		if pair == "" || strings.IndexByte(pair, ';') != -1 {
+			continue
+		}
+		key, value, _ := strings.Cut(pair, "=")
+		key, ok := queryUnescape(key)
+		if !ok {
+			continue
+		}
+		value, ok = queryUnescape(value)
+		if !ok {
+			continue
+		}
+		if !fn(key, value) {
+			return
+		}
+	}
+}
+
+func queryUnescape(s string) (string, bool) {
+	if strings.IndexByte(s, '%') == -1 && strings.IndexByte(s, '+') == -1 {
+		return s, true
 	}
-	return c.query.Get(name)
+	u, err := url.QueryUnescape(s)
+	return u, err == nil
 }
 
 func (c *context) QueryParams() url.Values {
-	if c.query == nil {
-		c.query = c.request.URL.Query()
+	if c.query == nil || c.queryRaw != c.request.URL.RawQuery {
+		if c.semicolonQuery() {
+			c.query, _ = url.ParseQuery(strings.ReplaceAll(c.request.URL.RawQuery, ";", "&"))
+		} else {
+			c.query = c.request.URL.Query()
+		}
+		c.queryRaw = c.request.URL.RawQuery
 	}
 	return c.query
 }
@@ -388,23 +780,34 @@ func (c *context) QueryString() string {
 }
 
 func (c *context) FormValue(name string) string {
+	if c.request.Form == nil {
+		// errors are ignored here the same way as `http.Request#FormValue` does
+		_ = c.readForm(c.parseForm)
+	}
 	return c.request.FormValue(name)
 }
 
 func (c *context) FormParams() (url.Values, error) {
-	if strings.HasPrefix(c.request.Header.Get(HeaderContentType), MIMEMultipartForm) {
-		if err := c.request.ParseMultipartForm(defaultMemory); err != nil {
-			return nil, err
-		}
-	} else {
-		if err := c.request.ParseForm(); err != nil {
-			return nil, err
-		}
+	if err := c.readForm(c.parseForm); err != nil {
+		return nil, err
 	}
 	return c.request.Form, nil
 }
 
+// parseForm parses urlencoded or multipart form (with configured memory limit) from the request.
+func (c *context) parseForm() error {
+	if strings.HasPrefix(c.request.Header.Get(HeaderContentType), MIMEMultipartForm) {
+		return c.request.ParseMultipartForm(c.multipartMemory())
+	}
+	return c.request.ParseForm()
+}
+
 func (c *context) FormFile(name string) (*multipart.FileHeader, error) {
+	if c.request.MultipartForm == nil {
+		if err := c.readForm(func() error { return c.request.ParseMultipartForm(c.multipartMemory()) }); err != nil {
+			return nil, err
+		}
+	}
 	f, fh, err := c.request.FormFile(name)
 	if err != nil {
 		return nil, err
@@ -414,10 +817,103 @@ func (c *context) FormFile(name string) (*multipart.FileHeader, error) {
 }
 
 func (c *context) MultipartForm() (*multipart.Form, error) {
-	err := c.request.ParseMultipartForm(defaultMemory)
+	err := c.readForm(func() error { return c.request.ParseMultipartForm(c.multipartMemory()) })
 	return c.request.MultipartForm, err
 }
 
+// readBody calls fn, that reads the request body, with body read deadline (see `Echo#BodyReadTimeout`) set on the
+// connection. Deadline does not extend read deadline of the server (`http.Server#ReadTimeout`), which is restored
+// when fn returns. Reads that have failed due to the deadline result "408 - Request Timeout" error.
+func (c *context) readBody(fn func() error) error {
+	if c.bodyReadDepth > 0 {
+		return fn()
+	}
+	timeout := c.bodyReadTimeout()
+	if timeout <= 0 {
+		return fn()
+	}
+	serverDeadline := c.serverReadDeadline()
+	deadline := time.Now().Add(timeout)
+	if !serverDeadline.IsZero() && serverDeadline.Before(deadline) {
+		deadline = serverDeadline
+	}
+	rc := http.NewResponseController(c.response)
+	if err := rc.SetReadDeadline(deadline); err != nil {
+		return fn() // not supported by the writer (ala httptest.ResponseRecorder)
+	}
+	c.bodyReadDepth++
+	err := fn()
+	c.bodyReadDepth--
+	// body read deadline left on the connection would cancel request context when it expires
+	_ = rc.SetReadDeadline(serverDeadline)
+	if err != nil && errors.Is(err, os.ErrDeadlineExceeded) {
+		// binders wrap read errors into "400 - Bad Request" errors, which error handler would prefer
+		for he, ok := err.(*HTTPError); ok && he.Internal != nil; he, ok = err.(*HTTPError) {
+			err = he.Internal
+		}
+		return ErrRequestTimeout.WithInternal(err)
+	}
+	return err
+}
+
+// serverReadDeadline returns read deadline that `http.Server` has set for the whole request with ReadTimeout. Deadline
+// can not be read back from the connection so it is approximated with the time the request has started to be served.
+// Returns zero time when there is no such deadline.
+func (c *context) serverReadDeadline() time.Time {
+	if c.serveStart.IsZero() {
+		return time.Time{}
+	}
+	s, ok := c.request.Context().Value(http.ServerContextKey).(*http.Server)
+	if !ok || s.ReadTimeout <= 0 {
+		return time.Time{}
+	}
+	return c.serveStart.Add(s.ReadTimeout)
+}
+
+func (c *context) bodyReadTimeout() time.Duration {
+	if c.routeOptions != nil && c.routeOptions.bodyReadTimeout > 0 {
+		return c.routeOptions.bodyReadTimeout
+	}
+	if c.echo != nil {
+		return c.echo.BodyReadTimeout
+	}
+	return 0
+}
+
+// multipartMemory returns maximum number of bytes of multipart form parts that are kept in memory while parsing.
+func (c *context) multipartMemory() int64 {
+	if c.routeOptions != nil && c.routeOptions.maxMultipartMemory > 0 {
+		return c.routeOptions.maxMultipartMemory
+	}
+	if c.echo != nil && c.echo.MaxMultipartMemory > 0 {
+		return c.echo.MaxMultipartMemory
+	}
+	return defaultMemory
+}
+
+// defaultMaxBodyDrain is default value of `Echo#MaxBodyDrain`.
+const defaultMaxBodyDrain = 256 << 10
+
+// drainRequestBody reads and discards unread request body after the handler chain has returned. Connection is closed
+// after the response when body is bigger than `Echo#MaxBodyDrain` or can not be read.
+func (c *context) drainRequestBody() {
+	req := c.request
+	if strings.EqualFold(req.Header.Get(HeaderExpect), "100-continue") {
+		return // reading would ask client to send the body
+	}
+	limit := c.echo.MaxBodyDrain
+	if limit == 0 {
+		limit = defaultMaxBodyDrain
+	}
+	n, err := io.CopyN(io.Discard, req.Body, limit+1)
+	if errors.Is(err, http.ErrBodyReadAfterClose) {
+		return
+	}
+	if n > limit || (err != nil && err != io.EOF) {
+		c.response.Header().Set(HeaderConnection, "close")
+	}
+}
+
 func (c *context) Cookie(name string) (*http.Cookie, error) {
 	return c.request.Cookie(name)
 }
@@ -447,11 +943,52 @@ func (c *context) Set(key string, val interface{}) {
 }
 
 func (c *context) Bind(i interface{}) error {
-	return c.echo.Binder.Bind(i, c)
+	return c.readBody(func() error { return c.echo.Binder.Bind(i, c) })
+}
+
+func (c *context) BindQuery(i interface{}) error {
+	b, err := c.singleSourceBinder()
+	if err != nil {
+		return err
+	}
+	return b.BindQueryParams(c, i)
+}
+
+func (c *context) BindForm(i interface{}) error {
+	b, err := c.singleSourceBinder()
+	if err != nil {
+		return err
+	}
+	return c.readBody(func() error { return b.BindForm(c, i) })
+}
+
+func (c *context) BindHeaders(i interface{}) error {
+	b, err := c.singleSourceBinder()
+	if err != nil {
+		return err
+	}
+	return b.BindHeaders(c, i)
+}
+
+func (c *context) BindBody(i interface{}) error {
+	b, err := c.singleSourceBinder()
+	if err != nil {
+		return err
+	}
+	return c.readBody(func() error { return b.BindBody(c, i) })
+}
+
+func (c *context) singleSourceBinder() (SingleSourceBinder, error) {
+	b, ok := c.echo.Binder.(SingleSourceBinder)
+	if !ok {
+		return nil, ErrBinderNotSingleSource
+	}
+	return b, nil
 }
 
 func (c *context) Validate(i interface{}) error {
 	if c.echo.Validator == nil {
+		c.echo.lintState.validateWithoutValidator.Add(1)
 		return ErrValidatorNotRegistered
 	}
 	return c.echo.Validator.Validate(i)
@@ -617,9 +1154,36 @@ func (c *context) Redirect(code int, url string) error {
 }
 
 func (c *context) Error(err error) {
+	if !c.errorHandled.CompareAndSwap(false, true) {
+		c.lock.RLock()
+		handled := c.handledErr
+		c.lock.RUnlock()
+		if err == nil || errors.Is(err, handled) {
+			return
+		}
+		c.suppressError(err)
+		c.echo.Logger.Warnf("echo: error of request %s %s is already handled, suppressed error: %v", c.request.Method, c.request.URL.Path, err)
+		return
+	}
+	c.lock.Lock()
+	c.handledErr = err
+	c.lock.Unlock()
 	c.echo.HTTPErrorHandler(err, c)
 }
 
+func (c *context) SuppressedErrors() []error {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return append([]error(nil), c.suppressedErrs...)
+}
+
+// suppressError records error that was not sent to the client.
+func (c *context) suppressError(err error) {
+	c.lock.Lock()
+	c.suppressedErrs = append(c.suppressedErrs, err)
+	c.lock.Unlock()
+}
+
 func (c *context) Echo() *Echo {
 	return c.echo
 }
@@ -637,7 +1201,47 @@ func (c *context) Logger() Logger {
 	if res != nil {
 		return res
 	}
-	return c.echo.Logger
+	l := c.echo.Logger
+	fl, ok := l.(LoggerWithFields)
+	if !ok {
+		return l
+	}
+	// fields are computed on every call so request ID and route set later in the chain are included
+	fields := c.requestLogFields()
+	if len(fields) == 0 {
+		return l
+	}
+	return fl.WithFields(fields)
+}
+
+func (c *context) requestLogFields() map[string]interface{} {
+	fields := make(map[string]interface{}, len(c.logFields)+3)
+	if c.request != nil {
+		requestID := c.response.Header().Get(HeaderXRequestID)
+		if requestID == "" {
+			requestID = c.request.Header.Get(HeaderXRequestID)
+		}
+		if requestID != "" {
+			fields["request_id"] = requestID
+		}
+		if c.path != "" {
+			fields["route"] = c.path
+		}
+		fields["remote_ip"] = c.RealIP()
+	}
+	for k, v := range c.logFields {
+		fields[k] = v
+	}
+	return fields
+}
+
+func (c *context) AddLogFields(fields map[string]interface{}) {
+	if c.logFields == nil {
+		c.logFields = make(map[string]interface{}, len(fields))
+	}
+	for k, v := range fields {
+		c.logFields[k] = v
+	}
 }
 
 func (c *context) SetLogger(l Logger) {
@@ -648,11 +1252,27 @@ func (c *context) Reset(r *http.Request, w http.ResponseWriter) {
 	c.request = r
 	c.response.reset(w)
 	c.query = nil
+	c.queryRaw = ""
 	c.handler = NotFoundHandler
+	c.routeOptions = nil
+	c.cancelRoute = nil
+	c.bodyReadDepth = 0
+	c.serveStart = time.Time{}
+	if c.echo != nil && (c.echo.BodyReadTimeout > 0 || c.echo.hasRouteBodyReadTimeout) {
+		c.serveStart = time.Now()
+	}
 	c.store = nil
+	c.errorHandled.Store(false)
+	c.handledErr = nil
+	c.suppressedErrs = nil
 	c.path = ""
 	c.pnames = nil
+	c.rawPvalues = c.rawPvalues[:0]
 	c.logger = nil
+	c.logFields = nil
+	c.deferred = nil
+	c.cachePolicy = nil
+	c.matrix = nil
 	// NOTE: Don't reset because it has to have length c.echo.maxParam (or bigger) at all times
 	for i := 0; i < len(c.pvalues); i++ {
 		c.pvalues[i] = ""
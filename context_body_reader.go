@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	stdContext "context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// statusClientClosedRequest is non-standard status code used (ala by nginx) when client closed the connection before
+// the server responded.
+const statusClientClosedRequest = 499
+
+// ErrBodyReadAborted is returned by request body reads that are aborted because the request context is done (client
+// has gone away or timeout middleware has fired). Errors wrapping it also wrap the context error.
+var ErrBodyReadAborted = errors.New("request body read aborted")
+
+// readForm calls fn, that parses form from the request body, and aborts reading the body when the request context is
+// done. Reads are aborted by setting read deadline of the connection to now, so reads that are blocked return at once
+// and no read is left running after fn returns. Aborted reads result "408 - Request Timeout" error when context
+// deadline was exceeded and "499 - Client Closed Request" error when context was canceled.
+//
+// Body can not be aborted when the response writer does not support read deadlines (ala httptest.ResponseRecorder).
+func (c *context) readForm(fn func() error) error {
+	ctx := c.request.Context()
+	if ctx.Done() == nil || c.request.Body == nil || c.request.Body == http.NoBody {
+		return c.readBody(fn)
+	}
+	aborted := false
+	err := c.readBody(func() error {
+		done := make(chan struct{})
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			select {
+			case <-ctx.Done():
+				// the deadline is left on the connection, request is over and the connection is not reused after
+				// failed body read
+				aborted = http.NewResponseController(c.response).SetReadDeadline(time.Now()) == nil
+			case <-done:
+			}
+		}()
+		err := fn()
+		close(done)
+		<-stopped
+		return err
+	})
+	if err == nil || !aborted {
+		return err
+	}
+	cause := fmt.Errorf("%w: %w", ErrBodyReadAborted, ctx.Err())
+	if errors.Is(cause, stdContext.DeadlineExceeded) {
+		return ErrRequestTimeout.WithInternal(cause)
+	}
+	return NewHTTPError(statusClientClosedRequest, "Client Closed Request").SetInternal(cause)
+}
@@ -0,0 +1,205 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"bufio"
+	stdContext "context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sendSlowForm sends request with the start of the body and stops sending, like a slow client with huge form.
+// Returns the response read from the connection.
+func sendSlowForm(t *testing.T, addr string, contentType string, body string) *http.Response {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	_, err = fmt.Fprintf(conn, "POST / HTTP/1.1\r\nHost: %s\r\nContent-Type: %s\r\nContent-Length: 1048576\r\n\r\n%s",
+		addr, contentType, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	res, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+// bodyReadGoroutines returns stacks of goroutines that are reading request body or parsing form.
+func bodyReadGoroutines() []string {
+	buf := make([]byte, 1<<20)
+	buf = buf[:runtime.Stack(buf, true)]
+	var result []string
+	for _, g := range strings.Split(string(buf), "\n\n") {
+		if strings.Contains(g, "net/http.(*body).Read") || strings.Contains(g, "echo/v4.(*context).readForm") {
+			result = append(result, g)
+		}
+	}
+	return result
+}
+
+func TestContext_Bind_formContextDone(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		whenContentType  string
+		whenBody         string
+		whenDeadline     bool
+		expectStatus     int
+		expectContextErr error
+	}{
+		{
+			name:             "urlencoded form, context canceled",
+			whenContentType:  MIMEApplicationForm,
+			whenBody:         "name=jon&",
+			expectStatus:     statusClientClosedRequest,
+			expectContextErr: stdContext.Canceled,
+		},
+		{
+			name:             "multipart form, context canceled",
+			whenContentType:  MIMEMultipartForm + "; boundary=xxx",
+			whenBody:         "--xxx\r\nContent-Disposition: form-data; name=\"name\"\r\n\r\njon",
+			expectStatus:     statusClientClosedRequest,
+			expectContextErr: stdContext.Canceled,
+		},
+		{
+			name:             "multipart form, deadline exceeded",
+			whenContentType:  MIMEMultipartForm + "; boundary=xxx",
+			whenBody:         "--xxx\r\nContent-Disposition: form-data; name=\"name\"\r\n\r\njon",
+			whenDeadline:     true,
+			expectStatus:     http.StatusRequestTimeout,
+			expectContextErr: stdContext.DeadlineExceeded,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.Use(func(next HandlerFunc) HandlerFunc {
+				return func(c Context) error {
+					var ctx stdContext.Context
+					var cancel stdContext.CancelFunc
+					if tc.whenDeadline {
+						ctx, cancel = stdContext.WithTimeout(c.Request().Context(), 20*time.Millisecond)
+					} else {
+						ctx, cancel = stdContext.WithCancel(c.Request().Context())
+						time.AfterFunc(20*time.Millisecond, cancel)
+					}
+					defer cancel()
+					c.SetRequest(c.Request().WithContext(ctx))
+					return next(c)
+				}
+			})
+			var bindErr error
+			var took time.Duration
+			e.POST("/", func(c Context) error {
+				payload := struct {
+					Name string `form:"name"`
+				}{}
+				start := time.Now()
+				bindErr = c.Bind(&payload)
+				took = time.Since(start)
+				return bindErr
+			})
+			server := httptest.NewServer(e)
+			defer server.Close()
+
+			res := sendSlowForm(t, server.Listener.Addr().String(), tc.whenContentType, tc.whenBody)
+			res.Body.Close()
+
+			assert.Equal(t, tc.expectStatus, res.StatusCode)
+			assert.Less(t, took, time.Second)
+			var he *HTTPError
+			if assert.True(t, errors.As(bindErr, &he)) {
+				assert.Equal(t, tc.expectStatus, he.Code)
+			}
+			assert.ErrorIs(t, bindErr, ErrBodyReadAborted)
+			assert.ErrorIs(t, bindErr, tc.expectContextErr)
+			// connection of the slow client is still open, nothing may be left reading its body
+			assert.Empty(t, bodyReadGoroutines())
+		})
+	}
+}
+
+func TestContext_FormParams_contextDone(t *testing.T) {
+	e := New()
+	e.POST("/", func(c Context) error {
+		ctx, cancel := stdContext.WithCancel(c.Request().Context())
+		time.AfterFunc(20*time.Millisecond, cancel)
+		defer cancel()
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		_, err := c.FormParams()
+		return err
+	})
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	res := sendSlowForm(t, server.Listener.Addr().String(), MIMEApplicationForm, "name=jon&")
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+
+	assert.NoError(t, err)
+	assert.Equal(t, statusClientClosedRequest, res.StatusCode)
+	assert.Equal(t, "{\"message\":\"Client Closed Request\"}\n", string(body))
+}
+
+func TestContext_FormValue_contextDone(t *testing.T) {
+	var value string
+	var took time.Duration
+	e := New()
+	e.POST("/", func(c Context) error {
+		ctx, cancel := stdContext.WithCancel(c.Request().Context())
+		time.AfterFunc(20*time.Millisecond, cancel)
+		defer cancel()
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		start := time.Now()
+		value = c.FormValue("name")
+		took = time.Since(start)
+		return c.NoContent(http.StatusNoContent)
+	})
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	res := sendSlowForm(t, server.Listener.Addr().String(), MIMEApplicationForm, "name=jon&")
+	res.Body.Close()
+
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	assert.Equal(t, "", value)
+	assert.Less(t, took, time.Second)
+	assert.Empty(t, bodyReadGoroutines())
+}
+
+func TestContext_FormParams_notDone(t *testing.T) {
+	ctx, cancel := stdContext.WithCancel(stdContext.Background())
+	defer cancel()
+	req := httptest.NewRequest(http.MethodPost, "/", io.NopCloser(iotest.OneByteReader(strings.NewReader("name=jon&age=30"))))
+	req = req.WithContext(ctx)
+	req.Header.Set(HeaderContentType, MIMEApplicationForm)
+	c := New().NewContext(req, httptest.NewRecorder())
+
+	params, err := c.FormParams()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "jon", params.Get("name"))
+	assert.Equal(t, "30", params.Get("age"))
+	assert.Empty(t, bodyReadGoroutines())
+}
@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrCachePolicyConflict is returned by `CachePolicy#Err` when the policy has conflicting directives (ala `no-store`
+// with `max-age`) or invalid durations.
+var ErrCachePolicyConflict = errors.New("echo: conflicting cache policy directives")
+
+// CachePolicy builds `Cache-Control` header of the response. It is created with `Context#CachePolicy` and the header is
+// written when the response is committed, replacing `Cache-Control` header set by other means.
+//
+// Conflicting directives (ala NoStore together with MaxAge) panic in Debug mode. Otherwise the error is available from
+// Err, logged on commit and the response is sent with `Cache-Control: no-store` as the safest choice.
+type CachePolicy struct {
+	public               bool
+	private              bool
+	noCache              bool
+	noStore              bool
+	mustRevalidate       bool
+	immutable            bool
+	maxAge               time.Duration
+	sMaxAge              time.Duration
+	staleWhileRevalidate time.Duration
+	hasMaxAge            bool
+	hasSMaxAge           bool
+	hasStale             bool
+
+	err    error
+	debug  bool
+	logger Logger
+}
+
+// CachePolicy returns cache policy of the response. All calls during the request return the same policy.
+//
+// Example:
+//
+//	c.CachePolicy().Public().MaxAge(time.Hour).StaleWhileRevalidate(time.Minute)
+//	// Cache-Control: public, max-age=3600, stale-while-revalidate=60
+func (c *context) CachePolicy() *CachePolicy {
+	if c.cachePolicy != nil {
+		return c.cachePolicy
+	}
+	p := &CachePolicy{debug: c.echo.Debug, logger: c.Logger()}
+	c.cachePolicy = p
+	res := c.response
+	res.Before(func() {
+		if err := p.Err(); err != nil {
+			p.logger.Error(err)
+			res.Header().Set(HeaderCacheControl, "no-store")
+			return
+		}
+		if v := p.String(); v != "" {
+			res.Header().Set(HeaderCacheControl, v)
+		}
+	})
+	return p
+}
+
+// NotModified sends "304 - Not Modified" response without body. As required by RFC 9110 (section 15.4.5) headers
+// `ETag`, `Cache-Control`, `Expires` and `Vary` set for the response are kept while content headers (`Content-Type`,
+// `Content-Length` and `Content-Encoding`) are removed. `Last-Modified` is removed when `ETag` is set.
+func (c *context) NotModified() error {
+	header := c.response.Header()
+	header.Del(HeaderContentType)
+	header.Del(HeaderContentLength)
+	header.Del(HeaderContentEncoding)
+	if header.Get(HeaderETag) != "" {
+		header.Del(HeaderLastModified)
+	}
+	c.response.WriteHeader(http.StatusNotModified)
+	return nil
+}
+
+// Public adds `public` directive: response may be stored by shared caches.
+func (p *CachePolicy) Public() *CachePolicy {
+	p.public = true
+	return p.check()
+}
+
+// Private adds `private` directive: response may be stored only by the browser cache.
+func (p *CachePolicy) Private() *CachePolicy {
+	p.private = true
+	return p.check()
+}
+
+// NoCache adds `no-cache` directive: stored response must be revalidated before every use.
+func (p *CachePolicy) NoCache() *CachePolicy {
+	p.noCache = true
+	return p.check()
+}
+
+// NoStore adds `no-store` directive: response must not be stored by any cache.
+func (p *CachePolicy) NoStore() *CachePolicy {
+	p.noStore = true
+	return p.check()
+}
+
+// MustRevalidate adds `must-revalidate` directive: stale response must not be used without revalidation.
+func (p *CachePolicy) MustRevalidate() *CachePolicy {
+	p.mustRevalidate = true
+	return p.check()
+}
+
+// Immutable adds `immutable` directive: response will not change while it is fresh.
+func (p *CachePolicy) Immutable() *CachePolicy {
+	p.immutable = true
+	return p.check()
+}
+
+// MaxAge adds `max-age` directive. Duration is truncated to seconds.
+func (p *CachePolicy) MaxAge(d time.Duration) *CachePolicy {
+	p.maxAge, p.hasMaxAge = d, true
+	return p.check()
+}
+
+// SMaxAge adds `s-maxage` directive (max-age for shared caches). Duration is truncated to seconds.
+func (p *CachePolicy) SMaxAge(d time.Duration) *CachePolicy {
+	p.sMaxAge, p.hasSMaxAge = d, true
+	return p.check()
+}
+
+// StaleWhileRevalidate adds `stale-while-revalidate` directive (RFC 5861). Duration is truncated to seconds.
+func (p *CachePolicy) StaleWhileRevalidate(d time.Duration) *CachePolicy {
+	p.staleWhileRevalidate, p.hasStale = d, true
+	return p.check()
+}
+
+// Err returns error wrapping ErrCachePolicyConflict when the policy has conflicting directives.
+func (p *CachePolicy) Err() error {
+	return p.err
+}
+
+// String returns value of `Cache-Control` header for the policy.
+func (p *CachePolicy) String() string {
+	var directives []string
+	add := func(ok bool, directive string) {
+		if ok {
+			directives = append(directives, directive)
+		}
+	}
+	seconds := func(d time.Duration) string {
+		return strconv.FormatInt(int64(d/time.Second), 10)
+	}
+	add(p.public, "public")
+	add(p.private, "private")
+	add(p.noCache, "no-cache")
+	add(p.noStore, "no-store")
+	add(p.hasMaxAge, "max-age="+seconds(p.maxAge))
+	add(p.hasSMaxAge, "s-maxage="+seconds(p.sMaxAge))
+	add(p.hasStale, "stale-while-revalidate="+seconds(p.staleWhileRevalidate))
+	add(p.mustRevalidate, "must-revalidate")
+	add(p.immutable, "immutable")
+	return strings.Join(directives, ", ")
+}
+
+// check records the first conflict of the policy and panics with it in Debug mode.
+func (p *CachePolicy) check() *CachePolicy {
+	if p.err != nil {
+		return p
+	}
+	var conflict string
+	switch {
+	case p.public && p.private:
+		conflict = "public with private"
+	case p.noStore && (p.public || p.hasMaxAge || p.hasSMaxAge || p.hasStale || p.immutable):
+		conflict = "no-store with directives allowing caching"
+	case p.maxAge < 0 || p.sMaxAge < 0 || p.staleWhileRevalidate < 0:
+		conflict = "negative duration"
+	default:
+		return p
+	}
+	p.err = fmt.Errorf("%w: %s", ErrCachePolicyConflict, conflict)
+	if p.debug {
+		panic(p.err)
+	}
+	return p
+}
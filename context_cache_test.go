@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_CachePolicy(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenPolicy  func(p *CachePolicy)
+		expect      string
+		expectError string
+	}{
+		{
+			name: "ok, public with max-age and stale-while-revalidate",
+			whenPolicy: func(p *CachePolicy) {
+				p.Public().MaxAge(time.Hour).StaleWhileRevalidate(90 * time.Second)
+			},
+			expect: "public, max-age=3600, stale-while-revalidate=90",
+		},
+		{
+			name: "ok, private with s-maxage, must-revalidate and immutable",
+			whenPolicy: func(p *CachePolicy) {
+				p.Immutable().MustRevalidate().SMaxAge(1500 * time.Millisecond).Private()
+			},
+			expect: "private, s-maxage=1, must-revalidate, immutable",
+		},
+		{
+			name: "ok, no-store",
+			whenPolicy: func(p *CachePolicy) {
+				p.NoStore()
+			},
+			expect: "no-store",
+		},
+		{
+			name: "ok, no-cache with max-age=0",
+			whenPolicy: func(p *CachePolicy) {
+				p.NoCache().MaxAge(0)
+			},
+			expect: "no-cache, max-age=0",
+		},
+		{
+			name: "nok, no-store with max-age",
+			whenPolicy: func(p *CachePolicy) {
+				p.NoStore().MaxAge(time.Minute)
+			},
+			expect:      "no-store",
+			expectError: "echo: conflicting cache policy directives: no-store with directives allowing caching",
+		},
+		{
+			name: "nok, public with private",
+			whenPolicy: func(p *CachePolicy) {
+				p.Public().Private()
+			},
+			expect:      "no-store",
+			expectError: "echo: conflicting cache policy directives: public with private",
+		},
+		{
+			name: "nok, negative duration",
+			whenPolicy: func(p *CachePolicy) {
+				p.MaxAge(-time.Second)
+			},
+			expect:      "no-store",
+			expectError: "echo: conflicting cache policy directives: negative duration",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.Logger.SetOutput(io.Discard)
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			c.Response().Header().Set(HeaderCacheControl, "max-age=5")
+			tc.whenPolicy(c.CachePolicy())
+			assert.Same(t, c.CachePolicy(), c.CachePolicy())
+
+			err := c.String(http.StatusOK, "OK")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, rec.Header().Get(HeaderCacheControl))
+			if tc.expectError != "" {
+				assert.ErrorIs(t, c.CachePolicy().Err(), ErrCachePolicyConflict)
+				assert.EqualError(t, c.CachePolicy().Err(), tc.expectError)
+			} else {
+				assert.NoError(t, c.CachePolicy().Err())
+			}
+		})
+	}
+}
+
+func TestContext_CachePolicy_debugPanics(t *testing.T) {
+	e := New()
+	e.Debug = true
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	assert.PanicsWithError(t, "echo: conflicting cache policy directives: no-store with directives allowing caching", func() {
+		c.CachePolicy().MaxAge(time.Minute).NoStore()
+	})
+}
+
+func TestContext_CachePolicy_resetBetweenRequests(t *testing.T) {
+	e := New()
+	e.GET("/cached", func(c Context) error {
+		c.CachePolicy().Public().MaxAge(time.Minute)
+		return c.String(http.StatusOK, "OK")
+	})
+	e.GET("/plain", func(c Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/cached", nil))
+	assert.Equal(t, "public, max-age=60", rec.Header().Get(HeaderCacheControl))
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/plain", nil))
+	assert.Equal(t, "", rec.Header().Get(HeaderCacheControl))
+}
+
+func TestContext_NotModified(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	header := c.Response().Header()
+	header.Set(HeaderContentType, MIMEApplicationJSON)
+	header.Set(HeaderContentLength, "42")
+	header.Set(HeaderContentEncoding, "gzip")
+	header.Set(HeaderLastModified, "Wed, 21 Oct 2015 07:28:00 GMT")
+	header.Set(HeaderETag, `"v1"`)
+	header.Set(HeaderVary, HeaderAcceptEncoding)
+	c.CachePolicy().Private().MaxAge(time.Minute)
+
+	err := c.NotModified()
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Equal(t, "", rec.Body.String())
+	expect := http.Header{}
+	expect.Set(HeaderETag, `"v1"`)
+	expect.Set(HeaderVary, HeaderAcceptEncoding)
+	expect.Set(HeaderCacheControl, "private, max-age=60")
+	assert.Equal(t, expect, rec.Header())
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrDetachedResponse is returned when response of the context created with `Context#Clone` is written.
+var ErrDetachedResponse = errors.New("echo: response of detached context can not be written")
+
+// Clone returns detached copy of the context that can be used after the request has ended (ala in goroutine that
+// outlives the handler). Pooled context is reset and reused for other requests so it must not be used after handler
+// returns.
+//
+// Detached context has its own copy of path params, store (values are copied shallowly) and request. Request context
+// carries values of the original request context but is never cancelled and request body is replaced with
+// `http.NoBody`. Valid operations are reading path/query params, headers, cookies, already parsed form values, store
+// values, `Get`/`Set`, `Logger` and `Echo`. Response is a stub: writes return ErrDetachedResponse, functions
+// registered with `Defer` are never run. Detached context must not be returned to the pool with
+// `Echo#ReleaseContext`.
+//
+// Example:
+//
+//	dc := c.Clone()
+//	go func() {
+//		audit(dc.Request().Context(), dc.Param("id"), dc.Get("user"))
+//	}()
+func (c *context) Clone() Context {
+	c.lock.RLock()
+	store := make(Map, len(c.store))
+	for k, v := range c.store {
+		store[k] = v
+	}
+	c.lock.RUnlock()
+
+	req := c.request.Clone(detachedContext{parent: c.request.Context()})
+	req.Body = http.NoBody
+
+	var logFields map[string]interface{}
+	if c.logFields != nil {
+		logFields = make(map[string]interface{}, len(c.logFields))
+		for k, v := range c.logFields {
+			logFields[k] = v
+		}
+	}
+
+	return &context{
+		logger:       c.logger,
+		logFields:    logFields,
+		request:      req,
+		response:     NewResponse(&detachedResponseWriter{header: c.response.Header().Clone()}, c.echo),
+		echo:         c.echo,
+		store:        store,
+		handler:      c.handler,
+		path:         c.path,
+		pvalues:      append([]string(nil), c.pvalues...),
+		pnames:       append([]string(nil), c.pnames...),
+		routeOptions: c.routeOptions,
+		rawPvalues:   append([]string(nil), c.rawPvalues...),
+		matrix:       c.matrix,
+	}
+}
+
+// detachedResponseWriter is response writer of detached context. Writes fail with ErrDetachedResponse.
+type detachedResponseWriter struct {
+	header http.Header
+}
+
+func (w *detachedResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *detachedResponseWriter) Write([]byte) (int, error) {
+	return 0, ErrDetachedResponse
+}
+
+func (w *detachedResponseWriter) WriteHeader(int) {}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	stdContext "context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_Clone(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/users/1?lang=en", strings.NewReader("body"))
+	req.Header.Set("X-Request-ID", "abc")
+	ctx, cancel := stdContext.WithCancel(stdContext.WithValue(req.Context(), testDeferCtxKey{}, "value"))
+	req = req.WithContext(ctx)
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetPath("/users/:id")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("user", "jon")
+
+	dc := c.Clone()
+
+	// original context is reset and reused for other request
+	cancel()
+	c.Reset(httptest.NewRequest(http.MethodGet, "/users/2", nil), httptest.NewRecorder())
+	c.SetParamNames("id")
+	c.SetParamValues("2")
+	c.Set("user", "doe")
+
+	assert.Equal(t, "1", dc.Param("id"))
+	assert.Equal(t, "/users/:id", dc.Path())
+	assert.Equal(t, "en", dc.QueryParam("lang"))
+	assert.Equal(t, "abc", dc.Request().Header.Get("X-Request-ID"))
+	assert.Equal(t, "jon", dc.Get("user"))
+
+	assert.NoError(t, dc.Request().Context().Err())
+	assert.Equal(t, "value", dc.Request().Context().Value(testDeferCtxKey{}))
+	assert.Equal(t, http.NoBody, dc.Request().Body)
+
+	assert.ErrorIs(t, dc.String(http.StatusOK, "test"), ErrDetachedResponse)
+}
+
+func TestContext_Clone_concurrentWithPooledContextReuse(t *testing.T) {
+	e := New()
+	results := make(chan string, 100)
+	wg := sync.WaitGroup{}
+	e.GET("/users/:id", func(c Context) error {
+		c.Set("id", c.Param("id"))
+		// using `c` itself in the goroutine is a data race as it is reset and reused for the next request
+		dc := c.Clone()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- dc.Param("id") + "=" + dc.Get("id").(string) + "=" + dc.QueryParam("q")
+		}()
+		return c.NoContent(http.StatusOK)
+	})
+
+	for i := 0; i < 100; i++ {
+		id := strconv.Itoa(i)
+		req := httptest.NewRequest(http.MethodGet, "/users/"+id+"?q="+id, nil)
+		e.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	wg.Wait()
+	close(results)
+
+	seen := map[string]bool{}
+	for r := range results {
+		parts := strings.Split(r, "=")
+		assert.Equal(t, parts[0], parts[1])
+		assert.Equal(t, parts[0], parts[2])
+		seen[parts[0]] = true
+	}
+	assert.Len(t, seen, 100)
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	stdContext "context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+)
+
+// ConnectionInfo describes connection the request arrived on.
+type ConnectionInfo struct {
+	// LocalAddr is address of the listener (server side) the connection was accepted on.
+	LocalAddr net.Addr
+	// RemoteAddr is address of the client side of the connection.
+	RemoteAddr net.Addr
+	// TLS is negotiated TLS state (version, cipher suite, ALPN protocol). Nil for plain text connections.
+	TLS *tls.ConnectionState
+	// Protocol is `http/1.0`, `http/1.1`, `h2`, `h2c` (HTTP/2 over plain text) or `h3`.
+	Protocol string
+}
+
+// connContextKey is request context key for the net.Conn of the request.
+type connContextKey struct{}
+
+// ConnContext is `http.Server.ConnContext` hook that stores the connection into the context of its requests for
+// `Context#ConnectionInfo`. Echo installs it on servers it starts. Set it on custom servers:
+//
+//	s := &http.Server{Handler: e, ConnContext: echo.ConnContext}
+func ConnContext(ctx stdContext.Context, c net.Conn) stdContext.Context {
+	return stdContext.WithValue(ctx, connContextKey{}, c)
+}
+
+// chainConnContext returns ConnContext hook that calls hook already set on the server first.
+func chainConnContext(prev func(ctx stdContext.Context, c net.Conn) stdContext.Context) func(ctx stdContext.Context, c net.Conn) stdContext.Context {
+	if prev == nil {
+		return ConnContext
+	}
+	return func(ctx stdContext.Context, c net.Conn) stdContext.Context {
+		return ConnContext(prev(ctx, c), c)
+	}
+}
+
+// ConnectionInfo returns information about connection the request arrived on.
+//
+// Addresses are taken from the connection when server was started by Echo (or `ConnContext` hook is installed on
+// custom server). Otherwise local address comes from `http.LocalAddrContextKey` and remote address is parsed from
+// `Request.RemoteAddr`, fields that are not available are nil. HTTP/2 multiplexes many requests over single
+// connection so all of them report the same connection and TLS state.
+func (c *context) ConnectionInfo() ConnectionInfo {
+	r := c.request
+	info := ConnectionInfo{
+		TLS:      r.TLS,
+		Protocol: requestProtocol(r),
+	}
+	ctx := r.Context()
+	if conn, ok := ctx.Value(connContextKey{}).(net.Conn); ok {
+		info.LocalAddr = conn.LocalAddr()
+		info.RemoteAddr = conn.RemoteAddr()
+		return info
+	}
+	if addr, ok := ctx.Value(http.LocalAddrContextKey).(net.Addr); ok {
+		info.LocalAddr = addr
+	}
+	if addrPort, err := netip.ParseAddrPort(r.RemoteAddr); err == nil {
+		info.RemoteAddr = net.TCPAddrFromAddrPort(addrPort)
+	}
+	return info
+}
+
+func requestProtocol(r *http.Request) string {
+	switch r.ProtoMajor {
+	case 3:
+		return "h3"
+	case 2:
+		if r.TLS == nil {
+			return "h2c"
+		}
+		return "h2"
+	}
+	if r.TLS != nil && r.TLS.NegotiatedProtocol != "" {
+		return r.TLS.NegotiatedProtocol
+	}
+	return "http/1." + strconv.Itoa(r.ProtoMinor)
+}
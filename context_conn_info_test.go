@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	stdContext "context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_ConnectionInfo_withoutConnContext(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	info := c.ConnectionInfo()
+
+	assert.Nil(t, info.LocalAddr)
+	assert.Equal(t, "192.0.2.1:1234", info.RemoteAddr.String())
+	assert.Nil(t, info.TLS)
+	assert.Equal(t, "http/1.1", info.Protocol)
+}
+
+func TestContext_ConnectionInfo_http(t *testing.T) {
+	e := New()
+	e.HideBanner = true
+	e.HidePort = true
+	var info ConnectionInfo
+	e.GET("/", func(c Context) error {
+		info = c.ConnectionInfo()
+		return c.String(http.StatusOK, "ok")
+	})
+
+	errChan := make(chan error)
+	go func() {
+		errChan <- e.Start("127.0.0.1:0")
+	}()
+	assert.NoError(t, waitForServerStart(e, errChan, false))
+	defer e.Close()
+
+	res, err := http.Get("http://" + e.ListenerAddr().String() + "/")
+	if assert.NoError(t, err) {
+		_, _ = io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+	}
+
+	assert.Equal(t, e.ListenerAddr().String(), info.LocalAddr.String())
+	assert.IsType(t, &net.TCPAddr{}, info.RemoteAddr)
+	assert.Nil(t, info.TLS)
+	assert.Equal(t, "http/1.1", info.Protocol)
+}
+
+func TestContext_ConnectionInfo_tls(t *testing.T) {
+	e := New()
+	e.HideBanner = true
+	e.HidePort = true
+	var info ConnectionInfo
+	e.GET("/", func(c Context) error {
+		info = c.ConnectionInfo()
+		return c.String(http.StatusOK, "ok")
+	})
+
+	errChan := make(chan error)
+	go func() {
+		errChan <- e.StartTLS("127.0.0.1:0", "_fixture/certs/cert.pem", "_fixture/certs/key.pem")
+	}()
+	assert.NoError(t, waitForServerStart(e, errChan, true))
+	defer e.Close()
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+		ForceAttemptHTTP2: true,
+	}}
+	res, err := client.Get("https://" + e.TLSListenerAddr().String() + "/")
+	if assert.NoError(t, err) {
+		_, _ = io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+	}
+
+	assert.Equal(t, e.TLSListenerAddr().String(), info.LocalAddr.String())
+	assert.NotNil(t, info.RemoteAddr)
+	if assert.NotNil(t, info.TLS) {
+		assert.Equal(t, "h2", info.TLS.NegotiatedProtocol)
+		assert.NotZero(t, info.TLS.Version)
+		assert.NotZero(t, info.TLS.CipherSuite)
+	}
+	assert.Equal(t, "h2", info.Protocol)
+}
+
+type testConnCtxKey struct{}
+
+func TestChainConnContext(t *testing.T) {
+	prev := func(ctx stdContext.Context, c net.Conn) stdContext.Context {
+		return stdContext.WithValue(ctx, testConnCtxKey{}, "prev")
+	}
+	conn, other := net.Pipe()
+	defer conn.Close()
+	defer other.Close()
+
+	ctx := chainConnContext(prev)(stdContext.Background(), conn)
+
+	assert.Equal(t, "prev", ctx.Value(testConnCtxKey{}))
+	assert.Equal(t, conn, ctx.Value(connContextKey{}))
+}
@@ -0,0 +1,219 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidCookieName is returned when cookie name is empty or contains characters not allowed in a token.
+	ErrInvalidCookieName = errors.New("echo: invalid cookie name")
+	// ErrInvalidCookieValue is returned when cookie value contains characters not allowed in cookie values (white
+	// space, double quote, comma, semicolon, backslash or control characters).
+	ErrInvalidCookieValue = errors.New("echo: invalid cookie value")
+	// ErrInvalidCookieSignature is returned by `Context#ReadSignedCookie` when cookie is not signed with any of
+	// `Echo#CookieSigningKeys`.
+	ErrInvalidCookieSignature = errors.New("echo: invalid cookie signature")
+	// ErrCookieSigningKeysNotSet is returned by signed cookie methods when `Echo#CookieSigningKeys` is empty.
+	ErrCookieSigningKeysNotSet = errors.New("echo: cookie signing keys are not set")
+)
+
+// CookieOption configures cookie set by `Context#SetCookieValue`, `Context#SignedCookie` and
+// `Context#DeleteCookie`.
+type CookieOption func(o *cookieOptions)
+
+type cookieOptions struct {
+	cookie      http.Cookie
+	secure      *bool
+	partitioned bool
+}
+
+// CookieTTL sets `Max-Age` and `Expires` attributes of the cookie. By default cookie is session cookie.
+func CookieTTL(ttl time.Duration) CookieOption {
+	return func(o *cookieOptions) {
+		o.cookie.MaxAge = int(ttl / time.Second)
+		o.cookie.Expires = time.Now().Add(ttl)
+	}
+}
+
+// CookiePath sets `Path` attribute of the cookie. Default value is `/`.
+func CookiePath(path string) CookieOption {
+	return func(o *cookieOptions) {
+		o.cookie.Path = path
+	}
+}
+
+// CookieDomain sets `Domain` attribute of the cookie. By default cookie is host-only.
+func CookieDomain(domain string) CookieOption {
+	return func(o *cookieOptions) {
+		o.cookie.Domain = domain
+	}
+}
+
+// CookieHTTPOnly sets `HttpOnly` attribute of the cookie. Default value is true.
+func CookieHTTPOnly(httpOnly bool) CookieOption {
+	return func(o *cookieOptions) {
+		o.cookie.HttpOnly = httpOnly
+	}
+}
+
+// CookieSecure sets `Secure` attribute of the cookie. By default cookie is Secure when request scheme is `https`
+// (see `Context#Scheme`).
+func CookieSecure(secure bool) CookieOption {
+	return func(o *cookieOptions) {
+		o.secure = &secure
+	}
+}
+
+// CookieSameSite sets `SameSite` attribute of the cookie. Default value is http.SameSiteLaxMode. Cookies with
+// http.SameSiteNoneMode are always Secure as browsers reject them otherwise.
+func CookieSameSite(mode http.SameSite) CookieOption {
+	return func(o *cookieOptions) {
+		o.cookie.SameSite = mode
+	}
+}
+
+// CookiePartitioned adds `Partitioned` attribute (CHIPS) to the cookie. Partitioned cookies are always Secure.
+func CookiePartitioned() CookieOption {
+	return func(o *cookieOptions) {
+		o.partitioned = true
+	}
+}
+
+// SetCookieValue adds `Set-Cookie` header with given name and value to the response. Cookie has `Path=/`, `HttpOnly`
+// and `SameSite=Lax` attributes and is Secure for https requests unless options say otherwise. Values containing
+// characters not allowed in cookies result ErrInvalidCookieValue instead of being changed.
+func (c *context) SetCookieValue(name, value string, opts ...CookieOption) error {
+	if !isValidCookieValue(value) {
+		return ErrInvalidCookieValue
+	}
+	return c.setCookie(name, value, opts)
+}
+
+// DeleteCookie adds `Set-Cookie` header that makes browser delete the cookie. Path and Domain options must match the
+// ones the cookie was set with.
+func (c *context) DeleteCookie(name string, opts ...CookieOption) error {
+	opts = append(opts, func(o *cookieOptions) {
+		o.cookie.MaxAge = -1
+		o.cookie.Expires = time.Unix(0, 0)
+	})
+	return c.setCookie(name, "", opts)
+}
+
+// SignedCookie sets cookie (see `Context#SetCookieValue`) with value signed by first of `Echo#CookieSigningKeys`.
+// Value is base64url encoded so it can contain any characters. Value is not encrypted and can be read by the client.
+func (c *context) SignedCookie(name, value string, opts ...CookieOption) error {
+	keys := c.echo.CookieSigningKeys
+	if len(keys) == 0 {
+		return ErrCookieSigningKeysNotSet
+	}
+	payload := base64.RawURLEncoding.EncodeToString([]byte(value))
+	signature := base64.RawURLEncoding.EncodeToString(signCookie(keys[0], name, payload))
+	return c.setCookie(name, payload+"."+signature, opts)
+}
+
+// ReadSignedCookie returns value of the cookie set with `Context#SignedCookie`. Signature is checked with all
+// `Echo#CookieSigningKeys` so keys can be rotated. Returns http.ErrNoCookie when cookie is missing and
+// ErrInvalidCookieSignature when it has been tampered with or signed with unknown key.
+func (c *context) ReadSignedCookie(name string) (string, error) {
+	keys := c.echo.CookieSigningKeys
+	if len(keys) == 0 {
+		return "", ErrCookieSigningKeysNotSet
+	}
+	cookie, err := c.request.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	payload, encodedSignature, ok := strings.Cut(cookie.Value, ".")
+	if !ok {
+		return "", ErrInvalidCookieSignature
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return "", ErrInvalidCookieSignature
+	}
+	for _, key := range keys {
+		if !hmac.Equal(signature, signCookie(key, name, payload)) {
+			continue
+		}
+		value, err := base64.RawURLEncoding.DecodeString(payload)
+		if err != nil {
+			return "", ErrInvalidCookieSignature
+		}
+		return string(value), nil
+	}
+	return "", ErrInvalidCookieSignature
+}
+
+func (c *context) setCookie(name, value string, opts []CookieOption) error {
+	if !isValidCookieName(name) {
+		return ErrInvalidCookieName
+	}
+	o := cookieOptions{
+		cookie: http.Cookie{Name: name, Value: value, Path: "/", HttpOnly: true, SameSite: http.SameSiteLaxMode},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	switch {
+	case o.partitioned || o.cookie.SameSite == http.SameSiteNoneMode:
+		o.cookie.Secure = true
+	case o.secure != nil:
+		o.cookie.Secure = *o.secure
+	default:
+		o.cookie.Secure = c.Scheme() == "https"
+	}
+
+	v := o.cookie.String()
+	if v == "" {
+		return ErrInvalidCookieName
+	}
+	if o.partitioned {
+		// http.Cookie does not support Partitioned attribute before Go 1.23
+		v += "; Partitioned"
+	}
+	c.Response().Header().Add(HeaderSetCookie, v)
+	return nil
+}
+
+// signCookie returns HMAC-SHA256 of cookie name and payload. Name is signed so value can not be moved to other cookie.
+func signCookie(key []byte, name, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(name))
+	mac.Write([]byte{'='})
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// isValidCookieName checks that name is RFC 7230 token.
+func isValidCookieName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		ch := name[i]
+		if ch <= ' ' || ch >= 0x7f || strings.IndexByte(`"(),/:;<=>?@[\]{}`, ch) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// isValidCookieValue checks that value consists of RFC 6265 cookie-octets.
+func isValidCookieValue(value string) bool {
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		if ch <= ' ' || ch >= 0x7f || ch == '"' || ch == ',' || ch == ';' || ch == '\\' {
+			return false
+		}
+	}
+	return true
+}
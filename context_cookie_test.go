@@ -0,0 +1,236 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_SetCookieValue(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenTLS    bool
+		givenHeader map[string]string
+		whenName    string
+		whenValue   string
+		whenOptions []CookieOption
+		expect      string
+		expectErr   error
+	}{
+		{
+			name:      "ok, defaults",
+			whenName:  "session",
+			whenValue: "abc",
+			expect:    "session=abc; Path=/; HttpOnly; SameSite=Lax",
+		},
+		{
+			name:      "ok, secure for TLS request",
+			givenTLS:  true,
+			whenName:  "session",
+			whenValue: "abc",
+			expect:    "session=abc; Path=/; HttpOnly; Secure; SameSite=Lax",
+		},
+		{
+			name:        "ok, secure for forwarded https request",
+			givenHeader: map[string]string{HeaderXForwardedProto: "https"},
+			whenName:    "session",
+			whenValue:   "abc",
+			expect:      "session=abc; Path=/; HttpOnly; Secure; SameSite=Lax",
+		},
+		{
+			name:        "ok, explicit secure wins",
+			givenTLS:    true,
+			whenName:    "session",
+			whenValue:   "abc",
+			whenOptions: []CookieOption{CookieSecure(false)},
+			expect:      "session=abc; Path=/; HttpOnly; SameSite=Lax",
+		},
+		{
+			name:      "ok, all options",
+			whenName:  "prefs",
+			whenValue: "dark",
+			whenOptions: []CookieOption{
+				CookiePath("/app"),
+				CookieDomain("example.com"),
+				CookieHTTPOnly(false),
+				CookieSameSite(http.SameSiteStrictMode),
+			},
+			expect: "prefs=dark; Path=/app; Domain=example.com; SameSite=Strict",
+		},
+		{
+			name:        "ok, SameSite=None is always secure",
+			whenName:    "embed",
+			whenValue:   "1",
+			whenOptions: []CookieOption{CookieSameSite(http.SameSiteNoneMode)},
+			expect:      "embed=1; Path=/; HttpOnly; Secure; SameSite=None",
+		},
+		{
+			name:        "ok, partitioned",
+			whenName:    "embed",
+			whenValue:   "1",
+			whenOptions: []CookieOption{CookieSameSite(http.SameSiteNoneMode), CookiePartitioned()},
+			expect:      "embed=1; Path=/; HttpOnly; Secure; SameSite=None; Partitioned",
+		},
+		{
+			name:      "nok, value with space",
+			whenName:  "session",
+			whenValue: "a b",
+			expectErr: ErrInvalidCookieValue,
+		},
+		{
+			name:      "nok, value with semicolon",
+			whenName:  "session",
+			whenValue: "a;b",
+			expectErr: ErrInvalidCookieValue,
+		},
+		{
+			name:      "nok, invalid name",
+			whenName:  "my session",
+			whenValue: "a",
+			expectErr: ErrInvalidCookieName,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.givenTLS {
+				req.TLS = &tls.ConnectionState{}
+			}
+			for k, v := range tc.givenHeader {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := c.SetCookieValue(tc.whenName, tc.whenValue, tc.whenOptions...)
+
+			if tc.expectErr != nil {
+				assert.ErrorIs(t, err, tc.expectErr)
+				assert.Empty(t, rec.Header().Values(HeaderSetCookie))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, []string{tc.expect}, rec.Header().Values(HeaderSetCookie))
+		})
+	}
+}
+
+func TestContext_SetCookieValue_ttl(t *testing.T) {
+	e := New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	err := c.SetCookieValue("session", "abc", CookieTTL(time.Hour))
+
+	assert.NoError(t, err)
+	cookie := (&http.Response{Header: rec.Header()}).Cookies()[0]
+	assert.Equal(t, 3600, cookie.MaxAge)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), cookie.Expires, 2*time.Second)
+}
+
+func TestContext_DeleteCookie(t *testing.T) {
+	e := New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	err := c.DeleteCookie("session", CookiePath("/app"), CookieDomain("example.com"))
+
+	assert.NoError(t, err)
+	assert.Equal(t,
+		[]string{"session=; Path=/app; Domain=example.com; Expires=Thu, 01 Jan 1970 00:00:00 GMT; Max-Age=0; HttpOnly; SameSite=Lax"},
+		rec.Header().Values(HeaderSetCookie),
+	)
+}
+
+func signedCookieRequest(t *testing.T, e *Echo, name, value string) *http.Request {
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	if err := c.SignedCookie(name, value); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, cookie := range (&http.Response{Header: rec.Header()}).Cookies() {
+		req.AddCookie(cookie)
+	}
+	return req
+}
+
+func TestContext_SignedCookie(t *testing.T) {
+	oldKey := []byte("old-key-old-key-old-key-old-key!")
+	newKey := []byte("new-key-new-key-new-key-new-key!")
+
+	e := New()
+	e.CookieSigningKeys = [][]byte{oldKey}
+	req := signedCookieRequest(t, e, "user", `jon "snow"; admin`)
+
+	// key rotation: cookie signed with old key is still accepted
+	e.CookieSigningKeys = [][]byte{newKey, oldKey}
+	value, err := e.NewContext(req, httptest.NewRecorder()).ReadSignedCookie("user")
+	assert.NoError(t, err)
+	assert.Equal(t, `jon "snow"; admin`, value)
+
+	// old key removed
+	e.CookieSigningKeys = [][]byte{newKey}
+	_, err = e.NewContext(req, httptest.NewRecorder()).ReadSignedCookie("user")
+	assert.ErrorIs(t, err, ErrInvalidCookieSignature)
+}
+
+func TestContext_ReadSignedCookie(t *testing.T) {
+	e := New()
+	e.CookieSigningKeys = [][]byte{[]byte("key-key-key-key-key-key-key-key!")}
+	signed, err := signedCookieRequest(t, e, "user", "jon").Cookie("user")
+	assert.NoError(t, err)
+	payload, signature, _ := strings.Cut(signed.Value, ".")
+
+	var testCases = []struct {
+		name       string
+		whenCookie string
+		whenRead   string
+		expect     string
+		expectErr  error
+	}{
+		{name: "ok", whenCookie: "user=" + signed.Value, whenRead: "user", expect: "jon"},
+		{name: "nok, missing cookie", whenRead: "user", expectErr: http.ErrNoCookie},
+		{name: "nok, tampered value", whenCookie: "user=YWRtaW4." + signature, whenRead: "user", expectErr: ErrInvalidCookieSignature},
+		{name: "nok, no signature", whenCookie: "user=" + payload, whenRead: "user", expectErr: ErrInvalidCookieSignature},
+		{name: "nok, value moved to other cookie", whenCookie: "admin=" + signed.Value, whenRead: "admin", expectErr: ErrInvalidCookieSignature},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.whenCookie != "" {
+				req.Header.Set("Cookie", tc.whenCookie)
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			value, err := c.ReadSignedCookie(tc.whenRead)
+
+			if tc.expectErr != nil {
+				assert.True(t, errors.Is(err, tc.expectErr), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, value)
+		})
+	}
+}
+
+func TestContext_SignedCookie_keysNotSet(t *testing.T) {
+	c := New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	assert.ErrorIs(t, c.SignedCookie("user", "jon"), ErrCookieSigningKeysNotSet)
+	_, err := c.ReadSignedCookie("user")
+	assert.ErrorIs(t, err, ErrCookieSigningKeysNotSet)
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+// ContextDecorator forwards all methods of Context to the wrapped Context. Embed it to decorate Context by overriding
+// only the methods that change, new methods added to Context are forwarded without changes to the decorator.
+//
+// Methods of the wrapped Context are called with the wrapped Context, so overridden methods are not seen by methods of
+// the wrapped context (ala `Bind` calling `Request`). `Clone` returns clone of the wrapped Context without the
+// decorator.
+//
+// Example:
+//
+//	type TenantContext struct {
+//		echo.ContextDecorator
+//		Tenant *Tenant
+//	}
+//
+//	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+//		return func(c echo.Context) error {
+//			return next(&TenantContext{ContextDecorator: echo.ContextDecorator{Context: c}, Tenant: lookup(c)})
+//		}
+//	})
+type ContextDecorator struct {
+	Context
+}
+
+// Unwrap returns the wrapped Context.
+func (d ContextDecorator) Unwrap() Context {
+	return d.Context
+}
+
+// unwrapContext returns Echo implementation of Context from (possibly decorated) context. Returns false when context
+// is not implemented by Echo (ala mock).
+func unwrapContext(c Context) (*context, bool) {
+	for {
+		switch t := c.(type) {
+		case *context:
+			return t, true
+		case interface{ Unwrap() Context }:
+			c = t.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantContext struct {
+	ContextDecorator
+	tenant string
+}
+
+// Param overrides single method of the decorated context.
+func (c *tenantContext) Param(name string) string {
+	if name == "tenant" {
+		return c.tenant
+	}
+	return c.ContextDecorator.Param(name)
+}
+
+func TestContextDecorator(t *testing.T) {
+	e := New()
+	e.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			return next(&tenantContext{ContextDecorator: ContextDecorator{Context: c}, tenant: "acme"})
+		}
+	})
+	e.POST("/users/:id", func(c Context) error {
+		_, ok := c.(*tenantContext)
+		assert.True(t, ok)
+
+		payload := struct {
+			ID   string `param:"id"`
+			Name string `json:"name"`
+		}{}
+		if err := c.Bind(&payload); err != nil {
+			return err
+		}
+		c.Set("key", "value")
+		return c.String(http.StatusOK, c.Param("tenant")+":"+payload.ID+":"+payload.Name+":"+c.Get("key").(string))
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/users/1", strings.NewReader(`{"name":"Jon"}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "acme:1:Jon:value", rec.Body.String())
+}
+
+func TestContextDecorator_capabilityInterfaces(t *testing.T) {
+	e := New()
+	c := &tenantContext{
+		ContextDecorator: ContextDecorator{Context: e.NewContext(httptest.NewRequest(http.MethodGet, "/?page=2", nil), httptest.NewRecorder())},
+		tenant:           "acme",
+	}
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	var params ParamReader = c
+	assert.Equal(t, "acme", params.Param("tenant"))
+	assert.Equal(t, "1", params.Param("id"))
+
+	var page int64
+	assert.NoError(t, QueryParamsBinder(c).Int64("page", &page).BindError())
+	assert.Equal(t, int64(2), page)
+}
+
+func TestContextDecorator_unwrap(t *testing.T) {
+	e := New()
+	inner := e.NewContext(httptest.NewRequest(http.MethodGet, "/users/1", nil), httptest.NewRecorder())
+	decorated := &tenantContext{ContextDecorator: ContextDecorator{Context: &tenantContext{ContextDecorator: ContextDecorator{Context: inner}}}}
+
+	ctx, ok := unwrapContext(decorated)
+	assert.True(t, ok)
+	assert.Same(t, inner, ctx)
+
+	_, ok = unwrapContext(ContextDecorator{})
+	assert.False(t, ok)
+}
+
+func TestContextDecorator_routerFind(t *testing.T) {
+	e := New()
+	e.GET("/users/:id", handlerFunc)
+	c := &tenantContext{ContextDecorator: ContextDecorator{Context: e.NewContext(nil, nil)}}
+
+	e.Router().Find(http.MethodGet, "/users/1", c)
+
+	assert.Equal(t, "/users/:id", c.Path())
+	assert.Equal(t, "1", c.Param("id"))
+}
+
+func TestContextDecorator_preMiddleware(t *testing.T) {
+	e := New()
+	e.Pre(func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			return next(&tenantContext{ContextDecorator: ContextDecorator{Context: c}, tenant: "acme"})
+		}
+	})
+	e.GET("/users/:id", func(c Context) error {
+		_, ok := c.(*tenantContext)
+		assert.True(t, ok)
+		return c.String(http.StatusOK, c.Param("tenant")+":"+c.Param("id"))
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "acme:1", rec.Body.String())
+}
+
+func TestContextDecorator_errorHandlerCommittedResponse(t *testing.T) {
+	e := New()
+	inner := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	c := &tenantContext{ContextDecorator: ContextDecorator{Context: inner}}
+	assert.NoError(t, c.NoContent(http.StatusOK))
+
+	errLate := errors.New("late error")
+	e.DefaultHTTPErrorHandler(errLate, c)
+
+	assert.Equal(t, []error{errLate}, c.SuppressedErrors())
+}
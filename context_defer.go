@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	stdContext "context"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// Defer registers function to be run after the response has been written. Functions are run in registration order
+// on the request goroutine or on the worker pool when `Echo#DeferredWorkers` is set. Panics in functions are recovered
+// and logged with `Echo#Logger`.
+//
+// Context passed to the function carries values of the request context but is not cancelled when the request
+// ends unless `Echo#DeferredCancelWithRequest` is set. Do not use echo.Context inside the function as it is
+// reused for other requests.
+func (c *context) Defer(fn func(ctx stdContext.Context)) {
+	c.deferred = append(c.deferred, fn)
+}
+
+// runDeferred runs functions registered with `Context#Defer`.
+func (e *Echo) runDeferred(c *context) {
+	funcs := c.deferred
+	ctx := c.request.Context()
+	if !e.DeferredCancelWithRequest {
+		ctx = detachedContext{parent: ctx}
+	}
+
+	if e.DeferredWorkers <= 0 {
+		if c.response.Committed {
+			// push already written response to the client before running deferred work
+			_ = http.NewResponseController(c.response.Writer).Flush()
+		}
+		e.callDeferred(ctx, funcs)
+		return
+	}
+
+	e.deferredPoolOnce.Do(func() {
+		e.deferredPool = make(chan struct{}, e.DeferredWorkers)
+	})
+	e.deferredPool <- struct{}{} // blocks when all workers are busy
+	go func() {
+		defer func() { <-e.deferredPool }()
+		e.callDeferred(ctx, funcs)
+	}()
+}
+
+func (e *Echo) callDeferred(ctx stdContext.Context, funcs []func(ctx stdContext.Context)) {
+	for _, fn := range funcs {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					e.Logger.Errorf("echo: panic in deferred function: %v\n%s", r, debug.Stack())
+				}
+			}()
+			fn(ctx)
+		}()
+	}
+}
+
+// detachedContext carries values of parent context but is never cancelled and has no deadline.
+type detachedContext struct {
+	parent stdContext.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}               { return nil }
+func (detachedContext) Err() error                          { return nil }
+func (d detachedContext) Value(key interface{}) interface{} { return d.parent.Value(key) }
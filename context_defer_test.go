@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"bytes"
+	stdContext "context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testDeferCtxKey struct{}
+
+func TestContext_Defer(t *testing.T) {
+	e := New()
+	buf := new(bytes.Buffer)
+	e.Logger.SetOutput(buf)
+
+	var calls []string
+	var deferredCtx stdContext.Context
+	e.GET("/", func(c Context) error {
+		c.SetRequest(c.Request().WithContext(stdContext.WithValue(c.Request().Context(), testDeferCtxKey{}, "trace-1")))
+		c.Defer(func(ctx stdContext.Context) {
+			calls = append(calls, "first")
+			deferredCtx = ctx
+		})
+		c.Defer(func(ctx stdContext.Context) {
+			panic("boom")
+		})
+		c.Defer(func(ctx stdContext.Context) {
+			calls = append(calls, "third")
+		})
+		calls = append(calls, "handler")
+		return c.String(http.StatusOK, "OK")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx, cancel := stdContext.WithCancel(req.Context())
+	cancel()
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req.WithContext(ctx))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, []string{"handler", "first", "third"}, calls)
+	assert.Equal(t, "trace-1", deferredCtx.Value(testDeferCtxKey{}))
+	assert.NoError(t, deferredCtx.Err()) // detached from request cancellation
+	assert.Contains(t, buf.String(), "echo: panic in deferred function: boom")
+}
+
+func TestContext_Defer_cancelWithRequest(t *testing.T) {
+	e := New()
+	e.DeferredCancelWithRequest = true
+
+	var deferredErr error
+	e.GET("/", func(c Context) error {
+		c.Defer(func(ctx stdContext.Context) {
+			deferredErr = ctx.Err()
+		})
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx, cancel := stdContext.WithCancel(req.Context())
+	cancel()
+	e.ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+
+	assert.ErrorIs(t, deferredErr, stdContext.Canceled)
+}
+
+func TestContext_Defer_workers(t *testing.T) {
+	e := New()
+	e.DeferredWorkers = 2
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var calls []int
+	e.GET("/", func(c Context) error {
+		wg.Add(1)
+		c.Defer(func(ctx stdContext.Context) {
+			defer wg.Done()
+			mu.Lock()
+			calls = append(calls, 1)
+			mu.Unlock()
+		})
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	for i := 0; i < 5; i++ {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	wg.Wait()
+
+	assert.Len(t, calls, 5)
+	assert.Equal(t, 2, cap(e.deferredPool))
+}
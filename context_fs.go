@@ -9,6 +9,8 @@ import (
 	"io/fs"
 	"net/http"
 	"path/filepath"
+	"strconv"
+	"strings"
 )
 
 func (c *context) File(file string) error {
@@ -43,10 +45,125 @@ func fsFile(c Context, file string, filesystem fs.FS) error {
 			return err
 		}
 	}
+	if c.Echo().ServePrecompressed {
+		if ok, err := ServePrecompressedFile(c, filesystem.Open, file); ok || err != nil {
+			return err
+		}
+	}
 	ff, ok := f.(io.ReadSeeker)
 	if !ok {
 		return errors.New("file does not implement io.ReadSeeker")
 	}
+	SetContentTypeByExtension(c, fi.Name())
 	http.ServeContent(c.Response(), c.Request(), fi.Name(), fi.ModTime(), ff)
 	return nil
 }
+
+// SetContentTypeByExtension sets `Content-Type` header of the response from extension of the file name (see
+// `Echo#ContentTypeByExtension`) unless the header is already set. When extension is unknown the header is not set
+// and `http.ServeContent` detects content type from the content.
+func SetContentTypeByExtension(c Context, name string) {
+	header := c.Response().Header()
+	if _, ok := header[HeaderContentType]; ok {
+		return
+	}
+	if ct := c.Echo().ContentTypeByExtension(filepath.Ext(name)); ct != "" {
+		header.Set(HeaderContentType, ct)
+	}
+}
+
+// precompressedEncodings are encodings of pre-compressed file variants in order of preference.
+var precompressedEncodings = []struct {
+	encoding  string
+	extension string
+}{
+	{encoding: "br", extension: ".br"},
+	{encoding: "gzip", extension: ".gz"},
+}
+
+// ServePrecompressedFile serves pre-compressed sibling of the file (`app.js.br` or `app.js.gz` for `app.js`) when
+// it exists and the client accepts its encoding. Response has `Content-Encoding` of the variant, `Content-Type` of
+// the original file and `Vary: Accept-Encoding` header. Range requests are not supported for pre-compressed variants
+// so the full content is sent. Returns false when no variant was served and the original file should be served
+// instead. Files with unknown content type (by extension) are never served pre-compressed.
+func ServePrecompressedFile(c Context, open func(name string) (fs.File, error), name string) (bool, error) {
+	contentType := c.Echo().ContentTypeByExtension(filepath.Ext(name))
+	if contentType == "" {
+		return false, nil
+	}
+	req := c.Request()
+	acceptEncoding := req.Header.Get(HeaderAcceptEncoding)
+	for _, v := range precompressedEncodings {
+		f, err := open(name + v.extension)
+		if err != nil {
+			continue
+		}
+		fi, err := f.Stat()
+		if err != nil || fi.IsDir() {
+			f.Close()
+			continue
+		}
+		addVaryAcceptEncoding(c.Response().Header())
+		rs, ok := f.(io.ReadSeeker)
+		if !ok || !acceptsEncoding(acceptEncoding, v.encoding) {
+			f.Close()
+			continue
+		}
+		defer f.Close()
+
+		header := c.Response().Header()
+		header.Set(HeaderContentType, contentType)
+		header.Set(HeaderContentEncoding, v.encoding)
+		// ranges over encoded bytes are confusing, so full content is always sent
+		req.Header.Del("Range")
+		req.Header.Del("If-Range")
+		// http.ServeContent does not set Content-Length for responses with Content-Encoding
+		res := c.Response()
+		size := strconv.FormatInt(fi.Size(), 10)
+		res.Before(func() {
+			if res.Status == http.StatusOK {
+				res.Header().Set(HeaderContentLength, size)
+			}
+		})
+		http.ServeContent(c.Response(), req, fi.Name(), fi.ModTime(), rs)
+		return true, nil
+	}
+	return false, nil
+}
+
+// acceptsEncoding checks if `Accept-Encoding` header value allows the encoding (`*` included). Encodings with
+// `q=0` are not accepted.
+func acceptsEncoding(acceptEncoding string, encoding string) bool {
+	accepted := false
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		value, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		value = strings.TrimSpace(value)
+		if !strings.EqualFold(value, encoding) && value != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q="); ok && isZeroQuality(q) {
+			if value != "*" {
+				return false // explicit refusal wins over `*`
+			}
+			continue
+		}
+		accepted = true
+	}
+	return accepted
+}
+
+func isZeroQuality(q string) bool {
+	f, err := strconv.ParseFloat(q, 64)
+	return err == nil && f == 0
+}
+
+func addVaryAcceptEncoding(header http.Header) {
+	for _, v := range header.Values(HeaderVary) {
+		for _, h := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(h), HeaderAcceptEncoding) {
+				return
+			}
+		}
+	}
+	header.Add(HeaderVary, HeaderAcceptEncoding)
+}
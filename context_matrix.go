@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// matrixSegment is path segment with its matrix parameters stripped.
+type matrixSegment struct {
+	segment string
+	params  url.Values
+}
+
+// findMatrixRoute finds route for request path with matrix parameters stripped and stores the parameters in context.
+func (e *Echo) findMatrixRoute(router *Router, r *http.Request, ctx *context) {
+	path, matrix := stripMatrixParams(r.URL.EscapedPath())
+	ctx.matrix = matrix
+	if e.UseEncodedPath {
+		router.Find(r.Method, path, ctx)
+		ctx.unescapePathParams()
+		return
+	}
+	// same as GetPath: escaped path is used only when request path has non-canonical encoding
+	if r.URL.RawPath == "" {
+		if unescaped, err := url.PathUnescape(path); err == nil {
+			path = unescaped
+		}
+	}
+	router.Find(r.Method, path, ctx)
+}
+
+// stripMatrixParams removes matrix parameters from each segment of escaped path. Returned segments are in the order
+// of path segments (first segment follows the leading slash). Only literal semicolons separate parameters so encoded
+// ones (`%3B`) stay part of segment or parameter value.
+func stripMatrixParams(escapedPath string) (string, []matrixSegment) {
+	segments := strings.Split(escapedPath, "/")
+	matrix := make([]matrixSegment, 0, len(segments))
+	var sb strings.Builder
+	sb.Grow(len(escapedPath))
+	for i, s := range segments {
+		if i > 0 {
+			sb.WriteByte('/')
+		}
+		segment, params, _ := strings.Cut(s, ";")
+		sb.WriteString(segment)
+		if i == 0 {
+			continue
+		}
+
+		m := matrixSegment{segment: unescapeMatrix(segment)}
+		for params != "" {
+			var param string
+			param, params, _ = strings.Cut(params, ";")
+			if param == "" {
+				continue
+			}
+			name, value, _ := strings.Cut(param, "=")
+			if m.params == nil {
+				m.params = url.Values{}
+			}
+			m.params.Add(unescapeMatrix(name), unescapeMatrix(value))
+		}
+		matrix = append(matrix, m)
+	}
+	return sb.String(), matrix
+}
+
+func unescapeMatrix(s string) string {
+	if v, err := url.PathUnescape(s); err == nil {
+		return v
+	}
+	return s
+}
+
+// MatrixParam returns the first value of matrix parameter of path segment. Segment is looked up first by name of the
+// route path param occupying the whole segment (`id` for `/users/:id`) and then by the segment value itself (`users`
+// for request `/users;role=admin/42`).
+func (c *context) MatrixParam(segment string, name string) string {
+	if len(c.matrix) == 0 {
+		return ""
+	}
+	index := 0
+	for _, s := range strings.Split(c.path, "/")[1:] {
+		if index >= len(c.matrix) {
+			break
+		}
+		if s == ":"+segment {
+			return c.matrix[index].params.Get(name)
+		}
+		index++
+	}
+	for _, m := range c.matrix {
+		if m.segment == segment {
+			return m.params.Get(name)
+		}
+	}
+	return ""
+}
+
+// MatrixParams returns matrix parameters of all path segments. Values of the same name from different segments are
+// returned in the order of segments.
+func (c *context) MatrixParams() url.Values {
+	params := url.Values{}
+	for _, m := range c.matrix {
+		for name, values := range m.params {
+			params[name] = append(params[name], values...)
+		}
+	}
+	return params
+}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripMatrixParams(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		whenPath     string
+		expectPath   string
+		expectMatrix []matrixSegment
+	}{
+		{
+			name:       "ok, params of several segments",
+			whenPath:   "/users;role=admin;active/42;v=1;v=2",
+			expectPath: "/users/42",
+			expectMatrix: []matrixSegment{
+				{segment: "users", params: url.Values{"role": {"admin"}, "active": {""}}},
+				{segment: "42", params: url.Values{"v": {"1", "2"}}},
+			},
+		},
+		{
+			name:       "ok, encoded semicolon is not separator",
+			whenPath:   "/files/a%3Bb;rev=x%3By",
+			expectPath: "/files/a%3Bb",
+			expectMatrix: []matrixSegment{
+				{segment: "files"},
+				{segment: "a;b", params: url.Values{"rev": {"x;y"}}},
+			},
+		},
+		{
+			name:       "ok, empty params and trailing slash",
+			whenPath:   "/users;;/",
+			expectPath: "/users/",
+			expectMatrix: []matrixSegment{
+				{segment: "users"},
+				{segment: ""},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, matrix := stripMatrixParams(tc.whenPath)
+			assert.Equal(t, tc.expectPath, path)
+			assert.Equal(t, tc.expectMatrix, matrix)
+		})
+	}
+}
+
+func TestEcho_MatrixParams(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		givenDisabled bool
+		givenEncoded  bool
+		whenURL       string
+		expectCode    int
+		expectBody    string
+	}{
+		{
+			name:       "ok, params of static segment and path param",
+			whenURL:    "/users;role=admin/42;v=2",
+			expectCode: http.StatusOK,
+			expectBody: "id=42 role=admin v=2",
+		},
+		{
+			name:       "ok, without matrix params",
+			whenURL:    "/users/42",
+			expectCode: http.StatusOK,
+			expectBody: "id=42 role= v=",
+		},
+		{
+			// same as without MatrixParams: path with non-canonical encoding is routed in its escaped form
+			name:       "ok, encoded semicolon stays in path param",
+			whenURL:    "/users/4%3B2;v=1",
+			expectCode: http.StatusOK,
+			expectBody: "id=4%3B2 role= v=1",
+		},
+		{
+			name:         "ok, encoded path",
+			givenEncoded: true,
+			whenURL:      "/users;role=admin/4%2F2;v=3",
+			expectCode:   http.StatusOK,
+			expectBody:   "id=4/2 role=admin v=3",
+		},
+		{
+			name:          "nok, disabled",
+			givenDisabled: true,
+			whenURL:       "/users;role=admin/42",
+			expectCode:    http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.MatrixParams = !tc.givenDisabled
+			e.UseEncodedPath = tc.givenEncoded
+			e.GET("/users/:id", func(c Context) error {
+				return c.String(http.StatusOK, "id="+c.Param("id")+" role="+c.MatrixParam("users", "role")+" v="+c.MatrixParam("id", "v"))
+			})
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.whenURL, nil))
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			if tc.expectBody != "" {
+				assert.Equal(t, tc.expectBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestDefaultBinder_BindMatrixParams(t *testing.T) {
+	type target struct {
+		ID   int    `param:"id"`
+		Role string `matrix:"role,required"`
+		Rev  []int  `matrix:"rev"`
+		Page int    `query:"page"`
+	}
+	e := New()
+	e.MatrixParams = true
+	var result target
+	var bindErr error
+	e.GET("/users/:id/files/:name", func(c Context) error {
+		result = target{}
+		bindErr = c.Bind(&result)
+		return nil
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users;role=admin/42/files/a;rev=1/b;rev=2?page=3", nil))
+	assert.NoError(t, bindErr)
+	assert.Equal(t, target{ID: 42, Role: "admin", Rev: []int{1, 2}, Page: 3}, result)
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42/files/a", nil))
+	var requiredErr *RequiredFieldsError
+	assert.ErrorAs(t, bindErr, &requiredErr)
+	assert.Equal(t, "matrix", requiredErr.Fields[0].Source)
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users;role=x/42/files/a;rev=abc", nil))
+	var fieldErr *BindFieldError
+	assert.ErrorAs(t, bindErr, &fieldErr)
+	assert.Equal(t, "rev", fieldErr.Name)
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	stdContext "context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+)
+
+// ErrPartWriterClosed is returned when part is written after `PartWriter#Close` has been called.
+var ErrPartWriterClosed = errors.New("echo: multipart stream is closed")
+
+// PartWriter writes parts of `multipart/x-mixed-replace` response. It is not safe for concurrent use.
+type PartWriter struct {
+	response   *Response
+	ctx        stdContext.Context
+	cancel     stdContext.CancelFunc
+	deregister func()
+	writer     *multipart.Writer
+	err        error
+	committed  bool
+	closed     bool
+}
+
+// MultipartStream starts streaming `multipart/x-mixed-replace` response. Response is marked with
+// `Response#DisableCompression` so compression middlewares do not buffer parts. Stream is registered with
+// `Echo#RegisterLongLivedConn` so NextPart returns context error when server is being shut down; Close must be called
+// to deregister it.
+//
+// Example:
+//
+//	pw := c.MultipartStream("frame")
+//	defer pw.Close()
+//	for frame := range frames {
+//		w, err := pw.NextPart(textproto.MIMEHeader{"Content-Type": {"image/jpeg"}})
+//		if err != nil {
+//			return err
+//		}
+//		w.Write(frame)
+//	}
+func (c *context) MultipartStream(boundary string) *PartWriter {
+	ctx, cancel := stdContext.WithCancel(c.request.Context())
+	pw := &PartWriter{
+		response:   c.response,
+		ctx:        ctx,
+		cancel:     cancel,
+		deregister: c.echo.RegisterLongLivedConn(cancel),
+		writer:     multipart.NewWriter(c.response),
+	}
+	if boundary != "" {
+		pw.err = pw.writer.SetBoundary(boundary)
+	}
+	return pw
+}
+
+// NextPart finishes the previous part, sends it to the client and starts new part with given headers. Returned writer
+// is valid until the next call of NextPart or Close. Returns error of the request context when client has
+// disconnected or server is being shut down.
+func (pw *PartWriter) NextPart(header textproto.MIMEHeader) (io.Writer, error) {
+	if pw.closed {
+		return nil, ErrPartWriterClosed
+	}
+	if pw.err != nil {
+		return nil, pw.err
+	}
+	if err := pw.ctx.Err(); err != nil {
+		return nil, err
+	}
+	pw.commit()
+	w, err := pw.writer.CreatePart(header)
+	if err != nil {
+		pw.err = err
+		return nil, err
+	}
+	// delimiter of the new part tells the client that the previous part is complete
+	pw.Flush()
+	return w, nil
+}
+
+// Flush sends data written so far to the client.
+func (pw *PartWriter) Flush() {
+	_ = http.NewResponseController(pw.response.Writer).Flush()
+}
+
+// Close writes the closing boundary, flushes the response and deregisters the stream from long-lived connections.
+// Close does not close underlying connection.
+func (pw *PartWriter) Close() error {
+	if pw.closed {
+		return nil
+	}
+	pw.closed = true
+	defer pw.cancel()
+	defer pw.deregister()
+	if pw.err != nil {
+		return pw.err
+	}
+	pw.commit()
+	if err := pw.writer.Close(); err != nil {
+		return err
+	}
+	pw.Flush()
+	return nil
+}
+
+func (pw *PartWriter) commit() {
+	if pw.committed {
+		return
+	}
+	pw.committed = true
+	pw.response.DisableCompression = true
+	header := pw.response.Header()
+	header.Del(HeaderContentLength)
+	header.Set(HeaderContentType, MIMEMultipartMixedReplace+"; boundary="+pw.writer.Boundary())
+	pw.response.WriteHeader(http.StatusOK)
+}
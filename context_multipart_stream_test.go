@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	stdContext "context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_MultipartStream(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	pw := c.MultipartStream("frame")
+	for _, frame := range []string{"first", "second"} {
+		w, err := pw.NextPart(textproto.MIMEHeader{HeaderContentType: {"image/jpeg"}})
+		assert.NoError(t, err)
+		_, err = io.WriteString(w, frame)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, pw.Close())
+	assert.NoError(t, pw.Close())
+
+	_, err := pw.NextPart(nil)
+	assert.ErrorIs(t, err, ErrPartWriterClosed)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, rec.Flushed)
+	assert.True(t, c.Response().DisableCompression)
+	assert.Equal(t, "multipart/x-mixed-replace; boundary=frame", rec.Header().Get(HeaderContentType))
+	assert.Equal(t, "--frame\r\nContent-Type: image/jpeg\r\n\r\nfirst\r\n--frame\r\nContent-Type: image/jpeg\r\n\r\nsecond\r\n--frame--\r\n", rec.Body.String())
+
+	_, params, err := mime.ParseMediaType(rec.Header().Get(HeaderContentType))
+	assert.NoError(t, err)
+	r := multipart.NewReader(rec.Body, params["boundary"])
+	part, err := r.NextPart()
+	assert.NoError(t, err)
+	b, _ := io.ReadAll(part)
+	assert.Equal(t, "first", string(b))
+}
+
+func TestContext_MultipartStream_clientDisconnected(t *testing.T) {
+	e := New()
+	ctx, cancel := stdContext.WithCancel(stdContext.Background())
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	pw := c.MultipartStream("")
+	_, err := pw.NextPart(nil)
+	assert.NoError(t, err)
+
+	cancel()
+	_, err = pw.NextPart(nil)
+	assert.ErrorIs(t, err, stdContext.Canceled)
+}
+
+func TestContext_MultipartStream_shutdown(t *testing.T) {
+	e := New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	pw := c.MultipartStream("frame")
+	_, err := pw.NextPart(nil)
+	assert.NoError(t, err)
+
+	shutdownErr := make(chan error, 1)
+	go func() { shutdownErr <- e.Shutdown(stdContext.Background()) }()
+
+	<-pw.ctx.Done()
+	_, err = pw.NextPart(nil)
+	assert.ErrorIs(t, err, stdContext.Canceled)
+	assert.NoError(t, pw.Close())
+
+	assert.NoError(t, <-shutdownErr)
+	assert.True(t, strings.HasSuffix(rec.Body.String(), "--frame--\r\n"))
+}
+
+func TestContext_MultipartStream_invalidBoundary(t *testing.T) {
+	e := New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	pw := c.MultipartStream("invalid boundary\n")
+	_, err := pw.NextPart(nil)
+	assert.EqualError(t, err, "mime: invalid boundary character")
+	assert.False(t, c.Response().Committed)
+}
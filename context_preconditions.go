@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// EvaluatePreconditions evaluates conditional request headers against current validators of the resource in order
+// and with precedence described in RFC 9110 section 13.2.2:
+//
+//  1. `If-Match` (strong comparison), or `If-Unmodified-Since` when `If-Match` is not present. Failing condition
+//     results "412 - Precondition Failed".
+//  2. `If-None-Match` (weak comparison), or for GET and HEAD `If-Modified-Since` when `If-None-Match` is not present.
+//     Failing condition results "304 - Not Modified" for GET and HEAD and "412 - Precondition Failed" for other
+//     methods.
+//  3. `If-Range` for GET requests with `Range` header. Status is "206 - Partial Content" when the range should be
+//     served and "200 - OK" when `If-Range` does not match and the whole representation should be sent instead.
+//
+// currentETag is the entity-tag as sent in `ETag` header (ala `"v1"` or `W/"v1"`) and lastModified the time of last
+// modification. Empty currentETag and zero lastModified mean that the resource has no such validator (or does not
+// exist when both are missing). Headers with invalid dates are ignored.
+//
+// proceed is false when the request should not be processed and status holds code of the response to send
+// (`http.StatusPreconditionFailed` or `http.StatusNotModified`). Otherwise status is `http.StatusOK` or
+// `http.StatusPartialContent`.
+//
+// Example:
+//
+//	if proceed, status := c.EvaluatePreconditions(doc.ETag, doc.UpdatedAt); !proceed {
+//		if status == http.StatusNotModified {
+//			return c.NotModified()
+//		}
+//		return c.PreconditionFailed()
+//	}
+func (c *context) EvaluatePreconditions(currentETag string, lastModified time.Time) (bool, int) {
+	req := c.request
+	isGetOrHead := req.Method == http.MethodGet || req.Method == http.MethodHead
+	exists := currentETag != "" || !lastModified.IsZero()
+
+	if ifMatch := req.Header.Get(HeaderIfMatch); ifMatch != "" {
+		if !matchETags(ifMatch, currentETag, exists, true) {
+			return false, http.StatusPreconditionFailed
+		}
+	} else if since, ok := parseConditionTime(req.Header.Get(HeaderIfUnmodifiedSince)); ok && !lastModified.IsZero() {
+		if lastModified.Truncate(time.Second).After(since) {
+			return false, http.StatusPreconditionFailed
+		}
+	}
+
+	if ifNoneMatch := req.Header.Get(HeaderIfNoneMatch); ifNoneMatch != "" {
+		if matchETags(ifNoneMatch, currentETag, exists, false) {
+			if isGetOrHead {
+				return false, http.StatusNotModified
+			}
+			return false, http.StatusPreconditionFailed
+		}
+	} else if since, ok := parseConditionTime(req.Header.Get(HeaderIfModifiedSince)); ok && isGetOrHead && !lastModified.IsZero() {
+		if !lastModified.Truncate(time.Second).After(since) {
+			return false, http.StatusNotModified
+		}
+	}
+
+	if req.Method != http.MethodGet || req.Header.Get(HeaderRange) == "" {
+		return true, http.StatusOK
+	}
+	ifRange := req.Header.Get(HeaderIfRange)
+	if ifRange == "" {
+		return true, http.StatusPartialContent
+	}
+	if strings.HasPrefix(ifRange, `"`) || strings.HasPrefix(ifRange, `W/"`) {
+		if matchETags(ifRange, currentETag, exists, true) {
+			return true, http.StatusPartialContent
+		}
+		return true, http.StatusOK
+	}
+	if date, ok := parseConditionTime(ifRange); ok && !lastModified.IsZero() && lastModified.Truncate(time.Second).Equal(date) {
+		return true, http.StatusPartialContent
+	}
+	return true, http.StatusOK
+}
+
+// PreconditionFailed sends "412 - Precondition Failed" response without body.
+func (c *context) PreconditionFailed() error {
+	return c.NoContent(http.StatusPreconditionFailed)
+}
+
+func parseConditionTime(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	t, err := http.ParseTime(value)
+	return t, err == nil
+}
+
+// matchETags reports whether the list of entity-tags (or `*`) of a conditional header matches currentETag using
+// strong or weak comparison (RFC 9110 section 8.8.3.2). `*` matches any existing representation.
+func matchETags(list string, currentETag string, exists bool, strong bool) bool {
+	list = strings.TrimSpace(list)
+	if list == "*" {
+		return exists
+	}
+	if currentETag == "" {
+		return false
+	}
+	for list != "" {
+		etag, rest, ok := scanETag(list)
+		if !ok {
+			return false
+		}
+		if compareETags(etag, currentETag, strong) {
+			return true
+		}
+		list = strings.TrimLeft(rest, " \t,")
+	}
+	return false
+}
+
+// compareETags compares two entity-tags. Strong comparison requires both tags to be strong and their opaque tags to
+// be equal. Weak comparison ignores the weak indicator.
+func compareETags(a string, b string, strong bool) bool {
+	aWeak := strings.HasPrefix(a, "W/")
+	bWeak := strings.HasPrefix(b, "W/")
+	if strong && (aWeak || bWeak) {
+		return false
+	}
+	return strings.TrimPrefix(a, "W/") == strings.TrimPrefix(b, "W/")
+}
+
+// scanETag returns first entity-tag of s and the rest of s after it.
+func scanETag(s string) (etag string, rest string, ok bool) {
+	start := 0
+	if strings.HasPrefix(s, "W/") {
+		start = 2
+	}
+	if len(s[start:]) < 2 || s[start] != '"' {
+		return "", "", false
+	}
+	end := strings.IndexByte(s[start+1:], '"')
+	if end == -1 {
+		return "", "", false
+	}
+	end += start + 2
+	return s[:end], s[end:], true
+}
@@ -0,0 +1,265 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareETags(t *testing.T) {
+	// examples of RFC 9110 section 8.8.3.2
+	var testCases = []struct {
+		a            string
+		b            string
+		expectWeak   bool
+		expectStrong bool
+	}{
+		{a: `W/"1"`, b: `W/"1"`, expectStrong: false, expectWeak: true},
+		{a: `W/"1"`, b: `W/"2"`, expectStrong: false, expectWeak: false},
+		{a: `W/"1"`, b: `"1"`, expectStrong: false, expectWeak: true},
+		{a: `"1"`, b: `"1"`, expectStrong: true, expectWeak: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.a+" "+tc.b, func(t *testing.T) {
+			assert.Equal(t, tc.expectStrong, compareETags(tc.a, tc.b, true))
+			assert.Equal(t, tc.expectWeak, compareETags(tc.a, tc.b, false))
+		})
+	}
+}
+
+func TestMatchETags(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		list        string
+		current     string
+		exists      bool
+		strong      bool
+		expectMatch bool
+	}{
+		{name: "star matches existing", list: "*", current: `"a"`, exists: true, strong: true, expectMatch: true},
+		{name: "star does not match missing", list: "*", exists: false, expectMatch: false},
+		{name: "list", list: `"x", W/"y" ,"a"`, current: `"a"`, strong: true, expectMatch: true},
+		{name: "list with weak current strong", list: `"x", "a"`, current: `W/"a"`, strong: true, expectMatch: false},
+		{name: "list with weak current weak", list: `"x", "a"`, current: `W/"a"`, strong: false, expectMatch: true},
+		{name: "comma inside tag", list: `"a,b"`, current: `"a,b"`, strong: true, expectMatch: true},
+		{name: "malformed", list: `a`, current: `"a"`, strong: false, expectMatch: false},
+		{name: "no current etag", list: `"a"`, current: "", exists: true, expectMatch: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectMatch, matchETags(tc.list, tc.current, tc.exists, tc.strong))
+		})
+	}
+}
+
+func TestContext_EvaluatePreconditions(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 10, 0, 0, 500, time.UTC)
+	before := modified.Add(-time.Hour).Format(http.TimeFormat)
+	same := modified.Format(http.TimeFormat)
+	after := modified.Add(time.Hour).Format(http.TimeFormat)
+
+	var testCases = []struct {
+		name          string
+		whenMethod    string
+		whenHeaders   map[string]string
+		givenETag     string
+		givenModified time.Time
+		expectProceed bool
+		expectStatus  int
+	}{
+		{
+			name:          "ok, no conditions",
+			whenMethod:    http.MethodPut,
+			givenETag:     `"v1"`,
+			expectProceed: true,
+			expectStatus:  http.StatusOK,
+		},
+		{
+			name:          "ok, If-Match matches",
+			whenMethod:    http.MethodPut,
+			whenHeaders:   map[string]string{HeaderIfMatch: `"v0", "v1"`},
+			givenETag:     `"v1"`,
+			expectProceed: true,
+			expectStatus:  http.StatusOK,
+		},
+		{
+			name:         "nok, If-Match does not match",
+			whenMethod:   http.MethodPut,
+			whenHeaders:  map[string]string{HeaderIfMatch: `"v0"`},
+			givenETag:    `"v1"`,
+			expectStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:         "nok, If-Match uses strong comparison",
+			whenMethod:   http.MethodPut,
+			whenHeaders:  map[string]string{HeaderIfMatch: `W/"v1"`},
+			givenETag:    `W/"v1"`,
+			expectStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:         "nok, If-Match star without representation",
+			whenMethod:   http.MethodPut,
+			whenHeaders:  map[string]string{HeaderIfMatch: "*"},
+			expectStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:          "ok, If-Match takes precedence over If-Unmodified-Since",
+			whenMethod:    http.MethodPut,
+			whenHeaders:   map[string]string{HeaderIfMatch: `"v1"`, HeaderIfUnmodifiedSince: before},
+			givenETag:     `"v1"`,
+			givenModified: modified,
+			expectProceed: true,
+			expectStatus:  http.StatusOK,
+		},
+		{
+			name:          "ok, If-Unmodified-Since with same second",
+			whenMethod:    http.MethodPut,
+			whenHeaders:   map[string]string{HeaderIfUnmodifiedSince: same},
+			givenModified: modified,
+			expectProceed: true,
+			expectStatus:  http.StatusOK,
+		},
+		{
+			name:          "nok, modified after If-Unmodified-Since",
+			whenMethod:    http.MethodPut,
+			whenHeaders:   map[string]string{HeaderIfUnmodifiedSince: before},
+			givenModified: modified,
+			expectStatus:  http.StatusPreconditionFailed,
+		},
+		{
+			name:          "ok, invalid If-Unmodified-Since is ignored",
+			whenMethod:    http.MethodPut,
+			whenHeaders:   map[string]string{HeaderIfUnmodifiedSince: "yesterday"},
+			givenModified: modified,
+			expectProceed: true,
+			expectStatus:  http.StatusOK,
+		},
+		{
+			name:         "nok, If-None-Match matches weakly for GET",
+			whenMethod:   http.MethodGet,
+			whenHeaders:  map[string]string{HeaderIfNoneMatch: `W/"v1"`},
+			givenETag:    `"v1"`,
+			expectStatus: http.StatusNotModified,
+		},
+		{
+			name:         "nok, If-None-Match star for PUT",
+			whenMethod:   http.MethodPut,
+			whenHeaders:  map[string]string{HeaderIfNoneMatch: "*"},
+			givenETag:    `"v1"`,
+			expectStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:          "ok, If-None-Match star creates missing resource",
+			whenMethod:    http.MethodPut,
+			whenHeaders:   map[string]string{HeaderIfNoneMatch: "*"},
+			expectProceed: true,
+			expectStatus:  http.StatusOK,
+		},
+		{
+			name:          "ok, If-None-Match takes precedence over If-Modified-Since",
+			whenMethod:    http.MethodGet,
+			whenHeaders:   map[string]string{HeaderIfNoneMatch: `"v0"`, HeaderIfModifiedSince: after},
+			givenETag:     `"v1"`,
+			givenModified: modified,
+			expectProceed: true,
+			expectStatus:  http.StatusOK,
+		},
+		{
+			name:          "nok, not modified since",
+			whenMethod:    http.MethodHead,
+			whenHeaders:   map[string]string{HeaderIfModifiedSince: same},
+			givenModified: modified,
+			expectStatus:  http.StatusNotModified,
+		},
+		{
+			name:          "ok, modified since",
+			whenMethod:    http.MethodGet,
+			whenHeaders:   map[string]string{HeaderIfModifiedSince: before},
+			givenModified: modified,
+			expectProceed: true,
+			expectStatus:  http.StatusOK,
+		},
+		{
+			name:          "ok, If-Modified-Since is ignored for POST",
+			whenMethod:    http.MethodPost,
+			whenHeaders:   map[string]string{HeaderIfModifiedSince: after},
+			givenModified: modified,
+			expectProceed: true,
+			expectStatus:  http.StatusOK,
+		},
+		{
+			name:          "ok, Range without If-Range",
+			whenMethod:    http.MethodGet,
+			whenHeaders:   map[string]string{HeaderRange: "bytes=0-9"},
+			givenETag:     `"v1"`,
+			expectProceed: true,
+			expectStatus:  http.StatusPartialContent,
+		},
+		{
+			name:          "ok, If-Range etag matches",
+			whenMethod:    http.MethodGet,
+			whenHeaders:   map[string]string{HeaderRange: "bytes=0-9", HeaderIfRange: `"v1"`},
+			givenETag:     `"v1"`,
+			expectProceed: true,
+			expectStatus:  http.StatusPartialContent,
+		},
+		{
+			name:          "ok, If-Range weak etag never matches",
+			whenMethod:    http.MethodGet,
+			whenHeaders:   map[string]string{HeaderRange: "bytes=0-9", HeaderIfRange: `W/"v1"`},
+			givenETag:     `W/"v1"`,
+			expectProceed: true,
+			expectStatus:  http.StatusOK,
+		},
+		{
+			name:          "ok, If-Range date matches exactly",
+			whenMethod:    http.MethodGet,
+			whenHeaders:   map[string]string{HeaderRange: "bytes=0-9", HeaderIfRange: same},
+			givenModified: modified,
+			expectProceed: true,
+			expectStatus:  http.StatusPartialContent,
+		},
+		{
+			name:          "ok, If-Range date does not match",
+			whenMethod:    http.MethodGet,
+			whenHeaders:   map[string]string{HeaderRange: "bytes=0-9", HeaderIfRange: after},
+			givenModified: modified,
+			expectProceed: true,
+			expectStatus:  http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.whenMethod, "/", nil)
+			for k, v := range tc.whenHeaders {
+				req.Header.Set(k, v)
+			}
+			c := New().NewContext(req, httptest.NewRecorder())
+
+			proceed, status := c.EvaluatePreconditions(tc.givenETag, tc.givenModified)
+
+			assert.Equal(t, tc.expectProceed, proceed)
+			assert.Equal(t, tc.expectStatus, status)
+		})
+	}
+}
+
+func TestContext_PreconditionFailed(t *testing.T) {
+	rec := httptest.NewRecorder()
+	c := New().NewContext(httptest.NewRequest(http.MethodPut, "/", nil), rec)
+
+	err := c.PreconditionFailed()
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	assert.Equal(t, "", rec.Body.String())
+}
@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	stdContext "context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrProgressStreamFinished is returned when record is written after `ProgressStream#Finish` or
+// `ProgressStream#Close` has been called.
+var ErrProgressStreamFinished = errors.New("echo: progress stream is finished")
+
+// ProgressStream writes progress of long-running operation as JSON lines (`application/x-ndjson`) response. Each
+// record is flushed to the client as soon as it is written. It is safe for concurrent use.
+type ProgressStream struct {
+	response *Response
+	// requestCtx is cancelled only when client disconnects, ctx also when server is being shut down
+	requestCtx stdContext.Context
+	ctx        stdContext.Context
+	cancel     stdContext.CancelFunc
+	deregister func()
+	code       int
+
+	mu            sync.Mutex
+	committed     bool
+	finished      bool
+	stopHeartbeat chan struct{}
+	heartbeatDone chan struct{}
+}
+
+// ProgressHeartbeat is the record written by `ProgressStream#Heartbeat`.
+type ProgressHeartbeat struct {
+	Heartbeat bool `json:"heartbeat"`
+}
+
+// ProgressResult is the terminal record written by `ProgressStream#Finish`.
+type ProgressResult struct {
+	Done   bool           `json:"done"`
+	Result interface{}    `json:"result,omitempty"`
+	Error  *ProgressError `json:"error,omitempty"`
+}
+
+// ProgressError is the error of failed operation in ProgressResult. Code and message of `*HTTPError` are used as is,
+// other errors have no code.
+type ProgressError struct {
+	Code    int         `json:"code,omitempty"`
+	Message interface{} `json:"message"`
+}
+
+// ProgressStream starts streaming JSON lines response with progress of long-running operation. Response is marked
+// with `Response#DisableCompression` so compression middlewares do not buffer records. Status code and headers are
+// sent with the first record. Stream is registered with `Echo#RegisterLongLivedConn` so its context is cancelled when
+// server is being shut down; Finish or Close must be called to deregister it.
+//
+// Example:
+//
+//	ps := c.ProgressStream(http.StatusOK)
+//	defer ps.Close()
+//	ps.Heartbeat(10 * time.Second)
+//	for i, item := range items {
+//		if err := ps.Update(map[string]int{"done": i, "total": len(items)}); err != nil {
+//			return err // client has disconnected
+//		}
+//		process(ps.Context(), item)
+//	}
+//	return ps.Finish(summary, nil)
+func (c *context) ProgressStream(code int) *ProgressStream {
+	requestCtx := c.request.Context()
+	ctx, cancel := stdContext.WithCancel(requestCtx)
+	return &ProgressStream{
+		response:   c.response,
+		requestCtx: requestCtx,
+		ctx:        ctx,
+		cancel:     cancel,
+		deregister: c.echo.RegisterLongLivedConn(cancel),
+		code:       code,
+	}
+}
+
+// Context returns context of the stream. It is cancelled when client disconnects or server is being shut down so
+// long-running work should use it (or context derived from it) to stop early.
+func (ps *ProgressStream) Context() stdContext.Context {
+	return ps.ctx
+}
+
+// Update writes progress record (JSON encoded v followed by newline) and flushes it to the client. Returns error of
+// the stream context when client has disconnected or server is being shut down and ErrProgressStreamFinished after
+// Finish or Close.
+func (ps *ProgressStream) Update(v interface{}) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if ps.finished {
+		return ErrProgressStreamFinished
+	}
+	return ps.write(ps.ctx, v)
+}
+
+// Heartbeat starts writing `{"heartbeat":true}` record every interval so proxies and clients do not time out idle
+// connection while the operation makes no progress. Heartbeats stop on Finish, Close or when client disconnects.
+// Calling Heartbeat again has no effect.
+func (ps *ProgressStream) Heartbeat(interval time.Duration) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if ps.finished || ps.heartbeatDone != nil || interval <= 0 {
+		return
+	}
+	ps.stopHeartbeat = make(chan struct{})
+	ps.heartbeatDone = make(chan struct{})
+	go ps.heartbeat(interval, ps.stopHeartbeat, ps.heartbeatDone)
+}
+
+func (ps *ProgressStream) heartbeat(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ps.ctx.Done():
+			return
+		case <-ticker.C:
+			ps.mu.Lock()
+			err := ps.write(ps.ctx, ProgressHeartbeat{Heartbeat: true})
+			ps.mu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Finish writes terminal record with result of the operation (or with error when err is not nil) and stops
+// heartbeats. Further records are rejected with ErrProgressStreamFinished. Terminal record is written also when server
+// is being shut down so the client learns the outcome. Finish does not close the connection.
+func (ps *ProgressStream) Finish(result interface{}, err error) error {
+	if ps.finish() {
+		return ErrProgressStreamFinished
+	}
+	record := ProgressResult{Done: true, Result: result}
+	if err != nil {
+		record = ProgressResult{Done: true, Error: newProgressError(err)}
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return ps.write(ps.requestCtx, record)
+}
+
+// Close stops heartbeats and rejects further records without writing terminal record. It is safe to call Close after
+// Finish so it can be deferred. Heartbeats are written from another goroutine so the stream must be finished or closed
+// before the handler returns.
+func (ps *ProgressStream) Close() error {
+	ps.finish()
+	return nil
+}
+
+// finish marks stream finished, stops heartbeat goroutine, waits until it has exited and deregisters the stream from
+// long-lived connections. Returns true when stream was already finished.
+func (ps *ProgressStream) finish() bool {
+	ps.mu.Lock()
+	finished := ps.finished
+	ps.finished = true
+	stop, done := ps.stopHeartbeat, ps.heartbeatDone
+	ps.stopHeartbeat = nil
+	ps.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+	if !finished {
+		ps.deregister()
+		ps.cancel()
+	}
+	return finished
+}
+
+// write writes record and flushes it unless ctx is done. Must be called with the lock held.
+func (ps *ProgressStream) write(ctx stdContext.Context, v interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	ps.commit()
+	if _, err := ps.response.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	_ = http.NewResponseController(ps.response.Writer).Flush()
+	return nil
+}
+
+func (ps *ProgressStream) commit() {
+	if ps.committed {
+		return
+	}
+	ps.committed = true
+	ps.response.DisableCompression = true
+	header := ps.response.Header()
+	header.Del(HeaderContentLength)
+	header.Set(HeaderContentType, MIMEApplicationNDJSON)
+	ps.response.WriteHeader(ps.code)
+}
+
+func newProgressError(err error) *ProgressError {
+	var he *HTTPError
+	if errors.As(err, &he) {
+		return &ProgressError{Code: he.Code, Message: he.Message}
+	}
+	return &ProgressError{Message: err.Error()}
+}
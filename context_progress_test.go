@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	stdContext "context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext_ProgressStream(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	ps := c.ProgressStream(http.StatusAccepted)
+	assert.NoError(t, ps.Update(map[string]int{"done": 1, "total": 2}))
+	assert.NoError(t, ps.Update(map[string]int{"done": 2, "total": 2}))
+	assert.NoError(t, ps.Finish(map[string]string{"id": "42"}, nil))
+
+	assert.ErrorIs(t, ps.Update(1), ErrProgressStreamFinished)
+	assert.ErrorIs(t, ps.Finish(nil, nil), ErrProgressStreamFinished)
+	assert.NoError(t, ps.Close())
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.True(t, rec.Flushed)
+	assert.True(t, c.Response().DisableCompression)
+	assert.Equal(t, MIMEApplicationNDJSON, rec.Header().Get(HeaderContentType))
+	assert.Equal(t, `{"done":1,"total":2}
+{"done":2,"total":2}
+{"done":true,"result":{"id":"42"}}
+`, rec.Body.String())
+}
+
+func TestContext_ProgressStream_finishWithError(t *testing.T) {
+	var testCases = []struct {
+		name      string
+		whenErr   error
+		expectOut string
+	}{
+		{
+			name:      "ok, HTTPError",
+			whenErr:   NewHTTPError(http.StatusConflict, "already running"),
+			expectOut: `{"done":true,"error":{"code":409,"message":"already running"}}` + "\n",
+		},
+		{
+			name:      "ok, other error",
+			whenErr:   errors.New("disk full"),
+			expectOut: `{"done":true,"error":{"message":"disk full"}}` + "\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), rec)
+
+			ps := c.ProgressStream(http.StatusOK)
+			assert.NoError(t, ps.Finish("ignored", tc.whenErr))
+
+			assert.Equal(t, tc.expectOut, rec.Body.String())
+		})
+	}
+}
+
+func TestContext_ProgressStream_heartbeat(t *testing.T) {
+	e := New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), rec)
+
+	ps := c.ProgressStream(http.StatusOK)
+	ps.Heartbeat(time.Millisecond)
+	ps.Heartbeat(time.Hour) // no effect
+	time.Sleep(20 * time.Millisecond)
+	assert.NoError(t, ps.Finish(nil, nil))
+
+	lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+	assert.Greater(t, len(lines), 1)
+	for _, line := range lines[:len(lines)-1] {
+		assert.Equal(t, `{"heartbeat":true}`, line)
+	}
+	assert.Equal(t, `{"done":true}`, lines[len(lines)-1])
+}
+
+func TestContext_ProgressStream_clientDisconnected(t *testing.T) {
+	e := New()
+	ctx, cancel := stdContext.WithCancel(stdContext.Background())
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil).WithContext(ctx), rec)
+
+	ps := c.ProgressStream(http.StatusOK)
+	ps.Heartbeat(time.Millisecond)
+	assert.NoError(t, ps.Update(1))
+
+	cancel()
+	<-ps.Context().Done()
+	assert.ErrorIs(t, ps.Update(2), stdContext.Canceled)
+	assert.ErrorIs(t, ps.Finish(nil, nil), stdContext.Canceled)
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "1\n"))
+	assert.NotContains(t, rec.Body.String(), "2\n")
+}
+
+func TestContext_ProgressStream_shutdown(t *testing.T) {
+	e := New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), rec)
+
+	ps := c.ProgressStream(http.StatusOK)
+	assert.NoError(t, ps.Update(1))
+
+	shutdownErr := make(chan error, 1)
+	go func() { shutdownErr <- e.Shutdown(stdContext.Background()) }()
+
+	<-ps.Context().Done()
+	assert.ErrorIs(t, ps.Update(2), stdContext.Canceled)
+	assert.NoError(t, ps.Finish(nil, errors.New("shutting down")))
+
+	assert.NoError(t, <-shutdownErr)
+	assert.Equal(t, "1\n"+`{"done":true,"error":{"message":"shutting down"}}`+"\n", rec.Body.String())
+}
@@ -4,6 +4,7 @@
 package echo
 
 import (
+	"bufio"
 	"bytes"
 	"crypto/tls"
 	"encoding/json"
@@ -13,10 +14,15 @@ import (
 	"io"
 	"math"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"reflect"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"text/template"
 	"time"
@@ -630,6 +636,9 @@ func TestContextPathParam(t *testing.T) {
 	// Param
 	assert.Equal(t, "501", c.Param("fid"))
 	assert.Equal(t, "", c.Param("undefined"))
+
+	// PathParamsMap
+	assert.Equal(t, map[string]string{"uid": "101", "fid": "501"}, c.PathParamsMap())
 }
 
 func TestContextGetAndSetParam(t *testing.T) {
@@ -750,6 +759,182 @@ func TestContextQueryParam(t *testing.T) {
 	}, c.QueryParams())
 }
 
+func TestContextQueryParams_cacheInvalidation(t *testing.T) {
+	e := New()
+	req := httptest.NewRequest(http.MethodGet, "/?name=first", nil)
+	c := e.NewContext(req, nil)
+	assert.Equal(t, "first", c.QueryParam("name"))
+
+	// request replaced
+	c.SetRequest(httptest.NewRequest(http.MethodGet, "/?name=second", nil))
+	assert.Equal(t, "second", c.QueryParam("name"))
+
+	// request URL mutated in place
+	c.Request().URL.RawQuery = "name=third"
+	assert.Equal(t, url.Values{"name": []string{"third"}}, c.QueryParams())
+
+	// values added to cached map are kept while URL stays the same
+	c.QueryParams().Add("extra", "x")
+	assert.Equal(t, "x", c.QueryParam("extra"))
+}
+
+func TestContext_QueryParamIter(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenQuery  string
+		whenStopAt  int
+		expectPairs []string
+	}{
+		{
+			name:        "ok, in order with duplicates",
+			givenQuery:  "b=2&a=1&b=3",
+			expectPairs: []string{"b=2", "a=1", "b=3"},
+		},
+		{
+			name:        "ok, unescapes plus and percent",
+			givenQuery:  "na%20me=Jon+Snow&x=%2B",
+			expectPairs: []string{"na me=Jon Snow", "x=+"},
+		},
+		{
+			name:        "ok, empty values and keys",
+			givenQuery:  "a&b=&=c&&",
+			expectPairs: []string{"a=", "b=", "=c"},
+		},
+		{
+			name:        "ok, skips pairs with semicolon and invalid escapes",
+			givenQuery:  "a=1;b=2&c=%zz&d=4",
+			expectPairs: []string{"d=4"},
+		},
+		{
+			name:        "ok, stops when fn returns false",
+			givenQuery:  "a=1&b=2&c=3",
+			whenStopAt:  2,
+			expectPairs: []string{"a=1", "b=2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/?"+tc.givenQuery, nil), nil)
+
+			var pairs []string
+			c.QueryParamIter(func(key, value string) bool {
+				pairs = append(pairs, key+"="+value)
+				return tc.whenStopAt == 0 || len(pairs) < tc.whenStopAt
+			})
+
+			assert.Equal(t, tc.expectPairs, pairs)
+			assert.Nil(t, c.(*context).query)
+		})
+	}
+}
+
+func TestContext_QueryParam_doesNotParseWholeQuery(t *testing.T) {
+	e := New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/?a=1&name=Jon+Snow&name=x", nil), nil)
+
+	assert.Equal(t, "Jon Snow", c.QueryParam("name"))
+	assert.Equal(t, "", c.QueryParam("missing"))
+	assert.Nil(t, c.(*context).query)
+
+	assert.Equal(t, url.Values{"a": {"1"}, "name": {"Jon Snow", "x"}}, c.QueryParams())
+}
+
+func TestContext_QueryParam_manyParams(t *testing.T) {
+	q := make(url.Values)
+	for i := 0; i < queryScanMaxParams*2; i++ {
+		q.Set("p"+strconv.Itoa(i), strconv.Itoa(i))
+	}
+	e := New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/?"+q.Encode(), nil), nil)
+
+	assert.Equal(t, "150", c.QueryParam("p150"))
+	count := 0
+	c.QueryParamIter(func(key, value string) bool {
+		count++
+		return true
+	})
+	assert.Equal(t, queryScanMaxParams*2, count)
+}
+
+func FuzzContext_QueryParam(f *testing.F) {
+	f.Add("name=Jon+Snow&email=jon%40labstack.com", "name")
+	f.Add("a=1&a=2&b", "a")
+	f.Add("a=1;b=2&c=3", "c")
+	f.Add("%zz=1&a=%2", "a")
+	f.Add("=x&&a==b", "")
+	f.Add("k%20y=v+al&k+y=2", "k y")
+
+	f.Fuzz(func(t *testing.T, rawQuery string, name string) {
+		expect, _ := url.ParseQuery(rawQuery)
+
+		e := New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.URL.RawQuery = rawQuery
+		c := e.NewContext(req, nil)
+
+		if got := c.QueryParam(name); got != expect.Get(name) {
+			t.Fatalf("QueryParam(%q) = %q, url.ParseQuery gives %q", name, got, expect.Get(name))
+		}
+
+		iterated := url.Values{}
+		c.QueryParamIter(func(key, value string) bool {
+			iterated[key] = append(iterated[key], value)
+			return true
+		})
+		if !reflect.DeepEqual(iterated, expect) {
+			t.Fatalf("QueryParamIter = %v, url.ParseQuery gives %v", iterated, expect)
+		}
+	})
+}
+
+func TestContextMultipartForm_maxMultipartMemory(t *testing.T) {
+	var testCases = []struct {
+		name                   string
+		whenMaxMultipartMemory int64
+		expectOnDisk           bool
+	}{
+		{
+			name:         "ok, default memory limit keeps small file in memory",
+			expectOnDisk: false,
+		},
+		{
+			name:                   "ok, small memory limit stores file on disk",
+			whenMaxMultipartMemory: 1,
+			expectOnDisk:           true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.MaxMultipartMemory = tc.whenMaxMultipartMemory
+
+			buf := new(bytes.Buffer)
+			mw := multipart.NewWriter(buf)
+			w, err := mw.CreateFormFile("file", "test.txt")
+			assert.NoError(t, err)
+			w.Write([]byte("This is a test file"))
+			mw.Close()
+
+			req := httptest.NewRequest(http.MethodPost, "/", buf)
+			req.Header.Set(HeaderContentType, mw.FormDataContentType())
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			fh, err := c.FormFile("file")
+			assert.NoError(t, err)
+			f, err := fh.Open()
+			assert.NoError(t, err)
+			defer f.Close()
+			defer req.MultipartForm.RemoveAll()
+
+			_, isFile := f.(*os.File)
+			assert.Equal(t, tc.expectOnDisk, isFile)
+		})
+	}
+}
+
 func TestContextFormFile(t *testing.T) {
 	e := New()
 	buf := new(bytes.Buffer)
@@ -1017,6 +1202,149 @@ func TestContext_Bind(t *testing.T) {
 	assert.Equal(t, &user{1, "Jon Snow"}, u)
 }
 
+func TestContext_BindSingleSource(t *testing.T) {
+	type target struct {
+		ID    int    `query:"id" form:"id" header:"X-Id" json:"id"`
+		Name  string `query:"name" form:"name" header:"X-Name" json:"name"`
+		Token string `query:"token" form:"token"`
+	}
+
+	var testCases = []struct {
+		name            string
+		whenMethod      string
+		whenURL         string
+		whenContentType string
+		whenBody        string
+		whenHeaders     map[string]string
+		whenBind        func(c Context, i interface{}) error
+		expect          target
+		expectError     string
+	}{
+		{
+			name:            "ok, BindQuery ignores body",
+			whenMethod:      http.MethodPost,
+			whenURL:         "/?id=1&name=query",
+			whenContentType: MIMEApplicationJSON,
+			whenBody:        `{"id":2,"name":"body"}`,
+			whenBind:        Context.BindQuery,
+			expect:          target{ID: 1, Name: "query"},
+		},
+		{
+			name:            "ok, BindForm ignores query params",
+			whenMethod:      http.MethodPost,
+			whenURL:         "/?token=injected",
+			whenContentType: MIMEApplicationForm,
+			whenBody:        "id=3&name=form",
+			whenBind:        Context.BindForm,
+			expect:          target{ID: 3, Name: "form"},
+		},
+		{
+			name:            "nok, BindForm with JSON body",
+			whenMethod:      http.MethodPost,
+			whenURL:         "/",
+			whenContentType: MIMEApplicationJSON,
+			whenBody:        `{"id":2}`,
+			whenBind:        Context.BindForm,
+			expectError:     `code=415, message=Unsupported Media Type: received "application/json", supported: application/x-www-form-urlencoded, multipart/form-data, internal=unsupported media type "application/json"`,
+		},
+		{
+			name:        "ok, BindHeaders",
+			whenMethod:  http.MethodGet,
+			whenURL:     "/?id=1",
+			whenHeaders: map[string]string{"X-Id": "4", "X-Name": "header"},
+			whenBind:    Context.BindHeaders,
+			expect:      target{ID: 4, Name: "header"},
+		},
+		{
+			name:            "ok, BindBody ignores query params",
+			whenMethod:      http.MethodPost,
+			whenURL:         "/?id=1&name=query",
+			whenContentType: MIMEApplicationJSON,
+			whenBody:        `{"id":2,"name":"body"}`,
+			whenBind:        Context.BindBody,
+			expect:          target{ID: 2, Name: "body"},
+		},
+		{
+			name:        "nok, BindQuery keeps same error shape as Bind",
+			whenMethod:  http.MethodGet,
+			whenURL:     "/?id=nope",
+			whenBind:    Context.BindQuery,
+			expectError: "code=400, message=strconv.ParseInt: parsing \"nope\": invalid syntax, internal=strconv.ParseInt: parsing \"nope\": invalid syntax",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			req := httptest.NewRequest(tc.whenMethod, tc.whenURL, strings.NewReader(tc.whenBody))
+			if tc.whenContentType != "" {
+				req.Header.Set(HeaderContentType, tc.whenContentType)
+			}
+			for k, v := range tc.whenHeaders {
+				req.Header.Set(k, v)
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			result := target{}
+			err := tc.whenBind(c, &result)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, result)
+		})
+	}
+}
+
+type testQueryOnlyBinder struct {
+	DefaultBinder
+	called bool
+}
+
+func (b *testQueryOnlyBinder) BindQueryParams(c Context, i interface{}) error {
+	b.called = true
+	return b.DefaultBinder.BindQueryParams(c, i)
+}
+
+func TestContext_BindQuery_usesConfiguredBinder(t *testing.T) {
+	e := New()
+	binder := &testQueryOnlyBinder{}
+	e.Binder = binder
+
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/?id=1", nil), httptest.NewRecorder())
+	result := struct {
+		ID int `query:"id"`
+	}{}
+
+	assert.NoError(t, c.BindQuery(&result))
+	assert.True(t, binder.called)
+	assert.Equal(t, 1, result.ID)
+}
+
+type testBindOnlyBinder struct{}
+
+func (b *testBindOnlyBinder) Bind(i interface{}, c Context) error {
+	return nil
+}
+
+func TestContext_BindSingleSource_binderNotSingleSource(t *testing.T) {
+	e := New()
+	e.Binder = &testBindOnlyBinder{}
+
+	req := httptest.NewRequest(http.MethodPost, "/?id=1", strings.NewReader(`{"id":1}`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	c := e.NewContext(req, httptest.NewRecorder())
+	result := struct {
+		ID int `query:"id" json:"id"`
+	}{}
+
+	for _, bind := range []func(c Context, i interface{}) error{Context.BindQuery, Context.BindForm, Context.BindHeaders, Context.BindBody} {
+		assert.ErrorIs(t, bind(c, &result), ErrBinderNotSingleSource)
+	}
+	assert.Equal(t, 0, result.ID)
+}
+
 func TestContext_Logger(t *testing.T) {
 	e := New()
 	c := e.NewContext(nil, nil)
@@ -1033,6 +1361,140 @@ func TestContext_Logger(t *testing.T) {
 	assert.Equal(t, log1, c.Logger())
 }
 
+func TestContext_Logger_plainLogger(t *testing.T) {
+	e := New()
+	buf := new(bytes.Buffer)
+	e.Logger.SetOutput(buf)
+	e.Logger.SetLevel(log.INFO)
+	e.GET("/users/:id", func(c Context) error {
+		c.AddLogFields(map[string]interface{}{"user_id": 7})
+		_, ok := c.Logger().(*log.Logger)
+		assert.True(t, ok)
+		c.Logger().Info("hello")
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Contains(t, buf.String(), `"file":"context_test.go"`)
+	assert.NotContains(t, buf.String(), `"user_id"`)
+}
+
+type testLoggerWithFields struct {
+	Logger
+	fields map[string]interface{}
+}
+
+func (l *testLoggerWithFields) WithFields(fields map[string]interface{}) Logger {
+	return &testLoggerWithFields{Logger: l.Logger, fields: fields}
+}
+
+func TestContext_Logger_loggerWithFields(t *testing.T) {
+	e := New()
+	e.Logger = &testLoggerWithFields{Logger: e.Logger}
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	c.AddLogFields(map[string]interface{}{"tenant": "acme"})
+
+	l, ok := c.Logger().(*testLoggerWithFields)
+	assert.True(t, ok)
+	assert.Equal(t, "acme", l.fields["tenant"])
+	assert.Equal(t, "192.0.2.1", l.fields["remote_ip"])
+}
+
+func TestContext_Logger_fieldsAtLogTime(t *testing.T) {
+	e := New()
+	e.Logger = &testLoggerWithFields{Logger: e.Logger}
+
+	var before, after map[string]interface{}
+	e.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			before = c.Logger().(*testLoggerWithFields).fields
+			c.Response().Header().Set(HeaderXRequestID, "rid-1")
+			return next(c)
+		}
+	})
+	e.GET("/users/:id", func(c Context) error {
+		c.AddLogFields(map[string]interface{}{"user_id": 7})
+		after = c.Logger().(*testLoggerWithFields).fields
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.NotContains(t, before, "request_id")
+	assert.NotContains(t, before, "user_id")
+	assert.Equal(t, "rid-1", after["request_id"])
+	assert.Equal(t, "/users/:id", after["route"])
+	assert.Equal(t, 7, after["user_id"])
+}
+
+func TestContext_RejectContinue_authBeforeUpload(t *testing.T) {
+	e := New()
+	handlerCalled := false
+	e.POST("/upload", func(c Context) error {
+		handlerCalled = true
+		b, err := io.ReadAll(c.Request().Body) // first read sends "100 Continue" to the client
+		if err != nil {
+			return err
+		}
+		return c.String(http.StatusOK, string(b))
+	}, func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			if c.Request().Header.Get(HeaderAuthorization) != "Bearer token" {
+				assert.True(t, c.ExpectsContinue())
+				return c.RejectContinue(http.StatusUnauthorized, errors.New("missing token"))
+			}
+			return next(c)
+		}
+	})
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	sendHeaders := func(conn net.Conn, auth string) *bufio.Reader {
+		_, err := fmt.Fprintf(conn, "POST /upload HTTP/1.1\r\nHost: example.com\r\n"+
+			"Content-Length: 5\r\nExpect: 100-continue\r\n%s\r\n", auth)
+		assert.NoError(t, err)
+		return bufio.NewReader(conn)
+	}
+
+	t.Run("nok, rejected without body being sent", func(t *testing.T) {
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		res, err := http.ReadResponse(sendHeaders(conn, ""), nil)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode) // no "100 Continue" before final status
+		assert.True(t, res.Close)
+		assert.False(t, handlerCalled)
+	})
+
+	t.Run("ok, accepted upload receives 100 continue", func(t *testing.T) {
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		reader := sendHeaders(conn, "Authorization: Bearer token\r\n")
+		res, err := http.ReadResponse(reader, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusContinue, res.StatusCode)
+
+		_, err = conn.Write([]byte("hello"))
+		assert.NoError(t, err)
+
+		res, err = http.ReadResponse(reader, nil)
+		assert.NoError(t, err)
+		body, _ := io.ReadAll(res.Body)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "hello", string(body))
+	})
+}
+
 func TestContext_RealIP(t *testing.T) {
 	tests := []struct {
 		c Context
@@ -1121,3 +1583,386 @@ func TestContext_RealIP(t *testing.T) {
 		assert.Equal(t, tt.s, tt.c.RealIP())
 	}
 }
+
+func TestContext_RealIPAddr(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		givenExtractor IPExtractor
+		whenRemoteAddr string
+		whenHeader     http.Header
+		expectAddr     string
+		expectErr      string
+	}{
+		{
+			name:           "ok, remote address",
+			whenRemoteAddr: "89.89.89.89:1654",
+			expectAddr:     "89.89.89.89",
+		},
+		{
+			name:           "ok, zoned remote address",
+			whenRemoteAddr: "[fe80::1%eth0]:1654",
+			expectAddr:     "fe80::1%eth0",
+		},
+		{
+			name:           "ok, mapped remote address",
+			whenRemoteAddr: "[::ffff:1.2.3.4]:1654",
+			expectAddr:     "1.2.3.4",
+		},
+		{
+			name:           "ok, first XFF entry with port",
+			whenRemoteAddr: "89.89.89.89:1654",
+			whenHeader:     http.Header{HeaderXForwardedFor: []string{"[2001:db8::1]:443, 10.0.0.1"}},
+			expectAddr:     "2001:db8::1",
+		},
+		{
+			name:           "ok, single XFF entry with port",
+			whenRemoteAddr: "89.89.89.89:1654",
+			whenHeader:     http.Header{HeaderXForwardedFor: []string{"1.2.3.4:443"}},
+			expectAddr:     "1.2.3.4",
+		},
+		{
+			name:           "ok, malformed XFF falls back to X-Real-IP",
+			whenRemoteAddr: "89.89.89.89:1654",
+			whenHeader: http.Header{
+				HeaderXForwardedFor: []string{"unknown, 10.0.0.1"},
+				"X-Real-Ip":         []string{"::ffff:1.2.3.4"},
+			},
+			expectAddr: "1.2.3.4",
+		},
+		{
+			name:           "ok, malformed X-Real-IP falls back to remote address",
+			whenRemoteAddr: "89.89.89.89:1654",
+			whenHeader:     http.Header{"X-Real-Ip": []string{"[2001:db8::1"}},
+			expectAddr:     "89.89.89.89",
+		},
+		{
+			name:           "ok, IP extractor",
+			givenExtractor: ExtractIPFromRealIPHeader(),
+			whenRemoteAddr: "127.0.0.1:1654",
+			whenHeader:     http.Header{"X-Real-Ip": []string{"[2001:db8::1]:443"}},
+			expectAddr:     "2001:db8::1",
+		},
+		{
+			name:           "nok, malformed remote address",
+			whenRemoteAddr: "pipe",
+			expectErr:      `echo: invalid IP address: "pipe"`,
+		},
+		{
+			name:           "nok, IP extractor returns invalid address",
+			givenExtractor: func(r *http.Request) string { return "unknown" },
+			whenRemoteAddr: "89.89.89.89:1654",
+			expectErr:      `echo: invalid IP address: "unknown"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.IPExtractor = tc.givenExtractor
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tc.whenRemoteAddr
+			for k, v := range tc.whenHeader {
+				req.Header[k] = v
+			}
+			c := e.NewContext(req, nil)
+
+			addr, err := c.RealIPAddr()
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				assert.Equal(t, "", c.RealIP())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectAddr, addr.String())
+			assert.Equal(t, tc.expectAddr, c.RealIP())
+		})
+	}
+}
+
+func TestContext_ParamSegments(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		givenEncoded   bool
+		whenURL        string
+		expectSegments []string
+	}{
+		{
+			name:           "ok, segments",
+			whenURL:        "/files/a/b/c.txt",
+			expectSegments: []string{"a", "b", "c.txt"},
+		},
+		{
+			name:           "ok, encoded slash stays in segment",
+			whenURL:        "/files/a%2Fb/c",
+			expectSegments: []string{"a/b", "c"},
+		},
+		{
+			name:           "ok, encoded slash stays in segment with UseEncodedPath",
+			givenEncoded:   true,
+			whenURL:        "/files/a%2Fb/c",
+			expectSegments: []string{"a/b", "c"},
+		},
+		{
+			name:           "ok, segments are decoded",
+			whenURL:        "/files/caf%C3%A9/a%20b",
+			expectSegments: []string{"café", "a b"},
+		},
+		{
+			name:           "ok, empty segments of double slash",
+			whenURL:        "/files/a//b",
+			expectSegments: []string{"a", "", "b"},
+		},
+		{
+			name:           "ok, trailing slash",
+			whenURL:        "/files/a/b/",
+			expectSegments: []string{"a", "b", ""},
+		},
+		{
+			name:           "ok, empty wildcard",
+			whenURL:        "/files/",
+			expectSegments: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.UseEncodedPath = tc.givenEncoded
+			var segments []string
+			e.GET("/files/*", func(c Context) error {
+				segments = c.ParamSegments("*")
+				return nil
+			})
+
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tc.whenURL, nil))
+
+			assert.Equal(t, tc.expectSegments, segments)
+		})
+	}
+}
+
+func TestContext_RawParam(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		whenURL       string
+		expectParam   string
+		expectRawPath string
+	}{
+		{
+			name:          "ok, encoded slash is kept",
+			whenURL:       "/files/a%2Fb/meta",
+			expectParam:   "a%2Fb",
+			expectRawPath: "a%2Fb",
+		},
+		{
+			name:          "ok, default encoding is restored",
+			whenURL:       "/files/a%20b/meta",
+			expectParam:   "a b",
+			expectRawPath: "a%20b",
+		},
+		{
+			name:          "ok, plain value",
+			whenURL:       "/files/ab/meta",
+			expectParam:   "ab",
+			expectRawPath: "ab",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			var param, rawParam string
+			e.GET("/files/:name/meta", func(c Context) error {
+				param = c.Param("name")
+				rawParam = c.RawParam("name")
+				return nil
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			e.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tc.expectParam, param)
+			assert.Equal(t, tc.expectRawPath, rawParam)
+		})
+	}
+}
+
+func TestContext_RouteName(t *testing.T) {
+	e := New()
+	var preName string
+	e.Pre(func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			preName = c.RouteName()
+			return next(c)
+		}
+	})
+	handler := func(c Context) error {
+		return c.String(http.StatusOK, c.RouteName())
+	}
+	e.GET("/users/:id", handler).Name = "user"
+	e.Host("api.example.com").GET("/users/:id", handler).Name = "api-user"
+
+	var testCases = []struct {
+		name       string
+		whenMethod string
+		whenHost   string
+		whenURL    string
+		expectCode int
+		expectName string
+	}{
+		{
+			name:       "ok",
+			whenMethod: http.MethodGet,
+			whenURL:    "/users/1",
+			expectCode: http.StatusOK,
+			expectName: "user",
+		},
+		{
+			name:       "ok, host router",
+			whenMethod: http.MethodGet,
+			whenHost:   "api.example.com",
+			whenURL:    "/users/1",
+			expectCode: http.StatusOK,
+			expectName: "api-user",
+		},
+		{
+			name:       "nok, no route",
+			whenMethod: http.MethodGet,
+			whenURL:    "/nope",
+			expectCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.whenMethod, tc.whenURL, nil)
+			if tc.whenHost != "" {
+				req.Host = tc.whenHost
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			assert.Equal(t, "", preName)
+			if tc.expectCode == http.StatusOK {
+				assert.Equal(t, tc.expectName, rec.Body.String())
+			}
+		})
+	}
+
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	assert.Equal(t, "", c.RouteName())
+}
+
+func TestContext_Error_handledOnce(t *testing.T) {
+	e := New()
+	logs := new(bytes.Buffer)
+	e.Logger.SetOutput(logs)
+	e.Logger.SetLevel(log.WARN)
+	errFirst := errors.New("first")
+	errSecond := NewHTTPError(http.StatusConflict, "second")
+
+	var suppressed []error
+	e.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			err := next(c)
+			c.Error(err) // same error again is no-op
+			suppressed = c.SuppressedErrors()
+			return err // returned error is handled by ServeHTTP with c.Error as well
+		}
+	})
+	e.GET("/", func(c Context) error {
+		c.Error(errFirst)
+		c.Error(errSecond)
+		return errFirst
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, []error{errSecond}, suppressed)
+	assert.Equal(t, 1, strings.Count(logs.String(), "already handled"))
+	assert.Contains(t, logs.String(), "echo: error of request GET / is already handled, suppressed error: code=409, message=second")
+}
+
+func TestContext_Error_committedResponse(t *testing.T) {
+	e := New()
+	errAfterWrite := errors.New("after write")
+
+	var c Context
+	e.GET("/", func(ctx Context) error {
+		c = ctx
+		_ = ctx.String(http.StatusOK, "OK")
+		return errAfterWrite
+	})
+	e.GuardReleasedResponse = true // keeps context out of the pool so it can be inspected after the request
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "OK", rec.Body.String())
+	assert.Equal(t, []error{errAfterWrite}, c.SuppressedErrors())
+}
+
+func TestContext_Error_concurrent(t *testing.T) {
+	// simulates handler goroutine and timeout path racing to handle error of the same request
+	for i := 0; i < 50; i++ {
+		e := New()
+		e.Logger.SetOutput(io.Discard)
+		errHandler := errors.New("handler")
+
+		calls := 0
+		var callsLock sync.Mutex
+		e.HTTPErrorHandler = func(err error, c Context) {
+			callsLock.Lock()
+			calls++
+			callsLock.Unlock()
+			e.DefaultHTTPErrorHandler(err, c)
+		}
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			<-start
+			c.Error(errHandler)
+		}()
+		go func() {
+			defer wg.Done()
+			<-start
+			c.Error(ErrServiceUnavailable)
+		}()
+		close(start)
+		wg.Wait()
+
+		assert.Equal(t, 1, calls)
+		assert.Len(t, c.SuppressedErrors(), 1)
+		if rec.Code == http.StatusServiceUnavailable {
+			assert.Equal(t, []error{errHandler}, c.SuppressedErrors())
+		} else {
+			assert.Equal(t, http.StatusInternalServerError, rec.Code)
+			assert.Equal(t, []error{ErrServiceUnavailable}, c.SuppressedErrors())
+		}
+	}
+}
+
+func TestContext_Error_resetClearsState(t *testing.T) {
+	e := New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder()).(*context)
+	c.Error(errors.New("first"))
+	c.suppressError(errors.New("suppressed"))
+
+	rec := httptest.NewRecorder()
+	c.Reset(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	assert.Empty(t, c.SuppressedErrors())
+
+	c.Error(ErrNotFound)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
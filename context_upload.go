@@ -0,0 +1,254 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	stdContext "context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUploadTooLarge is returned when uploaded file is bigger than the limit set with `SaveMaxSize`.
+var ErrUploadTooLarge = errors.New("echo: uploaded file is too large")
+
+// ErrInvalidUploadFilename is returned when original name of the uploaded file can not be used as file name.
+var ErrInvalidUploadFilename = errors.New("echo: invalid uploaded file name")
+
+// FilePutter stores uploaded files into storage other than local filesystem (ala S3 bucket). Put must consume the
+// reader until io.EOF and returns error when any of the reads fails, so partially written object can be discarded.
+type FilePutter interface {
+	Put(ctx stdContext.Context, name string, r io.Reader) error
+}
+
+// SaveResult describes saved uploaded file.
+type SaveResult struct {
+	// Name is path of the saved file for `Context#SaveUploadedFile` and object name for `Context#PutUploadedFile`.
+	Name string
+	// Size is number of bytes written.
+	Size int64
+	// Checksum is hex encoded checksum of the written bytes. Empty when checksum is disabled.
+	Checksum string
+}
+
+// SaveOption is option for `Context#SaveUploadedFile`, `Context#SaveUploadedFileTo` and `Context#PutUploadedFile`.
+type SaveOption func(*saveOptions)
+
+type saveOptions struct {
+	newHash      func() hash.Hash
+	maxSize      int64
+	perm         fs.FileMode
+	keepFilename bool
+	sanitize     bool
+}
+
+// SaveChecksum sets hash function used to compute checksum of the file during the copy (default: sha256). Nil
+// disables checksum.
+func SaveChecksum(newHash func() hash.Hash) SaveOption {
+	return func(o *saveOptions) {
+		o.newHash = newHash
+	}
+}
+
+// SaveMaxSize sets maximum size of the file in bytes. Bigger files are not saved and ErrUploadTooLarge is returned
+// (default: 0, no limit).
+func SaveMaxSize(size int64) SaveOption {
+	return func(o *saveOptions) {
+		o.maxSize = size
+	}
+}
+
+// SaveFileMode sets permissions of the saved file (default: 0644).
+func SaveFileMode(perm fs.FileMode) SaveOption {
+	return func(o *saveOptions) {
+		o.perm = perm
+	}
+}
+
+// SaveOriginalFilename makes destination to be treated as directory (object name prefix for `Context#PutUploadedFile`)
+// where the file is saved with its original name. Directory part of the original name is always dropped. When
+// sanitize is true characters other than ASCII letters, digits, `.`, `-` and `_` are replaced with `_`.
+func SaveOriginalFilename(sanitize bool) SaveOption {
+	return func(o *saveOptions) {
+		o.keepFilename = true
+		o.sanitize = sanitize
+	}
+}
+
+func newSaveOptions(opts []SaveOption) saveOptions {
+	o := saveOptions{newHash: sha256.New, perm: 0o644}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// SaveUploadedFile saves uploaded file (from `Context#FormFile` or bound `*multipart.FileHeader` field) to dst
+// path. File is written into temporary file in the same directory which is synced and renamed to dst after the copy
+// has succeeded so partially written files are never left behind.
+//
+// Example:
+//
+//	fh, err := c.FormFile("avatar")
+//	if err != nil {
+//		return err
+//	}
+//	res, err := c.SaveUploadedFile(fh, "/var/uploads", echo.SaveOriginalFilename(true), echo.SaveMaxSize(10<<20))
+func (c *context) SaveUploadedFile(fh *multipart.FileHeader, dst string, opts ...SaveOption) (SaveResult, error) {
+	o := newSaveOptions(opts)
+	if o.keepFilename {
+		name, err := uploadFilename(fh.Filename, o.sanitize)
+		if err != nil {
+			return SaveResult{}, err
+		}
+		dst = filepath.Join(dst, name)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return SaveResult{}, err
+	}
+	tmpName := tmp.Name()
+	result, err := copyUploadedFile(fh, tmp, o)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpName, o.perm)
+	}
+	if err == nil {
+		err = os.Rename(tmpName, dst)
+	}
+	if err != nil {
+		_ = os.Remove(tmpName)
+		return SaveResult{}, err
+	}
+	result.Name = dst
+	return result, nil
+}
+
+// SaveUploadedFileTo copies uploaded file to the writer. Options that control file names and permissions are ignored.
+// Writer receives data that was read before the error when copy fails.
+func (c *context) SaveUploadedFileTo(fh *multipart.FileHeader, w io.Writer, opts ...SaveOption) (SaveResult, error) {
+	result, err := copyUploadedFile(fh, w, newSaveOptions(opts))
+	if err != nil {
+		return SaveResult{}, err
+	}
+	result.Name = fh.Filename
+	return result, nil
+}
+
+// PutUploadedFile stores uploaded file with given name using the putter. Reader passed to the putter returns error
+// when file is bigger than the `SaveMaxSize` limit. Checksum is computed from the bytes consumed by the putter.
+func (c *context) PutUploadedFile(fh *multipart.FileHeader, putter FilePutter, name string, opts ...SaveOption) (SaveResult, error) {
+	o := newSaveOptions(opts)
+	if o.keepFilename {
+		filename, err := uploadFilename(fh.Filename, o.sanitize)
+		if err != nil {
+			return SaveResult{}, err
+		}
+		name = strings.TrimSuffix(name, "/") + "/" + filename
+		name = strings.TrimPrefix(name, "/")
+	}
+	r, err := openUploadedFile(fh, o)
+	if err != nil {
+		return SaveResult{}, err
+	}
+	defer r.Close()
+
+	if err := putter.Put(c.request.Context(), name, r); err != nil {
+		return SaveResult{}, err
+	}
+	return SaveResult{Name: name, Size: r.read, Checksum: r.checksum()}, nil
+}
+
+func copyUploadedFile(fh *multipart.FileHeader, w io.Writer, o saveOptions) (SaveResult, error) {
+	r, err := openUploadedFile(fh, o)
+	if err != nil {
+		return SaveResult{}, err
+	}
+	defer r.Close()
+
+	n, err := io.Copy(w, r)
+	if err != nil {
+		return SaveResult{}, err
+	}
+	return SaveResult{Size: n, Checksum: r.checksum()}, nil
+}
+
+func openUploadedFile(fh *multipart.FileHeader, o saveOptions) (*uploadReader, error) {
+	if o.maxSize > 0 && fh.Size > o.maxSize {
+		return nil, ErrUploadTooLarge
+	}
+	src, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	r := &uploadReader{file: src, limit: o.maxSize}
+	if o.newHash != nil {
+		r.hash = o.newHash()
+	}
+	return r, nil
+}
+
+// uploadReader counts and hashes bytes read from the uploaded file and fails when the size limit is exceeded.
+type uploadReader struct {
+	file  multipart.File
+	hash  hash.Hash
+	limit int64
+	read  int64
+}
+
+func (r *uploadReader) Read(p []byte) (int, error) {
+	n, err := r.file.Read(p)
+	r.read += int64(n)
+	if r.limit > 0 && r.read > r.limit {
+		return 0, ErrUploadTooLarge
+	}
+	if r.hash != nil {
+		r.hash.Write(p[:n])
+	}
+	return n, err
+}
+
+func (r *uploadReader) Close() error {
+	return r.file.Close()
+}
+
+func (r *uploadReader) checksum() string {
+	if r.hash == nil {
+		return ""
+	}
+	return hex.EncodeToString(r.hash.Sum(nil))
+}
+
+// uploadFilename returns base name of the uploaded file name. Both `/` and `\` are treated as path separators as
+// clients send paths of their own OS.
+func uploadFilename(name string, sanitize bool) (string, error) {
+	if i := strings.LastIndexAny(name, `/\`); i != -1 {
+		name = name[i+1:]
+	}
+	if sanitize {
+		name = strings.Map(func(r rune) rune {
+			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '-' || r == '_' {
+				return r
+			}
+			return '_'
+		}, name)
+	}
+	if name == "" || name == "." || name == ".." || strings.ContainsRune(name, 0) {
+		return "", ErrInvalidUploadFilename
+	}
+	return name, nil
+}
@@ -0,0 +1,268 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"bytes"
+	stdContext "context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newUploadContext(t *testing.T, filename string, content string) (Context, *multipart.FileHeader) {
+	buf := new(bytes.Buffer)
+	mw := multipart.NewWriter(buf)
+	w, err := mw.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = w.Write([]byte(content))
+	mw.Close()
+	req := httptest.NewRequest(http.MethodPost, "/", buf)
+	req.Header.Set(HeaderContentType, mw.FormDataContentType())
+	c := New().NewContext(req, httptest.NewRecorder())
+	fh, err := c.FormFile("file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, fh
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestContext_SaveUploadedFile(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		givenFilename  string
+		whenDst        string
+		whenOptions    []SaveOption
+		expectName     string
+		expectChecksum string
+		expectErr      error
+	}{
+		{
+			name:           "ok, to path",
+			givenFilename:  "report.pdf",
+			whenDst:        "saved.bin",
+			expectName:     "saved.bin",
+			expectChecksum: sha256Hex("file content"),
+		},
+		{
+			name:           "ok, original filename",
+			givenFilename:  "report.pdf",
+			whenOptions:    []SaveOption{SaveOriginalFilename(false)},
+			expectName:     "report.pdf",
+			expectChecksum: sha256Hex("file content"),
+		},
+		{
+			name:           "ok, original filename without directories",
+			givenFilename:  `..\..\windows\evil.exe`,
+			whenOptions:    []SaveOption{SaveOriginalFilename(false)},
+			expectName:     "evil.exe",
+			expectChecksum: sha256Hex("file content"),
+		},
+		{
+			name:           "ok, sanitized filename",
+			givenFilename:  "my report (final).pdf",
+			whenOptions:    []SaveOption{SaveOriginalFilename(true)},
+			expectName:     "my_report__final_.pdf",
+			expectChecksum: sha256Hex("file content"),
+		},
+		{
+			name:           "ok, md5 checksum",
+			givenFilename:  "a.txt",
+			whenDst:        "a.txt",
+			whenOptions:    []SaveOption{SaveChecksum(md5.New)},
+			expectName:     "a.txt",
+			expectChecksum: "d10b4c3ff123b26dc068d43a8bef2d23",
+		},
+		{
+			name:          "ok, checksum disabled",
+			givenFilename: "a.txt",
+			whenDst:       "a.txt",
+			whenOptions:   []SaveOption{SaveChecksum(nil)},
+			expectName:    "a.txt",
+		},
+		{
+			name:          "nok, too large",
+			givenFilename: "a.txt",
+			whenDst:       "a.txt",
+			whenOptions:   []SaveOption{SaveMaxSize(5)},
+			expectErr:     ErrUploadTooLarge,
+		},
+		{
+			name:          "nok, invalid original filename",
+			givenFilename: "uploads/..",
+			whenOptions:   []SaveOption{SaveOriginalFilename(false)},
+			expectErr:     ErrInvalidUploadFilename,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			c, fh := newUploadContext(t, tc.givenFilename, "file content")
+
+			result, err := c.SaveUploadedFile(fh, filepath.Join(dir, tc.whenDst), tc.whenOptions...)
+
+			entries, _ := os.ReadDir(dir)
+			if tc.expectErr != nil {
+				assert.ErrorIs(t, err, tc.expectErr)
+				assert.Empty(t, entries)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, filepath.Join(dir, tc.expectName), result.Name)
+			assert.Equal(t, int64(12), result.Size)
+			assert.Equal(t, tc.expectChecksum, result.Checksum)
+			if assert.Len(t, entries, 1) {
+				assert.Equal(t, tc.expectName, entries[0].Name())
+			}
+			data, err := os.ReadFile(result.Name)
+			assert.NoError(t, err)
+			assert.Equal(t, "file content", string(data))
+		})
+	}
+}
+
+func TestContext_SaveUploadedFile_fileMode(t *testing.T) {
+	dst := filepath.Join(t.TempDir(), "a.txt")
+	c, fh := newUploadContext(t, "a.txt", "file content")
+
+	_, err := c.SaveUploadedFile(fh, dst, SaveFileMode(0o600))
+
+	assert.NoError(t, err)
+	fi, err := os.Stat(dst)
+	if assert.NoError(t, err) && os.PathSeparator == '/' {
+		assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+	}
+}
+
+func TestContext_SaveUploadedFile_sizeLimitDuringCopy(t *testing.T) {
+	dir := t.TempDir()
+	c, fh := newUploadContext(t, "a.txt", "file content")
+	fh.Size = 1 // lying header, actual content is bigger
+
+	_, err := c.SaveUploadedFile(fh, filepath.Join(dir, "a.txt"), SaveMaxSize(5))
+
+	assert.ErrorIs(t, err, ErrUploadTooLarge)
+	entries, _ := os.ReadDir(dir)
+	assert.Empty(t, entries, "temporary file must be removed")
+}
+
+func TestContext_SaveUploadedFileTo(t *testing.T) {
+	c, fh := newUploadContext(t, "a.txt", "file content")
+	buf := new(bytes.Buffer)
+
+	result, err := c.SaveUploadedFileTo(fh, buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "file content", buf.String())
+	assert.Equal(t, SaveResult{Name: "a.txt", Size: 12, Checksum: sha256Hex("file content")}, result)
+}
+
+type testFilePutter struct {
+	name    string
+	data    []byte
+	failErr error
+}
+
+func (p *testFilePutter) Put(ctx stdContext.Context, name string, r io.Reader) error {
+	if p.failErr != nil {
+		return p.failErr
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	p.name = name
+	p.data = data
+	return nil
+}
+
+func TestContext_PutUploadedFile(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		givenPutter  *testFilePutter
+		whenName     string
+		whenOptions  []SaveOption
+		expectResult SaveResult
+		expectErr    error
+	}{
+		{
+			name:         "ok",
+			givenPutter:  &testFilePutter{},
+			whenName:     "uploads/1.pdf",
+			expectResult: SaveResult{Name: "uploads/1.pdf", Size: 12, Checksum: sha256Hex("file content")},
+		},
+		{
+			name:         "ok, original filename under prefix",
+			givenPutter:  &testFilePutter{},
+			whenName:     "uploads/",
+			whenOptions:  []SaveOption{SaveOriginalFilename(true)},
+			expectResult: SaveResult{Name: "uploads/my_report.pdf", Size: 12, Checksum: sha256Hex("file content")},
+		},
+		{
+			name:        "nok, too large",
+			givenPutter: &testFilePutter{},
+			whenName:    "uploads/1.pdf",
+			whenOptions: []SaveOption{SaveMaxSize(5)},
+			expectErr:   ErrUploadTooLarge,
+		},
+		{
+			name:        "nok, putter error",
+			givenPutter: &testFilePutter{failErr: errors.New("bucket not found")},
+			whenName:    "uploads/1.pdf",
+			expectErr:   errors.New("bucket not found"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, fh := newUploadContext(t, "my report.pdf", "file content")
+
+			result, err := c.PutUploadedFile(fh, tc.givenPutter, tc.whenName, tc.whenOptions...)
+
+			if tc.expectErr != nil {
+				assert.EqualError(t, err, tc.expectErr.Error())
+				assert.Nil(t, tc.givenPutter.data)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectResult, result)
+			assert.Equal(t, tc.expectResult.Name, tc.givenPutter.name)
+			assert.Equal(t, "file content", string(tc.givenPutter.data))
+		})
+	}
+}
+
+func TestContext_SaveUploadedFile_boundField(t *testing.T) {
+	type form struct {
+		File *multipart.FileHeader `form:"file"`
+	}
+	c, _ := newUploadContext(t, "a.txt", "file content")
+	var f form
+	assert.NoError(t, c.Bind(&f))
+
+	buf := new(bytes.Buffer)
+	result, err := c.SaveUploadedFileTo(f.File, buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), result.Size)
+	assert.Equal(t, "file content", buf.String())
+}
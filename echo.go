@@ -51,7 +51,9 @@ import (
 	"os"
 	"reflect"
 	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/labstack/gommon/color"
@@ -84,6 +86,20 @@ type Echo struct {
 	routers       map[string]*Router
 	pool          sync.Pool
 
+	// longLivedConns holds cancel functions of long-lived connections (websockets, SSE) that are notified on Shutdown.
+	longLivedConns longLivedConns
+	// maintenance holds maintenance mode settings. Nil when maintenance mode is disabled.
+	maintenance atomic.Pointer[maintenanceMode]
+	// deferredPool limits number of goroutines running functions registered with `Context#Defer`.
+	deferredPool     chan struct{}
+	deferredPoolOnce sync.Once
+	// tasks is background task runner created by `Echo#Tasks`.
+	tasks     atomic.Pointer[TaskRunner]
+	tasksOnce sync.Once
+	// shutdownFuncs are functions registered with `Echo#OnShutdown`.
+	shutdownFuncs   []func(ctx stdContext.Context) error
+	shutdownFuncsMu sync.Mutex
+
 	StdLogger        *stdLog.Logger
 	Server           *http.Server
 	TLSServer        *http.Server
@@ -99,15 +115,139 @@ type Echo struct {
 	IPExtractor      IPExtractor
 	ListenerNetwork  string
 
+	// MaxMultipartMemory is maximum number of bytes of multipart form parts that are kept in memory while parsing
+	// multipart forms. The remainder is stored on disk in temporary files. Routes can override it with
+	// `RouteMaxMultipartMemory` option. Default value is 32MB.
+	MaxMultipartMemory int64
+
+	// MaxHeaderBytes, ReadHeaderTimeout, ReadTimeout, WriteTimeout and IdleTimeout are copied to the same fields of
+	// the server (`Echo#Server`, `Echo#TLSServer` or server given to `Echo#StartServer`) when it is started.
+	// Non-zero Echo field takes precedence over value set directly on the server, zero value keeps the server value
+	// (so `e.Server.ReadTimeout = ...` still works). Negative values are rejected by `Echo#Validate` and server start.
+	// See `http.Server` for meaning of the fields.
+	MaxHeaderBytes    int
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+
+	// MaxBodyDrain is maximum number of unread request body bytes that are read and discarded after the handler chain
+	// has returned (before returned error is handled) so HTTP/1.x keep-alive connection can be reused by the client
+	// (ala when handler rejects request without reading its body). When the remaining body is bigger the response gets
+	// `Connection: close` header (unless the response is already committed). Default value 0 means 256KB, negative
+	// value disables draining. Only requests served by `http.Server` are drained, full duplex responses (see
+	// `Response#EnableFullDuplex`) are not.
+	MaxBodyDrain int64
+
+	// BodyReadTimeout is maximum duration of reading the request body by `Context#Bind`, `Context#BindBody`,
+	// `Context#BindForm`, `Context#FormParams`, `Context#FormFile` and `Context#MultipartForm`. Unlike
+	// `http.Server#ReadTimeout` it starts when the handler starts to read the body, so ReadTimeout can be kept generous
+	// for uploads while clients trickling the body are cut off. Timeouts result "408 - Request Timeout" error.
+	// Routes can override it with `RouteBodyReadTimeout` option. Default value 0 means no timeout.
+	BodyReadTimeout time.Duration
+
+	// GuardReleasedResponse makes writes to the response after the request has been completed (ala by goroutine that
+	// leaked echo.Context) fail with ErrResponseReleased instead of silently writing into response of other request
+	// that reuses the pooled context. When Debug is also on writes panic with the route of the request that leaked the
+	// context. Contexts are not reused while the guard is on, so every request allocates new context.
+	GuardReleasedResponse bool
+
 	// OnAddRouteHandler is called when Echo adds new route to specific host router.
 	OnAddRouteHandler func(host string, route Route, handler HandlerFunc, middleware []MiddlewareFunc)
 	DisableHTTP2      bool
 	Debug             bool
 	HideBanner        bool
 	HidePort          bool
+
+	// LongLivedConnShutdownTimeout is maximum duration Shutdown waits for long-lived connections registered with
+	// `RegisterLongLivedConn` to deregister after they have been notified. Shutdown context deadline applies as well.
+	// Default value 0 means waiting is limited only by Shutdown context.
+	LongLivedConnShutdownTimeout time.Duration
+
+	// UseEncodedPath makes the Router match routes against escaped request path (`URL#EscapedPath()`). In this mode
+	// an encoded slash (`%2F`) stays inside a single path parameter and path parameter values are delivered decoded
+	// by `Context#Param()`. Use `Context#RawParam()` to get value in its original encoded form.
+	// Static route segments containing characters that need escaping must be registered in their escaped form.
+	UseEncodedPath bool
+
+	// MatrixParams makes the Router strip matrix parameters (`/users;role=admin/42`) from each path segment before
+	// the route is matched. Stripped parameters are available with `Context#MatrixParam()` and bound to struct fields
+	// with `matrix` tag by `DefaultBinder`. Encoded semicolons (`%3B`) are not treated as separators. Request URL
+	// is not modified.
+	MatrixParams bool
+
+	// QueryParseMode defines how the query string of request URL is parsed by `Context#QueryParams`,
+	// `Context#QueryParam`, `Context#QueryParamIter` and everything built on them (binder, extractors). Default value
+	// QueryParseDefault parses it the same way as `url.ParseQuery` does.
+	QueryParseMode QueryParseMode
+
+	// DeferredWorkers is maximum number of goroutines running functions registered with `Context#Defer`. Default value
+	// 0 runs them on the request goroutine after the response has been written.
+	DeferredWorkers int
+
+	// DeferredCancelWithRequest makes context passed to functions registered with `Context#Defer` to be cancelled
+	// together with the request context. By default, the context is detached from request cancellation.
+	DeferredCancelWithRequest bool
+
+	// TaskWorkers is number of goroutines running tasks submitted with `Echo#Tasks`. Default value 0 means
+	// `runtime.GOMAXPROCS(0)`.
+	TaskWorkers int
+
+	// TaskQueueSize is maximum number of tasks waiting for a worker. Default value 0 means 100.
+	TaskQueueSize int
+
+	// TaskQueueFullPolicy defines what happens with tasks submitted when queue is full. Default value blocks Submit.
+	TaskQueueFullPolicy TaskQueueFullPolicy
+
+	// TaskShutdownTimeout is maximum duration Shutdown waits for queued and running tasks to finish. Shutdown context
+	// deadline applies as well. Default value 0 means waiting is limited only by Shutdown context.
+	TaskShutdownTimeout time.Duration
+
+	// DefaultRouteSLO is latency budget for routes without `RouteSLO` route option. Default value 0 means that only
+	// routes with `RouteSLO` are checked.
+	DefaultRouteSLO time.Duration
+
+	// OnSLOExceeded is called after the response has been written when request took longer than latency budget of
+	// the route. Default value is DefaultSLOExceededHandler.
+	OnSLOExceeded func(c Context, budget, latency time.Duration)
+
+	// CookieSigningKeys are keys of `Context#SignedCookie` and `Context#ReadSignedCookie`. First key signs new cookies
+	// and all keys are used to verify them, so keys can be rotated by putting new key first and removing the old one
+	// once cookies signed with it have expired. Keys should be at least 32 random bytes.
+	CookieSigningKeys [][]byte
+
+	// MIMETypes overrides content types of served files by extension (with leading dot, ala `.foo`). Keys are matched
+	// case-insensitively when they are lower case. Overrides are consulted before the built-in table of common web types
+	// and the system MIME database (see `ContentTypeByExtension`). Text types get `charset=utf-8` when they have no
+	// charset parameter.
+	MIMETypes map[string]string
+
+	// EnableSDNotify makes Echo report its state to systemd (services with `Type=notify`) when NOTIFY_SOCKET
+	// environment variable is set: READY=1 when server is listening, WATCHDOG=1 at half of WATCHDOG_USEC interval
+	// and STOPPING=1 when Shutdown starts. Failures to reach the socket are logged once and do not affect serving.
+	EnableSDNotify bool
+
+	// hasRouteSLO is set when any route has been registered with `RouteSLO` option
+	hasRouteSLO bool
+	// hasRouteBodyReadTimeout is set when any route has been registered with `RouteBodyReadTimeout` option
+	hasRouteBodyReadTimeout bool
+
+	// sdNotify holds systemd notification state of `EnableSDNotify`
+	sdNotify sdNotifier
+
+	// stats are request and context pool counters. Nil until debug endpoints are mounted with `Echo#MountDebug`.
+	stats *serverStats
+
+	// routeMiddlewares holds group and route level middlewares of routes for `MiddlewareChain`
+	routeMiddlewares map[*Route][]MiddlewareInfo
+
+	// lintState holds configuration recorded for `Echo#Lint` (group prefixes, static roots and runtime counters)
+	lintState lintState
 }
 
-// Route contains a handler and information for matching against requests.
+// Route contains a handler and information for matching against requests. Settings of route options and
+// documentation of routes added to the router are available through methods (ala `Route#BodyLimit`) and are
+// included in JSON of the route.
 type Route struct {
 	Method string `json:"method"`
 	Path   string `json:"path"`
@@ -178,13 +318,16 @@ const (
 	MIMETextXML                          = "text/xml"
 	MIMETextXMLCharsetUTF8               = MIMETextXML + "; " + charsetUTF8
 	MIMEApplicationForm                  = "application/x-www-form-urlencoded"
+	MIMEApplicationNDJSON                = "application/x-ndjson"
 	MIMEApplicationProtobuf              = "application/protobuf"
 	MIMEApplicationMsgpack               = "application/msgpack"
+	MIMEApplicationGob                   = "application/x-gob"
 	MIMETextHTML                         = "text/html"
 	MIMETextHTMLCharsetUTF8              = MIMETextHTML + "; " + charsetUTF8
 	MIMETextPlain                        = "text/plain"
 	MIMETextPlainCharsetUTF8             = MIMETextPlain + "; " + charsetUTF8
 	MIMEMultipartForm                    = "multipart/form-data"
+	MIMEMultipartMixedReplace            = "multipart/x-mixed-replace"
 	MIMEOctetStream                      = "application/octet-stream"
 )
 
@@ -213,9 +356,19 @@ const (
 	HeaderContentLength       = "Content-Length"
 	HeaderContentType         = "Content-Type"
 	HeaderCookie              = "Cookie"
+	HeaderDeprecation         = "Deprecation"
+	HeaderETag                = "ETag"
+	HeaderExpect              = "Expect"
 	HeaderSetCookie           = "Set-Cookie"
+	HeaderSunset              = "Sunset"
 	HeaderIfModifiedSince     = "If-Modified-Since"
+	HeaderIfNoneMatch         = "If-None-Match"
+	HeaderIfMatch             = "If-Match"
+	HeaderIfUnmodifiedSince   = "If-Unmodified-Since"
+	HeaderIfRange             = "If-Range"
+	HeaderRange               = "Range"
 	HeaderLastModified        = "Last-Modified"
+	HeaderLink                = "Link"
 	HeaderLocation            = "Location"
 	HeaderRetryAfter          = "Retry-After"
 	HeaderUpgrade             = "Upgrade"
@@ -229,6 +382,7 @@ const (
 	HeaderXHTTPMethodOverride = "X-HTTP-Method-Override"
 	HeaderXRealIP             = "X-Real-Ip"
 	HeaderXRequestID          = "X-Request-Id"
+	HeaderXTotalCount         = "X-Total-Count"
 	HeaderXCorrelationID      = "X-Correlation-Id"
 	HeaderXRequestedWith      = "X-Requested-With"
 	HeaderServer              = "Server"
@@ -333,6 +487,7 @@ var (
 
 	ErrValidatorNotRegistered = errors.New("validator not registered")
 	ErrRendererNotRegistered  = errors.New("renderer not registered")
+	ErrBinderNotSingleSource  = errors.New("binder does not support single source binding")
 	ErrInvalidRedirectCode    = errors.New("invalid redirect status code")
 	ErrCookieNotFound         = errors.New("cookie not found")
 	ErrInvalidCertOrKeyType   = errors.New("invalid cert or key type, must be string or []byte")
@@ -370,10 +525,12 @@ func New() (e *Echo) {
 		colorer:         color.New(),
 		maxParam:        new(int),
 		ListenerNetwork: "tcp",
+		MIMETypes:       map[string]string{},
 	}
 	e.Server.Handler = e
 	e.TLSServer.Handler = e
 	e.HTTPErrorHandler = e.DefaultHTTPErrorHandler
+	e.OnSLOExceeded = DefaultSLOExceededHandler
 	e.Binder = &DefaultBinder{}
 	e.JSONSerializer = &DefaultJSONSerializer{}
 	e.Logger.SetLevel(log.ERROR)
@@ -414,10 +571,12 @@ func (e *Echo) Routers() map[string]*Router {
 // NOTE: In case errors happens in middleware call-chain that is returning from handler (which did not return an error).
 // When handler has already sent response (ala c.JSON()) and there is error in middleware that is returning from
 // handler. Then the error that global error handler received will be ignored because we have already "committed" the
-// response and status code header has been sent to the client.
+// response and status code header has been sent to the client. Ignored error is recorded in `Context#SuppressedErrors`.
 func (e *Echo) DefaultHTTPErrorHandler(err error, c Context) {
-
 	if c.Response().Committed {
+		if ctx, ok := unwrapContext(c); ok && err != nil {
+			ctx.suppressError(err)
+		}
 		return
 	}
 
@@ -451,6 +610,9 @@ func (e *Echo) DefaultHTTPErrorHandler(err error, c Context) {
 	case error:
 		message = Map{"message": m.Error()}
 	}
+	if m, ok := message.(Map); ok {
+		e.addErrorHints(c, code, m)
+	}
 
 	// Send response
 	if c.Request().Method == http.MethodHead { // Issue #608
@@ -463,16 +625,57 @@ func (e *Echo) DefaultHTTPErrorHandler(err error, c Context) {
 	}
 }
 
-// Pre adds middleware to the chain which is run before router.
+// addErrorHints adds machine-readable hints to the error response body: `allowed_methods` for 405 responses and, in
+// debug mode, `suggestions` of similar routes for requests that did not match any route.
+func (e *Echo) addErrorHints(c Context, code int, body Map) {
+	switch code {
+	case http.StatusMethodNotAllowed:
+		if allow := c.Response().Header().Get(HeaderAllow); allow != "" {
+			body["allowed_methods"] = strings.Split(allow, ", ")
+		}
+	case http.StatusNotFound:
+		if !e.Debug {
+			return
+		}
+		if notFound, _ := c.Get(contextKeyRouteNotFound).(bool); !notFound {
+			return
+		}
+		req := c.Request()
+		if suggestions := e.findRouter(req.Host).suggestRoutes(req.Method, req.URL.Path); len(suggestions) > 0 {
+			body["suggestions"] = suggestions
+		}
+	}
+}
+
+// Pre adds middleware to the chain which is run before router. Panics when middleware is marked with
+// `RequireRoute` or is route option (see `RouteOption`).
 func (e *Echo) Pre(middleware ...MiddlewareFunc) {
+	for _, m := range middleware {
+		if requiresRoute(m) {
+			panic(fmt.Errorf("echo: middleware %s requires matched route and can not be added with Echo#Pre", middlewareName(m)))
+		}
+	}
+	panicOnRouteOptions("Echo#Pre", middleware)
 	e.premiddleware = append(e.premiddleware, middleware...)
 }
 
-// Use adds middleware to the chain which is run after router.
+// Use adds middleware to the chain which is run after router. Panics when middleware is route option (see
+// `RouteOption`).
 func (e *Echo) Use(middleware ...MiddlewareFunc) {
+	panicOnRouteOptions("Echo#Use", middleware)
 	e.middleware = append(e.middleware, middleware...)
 }
 
+// panicOnRouteOptions panics when middlewares contain route option. Route options are applied when route is added,
+// global middlewares are not known at that time.
+func panicOnRouteOptions(method string, middlewares []MiddlewareFunc) {
+	for _, m := range middlewares {
+		if routeOptionOf(m) != nil {
+			panic(fmt.Errorf("echo: route option can not be added with %s, add it to the route or group", method))
+		}
+	}
+}
+
 // CONNECT registers a new CONNECT route for a path with matching handler in the
 // router with optional route-level middleware.
 func (e *Echo) CONNECT(path string, h HandlerFunc, m ...MiddlewareFunc) *Route {
@@ -573,13 +776,45 @@ func (e *Echo) File(path, file string, m ...MiddlewareFunc) *Route {
 }
 
 func (e *Echo) add(host, method, path string, handler HandlerFunc, middlewares ...MiddlewareFunc) *Route {
+	return e.addWithGroup(host, method, path, handler, nil, nil, middlewares)
+}
+
+// addWithGroup registers route with route options and group and route level middlewares. Levels are kept apart only
+// for `Echo#MiddlewareChain`, otherwise they are applied as single list.
+func (e *Echo) addWithGroup(host, method, path string, handler HandlerFunc, opts []RouteOption, groupMiddlewares, routeMiddlewares []MiddlewareFunc) *Route {
+	opts, groupMiddlewares = splitRouteOptions(opts, groupMiddlewares)
+	opts, routeMiddlewares = splitRouteOptions(opts, routeMiddlewares)
+	// Combine into a new slice to avoid accidentally passing the same slice for
+	// multiple routes, which would lead to later add() calls overwriting the
+	// middleware from earlier calls.
+	middlewares := make([]MiddlewareFunc, 0, len(groupMiddlewares)+len(routeMiddlewares))
+	middlewares = append(middlewares, groupMiddlewares...)
+	middlewares = append(middlewares, routeMiddlewares...)
+
+	if err := checkHandlerParams(handler, path); err != nil {
+		panic(fmt.Errorf("echo: invalid route %s %s: %w", method, normalizePathSlash(path), err))
+	}
 	router := e.findRouter(host)
 	//FIXME: when handler+middleware are both nil ... make it behave like handler removal
 	name := handlerName(handler)
-	route := router.add(method, path, name, func(c Context) error {
+	options := newRouteOptions(opts)
+	if options != nil && options.slo > 0 {
+		e.hasRouteSLO = true
+	}
+	if options != nil && options.bodyReadTimeout > 0 {
+		e.hasRouteBodyReadTimeout = true
+	}
+	if len(groupMiddlewares) > 0 && method != http.MethodOptions && method != RouteNotFound {
+		if options == nil {
+			options = new(routeOptions)
+		}
+		options.groupMiddlewares = groupMiddlewares
+	}
+	route := router.addWithOptions(method, path, name, func(c Context) error {
 		h := applyMiddleware(handler, middlewares...)
 		return h(c)
-	})
+	}, options)
+	e.recordRouteMiddlewares(route, groupMiddlewares, routeMiddlewares, opts)
 
 	if e.OnAddRouteHandler != nil {
 		e.OnAddRouteHandler(host, *route, handler, middlewares)
@@ -605,10 +840,18 @@ func (e *Echo) Host(name string, m ...MiddlewareFunc) (g *Group) {
 // Group creates a new router group with prefix and optional group-level middleware.
 func (e *Echo) Group(prefix string, m ...MiddlewareFunc) (g *Group) {
 	g = &Group{prefix: prefix, echo: e}
+	e.lintState.recordGroup(prefix)
 	g.Use(m...)
 	return
 }
 
+// WithRouteOptions returns group without prefix that registers routes with given route options.
+//
+// Example: `e.WithRouteOptions(echo.RouteBodyLimit("100M"), echo.RouteTimeout(time.Minute)).POST("/upload", handler)`
+func (e *Echo) WithRouteOptions(options ...RouteOption) *Group {
+	return &Group{echo: e, routeOptions: append([]RouteOption(nil), options...)}
+}
+
 // URI generates an URI from handler.
 func (e *Echo) URI(handler HandlerFunc, params ...interface{}) string {
 	name := handlerName(handler)
@@ -634,29 +877,50 @@ func (e *Echo) Routes() []*Route {
 // AcquireContext returns an empty `Context` instance from the pool.
 // You must return the context by calling `ReleaseContext()`.
 func (e *Echo) AcquireContext() Context {
+	if e.stats != nil {
+		e.stats.acquired()
+	}
 	return e.pool.Get().(Context)
 }
 
 // ReleaseContext returns the `Context` instance back to the pool.
 // You must call it after `AcquireContext()`.
 func (e *Echo) ReleaseContext(c Context) {
+	if e.stats != nil {
+		e.stats.released()
+	}
 	e.pool.Put(c)
 }
 
 // ServeHTTP implements `http.Handler` interface, which serves HTTP requests.
 func (e *Echo) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if stats := e.stats; stats != nil {
+		stats.inFlight.Add(1)
+		stats.acquired()
+		defer func() {
+			stats.released()
+			stats.inFlight.Add(-1)
+		}()
+	}
 	// Acquire context
 	c := e.pool.Get().(*context)
 	c.Reset(r, w)
+	drainBody := e.MaxBodyDrain >= 0 && r.ProtoMajor == 1 && r.Body != nil && r.Body != http.NoBody &&
+		r.ContentLength != 0 && r.Context().Value(http.ServerContextKey) != nil // only requests read by http.Server
 	var h HandlerFunc
+	var start time.Time
+	checkSLO := e.hasRouteSLO || e.DefaultRouteSLO > 0
+	if checkSLO {
+		start = time.Now()
+	}
 
 	if e.premiddleware == nil {
-		e.findRouter(r.Host).Find(r.Method, GetPath(r), c)
+		e.findRoute(r, c)
 		h = c.Handler()
 		h = applyMiddleware(h, e.middleware...)
 	} else {
 		h = func(c Context) error {
-			e.findRouter(r.Host).Find(r.Method, GetPath(r), c)
+			e.findRoute(r, c)
 			h := c.Handler()
 			h = applyMiddleware(h, e.middleware...)
 			return h(c)
@@ -665,8 +929,27 @@ func (e *Echo) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Execute chain
-	if err := h(c); err != nil {
-		e.HTTPErrorHandler(err, c)
+	err := h(c)
+	if drainBody && !c.response.fullDuplex {
+		c.drainRequestBody()
+	}
+	if err != nil {
+		c.Error(err)
+	}
+	if checkSLO {
+		e.checkSLO(c, start)
+	}
+	if c.deferred != nil {
+		e.runDeferred(c)
+	}
+	if c.cancelRoute != nil {
+		c.cancelRoute()
+	}
+
+	if e.GuardReleasedResponse {
+		// context is not reused so the leaked one stays poisoned
+		c.response.release(r.Method+" "+c.path, e.Debug)
+		return
 	}
 
 	// Release context
@@ -772,11 +1055,16 @@ func (e *Echo) StartServer(s *http.Server) (err error) {
 
 func (e *Echo) configureServer(s *http.Server) error {
 	// Setup
+	if err := e.applyServerLimits(s); err != nil {
+		return err
+	}
 	e.colorer.SetOutput(e.Logger.Output())
 	s.ErrorLog = e.StdLogger
 	s.Handler = e
+	s.ConnContext = chainConnContext(s.ConnContext)
 	if e.Debug {
 		e.Logger.SetLevel(log.DEBUG)
+		e.logLintWarnings(e.Lint())
 	}
 
 	if !e.HideBanner {
@@ -794,6 +1082,7 @@ func (e *Echo) configureServer(s *http.Server) error {
 		if !e.HidePort {
 			e.colorer.Printf("⇨ http server started on %s\n", e.colorer.Green(e.Listener.Addr()))
 		}
+		e.notifyReady()
 		return nil
 	}
 	if e.TLSListener == nil {
@@ -806,6 +1095,51 @@ func (e *Echo) configureServer(s *http.Server) error {
 	if !e.HidePort {
 		e.colorer.Printf("⇨ https server started on %s\n", e.colorer.Green(e.TLSListener.Addr()))
 	}
+	e.notifyReady()
+	return nil
+}
+
+// validateServerLimits returns error for negative request parsing limits.
+func (e *Echo) validateServerLimits() error {
+	var errs []error
+	if e.MaxHeaderBytes < 0 {
+		errs = append(errs, errors.New("echo: max header bytes must not be negative"))
+	}
+	if e.ReadHeaderTimeout < 0 {
+		errs = append(errs, errors.New("echo: read header timeout must not be negative"))
+	}
+	if e.ReadTimeout < 0 {
+		errs = append(errs, errors.New("echo: read timeout must not be negative"))
+	}
+	if e.WriteTimeout < 0 {
+		errs = append(errs, errors.New("echo: write timeout must not be negative"))
+	}
+	if e.IdleTimeout < 0 {
+		errs = append(errs, errors.New("echo: idle timeout must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// applyServerLimits copies non-zero request parsing limits of Echo to the server.
+func (e *Echo) applyServerLimits(s *http.Server) error {
+	if err := e.validateServerLimits(); err != nil {
+		return err
+	}
+	if e.MaxHeaderBytes > 0 {
+		s.MaxHeaderBytes = e.MaxHeaderBytes
+	}
+	if e.ReadHeaderTimeout > 0 {
+		s.ReadHeaderTimeout = e.ReadHeaderTimeout
+	}
+	if e.ReadTimeout > 0 {
+		s.ReadTimeout = e.ReadTimeout
+	}
+	if e.WriteTimeout > 0 {
+		s.WriteTimeout = e.WriteTimeout
+	}
+	if e.IdleTimeout > 0 {
+		s.IdleTimeout = e.IdleTimeout
+	}
 	return nil
 }
 
@@ -835,6 +1169,10 @@ func (e *Echo) StartH2CServer(address string, h2s *http2.Server) error {
 	// Setup
 	s := e.Server
 	s.Addr = address
+	if err := e.applyServerLimits(s); err != nil {
+		e.startupMutex.Unlock()
+		return err
+	}
 	e.colorer.SetOutput(e.Logger.Output())
 	s.ErrorLog = e.StdLogger
 	s.Handler = h2c.NewHandler(e, h2s)
@@ -857,6 +1195,7 @@ func (e *Echo) StartH2CServer(address string, h2s *http2.Server) error {
 	if !e.HidePort {
 		e.colorer.Printf("⇨ http server started on %s\n", e.colorer.Green(e.Listener.Addr()))
 	}
+	e.notifyReady()
 	e.startupMutex.Unlock()
 	return s.Serve(e.Listener)
 }
@@ -874,13 +1213,151 @@ func (e *Echo) Close() error {
 
 // Shutdown stops the server gracefully.
 // It internally calls `http.Server#Shutdown()`.
+//
+// Long-lived connections registered with `RegisterLongLivedConn` are notified first so handlers have a chance to
+// send close frames or final events. After servers have been shut down, Shutdown waits for these connections to
+// deregister (up to `LongLivedConnShutdownTimeout`) as `http.Server#Shutdown()` does not track hijacked connections.
+// Then, Shutdown waits for tasks submitted with `Echo#Tasks` to finish (up to `TaskShutdownTimeout`). Finally, functions
+// registered with `OnShutdown` are called. With `EnableSDNotify` systemd is notified with STOPPING=1 before anything
+// else. With `Debug` enabled, misconfigurations detected while serving (see `Echo#Lint`) are logged at the end.
+// All steps are run even when some of them fail (ala context deadline exceeded) and their errors are joined.
 func (e *Echo) Shutdown(ctx stdContext.Context) error {
 	e.startupMutex.Lock()
 	defer e.startupMutex.Unlock()
-	if err := e.TLSServer.Shutdown(ctx); err != nil {
-		return err
+	e.notifyStopping()
+	drained := e.longLivedConns.startDrain()
+	errs := []error{
+		e.TLSServer.Shutdown(ctx),
+		e.Server.Shutdown(ctx),
+		e.longLivedConns.wait(ctx, drained, e.LongLivedConnShutdownTimeout),
+	}
+	if tasks := e.tasks.Load(); tasks != nil {
+		errs = append(errs, tasks.shutdown(ctx, e.TaskShutdownTimeout))
+	}
+	errs = append(errs, e.callShutdownFuncs(ctx))
+	if e.Debug {
+		e.logLintWarnings(e.lintRuntime())
+	}
+	return errors.Join(errs...)
+}
+
+// OnShutdown registers function that is called by `Echo#Shutdown` after servers have been shut down and background
+// tasks have finished. Functions are called in registration order and all of them are called even when some fail;
+// first error is returned by Shutdown. Use it to flush buffered writers (ala async access log) and close resources.
+func (e *Echo) OnShutdown(fn func(ctx stdContext.Context) error) {
+	e.shutdownFuncsMu.Lock()
+	defer e.shutdownFuncsMu.Unlock()
+	e.shutdownFuncs = append(e.shutdownFuncs, fn)
+}
+
+func (e *Echo) callShutdownFuncs(ctx stdContext.Context) error {
+	e.shutdownFuncsMu.Lock()
+	funcs := e.shutdownFuncs
+	e.shutdownFuncs = nil
+	e.shutdownFuncsMu.Unlock()
+
+	var err error
+	for _, fn := range funcs {
+		if fErr := fn(ctx); err == nil {
+			err = fErr
+		}
+	}
+	return err
+}
+
+// RegisterLongLivedConn registers cancel function of long-lived connection (websocket, SSE stream etc.) that is called
+// when server is being shut down. Handler must call returned deregister function when connection has been closed.
+// If server is already shutting down cancel is called immediately.
+//
+// Example:
+//
+//	e.GET("/ws", func(c echo.Context) error {
+//		ctx, cancel := context.WithCancel(c.Request().Context())
+//		deregister := c.Echo().RegisterLongLivedConn(cancel)
+//		defer deregister()
+//		return serveWebsocket(ctx, c) // sends close frame when ctx is cancelled
+//	})
+func (e *Echo) RegisterLongLivedConn(cancel func()) (deregister func()) {
+	return e.longLivedConns.register(cancel)
+}
+
+type longLivedConns struct {
+	mu       sync.Mutex
+	seq      uint64
+	cancels  map[uint64]func()
+	draining bool
+	// drained is closed when all connections have deregistered during draining
+	drained chan struct{}
+}
+
+func (l *longLivedConns) register(cancel func()) func() {
+	l.mu.Lock()
+	if l.cancels == nil {
+		l.cancels = map[uint64]func(){}
+	}
+	l.seq++
+	id := l.seq
+	l.cancels[id] = cancel
+	draining := l.draining
+	l.mu.Unlock()
+
+	if draining {
+		cancel()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			delete(l.cancels, id)
+			if l.drained != nil && len(l.cancels) == 0 {
+				close(l.drained)
+				l.drained = nil
+			}
+		})
+	}
+}
+
+// startDrain notifies all registered connections and returns channel that is closed when all of them have
+// deregistered. Returned channel is nil when there is nothing to wait for.
+func (l *longLivedConns) startDrain() <-chan struct{} {
+	l.mu.Lock()
+	l.draining = true
+	cancels := make([]func(), 0, len(l.cancels))
+	for _, cancel := range l.cancels {
+		cancels = append(cancels, cancel)
+	}
+	if len(cancels) > 0 && l.drained == nil {
+		l.drained = make(chan struct{})
+	}
+	drained := l.drained
+	l.mu.Unlock()
+
+	for _, cancel := range cancels {
+		cancel()
+	}
+	return drained
+}
+
+func (l *longLivedConns) wait(ctx stdContext.Context, drained <-chan struct{}, timeout time.Duration) error {
+	if drained == nil {
+		return nil
+	}
+	var timeoutCh <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+	select {
+	case <-drained:
+		return nil
+	case <-timeoutCh:
+		return stdContext.DeadlineExceeded
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	return e.Server.Shutdown(ctx)
 }
 
 // NewHTTPError creates a new HTTPError instance.
@@ -928,16 +1405,67 @@ func WrapHandler(h http.Handler) HandlerFunc {
 	}
 }
 
+type wrappedContextKey struct{}
+
+// ContextFromRequest returns echo.Context of the request that is passed through middleware wrapped with
+// `WrapMiddleware`. Use it in standard library handlers and middlewares to access echo.Context.
+func ContextFromRequest(r *http.Request) (Context, bool) {
+	c, ok := r.Context().Value(wrappedContextKey{}).(Context)
+	return c, ok
+}
+
 // WrapMiddleware wraps `func(http.Handler) http.Handler` into `echo.MiddlewareFunc`
+//
+// Echo context is carried through the wrapped middleware in the request context (see `ContextFromRequest`).
+//   - Error returned by the next handler is returned by the wrapped middleware.
+//   - When wrapped middleware does not call next handler (ala aborts the request) the response written by it is
+//     considered final and nil is returned.
+//   - When wrapped middleware replaces the http.ResponseWriter (ala compressing writer) the next handler writes
+//     through that writer. Original Response is restored after wrapped middleware returns.
+//   - When wrapped middleware runs next handler in another goroutine (ala http.TimeoutHandler) it waits for the
+//     next handler to finish so echo.Context is not reused while still in use.
 func WrapMiddleware(m func(http.Handler) http.Handler) MiddlewareFunc {
 	return func(next HandlerFunc) HandlerFunc {
-		return func(c Context) (err error) {
-			m(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		return func(c Context) error {
+			res := c.Response()
+			req := c.Request()
+			var (
+				err      error
+				done     chan struct{}
+				returned bool
+				mu       sync.Mutex
+			)
+			h := m(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				finished := make(chan struct{})
+				mu.Lock()
+				if returned {
+					// wrapped middleware has already returned, echo.Context could be serving another request by now
+					mu.Unlock()
+					return
+				}
+				done = finished
+				mu.Unlock()
+				defer close(finished)
+
 				c.SetRequest(r)
-				c.SetResponse(NewResponse(w, c.Echo()))
+				if w != http.ResponseWriter(res) {
+					c.SetResponse(NewResponse(w, c.Echo()))
+				}
 				err = next(c)
-			})).ServeHTTP(c.Response(), c.Request())
-			return
+			}))
+			h.ServeHTTP(res, req.WithContext(stdContext.WithValue(req.Context(), wrappedContextKey{}, c)))
+
+			mu.Lock()
+			finished := done
+			returned = true
+			mu.Unlock()
+			if finished == nil {
+				// next handler was not called, wrapped middleware has written the response
+				return nil
+			}
+			<-finished
+			c.SetResponse(res)
+			return err
 		}
 	}
 }
@@ -954,6 +1482,28 @@ func GetPath(r *http.Request) string {
 	return path
 }
 
+// findRoute finds route for request and loads matched handler and path parameters into context.
+// Context can be decorated by Pre middlewares (see ContextDecorator).
+func (e *Echo) findRoute(r *http.Request, c Context) {
+	ctx, ok := unwrapContext(c)
+	if !ok {
+		panic("echo: Pre middleware must pass context created by Echo or ContextDecorator wrapping it")
+	}
+	router := e.findRouter(r.Host)
+	if e.MatrixParams && strings.IndexByte(r.URL.EscapedPath(), ';') != -1 {
+		e.findMatrixRoute(router, r, ctx)
+	} else if !e.UseEncodedPath {
+		router.Find(r.Method, GetPath(r), ctx)
+	} else {
+		router.Find(r.Method, r.URL.EscapedPath(), ctx)
+		ctx.unescapePathParams()
+	}
+	ctx.applyRouteOptions()
+	applyRouteDeprecation(router, ctx)
+	e.applyQueryParseMode(ctx)
+	e.applyMaintenanceMode(router, ctx)
+}
+
 func (e *Echo) findRouter(host string) *Router {
 	if len(e.routers) > 0 {
 		if r, ok := e.routers[host]; ok {
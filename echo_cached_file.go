@@ -0,0 +1,207 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CachedFileConfig defines the config for CachedFile handler.
+type CachedFileConfig struct {
+	// RevalidateInterval is how often modification time of the file is checked. File is reloaded when it has changed.
+	// Optional. Default value 0 means that file is loaded only once.
+	RevalidateInterval time.Duration
+
+	// CompressMinSize is minimum size of the file in bytes for which gzip compressed variant is kept in memory and
+	// served to clients accepting gzip encoding. Brotli variant is served when pre-compressed sibling file (`name.br`)
+	// exists. Negative value disables compressed variants.
+	// Optional. Default value 1024.
+	CompressMinSize int
+}
+
+// DefaultCachedFileConfig is the default CachedFile handler config.
+var DefaultCachedFileConfig = CachedFileConfig{
+	RevalidateInterval: 0,
+	CompressMinSize:    1024,
+}
+
+type cachedFile struct {
+	filesystem fs.FS
+	name       string
+	config     CachedFileConfig
+
+	mutex     sync.RWMutex
+	content   *cachedFileContent
+	lastCheck time.Time
+}
+
+type cachedFileContent struct {
+	data        []byte
+	gzip        []byte
+	br          []byte
+	modTime     time.Time
+	etag        string
+	contentType string
+}
+
+// CachedFile registers a new GET route with path to serve file from `Echo#Filesystem` from memory. File is loaded on
+// registration so missing file panics instead of failing with "404 - Not Found" at request time.
+// See `CachedFileHandler`.
+//
+// Example: `e.CachedFile("/favicon.ico", "static/favicon.ico", echo.DefaultCachedFileConfig)`
+func (e *Echo) CachedFile(path, file string, config CachedFileConfig, m ...MiddlewareFunc) *Route {
+	return e.GET(path, mustCachedFileHandler(e.Filesystem, file, config), m...)
+}
+
+// CachedFile implements `Echo#CachedFile()` for sub-routes within the Group.
+func (g *Group) CachedFile(path, file string, config CachedFileConfig, m ...MiddlewareFunc) *Route {
+	return g.GET(path, mustCachedFileHandler(g.echo.Filesystem, file, config), m...)
+}
+
+func mustCachedFileHandler(filesystem fs.FS, file string, config CachedFileConfig) HandlerFunc {
+	h, err := CachedFileHandler(filesystem, file, config)
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+// CachedFileHandler creates handler function that serves single file from memory. File is read once (and reloaded
+// when its modification time changes, see `CachedFileConfig.RevalidateInterval`). Responses have `Content-Type`,
+// strong `ETag` and `Last-Modified` headers and conditional requests (`If-None-Match`, `If-Modified-Since`) are
+// answered with "304 - Not Modified". Returns error when file can not be read.
+func CachedFileHandler(filesystem fs.FS, file string, config CachedFileConfig) (HandlerFunc, error) {
+	if config.CompressMinSize == 0 {
+		config.CompressMinSize = DefaultCachedFileConfig.CompressMinSize
+	}
+	f := &cachedFile{filesystem: filesystem, name: file, config: config}
+	content, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	f.content = content
+	f.lastCheck = time.Now()
+	return f.serve, nil
+}
+
+func (f *cachedFile) load() (*cachedFileContent, error) {
+	fi, err := fs.Stat(f.filesystem, f.name)
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		return nil, errors.New("echo: cached file can not be a directory: " + f.name)
+	}
+	data, err := fs.ReadFile(f.filesystem, f.name)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	content := &cachedFileContent{
+		data:        data,
+		modTime:     fi.ModTime(),
+		etag:        `"` + hex.EncodeToString(sum[:16]) + `"`,
+		contentType: ContentTypeByExtension(filepath.Ext(f.name)),
+	}
+	if content.contentType == "" {
+		content.contentType = http.DetectContentType(data)
+	}
+	if f.config.CompressMinSize >= 0 && len(data) >= f.config.CompressMinSize {
+		buf := new(bytes.Buffer)
+		gz := gzip.NewWriter(buf)
+		if _, err := gz.Write(data); err != nil {
+			return nil, err
+		}
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+		if buf.Len() < len(data) {
+			content.gzip = buf.Bytes()
+		}
+		if br, err := fs.ReadFile(f.filesystem, f.name+".br"); err == nil {
+			content.br = br
+		}
+	}
+	return content, nil
+}
+
+// current returns content of the file, reloading it when revalidation interval has passed and the file has changed.
+// Previous content is kept when the file can not be read.
+func (f *cachedFile) current() *cachedFileContent {
+	f.mutex.RLock()
+	content := f.content
+	revalidate := f.config.RevalidateInterval > 0 && time.Since(f.lastCheck) >= f.config.RevalidateInterval
+	f.mutex.RUnlock()
+	if !revalidate {
+		return content
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if time.Since(f.lastCheck) < f.config.RevalidateInterval {
+		return f.content // revalidated by other request meanwhile
+	}
+	f.lastCheck = time.Now()
+	if fi, err := fs.Stat(f.filesystem, f.name); err != nil || fi.ModTime().Equal(f.content.modTime) {
+		return f.content
+	}
+	if content, err := f.load(); err == nil {
+		f.content = content
+	}
+	return f.content
+}
+
+func (f *cachedFile) serve(c Context) error {
+	content := f.current()
+	req := c.Request()
+	header := c.Response().Header()
+	contentType := content.contentType
+	if ct := c.Echo().mimeTypeOverride(filepath.Ext(f.name)); ct != "" {
+		contentType = ct
+	}
+	header.Set(HeaderContentType, contentType)
+
+	data := content.data
+	etag := content.etag
+	if content.gzip != nil || content.br != nil {
+		addVaryAcceptEncoding(header)
+		acceptEncoding := req.Header.Get(HeaderAcceptEncoding)
+		encoding := ""
+		switch {
+		case content.br != nil && acceptsEncoding(acceptEncoding, "br"):
+			encoding, data = "br", content.br
+		case content.gzip != nil && acceptsEncoding(acceptEncoding, "gzip"):
+			encoding, data = "gzip", content.gzip
+		}
+		if encoding != "" {
+			// every representation has its own strong ETag
+			etag = etag[:len(etag)-1] + "-" + encoding + `"`
+			header.Set(HeaderContentEncoding, encoding)
+			// ranges over encoded bytes are confusing, so full content is always sent
+			req.Header.Del("Range")
+			req.Header.Del("If-Range")
+			// http.ServeContent does not set Content-Length for responses with Content-Encoding
+			res := c.Response()
+			size := strconv.Itoa(len(data))
+			res.Before(func() {
+				if res.Status == http.StatusOK {
+					res.Header().Set(HeaderContentLength, size)
+				}
+			})
+		}
+	}
+	header.Set(HeaderETag, etag)
+	http.ServeContent(c.Response(), req, f.name, content.modTime, bytes.NewReader(data))
+	return nil
+}
@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var cachedFileModTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func TestEcho_CachedFile(t *testing.T) {
+	e := New()
+	e.Filesystem = fstest.MapFS{
+		"robots.txt": &fstest.MapFile{Data: []byte("User-agent: *\n"), ModTime: cachedFileModTime},
+	}
+	e.CachedFile("/robots.txt", "robots.txt", DefaultCachedFileConfig)
+
+	req := httptest.NewRequest(http.MethodGet, "/robots.txt", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "User-agent: *\n", rec.Body.String())
+	assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get(HeaderContentType))
+	assert.Equal(t, "Tue, 02 Jan 2024 03:04:05 GMT", rec.Header().Get(HeaderLastModified))
+	etag := rec.Header().Get(HeaderETag)
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	assert.Empty(t, rec.Header().Get(HeaderVary)) // too small to be compressed
+
+	t.Run("If-None-Match", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/robots.txt", nil)
+		req.Header.Set(HeaderIfNoneMatch, etag)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("If-Modified-Since", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/robots.txt", nil)
+		req.Header.Set(HeaderIfModifiedSince, "Tue, 02 Jan 2024 03:04:05 GMT")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+	})
+
+	t.Run("stale If-None-Match", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/robots.txt", nil)
+		req.Header.Set(HeaderIfNoneMatch, `"other"`)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestEcho_CachedFile_missingFilePanics(t *testing.T) {
+	e := New()
+	e.Filesystem = fstest.MapFS{"dir/file.txt": &fstest.MapFile{Data: []byte("x")}}
+
+	assert.Panics(t, func() {
+		e.CachedFile("/missing.txt", "missing.txt", DefaultCachedFileConfig)
+	})
+	assert.Panics(t, func() {
+		e.CachedFile("/dir", "dir", DefaultCachedFileConfig)
+	})
+}
+
+func TestEcho_CachedFile_compressed(t *testing.T) {
+	data := []byte(strings.Repeat(`{"openapi":"3.0.0"}`, 100))
+	e := New()
+	e.Filesystem = fstest.MapFS{
+		"openapi.json":    &fstest.MapFile{Data: data, ModTime: cachedFileModTime},
+		"openapi.json.br": &fstest.MapFile{Data: []byte("brotli"), ModTime: cachedFileModTime},
+		"plain.json":      &fstest.MapFile{Data: data, ModTime: cachedFileModTime},
+	}
+	g := e.Group("/api")
+	g.CachedFile("/openapi.json", "openapi.json", DefaultCachedFileConfig)
+	g.CachedFile("/plain.json", "plain.json", CachedFileConfig{CompressMinSize: -1})
+
+	var testCases = []struct {
+		name           string
+		whenURL        string
+		whenAccept     string
+		expectEncoding string
+		expectVary     string
+	}{
+		{
+			name:           "ok, gzip",
+			whenURL:        "/api/openapi.json",
+			whenAccept:     "gzip",
+			expectEncoding: "gzip",
+			expectVary:     HeaderAcceptEncoding,
+		},
+		{
+			name:           "ok, brotli is preferred",
+			whenURL:        "/api/openapi.json",
+			whenAccept:     "gzip, br",
+			expectEncoding: "br",
+			expectVary:     HeaderAcceptEncoding,
+		},
+		{
+			name:       "ok, identity",
+			whenURL:    "/api/openapi.json",
+			expectVary: HeaderAcceptEncoding,
+		},
+		{
+			name:       "ok, compression disabled",
+			whenURL:    "/api/plain.json",
+			whenAccept: "gzip",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			req.Header.Set(HeaderAcceptEncoding, tc.whenAccept)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tc.expectEncoding, rec.Header().Get(HeaderContentEncoding))
+			assert.Equal(t, tc.expectVary, rec.Header().Get(HeaderVary))
+			assert.Equal(t, MIMEApplicationJSON, rec.Header().Get(HeaderContentType))
+
+			body := rec.Body.Bytes()
+			switch tc.expectEncoding {
+			case "gzip":
+				assert.Equal(t, rec.Header().Get(HeaderContentLength), strconv.Itoa(len(body)))
+				assert.Contains(t, rec.Header().Get(HeaderETag), `-gzip"`)
+				r, err := gzip.NewReader(bytes.NewReader(body))
+				assert.NoError(t, err)
+				body, err = io.ReadAll(r)
+				assert.NoError(t, err)
+				assert.Equal(t, data, body)
+			case "br":
+				assert.Equal(t, "brotli", string(body))
+				assert.Contains(t, rec.Header().Get(HeaderETag), `-br"`)
+			default:
+				assert.Equal(t, data, body)
+			}
+		})
+	}
+}
+
+func TestEcho_CachedFile_revalidate(t *testing.T) {
+	filesystem := fstest.MapFS{
+		"robots.txt": &fstest.MapFile{Data: []byte("v1"), ModTime: cachedFileModTime},
+	}
+	e := New()
+	e.Filesystem = filesystem
+	e.CachedFile("/robots.txt", "robots.txt", CachedFileConfig{RevalidateInterval: time.Millisecond})
+
+	get := func() string {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/robots.txt", nil))
+		return rec.Body.String()
+	}
+	assert.Equal(t, "v1", get())
+
+	// content changed without modification time change is not noticed
+	filesystem["robots.txt"] = &fstest.MapFile{Data: []byte("v2"), ModTime: cachedFileModTime}
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, "v1", get())
+
+	filesystem["robots.txt"] = &fstest.MapFile{Data: []byte("v3"), ModTime: cachedFileModTime.Add(time.Second)}
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, "v3", get())
+
+	// previous content is kept when file disappears
+	delete(filesystem, "robots.txt")
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, "v3", get())
+}
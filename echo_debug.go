@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"errors"
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// DebugConfig defines the config for debug endpoints mounted with `Echo#MountDebug`.
+type DebugConfig struct {
+	// Guard is middleware that protects debug endpoints (ala IP allow list or basic auth middleware) as they expose
+	// internals of the application.
+	// Required.
+	Guard MiddlewareFunc
+
+	// EnablePprof registers `net/http/pprof` handlers under `<prefix>/pprof/`.
+	EnablePprof bool
+}
+
+// ErrDebugAlreadyMounted is returned by `Echo#MountDebug` when debug endpoints have already been mounted.
+var ErrDebugAlreadyMounted = errors.New("echo: debug endpoints are already mounted")
+
+// serverStats are counters of requests and context pool usage collected when debug endpoints are mounted.
+type serverStats struct {
+	inFlight     atomic.Int64
+	poolGets     atomic.Int64
+	poolMisses   atomic.Int64
+	poolReleases atomic.Int64
+}
+
+func (s *serverStats) acquired() {
+	s.poolGets.Add(1)
+}
+
+func (s *serverStats) released() {
+	s.poolReleases.Add(1)
+}
+
+// MountDebug registers debug endpoints under prefix, all protected by `DebugConfig.Guard`:
+//   - `GET <prefix>/routes` routes with their effective middleware chains (see `Echo#MiddlewareChainHandler`)
+//   - `GET <prefix>/stats` in-flight requests and context pool stats (gets, hits, misses, in use)
+//   - `GET <prefix>/build` build info of the binary (see `debug.ReadBuildInfo`)
+//   - `GET <prefix>/vars` expvar variables
+//   - `<prefix>/pprof/...` pprof handlers when `DebugConfig.EnablePprof` is set
+//
+// Stats are collected only after MountDebug has been called. MountDebug must be called before server is started and
+// only once.
+//
+// Example:
+//
+//	err := e.MountDebug("/debug", echo.DebugConfig{
+//		Guard:       middleware.BasicAuth(checkAdmin),
+//		EnablePprof: true,
+//	})
+func (e *Echo) MountDebug(prefix string, config DebugConfig) error {
+	if config.Guard == nil {
+		return errors.New("echo: debug endpoints require guard middleware")
+	}
+	e.startupMutex.Lock()
+	started := e.Listener != nil || e.TLSListener != nil
+	e.startupMutex.Unlock()
+	if started {
+		return errors.New("echo: debug endpoints must be mounted before server is started")
+	}
+	if e.stats != nil {
+		return ErrDebugAlreadyMounted
+	}
+
+	stats := &serverStats{}
+	newContext := e.pool.New
+	e.pool.New = func() interface{} {
+		stats.poolMisses.Add(1)
+		return newContext()
+	}
+	e.stats = stats
+
+	g := e.Group(prefix, config.Guard)
+	g.GET("/routes", e.MiddlewareChainHandler())
+	g.GET("/stats", e.debugStatsHandler)
+	g.GET("/build", debugBuildInfoHandler)
+	g.GET("/vars", WrapHandler(expvar.Handler()))
+	if config.EnablePprof {
+		g.GET("/pprof/", WrapHandler(http.HandlerFunc(pprof.Index)))
+		g.GET("/pprof/cmdline", WrapHandler(http.HandlerFunc(pprof.Cmdline)))
+		g.GET("/pprof/profile", WrapHandler(http.HandlerFunc(pprof.Profile)))
+		g.Match([]string{http.MethodGet, http.MethodPost}, "/pprof/symbol", WrapHandler(http.HandlerFunc(pprof.Symbol)))
+		g.GET("/pprof/trace", WrapHandler(http.HandlerFunc(pprof.Trace)))
+		g.GET("/pprof/:name", func(c Context) error {
+			pprof.Handler(c.Param("name")).ServeHTTP(c.Response(), c.Request())
+			return nil
+		})
+	}
+	return nil
+}
+
+func (e *Echo) debugStatsHandler(c Context) error {
+	type poolStats struct {
+		Gets   int64 `json:"gets"`
+		Hits   int64 `json:"hits"`
+		Misses int64 `json:"misses"`
+		InUse  int64 `json:"in_use"`
+	}
+	s := e.stats
+	gets := s.poolGets.Load()
+	misses := s.poolMisses.Load()
+	return c.JSON(http.StatusOK, Map{
+		"in_flight_requests": s.inFlight.Load(),
+		"context_pool": poolStats{
+			Gets:   gets,
+			Hits:   gets - misses,
+			Misses: misses,
+			InUse:  gets - s.poolReleases.Load(),
+		},
+		"routes": len(e.Routes()),
+	})
+}
+
+func debugBuildInfoHandler(c Context) error {
+	type module struct {
+		Path    string `json:"path"`
+		Version string `json:"version"`
+		Sum     string `json:"sum,omitempty"`
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return NewHTTPError(http.StatusNotFound, "build info is not available")
+	}
+	deps := make([]module, 0, len(info.Deps))
+	for _, d := range info.Deps {
+		deps = append(deps, module{Path: d.Path, Version: d.Version, Sum: d.Sum})
+	}
+	settings := make(map[string]string, len(info.Settings))
+	for _, s := range info.Settings {
+		settings[s.Key] = s.Value
+	}
+	return c.JSON(http.StatusOK, Map{
+		"go_version": info.GoVersion,
+		"path":       info.Path,
+		"main":       module{Path: info.Main.Path, Version: info.Main.Version, Sum: info.Main.Sum},
+		"deps":       deps,
+		"settings":   settings,
+	})
+}
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func debugTestGuard(next HandlerFunc) HandlerFunc {
+	return func(c Context) error {
+		if c.Request().Header.Get(HeaderAuthorization) != "secret" {
+			return ErrForbidden
+		}
+		return next(c)
+	}
+}
+
+func serveDebugRequest(e *Echo, target string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set(HeaderAuthorization, "secret")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestEcho_MountDebug(t *testing.T) {
+	e := New()
+	var statsDuringRequest map[string]interface{}
+	e.GET("/users", func(c Context) error {
+		rec := serveDebugRequest(e, "/debug/stats")
+		return json.Unmarshal(rec.Body.Bytes(), &statsDuringRequest)
+	}).Name = "users"
+	assert.NoError(t, e.MountDebug("/debug", DebugConfig{Guard: debugTestGuard, EnablePprof: true}))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/routes", nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serveDebugRequest(e, "/debug/routes?route=users")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[{"method":"GET","path":"/users","name":"users","middlewares":[]}]`, rec.Body.String())
+
+	serveDebugRequest(e, "/users")
+	assert.Equal(t, float64(2), statsDuringRequest["in_flight_requests"])
+
+	rec = serveDebugRequest(e, "/debug/stats")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var stats struct {
+		InFlight int64 `json:"in_flight_requests"`
+		Pool     struct {
+			Gets   int64 `json:"gets"`
+			Hits   int64 `json:"hits"`
+			Misses int64 `json:"misses"`
+			InUse  int64 `json:"in_use"`
+		} `json:"context_pool"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	assert.Equal(t, int64(1), stats.InFlight)
+	assert.Equal(t, int64(5), stats.Pool.Gets)
+	assert.Equal(t, int64(1), stats.Pool.InUse)
+	assert.Equal(t, stats.Pool.Gets, stats.Pool.Hits+stats.Pool.Misses)
+	assert.True(t, stats.Pool.Misses > 0)
+
+	rec = serveDebugRequest(e, "/debug/build")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"go_version"`)
+
+	rec = serveDebugRequest(e, "/debug/vars")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"memstats"`)
+
+	rec = serveDebugRequest(e, "/debug/pprof/cmdline")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serveDebugRequest(e, "/debug/pprof/goroutine?debug=1")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "goroutine profile")
+}
+
+func TestEcho_MountDebug_withoutPprof(t *testing.T) {
+	e := New()
+	assert.NoError(t, e.MountDebug("/debug", DebugConfig{Guard: debugTestGuard}))
+
+	rec := serveDebugRequest(e, "/debug/pprof/cmdline")
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestEcho_MountDebug_errors(t *testing.T) {
+	e := New()
+	assert.EqualError(t, e.MountDebug("/debug", DebugConfig{}), "echo: debug endpoints require guard middleware")
+
+	assert.NoError(t, e.MountDebug("/debug", DebugConfig{Guard: debugTestGuard}))
+	assert.ErrorIs(t, e.MountDebug("/debug2", DebugConfig{Guard: debugTestGuard}), ErrDebugAlreadyMounted)
+
+	started := New()
+	started.HideBanner = true
+	started.HidePort = true
+	errChan := make(chan error, 1)
+	go func() { errChan <- started.Start("127.0.0.1:0") }()
+	assert.NoError(t, waitForServerStart(started, errChan, false))
+	defer started.Close()
+
+	err := started.MountDebug("/debug", DebugConfig{Guard: debugTestGuard})
+	assert.EqualError(t, err, "echo: debug endpoints must be mounted before server is started")
+}
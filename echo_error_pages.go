@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// contextKeyErrorPage is set while error page is being rendered so error page handler that is called again for the
+// same request (ala rendering failed and the error reached HTTPErrorHandler) does not try to render the page again.
+const contextKeyErrorPage = "echo_error_page"
+
+// ErrorPagesConfig defines the config for error pages rendered with Echo#Renderer.
+type ErrorPagesConfig struct {
+	// Pages maps response status codes to template names (ala `404: "errors/404.html"`).
+	Pages map[int]string
+
+	// DefaultPage is template name used for status codes that are not in Pages. Errors with status codes not in
+	// Pages are handled by Fallback when DefaultPage is empty.
+	// Optional. Default value "".
+	DefaultPage string
+
+	// Fallback handles errors that are not rendered as error page: template is not configured for the status code,
+	// client prefers JSON, request method is HEAD, response is already committed or rendering the template failed.
+	// Optional. Default value Echo#DefaultHTTPErrorHandler.
+	Fallback HTTPErrorHandler
+}
+
+// ErrorPageData is data passed to the error page template.
+type ErrorPageData struct {
+	// Status is response status code.
+	Status int
+	// Message is message of HTTPError or status text for errors that are not HTTPErrors.
+	Message string
+	// RequestID is `X-Request-ID` header value of the response or the request.
+	RequestID string
+	// Path is request URL path.
+	Path string
+}
+
+// ErrorPages returns HTTPErrorHandler that renders error pages with Echo#Renderer for clients that accept HTML and
+// hands other errors to the fallback handler. Page is rendered to buffer first so template that fails does not send
+// half of the page, failed rendering is logged and the error is handled by the fallback handler.
+//
+// Example:
+//
+//	e.Renderer = &echo.TemplateRenderer{Template: template.Must(template.ParseGlob("templates/*.html"))}
+//	e.HTTPErrorHandler = echo.ErrorPages(echo.ErrorPagesConfig{
+//		Pages:       map[int]string{http.StatusNotFound: "404.html"},
+//		DefaultPage: "500.html",
+//	})
+func ErrorPages(config ErrorPagesConfig) HTTPErrorHandler {
+	return func(err error, c Context) {
+		if renderErrorPage(config, err, c) {
+			return
+		}
+		if config.Fallback == nil || isRenderingErrorPage(c) {
+			// error page handler used as its own fallback would end up here again
+			c.Echo().DefaultHTTPErrorHandler(err, c)
+			return
+		}
+		config.Fallback(err, c)
+	}
+}
+
+// ErrorPagesMiddleware returns middleware that renders error pages for errors returned by the handlers of the group
+// (ala server-rendered part of the application) while other groups keep responding with errors formatted by
+// Echo#HTTPErrorHandler. Errors that are not rendered as error page are returned to be handled by
+// Echo#HTTPErrorHandler, Fallback of the config is not used.
+//
+// Example:
+//
+//	site := e.Group("", echo.ErrorPagesMiddleware(echo.ErrorPagesConfig{DefaultPage: "error.html"}))
+//	api := e.Group("/api") // JSON errors from Echo#HTTPErrorHandler
+func ErrorPagesMiddleware(config ErrorPagesConfig) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			err := next(c)
+			if err == nil || !renderErrorPage(config, err, c) {
+				return err
+			}
+			return nil
+		}
+	}
+}
+
+func isRenderingErrorPage(c Context) bool {
+	rendering, _ := c.Get(contextKeyErrorPage).(bool)
+	return rendering
+}
+
+// renderErrorPage renders error page for the error and returns true when the page was sent.
+func renderErrorPage(config ErrorPagesConfig, err error, c Context) bool {
+	req := c.Request()
+	if c.Response().Committed || req.Method == http.MethodHead || isRenderingErrorPage(c) {
+		return false
+	}
+	data := newErrorPageData(err, c)
+	page, ok := config.Pages[data.Status]
+	if !ok {
+		page = config.DefaultPage
+	}
+	if page == "" || prefersJSON(req.Header.Get(HeaderAccept)) {
+		return false
+	}
+
+	c.Set(contextKeyErrorPage, true)
+	if rErr := c.Render(data.Status, page, data); rErr != nil {
+		c.Logger().Errorf("echo: failed to render error page %s: %v", page, rErr)
+		return false
+	}
+	return true
+}
+
+func newErrorPageData(err error, c Context) ErrorPageData {
+	data := ErrorPageData{
+		Status: http.StatusInternalServerError,
+		Path:   c.Request().URL.Path,
+	}
+	if he, ok := err.(*HTTPError); ok {
+		if herr, ok := he.Internal.(*HTTPError); ok {
+			he = herr
+		}
+		data.Status = he.Code
+		switch m := he.Message.(type) {
+		case string:
+			data.Message = m
+		case error:
+			data.Message = m.Error()
+		}
+	}
+	if data.Message == "" {
+		data.Message = http.StatusText(data.Status)
+	}
+	data.RequestID = c.Response().Header().Get(HeaderXRequestID)
+	if data.RequestID == "" {
+		data.RequestID = c.Request().Header.Get(HeaderXRequestID)
+	}
+	return data
+}
+
+// prefersJSON checks if `Accept` header value gives JSON higher quality than HTML. Most specific media range matching
+// the type sets its quality. Empty header and ties prefer HTML.
+func prefersJSON(accept string) bool {
+	if accept == "" {
+		return false
+	}
+	return acceptQuality(accept, "application", "json") > acceptQuality(accept, "text", "html")
+}
+
+// acceptQuality returns quality of the media type in `Accept` header value or -1 when no media range matches the type.
+func acceptQuality(accept string, typ string, subtype string) float64 {
+	quality, specificity := -1.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		value, params, _ := strings.Cut(part, ";")
+		t, s, _ := strings.Cut(strings.TrimSpace(value), "/")
+
+		spec := 0
+		switch {
+		case strings.EqualFold(t, typ) && strings.EqualFold(s, subtype):
+			spec = 2
+		case strings.EqualFold(t, typ) && s == "*":
+			spec = 1
+		case t == "*" && s == "*":
+		default:
+			continue
+		}
+		if spec <= specificity {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if v, ok := strings.CutPrefix(strings.ReplaceAll(param, " ", ""), "q="); ok {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+		}
+		quality, specificity = q, spec
+	}
+	return quality
+}
@@ -0,0 +1,235 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errorPagesTestTemplates = func() *template.Template {
+	t := template.Must(template.New("404.html").Parse(`not found: {{.Status}} {{.Message}} {{.RequestID}} {{.Path}}`))
+	template.Must(t.New("error.html").Parse(`error: {{.Status}} {{.Message}}`))
+	template.Must(t.New("broken.html").Parse(`partial {{.Missing}}`))
+	return t
+}()
+
+func TestErrorPages(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		givenConfig  ErrorPagesConfig
+		givenErr     error
+		whenMethod   string
+		whenAccept   string
+		expectStatus int
+		expectBody   string
+	}{
+		{
+			name:         "ok, page for status code",
+			givenConfig:  ErrorPagesConfig{Pages: map[int]string{http.StatusNotFound: "404.html"}, DefaultPage: "error.html"},
+			givenErr:     ErrNotFound,
+			whenAccept:   "text/html,application/xhtml+xml,*/*;q=0.8",
+			expectStatus: http.StatusNotFound,
+			expectBody:   "not found: 404 Not Found rid-1 /users/1",
+		},
+		{
+			name:         "ok, default page",
+			givenConfig:  ErrorPagesConfig{Pages: map[int]string{http.StatusNotFound: "404.html"}, DefaultPage: "error.html"},
+			givenErr:     NewHTTPError(http.StatusForbidden, "no access"),
+			expectStatus: http.StatusForbidden,
+			expectBody:   "error: 403 no access",
+		},
+		{
+			name:         "ok, internal HTTPError is used",
+			givenConfig:  ErrorPagesConfig{DefaultPage: "error.html"},
+			givenErr:     ErrBadRequest.WithInternal(NewHTTPError(http.StatusConflict, "exists")),
+			expectStatus: http.StatusConflict,
+			expectBody:   "error: 409 exists",
+		},
+		{
+			name:         "ok, error message is not leaked",
+			givenConfig:  ErrorPagesConfig{DefaultPage: "error.html"},
+			givenErr:     errors.New("db password is wrong"),
+			expectStatus: http.StatusInternalServerError,
+			expectBody:   "error: 500 Internal Server Error",
+		},
+		{
+			name:         "ok, any client gets html",
+			givenConfig:  ErrorPagesConfig{DefaultPage: "error.html"},
+			givenErr:     ErrNotFound,
+			whenAccept:   "*/*",
+			expectStatus: http.StatusNotFound,
+			expectBody:   "error: 404 Not Found",
+		},
+		{
+			name:         "ok, no page for status code",
+			givenConfig:  ErrorPagesConfig{Pages: map[int]string{http.StatusNotFound: "404.html"}},
+			givenErr:     ErrForbidden,
+			expectStatus: http.StatusForbidden,
+			expectBody:   "{\"message\":\"Forbidden\"}\n",
+		},
+		{
+			name:         "ok, client prefers JSON",
+			givenConfig:  ErrorPagesConfig{DefaultPage: "error.html"},
+			givenErr:     ErrNotFound,
+			whenAccept:   "application/json, text/html;q=0.9",
+			expectStatus: http.StatusNotFound,
+			expectBody:   "{\"message\":\"Not Found\"}\n",
+		},
+		{
+			name:         "ok, HEAD request",
+			givenConfig:  ErrorPagesConfig{DefaultPage: "error.html"},
+			givenErr:     ErrNotFound,
+			whenMethod:   http.MethodHead,
+			expectStatus: http.StatusNotFound,
+			expectBody:   "",
+		},
+		{
+			name: "ok, custom fallback",
+			givenConfig: ErrorPagesConfig{
+				Fallback: func(err error, c Context) {
+					_ = c.String(http.StatusTeapot, "fallback")
+				},
+			},
+			givenErr:     ErrNotFound,
+			expectStatus: http.StatusTeapot,
+			expectBody:   "fallback",
+		},
+		{
+			name:         "nok, rendering fails, nothing of the page is sent",
+			givenConfig:  ErrorPagesConfig{DefaultPage: "broken.html"},
+			givenErr:     ErrNotFound,
+			expectStatus: http.StatusNotFound,
+			expectBody:   "{\"message\":\"Not Found\"}\n",
+		},
+		{
+			name:         "nok, template does not exist",
+			givenConfig:  ErrorPagesConfig{DefaultPage: "missing.html"},
+			givenErr:     ErrNotFound,
+			expectStatus: http.StatusNotFound,
+			expectBody:   "{\"message\":\"Not Found\"}\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.Renderer = &TemplateRenderer{Template: errorPagesTestTemplates}
+			e.HTTPErrorHandler = ErrorPages(tc.givenConfig)
+			e.Match([]string{http.MethodGet, http.MethodHead}, "/users/:id", func(c Context) error {
+				return tc.givenErr
+			})
+
+			method := http.MethodGet
+			if tc.whenMethod != "" {
+				method = tc.whenMethod
+			}
+			req := httptest.NewRequest(method, "/users/1", nil)
+			req.Header.Set(HeaderXRequestID, "rid-1")
+			if tc.whenAccept != "" {
+				req.Header.Set(HeaderAccept, tc.whenAccept)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectStatus, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+		})
+	}
+}
+
+func TestErrorPages_withoutRenderer(t *testing.T) {
+	e := New()
+	e.HTTPErrorHandler = ErrorPages(ErrorPagesConfig{DefaultPage: "error.html"})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "{\"message\":\"Not Found\"}\n", rec.Body.String())
+}
+
+func TestErrorPages_fallbackToItself(t *testing.T) {
+	e := New()
+	e.Renderer = &TemplateRenderer{Template: errorPagesTestTemplates}
+	config := ErrorPagesConfig{DefaultPage: "broken.html"}
+	config.Fallback = func(err error, c Context) {
+		ErrorPages(config)(err, c)
+	}
+	e.HTTPErrorHandler = ErrorPages(config)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "{\"message\":\"Not Found\"}\n", rec.Body.String())
+}
+
+func TestErrorPagesMiddleware(t *testing.T) {
+	e := New()
+	e.Renderer = &TemplateRenderer{Template: errorPagesTestTemplates}
+	site := e.Group("/site", ErrorPagesMiddleware(ErrorPagesConfig{DefaultPage: "error.html"}))
+	site.GET("/ok", func(c Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+	site.GET("/fail", func(c Context) error {
+		return ErrForbidden
+	})
+	e.GET("/api/fail", func(c Context) error {
+		return ErrForbidden
+	})
+
+	var testCases = []struct {
+		whenURL      string
+		whenAccept   string
+		expectStatus int
+		expectBody   string
+	}{
+		{whenURL: "/site/ok", expectStatus: http.StatusOK, expectBody: "ok"},
+		{whenURL: "/site/fail", expectStatus: http.StatusForbidden, expectBody: "error: 403 Forbidden"},
+		{whenURL: "/site/missing", expectStatus: http.StatusNotFound, expectBody: "error: 404 Not Found"},
+		{whenURL: "/site/fail", whenAccept: MIMEApplicationJSON, expectStatus: http.StatusForbidden, expectBody: "{\"message\":\"Forbidden\"}\n"},
+		{whenURL: "/api/fail", expectStatus: http.StatusForbidden, expectBody: "{\"message\":\"Forbidden\"}\n"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.whenURL+" "+tc.whenAccept, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			if tc.whenAccept != "" {
+				req.Header.Set(HeaderAccept, tc.whenAccept)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectStatus, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+		})
+	}
+}
+
+func TestPrefersJSON(t *testing.T) {
+	var testCases = []struct {
+		whenAccept string
+		expect     bool
+	}{
+		{whenAccept: "", expect: false},
+		{whenAccept: "*/*", expect: false},
+		{whenAccept: "application/json", expect: true},
+		{whenAccept: "application/*", expect: true},
+		{whenAccept: "application/json, */*;q=0.1", expect: true},
+		{whenAccept: "text/html, application/json", expect: false},
+		{whenAccept: "application/json;q=0.5, text/html;q=0.9", expect: false},
+		{whenAccept: "text/*;q=0.5, application/json", expect: true},
+		{whenAccept: "text/html;q=0, */*", expect: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.whenAccept, func(t *testing.T) {
+			assert.Equal(t, tc.expect, prefersJSON(tc.whenAccept))
+		})
+	}
+}
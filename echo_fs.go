@@ -21,6 +21,10 @@ type filesystem struct {
 	// prefix for directory path. This is necessary as `//go:embed assets/images` embeds files with paths
 	// including `assets/images` as their prefix.
 	Filesystem fs.FS
+
+	// ServePrecompressed enables serving of pre-compressed file variants (`app.js.br`, `app.js.gz`) by Static and File
+	// handlers when client accepts their encoding. See `ServePrecompressedFile`.
+	ServePrecompressed bool
 }
 
 func createFilesystem() filesystem {
@@ -32,11 +36,13 @@ func createFilesystem() filesystem {
 // Static registers a new route with path prefix to serve static files from the provided root directory.
 func (e *Echo) Static(pathPrefix, fsRoot string) *Route {
 	subFs := MustSubFS(e.Filesystem, fsRoot)
-	return e.Add(
+	route := e.Add(
 		http.MethodGet,
 		pathPrefix+"*",
 		StaticDirectoryHandler(subFs, false),
 	)
+	e.lintState.recordStatic(route, subFs)
+	return route
 }
 
 // StaticFS registers a new route with path prefix to serve static files from the provided file system.
@@ -9,8 +9,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 func TestEcho_StaticFS(t *testing.T) {
@@ -269,3 +271,125 @@ func TestEcho_StaticPanic(t *testing.T) {
 		})
 	}
 }
+
+func TestEcho_StaticFS_precompressed(t *testing.T) {
+	filesystem := fstest.MapFS{
+		"app.js":         &fstest.MapFile{Data: []byte("console.log('original')")},
+		"app.js.br":      &fstest.MapFile{Data: []byte("brotli")},
+		"app.js.gz":      &fstest.MapFile{Data: []byte("gzipped")},
+		"plain.css":      &fstest.MapFile{Data: []byte("body{}")},
+		"unknown.xyz":    &fstest.MapFile{Data: []byte("unknown")},
+		"unknown.xyz.gz": &fstest.MapFile{Data: []byte("unknown gzipped")},
+	}
+
+	var testCases = []struct {
+		name               string
+		whenURL            string
+		whenAcceptEncoding string
+		whenRange          string
+		expectBody         string
+		expectEncoding     string
+		expectContentType  string
+		expectVary         string
+	}{
+		{
+			name:               "ok, brotli is preferred",
+			whenURL:            "/assets/app.js",
+			whenAcceptEncoding: "gzip, deflate, br",
+			expectBody:         "brotli",
+			expectEncoding:     "br",
+			expectContentType:  "text/javascript; charset=utf-8",
+			expectVary:         "Accept-Encoding",
+		},
+		{
+			name:               "ok, gzip",
+			whenURL:            "/assets/app.js",
+			whenAcceptEncoding: "gzip",
+			expectBody:         "gzipped",
+			expectEncoding:     "gzip",
+			expectContentType:  "text/javascript; charset=utf-8",
+			expectVary:         "Accept-Encoding",
+		},
+		{
+			name:               "ok, brotli refused with q=0",
+			whenURL:            "/assets/app.js",
+			whenAcceptEncoding: "br;q=0, *",
+			expectBody:         "gzipped",
+			expectEncoding:     "gzip",
+			expectContentType:  "text/javascript; charset=utf-8",
+			expectVary:         "Accept-Encoding",
+		},
+		{
+			name:              "ok, original when encodings are not accepted",
+			whenURL:           "/assets/app.js",
+			expectBody:        "console.log('original')",
+			expectContentType: "text/javascript; charset=utf-8",
+			expectVary:        "Accept-Encoding",
+		},
+		{
+			name:               "ok, range is ignored for precompressed variant",
+			whenURL:            "/assets/app.js",
+			whenAcceptEncoding: "br",
+			whenRange:          "bytes=0-1",
+			expectBody:         "brotli",
+			expectEncoding:     "br",
+			expectContentType:  "text/javascript; charset=utf-8",
+			expectVary:         "Accept-Encoding",
+		},
+		{
+			name:               "ok, original without variants",
+			whenURL:            "/assets/plain.css",
+			whenAcceptEncoding: "gzip, br",
+			expectBody:         "body{}",
+			expectContentType:  "text/css; charset=utf-8",
+		},
+		{
+			name:               "ok, original with unknown content type",
+			whenURL:            "/assets/unknown.xyz",
+			whenAcceptEncoding: "gzip",
+			expectBody:         "unknown",
+			expectContentType:  "text/plain; charset=utf-8",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.ServePrecompressed = true
+			e.StaticFS("/assets", filesystem)
+
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			if tc.whenAcceptEncoding != "" {
+				req.Header.Set(HeaderAcceptEncoding, tc.whenAcceptEncoding)
+			}
+			if tc.whenRange != "" {
+				req.Header.Set("Range", tc.whenRange)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+			assert.Equal(t, tc.expectEncoding, rec.Header().Get(HeaderContentEncoding))
+			assert.Equal(t, tc.expectContentType, rec.Header().Get(HeaderContentType))
+			assert.Equal(t, tc.expectVary, rec.Header().Get(HeaderVary))
+			assert.Equal(t, strconv.Itoa(len(tc.expectBody)), rec.Header().Get(HeaderContentLength))
+		})
+	}
+}
+
+func TestEcho_FileFS_precompressedDisabledByDefault(t *testing.T) {
+	e := New()
+	e.FileFS("/app.js", "app.js", fstest.MapFS{
+		"app.js":    &fstest.MapFile{Data: []byte("original")},
+		"app.js.gz": &fstest.MapFile{Data: []byte("gzipped")},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/app.js", nil)
+	req.Header.Set(HeaderAcceptEncoding, "gzip")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, "original", rec.Body.String())
+	assert.Equal(t, "", rec.Header().Get(HeaderContentEncoding))
+}
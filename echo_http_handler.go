@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+)
+
+// WrapToHTTPHandlerOptions defines options of `WrapToHTTPHandler`.
+type WrapToHTTPHandlerOptions struct {
+	// Pattern is route path (ala `/users/:id/files/*`) path params are extracted from as no Echo router runs for the
+	// request. Requests with path not matching the pattern result "404 - Not Found" error. Pattern should contain the
+	// prefix stripped by `http.StripPrefix` as it is matched against the request path the handler receives.
+	// Optional. Default value "" means that path params are not extracted and all paths are accepted.
+	Pattern string
+
+	// Middlewares are applied around the handler. Middlewares added with `Echo#Use` and `Echo#Pre` are not applied
+	// as the request does not go through `Echo#ServeHTTP`.
+	// Optional.
+	Middlewares []MiddlewareFunc
+
+	// DisableRecover turns off recovering from panics of the handler. By default panics are recovered the same way
+	// as Recover middleware with default config does: stack is logged and error is passed to `Echo#HTTPErrorHandler`.
+	// Optional. Default value false.
+	DisableRecover bool
+}
+
+// WrapToHTTPHandler wraps `echo.HandlerFunc` into `http.Handler` so echo handlers can be used with other muxes (ala
+// `http.ServeMux`). Handler is served with pooled echo.Context of e so Bind, Render, Logger etc. work as usual and
+// returned errors are handled with `Echo#HTTPErrorHandler`.
+//
+// Example:
+//
+//	mux := http.NewServeMux()
+//	mux.Handle("/users/", echo.WrapToHTTPHandler(e, getUser, echo.WrapToHTTPHandlerOptions{Pattern: "/users/:id"}))
+func WrapToHTTPHandler(e *Echo, h HandlerFunc, opts WrapToHTTPHandlerOptions) http.Handler {
+	pattern := opts.Pattern
+	var pnames []string
+	if pattern != "" {
+		pattern = normalizePathSlash(pattern)
+		if name, ok := duplicateParamName(pattern); ok {
+			panic(fmt.Errorf("echo: invalid pattern %s: path param %q is declared more than once", pattern, name))
+		}
+		pnames = routeParamNames(pattern)
+		if *e.maxParam < len(pnames) {
+			*e.maxParam = len(pnames)
+		}
+	}
+	if err := checkHandlerParams(h, pattern); err != nil {
+		panic(fmt.Errorf("echo: invalid pattern %s: %w", pattern, err))
+	}
+	h = applyMiddleware(h, opts.Middlewares...)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := e.pool.Get().(*context)
+		c.Reset(r, w)
+
+		handler := h
+		if pattern != "" {
+			path := GetPath(r)
+			if e.UseEncodedPath {
+				path = r.URL.EscapedPath()
+			}
+			if values, ok := matchPattern(pattern, path); ok {
+				c.path = pattern
+				c.SetParamNames(pnames...)
+				c.SetParamValues(values...)
+				if e.UseEncodedPath {
+					c.unescapePathParams()
+				}
+			} else {
+				handler = NotFoundHandler
+			}
+		}
+
+		if err := serveWrapped(c, handler, opts.DisableRecover); err != nil {
+			c.Error(err)
+		}
+		if c.deferred != nil {
+			e.runDeferred(c)
+		}
+
+		if e.GuardReleasedResponse {
+			c.response.release(r.Method+" "+c.path, e.Debug)
+			return
+		}
+		e.pool.Put(c)
+	})
+}
+
+// serveWrapped calls handler and converts its panic to error when recovering is enabled.
+func serveWrapped(c *context, h HandlerFunc, disableRecover bool) (err error) {
+	if !disableRecover {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			if r == http.ErrAbortHandler {
+				panic(r)
+			}
+			panicErr, ok := r.(error)
+			if !ok {
+				panicErr = fmt.Errorf("%v", r)
+			}
+			stack := make([]byte, 4<<10)
+			stack = stack[:runtime.Stack(stack, true)]
+			c.Logger().Print(fmt.Sprintf("[PANIC RECOVER] %v %s\n", panicErr, stack))
+			err = panicErr
+		}()
+	}
+	return h(c)
+}
+
+// matchPattern matches path against route pattern and returns values of the pattern params. Param (`:name`) matches
+// one path segment and wildcard (`*`) the rest of the path.
+func matchPattern(pattern string, path string) ([]string, bool) {
+	var values []string
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case pattern[i] == '\\' && i+1 < len(pattern) && pattern[i+1] == ':':
+			continue
+		case pattern[i] == ':' && (i == 0 || pattern[i-1] != '\\'):
+			for i < len(pattern) && pattern[i] != '/' {
+				i++
+			}
+			end := strings.IndexByte(path, '/')
+			if end == -1 {
+				end = len(path)
+			}
+			values = append(values, path[:end])
+			path = path[end:]
+			i--
+		case pattern[i] == '*':
+			values = append(values, path)
+			path = ""
+		default:
+			if path == "" || path[0] != pattern[i] {
+				return nil, false
+			}
+			path = path[1:]
+		}
+	}
+	return values, path == ""
+}
@@ -0,0 +1,216 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapToHTTPHandler_serveMux(t *testing.T) {
+	e := New()
+	logs := new(bytes.Buffer)
+	e.Logger.SetOutput(logs)
+
+	type user struct {
+		ID   int    `param:"id" json:"id"`
+		Name string `json:"name"`
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/users/", WrapToHTTPHandler(e, func(c Context) error {
+		var u user
+		if err := c.Bind(&u); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, u)
+	}, WrapToHTTPHandlerOptions{Pattern: "/users/:id"}))
+	mux.Handle("/files/", WrapToHTTPHandler(e, func(c Context) error {
+		return c.String(http.StatusOK, c.Path()+" "+c.Param("*"))
+	}, WrapToHTTPHandlerOptions{Pattern: "/files/*"}))
+	mux.Handle("/api/", http.StripPrefix("/api", WrapToHTTPHandler(e, func(c Context) error {
+		return c.String(http.StatusOK, "team="+c.Param("team")+" user="+c.Param("user"))
+	}, WrapToHTTPHandlerOptions{Pattern: "/teams/:team/users/:user"})))
+	mux.Handle("/error", WrapToHTTPHandler(e, func(c Context) error {
+		return NewHTTPError(http.StatusTeapot, "short and stout")
+	}, WrapToHTTPHandlerOptions{}))
+	mux.Handle("/panic", WrapToHTTPHandler(e, func(c Context) error {
+		panic("boom")
+	}, WrapToHTTPHandlerOptions{}))
+	mux.Handle("/middleware", WrapToHTTPHandler(e, func(c Context) error {
+		return c.String(http.StatusOK, c.Get("mw").(string))
+	}, WrapToHTTPHandlerOptions{Middlewares: []MiddlewareFunc{func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			c.Set("mw", "applied")
+			return next(c)
+		}
+	}}}))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var testCases = []struct {
+		name         string
+		whenMethod   string
+		whenURL      string
+		whenBody     string
+		expectStatus int
+		expectBody   string
+	}{
+		{
+			name:         "ok, path param and body are bound",
+			whenMethod:   http.MethodPost,
+			whenURL:      "/users/42",
+			whenBody:     `{"name":"Jon"}`,
+			expectStatus: http.StatusOK,
+			expectBody:   `{"id":42,"name":"Jon"}` + "\n",
+		},
+		{
+			name:         "nok, bind error is handled by HTTPErrorHandler",
+			whenMethod:   http.MethodPost,
+			whenURL:      "/users/abc",
+			whenBody:     `{}`,
+			expectStatus: http.StatusBadRequest,
+			expectBody:   `{"message":"strconv.ParseInt: parsing \"abc\": invalid syntax"}` + "\n",
+		},
+		{
+			name:         "nok, path not matching pattern",
+			whenMethod:   http.MethodGet,
+			whenURL:      "/users/42/friends",
+			expectStatus: http.StatusNotFound,
+			expectBody:   `{"message":"Not Found"}` + "\n",
+		},
+		{
+			name:         "ok, wildcard",
+			whenMethod:   http.MethodGet,
+			whenURL:      "/files/docs/readme.md",
+			expectStatus: http.StatusOK,
+			expectBody:   "/files/* docs/readme.md",
+		},
+		{
+			name:         "ok, pattern matched after StripPrefix",
+			whenMethod:   http.MethodGet,
+			whenURL:      "/api/teams/red/users/jon",
+			expectStatus: http.StatusOK,
+			expectBody:   "team=red user=jon",
+		},
+		{
+			name:         "nok, returned error",
+			whenMethod:   http.MethodGet,
+			whenURL:      "/error",
+			expectStatus: http.StatusTeapot,
+			expectBody:   `{"message":"short and stout"}` + "\n",
+		},
+		{
+			name:         "nok, panic is recovered",
+			whenMethod:   http.MethodGet,
+			whenURL:      "/panic",
+			expectStatus: http.StatusInternalServerError,
+			expectBody:   `{"message":"Internal Server Error"}` + "\n",
+		},
+		{
+			name:         "ok, middlewares",
+			whenMethod:   http.MethodGet,
+			whenURL:      "/middleware",
+			expectStatus: http.StatusOK,
+			expectBody:   "applied",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.whenMethod, server.URL+tc.whenURL, strings.NewReader(tc.whenBody))
+			assert.NoError(t, err)
+			req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+
+			res, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+			defer res.Body.Close()
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.expectStatus, res.StatusCode)
+			assert.Equal(t, tc.expectBody, string(body))
+		})
+	}
+	assert.Contains(t, logs.String(), "[PANIC RECOVER] boom")
+}
+
+func TestWrapToHTTPHandler_disableRecover(t *testing.T) {
+	e := New()
+	h := WrapToHTTPHandler(e, func(c Context) error {
+		panic(errors.New("boom"))
+	}, WrapToHTTPHandlerOptions{DisableRecover: true})
+
+	assert.PanicsWithError(t, "boom", func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func TestWrapToHTTPHandler_guardReleasedResponse(t *testing.T) {
+	var testCases = []struct {
+		name                  string
+		debug                 bool
+		guardReleasedResponse bool
+		expectReleased        bool
+	}{
+		{name: "ok, debug without guard returns context to pool", debug: true, expectReleased: false},
+		{name: "ok, guard releases response", guardReleasedResponse: true, expectReleased: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.Debug = tc.debug
+			e.GuardReleasedResponse = tc.guardReleasedResponse
+			var ctx Context
+			h := WrapToHTTPHandler(e, func(c Context) error {
+				ctx = c
+				return c.String(http.StatusOK, "OK")
+			}, WrapToHTTPHandlerOptions{})
+
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tc.expectReleased, ctx.Response().released.Load())
+		})
+	}
+}
+
+func TestWrapToHTTPHandler_invalidPattern(t *testing.T) {
+	assert.PanicsWithError(t, `echo: invalid pattern /a/:id/b/:id: path param "id" is declared more than once`, func() {
+		WrapToHTTPHandler(New(), NotFoundHandler, WrapToHTTPHandlerOptions{Pattern: "/a/:id/b/:id"})
+	})
+}
+
+func TestMatchPattern(t *testing.T) {
+	var testCases = []struct {
+		pattern      string
+		path         string
+		expectValues []string
+		expectOK     bool
+	}{
+		{pattern: "/users/:id", path: "/users/1", expectValues: []string{"1"}, expectOK: true},
+		{pattern: "/users/:id/files/*", path: "/users/1/files/a/b", expectValues: []string{"1", "a/b"}, expectOK: true},
+		{pattern: "/users/:id", path: "/users/1/", expectOK: false},
+		{pattern: "/users/:id", path: "/groups/1", expectOK: false},
+		{pattern: "/users/\\:id", path: "/users/:id", expectOK: true},
+		{pattern: "/", path: "/", expectOK: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.pattern+" "+tc.path, func(t *testing.T) {
+			values, ok := matchPattern(tc.pattern, tc.path)
+			assert.Equal(t, tc.expectOK, ok)
+			if tc.expectOK {
+				assert.Equal(t, tc.expectValues, values)
+			}
+		})
+	}
+}
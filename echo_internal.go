@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"bytes"
+	stdContext "context"
+	"errors"
+	"net/http"
+)
+
+// MaxInternalRequestDepth is maximum nesting of internal requests dispatched with `Echo#ServeInternal` (internal
+// request that dispatches internal request and so on).
+const MaxInternalRequestDepth = 8
+
+// ErrInternalRequestDepthExceeded is returned by `Echo#ServeInternal` when internal requests are nested deeper than
+// MaxInternalRequestDepth (ala route that dispatches request to itself).
+var ErrInternalRequestDepthExceeded = errors.New("echo: internal request depth exceeded")
+
+// internalRequestDepthKey is request context key holding nesting depth of internal request.
+type internalRequestDepthKey struct{}
+
+// RecordedResponse is response of internal request dispatched with `Echo#ServeInternal`.
+type RecordedResponse struct {
+	// Status is HTTP status code of the response.
+	Status int
+	// Header contains headers of the response.
+	Header http.Header
+	// Body is the response body.
+	Body []byte
+}
+
+// ServeInternal dispatches request through the full Echo pipeline (pre-middlewares, router, middlewares and handler)
+// without going through the network and returns the recorded response. Request context is derived from ctx so
+// deadline, cancellation and values (ala trace spans) of the parent request are propagated. `Context#IsInternal`
+// returns true for internal requests so middlewares (ala authentication) can treat them differently. Internal
+// requests can be nested up to MaxInternalRequestDepth levels, deeper nesting returns
+// ErrInternalRequestDepthExceeded. Hijacking connection is not supported for internal requests.
+//
+// Example (batch endpoint):
+//
+//	e.POST("/batch", func(c echo.Context) error {
+//		var paths []string
+//		if err := c.Bind(&paths); err != nil {
+//			return err
+//		}
+//		results := make([]json.RawMessage, 0, len(paths))
+//		for _, p := range paths {
+//			req := httptest.NewRequest(http.MethodGet, p, nil)
+//			req.Header.Set(echo.HeaderAuthorization, c.Request().Header.Get(echo.HeaderAuthorization))
+//			res, err := c.Echo().ServeInternal(c.Request().Context(), req)
+//			if err != nil {
+//				return err
+//			}
+//			results = append(results, res.Body)
+//		}
+//		return c.JSON(http.StatusOK, results)
+//	})
+func (e *Echo) ServeInternal(ctx stdContext.Context, req *http.Request) (*RecordedResponse, error) {
+	depth := internalRequestDepth(ctx)
+	if depth >= MaxInternalRequestDepth {
+		return nil, ErrInternalRequestDepthExceeded
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	req = req.WithContext(stdContext.WithValue(ctx, internalRequestDepthKey{}, depth+1))
+	if req.Body == nil {
+		req.Body = http.NoBody
+	}
+
+	rec := &internalResponseWriter{header: make(http.Header)}
+	e.ServeHTTP(rec, req)
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	return &RecordedResponse{Status: rec.status, Header: rec.header, Body: rec.body.Bytes()}, nil
+}
+
+// internalRequestDepth returns nesting depth of internal request with given context. Zero means that request is not
+// internal.
+func internalRequestDepth(ctx stdContext.Context) int {
+	depth, _ := ctx.Value(internalRequestDepthKey{}).(int)
+	return depth
+}
+
+// internalResponseWriter is in-memory http.ResponseWriter for internal requests.
+type internalResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *internalResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *internalResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *internalResponseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
+
+// Flush does nothing as the response is kept in memory. It allows streaming handlers to work with internal requests.
+func (w *internalResponseWriter) Flush() {}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	stdContext "context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type batchResult struct {
+	Path   string          `json:"path"`
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body"`
+}
+
+func TestEcho_ServeInternal_batch(t *testing.T) {
+	e := New()
+	e.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			if !c.IsInternal() && c.Request().Header.Get(HeaderAuthorization) != "Bearer token" {
+				return ErrUnauthorized
+			}
+			return next(c)
+		}
+	})
+	e.GET("/users/:id", func(c Context) error {
+		return c.JSON(http.StatusOK, map[string]interface{}{"id": c.Param("id"), "internal": c.IsInternal()})
+	})
+	e.POST("/batch", func(c Context) error {
+		var paths []string
+		if err := c.Bind(&paths); err != nil {
+			return err
+		}
+		results := make([]batchResult, 0, len(paths))
+		for _, p := range paths {
+			res, err := c.Echo().ServeInternal(c.Request().Context(), httptest.NewRequest(http.MethodGet, p, nil))
+			if err != nil {
+				return err
+			}
+			results = append(results, batchResult{Path: p, Status: res.Status, Body: res.Body})
+		}
+		return c.JSON(http.StatusOK, results)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(`["/users/1","/users/2","/nope"]`))
+	req.Header.Set(HeaderContentType, MIMEApplicationJSON)
+	req.Header.Set(HeaderAuthorization, "Bearer token")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[
+		{"path":"/users/1","status":200,"body":{"id":"1","internal":true}},
+		{"path":"/users/2","status":200,"body":{"id":"2","internal":true}},
+		{"path":"/nope","status":404,"body":{"message":"Not Found"}}
+	]`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestEcho_ServeInternal_response(t *testing.T) {
+	e := New()
+	e.POST("/echo", func(c Context) error {
+		c.Response().Header().Set("X-Custom", "1")
+		return c.String(http.StatusCreated, c.FormValue("name"))
+	})
+	e.GET("/empty", func(c Context) error {
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("name=jon"))
+	req.Header.Set(HeaderContentType, MIMEApplicationForm)
+	res, err := e.ServeInternal(stdContext.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, res.Status)
+	assert.Equal(t, "1", res.Header.Get("X-Custom"))
+	assert.Equal(t, "jon", string(res.Body))
+
+	req, _ = http.NewRequest(http.MethodGet, "/empty", nil) // nil body
+	res, err = e.ServeInternal(stdContext.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.Status)
+	assert.Empty(t, res.Body)
+}
+
+func TestEcho_ServeInternal_propagatesContext(t *testing.T) {
+	type traceKey struct{}
+	e := New()
+	var deadline time.Time
+	var trace interface{}
+	e.GET("/", func(c Context) error {
+		deadline, _ = c.Request().Context().Deadline()
+		trace = c.Request().Context().Value(traceKey{})
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	ctx, cancel := stdContext.WithTimeout(stdContext.WithValue(stdContext.Background(), traceKey{}, "span-1"), time.Minute)
+	defer cancel()
+	expectDeadline, _ := ctx.Deadline()
+
+	res, err := e.ServeInternal(ctx, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.Status)
+	assert.Equal(t, expectDeadline, deadline)
+	assert.Equal(t, "span-1", trace)
+
+	cancel()
+	_, err = e.ServeInternal(ctx, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.ErrorIs(t, err, stdContext.Canceled)
+}
+
+func TestEcho_ServeInternal_depthLimit(t *testing.T) {
+	e := New()
+	calls := 0
+	e.GET("/loop", func(c Context) error {
+		calls++
+		res, err := c.Echo().ServeInternal(c.Request().Context(), httptest.NewRequest(http.MethodGet, "/loop", nil))
+		if err != nil {
+			return err
+		}
+		return c.Blob(res.Status, MIMETextPlain, res.Body)
+	})
+
+	_, err := e.ServeInternal(stdContext.Background(), httptest.NewRequest(http.MethodGet, "/loop", nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, MaxInternalRequestDepth, calls)
+
+	ctx := stdContext.WithValue(stdContext.Background(), internalRequestDepthKey{}, MaxInternalRequestDepth)
+	_, err = e.ServeInternal(ctx, httptest.NewRequest(http.MethodGet, "/loop", nil))
+	assert.True(t, errors.Is(err, ErrInternalRequestDepthExceeded))
+}
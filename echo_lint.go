@@ -0,0 +1,398 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// WarningSeverity is severity of the `Warning` reported by `Echo#Lint`.
+type WarningSeverity string
+
+const (
+	// SeverityWarning is severity of configuration that works but probably not as intended.
+	SeverityWarning WarningSeverity = "warning"
+	// SeverityError is severity of configuration that breaks requests or security of the application.
+	SeverityError WarningSeverity = "error"
+)
+
+// Codes of warnings reported by `Echo#Lint`.
+const (
+	// LintRecoverNotFirst is reported when Recover middleware is registered after middlewares (other than loggers)
+	// that could panic.
+	LintRecoverNotFirst = "recover-not-first"
+	// LintLoggerAfterRecover is reported when logger middleware is registered after Recover middleware.
+	LintLoggerAfterRecover = "logger-after-recover"
+	// LintCORSAfterAuth is reported when CORS middleware is registered after authentication middleware.
+	LintCORSAfterAuth = "cors-after-auth"
+	// LintCORSPreflightUnroutable is reported when CORS middleware is registered at route level and there is no
+	// OPTIONS route for the path, so preflight requests never reach the middleware.
+	LintCORSPreflightUnroutable = "cors-preflight-unroutable"
+	// LintBodyDumpBeforeGzip is reported when BodyDump middleware is registered before Gzip middleware.
+	LintBodyDumpBeforeGzip = "body-dump-before-gzip"
+	// LintBodyLimitBeforeDecompress is reported when BodyLimit middleware is registered before Decompress middleware.
+	LintBodyLimitBeforeDecompress = "body-limit-before-decompress"
+	// LintRouteShadowed is reported when routes of the same method have the same path with different parameter names.
+	LintRouteShadowed = "route-shadowed"
+	// LintGroupPrefixTrailingSlash is reported when group prefix ends with slash.
+	LintGroupPrefixTrailingSlash = "group-prefix-trailing-slash"
+	// LintStaticRootOutsideWorkDir is reported when Static root directory is outside the working directory.
+	LintStaticRootOutsideWorkDir = "static-root-outside-workdir"
+	// LintValidatorNotRegistered is reported when `Context#Validate` has been called without `Echo#Validator`.
+	LintValidatorNotRegistered = "validator-not-registered"
+)
+
+// Warning describes misconfiguration detected by `Echo#Lint`.
+type Warning struct {
+	// Code identifies the kind of the warning (ala `recover-not-first`). See `Lint*` constants.
+	Code string `json:"code"`
+	// Severity is `error` for configuration that breaks requests or security and `warning` otherwise.
+	Severity WarningSeverity `json:"severity"`
+	// Message describes the problem.
+	Message string `json:"message"`
+	// Hint describes how to fix the problem.
+	Hint string `json:"hint"`
+}
+
+// String returns warning in form suitable for logging.
+func (w Warning) String() string {
+	return fmt.Sprintf("echo: %s [%s]: %s. %s", w.Severity, w.Code, w.Message, w.Hint)
+}
+
+// lintState holds configuration recorded for `Echo#Lint` that can not be read from routes and middlewares.
+type lintState struct {
+	mutex         sync.Mutex
+	groupPrefixes []string
+	staticRoots   []lintStaticRoot
+
+	// validateWithoutValidator counts `Context#Validate` calls made without registered Validator.
+	validateWithoutValidator atomic.Int64
+}
+
+type lintStaticRoot struct {
+	path string
+	dir  string
+}
+
+func (s *lintState) recordGroup(prefix string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.groupPrefixes = append(s.groupPrefixes, prefix)
+}
+
+// recordStatic records root directory of Static route. Only directories of the OS filesystem are recorded.
+func (s *lintState) recordStatic(route *Route, filesystem fs.FS) {
+	dFS, ok := filesystem.(*defaultFS)
+	if !ok {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.staticRoots = append(s.staticRoots, lintStaticRoot{path: route.Path, dir: dFS.prefix})
+}
+
+// Lint checks configuration of the Echo instance for common mistakes and returns warnings with remediation hints.
+// Checks are:
+//   - Recover middleware not first in the middleware chain (only loggers may precede it)
+//   - logger middleware registered after Recover (requests that panic are not logged)
+//   - CORS middleware registered after authentication middleware or at route level without OPTIONS route
+//   - BodyDump registered before Gzip and BodyLimit registered before Decompress
+//   - routes shadowed by later routes with the same path but different parameter names
+//   - group prefixes ending with slash
+//   - Static root directories outside the working directory
+//   - `Context#Validate` called without `Echo#Validator` (detectable only at runtime, counted while serving)
+//
+// Lint is run on server start when `Echo#Debug` is enabled and warnings are logged. Runtime checks are logged again on
+// `Echo#Shutdown`. Call Lint in tests to fail CI on warnings with `SeverityError`.
+//
+// Middlewares are recognized by their names (see `NamedMiddleware` and `Echo#MiddlewareChain`).
+func (e *Echo) Lint() []Warning {
+	warnings := e.lintMiddleware()
+	warnings = append(warnings, e.lintRoutes()...)
+	warnings = append(warnings, e.lintConfig()...)
+	return append(warnings, e.lintRuntime()...)
+}
+
+func (e *Echo) logLintWarnings(warnings []Warning) {
+	for _, w := range warnings {
+		if w.Severity == SeverityError {
+			e.Logger.Error(w.String())
+		} else {
+			e.Logger.Warn(w.String())
+		}
+	}
+}
+
+// lintMiddlewareKind returns kind of middleware recognized from its name.
+func lintMiddlewareKind(name string) string {
+	name = strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(name))
+	switch {
+	case strings.Contains(name, "recover"):
+		return "recover"
+	case strings.Contains(name, "logger"):
+		return "logger"
+	case strings.Contains(name, "cors"):
+		return "cors"
+	case strings.Contains(name, "auth") || strings.Contains(name, "jwt"):
+		return "auth"
+	case strings.Contains(name, "decompress"):
+		return "decompress"
+	case strings.Contains(name, "gzip") || strings.Contains(name, "compress"):
+		return "gzip"
+	case strings.Contains(name, "bodydump"):
+		return "bodydump"
+	case strings.Contains(name, "bodylimit"):
+		return "bodylimit"
+	}
+	return ""
+}
+
+// lintMiddleware checks global middleware chain and chains of routes with group or route level middlewares.
+func (e *Echo) lintMiddleware() []Warning {
+	var warnings []Warning
+	seen := map[Warning]struct{}{}
+	add := func(w Warning) {
+		if _, ok := seen[w]; !ok {
+			seen[w] = struct{}{}
+			warnings = append(warnings, w)
+		}
+	}
+
+	lint := func(chain []MiddlewareInfo) {
+		index := map[string]int{}
+		firstIndex := -1 // first middleware executed after routing, loggers excluded
+		for i, m := range chain {
+			kind := lintMiddlewareKind(m.Name)
+			if firstIndex == -1 && m.Level != MiddlewareLevelPre && kind != "logger" && kind != "recover" {
+				firstIndex = i
+			}
+			switch kind {
+			case "recover":
+				if firstIndex != -1 {
+					add(Warning{
+						Code:     LintRecoverNotFirst,
+						Severity: SeverityError,
+						Message: fmt.Sprintf("%s middleware (%s) is registered after %s, panics in preceding middlewares are not recovered",
+							m.Name, m.Level, chain[firstIndex].Name),
+						Hint: "Register Recover with Echo#Use before other middlewares, only loggers should precede it.",
+					})
+				}
+			case "logger":
+				if r, ok := index["recover"]; ok {
+					add(Warning{
+						Code:     LintLoggerAfterRecover,
+						Severity: SeverityWarning,
+						Message: fmt.Sprintf("%s middleware (%s) is registered after %s, requests that panic are not logged",
+							m.Name, m.Level, chain[r].Name),
+						Hint: "Register logger middleware before Recover.",
+					})
+				}
+			case "cors":
+				if a, ok := index["auth"]; ok {
+					add(Warning{
+						Code:     LintCORSAfterAuth,
+						Severity: SeverityError,
+						Message: fmt.Sprintf("%s middleware (%s) is registered after %s, CORS preflight requests will be rejected",
+							m.Name, m.Level, chain[a].Name),
+						Hint: "Register CORS before authentication middlewares.",
+					})
+				}
+			case "gzip":
+				if d, ok := index["bodydump"]; ok {
+					add(Warning{
+						Code:     LintBodyDumpBeforeGzip,
+						Severity: SeverityWarning,
+						Message: fmt.Sprintf("%s middleware (%s) is registered after %s, dumped response bodies are compressed",
+							m.Name, m.Level, chain[d].Name),
+						Hint: "Register Gzip before BodyDump so BodyDump sees uncompressed response body.",
+					})
+				}
+			case "decompress":
+				if l, ok := index["bodylimit"]; ok {
+					add(Warning{
+						Code:     LintBodyLimitBeforeDecompress,
+						Severity: SeverityError,
+						Message: fmt.Sprintf("%s middleware (%s) is registered after %s, body limit applies to compressed body",
+							m.Name, m.Level, chain[l].Name),
+						Hint: "Register Decompress before BodyLimit so decompressed body is limited.",
+					})
+				}
+			}
+			if _, ok := index[kind]; !ok && kind != "" {
+				index[kind] = i
+			}
+		}
+	}
+
+	global := append(middlewareInfos(MiddlewareLevelPre, e.premiddleware), middlewareInfos(MiddlewareLevelGlobal, e.middleware)...)
+	lint(global)
+	for _, route := range sortedRoutes(e.Routes()) {
+		if chain := e.routeMiddlewares[route]; len(chain) > 0 {
+			lint(e.middlewareChain(route))
+		}
+	}
+	return warnings
+}
+
+// lintRoutes checks routes of all routers for shadowed routes and CORS preflight requests that can not be routed.
+func (e *Echo) lintRoutes() []Warning {
+	routers := []*Router{e.router}
+	hosts := make([]string, 0, len(e.routers))
+	for host := range e.routers {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		routers = append(routers, e.routers[host])
+	}
+
+	var warnings []Warning
+	for _, router := range routers {
+		routes := sortedRoutes(router.Routes())
+		shapes := map[string][]*Route{}
+		for _, route := range routes {
+			key := route.Method + " " + routeShape(route.Path)
+			shapes[key] = append(shapes[key], route)
+		}
+		for _, route := range routes {
+			if same := shapes[route.Method+" "+routeShape(route.Path)]; len(same) > 1 && same[0] == route {
+				paths := make([]string, 0, len(same))
+				for _, r := range same {
+					paths = append(paths, r.Path)
+				}
+				warnings = append(warnings, Warning{
+					Code:     LintRouteShadowed,
+					Severity: SeverityError,
+					Message: fmt.Sprintf("%s routes %s differ only by parameter names, only the last registered route is reachable",
+						route.Method, strings.Join(paths, ", ")),
+					Hint: "Use the same path for these routes and dispatch in the handler, or remove the unreachable route.",
+				})
+			}
+		}
+
+		for _, route := range routes {
+			if route.Method == http.MethodOptions || route.Method == RouteNotFound || !e.hasRouteLevelCORS(route) {
+				continue
+			}
+			if _, ok := router.routes[http.MethodOptions+route.Path]; ok {
+				continue
+			}
+			warnings = append(warnings, Warning{
+				Code:     LintCORSPreflightUnroutable,
+				Severity: SeverityError,
+				Message: fmt.Sprintf("CORS middleware of route %s %s is not executed for preflight requests because there is no OPTIONS route for the path",
+					route.Method, route.Path),
+				Hint: "Register CORS with Echo#Use or Group#Use, or add OPTIONS route with the same middleware.",
+			})
+		}
+	}
+	return warnings
+}
+
+// hasRouteLevelCORS reports whether route has CORS middleware only at route level.
+func (e *Echo) hasRouteLevelCORS(route *Route) bool {
+	found := false
+	for _, m := range e.middlewareChain(route) {
+		if lintMiddlewareKind(m.Name) != "cors" {
+			continue
+		}
+		if m.Level != MiddlewareLevelRoute {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// routeShape returns route path with parameter names removed. Routes with the same shape are matched by the same
+// router node.
+func routeShape(path string) string {
+	var sb strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+1 < len(path) && path[i+1] == ':' {
+			sb.WriteString(`\:`)
+			i++
+			continue
+		}
+		sb.WriteByte(path[i])
+		if path[i] == ':' {
+			for i+1 < len(path) && path[i+1] != '/' {
+				i++
+			}
+		}
+	}
+	return sb.String()
+}
+
+func sortedRoutes(routes []*Route) []*Route {
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// lintConfig checks recorded group prefixes and static roots.
+func (e *Echo) lintConfig() []Warning {
+	s := &e.lintState
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var warnings []Warning
+	seen := map[string]struct{}{}
+	for _, prefix := range s.groupPrefixes {
+		if _, ok := seen[prefix]; ok || !strings.HasSuffix(prefix, "/") {
+			continue
+		}
+		seen[prefix] = struct{}{}
+		warnings = append(warnings, Warning{
+			Code:     LintGroupPrefixTrailingSlash,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("group prefix %s ends with slash, paths of its routes contain double slash (ala %susers)", prefix, prefix+"/"),
+			Hint:     "Remove trailing slash from the group prefix, route paths start with slash.",
+		})
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return warnings
+	}
+	for _, root := range s.staticRoots {
+		rel, err := filepath.Rel(wd, root.dir)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		warnings = append(warnings, Warning{
+			Code:     LintStaticRootOutsideWorkDir,
+			Severity: SeverityWarning,
+			Message:  fmt.Sprintf("static route %s serves directory %s outside the working directory %s", root.path, root.dir, wd),
+			Hint:     "Check that the directory is meant to be public, or use Echo#StaticFS with explicit file system.",
+		})
+	}
+	return warnings
+}
+
+// lintRuntime returns warnings about misconfigurations detected while serving requests.
+func (e *Echo) lintRuntime() []Warning {
+	var warnings []Warning
+	if n := e.lintState.validateWithoutValidator.Load(); n > 0 {
+		warnings = append(warnings, Warning{
+			Code:     LintValidatorNotRegistered,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("Context#Validate has been called %d times without registered Validator", n),
+			Hint:     "Set Echo#Validator.",
+		})
+	}
+	return warnings
+}
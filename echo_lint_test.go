@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lintCodes(warnings []Warning) []string {
+	codes := make([]string, 0, len(warnings))
+	for _, w := range warnings {
+		codes = append(codes, w.Code)
+	}
+	return codes
+}
+
+func TestEcho_Lint_clean(t *testing.T) {
+	e := New()
+	e.Use(NamedMiddleware("logger", testChainMiddleware))
+	e.Use(NamedMiddleware("recover", testChainMiddleware))
+	e.Use(NamedMiddleware("cors", testChainMiddleware))
+	e.Use(NamedMiddleware("gzip", testChainMiddleware))
+	e.Use(NamedMiddleware("body-dump", testChainMiddleware))
+	e.Use(NamedMiddleware("decompress", testChainMiddleware))
+	e.Use(NamedMiddleware("body-limit", testChainMiddleware))
+	api := e.Group("/api", NamedMiddleware("key-auth", testChainMiddleware))
+	api.GET("/users/:id", handlerFunc)
+	api.GET("/users/:id/groups", handlerFunc)
+	e.Static("/assets", ".")
+
+	assert.Empty(t, e.Lint())
+}
+
+func TestEcho_Lint_middlewares(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenNames  []string
+		expectCodes []string
+	}{
+		{
+			name:        "recover not first",
+			givenNames:  []string{"request-id", "recover"},
+			expectCodes: []string{LintRecoverNotFirst},
+		},
+		{
+			name:        "logger after recover",
+			givenNames:  []string{"recover", "request-logger"},
+			expectCodes: []string{LintLoggerAfterRecover},
+		},
+		{
+			name:        "body dump before gzip",
+			givenNames:  []string{"recover", "middleware.BodyDumpWithConfig", "middleware.GzipWithConfig"},
+			expectCodes: []string{LintBodyDumpBeforeGzip},
+		},
+		{
+			name:        "body limit before decompress",
+			givenNames:  []string{"recover", "middleware.BodyLimit", "middleware.Decompress"},
+			expectCodes: []string{LintBodyLimitBeforeDecompress},
+		},
+		{
+			name:        "cors after auth",
+			givenNames:  []string{"recover", "jwt", "cors"},
+			expectCodes: []string{LintCORSAfterAuth},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			for _, name := range tc.givenNames {
+				e.Use(NamedMiddleware(name, testChainMiddleware))
+			}
+			assert.Equal(t, tc.expectCodes, lintCodes(e.Lint()))
+		})
+	}
+}
+
+func TestEcho_Lint_routes(t *testing.T) {
+	e := New()
+	e.GET("/users/:id", handlerFunc)
+	e.GET("/users/:name", handlerFunc)
+	e.POST("/users/:name", handlerFunc)
+	e.GET("/items", handlerFunc, NamedMiddleware("cors", testChainMiddleware))
+	e.GET("/orders", handlerFunc, NamedMiddleware("cors", testChainMiddleware))
+	e.OPTIONS("/orders", handlerFunc, NamedMiddleware("cors", testChainMiddleware))
+	e.GET(`/time/\:id`, handlerFunc)
+	e.GET("/time/:id", handlerFunc)
+
+	assert.Equal(t, []Warning{
+		{
+			Code:     LintRouteShadowed,
+			Severity: SeverityError,
+			Message:  "GET routes /users/:id, /users/:name differ only by parameter names, only the last registered route is reachable",
+			Hint:     "Use the same path for these routes and dispatch in the handler, or remove the unreachable route.",
+		},
+		{
+			Code:     LintCORSPreflightUnroutable,
+			Severity: SeverityError,
+			Message:  "CORS middleware of route GET /items is not executed for preflight requests because there is no OPTIONS route for the path",
+			Hint:     "Register CORS with Echo#Use or Group#Use, or add OPTIONS route with the same middleware.",
+		},
+	}, e.Lint())
+}
+
+func TestEcho_Lint_config(t *testing.T) {
+	e := New()
+	api := e.Group("/api/")
+	api.Group("/v1")
+	e.Group("/admin")
+	e.Static("/files", "..")
+	e.Static("/assets", "_fixture")
+
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.Equal(t, []Warning{
+		{
+			Code:     LintGroupPrefixTrailingSlash,
+			Severity: SeverityWarning,
+			Message:  "group prefix /api/ ends with slash, paths of its routes contain double slash (ala /api//users)",
+			Hint:     "Remove trailing slash from the group prefix, route paths start with slash.",
+		},
+		{
+			Code:     LintStaticRootOutsideWorkDir,
+			Severity: SeverityWarning,
+			Message:  "static route /files* serves directory " + filepath.Dir(wd) + " outside the working directory " + wd,
+			Hint:     "Check that the directory is meant to be public, or use Echo#StaticFS with explicit file system.",
+		},
+	}, e.Lint())
+}
+
+func TestEcho_Lint_validatorNotRegistered(t *testing.T) {
+	e := New()
+	e.POST("/", func(c Context) error {
+		return c.Validate(struct{}{})
+	})
+	assert.Empty(t, e.Lint())
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	}
+
+	warnings := e.Lint()
+	assert.Equal(t, []string{LintValidatorNotRegistered}, lintCodes(warnings))
+	assert.Equal(t, "echo: error [validator-not-registered]: Context#Validate has been called 2 times without registered Validator. Set Echo#Validator.", warnings[0].String())
+}
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrMaintenanceMode is set as internal error of the "503 - Service Unavailable" error that is returned for requests
+// while maintenance mode is enabled. Custom HTTPErrorHandler can use it to render a maintenance page.
+var ErrMaintenanceMode = errors.New("maintenance mode")
+
+// MaintenanceConfig defines the config for maintenance mode.
+type MaintenanceConfig struct {
+	// AllowPathPrefixes is list of request path prefixes that are served normally during maintenance (ala `/health`).
+	AllowPathPrefixes []string
+
+	// AllowRouteNames is list of route names that are served normally during maintenance (ala route that toggles
+	// maintenance mode off).
+	AllowRouteNames []string
+
+	// RetryAfter is sent as `Retry-After` header value in seconds. Header is not sent when value is 0.
+	RetryAfter time.Duration
+
+	// Message is used as message of returned HTTPError and is rendered by HTTPErrorHandler.
+	// Optional. Default value is "Service Unavailable".
+	Message interface{}
+}
+
+type maintenanceMode struct {
+	config     MaintenanceConfig
+	routeNames map[string]struct{}
+	retryAfter string
+	err        *HTTPError
+}
+
+// SetMaintenanceMode enables or disables maintenance mode. While maintenance mode is enabled requests to routes that
+// are not allowed by the config are served by handler returning "503 - Service Unavailable" error with `Retry-After`
+// header. The check is done right after routing so middlewares and HTTPErrorHandler still apply to the response.
+// Config is ignored when maintenance mode is disabled.
+//
+// It is safe to toggle maintenance mode while server is running.
+func (e *Echo) SetMaintenanceMode(enabled bool, config MaintenanceConfig) {
+	if !enabled {
+		e.maintenance.Store(nil)
+		return
+	}
+	m := &maintenanceMode{
+		config:     config,
+		routeNames: make(map[string]struct{}, len(config.AllowRouteNames)),
+		err:        NewHTTPError(http.StatusServiceUnavailable).WithInternal(ErrMaintenanceMode),
+	}
+	if config.Message != nil {
+		m.err.Message = config.Message
+	}
+	for _, name := range config.AllowRouteNames {
+		m.routeNames[name] = struct{}{}
+	}
+	if config.RetryAfter > 0 {
+		m.retryAfter = strconv.FormatInt(int64((config.RetryAfter+time.Second-1)/time.Second), 10)
+	}
+	e.maintenance.Store(m)
+}
+
+// IsMaintenanceMode returns true when maintenance mode is enabled.
+func (e *Echo) IsMaintenanceMode() bool {
+	return e.maintenance.Load() != nil
+}
+
+// applyMaintenanceMode replaces matched route handler when maintenance mode is enabled and the route is not allowed.
+func (e *Echo) applyMaintenanceMode(router *Router, c *context) {
+	m := e.maintenance.Load()
+	if m == nil || m.allows(router, c) {
+		return
+	}
+	c.handler = func(c Context) error {
+		if m.retryAfter != "" {
+			c.Response().Header().Set(HeaderRetryAfter, m.retryAfter)
+		}
+		return m.err
+	}
+}
+
+func (m *maintenanceMode) allows(router *Router, c *context) bool {
+	path := c.request.URL.Path
+	for _, prefix := range m.config.AllowPathPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	if len(m.routeNames) == 0 {
+		return false
+	}
+	if route, ok := router.routes[c.request.Method+c.path]; ok {
+		_, ok = m.routeNames[route.Name]
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEcho_SetMaintenanceMode(t *testing.T) {
+	e := New()
+	e.GET("/health", func(c Context) error {
+		return c.String(http.StatusOK, "healthy")
+	})
+	e.POST("/admin/maintenance", func(c Context) error {
+		return c.String(http.StatusOK, "toggled")
+	}).Name = "maintenance-toggle"
+	e.GET("/users", func(c Context) error {
+		return c.String(http.StatusOK, "users")
+	})
+
+	e.SetMaintenanceMode(true, MaintenanceConfig{
+		AllowPathPrefixes: []string{"/health"},
+		AllowRouteNames:   []string{"maintenance-toggle"},
+		RetryAfter:        1500 * time.Millisecond,
+	})
+	assert.True(t, e.IsMaintenanceMode())
+
+	var testCases = []struct {
+		name             string
+		whenMethod       string
+		whenURL          string
+		expectStatus     int
+		expectBody       string
+		expectRetryAfter string
+	}{
+		{
+			name:             "nok, route in maintenance",
+			whenMethod:       http.MethodGet,
+			whenURL:          "/users",
+			expectStatus:     http.StatusServiceUnavailable,
+			expectBody:       "{\"message\":\"Service Unavailable\"}\n",
+			expectRetryAfter: "2",
+		},
+		{
+			name:             "nok, not found route in maintenance",
+			whenMethod:       http.MethodGet,
+			whenURL:          "/unknown",
+			expectStatus:     http.StatusServiceUnavailable,
+			expectBody:       "{\"message\":\"Service Unavailable\"}\n",
+			expectRetryAfter: "2",
+		},
+		{
+			name:         "ok, allowed path prefix",
+			whenMethod:   http.MethodGet,
+			whenURL:      "/health",
+			expectStatus: http.StatusOK,
+			expectBody:   "healthy",
+		},
+		{
+			name:         "ok, allowed route name",
+			whenMethod:   http.MethodPost,
+			whenURL:      "/admin/maintenance",
+			expectStatus: http.StatusOK,
+			expectBody:   "toggled",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.whenMethod, tc.whenURL, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectStatus, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+			assert.Equal(t, tc.expectRetryAfter, rec.Header().Get(HeaderRetryAfter))
+		})
+	}
+
+	e.SetMaintenanceMode(false, MaintenanceConfig{})
+	assert.False(t, e.IsMaintenanceMode())
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestEcho_SetMaintenanceMode_customErrorHandler(t *testing.T) {
+	e := New()
+	e.HTTPErrorHandler = func(err error, c Context) {
+		if errors.Is(err, ErrMaintenanceMode) {
+			_ = c.HTML(http.StatusServiceUnavailable, "<h1>back soon</h1>")
+			return
+		}
+		e.DefaultHTTPErrorHandler(err, c)
+	}
+	e.GET("/", func(c Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+	e.SetMaintenanceMode(true, MaintenanceConfig{Message: "ignored by custom handler"})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "<h1>back soon</h1>", rec.Body.String())
+	assert.Equal(t, "", rec.Header().Get(HeaderRetryAfter))
+}
@@ -0,0 +1,247 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// Middleware levels reported by `Echo#MiddlewareChain`.
+const (
+	// MiddlewareLevelPre is level of middlewares added with `Echo#Pre`. These are executed before routing.
+	MiddlewareLevelPre = "pre"
+	// MiddlewareLevelGlobal is level of middlewares added with `Echo#Use`.
+	MiddlewareLevelGlobal = "global"
+	// MiddlewareLevelGroup is level of middlewares added to the Group (including parent groups).
+	MiddlewareLevelGroup = "group"
+	// MiddlewareLevelRoute is level of middlewares added when route is registered.
+	MiddlewareLevelRoute = "route"
+	// MiddlewareLevelRouteOption is level of route options (see `RouteOption`) that process the response (ala
+	// `RouteResponseHeaders`). Options that do not process the response are not reported.
+	MiddlewareLevelRouteOption = "route_option"
+)
+
+// MiddlewareInfo describes middleware in effective middleware chain of the route.
+type MiddlewareInfo struct {
+	// Name is name given with `NamedMiddleware` or name derived from the function that created the middleware (ala
+	// `middleware.CORSWithConfig`).
+	Name string `json:"name"`
+	// Level is one of `pre`, `global`, `group`, `route` or `route_option`.
+	Level string `json:"level"`
+}
+
+// middlewareNameProbe is passed to handler returned by named middleware to get its name.
+type middlewareNameProbe struct {
+	Context
+	mark *middlewareMark
+}
+
+// middlewareMark is returned (as method value) by named and RequireRoute middlewares when they are called with nil
+// next handler.
+type middlewareMark struct {
+	name          string
+	requiresRoute bool
+	// routeOption applies setting of the route option (see `RouteOption`). Nil for other middlewares.
+	routeOption func(o *routeOptions)
+}
+
+func (m *middlewareMark) handle(c Context) error {
+	if p, ok := c.(*middlewareNameProbe); ok {
+		p.mark = m
+	}
+	return nil
+}
+
+// NamedMiddleware gives middleware a name that is reported by `Echo#MiddlewareChain`.
+//
+// Example: `e.Use(echo.NamedMiddleware("cors", middleware.CORS()))`
+func NamedMiddleware(name string, middleware MiddlewareFunc) MiddlewareFunc {
+	mark := &middlewareMark{name: name}
+	if m := markOf(middleware); m != nil {
+		mark.requiresRoute = m.requiresRoute
+		mark.routeOption = m.routeOption
+	}
+	return markedMiddleware(mark, middleware)
+}
+
+// RequireRoute marks middleware that depends on the matched route (ala middleware using `Context#RouteName` or
+// `Context#Path`). `Echo#Pre` panics when marked middleware is added because pre-middlewares are executed before
+// routing.
+func RequireRoute(middleware MiddlewareFunc) MiddlewareFunc {
+	return markedMiddleware(&middlewareMark{name: middlewareName(middleware), requiresRoute: true}, middleware)
+}
+
+// markedMiddlewarePC is code pointer shared by all middlewares created with markedMiddleware. It is used to recognize
+// marked middlewares without calling other middlewares.
+var markedMiddlewarePC = reflect.ValueOf(markedMiddleware(nil, nil)).Pointer()
+
+// markedMiddleware must not be inlined so all middlewares created by it share the code of the same closure.
+//
+//go:noinline
+func markedMiddleware(mark *middlewareMark, middleware MiddlewareFunc) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		if next == nil {
+			return mark.handle
+		}
+		if middleware == nil {
+			return next // route option
+		}
+		return middleware(next)
+	}
+}
+
+// markOf returns mark of the middleware created with NamedMiddleware or RequireRoute. Returns nil for other
+// middlewares, these are never called.
+func markOf(m MiddlewareFunc) *middlewareMark {
+	if m == nil || reflect.ValueOf(m).Pointer() != markedMiddlewarePC {
+		return nil
+	}
+	probe := &middlewareNameProbe{}
+	_ = m(nil)(probe)
+	return probe.mark
+}
+
+// requiresRoute reports whether middleware is marked with RequireRoute.
+func requiresRoute(m MiddlewareFunc) bool {
+	mark := markOf(m)
+	return mark != nil && mark.requiresRoute
+}
+
+// middlewareName returns name of the middleware given with NamedMiddleware or derived from the function name.
+func middlewareName(m MiddlewareFunc) string {
+	if mark := markOf(m); mark != nil {
+		return mark.name
+	}
+	name := runtime.FuncForPC(reflect.ValueOf(m).Pointer()).Name()
+	// `github.com/labstack/echo/v4/middleware.CORSWithConfig.func1` -> `middleware.CORSWithConfig`
+	// `github.com/labstack/echo/v4.WrapMiddleware.func1` -> `echo.WrapMiddleware`
+	if i := strings.LastIndexByte(name, '/'); i != -1 {
+		pkg := name[:i]
+		name = name[i+1:]
+		if dot := strings.IndexByte(name, '.'); dot != -1 && isMajorVersion(name[:dot]) {
+			name = pkg[strings.LastIndexByte(pkg, '/')+1:] + name[dot:]
+		}
+	}
+	for {
+		i := strings.LastIndexByte(name, '.')
+		if i == -1 || !strings.HasPrefix(name[i+1:], "func") {
+			break
+		}
+		name = name[:i]
+	}
+	return name
+}
+
+// isMajorVersion reports whether package path element is major version suffix (ala `v4`).
+func isMajorVersion(element string) bool {
+	if len(element) < 2 || element[0] != 'v' {
+		return false
+	}
+	for _, r := range element[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func middlewareInfos(level string, middlewares []MiddlewareFunc) []MiddlewareInfo {
+	result := make([]MiddlewareInfo, 0, len(middlewares))
+	for _, m := range middlewares {
+		result = append(result, MiddlewareInfo{Name: middlewareName(m), Level: level})
+	}
+	return result
+}
+
+// recordRouteMiddlewares stores group and route level middlewares and route options of the route for
+// `Echo#MiddlewareChain`.
+func (e *Echo) recordRouteMiddlewares(route *Route, groupMiddlewares, routeMiddlewares []MiddlewareFunc, options []RouteOption) {
+	if e.routeMiddlewares == nil {
+		e.routeMiddlewares = map[*Route][]MiddlewareInfo{}
+	}
+	infos := middlewareInfos(MiddlewareLevelGroup, groupMiddlewares)
+	infos = append(infos, middlewareInfos(MiddlewareLevelRoute, routeMiddlewares)...)
+	for _, option := range options {
+		if name := middlewareName(option); name != "" {
+			infos = append(infos, MiddlewareInfo{Name: name, Level: MiddlewareLevelRouteOption})
+		}
+	}
+	e.routeMiddlewares[route] = infos
+}
+
+// MiddlewareChain returns effective ordered list of middlewares (pre-router, global, group, route) that are executed
+// for the route with given name. Returns nil when route is not found.
+func (e *Echo) MiddlewareChain(routeName string) []MiddlewareInfo {
+	route := e.findRouteByName(routeName)
+	if route == nil {
+		return nil
+	}
+	return e.middlewareChain(route)
+}
+
+func (e *Echo) middlewareChain(route *Route) []MiddlewareInfo {
+	result := middlewareInfos(MiddlewareLevelPre, e.premiddleware)
+	result = append(result, middlewareInfos(MiddlewareLevelGlobal, e.middleware)...)
+	return append(result, e.routeMiddlewares[route]...)
+}
+
+func (e *Echo) findRouteByName(name string) *Route {
+	for _, route := range e.router.routes {
+		if route.Name == name {
+			return route
+		}
+	}
+	for _, router := range e.routers {
+		for _, route := range router.routes {
+			if route.Name == name {
+				return route
+			}
+		}
+	}
+	return nil
+}
+
+// MiddlewareChainHandler returns debug handler that renders effective middleware chains of all routes as JSON.
+// Query parameter `route` limits output to the route with given name.
+//
+// Example: `e.GET("/debug/middlewares", e.MiddlewareChainHandler())`
+func (e *Echo) MiddlewareChainHandler() HandlerFunc {
+	type routeChain struct {
+		Method      string           `json:"method"`
+		Path        string           `json:"path"`
+		Name        string           `json:"name"`
+		Middlewares []MiddlewareInfo `json:"middlewares"`
+	}
+	return func(c Context) error {
+		name := c.QueryParam("route")
+		result := make([]routeChain, 0)
+		for _, route := range e.Routes() {
+			if route.Method == RouteNotFound || (name != "" && route.Name != name) {
+				continue
+			}
+			result = append(result, routeChain{
+				Method:      route.Method,
+				Path:        route.Path,
+				Name:        route.Name,
+				Middlewares: e.middlewareChain(route),
+			})
+		}
+		return c.JSON(http.StatusOK, result)
+	}
+}
+
+// LintMiddleware checks middleware chains of all routes for classic ordering mistakes and returns messages of
+// warnings.
+//
+// Deprecated: use `Echo#Lint` that also checks routes and configuration and returns warnings with codes and hints.
+func (e *Echo) LintMiddleware() []string {
+	var result []string
+	for _, w := range e.lintMiddleware() {
+		result = append(result, "echo: "+w.Message)
+	}
+	return result
+}
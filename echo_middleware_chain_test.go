@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testChainMiddleware(next HandlerFunc) HandlerFunc {
+	return next
+}
+
+func testChainMiddlewareWithConfig() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			return next(c)
+		}
+	}
+}
+
+func TestEcho_MiddlewareChain(t *testing.T) {
+	e := New()
+	e.Pre(NamedMiddleware("trailing-slash", testChainMiddleware))
+	e.Use(testChainMiddleware)
+	g := e.Group("/api", NamedMiddleware("auth", testChainMiddleware))
+	sub := g.Group("/v1", testChainMiddlewareWithConfig())
+	sub.WithRouteOptions(RouteSLO(time.Second)).GET("/users", handlerFunc, NamedMiddleware("cache", testChainMiddleware)).Name = "users"
+	sub.WithRouteOptions(RouteResponseHeaders(map[string]string{"X-API-Version": "1"})).GET("/headers", handlerFunc).Name = "headers"
+	e.GET("/plain", handlerFunc).Name = "plain"
+
+	assert.Equal(t, []MiddlewareInfo{
+		{Name: "trailing-slash", Level: MiddlewareLevelPre},
+		{Name: "echo.testChainMiddleware", Level: MiddlewareLevelGlobal},
+		{Name: "auth", Level: MiddlewareLevelGroup},
+		{Name: "echo.testChainMiddlewareWithConfig", Level: MiddlewareLevelGroup},
+		{Name: "cache", Level: MiddlewareLevelRoute},
+	}, e.MiddlewareChain("users"))
+	assert.Equal(t, []MiddlewareInfo{
+		{Name: "trailing-slash", Level: MiddlewareLevelPre},
+		{Name: "echo.testChainMiddleware", Level: MiddlewareLevelGlobal},
+	}, e.MiddlewareChain("plain"))
+	assert.Equal(t, []MiddlewareInfo{
+		{Name: "trailing-slash", Level: MiddlewareLevelPre},
+		{Name: "echo.testChainMiddleware", Level: MiddlewareLevelGlobal},
+		{Name: "auth", Level: MiddlewareLevelGroup},
+		{Name: "echo.testChainMiddlewareWithConfig", Level: MiddlewareLevelGroup},
+		{Name: "echo.RouteResponseHeaders", Level: MiddlewareLevelRouteOption},
+	}, e.MiddlewareChain("headers"))
+	assert.Nil(t, e.MiddlewareChain("unknown"))
+
+	// named middleware still works
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/users", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestEcho_MiddlewareChainHandler(t *testing.T) {
+	e := New()
+	e.Use(NamedMiddleware("logger", testChainMiddleware))
+	e.GET("/a", handlerFunc).Name = "a"
+	e.GET("/b", handlerFunc, NamedMiddleware("gzip", testChainMiddleware)).Name = "b"
+	e.GET("/debug/middlewares", e.MiddlewareChainHandler())
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/middlewares?route=b", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var result []struct {
+		Method      string           `json:"method"`
+		Path        string           `json:"path"`
+		Middlewares []MiddlewareInfo `json:"middlewares"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Len(t, result, 1)
+	assert.Equal(t, "/b", result[0].Path)
+	assert.Equal(t, []MiddlewareInfo{
+		{Name: "logger", Level: MiddlewareLevelGlobal},
+		{Name: "gzip", Level: MiddlewareLevelRoute},
+	}, result[0].Middlewares)
+}
+
+func TestEcho_LintMiddleware(t *testing.T) {
+	e := New()
+	e.Pre(NamedMiddleware("trailing-slash", testChainMiddleware))
+	e.Use(NamedMiddleware("recover", testChainMiddleware))
+	assert.Empty(t, e.LintMiddleware())
+
+	e = New()
+	e.Use(NamedMiddleware("logger", testChainMiddleware))
+	e.Use(NamedMiddleware("request-id", testChainMiddleware))
+	e.Use(NamedMiddleware("recover", testChainMiddleware))
+	g := e.Group("/api", NamedMiddleware("key-auth", testChainMiddleware))
+	g.GET("/", handlerFunc, NamedMiddleware("cors", testChainMiddleware))
+	g.GET("/other", handlerFunc, NamedMiddleware("cors", testChainMiddleware))
+
+	assert.Equal(t, []string{
+		"echo: recover middleware (global) is registered after request-id, panics in preceding middlewares are not recovered",
+		"echo: cors middleware (route) is registered after key-auth, CORS preflight requests will be rejected",
+	}, e.LintMiddleware())
+}
+
+func TestRequireRoute(t *testing.T) {
+	e := New()
+	mw := RequireRoute(testChainMiddleware)
+	assert.True(t, requiresRoute(mw))
+	assert.False(t, requiresRoute(testChainMiddleware))
+	assert.Equal(t, "echo.testChainMiddleware", middlewareName(mw))
+
+	named := NamedMiddleware("route-aware", mw)
+	assert.True(t, requiresRoute(named))
+	assert.Equal(t, "route-aware", middlewareName(named))
+
+	assert.PanicsWithError(t, "echo: middleware route-aware requires matched route and can not be added with Echo#Pre", func() {
+		e.Pre(named)
+	})
+	assert.Empty(t, e.premiddleware)
+
+	e.Use(mw)
+	e.GET("/", handlerFunc).Name = "root"
+	assert.Equal(t, []MiddlewareInfo{
+		{Name: "echo.testChainMiddleware", Level: MiddlewareLevelGlobal},
+	}, e.MiddlewareChain("root"))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestEcho_Pre_doesNotCallMiddleware(t *testing.T) {
+	e := New()
+	called := 0
+	mw := func(next HandlerFunc) HandlerFunc {
+		called++
+		return func(c Context) error {
+			return next(c)
+		}
+	}
+
+	e.Pre(mw, NamedMiddleware("named", mw))
+	e.GET("/", handlerFunc).Name = "root"
+	assert.Equal(t, []MiddlewareInfo{
+		{Name: "echo.TestEcho_Pre_doesNotCallMiddleware", Level: MiddlewareLevelPre},
+		{Name: "named", Level: MiddlewareLevelPre},
+	}, e.MiddlewareChain("root"))
+	assert.Equal(t, 0, called)
+}
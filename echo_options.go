@@ -0,0 +1,193 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"errors"
+	"time"
+)
+
+// Option configures Echo instance created with `NewWithOptions`. Options set the same exported fields that can be
+// mutated after `New()` so both ways of configuration can be mixed.
+type Option func(e *Echo)
+
+// NewWithOptions creates an instance of Echo, applies given options and validates the resulting configuration.
+//
+// Example:
+//
+//	e, err := echo.NewWithOptions(
+//		echo.WithDebug(true),
+//		echo.WithIPExtractor(echo.ExtractIPFromXFFHeader()),
+//	)
+func NewWithOptions(options ...Option) (*Echo, error) {
+	e := New()
+	for _, opt := range options {
+		opt(e)
+	}
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Validate checks Echo instance configuration for invalid values and combinations. It is useful to call it before
+// starting the server when fields have been modified after `New()`.
+func (e *Echo) Validate() error {
+	var errs []error
+	if e.Binder == nil {
+		errs = append(errs, errors.New("echo: binder must not be nil"))
+	}
+	if e.JSONSerializer == nil {
+		errs = append(errs, errors.New("echo: JSON serializer must not be nil"))
+	}
+	if e.HTTPErrorHandler == nil {
+		errs = append(errs, errors.New("echo: HTTP error handler must not be nil"))
+	}
+	if e.Logger == nil {
+		errs = append(errs, errors.New("echo: logger must not be nil"))
+	}
+	if e.ListenerNetwork != "tcp" && e.ListenerNetwork != "tcp4" && e.ListenerNetwork != "tcp6" {
+		errs = append(errs, ErrInvalidListenerNetwork)
+	}
+	if e.MaxMultipartMemory < 0 {
+		errs = append(errs, errors.New("echo: max multipart memory must not be negative"))
+	}
+	if err := e.validateServerLimits(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// WithBinder sets `Echo#Binder`.
+func WithBinder(binder Binder) Option {
+	return func(e *Echo) {
+		e.Binder = binder
+	}
+}
+
+// WithJSONSerializer sets `Echo#JSONSerializer`.
+func WithJSONSerializer(serializer JSONSerializer) Option {
+	return func(e *Echo) {
+		e.JSONSerializer = serializer
+	}
+}
+
+// WithValidator sets `Echo#Validator`.
+func WithValidator(validator Validator) Option {
+	return func(e *Echo) {
+		e.Validator = validator
+	}
+}
+
+// WithRenderer sets `Echo#Renderer`.
+func WithRenderer(renderer Renderer) Option {
+	return func(e *Echo) {
+		e.Renderer = renderer
+	}
+}
+
+// WithLogger sets `Echo#Logger`.
+func WithLogger(logger Logger) Option {
+	return func(e *Echo) {
+		e.Logger = logger
+	}
+}
+
+// WithHTTPErrorHandler sets `Echo#HTTPErrorHandler`.
+func WithHTTPErrorHandler(handler HTTPErrorHandler) Option {
+	return func(e *Echo) {
+		e.HTTPErrorHandler = handler
+	}
+}
+
+// WithIPExtractor sets `Echo#IPExtractor`.
+func WithIPExtractor(extractor IPExtractor) Option {
+	return func(e *Echo) {
+		e.IPExtractor = extractor
+	}
+}
+
+// WithListenerNetwork sets `Echo#ListenerNetwork`.
+func WithListenerNetwork(network string) Option {
+	return func(e *Echo) {
+		e.ListenerNetwork = network
+	}
+}
+
+// WithMaxMultipartMemory sets `Echo#MaxMultipartMemory`.
+func WithMaxMultipartMemory(maxMemory int64) Option {
+	return func(e *Echo) {
+		e.MaxMultipartMemory = maxMemory
+	}
+}
+
+// WithMaxHeaderBytes sets `Echo#MaxHeaderBytes`.
+func WithMaxHeaderBytes(maxBytes int) Option {
+	return func(e *Echo) {
+		e.MaxHeaderBytes = maxBytes
+	}
+}
+
+// WithReadHeaderTimeout sets `Echo#ReadHeaderTimeout`.
+func WithReadHeaderTimeout(timeout time.Duration) Option {
+	return func(e *Echo) {
+		e.ReadHeaderTimeout = timeout
+	}
+}
+
+// WithReadTimeout sets `Echo#ReadTimeout`.
+func WithReadTimeout(timeout time.Duration) Option {
+	return func(e *Echo) {
+		e.ReadTimeout = timeout
+	}
+}
+
+// WithWriteTimeout sets `Echo#WriteTimeout`.
+func WithWriteTimeout(timeout time.Duration) Option {
+	return func(e *Echo) {
+		e.WriteTimeout = timeout
+	}
+}
+
+// WithIdleTimeout sets `Echo#IdleTimeout`.
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(e *Echo) {
+		e.IdleTimeout = timeout
+	}
+}
+
+// WithUseEncodedPath sets `Echo#UseEncodedPath`.
+func WithUseEncodedPath(useEncodedPath bool) Option {
+	return func(e *Echo) {
+		e.UseEncodedPath = useEncodedPath
+	}
+}
+
+// WithDebug sets `Echo#Debug`.
+func WithDebug(debug bool) Option {
+	return func(e *Echo) {
+		e.Debug = debug
+	}
+}
+
+// WithHideBanner sets `Echo#HideBanner`.
+func WithHideBanner(hide bool) Option {
+	return func(e *Echo) {
+		e.HideBanner = hide
+	}
+}
+
+// WithHidePort sets `Echo#HidePort`.
+func WithHidePort(hide bool) Option {
+	return func(e *Echo) {
+		e.HidePort = hide
+	}
+}
+
+// WithDisableHTTP2 sets `Echo#DisableHTTP2`.
+func WithDisableHTTP2(disable bool) Option {
+	return func(e *Echo) {
+		e.DisableHTTP2 = disable
+	}
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	binder := &DefaultBinder{}
+	serializer := &DefaultJSONSerializer{}
+	extractor := ExtractIPDirect()
+
+	e, err := NewWithOptions(
+		WithBinder(binder),
+		WithJSONSerializer(serializer),
+		WithIPExtractor(extractor),
+		WithDebug(true),
+		WithHideBanner(true),
+		WithHidePort(true),
+		WithListenerNetwork("tcp6"),
+		WithMaxMultipartMemory(1024),
+		WithUseEncodedPath(true),
+		WithMaxHeaderBytes(4096),
+		WithReadHeaderTimeout(time.Second),
+		WithReadTimeout(2*time.Second),
+		WithWriteTimeout(3*time.Second),
+		WithIdleTimeout(4*time.Second),
+	)
+
+	assert.NoError(t, err)
+	assert.Same(t, binder, e.Binder)
+	assert.Same(t, serializer, e.JSONSerializer)
+	assert.NotNil(t, e.IPExtractor)
+	assert.True(t, e.Debug)
+	assert.True(t, e.HideBanner)
+	assert.True(t, e.HidePort)
+	assert.True(t, e.UseEncodedPath)
+	assert.Equal(t, "tcp6", e.ListenerNetwork)
+	assert.Equal(t, int64(1024), e.MaxMultipartMemory)
+	assert.Equal(t, 4096, e.MaxHeaderBytes)
+	assert.Equal(t, time.Second, e.ReadHeaderTimeout)
+	assert.Equal(t, 2*time.Second, e.ReadTimeout)
+	assert.Equal(t, 3*time.Second, e.WriteTimeout)
+	assert.Equal(t, 4*time.Second, e.IdleTimeout)
+}
+
+func TestNewWithOptions_validationError(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenOptions []Option
+		expectError string
+	}{
+		{
+			name:        "nok, nil binder",
+			whenOptions: []Option{WithBinder(nil)},
+			expectError: "echo: binder must not be nil",
+		},
+		{
+			name:        "nok, nil serializer",
+			whenOptions: []Option{WithJSONSerializer(nil)},
+			expectError: "echo: JSON serializer must not be nil",
+		},
+		{
+			name:        "nok, nil error handler and logger",
+			whenOptions: []Option{WithHTTPErrorHandler(nil), WithLogger(nil)},
+			expectError: "echo: HTTP error handler must not be nil\necho: logger must not be nil",
+		},
+		{
+			name:        "nok, invalid listener network",
+			whenOptions: []Option{WithListenerNetwork("udp")},
+			expectError: "invalid listener network",
+		},
+		{
+			name:        "nok, negative multipart memory",
+			whenOptions: []Option{WithMaxMultipartMemory(-1)},
+			expectError: "echo: max multipart memory must not be negative",
+		},
+		{
+			name:        "nok, negative server limits",
+			whenOptions: []Option{WithMaxHeaderBytes(-1), WithIdleTimeout(-time.Second)},
+			expectError: "echo: max header bytes must not be negative\necho: idle timeout must not be negative",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e, err := NewWithOptions(tc.whenOptions...)
+
+			assert.Nil(t, e)
+			assert.EqualError(t, err, tc.expectError)
+		})
+	}
+}
+
+func TestEcho_Validate(t *testing.T) {
+	e := New()
+	assert.NoError(t, e.Validate())
+
+	e.Binder = nil
+	assert.EqualError(t, e.Validate(), "echo: binder must not be nil")
+
+	e.Binder = &DefaultBinder{}
+	e.HTTPErrorHandler = func(err error, c Context) { _ = c.NoContent(http.StatusInternalServerError) }
+	assert.NoError(t, e.Validate())
+}
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// QueryParseMode defines how the query string of request URL is parsed. See `Echo#QueryParseMode`.
+type QueryParseMode uint8
+
+const (
+	// QueryParseDefault parses query the same way as `url.ParseQuery` does: parameters containing semicolon or invalid
+	// escapes are silently dropped.
+	QueryParseDefault QueryParseMode = iota
+	// QueryParseSemicolon treats semicolons as parameter separators the same way as ampersands (`?a=1;b=2`) like Go
+	// did before 1.17. Encoded semicolons (`%3B`) are part of keys and values.
+	QueryParseSemicolon
+	// QueryParseStrict rejects requests with query that `url.ParseQuery` can not fully parse with
+	// "400 - Bad Request" error instead of silently dropping invalid parameters. Check is done when the route is
+	// matched so pre-middlewares still see partially parsed query.
+	QueryParseStrict
+)
+
+// applyQueryParseMode replaces handler of request with invalid query with handler returning error in
+// QueryParseStrict mode. Parsed query is cached in context.
+func (e *Echo) applyQueryParseMode(c *context) {
+	if e.QueryParseMode != QueryParseStrict || c.request.URL.RawQuery == "" {
+		return
+	}
+	raw := c.request.URL.RawQuery
+	query, err := url.ParseQuery(raw)
+	c.query = query
+	c.queryRaw = raw
+	if err == nil {
+		return
+	}
+	httpErr := NewHTTPError(http.StatusBadRequest, "invalid query string: "+err.Error()).SetInternal(err)
+	c.handler = func(c Context) error {
+		return httpErr
+	}
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEcho_QueryParseMode(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		givenMode    QueryParseMode
+		whenQuery    string
+		expectCode   int
+		expectBody   string
+		expectParams url.Values
+	}{
+		{
+			name:         "ok, default drops semicolon pairs",
+			whenQuery:    "a=1;b=2&c=3",
+			expectCode:   http.StatusOK,
+			expectBody:   "a= c=3",
+			expectParams: url.Values{"c": {"3"}},
+		},
+		{
+			name:         "ok, semicolon separator",
+			givenMode:    QueryParseSemicolon,
+			whenQuery:    "a=1;b=2&c=3;a=x%3By",
+			expectCode:   http.StatusOK,
+			expectBody:   "a=1 c=3",
+			expectParams: url.Values{"a": {"1", "x;y"}, "b": {"2"}, "c": {"3"}},
+		},
+		{
+			name:         "ok, strict with valid query",
+			givenMode:    QueryParseStrict,
+			whenQuery:    "a=1&c=3",
+			expectCode:   http.StatusOK,
+			expectBody:   "a=1 c=3",
+			expectParams: url.Values{"a": {"1"}, "c": {"3"}},
+		},
+		{
+			name:       "nok, strict with semicolon",
+			givenMode:  QueryParseStrict,
+			whenQuery:  "a=1;b=2&c=3",
+			expectCode: http.StatusBadRequest,
+			expectBody: `{"message":"invalid query string: invalid semicolon separator in query"}` + "\n",
+		},
+		{
+			name:       "nok, strict with invalid escape",
+			givenMode:  QueryParseStrict,
+			whenQuery:  "a=%zz",
+			expectCode: http.StatusBadRequest,
+			expectBody: `{"message":"invalid query string: invalid URL escape \"%zz\""}` + "\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.QueryParseMode = tc.givenMode
+			var params url.Values
+			var iterated url.Values
+			e.GET("/", func(c Context) error {
+				params = c.QueryParams()
+				iterated = url.Values{}
+				c.QueryParamIter(func(key, value string) bool {
+					iterated.Add(key, value)
+					return true
+				})
+				return c.String(http.StatusOK, "a="+c.QueryParam("a")+" c="+c.QueryParam("c"))
+			})
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?"+tc.whenQuery, nil))
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+			if tc.expectParams != nil {
+				assert.Equal(t, tc.expectParams, params)
+				assert.Equal(t, tc.expectParams, iterated)
+			}
+		})
+	}
+}
+
+func TestDefaultBinder_BindQueryParams_semicolon(t *testing.T) {
+	e := New()
+	e.QueryParseMode = QueryParseSemicolon
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/?id=1;name=jon", nil), httptest.NewRecorder())
+
+	var result struct {
+		ID   int    `query:"id"`
+		Name string `query:"name"`
+	}
+	err := c.Bind(&result)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.ID)
+	assert.Equal(t, "jon", result.Name)
+}
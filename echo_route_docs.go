@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net/http"
+	"sort"
+	"time"
+)
+
+// SetDescription sets human readable description of the route. It is served by `Echo#RoutesHandler`. Routes that
+// have not been added to the router (ala copies) are not changed.
+//
+// Example: `e.GET("/users/:id", handler).SetDescription("Fetch a user by ID")`
+func (r *Route) SetDescription(description string) *Route {
+	if m := r.meta(); m != nil {
+		m.description = description
+	}
+	return r
+}
+
+// SetDeprecated marks route as deprecated. Responses of the route have `Deprecation: true` header (and `Sunset`
+// header when sunset is set with `Route#SetSunset`). Message tells clients what to use instead and is served by
+// `Echo#RoutesHandler`.
+//
+// Example: `e.GET("/users/:id", handler).SetDeprecated("use /v2/users/:id")`
+func (r *Route) SetDeprecated(message string) *Route {
+	if m := r.meta(); m != nil {
+		m.deprecated = true
+		m.deprecationMessage = message
+		m.router.hasDeprecatedRoutes.Store(true)
+	}
+	return r
+}
+
+// SetSunset marks route as deprecated and sets time after which the route is expected to be removed. It is sent as
+// `Sunset` header (RFC 8594) with responses of the route.
+func (r *Route) SetSunset(sunset time.Time) *Route {
+	if m := r.meta(); m != nil {
+		sunset = sunset.UTC()
+		m.deprecated = true
+		m.sunset = &sunset
+		m.router.hasDeprecatedRoutes.Store(true)
+	}
+	return r
+}
+
+// applyRouteDeprecation adds deprecation headers to the response of the matched route when route is deprecated.
+func applyRouteDeprecation(router *Router, c *context) {
+	if !router.hasDeprecatedRoutes.Load() || c.path == "" {
+		return
+	}
+	route := router.matchedRoute(c)
+	if route == nil || !route.Deprecated() {
+		return
+	}
+	header := c.response.Header()
+	header.Set(HeaderDeprecation, "true")
+	if sunset := route.Sunset(); sunset != nil {
+		header.Set(HeaderSunset, sunset.Format(http.TimeFormat))
+	}
+}
+
+// RoutesHandler returns handler that renders routes with their descriptions and deprecation notices as JSON so the
+// API is self-describing. Routes are sorted by path and method.
+//
+// Example: `e.GET("/_routes", e.RoutesHandler())`
+func (e *Echo) RoutesHandler() HandlerFunc {
+	type routeDoc struct {
+		Method             string     `json:"method"`
+		Path               string     `json:"path"`
+		Name               string     `json:"name"`
+		Description        string     `json:"description,omitempty"`
+		Deprecated         bool       `json:"deprecated,omitempty"`
+		DeprecationMessage string     `json:"deprecation_message,omitempty"`
+		Sunset             *time.Time `json:"sunset,omitempty"`
+	}
+	return func(c Context) error {
+		result := make([]routeDoc, 0)
+		for _, route := range e.Routes() {
+			if route.Method == RouteNotFound {
+				continue
+			}
+			result = append(result, routeDoc{
+				Method:             route.Method,
+				Path:               route.Path,
+				Name:               route.Name,
+				Description:        route.Description(),
+				Deprecated:         route.Deprecated(),
+				DeprecationMessage: route.DeprecationMessage(),
+				Sunset:             route.Sunset(),
+			})
+		}
+		sort.Slice(result, func(i, j int) bool {
+			if result[i].Path != result[j].Path {
+				return result[i].Path < result[j].Path
+			}
+			return result[i].Method < result[j].Method
+		})
+		return c.JSON(http.StatusOK, result)
+	}
+}
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoute_SetDeprecated(t *testing.T) {
+	e := New()
+	handler := func(c Context) error {
+		return c.String(http.StatusOK, "OK")
+	}
+	sunset := time.Date(2030, 1, 2, 3, 4, 5, 0, time.FixedZone("EET", 2*3600))
+	e.GET("/v1/users/:id", handler).SetDeprecated("use /v2/users/:id")
+	e.GET("/v1/orders", handler).SetSunset(sunset)
+	e.GET("/v2/users/:id", handler)
+	g := e.Group("/admin")
+	g.GET("/stats", handler).SetDeprecated("")
+
+	var testCases = []struct {
+		name              string
+		whenMethod        string
+		whenURL           string
+		expectDeprecation string
+		expectSunset      string
+	}{
+		{name: "deprecated", whenMethod: http.MethodGet, whenURL: "/v1/users/1", expectDeprecation: "true"},
+		{name: "deprecated HEAD", whenMethod: http.MethodHead, whenURL: "/v1/users/1", expectDeprecation: "true"},
+		{name: "sunset", whenMethod: http.MethodGet, whenURL: "/v1/orders", expectDeprecation: "true", expectSunset: "Wed, 02 Jan 2030 01:04:05 GMT"},
+		{name: "group route", whenMethod: http.MethodGet, whenURL: "/admin/stats", expectDeprecation: "true"},
+		{name: "not deprecated", whenMethod: http.MethodGet, whenURL: "/v2/users/1"},
+		{name: "not found", whenMethod: http.MethodGet, whenURL: "/v3/users/1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(tc.whenMethod, tc.whenURL, nil))
+
+			assert.Equal(t, tc.expectDeprecation, rec.Header().Get(HeaderDeprecation))
+			assert.Equal(t, tc.expectSunset, rec.Header().Get(HeaderSunset))
+		})
+	}
+}
+
+func TestRoute_SetDeprecated_perRouter(t *testing.T) {
+	handler := func(c Context) error {
+		return c.String(http.StatusOK, "OK")
+	}
+	e1 := New()
+	e1.GET("/v1/users", handler).SetDeprecated("use /v2/users")
+	e1.Host("api.example.com").GET("/users", handler)
+	e2 := New()
+	e2.GET("/v1/users", handler)
+
+	assert.True(t, e1.router.hasDeprecatedRoutes.Load())
+	assert.False(t, e1.routers["api.example.com"].hasDeprecatedRoutes.Load())
+	assert.False(t, e2.router.hasDeprecatedRoutes.Load())
+
+	rec := httptest.NewRecorder()
+	e2.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/users", nil))
+	assert.Equal(t, "", rec.Header().Get(HeaderDeprecation))
+
+	rec = httptest.NewRecorder()
+	e1.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/users", nil))
+	assert.Equal(t, "true", rec.Header().Get(HeaderDeprecation))
+}
+
+func TestEcho_RoutesHandler(t *testing.T) {
+	e := New()
+	handler := func(c Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+	e.GET("/v2/users/:id", handler).SetDescription("Fetch a user by ID")
+	e.GET("/v1/users/:id", handler).
+		SetDescription("Fetch a user by ID").
+		SetDeprecated("use /v2/users/:id").
+		SetSunset(time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC))
+	e.DELETE("/v2/users/:id", handler)
+	e.RouteNotFound("/*", handler)
+	route := e.GET("/_routes", e.RoutesHandler())
+	route.Name = "routes"
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/_routes", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	name := "github.com/labstack/echo/v4.TestEcho_RoutesHandler.func1"
+	expect := `[{"method":"GET","path":"/_routes","name":"routes"},` +
+		`{"method":"GET","path":"/v1/users/:id","name":"` + name + `","description":"Fetch a user by ID","deprecated":true,"deprecation_message":"use /v2/users/:id","sunset":"2030-01-02T00:00:00Z"},` +
+		`{"method":"DELETE","path":"/v2/users/:id","name":"` + name + `"},` +
+		`{"method":"GET","path":"/v2/users/:id","name":"` + name + `","description":"Fetch a user by ID"}]` + "\n"
+	assert.Equal(t, expect, rec.Body.String())
+}
@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// sdNotifyWriteTimeout is maximum duration of writing single notification to systemd socket.
+const sdNotifyWriteTimeout = time.Second
+
+// sdNotifier holds state of systemd notifications sent by Echo when `Echo#EnableSDNotify` is set.
+type sdNotifier struct {
+	readyOnce sync.Once
+	warnOnce  sync.Once
+
+	mu           sync.Mutex
+	stopWatchdog chan struct{}
+}
+
+// notifyReady sends READY=1 to systemd and starts sending WATCHDOG=1 when watchdog is enabled for the service. Only
+// first call (ala when both HTTP and HTTPS servers are started) has effect.
+func (e *Echo) notifyReady() {
+	if !e.EnableSDNotify {
+		return
+	}
+	e.sdNotify.readyOnce.Do(func() {
+		e.sdNotifyState("READY=1")
+
+		interval := sdWatchdogInterval()
+		if interval <= 0 {
+			return
+		}
+		stop := make(chan struct{})
+		e.sdNotify.mu.Lock()
+		e.sdNotify.stopWatchdog = stop
+		e.sdNotify.mu.Unlock()
+		go e.sdWatchdog(interval, stop)
+	})
+}
+
+// notifyStopping stops watchdog notifications and sends STOPPING=1 to systemd.
+func (e *Echo) notifyStopping() {
+	if !e.EnableSDNotify {
+		return
+	}
+	e.sdNotify.mu.Lock()
+	if e.sdNotify.stopWatchdog != nil {
+		close(e.sdNotify.stopWatchdog)
+		e.sdNotify.stopWatchdog = nil
+	}
+	e.sdNotify.mu.Unlock()
+	e.sdNotifyState("STOPPING=1")
+}
+
+func (e *Echo) sdWatchdog(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			e.sdNotifyState("WATCHDOG=1")
+		case <-stop:
+			return
+		}
+	}
+}
+
+// sdNotifyState sends state to systemd. Failure is logged only once so unreachable socket does not flood the log.
+func (e *Echo) sdNotifyState(state string) {
+	if err := sdNotify(os.Getenv("NOTIFY_SOCKET"), state); err != nil {
+		e.sdNotify.warnOnce.Do(func() {
+			e.Logger.Errorf("echo: failed to notify systemd: %v", err)
+		})
+	}
+}
+
+// sdNotify writes state to systemd notification socket. Sockets starting with `@` are in abstract namespace. Nothing
+// is sent when socket is empty (service is not run by systemd with `Type=notify`).
+func sdNotify(socket string, state string) error {
+	if socket == "" {
+		return nil
+	}
+	if socket[0] == '@' {
+		socket = "\x00" + socket[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetWriteDeadline(time.Now().Add(sdNotifyWriteTimeout)); err != nil {
+		return err
+	}
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// sdWatchdogInterval returns interval of WATCHDOG=1 notifications (half of WATCHDOG_USEC as systemd recommends) or 0
+// when watchdog is not enabled for this process.
+func sdWatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond / 2
+}
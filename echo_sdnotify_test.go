@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"bytes"
+	stdContext "context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func listenSDNotifySocket(t *testing.T) *net.UnixConn {
+	dir, err := os.MkdirTemp("", "sdnotify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram sockets are not supported: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	t.Setenv("NOTIFY_SOCKET", path)
+	return conn
+}
+
+func readSDNotifyState(t *testing.T, conn *net.UnixConn) string {
+	t.Helper()
+	buf := make([]byte, 64)
+	if err := conn.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("failed to read notification: %v", err)
+	}
+	return string(buf[:n])
+}
+
+func startSDNotifyTestServer(t *testing.T, e *Echo) {
+	e.HideBanner = true
+	e.HidePort = true
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- e.Start("127.0.0.1:0")
+	}()
+	if err := waitForServerStart(e, errChan, false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEcho_EnableSDNotify(t *testing.T) {
+	conn := listenSDNotifySocket(t)
+	t.Setenv("WATCHDOG_USEC", "20000")
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()))
+
+	e := New()
+	e.EnableSDNotify = true
+	startSDNotifyTestServer(t, e)
+
+	assert.Equal(t, "READY=1", readSDNotifyState(t, conn))
+	assert.Equal(t, "WATCHDOG=1", readSDNotifyState(t, conn))
+	assert.Equal(t, "WATCHDOG=1", readSDNotifyState(t, conn))
+
+	assert.NoError(t, e.Shutdown(stdContext.Background()))
+	for {
+		// watchdog notification could have been sent just before shutdown
+		if state := readSDNotifyState(t, conn); state != "WATCHDOG=1" {
+			assert.Equal(t, "STOPPING=1", state)
+			break
+		}
+	}
+}
+
+func TestEcho_EnableSDNotify_disabled(t *testing.T) {
+	conn := listenSDNotifySocket(t)
+
+	e := New()
+	startSDNotifyTestServer(t, e)
+	assert.NoError(t, e.Shutdown(stdContext.Background()))
+
+	assert.NoError(t, conn.SetReadDeadline(time.Now().Add(50*time.Millisecond)))
+	_, err := conn.Read(make([]byte, 64))
+	assert.True(t, os.IsTimeout(err), "no notification must be sent")
+}
+
+func TestEcho_EnableSDNotify_unreachableSocket(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", filepath.Join(t.TempDir(), "missing.sock"))
+	t.Setenv("WATCHDOG_USEC", "10000")
+	t.Setenv("WATCHDOG_PID", "")
+
+	e := New()
+	e.EnableSDNotify = true
+	buf := new(bytes.Buffer)
+	e.Logger.SetOutput(buf)
+	e.GET("/", func(c Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+	startSDNotifyTestServer(t, e)
+
+	time.Sleep(50 * time.Millisecond) // let watchdog fail few times
+	res, err := http.Get("http://" + e.ListenerAddr().String() + "/")
+	if assert.NoError(t, err) {
+		res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	}
+	assert.NoError(t, e.Shutdown(stdContext.Background()))
+
+	assert.Equal(t, 1, strings.Count(buf.String(), "failed to notify systemd"))
+}
+
+func TestSDWatchdogInterval(t *testing.T) {
+	var testCases = []struct {
+		name      string
+		givenUSEC string
+		givenPID  string
+		expect    time.Duration
+	}{
+		{name: "ok, half of WATCHDOG_USEC", givenUSEC: "30000000", expect: 15 * time.Second},
+		{name: "ok, WATCHDOG_PID is this process", givenUSEC: "2000", givenPID: strconv.Itoa(os.Getpid()), expect: time.Millisecond},
+		{name: "nok, WATCHDOG_PID is other process", givenUSEC: "2000", givenPID: "1", expect: 0},
+		{name: "nok, WATCHDOG_USEC not set", expect: 0},
+		{name: "nok, invalid WATCHDOG_USEC", givenUSEC: "x", expect: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("WATCHDOG_USEC", tc.givenUSEC)
+			t.Setenv("WATCHDOG_PID", tc.givenPID)
+
+			assert.Equal(t, tc.expect, sdWatchdogInterval())
+		})
+	}
+}
@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	stdContext "context"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TaskQueueFullPolicy defines what `TaskRunner#Submit` does when the task queue is full.
+type TaskQueueFullPolicy int
+
+const (
+	// TaskQueueBlock makes Submit wait until there is room in the queue.
+	TaskQueueBlock TaskQueueFullPolicy = iota
+	// TaskQueueDrop makes Submit discard the task. Dropped tasks are counted in `TaskStats.Dropped`.
+	TaskQueueDrop
+	// TaskQueueError makes Submit return ErrTaskQueueFull.
+	TaskQueueError
+)
+
+// defaultTaskQueueSize is default value of `Echo#TaskQueueSize`.
+const defaultTaskQueueSize = 100
+
+var (
+	// ErrTaskQueueFull is returned by `TaskRunner#Submit` when queue is full and policy is TaskQueueError.
+	ErrTaskQueueFull = errors.New("echo: task queue is full")
+	// ErrTaskRunnerStopped is returned by `TaskRunner#Submit` after the server has been shut down.
+	ErrTaskRunnerStopped = errors.New("echo: task runner is stopped")
+)
+
+// TaskStats are statistics of the TaskRunner.
+type TaskStats struct {
+	// Queued is number of tasks waiting for a worker.
+	Queued int64 `json:"queued"`
+	// Running is number of tasks being run.
+	Running int64 `json:"running"`
+	// Completed is number of tasks that have finished without error.
+	Completed uint64 `json:"completed"`
+	// Failed is number of tasks that have returned error or panicked.
+	Failed uint64 `json:"failed"`
+	// Dropped is number of tasks discarded because the queue was full.
+	Dropped uint64 `json:"dropped"`
+}
+
+// TaskRunner runs background tasks on bounded worker pool tied to lifecycle of the server. See `Echo#Tasks`.
+type TaskRunner struct {
+	logger func() Logger
+	policy TaskQueueFullPolicy
+	queue  chan task
+	// ctx is passed to tasks. It is cancelled when shutdown stops waiting for tasks.
+	ctx    stdContext.Context
+	cancel stdContext.CancelFunc
+
+	// mu guards stopped flag and sending to the queue so queue is never closed while Submit is sending to it.
+	mu      sync.RWMutex
+	stopped bool
+	// pending counts tasks that have been queued and not yet finished.
+	pending sync.WaitGroup
+
+	queued    int64
+	running   int64
+	completed uint64
+	failed    uint64
+	dropped   uint64
+}
+
+type task struct {
+	name string
+	fn   func(ctx stdContext.Context) error
+}
+
+// Tasks returns background task runner of the Echo instance. Runner is created on first call with
+// `Echo#TaskWorkers`, `Echo#TaskQueueSize` and `Echo#TaskQueueFullPolicy` settings. `Echo#Shutdown` stops accepting
+// new tasks and waits for queued and running tasks to finish (up to `Echo#TaskShutdownTimeout`).
+//
+// Example:
+//
+//	e.POST("/orders", func(c echo.Context) error {
+//		// ... store order
+//		err := c.Echo().Tasks().Submit("send-receipt", func(ctx context.Context) error {
+//			return mailer.SendReceipt(ctx, order)
+//		})
+//		...
+//	})
+func (e *Echo) Tasks() *TaskRunner {
+	e.tasksOnce.Do(func() {
+		workers := e.TaskWorkers
+		if workers <= 0 {
+			workers = runtime.GOMAXPROCS(0)
+		}
+		queueSize := e.TaskQueueSize
+		if queueSize <= 0 {
+			queueSize = defaultTaskQueueSize
+		}
+		e.tasks.Store(newTaskRunner(func() Logger { return e.Logger }, workers, queueSize, e.TaskQueueFullPolicy))
+	})
+	return e.tasks.Load()
+}
+
+func newTaskRunner(logger func() Logger, workers int, queueSize int, policy TaskQueueFullPolicy) *TaskRunner {
+	ctx, cancel := stdContext.WithCancel(stdContext.Background())
+	r := &TaskRunner{
+		logger: logger,
+		policy: policy,
+		queue:  make(chan task, queueSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	for i := 0; i < workers; i++ {
+		go r.work()
+	}
+	return r
+}
+
+// Submit queues task with given name. Name is used in logs when task fails. Panics in tasks are recovered. Returns
+// ErrTaskRunnerStopped after the server has been shut down and ErrTaskQueueFull when queue is full and policy is
+// TaskQueueError.
+func (r *TaskRunner) Submit(name string, fn func(ctx stdContext.Context) error) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.stopped {
+		return ErrTaskRunnerStopped
+	}
+
+	t := task{name: name, fn: fn}
+	r.pending.Add(1)
+	atomic.AddInt64(&r.queued, 1)
+	if r.policy == TaskQueueBlock {
+		r.queue <- t
+		return nil
+	}
+	select {
+	case r.queue <- t:
+		return nil
+	default:
+	}
+	atomic.AddInt64(&r.queued, -1)
+	r.pending.Done()
+	if r.policy == TaskQueueDrop {
+		atomic.AddUint64(&r.dropped, 1)
+		return nil
+	}
+	return ErrTaskQueueFull
+}
+
+// Stats returns current statistics of the runner.
+func (r *TaskRunner) Stats() TaskStats {
+	return TaskStats{
+		Queued:    atomic.LoadInt64(&r.queued),
+		Running:   atomic.LoadInt64(&r.running),
+		Completed: atomic.LoadUint64(&r.completed),
+		Failed:    atomic.LoadUint64(&r.failed),
+		Dropped:   atomic.LoadUint64(&r.dropped),
+	}
+}
+
+func (r *TaskRunner) work() {
+	for t := range r.queue {
+		atomic.AddInt64(&r.queued, -1)
+		atomic.AddInt64(&r.running, 1)
+		err := r.run(t)
+		atomic.AddInt64(&r.running, -1)
+		if err != nil {
+			atomic.AddUint64(&r.failed, 1)
+			r.logger().Errorf("echo: task %s failed: %v", t.name, err)
+		} else {
+			atomic.AddUint64(&r.completed, 1)
+		}
+		r.pending.Done()
+	}
+}
+
+func (r *TaskRunner) run(t task) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("panic: %v\n%s", rec, debug.Stack())
+		}
+	}()
+	return t.fn(r.ctx)
+}
+
+// shutdown stops accepting new tasks and waits for queued and running tasks to finish. When ctx is done or timeout
+// has passed, context of the running tasks is cancelled and ctx error (or context.DeadlineExceeded) is returned.
+func (r *TaskRunner) shutdown(ctx stdContext.Context, timeout time.Duration) error {
+	r.mu.Lock()
+	if !r.stopped {
+		r.stopped = true
+		close(r.queue)
+	}
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.pending.Wait()
+		close(done)
+	}()
+	var timeoutCh <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutCh = timer.C
+	}
+	select {
+	case <-done:
+		r.cancel()
+		return nil
+	case <-ctx.Done():
+		r.cancel()
+		return ctx.Err()
+	case <-timeoutCh:
+		r.cancel()
+		return stdContext.DeadlineExceeded
+	}
+}
@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"bytes"
+	stdContext "context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEcho_Tasks(t *testing.T) {
+	e := New()
+	buf := new(bytes.Buffer)
+	e.Logger.SetOutput(buf)
+	e.TaskWorkers = 2
+
+	var ran int32
+	assert.NoError(t, e.Tasks().Submit("ok", func(ctx stdContext.Context) error {
+		atomic.AddInt32(&ran, 1)
+		return nil
+	}))
+	assert.NoError(t, e.Tasks().Submit("fails", func(ctx stdContext.Context) error {
+		atomic.AddInt32(&ran, 1)
+		return errors.New("smtp unavailable")
+	}))
+	assert.NoError(t, e.Tasks().Submit("panics", func(ctx stdContext.Context) error {
+		atomic.AddInt32(&ran, 1)
+		panic("boom")
+	}))
+
+	ctx, cancel := stdContext.WithTimeout(stdContext.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, e.Shutdown(ctx))
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&ran))
+	assert.Equal(t, TaskStats{Completed: 1, Failed: 2}, e.Tasks().Stats())
+	assert.Contains(t, buf.String(), "echo: task fails failed: smtp unavailable")
+	assert.Contains(t, buf.String(), "echo: task panics failed: panic: boom")
+
+	err := e.Tasks().Submit("late", func(ctx stdContext.Context) error { return nil })
+	assert.ErrorIs(t, err, ErrTaskRunnerStopped)
+}
+
+func TestEcho_Tasks_queueFullPolicy(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		givenPolicy   TaskQueueFullPolicy
+		expectErr     error
+		expectDropped uint64
+	}{
+		{
+			name:          "drop",
+			givenPolicy:   TaskQueueDrop,
+			expectDropped: 1,
+		},
+		{
+			name:        "error",
+			givenPolicy: TaskQueueError,
+			expectErr:   ErrTaskQueueFull,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.TaskWorkers = 1
+			e.TaskQueueSize = 1
+			e.TaskQueueFullPolicy = tc.givenPolicy
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+			assert.NoError(t, e.Tasks().Submit("blocker", func(ctx stdContext.Context) error {
+				close(started)
+				<-release
+				return nil
+			}))
+			<-started
+			assert.NoError(t, e.Tasks().Submit("queued", func(ctx stdContext.Context) error { return nil }))
+
+			err := e.Tasks().Submit("overflow", func(ctx stdContext.Context) error { return nil })
+
+			assert.Equal(t, tc.expectErr, err)
+			assert.Equal(t, TaskStats{Queued: 1, Running: 1, Dropped: tc.expectDropped}, e.Tasks().Stats())
+
+			close(release)
+			assert.NoError(t, e.Shutdown(stdContext.Background()))
+			assert.Equal(t, TaskStats{Completed: 2, Dropped: tc.expectDropped}, e.Tasks().Stats())
+		})
+	}
+}
+
+func TestEcho_Tasks_blockPolicyWaitsForRoom(t *testing.T) {
+	e := New()
+	e.TaskWorkers = 1
+	e.TaskQueueSize = 1
+
+	release := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, e.Tasks().Submit("blocker", func(ctx stdContext.Context) error {
+			<-release
+			return nil
+		}))
+	}
+	submitted := make(chan error)
+	go func() {
+		submitted <- e.Tasks().Submit("waits", func(ctx stdContext.Context) error { return nil })
+	}()
+
+	select {
+	case <-submitted:
+		t.Fatal("submit must block while queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	assert.NoError(t, <-submitted)
+	assert.NoError(t, e.Shutdown(stdContext.Background()))
+	assert.Equal(t, uint64(3), e.Tasks().Stats().Completed)
+}
+
+func TestEcho_Tasks_shutdownTimeoutCancelsTasks(t *testing.T) {
+	e := New()
+	e.TaskShutdownTimeout = 50 * time.Millisecond
+
+	cancelled := make(chan struct{})
+	assert.NoError(t, e.Tasks().Submit("slow", func(ctx stdContext.Context) error {
+		<-ctx.Done()
+		close(cancelled)
+		return ctx.Err()
+	}))
+
+	err := e.Shutdown(stdContext.Background())
+
+	assert.ErrorIs(t, err, stdContext.DeadlineExceeded)
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("task context must be cancelled when shutdown stops waiting")
+	}
+}
@@ -4,6 +4,7 @@
 package echo
 
 import (
+	"bufio"
 	"bytes"
 	stdContext "context"
 	"crypto/tls"
@@ -17,6 +18,7 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -429,6 +431,83 @@ func TestEchoWrapMiddleware(t *testing.T) {
 	}
 }
 
+type upperCaseWriter struct {
+	http.ResponseWriter
+}
+
+func (w upperCaseWriter) Write(b []byte) (int, error) {
+	return w.ResponseWriter.Write(bytes.ToUpper(b))
+}
+
+func TestEchoWrapMiddleware_swapsWriterAndPropagatesError(t *testing.T) {
+	e := New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	original := c.Response()
+
+	var fromRequest Context
+	mw := WrapMiddleware(func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fromRequest, _ = ContextFromRequest(r)
+			h.ServeHTTP(upperCaseWriter{ResponseWriter: w}, r)
+		})
+	})
+	h := mw(func(c Context) error {
+		_ = c.String(http.StatusTeapot, "hello")
+		return ErrForbidden
+	})
+
+	assert.Equal(t, ErrForbidden, h(c))
+	assert.Equal(t, c, fromRequest)
+	assert.Same(t, original, c.Response())
+	assert.True(t, original.Committed)
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+	assert.Equal(t, "HELLO", rec.Body.String())
+}
+
+func TestEchoWrapMiddleware_aborts(t *testing.T) {
+	e := New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	mw := WrapMiddleware(func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "denied", http.StatusUnauthorized)
+		})
+	})
+	called := false
+	h := mw(func(c Context) error {
+		called = true
+		return nil
+	})
+
+	assert.NoError(t, h(c))
+	assert.False(t, called)
+	assert.True(t, c.Response().Committed)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestEchoWrapMiddleware_timeoutHandler(t *testing.T) {
+	e := New()
+	e.Use(WrapMiddleware(func(h http.Handler) http.Handler {
+		return http.TimeoutHandler(h, 10*time.Millisecond, "timeout")
+	}))
+	var handlerErr error
+	e.GET("/", func(c Context) error {
+		<-c.Request().Context().Done()
+		handlerErr = c.Request().Context().Err()
+		return handlerErr
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// handler has finished before echo.Context is released
+	assert.ErrorIs(t, handlerErr, stdContext.DeadlineExceeded)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "timeout", rec.Body.String())
+}
+
 func TestEchoConnect(t *testing.T) {
 	e := New()
 	testMethod(t, http.MethodConnect, "/", e)
@@ -651,6 +730,76 @@ func TestEchoServeHTTPPathEncoding(t *testing.T) {
 	}
 }
 
+func TestEchoServeHTTP_UseEncodedPath(t *testing.T) {
+	e := New()
+	e.UseEncodedPath = true
+	e.GET("/files/:name/meta", func(c Context) error {
+		return c.String(http.StatusOK, "meta:"+c.Param("name")+"|"+c.RawParam("name"))
+	})
+	e.GET("/files/a/b/meta", func(c Context) error {
+		return c.String(http.StatusOK, "static")
+	})
+	e.GET("/static/*", func(c Context) error {
+		return c.String(http.StatusOK, "any:"+c.Param("*")+"|"+c.RawParam("*"))
+	})
+
+	var testCases = []struct {
+		name         string
+		whenURL      string
+		expectBody   string
+		expectStatus int
+	}{
+		{
+			name:         "ok, encoded slash stays inside param and is decoded",
+			whenURL:      "/files/a%2Fb/meta",
+			expectBody:   "meta:a/b|a%2Fb",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "ok, real slash matches static route",
+			whenURL:      "/files/a/b/meta",
+			expectBody:   "static",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "ok, encoded percent is decoded only once",
+			whenURL:      "/files/a%252Fb/meta",
+			expectBody:   "meta:a%2Fb|a%252Fb",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "ok, percent sign",
+			whenURL:      "/files/100%25/meta",
+			expectBody:   "meta:100%|100%25",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "ok, wildcard with encoded and real slashes",
+			whenURL:      "/static/a%2Fb/c%20d",
+			expectBody:   "any:a/b/c d|a%2Fb/c%20d",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "nok, encoded slash does not match static route",
+			whenURL:      "/files/a%2Fb",
+			expectBody:   "{\"message\":\"Not Found\"}\n",
+			expectStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectStatus, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+		})
+	}
+}
+
 func TestEchoHost(t *testing.T) {
 	okHandler := func(c Context) error { return c.String(http.StatusOK, http.StatusText(http.StatusOK)) }
 	teapotHandler := func(c Context) error { return c.String(http.StatusTeapot, http.StatusText(http.StatusTeapot)) }
@@ -950,6 +1099,48 @@ func TestEchoStart(t *testing.T) {
 	assert.NoError(t, e.Close())
 }
 
+func TestEchoStart_serverLimits(t *testing.T) {
+	e := New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.Server.ReadTimeout = 5 * time.Second
+	e.Server.WriteTimeout = 5 * time.Second
+	e.MaxHeaderBytes = 4096
+	e.ReadHeaderTimeout = time.Second
+	e.WriteTimeout = 2 * time.Second
+	e.IdleTimeout = 3 * time.Second
+	errChan := make(chan error)
+
+	go func() {
+		if err := e.Start("127.0.0.1:0"); err != nil {
+			errChan <- err
+		}
+	}()
+
+	err := waitForServerStart(e, errChan, false)
+	assert.NoError(t, err)
+	defer e.Close()
+
+	e.startupMutex.RLock()
+	defer e.startupMutex.RUnlock()
+	assert.Equal(t, 4096, e.Server.MaxHeaderBytes)
+	assert.Equal(t, time.Second, e.Server.ReadHeaderTimeout)
+	assert.Equal(t, 5*time.Second, e.Server.ReadTimeout) // zero Echo value keeps server value
+	assert.Equal(t, 2*time.Second, e.Server.WriteTimeout)
+	assert.Equal(t, 3*time.Second, e.Server.IdleTimeout)
+}
+
+func TestEchoStart_negativeServerLimit(t *testing.T) {
+	e := New()
+	e.HideBanner = true
+	e.ReadTimeout = -time.Second
+
+	err := e.Start("127.0.0.1:0")
+
+	assert.EqualError(t, err, "echo: read timeout must not be negative")
+	assert.Nil(t, e.Listener)
+}
+
 func TestEcho_StartTLS(t *testing.T) {
 	var testCases = []struct {
 		name        string
@@ -1478,6 +1669,93 @@ func TestEchoShutdown(t *testing.T) {
 	assert.Equal(t, err.Error(), "http: Server closed")
 }
 
+func TestEchoShutdown_longLivedConns(t *testing.T) {
+	e := New()
+
+	closed := make(chan string, 2)
+	var deregisterWS, deregisterSSE func()
+	deregisterWS = e.RegisterLongLivedConn(func() {
+		go func() { // handler sends close frame and returns
+			closed <- "ws"
+			deregisterWS()
+		}()
+	})
+	deregisterSSE = e.RegisterLongLivedConn(func() {
+		go func() {
+			closed <- "sse"
+			deregisterSSE()
+		}()
+	})
+
+	ctx, cancel := stdContext.WithTimeout(stdContext.Background(), 10*time.Second)
+	defer cancel()
+	assert.NoError(t, e.Shutdown(ctx))
+	assert.ElementsMatch(t, []string{"ws", "sse"}, []string{<-closed, <-closed})
+
+	// connections registered after shutdown are cancelled immediately
+	called := false
+	deregister := e.RegisterLongLivedConn(func() { called = true })
+	deregister()
+	deregister() // calling deregister multiple times is safe
+	assert.True(t, called)
+}
+
+func TestEchoShutdown_longLivedConnsTimeout(t *testing.T) {
+	e := New()
+	e.LongLivedConnShutdownTimeout = 10 * time.Millisecond
+
+	notified := false
+	deregister := e.RegisterLongLivedConn(func() { notified = true })
+	defer deregister()
+
+	err := e.Shutdown(stdContext.Background())
+	assert.ErrorIs(t, err, stdContext.DeadlineExceeded)
+	assert.True(t, notified)
+}
+
+func TestEchoShutdown_onShutdown(t *testing.T) {
+	e := New()
+	var calls []string
+	e.OnShutdown(func(ctx stdContext.Context) error {
+		calls = append(calls, "first")
+		return errors.New("flush failed")
+	})
+	e.OnShutdown(func(ctx stdContext.Context) error {
+		calls = append(calls, "second")
+		return errors.New("close failed")
+	})
+
+	err := e.Shutdown(stdContext.Background())
+
+	assert.EqualError(t, err, "flush failed")
+	assert.Equal(t, []string{"first", "second"}, calls)
+}
+
+func TestEchoShutdown_onShutdownAfterError(t *testing.T) {
+	e := New()
+	e.LongLivedConnShutdownTimeout = 10 * time.Millisecond
+	deregister := e.RegisterLongLivedConn(func() {})
+	defer deregister()
+
+	taskDone := false
+	assert.NoError(t, e.Tasks().Submit("flush", func(ctx stdContext.Context) error {
+		taskDone = true
+		return nil
+	}))
+	called := false
+	e.OnShutdown(func(ctx stdContext.Context) error {
+		called = true
+		return errors.New("flush failed")
+	})
+
+	err := e.Shutdown(stdContext.Background())
+
+	assert.ErrorIs(t, err, stdContext.DeadlineExceeded)
+	assert.ErrorContains(t, err, "flush failed")
+	assert.True(t, taskDone)
+	assert.True(t, called)
+}
+
 var listenerNetworkTests = []struct {
 	test    string
 	network string
@@ -1870,3 +2148,327 @@ func BenchmarkEchoGitHubAPIMisses(b *testing.B) {
 func BenchmarkEchoParseAPI(b *testing.B) {
 	benchmarkEchoRoutes(b, parseAPI)
 }
+
+func TestEcho_BodyReadTimeout(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		whenPath      string
+		whenBodyDelay time.Duration
+		expectStatus  int
+		expectBody    string
+	}{
+		{
+			name:         "ok, body sent in time",
+			whenPath:     "/",
+			expectStatus: http.StatusOK,
+			expectBody:   `{"ctx_err":"","name":"jon"}`,
+		},
+		{
+			name:          "nok, trickling body results 408",
+			whenPath:      "/",
+			whenBodyDelay: 500 * time.Millisecond,
+			expectStatus:  http.StatusRequestTimeout,
+			expectBody:    `{"message":"Request Timeout"}`,
+		},
+		{
+			name:          "ok, route option overrides timeout",
+			whenPath:      "/upload",
+			whenBodyDelay: 200 * time.Millisecond,
+			expectStatus:  http.StatusOK,
+			expectBody:    `{"ctx_err":"","name":"jon"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.BodyReadTimeout = 100 * time.Millisecond
+			handler := func(c Context) error {
+				var u struct {
+					Name string `json:"name"`
+				}
+				if err := c.Bind(&u); err != nil {
+					return err
+				}
+				// deadline must be cleared so it does not cancel request context after it expires
+				time.Sleep(150 * time.Millisecond)
+				ctxErr := ""
+				if err := c.Request().Context().Err(); err != nil {
+					ctxErr = err.Error()
+				}
+				return c.JSON(http.StatusOK, map[string]string{"name": u.Name, "ctx_err": ctxErr})
+			}
+			e.POST("/", handler)
+			e.WithRouteOptions(RouteBodyReadTimeout(time.Second)).POST("/upload", handler)
+
+			server := httptest.NewServer(e)
+			defer server.Close()
+
+			conn, err := net.Dial("tcp", server.Listener.Addr().String())
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer conn.Close()
+
+			body := `{"name":"jon"}`
+			_, err = fmt.Fprintf(conn, "POST %v HTTP/1.1\r\nHost: example.com\r\nContent-Type: application/json\r\nContent-Length: %d\r\n\r\n%v", tc.whenPath, len(body), body[:5])
+			assert.NoError(t, err)
+			time.Sleep(tc.whenBodyDelay)
+			_, _ = conn.Write([]byte(body[5:]))
+
+			res, err := http.ReadResponse(bufio.NewReader(conn), nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer res.Body.Close()
+			resBody, _ := io.ReadAll(res.Body)
+			assert.Equal(t, tc.expectStatus, res.StatusCode)
+			assert.Equal(t, tc.expectBody, strings.TrimSpace(string(resBody)))
+		})
+	}
+}
+
+func TestEcho_BodyReadTimeout_keepsServerReadTimeout(t *testing.T) {
+	e := New()
+	e.BodyReadTimeout = time.Second
+	var readErr error
+	var took time.Duration
+	e.POST("/", func(c Context) error {
+		// body read deadline is set and restored without reading anything
+		if err := c.(*context).readBody(func() error { return nil }); err != nil {
+			return err
+		}
+		start := time.Now()
+		_, readErr = io.ReadAll(c.Request().Body)
+		took = time.Since(start)
+		return c.NoContent(http.StatusNoContent)
+	})
+	server := httptest.NewUnstartedServer(e)
+	server.Config.ReadTimeout = 200 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	res := sendSlowForm(t, server.Listener.Addr().String(), MIMEApplicationForm, "name=jon&")
+	res.Body.Close()
+
+	var netErr net.Error
+	if assert.ErrorAs(t, readErr, &netErr) {
+		assert.True(t, netErr.Timeout())
+	}
+	assert.Less(t, took, time.Second)
+}
+
+func TestEcho_MaxBodyDrain(t *testing.T) {
+	var testCases = []struct {
+		name              string
+		givenMaxBodyDrain int64
+		whenBodySize      int
+		expectConns       int32
+		expectConnClose   bool
+	}{
+		{
+			name:              "ok, unread body is drained and connection is reused",
+			givenMaxBodyDrain: 1 << 20,
+			whenBodySize:      512 << 10,
+			expectConns:       1,
+		},
+		{
+			name:         "ok, small unread body is drained with default limit",
+			whenBodySize: 10 << 10,
+			expectConns:  1,
+		},
+		{
+			name:              "ok, body bigger than limit closes connection",
+			givenMaxBodyDrain: 1 << 10,
+			whenBodySize:      10 << 10,
+			expectConns:       3,
+			expectConnClose:   true,
+		},
+		{
+			name:              "ok, draining disabled leaves big body to http.Server which closes connection",
+			givenMaxBodyDrain: -1,
+			whenBodySize:      512 << 10,
+			expectConns:       3,
+			expectConnClose:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.MaxBodyDrain = tc.givenMaxBodyDrain
+			e.POST("/", func(c Context) error {
+				return ErrUnauthorized // rejected without reading the body
+			})
+
+			var conns int32
+			server := httptest.NewUnstartedServer(e)
+			server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					atomic.AddInt32(&conns, 1)
+				}
+			}
+			server.Start()
+			defer server.Close()
+
+			client := &http.Client{Transport: &http.Transport{}}
+			defer client.CloseIdleConnections()
+			body := bytes.Repeat([]byte("x"), tc.whenBodySize)
+			for i := 0; i < 3; i++ {
+				res, err := client.Post(server.URL+"/", MIMEOctetStream, bytes.NewReader(body))
+				if !assert.NoError(t, err) {
+					return
+				}
+				_, _ = io.Copy(io.Discard, res.Body)
+				res.Body.Close()
+
+				assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+				assert.Equal(t, tc.expectConnClose, res.Close)
+			}
+			assert.Equal(t, tc.expectConns, atomic.LoadInt32(&conns))
+		})
+	}
+}
+
+func TestEcho_MaxBodyDrain_readAfterWriteHeader(t *testing.T) {
+	var testCases = []struct {
+		name            string
+		givenFullDuplex bool
+	}{
+		{name: "ok, body is read after headers are written"},
+		{name: "ok, body is read after headers are flushed in full duplex mode", givenFullDuplex: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.POST("/", func(c Context) error {
+				res := c.Response()
+				if tc.givenFullDuplex {
+					if err := http.NewResponseController(res).EnableFullDuplex(); err != nil {
+						return err
+					}
+				}
+				res.Header().Set(HeaderContentType, MIMETextPlain)
+				res.WriteHeader(http.StatusOK)
+				if tc.givenFullDuplex {
+					res.Flush()
+				}
+				body, err := io.ReadAll(c.Request().Body)
+				if err != nil {
+					return err
+				}
+				_, err = res.Write(body)
+				return err
+			})
+			server := httptest.NewServer(e)
+			defer server.Close()
+
+			res, err := http.Post(server.URL+"/", MIMETextPlain, strings.NewReader("hello world"))
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer res.Body.Close()
+			body, err := io.ReadAll(res.Body)
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, "hello world", string(body))
+		})
+	}
+}
+
+func TestEcho_leakedContextCorruptsOtherRequest(t *testing.T) {
+	// documents the bug the guard protects against: pooled context leaked by handler is reused by the next request
+	e := New()
+	var leaked Context
+	e.GET("/leak", func(c Context) error {
+		leaked = c
+		return c.String(http.StatusOK, "leak")
+	})
+	var reused bool
+	e.GET("/other", func(c Context) error {
+		if c == leaked {
+			reused = true
+			_ = leaked.String(http.StatusTeapot, "corrupted") // goroutine of the first request writing late
+			return nil
+		}
+		return c.String(http.StatusOK, "other")
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/leak", nil))
+	for i := 0; i < 100 && !reused; i++ { // sync.Pool gives no guarantees about reuse
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/other", nil))
+		if reused {
+			assert.Equal(t, http.StatusTeapot, rec.Code)
+			assert.Equal(t, "corrupted", rec.Body.String())
+		}
+	}
+}
+
+func TestEcho_GuardReleasedResponse(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenDebug  bool
+		expectPanic string
+	}{
+		{
+			name: "ok, writes fail with error",
+		},
+		{
+			name:        "ok, writes panic in debug mode",
+			givenDebug:  true,
+			expectPanic: "echo: response written after request to route GET /leak/:id has been completed, handler leaked echo.Context (use Context#Clone for work that outlives the request)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.Debug = tc.givenDebug
+			e.GuardReleasedResponse = true
+			leaked := make(chan Context, 1)
+			e.GET("/leak/:id", func(c Context) error {
+				leaked <- c
+				return c.String(http.StatusOK, "leak")
+			})
+			e.GET("/other", func(c Context) error {
+				return c.String(http.StatusOK, "other")
+			})
+
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/leak/1", nil))
+			c := <-leaked
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/other", nil))
+
+			if tc.expectPanic != "" {
+				assert.PanicsWithValue(t, tc.expectPanic, func() {
+					_ = c.String(http.StatusTeapot, "corrupted")
+				})
+			} else {
+				assert.ErrorIs(t, c.String(http.StatusTeapot, "corrupted"), ErrResponseReleased)
+				_, err := c.Response().Write([]byte("corrupted"))
+				assert.ErrorIs(t, err, ErrResponseReleased)
+			}
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "other", rec.Body.String())
+		})
+	}
+}
+
+func TestEcho_DebugWithoutGuardReleasedResponse(t *testing.T) {
+	e := New()
+	e.Debug = true
+	var ctx Context
+	e.GET("/", func(c Context) error {
+		ctx = c
+		return c.String(http.StatusOK, "OK")
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// context is returned to the pool unguarded
+	assert.False(t, ctx.Response().released.Load())
+}
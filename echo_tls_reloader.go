@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	stdContext "context"
+	"crypto/tls"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// CertificateReloader serves TLS certificate from certificate and key files and reloads it when files change, without
+// restarting the server. Use `CertificateReloader#GetCertificate` as `tls.Config.GetCertificate` callback.
+//
+// Example with custom server:
+//
+//	reloader, err := echo.NewCertificateReloader("cert.pem", "key.pem")
+//	if err != nil {
+//		return err
+//	}
+//	go reloader.Watch(ctx, time.Minute)
+//	s := &http.Server{TLSConfig: &tls.Config{GetCertificate: reloader.GetCertificate}}
+type CertificateReloader struct {
+	// Logger is used to log results of reloads. Optional.
+	Logger Logger
+
+	certFile string
+	keyFile  string
+	cert     atomic.Pointer[tls.Certificate]
+
+	mutex       sync.Mutex // serializes reloads
+	certModTime time.Time
+	keyModTime  time.Time
+}
+
+// NewCertificateReloader creates CertificateReloader and loads certificate from the files.
+func NewCertificateReloader(certFile, keyFile string) (*CertificateReloader, error) {
+	r := &CertificateReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate returns the current certificate. It implements `tls.Config.GetCertificate` callback.
+func (r *CertificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Load(), nil
+}
+
+// Reload reads certificate and key files and swaps the current certificate. Invalid certificate/key pair is not
+// swapped and the previous certificate is kept in use.
+func (r *CertificateReloader) Reload() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.reload()
+}
+
+func (r *CertificateReloader) reload() error {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return err
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	r.cert.Store(&cert)
+	r.certModTime = certInfo.ModTime()
+	r.keyModTime = keyInfo.ModTime()
+	return nil
+}
+
+// reloadIfChanged reloads certificate when modification time of the certificate or the key file has changed.
+func (r *CertificateReloader) reloadIfChanged() (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return false, err
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return false, err
+	}
+	if certInfo.ModTime().Equal(r.certModTime) && keyInfo.ModTime().Equal(r.keyModTime) {
+		return false, nil
+	}
+	return true, r.reload()
+}
+
+// Watch reloads certificate when certificate or key file modification time changes (checked every checkInterval) or
+// when the process receives SIGHUP signal. Zero checkInterval means that only SIGHUP triggers reload. Watch blocks
+// until the context is cancelled.
+func (r *CertificateReloader) Watch(ctx stdContext.Context, checkInterval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if checkInterval > 0 {
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.logResult(true, r.Reload())
+		case <-tick:
+			r.logResult(r.reloadIfChanged())
+		}
+	}
+}
+
+func (r *CertificateReloader) logResult(reloaded bool, err error) {
+	if r.Logger == nil {
+		return
+	}
+	if err != nil {
+		r.Logger.Errorf("echo: failed to reload TLS certificate %s: %v", r.certFile, err)
+		return
+	}
+	if reloaded {
+		r.Logger.Infof("echo: reloaded TLS certificate %s", r.certFile)
+	}
+}
+
+// StartTLSWithReloader starts an HTTPS server with certificate that is reloaded from the files when they change (checked
+// every checkInterval) or when the process receives SIGHUP signal. Connections are not dropped on reload, new
+// certificate is used for new TLS handshakes. Reload results are logged with `Echo#Logger`.
+func (e *Echo) StartTLSWithReloader(address, certFile, keyFile string, checkInterval time.Duration) error {
+	e.startupMutex.Lock()
+	reloader, err := NewCertificateReloader(certFile, keyFile)
+	if err != nil {
+		e.startupMutex.Unlock()
+		return err
+	}
+	reloader.Logger = e.Logger
+
+	s := e.TLSServer
+	s.TLSConfig = new(tls.Config)
+	s.TLSConfig.GetCertificate = reloader.GetCertificate
+
+	e.configureTLS(address)
+	if err := e.configureServer(s); err != nil {
+		e.startupMutex.Unlock()
+		return err
+	}
+	e.startupMutex.Unlock()
+
+	ctx, cancel := stdContext.WithCancel(stdContext.Background())
+	defer cancel()
+	go reloader.Watch(ctx, checkInterval)
+
+	return s.Serve(e.TLSListener)
+}
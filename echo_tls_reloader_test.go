@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	stdContext "context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestCertificate(t *testing.T, certFile, keyFile, commonName string, modTime time.Time) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+}
+
+func testCertificateCommonName(t *testing.T, r *CertificateReloader) string {
+	cert, err := r.GetCertificate(nil)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	return leaf.Subject.CommonName
+}
+
+func TestCertificateReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	now := time.Now()
+	writeTestCertificate(t, certFile, keyFile, "first", now.Add(-time.Minute))
+
+	r, err := NewCertificateReloader(certFile, keyFile)
+	require.NoError(t, err)
+	assert.Equal(t, "first", testCertificateCommonName(t, r))
+
+	reloaded, err := r.reloadIfChanged()
+	assert.NoError(t, err)
+	assert.False(t, reloaded)
+
+	// invalid pair is not swapped
+	require.NoError(t, os.WriteFile(keyFile, []byte("invalid"), 0o600))
+	reloaded, err = r.reloadIfChanged()
+	assert.True(t, reloaded)
+	assert.Error(t, err)
+	assert.Equal(t, "first", testCertificateCommonName(t, r))
+
+	writeTestCertificate(t, certFile, keyFile, "second", now)
+	reloaded, err = r.reloadIfChanged()
+	assert.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, "second", testCertificateCommonName(t, r))
+}
+
+func TestCertificateReloader_Watch(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	writeTestCertificate(t, certFile, keyFile, "first", time.Now().Add(-time.Minute))
+
+	r, err := NewCertificateReloader(certFile, keyFile)
+	require.NoError(t, err)
+
+	ctx, cancel := stdContext.WithCancel(stdContext.Background())
+	defer cancel()
+	go r.Watch(ctx, 5*time.Millisecond)
+
+	writeTestCertificate(t, certFile, keyFile, "second", time.Now())
+	assert.Eventually(t, func() bool {
+		return testCertificateCommonName(t, r) == "second"
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestEcho_StartTLSWithReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	writeTestCertificate(t, certFile, keyFile, "first", time.Now().Add(-time.Minute))
+
+	e := New()
+	errChan := make(chan error)
+	go func() {
+		errChan <- e.StartTLSWithReloader("localhost:", certFile, keyFile, 5*time.Millisecond)
+	}()
+	require.NoError(t, waitForServerStart(e, errChan, true))
+	defer e.Shutdown(stdContext.Background())
+
+	serverCommonName := func() string {
+		conn, err := tls.Dial("tcp", e.TLSListenerAddr().String(), &tls.Config{InsecureSkipVerify: true})
+		if err != nil {
+			return err.Error()
+		}
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+	}
+	assert.Equal(t, "first", serverCommonName())
+
+	writeTestCertificate(t, certFile, keyFile, "second", time.Now())
+	assert.Eventually(t, func() bool {
+		return serverCommonName() == "second"
+	}, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,335 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+// Command gen generates MockContext of echomock package from the `echo.Context` interface.
+//
+// Usage:
+//
+//	go run ./internal/gen -src .. -out mock_context_gen.go
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const echoImportPath = "github.com/labstack/echo/v4"
+
+func main() {
+	src := flag.String("src", "..", "directory of echo package")
+	out := flag.String("out", "mock_context_gen.go", "output file")
+	flag.Parse()
+
+	code, err := generate(*src)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, code, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// method is method of the interface with types of its signature qualified for echomock package.
+type method struct {
+	name     string
+	params   []field
+	results  []string
+	variadic bool
+}
+
+type field struct {
+	name string
+	typ  string
+}
+
+// generator resolves methods of interfaces declared in echo package.
+type generator struct {
+	interfaces map[string]*ast.InterfaceType
+	// fileImports maps interface name to imports (name -> path) of the file it is declared in
+	fileImports map[string]map[string]string
+	// imports are imports used by generated code (path -> name)
+	imports map[string]string
+}
+
+func generate(srcDir string) ([]byte, error) {
+	g := &generator{
+		interfaces:  map[string]*ast.InterfaceType{},
+		fileImports: map[string]map[string]string{},
+		imports:     map[string]string{},
+	}
+	if err := g.parse(srcDir); err != nil {
+		return nil, err
+	}
+	methods, err := g.methods("Context", map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].name < methods[j].name
+	})
+	return g.render(methods)
+}
+
+func (g *generator) parse(srcDir string) error {
+	files, err := filepath.Glob(filepath.Join(srcDir, "*.go"))
+	if err != nil {
+		return err
+	}
+	fset := token.NewFileSet()
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+		imports := map[string]string{}
+		for _, spec := range f.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			name := importPath[strings.LastIndex(importPath, "/")+1:]
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			imports[name] = importPath
+		}
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if it, ok := ts.Type.(*ast.InterfaceType); ok {
+					g.interfaces[ts.Name.Name] = it
+					g.fileImports[ts.Name.Name] = imports
+				}
+			}
+		}
+	}
+	if _, ok := g.interfaces["Context"]; !ok {
+		return errors.New("gen: Context interface not found in " + srcDir)
+	}
+	return nil
+}
+
+// methods returns methods of interface including methods of embedded interfaces.
+func (g *generator) methods(name string, seen map[string]bool) ([]method, error) {
+	it, ok := g.interfaces[name]
+	if !ok {
+		return nil, fmt.Errorf("gen: interface %s not found", name)
+	}
+	imports := g.fileImports[name]
+	var result []method
+	for _, f := range it.Methods.List {
+		ft, ok := f.Type.(*ast.FuncType)
+		if !ok {
+			ident, ok := f.Type.(*ast.Ident)
+			if !ok {
+				return nil, fmt.Errorf("gen: unsupported embedded type in %s", name)
+			}
+			embedded, err := g.methods(ident.Name, seen)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, embedded...)
+			continue
+		}
+		m := method{name: f.Names[0].Name}
+		if seen[m.name] {
+			continue
+		}
+		seen[m.name] = true
+		for i, p := range ft.Params.List {
+			if _, ok := p.Type.(*ast.Ellipsis); ok {
+				m.variadic = true
+			}
+			typ := g.typeString(p.Type, imports)
+			if len(p.Names) == 0 {
+				m.params = append(m.params, field{name: fmt.Sprintf("p%d", i), typ: typ})
+			}
+			for _, n := range p.Names {
+				if n.Name == "m" {
+					return nil, fmt.Errorf("gen: param of %s.%s conflicts with receiver name", name, m.name)
+				}
+				m.params = append(m.params, field{name: n.Name, typ: typ})
+			}
+		}
+		if ft.Results != nil {
+			for _, r := range ft.Results.List {
+				n := len(r.Names)
+				if n == 0 {
+					n = 1
+				}
+				for i := 0; i < n; i++ {
+					m.results = append(m.results, g.typeString(r.Type, imports))
+				}
+			}
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
+
+// typeString returns type expression as it is written in echomock package: exported identifiers of echo package are
+// qualified with `echo.` and used imports are recorded.
+func (g *generator) typeString(expr ast.Expr, imports map[string]string) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if ast.IsExported(t.Name) {
+			g.imports[echoImportPath] = "echo"
+			return "echo." + t.Name
+		}
+		return t.Name
+	case *ast.SelectorExpr:
+		pkg := t.X.(*ast.Ident).Name
+		g.imports[imports[pkg]] = pkg
+		return pkg + "." + t.Sel.Name
+	case *ast.StarExpr:
+		return "*" + g.typeString(t.X, imports)
+	case *ast.ArrayType:
+		return "[]" + g.typeString(t.Elt, imports)
+	case *ast.Ellipsis:
+		return "..." + g.typeString(t.Elt, imports)
+	case *ast.MapType:
+		return "map[" + g.typeString(t.Key, imports) + "]" + g.typeString(t.Value, imports)
+	case *ast.InterfaceType:
+		return "interface{}"
+	case *ast.FuncType:
+		var params []string
+		for _, p := range t.Params.List {
+			typ := g.typeString(p.Type, imports)
+			names := make([]string, 0, len(p.Names))
+			for _, n := range p.Names {
+				names = append(names, n.Name)
+			}
+			if len(names) > 0 {
+				typ = strings.Join(names, ", ") + " " + typ
+			}
+			params = append(params, typ)
+		}
+		s := "func(" + strings.Join(params, ", ") + ")"
+		if t.Results != nil {
+			var results []string
+			for _, r := range t.Results.List {
+				results = append(results, g.typeString(r.Type, imports))
+			}
+			if len(results) == 1 {
+				s += " " + results[0]
+			} else {
+				s += " (" + strings.Join(results, ", ") + ")"
+			}
+		}
+		return s
+	}
+	panic(fmt.Sprintf("gen: unsupported type expression %T", expr))
+}
+
+func (g *generator) render(methods []method) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteString("// Code generated by echomock/internal/gen from echo.Context interface. DO NOT EDIT.\n\n")
+	buf.WriteString("package echomock\n\nimport (\n")
+	paths := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if path == echoImportPath {
+			continue
+		}
+		name := g.imports[path]
+		if name == path[strings.LastIndex(path, "/")+1:] {
+			fmt.Fprintf(buf, "\t%q\n", path)
+		} else {
+			fmt.Fprintf(buf, "\t%s %q\n", name, path)
+		}
+	}
+	if _, ok := g.imports[echoImportPath]; ok {
+		fmt.Fprintf(buf, "\n\t%q\n", echoImportPath)
+	}
+	buf.WriteString(")\n\n")
+
+	buf.WriteString("// MockContext implements `echo.Context` for unit tests. Every call is recorded (see `Calls`). Return\n")
+	buf.WriteString("// values of a method are stubbed by setting its `<Method>Func` field. Methods without stub return zero\n")
+	buf.WriteString("// values.\n")
+	buf.WriteString("type MockContext struct {\n\trecorder\n\n")
+	for i, m := range methods {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(buf, "\t// %sFunc stubs %s method.\n", m.name, m.name)
+		fmt.Fprintf(buf, "\t%sFunc %s\n", m.name, m.funcType())
+	}
+	buf.WriteString("}\n")
+
+	for _, m := range methods {
+		names := make([]string, len(m.params))
+		for i, p := range m.params {
+			names[i] = p.name
+		}
+		args := strings.Join(names, ", ")
+		callArgs := args
+		if m.variadic {
+			callArgs += "..."
+		}
+		recordArgs := ""
+		if args != "" {
+			recordArgs = ", " + args
+		}
+
+		fmt.Fprintf(buf, "\n// %s records the call and calls %sFunc when set.\n", m.name, m.name)
+		fmt.Fprintf(buf, "func (m *MockContext) %s(%s)%s {\n", m.name, m.paramList(), m.resultList(true))
+		fmt.Fprintf(buf, "\tm.record(%q%s)\n", m.name, recordArgs)
+		fmt.Fprintf(buf, "\tif m.%sFunc != nil {\n", m.name)
+		if len(m.results) > 0 {
+			fmt.Fprintf(buf, "\t\treturn m.%sFunc(%s)\n\t}\n\treturn\n}\n", m.name, callArgs)
+		} else {
+			fmt.Fprintf(buf, "\t\tm.%sFunc(%s)\n\t}\n}\n", m.name, callArgs)
+		}
+	}
+	return format.Source(buf.Bytes())
+}
+
+func (m method) paramList() string {
+	params := make([]string, len(m.params))
+	for i, p := range m.params {
+		params[i] = p.name + " " + p.typ
+	}
+	return strings.Join(params, ", ")
+}
+
+// resultList returns results of the method. Named results are used so stubs can return zero values with bare return.
+func (m method) resultList(named bool) string {
+	if len(m.results) == 0 {
+		return ""
+	}
+	results := make([]string, len(m.results))
+	for i, r := range m.results {
+		if named {
+			results[i] = fmt.Sprintf("r%d %s", i, r)
+		} else {
+			results[i] = r
+		}
+	}
+	if len(results) == 1 && !named {
+		return " " + results[0]
+	}
+	return " (" + strings.Join(results, ", ") + ")"
+}
+
+func (m method) funcType() string {
+	return "func(" + m.paramList() + ")" + m.resultList(false)
+}
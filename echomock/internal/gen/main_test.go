@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate_upToDate(t *testing.T) {
+	code, err := generate("../../..")
+	assert.NoError(t, err)
+
+	current, err := os.ReadFile("../../mock_context_gen.go")
+	assert.NoError(t, err)
+	assert.Equal(t, string(current), string(code), "mock is out of date, run `go generate ./echomock`")
+}
@@ -0,0 +1,234 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+// Package echomock provides MockContext, an `echo.Context` implementation with stubbed methods and recorded calls for
+// testing middlewares and handlers without Echo instance.
+//
+// Example:
+//
+//	c := &echomock.MockContext{
+//		ParamFunc: func(name string) string { return "42" },
+//	}
+//	err := getUser(c)
+//
+//	c.AssertJSON(t, http.StatusOK, map[string]interface{}{"id": 42})
+//	c.AssertNotCalled(t, "Redirect")
+//
+// Assertions accept any value with `Errorf` method so they work with `*testing.T` and testify's `assert.TestingT`
+// and return result of the assertion the same way testify assertions do.
+package echomock
+
+//go:generate go run ./internal/gen -src .. -out mock_context_gen.go
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+var _ echo.Context = (*MockContext)(nil)
+
+// TestingT is the interface of test state assertions report failures to. It is implemented by `*testing.T` and
+// compatible with testify's `assert.TestingT`.
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// Anything matches any argument in `AssertCalled`.
+const Anything = "echomock.Anything"
+
+// Call is recorded call of MockContext method.
+type Call struct {
+	// Method is name of the called method.
+	Method string
+	// Args are arguments of the call. Variadic arguments are recorded as single slice.
+	Args []interface{}
+}
+
+// recorder records calls of MockContext methods.
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *recorder) record(method string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns all recorded calls in order they were made.
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsTo returns recorded calls of the method.
+func (r *recorder) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calls []Call
+	for _, c := range r.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// CallCount returns number of recorded calls of the method.
+func (r *recorder) CallCount(method string) int {
+	return len(r.CallsTo(method))
+}
+
+// ClearCalls removes all recorded calls.
+func (r *recorder) ClearCalls() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// AssertCalled asserts that the method was called at least once with given arguments. `Anything` matches any
+// argument. Arguments are compared with `reflect.DeepEqual`.
+func (r *recorder) AssertCalled(t TestingT, method string, args ...interface{}) bool {
+	helper(t)
+	calls := r.CallsTo(method)
+	for _, c := range calls {
+		if argsMatch(args, c.Args) {
+			return true
+		}
+	}
+	if len(calls) == 0 {
+		t.Errorf("echomock: expected %s to be called, but it was not called", method)
+		return false
+	}
+	t.Errorf("echomock: expected %s to be called with %v, but it was called with:\n%s", method, args, formatCalls(calls))
+	return false
+}
+
+// AssertNotCalled asserts that the method was not called.
+func (r *recorder) AssertNotCalled(t TestingT, method string) bool {
+	helper(t)
+	if calls := r.CallsTo(method); len(calls) > 0 {
+		t.Errorf("echomock: expected %s not to be called, but it was called with:\n%s", method, formatCalls(calls))
+		return false
+	}
+	return true
+}
+
+// AssertCallCount asserts that the method was called exactly n times.
+func (r *recorder) AssertCallCount(t TestingT, method string, n int) bool {
+	helper(t)
+	if count := r.CallCount(method); count != n {
+		t.Errorf("echomock: expected %s to be called %d times, but it was called %d times", method, n, count)
+		return false
+	}
+	return true
+}
+
+// AssertJSON asserts that JSON, JSONPretty or JSONBlob was called with the status code and body that is equal to
+// expected after both are encoded to JSON (so struct and map with the same fields match). Expected of type string,
+// []byte or `json.RawMessage` is treated as JSON document.
+func (r *recorder) AssertJSON(t TestingT, code int, expected interface{}) bool {
+	helper(t)
+	want, err := normalizeJSON(expected, true)
+	if err != nil {
+		t.Errorf("echomock: can not encode expected JSON: %v", err)
+		return false
+	}
+	var calls []Call
+	for _, c := range r.Calls() {
+		if c.Method != "JSON" && c.Method != "JSONPretty" && c.Method != "JSONBlob" {
+			continue
+		}
+		calls = append(calls, c)
+		if c.Args[0] != code {
+			continue
+		}
+		got, err := normalizeJSON(c.Args[1], c.Method == "JSONBlob")
+		if err == nil && bytes.Equal(got, want) {
+			return true
+		}
+	}
+	if len(calls) == 0 {
+		t.Errorf("echomock: expected JSON response with status %d, but no JSON response was sent", code)
+		return false
+	}
+	t.Errorf("echomock: expected JSON response with status %d and body %s, but got:\n%s", code, want, formatCalls(calls))
+	return false
+}
+
+func helper(t TestingT) {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+}
+
+func argsMatch(expected []interface{}, actual []interface{}) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	for i := range expected {
+		if expected[i] == Anything {
+			continue
+		}
+		if !reflect.DeepEqual(expected[i], actual[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeJSON encodes v to compact JSON with object keys sorted. When raw is true string and []byte are treated as
+// JSON documents.
+func normalizeJSON(v interface{}, raw bool) ([]byte, error) {
+	var data []byte
+	switch tv := v.(type) {
+	case string:
+		if raw {
+			data = []byte(tv)
+		}
+	case []byte:
+		if raw {
+			data = tv
+		}
+	case json.RawMessage:
+		data = tv
+	}
+	if data == nil {
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	return json.Marshal(decoded)
+}
+
+func formatCalls(calls []Call) string {
+	var sb strings.Builder
+	for _, c := range calls {
+		fmt.Fprintf(&sb, "\t%s(", c.Method)
+		for i, a := range c.Args {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			if b, ok := a.([]byte); ok {
+				fmt.Fprintf(&sb, "%q", b)
+			} else {
+				fmt.Fprintf(&sb, "%#v", a)
+			}
+		}
+		sb.WriteString(")\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,1076 @@
+// Code generated by echomock/internal/gen from echo.Context interface. DO NOT EDIT.
+
+package echomock
+
+import (
+	stdContext "context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// MockContext implements `echo.Context` for unit tests. Every call is recorded (see `Calls`). Return
+// values of a method are stubbed by setting its `<Method>Func` field. Methods without stub return zero
+// values.
+type MockContext struct {
+	recorder
+
+	// AddLogFieldsFunc stubs AddLogFields method.
+	AddLogFieldsFunc func(fields map[string]interface{})
+
+	// AttachmentFunc stubs Attachment method.
+	AttachmentFunc func(file string, name string) error
+
+	// BindFunc stubs Bind method.
+	BindFunc func(i interface{}) error
+
+	// BindBodyFunc stubs BindBody method.
+	BindBodyFunc func(i interface{}) error
+
+	// BindFormFunc stubs BindForm method.
+	BindFormFunc func(i interface{}) error
+
+	// BindHeadersFunc stubs BindHeaders method.
+	BindHeadersFunc func(i interface{}) error
+
+	// BindQueryFunc stubs BindQuery method.
+	BindQueryFunc func(i interface{}) error
+
+	// BlobFunc stubs Blob method.
+	BlobFunc func(code int, contentType string, b []byte) error
+
+	// CachePolicyFunc stubs CachePolicy method.
+	CachePolicyFunc func() *echo.CachePolicy
+
+	// CloneFunc stubs Clone method.
+	CloneFunc func() echo.Context
+
+	// ConnectionInfoFunc stubs ConnectionInfo method.
+	ConnectionInfoFunc func() echo.ConnectionInfo
+
+	// CookieFunc stubs Cookie method.
+	CookieFunc func(name string) (*http.Cookie, error)
+
+	// CookiesFunc stubs Cookies method.
+	CookiesFunc func() []*http.Cookie
+
+	// DeferFunc stubs Defer method.
+	DeferFunc func(fn func(ctx stdContext.Context))
+
+	// DeleteCookieFunc stubs DeleteCookie method.
+	DeleteCookieFunc func(name string, opts ...echo.CookieOption) error
+
+	// EchoFunc stubs Echo method.
+	EchoFunc func() *echo.Echo
+
+	// ErrorFunc stubs Error method.
+	ErrorFunc func(err error)
+
+	// EvaluatePreconditionsFunc stubs EvaluatePreconditions method.
+	EvaluatePreconditionsFunc func(currentETag string, lastModified time.Time) (bool, int)
+
+	// ExpectsContinueFunc stubs ExpectsContinue method.
+	ExpectsContinueFunc func() bool
+
+	// FileFunc stubs File method.
+	FileFunc func(file string) error
+
+	// FormFileFunc stubs FormFile method.
+	FormFileFunc func(name string) (*multipart.FileHeader, error)
+
+	// FormParamsFunc stubs FormParams method.
+	FormParamsFunc func() (url.Values, error)
+
+	// FormValueFunc stubs FormValue method.
+	FormValueFunc func(name string) string
+
+	// GetFunc stubs Get method.
+	GetFunc func(key string) interface{}
+
+	// HTMLFunc stubs HTML method.
+	HTMLFunc func(code int, html string) error
+
+	// HTMLBlobFunc stubs HTMLBlob method.
+	HTMLBlobFunc func(code int, b []byte) error
+
+	// HandlerFunc stubs Handler method.
+	HandlerFunc func() echo.HandlerFunc
+
+	// InlineFunc stubs Inline method.
+	InlineFunc func(file string, name string) error
+
+	// IsInternalFunc stubs IsInternal method.
+	IsInternalFunc func() bool
+
+	// IsTLSFunc stubs IsTLS method.
+	IsTLSFunc func() bool
+
+	// IsWebSocketFunc stubs IsWebSocket method.
+	IsWebSocketFunc func() bool
+
+	// JSONFunc stubs JSON method.
+	JSONFunc func(code int, i interface{}) error
+
+	// JSONBlobFunc stubs JSONBlob method.
+	JSONBlobFunc func(code int, b []byte) error
+
+	// JSONPFunc stubs JSONP method.
+	JSONPFunc func(code int, callback string, i interface{}) error
+
+	// JSONPBlobFunc stubs JSONPBlob method.
+	JSONPBlobFunc func(code int, callback string, b []byte) error
+
+	// JSONPrettyFunc stubs JSONPretty method.
+	JSONPrettyFunc func(code int, i interface{}, indent string) error
+
+	// LoggerFunc stubs Logger method.
+	LoggerFunc func() echo.Logger
+
+	// MatrixParamFunc stubs MatrixParam method.
+	MatrixParamFunc func(segment string, name string) string
+
+	// MatrixParamsFunc stubs MatrixParams method.
+	MatrixParamsFunc func() url.Values
+
+	// MultipartFormFunc stubs MultipartForm method.
+	MultipartFormFunc func() (*multipart.Form, error)
+
+	// MultipartStreamFunc stubs MultipartStream method.
+	MultipartStreamFunc func(boundary string) *echo.PartWriter
+
+	// NoContentFunc stubs NoContent method.
+	NoContentFunc func(code int) error
+
+	// NotModifiedFunc stubs NotModified method.
+	NotModifiedFunc func() error
+
+	// ParamFunc stubs Param method.
+	ParamFunc func(name string) string
+
+	// ParamNamesFunc stubs ParamNames method.
+	ParamNamesFunc func() []string
+
+	// ParamSegmentsFunc stubs ParamSegments method.
+	ParamSegmentsFunc func(name string) []string
+
+	// ParamValuesFunc stubs ParamValues method.
+	ParamValuesFunc func() []string
+
+	// PathFunc stubs Path method.
+	PathFunc func() string
+
+	// PathParamsMapFunc stubs PathParamsMap method.
+	PathParamsMapFunc func() map[string]string
+
+	// PreconditionFailedFunc stubs PreconditionFailed method.
+	PreconditionFailedFunc func() error
+
+	// ProgressStreamFunc stubs ProgressStream method.
+	ProgressStreamFunc func(code int) *echo.ProgressStream
+
+	// PutUploadedFileFunc stubs PutUploadedFile method.
+	PutUploadedFileFunc func(fh *multipart.FileHeader, putter echo.FilePutter, name string, opts ...echo.SaveOption) (echo.SaveResult, error)
+
+	// QueryParamFunc stubs QueryParam method.
+	QueryParamFunc func(name string) string
+
+	// QueryParamIterFunc stubs QueryParamIter method.
+	QueryParamIterFunc func(fn func(key, value string) bool)
+
+	// QueryParamsFunc stubs QueryParams method.
+	QueryParamsFunc func() url.Values
+
+	// QueryStringFunc stubs QueryString method.
+	QueryStringFunc func() string
+
+	// RawParamFunc stubs RawParam method.
+	RawParamFunc func(name string) string
+
+	// ReadSignedCookieFunc stubs ReadSignedCookie method.
+	ReadSignedCookieFunc func(name string) (string, error)
+
+	// RealIPFunc stubs RealIP method.
+	RealIPFunc func() string
+
+	// RealIPAddrFunc stubs RealIPAddr method.
+	RealIPAddrFunc func() (netip.Addr, error)
+
+	// RedirectFunc stubs Redirect method.
+	RedirectFunc func(code int, url string) error
+
+	// RejectContinueFunc stubs RejectContinue method.
+	RejectContinueFunc func(code int, err error) error
+
+	// RenderFunc stubs Render method.
+	RenderFunc func(code int, name string, data interface{}) error
+
+	// RequestFunc stubs Request method.
+	RequestFunc func() *http.Request
+
+	// ResetFunc stubs Reset method.
+	ResetFunc func(r *http.Request, w http.ResponseWriter)
+
+	// ResponseFunc stubs Response method.
+	ResponseFunc func() *echo.Response
+
+	// RouteNameFunc stubs RouteName method.
+	RouteNameFunc func() string
+
+	// RouteScopesFunc stubs RouteScopes method.
+	RouteScopesFunc func() []string
+
+	// SaveUploadedFileFunc stubs SaveUploadedFile method.
+	SaveUploadedFileFunc func(fh *multipart.FileHeader, dst string, opts ...echo.SaveOption) (echo.SaveResult, error)
+
+	// SaveUploadedFileToFunc stubs SaveUploadedFileTo method.
+	SaveUploadedFileToFunc func(fh *multipart.FileHeader, w io.Writer, opts ...echo.SaveOption) (echo.SaveResult, error)
+
+	// SchemeFunc stubs Scheme method.
+	SchemeFunc func() string
+
+	// SetFunc stubs Set method.
+	SetFunc func(key string, val interface{})
+
+	// SetCookieFunc stubs SetCookie method.
+	SetCookieFunc func(cookie *http.Cookie)
+
+	// SetCookieValueFunc stubs SetCookieValue method.
+	SetCookieValueFunc func(name string, value string, opts ...echo.CookieOption) error
+
+	// SetHandlerFunc stubs SetHandler method.
+	SetHandlerFunc func(h echo.HandlerFunc)
+
+	// SetLoggerFunc stubs SetLogger method.
+	SetLoggerFunc func(l echo.Logger)
+
+	// SetParamNamesFunc stubs SetParamNames method.
+	SetParamNamesFunc func(names ...string)
+
+	// SetParamValuesFunc stubs SetParamValues method.
+	SetParamValuesFunc func(values ...string)
+
+	// SetPathFunc stubs SetPath method.
+	SetPathFunc func(p string)
+
+	// SetRequestFunc stubs SetRequest method.
+	SetRequestFunc func(r *http.Request)
+
+	// SetResponseFunc stubs SetResponse method.
+	SetResponseFunc func(r *echo.Response)
+
+	// SignedCookieFunc stubs SignedCookie method.
+	SignedCookieFunc func(name string, value string, opts ...echo.CookieOption) error
+
+	// StreamFunc stubs Stream method.
+	StreamFunc func(code int, contentType string, r io.Reader) error
+
+	// StringFunc stubs String method.
+	StringFunc func(code int, s string) error
+
+	// SuppressedErrorsFunc stubs SuppressedErrors method.
+	SuppressedErrorsFunc func() []error
+
+	// ValidateFunc stubs Validate method.
+	ValidateFunc func(i interface{}) error
+
+	// XMLFunc stubs XML method.
+	XMLFunc func(code int, i interface{}) error
+
+	// XMLBlobFunc stubs XMLBlob method.
+	XMLBlobFunc func(code int, b []byte) error
+
+	// XMLPrettyFunc stubs XMLPretty method.
+	XMLPrettyFunc func(code int, i interface{}, indent string) error
+}
+
+// AddLogFields records the call and calls AddLogFieldsFunc when set.
+func (m *MockContext) AddLogFields(fields map[string]interface{}) {
+	m.record("AddLogFields", fields)
+	if m.AddLogFieldsFunc != nil {
+		m.AddLogFieldsFunc(fields)
+	}
+}
+
+// Attachment records the call and calls AttachmentFunc when set.
+func (m *MockContext) Attachment(file string, name string) (r0 error) {
+	m.record("Attachment", file, name)
+	if m.AttachmentFunc != nil {
+		return m.AttachmentFunc(file, name)
+	}
+	return
+}
+
+// Bind records the call and calls BindFunc when set.
+func (m *MockContext) Bind(i interface{}) (r0 error) {
+	m.record("Bind", i)
+	if m.BindFunc != nil {
+		return m.BindFunc(i)
+	}
+	return
+}
+
+// BindBody records the call and calls BindBodyFunc when set.
+func (m *MockContext) BindBody(i interface{}) (r0 error) {
+	m.record("BindBody", i)
+	if m.BindBodyFunc != nil {
+		return m.BindBodyFunc(i)
+	}
+	return
+}
+
+// BindForm records the call and calls BindFormFunc when set.
+func (m *MockContext) BindForm(i interface{}) (r0 error) {
+	m.record("BindForm", i)
+	if m.BindFormFunc != nil {
+		return m.BindFormFunc(i)
+	}
+	return
+}
+
+// BindHeaders records the call and calls BindHeadersFunc when set.
+func (m *MockContext) BindHeaders(i interface{}) (r0 error) {
+	m.record("BindHeaders", i)
+	if m.BindHeadersFunc != nil {
+		return m.BindHeadersFunc(i)
+	}
+	return
+}
+
+// BindQuery records the call and calls BindQueryFunc when set.
+func (m *MockContext) BindQuery(i interface{}) (r0 error) {
+	m.record("BindQuery", i)
+	if m.BindQueryFunc != nil {
+		return m.BindQueryFunc(i)
+	}
+	return
+}
+
+// Blob records the call and calls BlobFunc when set.
+func (m *MockContext) Blob(code int, contentType string, b []byte) (r0 error) {
+	m.record("Blob", code, contentType, b)
+	if m.BlobFunc != nil {
+		return m.BlobFunc(code, contentType, b)
+	}
+	return
+}
+
+// CachePolicy records the call and calls CachePolicyFunc when set.
+func (m *MockContext) CachePolicy() (r0 *echo.CachePolicy) {
+	m.record("CachePolicy")
+	if m.CachePolicyFunc != nil {
+		return m.CachePolicyFunc()
+	}
+	return
+}
+
+// Clone records the call and calls CloneFunc when set.
+func (m *MockContext) Clone() (r0 echo.Context) {
+	m.record("Clone")
+	if m.CloneFunc != nil {
+		return m.CloneFunc()
+	}
+	return
+}
+
+// ConnectionInfo records the call and calls ConnectionInfoFunc when set.
+func (m *MockContext) ConnectionInfo() (r0 echo.ConnectionInfo) {
+	m.record("ConnectionInfo")
+	if m.ConnectionInfoFunc != nil {
+		return m.ConnectionInfoFunc()
+	}
+	return
+}
+
+// Cookie records the call and calls CookieFunc when set.
+func (m *MockContext) Cookie(name string) (r0 *http.Cookie, r1 error) {
+	m.record("Cookie", name)
+	if m.CookieFunc != nil {
+		return m.CookieFunc(name)
+	}
+	return
+}
+
+// Cookies records the call and calls CookiesFunc when set.
+func (m *MockContext) Cookies() (r0 []*http.Cookie) {
+	m.record("Cookies")
+	if m.CookiesFunc != nil {
+		return m.CookiesFunc()
+	}
+	return
+}
+
+// Defer records the call and calls DeferFunc when set.
+func (m *MockContext) Defer(fn func(ctx stdContext.Context)) {
+	m.record("Defer", fn)
+	if m.DeferFunc != nil {
+		m.DeferFunc(fn)
+	}
+}
+
+// DeleteCookie records the call and calls DeleteCookieFunc when set.
+func (m *MockContext) DeleteCookie(name string, opts ...echo.CookieOption) (r0 error) {
+	m.record("DeleteCookie", name, opts)
+	if m.DeleteCookieFunc != nil {
+		return m.DeleteCookieFunc(name, opts...)
+	}
+	return
+}
+
+// Echo records the call and calls EchoFunc when set.
+func (m *MockContext) Echo() (r0 *echo.Echo) {
+	m.record("Echo")
+	if m.EchoFunc != nil {
+		return m.EchoFunc()
+	}
+	return
+}
+
+// Error records the call and calls ErrorFunc when set.
+func (m *MockContext) Error(err error) {
+	m.record("Error", err)
+	if m.ErrorFunc != nil {
+		m.ErrorFunc(err)
+	}
+}
+
+// EvaluatePreconditions records the call and calls EvaluatePreconditionsFunc when set.
+func (m *MockContext) EvaluatePreconditions(currentETag string, lastModified time.Time) (r0 bool, r1 int) {
+	m.record("EvaluatePreconditions", currentETag, lastModified)
+	if m.EvaluatePreconditionsFunc != nil {
+		return m.EvaluatePreconditionsFunc(currentETag, lastModified)
+	}
+	return
+}
+
+// ExpectsContinue records the call and calls ExpectsContinueFunc when set.
+func (m *MockContext) ExpectsContinue() (r0 bool) {
+	m.record("ExpectsContinue")
+	if m.ExpectsContinueFunc != nil {
+		return m.ExpectsContinueFunc()
+	}
+	return
+}
+
+// File records the call and calls FileFunc when set.
+func (m *MockContext) File(file string) (r0 error) {
+	m.record("File", file)
+	if m.FileFunc != nil {
+		return m.FileFunc(file)
+	}
+	return
+}
+
+// FormFile records the call and calls FormFileFunc when set.
+func (m *MockContext) FormFile(name string) (r0 *multipart.FileHeader, r1 error) {
+	m.record("FormFile", name)
+	if m.FormFileFunc != nil {
+		return m.FormFileFunc(name)
+	}
+	return
+}
+
+// FormParams records the call and calls FormParamsFunc when set.
+func (m *MockContext) FormParams() (r0 url.Values, r1 error) {
+	m.record("FormParams")
+	if m.FormParamsFunc != nil {
+		return m.FormParamsFunc()
+	}
+	return
+}
+
+// FormValue records the call and calls FormValueFunc when set.
+func (m *MockContext) FormValue(name string) (r0 string) {
+	m.record("FormValue", name)
+	if m.FormValueFunc != nil {
+		return m.FormValueFunc(name)
+	}
+	return
+}
+
+// Get records the call and calls GetFunc when set.
+func (m *MockContext) Get(key string) (r0 interface{}) {
+	m.record("Get", key)
+	if m.GetFunc != nil {
+		return m.GetFunc(key)
+	}
+	return
+}
+
+// HTML records the call and calls HTMLFunc when set.
+func (m *MockContext) HTML(code int, html string) (r0 error) {
+	m.record("HTML", code, html)
+	if m.HTMLFunc != nil {
+		return m.HTMLFunc(code, html)
+	}
+	return
+}
+
+// HTMLBlob records the call and calls HTMLBlobFunc when set.
+func (m *MockContext) HTMLBlob(code int, b []byte) (r0 error) {
+	m.record("HTMLBlob", code, b)
+	if m.HTMLBlobFunc != nil {
+		return m.HTMLBlobFunc(code, b)
+	}
+	return
+}
+
+// Handler records the call and calls HandlerFunc when set.
+func (m *MockContext) Handler() (r0 echo.HandlerFunc) {
+	m.record("Handler")
+	if m.HandlerFunc != nil {
+		return m.HandlerFunc()
+	}
+	return
+}
+
+// Inline records the call and calls InlineFunc when set.
+func (m *MockContext) Inline(file string, name string) (r0 error) {
+	m.record("Inline", file, name)
+	if m.InlineFunc != nil {
+		return m.InlineFunc(file, name)
+	}
+	return
+}
+
+// IsInternal records the call and calls IsInternalFunc when set.
+func (m *MockContext) IsInternal() (r0 bool) {
+	m.record("IsInternal")
+	if m.IsInternalFunc != nil {
+		return m.IsInternalFunc()
+	}
+	return
+}
+
+// IsTLS records the call and calls IsTLSFunc when set.
+func (m *MockContext) IsTLS() (r0 bool) {
+	m.record("IsTLS")
+	if m.IsTLSFunc != nil {
+		return m.IsTLSFunc()
+	}
+	return
+}
+
+// IsWebSocket records the call and calls IsWebSocketFunc when set.
+func (m *MockContext) IsWebSocket() (r0 bool) {
+	m.record("IsWebSocket")
+	if m.IsWebSocketFunc != nil {
+		return m.IsWebSocketFunc()
+	}
+	return
+}
+
+// JSON records the call and calls JSONFunc when set.
+func (m *MockContext) JSON(code int, i interface{}) (r0 error) {
+	m.record("JSON", code, i)
+	if m.JSONFunc != nil {
+		return m.JSONFunc(code, i)
+	}
+	return
+}
+
+// JSONBlob records the call and calls JSONBlobFunc when set.
+func (m *MockContext) JSONBlob(code int, b []byte) (r0 error) {
+	m.record("JSONBlob", code, b)
+	if m.JSONBlobFunc != nil {
+		return m.JSONBlobFunc(code, b)
+	}
+	return
+}
+
+// JSONP records the call and calls JSONPFunc when set.
+func (m *MockContext) JSONP(code int, callback string, i interface{}) (r0 error) {
+	m.record("JSONP", code, callback, i)
+	if m.JSONPFunc != nil {
+		return m.JSONPFunc(code, callback, i)
+	}
+	return
+}
+
+// JSONPBlob records the call and calls JSONPBlobFunc when set.
+func (m *MockContext) JSONPBlob(code int, callback string, b []byte) (r0 error) {
+	m.record("JSONPBlob", code, callback, b)
+	if m.JSONPBlobFunc != nil {
+		return m.JSONPBlobFunc(code, callback, b)
+	}
+	return
+}
+
+// JSONPretty records the call and calls JSONPrettyFunc when set.
+func (m *MockContext) JSONPretty(code int, i interface{}, indent string) (r0 error) {
+	m.record("JSONPretty", code, i, indent)
+	if m.JSONPrettyFunc != nil {
+		return m.JSONPrettyFunc(code, i, indent)
+	}
+	return
+}
+
+// Logger records the call and calls LoggerFunc when set.
+func (m *MockContext) Logger() (r0 echo.Logger) {
+	m.record("Logger")
+	if m.LoggerFunc != nil {
+		return m.LoggerFunc()
+	}
+	return
+}
+
+// MatrixParam records the call and calls MatrixParamFunc when set.
+func (m *MockContext) MatrixParam(segment string, name string) (r0 string) {
+	m.record("MatrixParam", segment, name)
+	if m.MatrixParamFunc != nil {
+		return m.MatrixParamFunc(segment, name)
+	}
+	return
+}
+
+// MatrixParams records the call and calls MatrixParamsFunc when set.
+func (m *MockContext) MatrixParams() (r0 url.Values) {
+	m.record("MatrixParams")
+	if m.MatrixParamsFunc != nil {
+		return m.MatrixParamsFunc()
+	}
+	return
+}
+
+// MultipartForm records the call and calls MultipartFormFunc when set.
+func (m *MockContext) MultipartForm() (r0 *multipart.Form, r1 error) {
+	m.record("MultipartForm")
+	if m.MultipartFormFunc != nil {
+		return m.MultipartFormFunc()
+	}
+	return
+}
+
+// MultipartStream records the call and calls MultipartStreamFunc when set.
+func (m *MockContext) MultipartStream(boundary string) (r0 *echo.PartWriter) {
+	m.record("MultipartStream", boundary)
+	if m.MultipartStreamFunc != nil {
+		return m.MultipartStreamFunc(boundary)
+	}
+	return
+}
+
+// NoContent records the call and calls NoContentFunc when set.
+func (m *MockContext) NoContent(code int) (r0 error) {
+	m.record("NoContent", code)
+	if m.NoContentFunc != nil {
+		return m.NoContentFunc(code)
+	}
+	return
+}
+
+// NotModified records the call and calls NotModifiedFunc when set.
+func (m *MockContext) NotModified() (r0 error) {
+	m.record("NotModified")
+	if m.NotModifiedFunc != nil {
+		return m.NotModifiedFunc()
+	}
+	return
+}
+
+// Param records the call and calls ParamFunc when set.
+func (m *MockContext) Param(name string) (r0 string) {
+	m.record("Param", name)
+	if m.ParamFunc != nil {
+		return m.ParamFunc(name)
+	}
+	return
+}
+
+// ParamNames records the call and calls ParamNamesFunc when set.
+func (m *MockContext) ParamNames() (r0 []string) {
+	m.record("ParamNames")
+	if m.ParamNamesFunc != nil {
+		return m.ParamNamesFunc()
+	}
+	return
+}
+
+// ParamSegments records the call and calls ParamSegmentsFunc when set.
+func (m *MockContext) ParamSegments(name string) (r0 []string) {
+	m.record("ParamSegments", name)
+	if m.ParamSegmentsFunc != nil {
+		return m.ParamSegmentsFunc(name)
+	}
+	return
+}
+
+// ParamValues records the call and calls ParamValuesFunc when set.
+func (m *MockContext) ParamValues() (r0 []string) {
+	m.record("ParamValues")
+	if m.ParamValuesFunc != nil {
+		return m.ParamValuesFunc()
+	}
+	return
+}
+
+// Path records the call and calls PathFunc when set.
+func (m *MockContext) Path() (r0 string) {
+	m.record("Path")
+	if m.PathFunc != nil {
+		return m.PathFunc()
+	}
+	return
+}
+
+// PathParamsMap records the call and calls PathParamsMapFunc when set.
+func (m *MockContext) PathParamsMap() (r0 map[string]string) {
+	m.record("PathParamsMap")
+	if m.PathParamsMapFunc != nil {
+		return m.PathParamsMapFunc()
+	}
+	return
+}
+
+// PreconditionFailed records the call and calls PreconditionFailedFunc when set.
+func (m *MockContext) PreconditionFailed() (r0 error) {
+	m.record("PreconditionFailed")
+	if m.PreconditionFailedFunc != nil {
+		return m.PreconditionFailedFunc()
+	}
+	return
+}
+
+// ProgressStream records the call and calls ProgressStreamFunc when set.
+func (m *MockContext) ProgressStream(code int) (r0 *echo.ProgressStream) {
+	m.record("ProgressStream", code)
+	if m.ProgressStreamFunc != nil {
+		return m.ProgressStreamFunc(code)
+	}
+	return
+}
+
+// PutUploadedFile records the call and calls PutUploadedFileFunc when set.
+func (m *MockContext) PutUploadedFile(fh *multipart.FileHeader, putter echo.FilePutter, name string, opts ...echo.SaveOption) (r0 echo.SaveResult, r1 error) {
+	m.record("PutUploadedFile", fh, putter, name, opts)
+	if m.PutUploadedFileFunc != nil {
+		return m.PutUploadedFileFunc(fh, putter, name, opts...)
+	}
+	return
+}
+
+// QueryParam records the call and calls QueryParamFunc when set.
+func (m *MockContext) QueryParam(name string) (r0 string) {
+	m.record("QueryParam", name)
+	if m.QueryParamFunc != nil {
+		return m.QueryParamFunc(name)
+	}
+	return
+}
+
+// QueryParamIter records the call and calls QueryParamIterFunc when set.
+func (m *MockContext) QueryParamIter(fn func(key, value string) bool) {
+	m.record("QueryParamIter", fn)
+	if m.QueryParamIterFunc != nil {
+		m.QueryParamIterFunc(fn)
+	}
+}
+
+// QueryParams records the call and calls QueryParamsFunc when set.
+func (m *MockContext) QueryParams() (r0 url.Values) {
+	m.record("QueryParams")
+	if m.QueryParamsFunc != nil {
+		return m.QueryParamsFunc()
+	}
+	return
+}
+
+// QueryString records the call and calls QueryStringFunc when set.
+func (m *MockContext) QueryString() (r0 string) {
+	m.record("QueryString")
+	if m.QueryStringFunc != nil {
+		return m.QueryStringFunc()
+	}
+	return
+}
+
+// RawParam records the call and calls RawParamFunc when set.
+func (m *MockContext) RawParam(name string) (r0 string) {
+	m.record("RawParam", name)
+	if m.RawParamFunc != nil {
+		return m.RawParamFunc(name)
+	}
+	return
+}
+
+// ReadSignedCookie records the call and calls ReadSignedCookieFunc when set.
+func (m *MockContext) ReadSignedCookie(name string) (r0 string, r1 error) {
+	m.record("ReadSignedCookie", name)
+	if m.ReadSignedCookieFunc != nil {
+		return m.ReadSignedCookieFunc(name)
+	}
+	return
+}
+
+// RealIP records the call and calls RealIPFunc when set.
+func (m *MockContext) RealIP() (r0 string) {
+	m.record("RealIP")
+	if m.RealIPFunc != nil {
+		return m.RealIPFunc()
+	}
+	return
+}
+
+// RealIPAddr records the call and calls RealIPAddrFunc when set.
+func (m *MockContext) RealIPAddr() (r0 netip.Addr, r1 error) {
+	m.record("RealIPAddr")
+	if m.RealIPAddrFunc != nil {
+		return m.RealIPAddrFunc()
+	}
+	return
+}
+
+// Redirect records the call and calls RedirectFunc when set.
+func (m *MockContext) Redirect(code int, url string) (r0 error) {
+	m.record("Redirect", code, url)
+	if m.RedirectFunc != nil {
+		return m.RedirectFunc(code, url)
+	}
+	return
+}
+
+// RejectContinue records the call and calls RejectContinueFunc when set.
+func (m *MockContext) RejectContinue(code int, err error) (r0 error) {
+	m.record("RejectContinue", code, err)
+	if m.RejectContinueFunc != nil {
+		return m.RejectContinueFunc(code, err)
+	}
+	return
+}
+
+// Render records the call and calls RenderFunc when set.
+func (m *MockContext) Render(code int, name string, data interface{}) (r0 error) {
+	m.record("Render", code, name, data)
+	if m.RenderFunc != nil {
+		return m.RenderFunc(code, name, data)
+	}
+	return
+}
+
+// Request records the call and calls RequestFunc when set.
+func (m *MockContext) Request() (r0 *http.Request) {
+	m.record("Request")
+	if m.RequestFunc != nil {
+		return m.RequestFunc()
+	}
+	return
+}
+
+// Reset records the call and calls ResetFunc when set.
+func (m *MockContext) Reset(r *http.Request, w http.ResponseWriter) {
+	m.record("Reset", r, w)
+	if m.ResetFunc != nil {
+		m.ResetFunc(r, w)
+	}
+}
+
+// Response records the call and calls ResponseFunc when set.
+func (m *MockContext) Response() (r0 *echo.Response) {
+	m.record("Response")
+	if m.ResponseFunc != nil {
+		return m.ResponseFunc()
+	}
+	return
+}
+
+// RouteName records the call and calls RouteNameFunc when set.
+func (m *MockContext) RouteName() (r0 string) {
+	m.record("RouteName")
+	if m.RouteNameFunc != nil {
+		return m.RouteNameFunc()
+	}
+	return
+}
+
+// RouteScopes records the call and calls RouteScopesFunc when set.
+func (m *MockContext) RouteScopes() (r0 []string) {
+	m.record("RouteScopes")
+	if m.RouteScopesFunc != nil {
+		return m.RouteScopesFunc()
+	}
+	return
+}
+
+// SaveUploadedFile records the call and calls SaveUploadedFileFunc when set.
+func (m *MockContext) SaveUploadedFile(fh *multipart.FileHeader, dst string, opts ...echo.SaveOption) (r0 echo.SaveResult, r1 error) {
+	m.record("SaveUploadedFile", fh, dst, opts)
+	if m.SaveUploadedFileFunc != nil {
+		return m.SaveUploadedFileFunc(fh, dst, opts...)
+	}
+	return
+}
+
+// SaveUploadedFileTo records the call and calls SaveUploadedFileToFunc when set.
+func (m *MockContext) SaveUploadedFileTo(fh *multipart.FileHeader, w io.Writer, opts ...echo.SaveOption) (r0 echo.SaveResult, r1 error) {
+	m.record("SaveUploadedFileTo", fh, w, opts)
+	if m.SaveUploadedFileToFunc != nil {
+		return m.SaveUploadedFileToFunc(fh, w, opts...)
+	}
+	return
+}
+
+// Scheme records the call and calls SchemeFunc when set.
+func (m *MockContext) Scheme() (r0 string) {
+	m.record("Scheme")
+	if m.SchemeFunc != nil {
+		return m.SchemeFunc()
+	}
+	return
+}
+
+// Set records the call and calls SetFunc when set.
+func (m *MockContext) Set(key string, val interface{}) {
+	m.record("Set", key, val)
+	if m.SetFunc != nil {
+		m.SetFunc(key, val)
+	}
+}
+
+// SetCookie records the call and calls SetCookieFunc when set.
+func (m *MockContext) SetCookie(cookie *http.Cookie) {
+	m.record("SetCookie", cookie)
+	if m.SetCookieFunc != nil {
+		m.SetCookieFunc(cookie)
+	}
+}
+
+// SetCookieValue records the call and calls SetCookieValueFunc when set.
+func (m *MockContext) SetCookieValue(name string, value string, opts ...echo.CookieOption) (r0 error) {
+	m.record("SetCookieValue", name, value, opts)
+	if m.SetCookieValueFunc != nil {
+		return m.SetCookieValueFunc(name, value, opts...)
+	}
+	return
+}
+
+// SetHandler records the call and calls SetHandlerFunc when set.
+func (m *MockContext) SetHandler(h echo.HandlerFunc) {
+	m.record("SetHandler", h)
+	if m.SetHandlerFunc != nil {
+		m.SetHandlerFunc(h)
+	}
+}
+
+// SetLogger records the call and calls SetLoggerFunc when set.
+func (m *MockContext) SetLogger(l echo.Logger) {
+	m.record("SetLogger", l)
+	if m.SetLoggerFunc != nil {
+		m.SetLoggerFunc(l)
+	}
+}
+
+// SetParamNames records the call and calls SetParamNamesFunc when set.
+func (m *MockContext) SetParamNames(names ...string) {
+	m.record("SetParamNames", names)
+	if m.SetParamNamesFunc != nil {
+		m.SetParamNamesFunc(names...)
+	}
+}
+
+// SetParamValues records the call and calls SetParamValuesFunc when set.
+func (m *MockContext) SetParamValues(values ...string) {
+	m.record("SetParamValues", values)
+	if m.SetParamValuesFunc != nil {
+		m.SetParamValuesFunc(values...)
+	}
+}
+
+// SetPath records the call and calls SetPathFunc when set.
+func (m *MockContext) SetPath(p string) {
+	m.record("SetPath", p)
+	if m.SetPathFunc != nil {
+		m.SetPathFunc(p)
+	}
+}
+
+// SetRequest records the call and calls SetRequestFunc when set.
+func (m *MockContext) SetRequest(r *http.Request) {
+	m.record("SetRequest", r)
+	if m.SetRequestFunc != nil {
+		m.SetRequestFunc(r)
+	}
+}
+
+// SetResponse records the call and calls SetResponseFunc when set.
+func (m *MockContext) SetResponse(r *echo.Response) {
+	m.record("SetResponse", r)
+	if m.SetResponseFunc != nil {
+		m.SetResponseFunc(r)
+	}
+}
+
+// SignedCookie records the call and calls SignedCookieFunc when set.
+func (m *MockContext) SignedCookie(name string, value string, opts ...echo.CookieOption) (r0 error) {
+	m.record("SignedCookie", name, value, opts)
+	if m.SignedCookieFunc != nil {
+		return m.SignedCookieFunc(name, value, opts...)
+	}
+	return
+}
+
+// Stream records the call and calls StreamFunc when set.
+func (m *MockContext) Stream(code int, contentType string, r io.Reader) (r0 error) {
+	m.record("Stream", code, contentType, r)
+	if m.StreamFunc != nil {
+		return m.StreamFunc(code, contentType, r)
+	}
+	return
+}
+
+// String records the call and calls StringFunc when set.
+func (m *MockContext) String(code int, s string) (r0 error) {
+	m.record("String", code, s)
+	if m.StringFunc != nil {
+		return m.StringFunc(code, s)
+	}
+	return
+}
+
+// SuppressedErrors records the call and calls SuppressedErrorsFunc when set.
+func (m *MockContext) SuppressedErrors() (r0 []error) {
+	m.record("SuppressedErrors")
+	if m.SuppressedErrorsFunc != nil {
+		return m.SuppressedErrorsFunc()
+	}
+	return
+}
+
+// Validate records the call and calls ValidateFunc when set.
+func (m *MockContext) Validate(i interface{}) (r0 error) {
+	m.record("Validate", i)
+	if m.ValidateFunc != nil {
+		return m.ValidateFunc(i)
+	}
+	return
+}
+
+// XML records the call and calls XMLFunc when set.
+func (m *MockContext) XML(code int, i interface{}) (r0 error) {
+	m.record("XML", code, i)
+	if m.XMLFunc != nil {
+		return m.XMLFunc(code, i)
+	}
+	return
+}
+
+// XMLBlob records the call and calls XMLBlobFunc when set.
+func (m *MockContext) XMLBlob(code int, b []byte) (r0 error) {
+	m.record("XMLBlob", code, b)
+	if m.XMLBlobFunc != nil {
+		return m.XMLBlobFunc(code, b)
+	}
+	return
+}
+
+// XMLPretty records the call and calls XMLPrettyFunc when set.
+func (m *MockContext) XMLPretty(code int, i interface{}, indent string) (r0 error) {
+	m.record("XMLPretty", code, i, indent)
+	if m.XMLPrettyFunc != nil {
+		return m.XMLPrettyFunc(code, i, indent)
+	}
+	return
+}
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echomock
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingT struct {
+	errors []string
+}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func getUser(c echo.Context) error {
+	if c.Param("id") != "42" {
+		return echo.ErrNotFound
+	}
+	c.Set("user", 42)
+	return c.JSON(http.StatusOK, user{ID: 42, Name: "Jon"})
+}
+
+func TestMockContext(t *testing.T) {
+	c := &MockContext{
+		ParamFunc: func(name string) string {
+			return "42"
+		},
+	}
+
+	err := getUser(c)
+
+	assert.NoError(t, err)
+	assert.True(t, c.AssertJSON(t, http.StatusOK, map[string]interface{}{"name": "Jon", "id": 42}))
+	assert.True(t, c.AssertJSON(t, http.StatusOK, `{"id":42,"name":"Jon"}`))
+	assert.True(t, c.AssertCalled(t, "Param", "id"))
+	assert.True(t, c.AssertCalled(t, "Set", "user", Anything))
+	assert.True(t, c.AssertCallCount(t, "Param", 1))
+	assert.True(t, c.AssertNotCalled(t, "Redirect"))
+	assert.Equal(t, []Call{
+		{Method: "Param", Args: []interface{}{"id"}},
+		{Method: "Set", Args: []interface{}{"user", 42}},
+		{Method: "JSON", Args: []interface{}{http.StatusOK, user{ID: 42, Name: "Jon"}}},
+	}, c.Calls())
+
+	c.ClearCalls()
+	assert.Empty(t, c.Calls())
+}
+
+func TestMockContext_zeroValuesWithoutStubs(t *testing.T) {
+	c := &MockContext{}
+
+	assert.Equal(t, "", c.Param("id"))
+	assert.NoError(t, c.JSON(http.StatusOK, nil))
+	addr, err := c.RealIPAddr()
+	assert.False(t, addr.IsValid())
+	assert.NoError(t, err)
+	c.SetParamNames("a", "b")
+
+	assert.True(t, c.AssertCalled(t, "SetParamNames", []string{"a", "b"}))
+	assert.Equal(t, 1, c.CallCount("JSON"))
+}
+
+func TestMockContext_middleware(t *testing.T) {
+	errBoom := errors.New("boom")
+	c := &MockContext{}
+	mw := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+			return nil
+		}
+	}
+
+	err := mw(func(c echo.Context) error { return errBoom })(c)
+
+	assert.NoError(t, err)
+	assert.True(t, c.AssertCalled(t, "Error", errBoom))
+}
+
+func TestMockContext_failedAssertions(t *testing.T) {
+	c := &MockContext{}
+	_ = c.JSON(http.StatusCreated, map[string]int{"id": 1})
+	_ = c.Redirect(http.StatusFound, "/login")
+
+	rt := &recordingT{}
+	assert.False(t, c.AssertJSON(rt, http.StatusOK, map[string]int{"id": 1}))
+	assert.False(t, c.AssertCalled(rt, "Redirect", http.StatusFound, "/home"))
+	assert.False(t, c.AssertCalled(rt, "NoContent"))
+	assert.False(t, c.AssertNotCalled(rt, "Redirect"))
+	assert.False(t, c.AssertCallCount(rt, "JSON", 2))
+
+	assert.Equal(t, []string{
+		"echomock: expected JSON response with status 200 and body {\"id\":1}, but got:\n\tJSON(201, map[string]int{\"id\":1})\n",
+		"echomock: expected Redirect to be called with [302 /home], but it was called with:\n\tRedirect(302, \"/login\")\n",
+		"echomock: expected NoContent to be called, but it was not called",
+		"echomock: expected Redirect not to be called, but it was called with:\n\tRedirect(302, \"/login\")\n",
+		"echomock: expected JSON to be called 2 times, but it was called 1 times",
+	}, rt.errors)
+}
@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+// Package echotest provides helpers for testing Echo handlers.
+package echotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// UpdateGoldenEnv is the environment variable that makes AssertJSONGolden write response bodies to golden files
+// instead of comparing them. Example: `ECHO_UPDATE_GOLDEN=1 go test ./...`
+const UpdateGoldenEnv = "ECHO_UPDATE_GOLDEN"
+
+// Redacted is the value that replaces redacted fields in normalized JSON.
+const Redacted = "[REDACTED]"
+
+// GoldenOption configures AssertJSONGolden.
+type GoldenOption func(*goldenConfig)
+
+type goldenConfig struct {
+	fields []*regexp.Regexp
+	values []*regexp.Regexp
+}
+
+// RedactFields redacts values of object fields whose name matches any of the regular expressions (ala `^id$` or
+// `_at$`). Panics when pattern is not valid regular expression.
+func RedactFields(patterns ...string) GoldenOption {
+	return func(c *goldenConfig) {
+		for _, p := range patterns {
+			c.fields = append(c.fields, regexp.MustCompile(p))
+		}
+	}
+}
+
+// RedactValues redacts string values that match any of the regular expressions (ala RFC3339 timestamps or UUIDs).
+// Panics when pattern is not valid regular expression.
+func RedactValues(patterns ...string) GoldenOption {
+	return func(c *goldenConfig) {
+		for _, p := range patterns {
+			c.values = append(c.values, regexp.MustCompile(p))
+		}
+	}
+}
+
+// AssertJSONGolden asserts that JSON body of the recorded response equals contents of the golden file. Both are
+// normalized before comparison: whitespace is removed, object keys are sorted and redacted fields are replaced with
+// Redacted. Golden file is (re)written with normalized body when UpdateGoldenEnv environment variable is set to true.
+//
+// Example:
+//
+//	rec := httptest.NewRecorder()
+//	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+//	echotest.AssertJSONGolden(t, rec, "testdata/user.golden.json", echotest.RedactFields("^id$", "_at$"))
+func AssertJSONGolden(t testing.TB, rec *httptest.ResponseRecorder, goldenPath string, options ...GoldenOption) bool {
+	t.Helper()
+
+	config := goldenConfig{}
+	for _, o := range options {
+		o(&config)
+	}
+
+	actual, err := config.normalize(rec.Body.Bytes())
+	if err != nil {
+		t.Errorf("echotest: response body is not valid JSON: %v", err)
+		return false
+	}
+
+	if update, _ := strconv.ParseBool(os.Getenv(UpdateGoldenEnv)); update {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0o755); err != nil {
+			t.Errorf("echotest: failed to create golden file directory: %v", err)
+			return false
+		}
+		if err := os.WriteFile(goldenPath, actual, 0o644); err != nil {
+			t.Errorf("echotest: failed to write golden file: %v", err)
+			return false
+		}
+		return true
+	}
+
+	golden, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Errorf("echotest: failed to read golden file (run tests with %s=1 to create it): %v", UpdateGoldenEnv, err)
+		return false
+	}
+	expected, err := config.normalize(golden)
+	if err != nil {
+		t.Errorf("echotest: golden file %s is not valid JSON: %v", goldenPath, err)
+		return false
+	}
+	return assert.Equal(t, string(expected), string(actual), "response body does not match golden file %s", goldenPath)
+}
+
+func (c goldenConfig) normalize(body []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after top-level value at offset %d", dec.InputOffset())
+	}
+
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(c.redact(v)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c goldenConfig) redact(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, fv := range t {
+			if matchAny(c.fields, k) {
+				t[k] = Redacted
+				continue
+			}
+			t[k] = c.redact(fv)
+		}
+	case []interface{}:
+		for i, iv := range t {
+			t[i] = c.redact(iv)
+		}
+	case string:
+		if matchAny(c.values, t) {
+			return Redacted
+		}
+	}
+	return v
+}
+
+func matchAny(patterns []*regexp.Regexp, s string) bool {
+	for _, p := range patterns {
+		if p.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
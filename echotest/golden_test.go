@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echotest
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func newRecorder(body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	rec.WriteString(body)
+	return rec
+}
+
+func TestAssertJSONGolden(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		givenBody    string
+		whenOptions  []GoldenOption
+		expectOK     bool
+		expectErrors int
+	}{
+		{
+			name:        "ok, keys in different order and redacted fields",
+			givenBody:   `{"score":1.50,"name":"Jon Snow","roles":["admin","user"],"id":42,"created_at":"2024-01-02T15:04:05Z"}` + "\n",
+			whenOptions: []GoldenOption{RedactFields("^id$"), RedactValues(`^\d{4}-\d{2}-\d{2}T`)},
+			expectOK:    true,
+		},
+		{
+			name:         "nok, fields are not redacted",
+			givenBody:    `{"score":1.50,"name":"Jon Snow","roles":["admin","user"],"id":42,"created_at":"2024-01-02T15:04:05Z"}`,
+			expectErrors: 1,
+		},
+		{
+			name:         "nok, number formatting differs",
+			givenBody:    `{"score":1.5,"name":"Jon Snow","roles":["admin","user"],"id":1,"created_at":"x"}`,
+			whenOptions:  []GoldenOption{RedactFields("^id$", "_at$")},
+			expectErrors: 1,
+		},
+		{
+			name:         "nok, body is not JSON",
+			givenBody:    `not json`,
+			expectErrors: 1,
+		},
+		{
+			name:         "nok, trailing data after JSON value",
+			givenBody:    `{} {}`,
+			expectErrors: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tb := &recordingTB{TB: t}
+
+			ok := AssertJSONGolden(tb, newRecorder(tc.givenBody), "testdata/user.golden.json", tc.whenOptions...)
+
+			assert.Equal(t, tc.expectOK, ok)
+			assert.Len(t, tb.errors, tc.expectErrors)
+		})
+	}
+}
+
+func TestAssertJSONGolden_update(t *testing.T) {
+	t.Setenv(UpdateGoldenEnv, "1")
+	path := filepath.Join(t.TempDir(), "nested", "out.golden.json")
+
+	ok := AssertJSONGolden(t, newRecorder(`{"b":1,"a":{"url":"/x?a=1&b=2","id":"abc"}}`), path, RedactFields("^id$"))
+	assert.True(t, ok)
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"a\": {\n    \"id\": \"[REDACTED]\",\n    \"url\": \"/x?a=1&b=2\"\n  },\n  \"b\": 1\n}\n", string(b))
+
+	t.Setenv(UpdateGoldenEnv, "")
+	assert.True(t, AssertJSONGolden(t, newRecorder(`{"a":{"id":"other","url":"/x?a=1&b=2"},"b":1}`), path, RedactFields("^id$")))
+}
+
+func TestAssertJSONGolden_missingGoldenFile(t *testing.T) {
+	tb := &recordingTB{TB: t}
+
+	ok := AssertJSONGolden(tb, newRecorder(`{}`), filepath.Join(t.TempDir(), "missing.json"))
+
+	assert.False(t, ok)
+	if assert.Len(t, tb.errors, 1) {
+		assert.Contains(t, tb.errors[0], UpdateGoldenEnv)
+	}
+}
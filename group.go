@@ -16,6 +16,8 @@ type Group struct {
 	prefix     string
 	echo       *Echo
 	middleware []MiddlewareFunc
+	// routeOptions are route options of routes registered in the group. See `Group#WithRouteOptions`.
+	routeOptions []RouteOption
 }
 
 // Use implements `Echo#Use()` for sub-routes within the Group.
@@ -32,6 +34,37 @@ func (g *Group) Use(middleware ...MiddlewareFunc) {
 	g.RouteNotFound("/*", NotFoundHandler)
 }
 
+// ResponseHeaders adds headers to responses of all routes registered in the group (and its subgroups) after this call.
+// Handler set values win. Headers are merged down the group chain and the inner-most value wins.
+// See `RouteResponseHeaders`.
+//
+// Example: `api.ResponseHeaders(map[string]string{"X-API-Version": "2"})`
+func (g *Group) ResponseHeaders(headers map[string]string) {
+	g.routeOptions = append(g.routeOptions, RouteResponseHeaders(headers))
+	// not found responses of the group have the headers too
+	g.RouteNotFound("", NotFoundHandler)
+	g.RouteNotFound("/*", NotFoundHandler)
+}
+
+// WithRouteOptions returns group with the same prefix and middlewares that registers routes with route options of this
+// group and given options. Options given later win or are combined with earlier ones as described by each option.
+//
+// Example:
+//
+//	api := e.Group("/api").WithRouteOptions(echo.RouteTimeout(time.Minute))
+//	api.WithRouteOptions(echo.RouteBodyLimit("100M")).POST("/upload", handler)
+func (g *Group) WithRouteOptions(options ...RouteOption) *Group {
+	routeOptions := make([]RouteOption, 0, len(g.routeOptions)+len(options))
+	routeOptions = append(routeOptions, g.routeOptions...)
+	return &Group{
+		host:         g.host,
+		prefix:       g.prefix,
+		echo:         g.echo,
+		middleware:   append([]MiddlewareFunc(nil), g.middleware...),
+		routeOptions: append(routeOptions, options...),
+	}
+}
+
 // CONNECT implements `Echo#CONNECT()` for sub-routes within the Group.
 func (g *Group) CONNECT(path string, h HandlerFunc, m ...MiddlewareFunc) *Route {
 	return g.Add(http.MethodConnect, path, h, m...)
@@ -100,14 +133,15 @@ func (g *Group) Group(prefix string, middleware ...MiddlewareFunc) (sg *Group) {
 	m := make([]MiddlewareFunc, 0, len(g.middleware)+len(middleware))
 	m = append(m, g.middleware...)
 	m = append(m, middleware...)
-	sg = g.echo.Group(g.prefix+prefix, m...)
-	sg.host = g.host
+	sg = &Group{host: g.host, prefix: g.prefix + prefix, echo: g.echo, routeOptions: append([]RouteOption(nil), g.routeOptions...)}
+	g.echo.lintState.recordGroup(sg.prefix)
+	sg.Use(m...)
 	return
 }
 
 // File implements `Echo#File()` for sub-routes within the Group.
-func (g *Group) File(path, file string) {
-	g.file(path, file, g.GET)
+func (g *Group) File(path, file string, m ...MiddlewareFunc) *Route {
+	return g.file(path, file, g.GET, m...)
 }
 
 // RouteNotFound implements `Echo#RouteNotFound()` for sub-routes within the Group.
@@ -119,11 +153,5 @@ func (g *Group) RouteNotFound(path string, h HandlerFunc, m ...MiddlewareFunc) *
 
 // Add implements `Echo#Add()` for sub-routes within the Group.
 func (g *Group) Add(method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) *Route {
-	// Combine into a new slice to avoid accidentally passing the same slice for
-	// multiple routes, which would lead to later add() calls overwriting the
-	// middleware from earlier calls.
-	m := make([]MiddlewareFunc, 0, len(g.middleware)+len(middleware))
-	m = append(m, g.middleware...)
-	m = append(m, middleware...)
-	return g.echo.add(g.host, method, g.prefix+path, handler, m...)
+	return g.echo.addWithGroup(g.host, method, g.prefix+path, handler, g.routeOptions, g.middleware, middleware)
 }
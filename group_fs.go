@@ -9,9 +9,11 @@ import (
 )
 
 // Static implements `Echo#Static()` for sub-routes within the Group.
-func (g *Group) Static(pathPrefix, fsRoot string) {
+func (g *Group) Static(pathPrefix, fsRoot string) *Route {
 	subFs := MustSubFS(g.echo.Filesystem, fsRoot)
-	g.StaticFS(pathPrefix, subFs)
+	route := g.StaticFS(pathPrefix, subFs)
+	g.echo.lintState.recordStatic(route, subFs)
+	return route
 }
 
 // StaticFS implements `Echo#StaticFS()` for sub-routes within the Group.
@@ -19,8 +21,8 @@ func (g *Group) Static(pathPrefix, fsRoot string) {
 // When dealing with `embed.FS` use `fs := echo.MustSubFS(fs, "rootDirectory") to create sub fs which uses necessary
 // prefix for directory path. This is necessary as `//go:embed assets/images` embeds files with paths
 // including `assets/images` as their prefix.
-func (g *Group) StaticFS(pathPrefix string, filesystem fs.FS) {
-	g.Add(
+func (g *Group) StaticFS(pathPrefix string, filesystem fs.FS) *Route {
+	return g.Add(
 		http.MethodGet,
 		pathPrefix+"*",
 		StaticDirectoryHandler(filesystem, false),
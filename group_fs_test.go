@@ -101,3 +101,94 @@ func TestGroup_StaticPanic(t *testing.T) {
 		})
 	}
 }
+
+func TestGroup_StaticFS(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		givenURL         string
+		expectCode       int
+		expectStartsWith []byte
+	}{
+		{
+			name:             "ok",
+			givenURL:         "/assets/images/walle.png",
+			expectCode:       http.StatusOK,
+			expectStartsWith: []byte{0x89, 0x50, 0x4e},
+		},
+		{
+			name:             "nok, traversal with ..",
+			givenURL:         "/assets/../favicon.ico",
+			expectCode:       http.StatusNotFound,
+			expectStartsWith: []byte(`{"message":"Not Found"}`),
+		},
+		{
+			name:             "nok, traversal with .. after group prefix",
+			givenURL:         "/assets/images/../../echo.go",
+			expectCode:       http.StatusNotFound,
+			expectStartsWith: []byte(`{"message":"Not Found"}`),
+		},
+		{
+			name:             "nok, traversal with encoded ..",
+			givenURL:         "/assets/%2e%2e/echo.go",
+			expectCode:       http.StatusNotFound,
+			expectStartsWith: []byte(`{"message":"Not Found"}`),
+		},
+		{
+			name:             "nok, traversal with encoded .. and slashes",
+			givenURL:         "/assets/%2e%2e%2f%2e%2e%2fecho.go",
+			expectCode:       http.StatusNotFound,
+			expectStartsWith: []byte(`{"message":"Not Found"}`),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			g := e.Group("/assets")
+			g.StaticFS("/", os.DirFS("_fixture"))
+
+			req := httptest.NewRequest(http.MethodGet, tc.givenURL, nil)
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+
+			body := rec.Body.Bytes()
+			if len(body) > len(tc.expectStartsWith) {
+				body = body[:len(tc.expectStartsWith)]
+			}
+			assert.Equal(t, tc.expectStartsWith, body)
+		})
+	}
+}
+
+func TestGroup_StaticRoutesAreNamedAndUseGroupMiddleware(t *testing.T) {
+	e := New()
+	e.Filesystem = os.DirFS("_fixture")
+	g := e.Group("/assets", func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			c.Response().Header().Set("X-Group", "assets")
+			return next(c)
+		}
+	})
+
+	g.Static("/static/", "images").Name = "static"
+	g.StaticFS("/fs/", os.DirFS("_fixture/images")).Name = "static-fs"
+	g.File("/walle", "images/walle.png").Name = "file"
+	g.FileFS("/walle-fs", "walle.png", os.DirFS("_fixture/images")).Name = "file-fs"
+
+	assert.Equal(t, "/assets/static/walle.png", e.Reverse("static", "walle.png"))
+	assert.Equal(t, "/assets/fs/walle.png", e.Reverse("static-fs", "walle.png"))
+	assert.Equal(t, "/assets/walle", e.Reverse("file"))
+	assert.Equal(t, "/assets/walle-fs", e.Reverse("file-fs"))
+
+	for _, target := range []string{"/assets/static/walle.png", "/assets/fs/walle.png", "/assets/walle", "/assets/walle-fs"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code, target)
+		assert.Equal(t, "assets", rec.Header().Get("X-Group"), target)
+	}
+}
@@ -257,3 +257,29 @@ func TestGroup_RouteNotFoundWithMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestGroup_automaticOptionsUsesGroupMiddlewares(t *testing.T) {
+	e := New()
+	g := e.Group("/group", func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			c.Response().Header().Set("X-Group", "true")
+			return next(c)
+		}
+	})
+	g.GET("/users/:id", handlerFunc)
+	g.PUT("/users/:id", handlerFunc)
+
+	req := httptest.NewRequest(http.MethodOptions, "/group/users/1", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "true", rec.Header().Get("X-Group"))
+	assert.Equal(t, "OPTIONS, GET, PUT", rec.Header().Get(HeaderAllow))
+
+	// other methods still hit catch-all route of the group
+	req = httptest.NewRequest(http.MethodPost, "/group/users/1", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
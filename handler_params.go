@@ -0,0 +1,213 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// Handler1 returns handler that calls fn with the first path param of the matched route converted to type A.
+// Path params are passed in the order they are declared in the route path (wildcard `*` included). Param values are
+// converted with the same rules as `BindPathParams` uses for struct fields (numbers, bool, string, pointers to these,
+// `BindUnmarshaler` and `encoding.TextUnmarshaler`). Values that can not be converted result "400 - Bad Request" error
+// with `*BindFieldError` as internal error (message follows `DefaultBinder.DetailedFieldErrors` of `Echo#Binder`).
+// Registering returned handler for route with fewer path params than fn expects panics.
+//
+// Converter for the argument type is resolved once when Handler1 is called, unsupported argument type panics.
+//
+// Example:
+//
+//	e.GET("/users/:id", echo.Handler1(func(c echo.Context, id int64) error {
+//		return c.JSON(http.StatusOK, store.User(id))
+//	}))
+func Handler1[A any](fn func(c Context, a A) error) HandlerFunc {
+	convA := newParamConverter[A](1)
+	return withHandlerParamCount(1, func(c Context) error {
+		values, err := handlerParamValues(c, 1)
+		if err != nil {
+			return err
+		}
+		a, err := convA(c, values)
+		if err != nil {
+			return err
+		}
+		return fn(c, a)
+	})
+}
+
+// Handler2 returns handler that calls fn with the first two path params of the matched route. See Handler1.
+//
+// Example:
+//
+//	e.GET("/users/:id/posts/:slug", echo.Handler2(func(c echo.Context, id int64, slug string) error {
+//		return c.JSON(http.StatusOK, store.Post(id, slug))
+//	}))
+func Handler2[A, B any](fn func(c Context, a A, b B) error) HandlerFunc {
+	convA := newParamConverter[A](1)
+	convB := newParamConverter[B](2)
+	return withHandlerParamCount(2, func(c Context) error {
+		values, err := handlerParamValues(c, 2)
+		if err != nil {
+			return err
+		}
+		a, err := convA(c, values)
+		if err != nil {
+			return err
+		}
+		b, err := convB(c, values)
+		if err != nil {
+			return err
+		}
+		return fn(c, a, b)
+	})
+}
+
+// Handler3 returns handler that calls fn with the first three path params of the matched route. See Handler1.
+func Handler3[A, B, C any](fn func(c Context, a A, b B, cc C) error) HandlerFunc {
+	convA := newParamConverter[A](1)
+	convB := newParamConverter[B](2)
+	convC := newParamConverter[C](3)
+	return withHandlerParamCount(3, func(c Context) error {
+		values, err := handlerParamValues(c, 3)
+		if err != nil {
+			return err
+		}
+		a, err := convA(c, values)
+		if err != nil {
+			return err
+		}
+		b, err := convB(c, values)
+		if err != nil {
+			return err
+		}
+		cc, err := convC(c, values)
+		if err != nil {
+			return err
+		}
+		return fn(c, a, b, cc)
+	})
+}
+
+// Handler4 returns handler that calls fn with the first four path params of the matched route. See Handler1.
+func Handler4[A, B, C, D any](fn func(c Context, a A, b B, cc C, d D) error) HandlerFunc {
+	convA := newParamConverter[A](1)
+	convB := newParamConverter[B](2)
+	convC := newParamConverter[C](3)
+	convD := newParamConverter[D](4)
+	return withHandlerParamCount(4, func(c Context) error {
+		values, err := handlerParamValues(c, 4)
+		if err != nil {
+			return err
+		}
+		a, err := convA(c, values)
+		if err != nil {
+			return err
+		}
+		b, err := convB(c, values)
+		if err != nil {
+			return err
+		}
+		cc, err := convC(c, values)
+		if err != nil {
+			return err
+		}
+		d, err := convD(c, values)
+		if err != nil {
+			return err
+		}
+		return fn(c, a, b, cc, d)
+	})
+}
+
+// paramConverter converts path param value at its position to handler argument.
+type paramConverter[T any] func(c Context, values []string) (T, error)
+
+// newParamConverter returns converter for path param at position (1-based) to type T. Panics when T is not supported.
+func newParamConverter[T any](position int) paramConverter[T] {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if !isParamArgType(typ) {
+		panic(fmt.Errorf("echo: handler argument %d has unsupported type %v", position, typ))
+	}
+	index := position - 1
+	kind := typ.Kind()
+	isPtr := kind == reflect.Ptr
+	return func(c Context, values []string) (T, error) {
+		var value T
+		arg := reflect.ValueOf(&value).Elem()
+		if isPtr {
+			arg.Set(reflect.New(typ.Elem()))
+		}
+		if err := setWithProperType(kind, values[index], arg); err != nil {
+			return value, newHandlerParamError(c, index, values[index], err)
+		}
+		return value, nil
+	}
+}
+
+// handlerParamCounts holds number of path params expected by handlers created with Handler1..4. Keys are code
+// pointers of returned handlers. All handlers created by the same HandlerN (and type shape) share the code so number
+// of entries is bounded.
+var handlerParamCounts sync.Map
+
+func withHandlerParamCount(count int, h HandlerFunc) HandlerFunc {
+	handlerParamCounts.Store(reflect.ValueOf(h).Pointer(), count)
+	return h
+}
+
+// checkHandlerParams returns error when handler created with Handler1..4 expects more path params than path declares.
+func checkHandlerParams(h HandlerFunc, path string) error {
+	if h == nil {
+		return nil
+	}
+	expected, ok := handlerParamCounts.Load(reflect.ValueOf(h).Pointer())
+	if !ok {
+		return nil
+	}
+	if count := len(routeParamNames(path)); count < expected.(int) {
+		return fmt.Errorf("handler expects %d path params but path has %d", expected, count)
+	}
+	return nil
+}
+
+// handlerParamValues returns path param values of the matched route. Returns error when the route has fewer params
+// than the handler expects (ala route added directly to `Router`).
+func handlerParamValues(c Context, expected int) ([]string, error) {
+	values := c.ParamValues()
+	if len(values) < expected {
+		return nil, NewHTTPError(http.StatusInternalServerError).SetInternal(
+			fmt.Errorf("echo: handler expects %d path params but route %s has %d", expected, c.Path(), len(values)),
+		)
+	}
+	return values, nil
+}
+
+func newHandlerParamError(c Context, index int, value string, err error) error {
+	binder, ok := c.Echo().Binder.(*DefaultBinder)
+	if !ok {
+		binder = &DefaultBinder{}
+	}
+	httpErr := binder.bindDataError(newBindFieldError("param", c.ParamNames()[index], value, err))
+	if binder.OnBindError != nil {
+		binder.reportBindError(c, httpErr, "path")
+	}
+	return httpErr
+}
+
+func isParamArgType(t reflect.Type) bool {
+	if reflect.PtrTo(t).Implements(bindUnmarshalerType) || reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return t.Elem().Kind() != reflect.Ptr && isParamArgType(t.Elem())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Bool, reflect.Float32, reflect.Float64, reflect.String:
+		return true
+	}
+	return false
+}
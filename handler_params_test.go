@@ -0,0 +1,193 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler4(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenURL     string
+		expectCode  int
+		expectBody  string
+		expectError string
+	}{
+		{
+			name:       "ok",
+			whenURL:    "/users/42/posts/hello-world/2024-05-01/docs/a.txt",
+			expectCode: http.StatusOK,
+			expectBody: "id=42 slug=hello-world draft=false date=2024-05-01 rest=docs/a.txt",
+		},
+		{
+			name:        "nok, param not convertible to argument type",
+			whenURL:     "/users/abc/posts/hello-world/2024-05-01/docs",
+			expectCode:  http.StatusBadRequest,
+			expectError: `failed to bind path param "id" (value "abc"): strconv.ParseInt: parsing "abc": invalid syntax`,
+		},
+		{
+			name:        "nok, TextUnmarshaler argument fails",
+			whenURL:     "/users/1/posts/hello-world/yesterday/docs",
+			expectCode:  http.StatusBadRequest,
+			expectError: `failed to bind path param "date" (value "yesterday"): parsing time "yesterday" as "2006-01-02": cannot parse "yesterday" as "2006"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.Binder = &DefaultBinder{DetailedFieldErrors: true}
+			var handlerErr error
+			e.HTTPErrorHandler = func(err error, c Context) {
+				handlerErr = err
+				e.DefaultHTTPErrorHandler(err, c)
+			}
+			e.GET("/users/:id/posts/:slug/:date/*", Handler4(func(c Context, id int64, slug *string, date paramDate, rest string) error {
+				return c.String(http.StatusOK, fmt.Sprintf("id=%d slug=%s draft=false date=%s rest=%s", id, *slug, time.Time(date).Format("2006-01-02"), rest))
+			}))
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.whenURL, nil))
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			if tc.expectError != "" {
+				var fieldErr *BindFieldError
+				assert.ErrorAs(t, handlerErr, &fieldErr)
+				assert.Equal(t, tc.expectError, handlerErr.(*HTTPError).Message)
+				return
+			}
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+		})
+	}
+}
+
+type paramDate time.Time
+
+func (d *paramDate) UnmarshalText(text []byte) error {
+	t, err := time.Parse("2006-01-02", string(text))
+	if err != nil {
+		return err
+	}
+	*d = paramDate(t)
+	return nil
+}
+
+func TestHandler1_returnsHandlerError(t *testing.T) {
+	e := New()
+	e.GET("/:id", Handler1(func(c Context, id int) error {
+		return ErrNotFound
+	}))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/1", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandler2(t *testing.T) {
+	e := New()
+	e.GET("/users/:id/active/:active", Handler2(func(c Context, id uint8, active bool) error {
+		return c.String(http.StatusOK, fmt.Sprintf("id=%d active=%t", id, active))
+	}))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/7/active/true", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "id=7 active=true", rec.Body.String())
+}
+
+func TestHandler3_unsupportedArgumentPanics(t *testing.T) {
+	assert.PanicsWithError(t, "echo: handler argument 2 has unsupported type []int", func() {
+		Handler3(func(c Context, id int, ids []int, name string) error { return nil })
+	})
+	assert.PanicsWithError(t, "echo: handler argument 1 has unsupported type **int", func() {
+		Handler1(func(c Context, id **int) error { return nil })
+	})
+}
+
+func TestHandler2_routeWithFewerParams(t *testing.T) {
+	e := New()
+	h := Handler2(func(c Context, id int64, slug string) error { return nil })
+
+	assert.PanicsWithError(t, "echo: invalid route GET /users/:id: handler expects 2 path params but path has 1", func() {
+		e.GET("/users/:id", h)
+	})
+	assert.PanicsWithError(t, "echo: invalid route GET /api/users: handler expects 2 path params but path has 0", func() {
+		e.Group("/api").GET("/users", h)
+	})
+	assert.PanicsWithError(t, "echo: invalid pattern /users/:id: handler expects 2 path params but path has 1", func() {
+		WrapToHTTPHandler(e, h, WrapToHTTPHandlerOptions{Pattern: "/users/:id"})
+	})
+	assert.NotPanics(t, func() {
+		e.Group("/users/:id").GET("/posts/:slug", h)
+		e.GET("/files/:id/*", h)
+	})
+}
+
+func TestHandler2_routerWithFewerParams(t *testing.T) {
+	e := New()
+	var handlerErr error
+	e.HTTPErrorHandler = func(err error, c Context) {
+		handlerErr = err
+		e.DefaultHTTPErrorHandler(err, c)
+	}
+	e.Router().Add(http.MethodGet, "/users/:id", Handler2(func(c Context, id int64, slug string) error { return nil }))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.EqualError(t, handlerErr.(*HTTPError).Internal, "echo: handler expects 2 path params but route /users/:id has 1")
+}
+
+func BenchmarkHandler2(b *testing.B) {
+	handwritten := func(c Context) error {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			return NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if id == 0 || c.Param("slug") == "" {
+			return errors.New("unexpected")
+		}
+		return nil
+	}
+	injected := Handler2(func(c Context, id int64, slug string) error {
+		if id == 0 || slug == "" {
+			return errors.New("unexpected")
+		}
+		return nil
+	})
+
+	for _, bc := range []struct {
+		name    string
+		handler HandlerFunc
+	}{
+		{name: "handwritten", handler: handwritten},
+		{name: "Handler2", handler: injected},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			e := New()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/users/42/posts/hello", nil), httptest.NewRecorder())
+			c.SetParamNames("id", "slug")
+			c.SetParamValues("42", "hello")
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := bc.handler(c); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -4,8 +4,11 @@
 package echo
 
 import (
+	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"net/netip"
 	"strings"
 )
 
@@ -133,8 +136,11 @@ Private IPv6 address ranges:
 
 */
 
+// ErrInvalidIPAddress is returned when remote address or IP header value can not be parsed as IP address.
+var ErrInvalidIPAddress = errors.New("echo: invalid IP address")
+
 type ipChecker struct {
-	trustExtraRanges []*net.IPNet
+	trustExtraRanges []netip.Prefix
 	trustLoopback    bool
 	trustLinkLocal   bool
 	trustPrivateNet  bool
@@ -164,13 +170,55 @@ func TrustPrivateNet(v bool) TrustOption {
 	}
 }
 
-// TrustIPRange add trustable IP ranges using CIDR notation.
+// TrustIPRange add trustable IP ranges using CIDR notation. Ranges with non-canonical masks are ignored.
 func TrustIPRange(ipRange *net.IPNet) TrustOption {
 	return func(c *ipChecker) {
-		c.trustExtraRanges = append(c.trustExtraRanges, ipRange)
+		if prefix, ok := ipNetToPrefix(ipRange); ok {
+			c.trustExtraRanges = append(c.trustExtraRanges, prefix)
+		}
 	}
 }
 
+// TrustIPPrefix add trustable IP range.
+func TrustIPPrefix(prefix netip.Prefix) TrustOption {
+	return func(c *ipChecker) {
+		if prefix.IsValid() {
+			c.trustExtraRanges = append(c.trustExtraRanges, netip.PrefixFrom(prefix.Addr().Unmap(), prefixBits(prefix)).Masked())
+		}
+	}
+}
+
+// prefixBits returns number of prefix bits of the unmapped prefix address. IPv4-mapped IPv6 prefix (ala
+// `::ffff:10.0.0.0/104`) is turned into IPv4 prefix as addresses are unmapped before they are checked.
+func prefixBits(prefix netip.Prefix) int {
+	if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+		return prefix.Bits() - 96
+	}
+	return prefix.Bits()
+}
+
+func ipNetToPrefix(ipNet *net.IPNet) (netip.Prefix, bool) {
+	if ipNet == nil {
+		return netip.Prefix{}, false
+	}
+	addr, ok := netip.AddrFromSlice(ipNet.IP)
+	if !ok {
+		return netip.Prefix{}, false
+	}
+	ones, bits := ipNet.Mask.Size()
+	if bits == 0 {
+		return netip.Prefix{}, false
+	}
+	if bits == 32 {
+		addr = addr.Unmap()
+	}
+	prefix := netip.PrefixFrom(addr, ones)
+	if !prefix.IsValid() {
+		return netip.Prefix{}, false
+	}
+	return netip.PrefixFrom(addr.Unmap(), prefixBits(prefix)).Masked(), true
+}
+
 func newIPChecker(configs []TrustOption) *ipChecker {
 	checker := &ipChecker{trustLoopback: true, trustLinkLocal: true, trustPrivateNet: true}
 	for _, configure := range configs {
@@ -179,34 +227,78 @@ func newIPChecker(configs []TrustOption) *ipChecker {
 	return checker
 }
 
-// Go1.16+ added `ip.IsPrivate()` but until that use this implementation
-func isPrivateIPRange(ip net.IP) bool {
-	if ip4 := ip.To4(); ip4 != nil {
-		return ip4[0] == 10 ||
-			ip4[0] == 172 && ip4[1]&0xf0 == 16 ||
-			ip4[0] == 192 && ip4[1] == 168
+func (c *ipChecker) trust(addr netip.Addr) bool {
+	if !addr.IsValid() {
+		return false
 	}
-	return len(ip) == net.IPv6len && ip[0]&0xfe == 0xfc
-}
-
-func (c *ipChecker) trust(ip net.IP) bool {
-	if c.trustLoopback && ip.IsLoopback() {
+	addr = addr.Unmap().WithZone("")
+	if c.trustLoopback && addr.IsLoopback() {
 		return true
 	}
-	if c.trustLinkLocal && ip.IsLinkLocalUnicast() {
+	if c.trustLinkLocal && addr.IsLinkLocalUnicast() {
 		return true
 	}
-	if c.trustPrivateNet && isPrivateIPRange(ip) {
+	if c.trustPrivateNet && addr.IsPrivate() {
 		return true
 	}
 	for _, trustedRange := range c.trustExtraRanges {
-		if trustedRange.Contains(ip) {
+		if trustedRange.Contains(addr) {
 			return true
 		}
 	}
 	return false
 }
 
+// parseIPAddr parses IP address from remote address or IP header value. Accepted forms are `ip`, `ip:port`,
+// `[ipv6]` and `[ipv6]:port`. IPv6 zones are kept and IPv4-mapped IPv6 addresses are unmapped (`::ffff:1.2.3.4`
+// becomes `1.2.3.4`).
+func parseIPAddr(s string) (netip.Addr, error) {
+	host := strings.TrimSpace(s)
+	if strings.HasPrefix(host, "[") {
+		end := strings.IndexByte(host, ']')
+		if end == -1 || !isValidPortSuffix(host[end+1:]) {
+			return netip.Addr{}, fmt.Errorf("%w: %q", ErrInvalidIPAddress, s)
+		}
+		host = host[1:end]
+	} else if i := strings.IndexByte(host, ':'); i != -1 && i == strings.LastIndexByte(host, ':') {
+		// single colon can not be part of IPv6 address, so this is IPv4 with port
+		if !isValidPortSuffix(host[i:]) {
+			return netip.Addr{}, fmt.Errorf("%w: %q", ErrInvalidIPAddress, s)
+		}
+		host = host[:i]
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("%w: %q", ErrInvalidIPAddress, s)
+	}
+	if addr.Is4In6() {
+		addr = addr.Unmap()
+	}
+	return addr, nil
+}
+
+func isValidPortSuffix(s string) bool {
+	if s == "" {
+		return true
+	}
+	if s[0] != ':' || len(s) == 1 || len(s) > 6 {
+		return false
+	}
+	for _, r := range s[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func formatIPAddr(addr netip.Addr) string {
+	if !addr.IsValid() {
+		return ""
+	}
+	return addr.String()
+}
+
 // IPExtractor is a function to extract IP addr from http.Request.
 // Set appropriate one to Echo#IPExtractor.
 // See https://echo.labstack.com/guide/ip-address for more details.
@@ -219,59 +311,57 @@ func ExtractIPDirect() IPExtractor {
 }
 
 func extractIP(req *http.Request) string {
-	ra, _, _ := net.SplitHostPort(req.RemoteAddr)
-	return ra
+	return formatIPAddr(extractIPAddr(req))
+}
+
+func extractIPAddr(req *http.Request) netip.Addr {
+	addr, _ := parseIPAddr(req.RemoteAddr)
+	return addr
 }
 
 // ExtractIPFromRealIPHeader extracts IP address using x-real-ip header.
 // Use this if you put proxy which uses this header.
+// Header value may contain port and brackets around IPv6 address. Invalid values are ignored.
 func ExtractIPFromRealIPHeader(options ...TrustOption) IPExtractor {
 	checker := newIPChecker(options)
 	return func(req *http.Request) string {
-		directIP := extractIP(req)
+		directIP := extractIPAddr(req)
 		realIP := req.Header.Get(HeaderXRealIP)
-		if realIP == "" {
-			return directIP
-		}
-
-		if checker.trust(net.ParseIP(directIP)) {
-			realIP = strings.TrimPrefix(realIP, "[")
-			realIP = strings.TrimSuffix(realIP, "]")
-			if rIP := net.ParseIP(realIP); rIP != nil {
-				return realIP
+		if realIP != "" && checker.trust(directIP) {
+			if addr, err := parseIPAddr(realIP); err == nil {
+				return addr.String()
 			}
 		}
-
-		return directIP
+		return formatIPAddr(directIP)
 	}
 }
 
 // ExtractIPFromXFFHeader extracts IP address using x-forwarded-for header.
 // Use this if you put proxy which uses this header.
 // This returns nearest untrustable IP. If all IPs are trustable, returns furthest one (i.e.: XFF[0]).
+// Entries may contain port and brackets around IPv6 address.
 func ExtractIPFromXFFHeader(options ...TrustOption) IPExtractor {
 	checker := newIPChecker(options)
 	return func(req *http.Request) string {
-		directIP := extractIP(req)
+		directIP := extractIPAddr(req)
 		xffs := req.Header[HeaderXForwardedFor]
-		if len(xffs) == 0 {
-			return directIP
+		if len(xffs) == 0 || !checker.trust(directIP) {
+			return formatIPAddr(directIP)
 		}
-		ips := append(strings.Split(strings.Join(xffs, ","), ","), directIP)
+		ips := strings.Split(strings.Join(xffs, ","), ",")
+		furthest := directIP
 		for i := len(ips) - 1; i >= 0; i-- {
-			ips[i] = strings.TrimSpace(ips[i])
-			ips[i] = strings.TrimPrefix(ips[i], "[")
-			ips[i] = strings.TrimSuffix(ips[i], "]")
-			ip := net.ParseIP(ips[i])
-			if ip == nil {
+			ip, err := parseIPAddr(ips[i])
+			if err != nil {
 				// Unable to parse IP; cannot trust entire records
-				return directIP
+				return formatIPAddr(directIP)
 			}
 			if !checker.trust(ip) {
 				return ip.String()
 			}
+			furthest = ip
 		}
 		// All of the IPs are trusted; return first element because it is furthest from server (best effort strategy).
-		return strings.TrimSpace(ips[0])
+		return furthest.String()
 	}
 }
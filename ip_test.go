@@ -6,6 +6,7 @@ package echo
 import (
 	"net"
 	"net/http"
+	"net/netip"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -19,6 +20,12 @@ func mustParseCIDR(s string) *net.IPNet {
 	return IPNet
 }
 
+// parseTestIPAddr returns zero Addr for invalid input so trust checks can be tested with malformed addresses.
+func parseTestIPAddr(s string) netip.Addr {
+	addr, _ := netip.ParseAddr(s)
+	return addr
+}
+
 func TestIPChecker_TrustOption(t *testing.T) {
 	var testCases = []struct {
 		name         string
@@ -56,7 +63,7 @@ func TestIPChecker_TrustOption(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			checker := newIPChecker(tc.givenOptions)
 
-			result := checker.trust(net.ParseIP(tc.whenIP))
+			result := checker.trust(parseTestIPAddr(tc.whenIP))
 			assert.Equal(t, tc.expect, result)
 		})
 	}
@@ -160,7 +167,7 @@ func TestTrustIPRange(t *testing.T) {
 				TrustIPRange(cidr),
 			})
 
-			result := checker.trust(net.ParseIP(tc.whenIP))
+			result := checker.trust(parseTestIPAddr(tc.whenIP))
 			assert.Equal(t, tc.expect, result)
 		})
 	}
@@ -269,7 +276,7 @@ func TestTrustPrivateNet(t *testing.T) {
 				TrustPrivateNet(true),
 			})
 
-			result := checker.trust(net.ParseIP(tc.whenIP))
+			result := checker.trust(parseTestIPAddr(tc.whenIP))
 			assert.Equal(t, tc.expect, result)
 		})
 	}
@@ -322,7 +329,7 @@ func TestTrustLinkLocal(t *testing.T) {
 				TrustLinkLocal(true),
 			})
 
-			result := checker.trust(net.ParseIP(tc.whenIP))
+			result := checker.trust(parseTestIPAddr(tc.whenIP))
 			assert.Equal(t, tc.expect, result)
 		})
 	}
@@ -360,7 +367,7 @@ func TestTrustLoopback(t *testing.T) {
 				TrustLoopback(true),
 			})
 
-			result := checker.trust(net.ParseIP(tc.whenIP))
+			result := checker.trust(parseTestIPAddr(tc.whenIP))
 			assert.Equal(t, tc.expect, result)
 		})
 	}
@@ -692,3 +699,174 @@ func TestExtractIPFromXFFHeader(t *testing.T) {
 		})
 	}
 }
+
+func TestParseIPAddr(t *testing.T) {
+	var testCases = []struct {
+		name      string
+		whenValue string
+		expect    string
+		expectErr string
+	}{
+		{name: "ok, IPv4", whenValue: "203.0.113.1", expect: "203.0.113.1"},
+		{name: "ok, IPv4 with port", whenValue: "203.0.113.1:8080", expect: "203.0.113.1"},
+		{name: "ok, IPv4 with spaces", whenValue: " 203.0.113.1 ", expect: "203.0.113.1"},
+		{name: "ok, IPv6", whenValue: "2001:db8::1", expect: "2001:db8::1"},
+		{name: "ok, IPv6 in brackets", whenValue: "[2001:db8::1]", expect: "2001:db8::1"},
+		{name: "ok, IPv6 in brackets with port", whenValue: "[2001:db8::1]:8080", expect: "2001:db8::1"},
+		{name: "ok, zoned IPv6", whenValue: "fe80::1%eth0", expect: "fe80::1%eth0"},
+		{name: "ok, zoned IPv6 with port", whenValue: "[fe80::1%eth0]:8080", expect: "fe80::1%eth0"},
+		{name: "ok, IPv4-mapped IPv6", whenValue: "::ffff:1.2.3.4", expect: "1.2.3.4"},
+		{name: "ok, IPv4-mapped IPv6 with port", whenValue: "[::ffff:1.2.3.4]:8080", expect: "1.2.3.4"},
+		{name: "nok, empty", whenValue: "", expectErr: `echo: invalid IP address: ""`},
+		{name: "nok, hostname", whenValue: "localhost:8080", expectErr: `echo: invalid IP address: "localhost:8080"`},
+		{name: "nok, invalid IPv4", whenValue: "xxx.yyy.zzz.ccc", expectErr: `echo: invalid IP address: "xxx.yyy.zzz.ccc"`},
+		{name: "nok, invalid port", whenValue: "203.0.113.1:http", expectErr: `echo: invalid IP address: "203.0.113.1:http"`},
+		{name: "nok, empty port", whenValue: "203.0.113.1:", expectErr: `echo: invalid IP address: "203.0.113.1:"`},
+		{name: "nok, missing closing bracket", whenValue: "[2001:db8::1:8080", expectErr: `echo: invalid IP address: "[2001:db8::1:8080"`},
+		{name: "nok, garbage after bracket", whenValue: "[2001:db8::1]x", expectErr: `echo: invalid IP address: "[2001:db8::1]x"`},
+		{name: "nok, IPv6 with port without brackets", whenValue: "2001:db8::1:8080:", expectErr: `echo: invalid IP address: "2001:db8::1:8080:"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			addr, err := parseIPAddr(tc.whenValue)
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				assert.ErrorIs(t, err, ErrInvalidIPAddress)
+				assert.False(t, addr.IsValid())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, addr.String())
+		})
+	}
+}
+
+func TestTrustIPPrefix(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenPrefix string
+		whenIP      string
+		expect      bool
+	}{
+		{name: "IPv4 prefix", givenPrefix: "203.0.113.0/24", whenIP: "203.0.113.10", expect: true},
+		{name: "IPv4 prefix, mapped address", givenPrefix: "203.0.113.0/24", whenIP: "::ffff:203.0.113.10", expect: true},
+		{name: "IPv4-mapped prefix, IPv4 address", givenPrefix: "::ffff:203.0.113.0/120", whenIP: "203.0.113.10", expect: true},
+		{name: "IPv6 prefix, zoned address", givenPrefix: "2001:db8::/64", whenIP: "2001:db8::1%eth0", expect: true},
+		{name: "outside of prefix", givenPrefix: "203.0.113.0/24", whenIP: "203.0.114.10", expect: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := newIPChecker([]TrustOption{
+				TrustLoopback(false),
+				TrustLinkLocal(false),
+				TrustPrivateNet(false),
+				TrustIPPrefix(netip.MustParsePrefix(tc.givenPrefix)),
+			})
+
+			assert.Equal(t, tc.expect, checker.trust(parseTestIPAddr(tc.whenIP)))
+		})
+	}
+}
+
+func TestExtractIP_portsAndMalformedEntries(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		whenExtractor  IPExtractor
+		whenRemoteAddr string
+		whenHeader     http.Header
+		expectIP       string
+	}{
+		{
+			name:           "direct, remote address without port",
+			whenExtractor:  ExtractIPDirect(),
+			whenRemoteAddr: "203.0.113.1",
+			expectIP:       "203.0.113.1",
+		},
+		{
+			name:           "direct, zoned remote address",
+			whenExtractor:  ExtractIPDirect(),
+			whenRemoteAddr: "[fe80::1%eth0]:8080",
+			expectIP:       "fe80::1%eth0",
+		},
+		{
+			name:           "direct, unparsable remote address",
+			whenExtractor:  ExtractIPDirect(),
+			whenRemoteAddr: "@",
+			expectIP:       "",
+		},
+		{
+			name:           "real ip, value with port",
+			whenExtractor:  ExtractIPFromRealIPHeader(),
+			whenRemoteAddr: "127.0.0.1:8080",
+			whenHeader:     http.Header{HeaderXRealIP: []string{"203.0.113.10:5555"}},
+			expectIP:       "203.0.113.10",
+		},
+		{
+			name:           "real ip, IPv6 value with port",
+			whenExtractor:  ExtractIPFromRealIPHeader(),
+			whenRemoteAddr: "127.0.0.1:8080",
+			whenHeader:     http.Header{HeaderXRealIP: []string{"[2001:db8::1]:5555"}},
+			expectIP:       "2001:db8::1",
+		},
+		{
+			name:           "real ip, mapped value",
+			whenExtractor:  ExtractIPFromRealIPHeader(),
+			whenRemoteAddr: "127.0.0.1:8080",
+			whenHeader:     http.Header{HeaderXRealIP: []string{"::ffff:203.0.113.10"}},
+			expectIP:       "203.0.113.10",
+		},
+		{
+			name:           "real ip, mapped loopback remote address is trusted",
+			whenExtractor:  ExtractIPFromRealIPHeader(),
+			whenRemoteAddr: "[::ffff:127.0.0.1]:8080",
+			whenHeader:     http.Header{HeaderXRealIP: []string{"203.0.113.10"}},
+			expectIP:       "203.0.113.10",
+		},
+		{
+			name:           "real ip, malformed value",
+			whenExtractor:  ExtractIPFromRealIPHeader(),
+			whenRemoteAddr: "127.0.0.1:8080",
+			whenHeader:     http.Header{HeaderXRealIP: []string{"203.0.113.10:port"}},
+			expectIP:       "127.0.0.1",
+		},
+		{
+			name:           "xff, entries with ports",
+			whenExtractor:  ExtractIPFromXFFHeader(),
+			whenRemoteAddr: "127.0.0.1:8080",
+			whenHeader:     http.Header{HeaderXForwardedFor: []string{"203.0.113.10:5555, [fe80::1%eth0]:80"}},
+			expectIP:       "203.0.113.10",
+		},
+		{
+			name:           "xff, mapped entry",
+			whenExtractor:  ExtractIPFromXFFHeader(),
+			whenRemoteAddr: "127.0.0.1:8080",
+			whenHeader:     http.Header{HeaderXForwardedFor: []string{"::ffff:203.0.113.10, 10.0.0.1"}},
+			expectIP:       "203.0.113.10",
+		},
+		{
+			name:           "xff, all trusted returns furthest normalized",
+			whenExtractor:  ExtractIPFromXFFHeader(),
+			whenRemoteAddr: "127.0.0.1:8080",
+			whenHeader:     http.Header{HeaderXForwardedFor: []string{"[10.0.0.2]:80, 10.0.0.1"}},
+			expectIP:       "10.0.0.2",
+		},
+		{
+			name:           "xff, malformed entry",
+			whenExtractor:  ExtractIPFromXFFHeader(),
+			whenRemoteAddr: "127.0.0.1:8080",
+			whenHeader:     http.Header{HeaderXForwardedFor: []string{"203.0.113.10, unknown"}},
+			expectIP:       "127.0.0.1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &http.Request{RemoteAddr: tc.whenRemoteAddr, Header: tc.whenHeader}
+
+			assert.Equal(t, tc.expectIP, tc.whenExtractor(req))
+		})
+	}
+}
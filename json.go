@@ -4,17 +4,33 @@
 package echo
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
 )
 
 // DefaultJSONSerializer implements JSON encoding using encoding/json.
-type DefaultJSONSerializer struct{}
+type DefaultJSONSerializer struct {
+	// SortKeys enables deterministic output mode where keys of all JSON objects are written in sorted order. This
+	// includes struct fields and output of custom json.Marshaler implementations (encoding/json sorts only map keys).
+	// Numbers are written as they were marshalled. Useful for snapshot (golden file) tests.
+	//
+	// Output is marshalled, decoded and encoded again, so serialization takes roughly 3 times longer and allocates
+	// more. Not recommended for production traffic.
+	SortKeys bool
+}
 
 // Serialize converts an interface into a json and writes it to the response.
 // You can optionally use the indent parameter to produce pretty JSONs.
 func (d DefaultJSONSerializer) Serialize(c Context, i interface{}, indent string) error {
+	if d.SortKeys {
+		sorted, err := sortJSONKeys(i)
+		if err != nil {
+			return err
+		}
+		i = sorted
+	}
 	enc := json.NewEncoder(c.Response())
 	if indent != "" {
 		enc.SetIndent("", indent)
@@ -22,6 +38,22 @@ func (d DefaultJSONSerializer) Serialize(c Context, i interface{}, indent string
 	return enc.Encode(i)
 }
 
+// sortJSONKeys marshals i and decodes it to generic form (maps, slices and json.Number) that encoding/json encodes
+// with object keys in sorted order.
+func sortJSONKeys(i interface{}) (interface{}, error) {
+	b, err := json.Marshal(i)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var sorted interface{}
+	if err := dec.Decode(&sorted); err != nil {
+		return nil, err
+	}
+	return sorted, nil
+}
+
 // Deserialize reads a JSON from a request body and converts it into an interface.
 func (d DefaultJSONSerializer) Deserialize(c Context, i interface{}) error {
 	err := json.NewDecoder(c.Request().Body).Decode(i)
@@ -98,3 +98,54 @@ func TestDefaultJSONCodec_Decode(t *testing.T) {
 	assert.EqualError(t, err, "code=400, message=Unmarshal type error: expected=string, got=number, field=id, offset=7, internal=json: cannot unmarshal number into Go struct field .id of type string")
 
 }
+
+type sortKeysMarshaler struct{}
+
+func (sortKeysMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`{"z":1.50,"a":"<b>"}`), nil
+}
+
+func TestDefaultJSONSerializer_SortKeys(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		whenSort   bool
+		whenIndent string
+		expect     string
+	}{
+		{
+			name:   "ok, keys are written as marshalled",
+			expect: `{"name":"Jon","id":1,"nested":{"z":1.50,"a":"\u003cb\u003e"},"tags":{"a":true,"b":false}}` + "\n",
+		},
+		{
+			name:     "ok, keys are sorted",
+			whenSort: true,
+			expect:   `{"id":1,"name":"Jon","nested":{"a":"\u003cb\u003e","z":1.50},"tags":{"a":true,"b":false}}` + "\n",
+		},
+		{
+			name:       "ok, keys are sorted with indent",
+			whenSort:   true,
+			whenIndent: " ",
+			expect:     "{\n \"id\": 1,\n \"name\": \"Jon\",\n \"nested\": {\n  \"a\": \"\\u003cb\\u003e\",\n  \"z\": 1.50\n },\n \"tags\": {\n  \"a\": true,\n  \"b\": false\n }\n}\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+			value := struct {
+				Name   string            `json:"name"`
+				ID     int               `json:"id"`
+				Nested sortKeysMarshaler `json:"nested"`
+				Tags   map[string]bool   `json:"tags"`
+			}{Name: "Jon", ID: 1, Tags: map[string]bool{"b": false, "a": true}}
+
+			err := DefaultJSONSerializer{SortKeys: tc.whenSort}.Serialize(c, value, tc.whenIndent)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, rec.Body.String())
+		})
+	}
+}
@@ -4,8 +4,9 @@
 package echo
 
 import (
-	"github.com/labstack/gommon/log"
 	"io"
+
+	"github.com/labstack/gommon/log"
 )
 
 // Logger defines the logging interface.
@@ -39,3 +40,11 @@ type Logger interface {
 	Panicj(j log.JSON)
 	Panicf(format string, args ...interface{})
 }
+
+// LoggerWithFields is implemented by loggers that are able to create child logger with fields attached to every log
+// entry. When `Echo#Logger` implements it, `Context#Logger()` returns child logger created with request fields.
+// Loggers not implementing it (ala default gommon logger) are returned as is so file and line of log entries point
+// to the caller and type assertions on `Context#Logger()` keep working.
+type LoggerWithFields interface {
+	WithFields(fields map[string]interface{}) Logger
+}
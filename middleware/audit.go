@@ -0,0 +1,368 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AuditConfig defines the config for Audit middleware.
+type AuditConfig struct {
+	// Skipper defines a function to skip middleware.
+	Skipper Skipper
+
+	// Sink receives audit records. Records are written by a background goroutine after the request has been served.
+	// Required.
+	Sink AuditSink
+
+	// Methods is list of HTTP methods of requests that are audited.
+	// Optional. Default value []string{PUT, POST, PATCH, DELETE}.
+	Methods []string
+
+	// ActorContextKey is the context key that holds the actor (authenticated user) of the request. It is read after
+	// the handler has returned so actor set by authentication middleware registered after Audit is recorded as well.
+	// Optional. Default value "user".
+	ActorContextKey string
+
+	// MaxBodySize is maximum number of request body bytes stored in the record. Longer bodies are truncated.
+	// Optional. Default value 4KB. Use -1 to not store request body at all.
+	MaxBodySize int64
+
+	// RedactBody is called with request body snapshot before it is stored in the record. Use it to remove passwords,
+	// tokens and other secrets.
+	// Optional.
+	RedactBody func(c echo.Context, body []byte) []byte
+
+	// DiffFunc creates the mutation diff from before and after states set by handler with `AuditChange`.
+	// Optional. When not set before and after states are stored in the record as is.
+	DiffFunc func(before, after interface{}) interface{}
+
+	// QueueSize is number of records that can wait for the Sink. Records that do not fit into the queue are dropped
+	// and counted so the response is never blocked by slow Sink.
+	// Optional. Default value 100.
+	QueueSize int
+
+	// OnSinkError is called by background goroutine when Sink fails to write a record.
+	// Optional.
+	OnSinkError func(err error, record AuditRecord)
+}
+
+// AuditRecord is single audit trail entry of a state-changing request.
+type AuditRecord struct {
+	Time        time.Time         `json:"time"`
+	Actor       interface{}       `json:"actor,omitempty"`
+	Method      string            `json:"method"`
+	Route       string            `json:"route"`
+	URI         string            `json:"uri"`
+	Params      map[string]string `json:"params,omitempty"`
+	RemoteIP    string            `json:"remote_ip"`
+	RequestBody string            `json:"request_body,omitempty"`
+	// BodyTruncated is true when request body was longer than MaxBodySize.
+	BodyTruncated bool          `json:"body_truncated,omitempty"`
+	Status        int           `json:"status"`
+	Duration      time.Duration `json:"duration"`
+	Error         string        `json:"error,omitempty"`
+	Before        interface{}   `json:"before,omitempty"`
+	After         interface{}   `json:"after,omitempty"`
+	Diff          interface{}   `json:"diff,omitempty"`
+}
+
+// AuditSink receives audit records.
+type AuditSink interface {
+	WriteAuditRecord(record AuditRecord) error
+}
+
+// AuditStats contains counters of Auditor.
+type AuditStats struct {
+	// Written is number of records written to the Sink.
+	Written uint64 `json:"written"`
+	// Dropped is number of records dropped because queue was full.
+	Dropped uint64 `json:"dropped"`
+	// Failed is number of records the Sink failed to write.
+	Failed uint64 `json:"failed"`
+}
+
+// Auditor creates audit records of requests and delivers them to the Sink.
+type Auditor struct {
+	config  AuditConfig
+	methods map[string]struct{}
+	queue   chan AuditRecord
+	done    chan struct{}
+
+	// mu guards closed and sending to queue so records are not sent to the closed queue
+	mu     sync.Mutex
+	closed bool
+
+	written uint64
+	dropped uint64
+	failed  uint64
+}
+
+type auditChange struct {
+	before interface{}
+	after  interface{}
+}
+
+const auditChangeContextKey = "_audit_change"
+
+// DefaultAuditConfig is the default Audit middleware config.
+var DefaultAuditConfig = AuditConfig{
+	Skipper:         DefaultSkipper,
+	Methods:         []string{http.MethodPut, http.MethodPost, http.MethodPatch, http.MethodDelete},
+	ActorContextKey: "user",
+	MaxBodySize:     4 * 1024,
+	QueueSize:       100,
+}
+
+// Audit returns an Audit middleware that writes records of state-changing requests to the sink.
+func Audit(sink AuditSink) echo.MiddlewareFunc {
+	c := DefaultAuditConfig
+	c.Sink = sink
+	return AuditWithConfig(c)
+}
+
+// AuditWithConfig returns an Audit middleware with config.
+// See: `Audit()`.
+func AuditWithConfig(config AuditConfig) echo.MiddlewareFunc {
+	return NewAuditor(config).Middleware()
+}
+
+// AuditChange stores state of the changed entity before and after the mutation into context so it is added to the
+// audit record of the request.
+//
+// Example:
+//
+//	middleware.AuditChange(c, oldUser, newUser)
+func AuditChange(c echo.Context, before, after interface{}) {
+	c.Set(auditChangeContextKey, auditChange{before: before, after: after})
+}
+
+// NewAuditor creates Auditor with config and starts goroutine that writes records to the Sink. Use it instead of
+// `AuditWithConfig` when access to statistics is needed or the Sink must be flushed with `Close` on shutdown.
+func NewAuditor(config AuditConfig) *Auditor {
+	if config.Sink == nil {
+		panic("echo: audit middleware requires a sink")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultAuditConfig.Skipper
+	}
+	if len(config.Methods) == 0 {
+		config.Methods = DefaultAuditConfig.Methods
+	}
+	if config.ActorContextKey == "" {
+		config.ActorContextKey = DefaultAuditConfig.ActorContextKey
+	}
+	if config.MaxBodySize == 0 {
+		config.MaxBodySize = DefaultAuditConfig.MaxBodySize
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = DefaultAuditConfig.QueueSize
+	}
+
+	a := &Auditor{
+		config:  config,
+		methods: make(map[string]struct{}, len(config.Methods)),
+		queue:   make(chan AuditRecord, config.QueueSize),
+		done:    make(chan struct{}),
+	}
+	for _, m := range config.Methods {
+		a.methods[m] = struct{}{}
+	}
+	go a.run()
+	return a
+}
+
+// Middleware returns middleware that audits requests.
+func (a *Auditor) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if a.config.Skipper(c) {
+				return next(c)
+			}
+			req := c.Request()
+			if _, ok := a.methods[req.Method]; !ok {
+				return next(c)
+			}
+
+			start := time.Now()
+			record := AuditRecord{
+				Time:     start,
+				Method:   req.Method,
+				URI:      req.RequestURI,
+				RemoteIP: c.RealIP(),
+			}
+			if a.config.MaxBodySize > 0 && req.Body != nil {
+				body, truncated, err := snapshotBody(req, a.config.MaxBodySize)
+				if err != nil {
+					return err
+				}
+				if a.config.RedactBody != nil {
+					body = a.config.RedactBody(c, body)
+				}
+				record.RequestBody = string(body)
+				record.BodyTruncated = truncated
+			}
+
+			err := next(c)
+
+			record.Duration = time.Since(start)
+			record.Actor = c.Get(a.config.ActorContextKey)
+			record.Route = c.Path()
+			if names := c.ParamNames(); len(names) > 0 {
+				record.Params = make(map[string]string, len(names))
+				for _, name := range names {
+					record.Params[name] = c.Param(name)
+				}
+			}
+			record.Status = c.Response().Status
+			if err != nil {
+				record.Error = err.Error()
+				record.Status = http.StatusInternalServerError
+				var httpErr *echo.HTTPError
+				if errors.As(err, &httpErr) {
+					record.Status = httpErr.Code
+				}
+			}
+			if change, ok := c.Get(auditChangeContextKey).(auditChange); ok {
+				if a.config.DiffFunc != nil {
+					record.Diff = a.config.DiffFunc(change.before, change.after)
+				} else {
+					record.Before = change.before
+					record.After = change.after
+				}
+			}
+
+			a.enqueue(record)
+			return err
+		}
+	}
+}
+
+// snapshotBody reads up to limit bytes from request body and restores the body so handler can read it fully.
+func snapshotBody(req *http.Request, limit int64) ([]byte, bool, error) {
+	buf := make([]byte, limit+1)
+	n, err := io.ReadFull(req.Body, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, false, err
+	}
+	buf = buf[:n]
+	req.Body = &auditBody{Reader: io.MultiReader(bytes.NewReader(buf), req.Body), Closer: req.Body}
+	if int64(n) > limit {
+		return buf[:limit], true, nil
+	}
+	return buf, false, nil
+}
+
+type auditBody struct {
+	io.Reader
+	io.Closer
+}
+
+func (a *Auditor) enqueue(record AuditRecord) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		atomic.AddUint64(&a.dropped, 1)
+		return
+	}
+	select {
+	case a.queue <- record:
+	default:
+		atomic.AddUint64(&a.dropped, 1)
+	}
+}
+
+func (a *Auditor) run() {
+	defer close(a.done)
+	for record := range a.queue {
+		if err := a.config.Sink.WriteAuditRecord(record); err != nil {
+			atomic.AddUint64(&a.failed, 1)
+			if a.config.OnSinkError != nil {
+				a.config.OnSinkError(err, record)
+			}
+			continue
+		}
+		atomic.AddUint64(&a.written, 1)
+	}
+}
+
+// Stats returns counters of written, dropped and failed records.
+func (a *Auditor) Stats() AuditStats {
+	return AuditStats{
+		Written: atomic.LoadUint64(&a.written),
+		Dropped: atomic.LoadUint64(&a.dropped),
+		Failed:  atomic.LoadUint64(&a.failed),
+	}
+}
+
+// Close stops accepting new records and waits until queued records are written to the Sink. Records of requests
+// served after Close are dropped.
+func (a *Auditor) Close() error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.queue)
+	}
+	a.mu.Unlock()
+	<-a.done
+	return nil
+}
+
+// AuditJSONLinesSink writes audit records as JSON lines to the writer.
+type AuditJSONLinesSink struct {
+	mutex   sync.Mutex
+	writer  io.Writer
+	encoder *json.Encoder
+}
+
+// NewAuditJSONLinesSink creates sink that writes records as JSON lines to the writer.
+func NewAuditJSONLinesSink(w io.Writer) *AuditJSONLinesSink {
+	return &AuditJSONLinesSink{writer: w, encoder: json.NewEncoder(w)}
+}
+
+// NewAuditFileSink creates sink that appends records as JSON lines to the file. File is created when it does not
+// exist. Close the sink to close the file.
+func NewAuditFileSink(filename string) (*AuditJSONLinesSink, error) {
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return NewAuditJSONLinesSink(f), nil
+}
+
+// WriteAuditRecord writes record as single JSON line.
+func (s *AuditJSONLinesSink) WriteAuditRecord(record AuditRecord) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.encoder.Encode(record)
+}
+
+// Close closes underlying writer when it implements io.Closer.
+func (s *AuditJSONLinesSink) Close() error {
+	if c, ok := s.writer.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// AuditChannelSink sends audit records to the channel. Sending blocks the background goroutine of Auditor (not the
+// response) when channel is full.
+type AuditChannelSink chan<- AuditRecord
+
+// WriteAuditRecord sends record to the channel.
+func (s AuditChannelSink) WriteAuditRecord(record AuditRecord) error {
+	s <- record
+	return nil
+}
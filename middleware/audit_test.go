@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudit(t *testing.T) {
+	records := make(chan AuditRecord, 10)
+	auditor := NewAuditor(AuditConfig{
+		Sink:        AuditChannelSink(records),
+		MaxBodySize: 10,
+		RedactBody: func(c echo.Context, body []byte) []byte {
+			return bytes.ReplaceAll(body, []byte("secret"), []byte("******"))
+		},
+	})
+
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("user", "alice")
+			return next(c)
+		}
+	})
+	e.Use(auditor.Middleware())
+	e.PUT("/users/:id", func(c echo.Context) error {
+		b, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+		AuditChange(c, "old", "new")
+		return c.String(http.StatusOK, string(b))
+	})
+	e.GET("/users/:id", func(c echo.Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/users/1", strings.NewReader("secret=1&name=long-name"))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "secret=1&name=long-name", rec.Body.String()) // handler still reads full body
+
+	req = httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.NoError(t, auditor.Close())
+	close(records)
+
+	var result []AuditRecord
+	for r := range records {
+		result = append(result, r)
+	}
+	assert.Len(t, result, 1)
+	r := result[0]
+	assert.Equal(t, "alice", r.Actor)
+	assert.Equal(t, http.MethodPut, r.Method)
+	assert.Equal(t, "/users/:id", r.Route)
+	assert.Equal(t, "/users/1", r.URI)
+	assert.Equal(t, map[string]string{"id": "1"}, r.Params)
+	assert.Equal(t, "******=1&n", r.RequestBody)
+	assert.True(t, r.BodyTruncated)
+	assert.Equal(t, http.StatusOK, r.Status)
+	assert.Equal(t, "old", r.Before)
+	assert.Equal(t, "new", r.After)
+	assert.Equal(t, AuditStats{Written: 1}, auditor.Stats())
+}
+
+func TestAudit_errorStatusAndDiff(t *testing.T) {
+	records := make(chan AuditRecord, 1)
+	auditor := NewAuditor(AuditConfig{
+		Sink: AuditChannelSink(records),
+		DiffFunc: func(before, after interface{}) interface{} {
+			return map[string]interface{}{"from": before, "to": after}
+		},
+	})
+
+	e := echo.New()
+	e.DELETE("/", func(c echo.Context) error {
+		AuditChange(c, 1, 2)
+		return echo.ErrForbidden
+	}, auditor.Middleware())
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/", nil))
+	assert.NoError(t, auditor.Close())
+
+	r := <-records
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, http.StatusForbidden, r.Status)
+	assert.Equal(t, "code=403, message=Forbidden", r.Error)
+	assert.Equal(t, map[string]interface{}{"from": 1, "to": 2}, r.Diff)
+	assert.Nil(t, r.Before)
+}
+
+func TestAudit_droppedWhenQueueIsFull(t *testing.T) {
+	records := make(chan AuditRecord) // unbuffered, blocks the sink
+	auditor := NewAuditor(AuditConfig{Sink: AuditChannelSink(records), QueueSize: 1})
+
+	e := echo.New()
+	e.POST("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}, auditor.Middleware())
+
+	for i := 0; i < 5; i++ {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+	}
+	// at most one record is held by sink goroutine and one waits in the queue
+	assert.GreaterOrEqual(t, auditor.Stats().Dropped, uint64(3))
+
+	go func() {
+		for range records {
+		}
+	}()
+	assert.NoError(t, auditor.Close())
+	close(records)
+}
+
+func TestAudit_actorSetByHandler(t *testing.T) {
+	records := make(chan AuditRecord, 1)
+	auditor := NewAuditor(AuditConfig{Sink: AuditChannelSink(records)})
+
+	e := echo.New()
+	e.Use(auditor.Middleware())
+	e.POST("/", func(c echo.Context) error {
+		c.Set("user", "bob") // ala authentication middleware registered on route
+		return c.NoContent(http.StatusNoContent)
+	})
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.NoError(t, auditor.Close())
+
+	r := <-records
+	assert.Equal(t, "bob", r.Actor)
+}
+
+func TestAudit_droppedAfterClose(t *testing.T) {
+	records := make(chan AuditRecord, 1)
+	auditor := NewAuditor(AuditConfig{Sink: AuditChannelSink(records)})
+	assert.NoError(t, auditor.Close())
+	assert.NoError(t, auditor.Close())
+
+	e := echo.New()
+	e.POST("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}, auditor.Middleware())
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, AuditStats{Dropped: 1}, auditor.Stats())
+}
+
+type failingAuditSink struct{}
+
+func (failingAuditSink) WriteAuditRecord(AuditRecord) error {
+	return errors.New("disk full")
+}
+
+func TestAudit_sinkError(t *testing.T) {
+	var sinkErr error
+	auditor := NewAuditor(AuditConfig{
+		Sink: failingAuditSink{},
+		OnSinkError: func(err error, record AuditRecord) {
+			sinkErr = err
+		},
+	})
+
+	e := echo.New()
+	e.POST("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}, auditor.Middleware())
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+
+	assert.NoError(t, auditor.Close())
+	assert.EqualError(t, sinkErr, "disk full")
+	assert.Equal(t, AuditStats{Failed: 1}, auditor.Stats())
+}
+
+func TestAuditFileSink(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := NewAuditFileSink(filename)
+	assert.NoError(t, err)
+
+	assert.NoError(t, sink.WriteAuditRecord(AuditRecord{Method: http.MethodPost, Route: "/a", Status: 201}))
+	assert.NoError(t, sink.WriteAuditRecord(AuditRecord{Method: http.MethodDelete, Route: "/b", Status: 204}))
+	assert.NoError(t, sink.Close())
+
+	b, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	assert.Len(t, lines, 2)
+
+	var r AuditRecord
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &r))
+	assert.Equal(t, "/b", r.Route)
+	assert.Equal(t, 204, r.Status)
+}
+
+func TestAudit_panicsWithoutSink(t *testing.T) {
+	assert.Panics(t, func() {
+		Audit(nil)
+	})
+}
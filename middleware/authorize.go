@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AuthorizeConfig defines the config for Authorize middleware.
+type AuthorizeConfig struct {
+	// Skipper defines a function to skip middleware.
+	Skipper Skipper
+
+	// ScopesExtractor returns scopes granted to the request. Returning false means that the request is not
+	// authenticated and results "401 - Unauthorized" error.
+	// Optional. Default value reads scopes from context key ScopesContextKey (see `ScopesFromContext`).
+	ScopesExtractor func(c echo.Context) ([]string, bool)
+
+	// MatchAny makes request with any of the route scopes authorized. By default all route scopes are required.
+	// Optional. Default value false.
+	MatchAny bool
+
+	// Matcher returns true when granted scope satisfies required scope.
+	// Optional. Default value MatchScope (exact match and `users:*` wildcards). Use MatchScopeExact to disable wildcards.
+	Matcher func(granted, required string) bool
+
+	// DefaultDeny makes routes without declared scopes (see `echo.RequireScopes`) inaccessible: authenticated requests
+	// result "403 - Forbidden" and unauthenticated requests "401 - Unauthorized" error. By default routes without
+	// scopes are accessible without authentication.
+	// Optional. Default value false.
+	DefaultDeny bool
+}
+
+// ScopesContextKey is the context key the default `AuthorizeConfig.ScopesExtractor` reads granted scopes from.
+// Authentication middleware (ala JWT or KeyAuth validator) should store `[]string` or space separated string (OAuth2
+// `scope` claim format) there.
+const ScopesContextKey = "scopes"
+
+// InsufficientScopeError is returned (wrapped into `HTTPError.Internal`) by Authorize middleware when request lacks
+// scopes required by the route.
+type InsufficientScopeError struct {
+	// Required are scopes declared for the route.
+	Required []string `json:"required"`
+	// MatchAny is true when any of the Required scopes would have been enough.
+	MatchAny bool `json:"match_any"`
+}
+
+// Error returns error message listing the required scopes.
+func (e *InsufficientScopeError) Error() string {
+	if len(e.Required) == 0 {
+		return "insufficient scope: route does not allow access"
+	}
+	if e.MatchAny {
+		return fmt.Sprintf("insufficient scope: requires any of %s", strings.Join(e.Required, ", "))
+	}
+	return fmt.Sprintf("insufficient scope: requires %s", strings.Join(e.Required, ", "))
+}
+
+// DefaultAuthorizeConfig is the default Authorize middleware config.
+var DefaultAuthorizeConfig = AuthorizeConfig{
+	Skipper:         DefaultSkipper,
+	ScopesExtractor: ScopesFromContext,
+	Matcher:         MatchScope,
+}
+
+// ScopesFromContext returns scopes stored in context key ScopesContextKey. Value can be `[]string` or space separated
+// string. Returns false when the key is not set.
+func ScopesFromContext(c echo.Context) ([]string, bool) {
+	switch v := c.Get(ScopesContextKey).(type) {
+	case []string:
+		return v, true
+	case string:
+		return strings.Fields(v), true
+	default:
+		return nil, false
+	}
+}
+
+// MatchScope returns true when granted scope is equal to required scope or is wildcard matching it. Wildcard `*`
+// matches all scopes and `users:*` matches scopes starting with `users:` (ala `users:read` or `users:read:self`).
+func MatchScope(granted, required string) bool {
+	if granted == required || granted == "*" {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(granted, "*"); ok && strings.HasSuffix(prefix, ":") {
+		return len(required) > len(prefix) && strings.HasPrefix(required, prefix)
+	}
+	return false
+}
+
+// MatchScopeExact returns true when granted scope is equal to required scope.
+func MatchScopeExact(granted, required string) bool {
+	return granted == required
+}
+
+// Authorize returns an Authorize middleware that allows requests only when scopes granted to the request satisfy all
+// scopes declared for the matched route with `echo.RequireScopes`.
+//
+// Example:
+//
+//	e.Use(authenticate) // stores granted scopes with c.Set(middleware.ScopesContextKey, scopes)
+//	e.Use(middleware.Authorize())
+//	e.WithRouteOptions(echo.RequireScopes("users:write")).POST("/users", createUser)
+func Authorize() echo.MiddlewareFunc {
+	return AuthorizeWithConfig(DefaultAuthorizeConfig)
+}
+
+// AuthorizeWithConfig returns an Authorize middleware with config.
+// See: `Authorize()`.
+func AuthorizeWithConfig(config AuthorizeConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = DefaultAuthorizeConfig.Skipper
+	}
+	if config.ScopesExtractor == nil {
+		config.ScopesExtractor = DefaultAuthorizeConfig.ScopesExtractor
+	}
+	if config.Matcher == nil {
+		config.Matcher = DefaultAuthorizeConfig.Matcher
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			required := c.RouteScopes()
+			if len(required) == 0 && !config.DefaultDeny {
+				return next(c)
+			}
+			granted, ok := config.ScopesExtractor(c)
+			if !ok {
+				return echo.ErrUnauthorized
+			}
+			if len(required) == 0 || !config.authorized(granted, required) {
+				err := &InsufficientScopeError{Required: required, MatchAny: config.MatchAny}
+				return echo.NewHTTPError(http.StatusForbidden, err.Error()).SetInternal(err)
+			}
+			return next(c)
+		}
+	}
+}
+
+func (config AuthorizeConfig) authorized(granted []string, required []string) bool {
+	for _, r := range required {
+		satisfied := false
+		for _, g := range granted {
+			if config.Matcher(g, r) {
+				satisfied = true
+				break
+			}
+		}
+		if satisfied && config.MatchAny {
+			return true
+		}
+		if !satisfied && !config.MatchAny {
+			return false
+		}
+	}
+	return !config.MatchAny
+}
@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorize(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		givenConfig  AuthorizeConfig
+		givenScopes  []string
+		givenRoute   []string
+		whenScopes   interface{}
+		expectStatus int
+		expectErr    string
+	}{
+		{
+			name:         "ok, route without scopes is allowed",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "ok, all scopes granted",
+			givenRoute:   []string{"users:read", "users:write"},
+			whenScopes:   []string{"users:write", "users:read", "orders:read"},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "ok, scopes from space separated string",
+			givenRoute:   []string{"users:read"},
+			whenScopes:   "orders:read users:read",
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "ok, wildcard scope",
+			givenRoute:   []string{"users:write"},
+			whenScopes:   []string{"users:*"},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "ok, any of scopes",
+			givenConfig:  AuthorizeConfig{MatchAny: true},
+			givenRoute:   []string{"users:write", "admin"},
+			whenScopes:   []string{"admin"},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "nok, unauthenticated",
+			givenRoute:   []string{"users:read"},
+			expectStatus: http.StatusUnauthorized,
+			expectErr:    "code=401, message=Unauthorized",
+		},
+		{
+			name:         "nok, missing one of all scopes",
+			givenRoute:   []string{"users:read", "users:write"},
+			whenScopes:   []string{"users:read"},
+			expectStatus: http.StatusForbidden,
+			expectErr:    "code=403, message=insufficient scope: requires users:read, users:write, internal=insufficient scope: requires users:read, users:write",
+		},
+		{
+			name:         "nok, none of any scopes",
+			givenConfig:  AuthorizeConfig{MatchAny: true},
+			givenRoute:   []string{"users:write", "admin"},
+			whenScopes:   []string{"users:read"},
+			expectStatus: http.StatusForbidden,
+			expectErr:    "code=403, message=insufficient scope: requires any of users:write, admin, internal=insufficient scope: requires any of users:write, admin",
+		},
+		{
+			name:         "nok, wildcard disabled with exact matcher",
+			givenConfig:  AuthorizeConfig{Matcher: MatchScopeExact},
+			givenRoute:   []string{"users:write"},
+			whenScopes:   []string{"users:*"},
+			expectStatus: http.StatusForbidden,
+			expectErr:    "code=403, message=insufficient scope: requires users:write, internal=insufficient scope: requires users:write",
+		},
+		{
+			name:         "nok, default deny for authenticated request",
+			givenConfig:  AuthorizeConfig{DefaultDeny: true},
+			whenScopes:   []string{"*"},
+			expectStatus: http.StatusForbidden,
+			expectErr:    "code=403, message=insufficient scope: route does not allow access, internal=insufficient scope: route does not allow access",
+		},
+		{
+			name:         "nok, default deny for unauthenticated request",
+			givenConfig:  AuthorizeConfig{DefaultDeny: true},
+			expectStatus: http.StatusUnauthorized,
+			expectErr:    "code=401, message=Unauthorized",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			var handlerErr error
+			e.HTTPErrorHandler = func(err error, c echo.Context) {
+				handlerErr = err
+				e.DefaultHTTPErrorHandler(err, c)
+			}
+			e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					if tc.whenScopes != nil {
+						c.Set(ScopesContextKey, tc.whenScopes)
+					}
+					return next(c)
+				}
+			})
+			e.Use(AuthorizeWithConfig(tc.givenConfig))
+			var options []echo.RouteOption
+			if len(tc.givenRoute) > 0 {
+				options = append(options, echo.RequireScopes(tc.givenRoute...))
+			}
+			e.WithRouteOptions(options...).GET("/", func(c echo.Context) error {
+				return c.String(http.StatusOK, "ok")
+			})
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, tc.expectStatus, rec.Code)
+			if tc.expectErr == "" {
+				assert.NoError(t, handlerErr)
+				return
+			}
+			assert.EqualError(t, handlerErr, tc.expectErr)
+			if tc.expectStatus == http.StatusForbidden {
+				var scopeErr *InsufficientScopeError
+				assert.True(t, errors.As(handlerErr, &scopeErr))
+				assert.Equal(t, tc.givenRoute, scopeErr.Required)
+			}
+		})
+	}
+}
+
+func TestMatchScope(t *testing.T) {
+	var testCases = []struct {
+		granted  string
+		required string
+		expect   bool
+	}{
+		{granted: "users:read", required: "users:read", expect: true},
+		{granted: "users:read", required: "users:write", expect: false},
+		{granted: "*", required: "users:read", expect: true},
+		{granted: "users:*", required: "users:read", expect: true},
+		{granted: "users:*", required: "users:read:self", expect: true},
+		{granted: "users:*", required: "users", expect: false},
+		{granted: "users:*", required: "users:", expect: false},
+		{granted: "users:*", required: "usersx:read", expect: false},
+		{granted: "users*", required: "users:read", expect: false},
+		{granted: "", required: "users:read", expect: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.granted+" "+tc.required, func(t *testing.T) {
+			assert.Equal(t, tc.expect, MatchScope(tc.granted, tc.required))
+		})
+	}
+}
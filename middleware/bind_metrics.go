@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// BindMetricsConfig defines the config for InstrumentBinder. Functions are meant to be adapters to metrics library
+// counters and histograms (ala Prometheus `CounterVec` labeled by route and source).
+type BindMetricsConfig struct {
+	// IncBindError is called for every field that failed to bind. Route is the matched route path (`c.Path()`) and
+	// source is one of "path", "query", "header", "form" or "body".
+	// Required.
+	IncBindError func(route string, source string)
+
+	// ObserveBindDuration is called with duration of every bind call.
+	// Optional.
+	ObserveBindDuration func(route string, duration time.Duration)
+}
+
+// InstrumentBinder sets `OnBindError` and `OnBindComplete` hooks of the binder to report metrics labeled by route.
+// Hooks already set on the binder are still called.
+//
+// Example:
+//
+//	binder := &echo.DefaultBinder{}
+//	middleware.InstrumentBinder(binder, middleware.BindMetricsConfig{
+//		IncBindError: func(route, source string) {
+//			bindErrors.WithLabelValues(route, source).Inc()
+//		},
+//	})
+//	e.Binder = binder
+func InstrumentBinder(binder *echo.DefaultBinder, config BindMetricsConfig) {
+	if config.IncBindError == nil {
+		panic("echo: instrument binder requires IncBindError")
+	}
+
+	onError := binder.OnBindError
+	binder.OnBindError = func(c echo.Context, source string, field string, err error) {
+		config.IncBindError(c.Path(), source)
+		if onError != nil {
+			onError(c, source, field, err)
+		}
+	}
+
+	if config.ObserveBindDuration == nil {
+		return
+	}
+	onComplete := binder.OnBindComplete
+	binder.OnBindComplete = func(c echo.Context, duration time.Duration) {
+		config.ObserveBindDuration(c.Path(), duration)
+		if onComplete != nil {
+			onComplete(c, duration)
+		}
+	}
+}
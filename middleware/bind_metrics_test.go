@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstrumentBinder(t *testing.T) {
+	errorCounts := map[string]int{}
+	observed := map[string]int{}
+	var chained []string
+	binder := &echo.DefaultBinder{
+		OnBindError: func(c echo.Context, source string, field string, err error) {
+			chained = append(chained, field)
+		},
+	}
+	InstrumentBinder(binder, BindMetricsConfig{
+		IncBindError: func(route string, source string) {
+			errorCounts[route+" "+source]++
+		},
+		ObserveBindDuration: func(route string, duration time.Duration) {
+			observed[route]++
+		},
+	})
+
+	e := echo.New()
+	e.Binder = binder
+	e.POST("/users/:id", func(c echo.Context) error {
+		var u struct {
+			ID   int    `param:"id"`
+			Name string `json:"name"`
+		}
+		return c.Bind(&u)
+	})
+
+	for _, body := range []string{`{"name":"jon"}`, `{"name":`} {
+		req := httptest.NewRequest(http.MethodPost, "/users/abc", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		e.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/users/1", strings.NewReader(`{"name":`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, map[string]int{"/users/:id path": 2, "/users/:id body": 1}, errorCounts)
+	assert.Equal(t, map[string]int{"/users/:id": 3}, observed)
+	assert.Equal(t, []string{"id", "id", ""}, chained)
+}
+
+func TestInstrumentBinder_requiresIncBindError(t *testing.T) {
+	assert.PanicsWithValue(t, "echo: instrument binder requires IncBindError", func() {
+		InstrumentBinder(&echo.DefaultBinder{}, BindMetricsConfig{})
+	})
+}
@@ -0,0 +1,337 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// BruteForceStore is the interface to be implemented by custom stores (ala Redis) of BruteForce middleware. Stores
+// keep number of consecutive failures per key and must be safe for concurrent use.
+type BruteForceStore interface {
+	// Failures returns number of consecutive failures of the key and time of the last failure.
+	Failures(key string) (count int, lastFailure time.Time, err error)
+	// Reserve checks that the key does not have to wait and counts the attempt as failure at given time, before the
+	// outcome is known. Check and increment must be atomic, so concurrent attempts can not pass the check together.
+	// delay returns time to wait since the last failure after given number of failures. Returns time left to wait,
+	// without counting the attempt, when the key has to wait.
+	Reserve(key string, now time.Time, delay func(failures int) time.Duration) (wait time.Duration, err error)
+	// Release reverts attempt counted by Reserve that turned out not to be an authentication attempt.
+	Release(key string) error
+	// Reset clears failures of the key.
+	Reset(key string) error
+}
+
+// BruteForceConfig defines the config for BruteForce middleware.
+type BruteForceConfig struct {
+	// Skipper defines a function to skip middleware.
+	Skipper Skipper
+
+	// KeyExtractor returns key failures are tracked by.
+	// Optional. Default value is IP of the direct peer (see `echo.ExtractIPDirect`) and form value `username` (ala
+	// `10.0.0.1|alice`). Use key that can not be changed by the client, real IP resolved from headers is spoofable when
+	// `Echo#IPExtractor` does not trust only known proxies.
+	KeyExtractor Extractor
+
+	// Store keeps failures per key.
+	// Required.
+	Store BruteForceStore
+
+	// Threshold is number of consecutive failures allowed before delays are enforced.
+	// Optional. Default value 5.
+	Threshold int
+
+	// BaseDelay is delay enforced after the first failure over the threshold. Delay doubles with every following
+	// failure.
+	// Optional. Default value 1 second.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay.
+	// Optional. Default value 15 minutes.
+	MaxDelay time.Duration
+
+	// IsFailure decides outcome of the request when handler did not report it with `ReportAuthResult`.
+	// Optional. Default value treats "401 - Unauthorized" and "403 - Forbidden" as failures and 2xx responses as
+	// successes. Other responses do not change the failure count.
+	IsFailure func(c echo.Context, err error) (failure bool, known bool)
+
+	// ErrorHandler is called when KeyExtractor or Store returns an error.
+	// Optional. Default value returns "500 - Internal Server Error".
+	ErrorHandler func(c echo.Context, err error) error
+
+	// DenyHandler is called when request is rejected before the handler runs. `Retry-After` header is already set.
+	// Optional. Default value returns ErrTooManyFailedAttempts.
+	DenyHandler func(c echo.Context, key string, retryAfter time.Duration) error
+}
+
+// ErrTooManyFailedAttempts denotes an error raised when client has to wait before the next attempt.
+var ErrTooManyFailedAttempts = echo.NewHTTPError(http.StatusTooManyRequests, "too many failed attempts")
+
+// DefaultBruteForceConfig is the default BruteForce middleware config.
+var DefaultBruteForceConfig = BruteForceConfig{
+	Skipper: DefaultSkipper,
+	KeyExtractor: func(c echo.Context) (string, error) {
+		return echo.ExtractIPDirect()(c.Request()) + "|" + c.FormValue("username"), nil
+	},
+	Threshold: 5,
+	BaseDelay: time.Second,
+	MaxDelay:  15 * time.Minute,
+	IsFailure: func(c echo.Context, err error) (bool, bool) {
+		status := c.Response().Status
+		if err != nil {
+			status = http.StatusInternalServerError
+			var he *echo.HTTPError
+			if errors.As(err, &he) {
+				status = he.Code
+			}
+		}
+		switch {
+		case status == http.StatusUnauthorized || status == http.StatusForbidden:
+			return true, true
+		case status >= 200 && status < 300:
+			return false, true
+		}
+		return false, false
+	},
+	ErrorHandler: func(c echo.Context, err error) error {
+		return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
+	},
+	DenyHandler: func(c echo.Context, key string, retryAfter time.Duration) error {
+		return ErrTooManyFailedAttempts
+	},
+}
+
+const bruteForceResultContextKey = "_brute_force_result"
+
+// ReportAuthResult reports outcome of the authentication attempt to BruteForce middleware. Successful attempt resets
+// the failure count of the key.
+//
+// Example:
+//
+//	if !checkPassword(user, password) {
+//		middleware.ReportAuthResult(c, false)
+//		return echo.ErrUnauthorized
+//	}
+//	middleware.ReportAuthResult(c, true)
+func ReportAuthResult(c echo.Context, success bool) {
+	c.Set(bruteForceResultContextKey, success)
+}
+
+/*
+BruteForce returns a middleware that protects authentication endpoints against password guessing. Consecutive failures
+are counted per key (client IP and username) and once the threshold is exceeded the client has to wait exponentially
+growing delay before the next attempt. Requests sent too early are rejected with "429 - Too Many Requests" and
+`Retry-After` header before the handler runs. Attempts are counted as failures before the handler runs, so concurrent
+attempts can not get past the threshold while the outcome of earlier attempts is not known yet.
+
+	e.POST("/login", loginHandler, middleware.BruteForce(middleware.NewBruteForceMemoryStore(time.Hour)))
+*/
+func BruteForce(store BruteForceStore) echo.MiddlewareFunc {
+	c := DefaultBruteForceConfig
+	c.Store = store
+	return BruteForceWithConfig(c)
+}
+
+// BruteForceWithConfig returns a BruteForce middleware with config.
+// See: `BruteForce()`.
+func BruteForceWithConfig(config BruteForceConfig) echo.MiddlewareFunc {
+	if config.Store == nil {
+		panic("echo: brute force middleware requires a store")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultBruteForceConfig.Skipper
+	}
+	if config.KeyExtractor == nil {
+		config.KeyExtractor = DefaultBruteForceConfig.KeyExtractor
+	}
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultBruteForceConfig.Threshold
+	}
+	if config.BaseDelay <= 0 {
+		config.BaseDelay = DefaultBruteForceConfig.BaseDelay
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = DefaultBruteForceConfig.MaxDelay
+	}
+	if config.IsFailure == nil {
+		config.IsFailure = DefaultBruteForceConfig.IsFailure
+	}
+	if config.ErrorHandler == nil {
+		config.ErrorHandler = DefaultBruteForceConfig.ErrorHandler
+	}
+	if config.DenyHandler == nil {
+		config.DenyHandler = DefaultBruteForceConfig.DenyHandler
+	}
+
+	delay := func(failures int) time.Duration {
+		return bruteForceDelay(failures, config)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			key, err := config.KeyExtractor(c)
+			if err != nil {
+				return config.ErrorHandler(c, err)
+			}
+			wait, err := config.Store.Reserve(key, time.Now(), delay)
+			if err != nil {
+				return config.ErrorHandler(c, err)
+			}
+			if wait > 0 {
+				seconds := int64(math.Ceil(wait.Seconds()))
+				c.Response().Header().Set(echo.HeaderRetryAfter, strconv.FormatInt(seconds, 10))
+				return config.DenyHandler(c, key, wait)
+			}
+
+			err = next(c)
+
+			success, reported := c.Get(bruteForceResultContextKey).(bool)
+			if !reported {
+				var failure bool
+				failure, reported = config.IsFailure(c, err)
+				success = !failure
+			}
+			var storeErr error
+			switch {
+			case !reported:
+				storeErr = config.Store.Release(key)
+			case success:
+				storeErr = config.Store.Reset(key)
+			}
+			if storeErr != nil && err == nil {
+				return config.ErrorHandler(c, storeErr)
+			}
+			return err
+		}
+	}
+}
+
+// bruteForceDelay returns delay the client has to wait after given number of consecutive failures.
+func bruteForceDelay(failures int, config BruteForceConfig) time.Duration {
+	over := failures - config.Threshold
+	if over <= 0 {
+		return 0
+	}
+	if over > 62 {
+		return config.MaxDelay
+	}
+	delay := config.BaseDelay << (over - 1)
+	if delay <= 0 || delay > config.MaxDelay {
+		return config.MaxDelay
+	}
+	return delay
+}
+
+// BruteForceMemoryStore is the built-in in-memory store of BruteForce middleware. Keys without failures for
+// ExpiresIn are removed.
+type BruteForceMemoryStore struct {
+	mutex       sync.Mutex
+	failures    map[string]*bruteForceFailures
+	expiresIn   time.Duration
+	lastCleanup time.Time
+
+	timeNow func() time.Time
+}
+
+type bruteForceFailures struct {
+	count       int
+	lastFailure time.Time
+}
+
+// NewBruteForceMemoryStore creates in-memory store that forgets failures of keys after expiresIn since the last
+// failure. ExpiresIn should be longer than MaxDelay of the middleware. Zero value means 1 hour.
+func NewBruteForceMemoryStore(expiresIn time.Duration) *BruteForceMemoryStore {
+	if expiresIn <= 0 {
+		expiresIn = time.Hour
+	}
+	return &BruteForceMemoryStore{
+		failures:    make(map[string]*bruteForceFailures),
+		expiresIn:   expiresIn,
+		lastCleanup: time.Now(),
+		timeNow:     time.Now,
+	}
+}
+
+// Failures implements BruteForceStore.Failures
+func (s *BruteForceMemoryStore) Failures(key string) (int, time.Time, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	f, ok := s.failures[key]
+	if !ok || s.timeNow().Sub(f.lastFailure) > s.expiresIn {
+		return 0, time.Time{}, nil
+	}
+	return f.count, f.lastFailure, nil
+}
+
+// Reserve implements BruteForceStore.Reserve
+func (s *BruteForceMemoryStore) Reserve(key string, now time.Time, delay func(failures int) time.Duration) (time.Duration, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.timeNow().Sub(s.lastCleanup) > s.expiresIn {
+		s.cleanup()
+	}
+	f, ok := s.failures[key]
+	if !ok || now.Sub(f.lastFailure) > s.expiresIn {
+		f = &bruteForceFailures{}
+		s.failures[key] = f
+	}
+	if f.count > 0 {
+		if wait := f.lastFailure.Add(delay(f.count)).Sub(now); wait > 0 {
+			return wait, nil
+		}
+	}
+	f.count++
+	f.lastFailure = now
+	return 0, nil
+}
+
+// Release implements BruteForceStore.Release. Time of the last failure is kept, so delay of the key is counted from
+// the released attempt.
+func (s *BruteForceMemoryStore) Release(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	f, ok := s.failures[key]
+	if !ok {
+		return nil
+	}
+	f.count--
+	if f.count <= 0 {
+		delete(s.failures, key)
+	}
+	return nil
+}
+
+// Reset implements BruteForceStore.Reset
+func (s *BruteForceMemoryStore) Reset(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.failures, key)
+	return nil
+}
+
+// cleanup removes expired keys. Must be called with the mutex locked.
+func (s *BruteForceMemoryStore) cleanup() {
+	now := s.timeNow()
+	for key, f := range s.failures {
+		if now.Sub(f.lastFailure) > s.expiresIn {
+			delete(s.failures, key)
+		}
+	}
+	s.lastCleanup = now
+}
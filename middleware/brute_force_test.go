@@ -0,0 +1,221 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBruteForceTestEcho(config BruteForceConfig) *echo.Echo {
+	e := echo.New()
+	e.POST("/login", func(c echo.Context) error {
+		if c.FormValue("password") != "secret" {
+			return echo.ErrUnauthorized
+		}
+		return c.String(http.StatusOK, "welcome")
+	}, BruteForceWithConfig(config))
+	return e
+}
+
+func bruteForceLogin(e *echo.Echo, username, password string) *httptest.ResponseRecorder {
+	form := url.Values{"username": {username}, "password": {password}}
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestBruteForce(t *testing.T) {
+	e := newBruteForceTestEcho(BruteForceConfig{
+		Store:     NewBruteForceMemoryStore(0),
+		Threshold: 2,
+		BaseDelay: time.Hour,
+		MaxDelay:  2 * time.Hour,
+	})
+
+	assert.Equal(t, http.StatusUnauthorized, bruteForceLogin(e, "alice", "x").Code)
+	assert.Equal(t, http.StatusUnauthorized, bruteForceLogin(e, "alice", "x").Code)
+	assert.Equal(t, http.StatusUnauthorized, bruteForceLogin(e, "alice", "x").Code) // third failure is over threshold
+
+	rec := bruteForceLogin(e, "alice", "secret")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "3600", rec.Header().Get(echo.HeaderRetryAfter))
+
+	// other username is tracked separately
+	assert.Equal(t, http.StatusOK, bruteForceLogin(e, "bob", "secret").Code)
+}
+
+func TestBruteForce_successResetsFailures(t *testing.T) {
+	store := NewBruteForceMemoryStore(0)
+	e := newBruteForceTestEcho(BruteForceConfig{Store: store, Threshold: 2, BaseDelay: time.Hour})
+
+	assert.Equal(t, http.StatusUnauthorized, bruteForceLogin(e, "alice", "x").Code)
+	assert.Equal(t, http.StatusUnauthorized, bruteForceLogin(e, "alice", "x").Code)
+	assert.Equal(t, http.StatusOK, bruteForceLogin(e, "alice", "secret").Code)
+
+	count, _, err := store.Failures("192.0.2.1|alice")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestBruteForce_reportAuthResult(t *testing.T) {
+	store := NewBruteForceMemoryStore(0)
+	e := echo.New()
+	e.POST("/login", func(c echo.Context) error {
+		ReportAuthResult(c, false)
+		return c.JSON(http.StatusOK, map[string]bool{"ok": false}) // status does not reveal the failure
+	}, BruteForce(store))
+
+	bruteForceLogin(e, "alice", "x")
+
+	count, _, err := store.Failures("192.0.2.1|alice")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestBruteForceDelay(t *testing.T) {
+	config := BruteForceConfig{Threshold: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+
+	assert.Equal(t, time.Duration(0), bruteForceDelay(3, config))
+	assert.Equal(t, time.Second, bruteForceDelay(4, config))
+	assert.Equal(t, 2*time.Second, bruteForceDelay(5, config))
+	assert.Equal(t, 8*time.Second, bruteForceDelay(7, config))
+	assert.Equal(t, 10*time.Second, bruteForceDelay(8, config))
+	assert.Equal(t, 10*time.Second, bruteForceDelay(1000, config))
+}
+
+func TestBruteForce_concurrentAttempts(t *testing.T) {
+	const attempts = 20
+	entered := make(chan struct{}, attempts)
+	release := make(chan struct{})
+	e := echo.New()
+	e.POST("/login", func(c echo.Context) error {
+		entered <- struct{}{}
+		<-release
+		return echo.ErrUnauthorized
+	}, BruteForceWithConfig(BruteForceConfig{
+		Store:     NewBruteForceMemoryStore(0),
+		Threshold: 2,
+		BaseDelay: time.Hour,
+	}))
+
+	var wg sync.WaitGroup
+	codes := make(chan int, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- bruteForceLogin(e, "alice", "x").Code
+		}()
+	}
+	// attempts that are not waiting in the handler are rejected by now
+	for i := 0; i < attempts-3; i++ {
+		assert.Equal(t, http.StatusTooManyRequests, <-codes)
+	}
+	close(release)
+	wg.Wait()
+	close(codes)
+
+	assert.Len(t, entered, 3) // threshold and the first attempt over it
+	for code := range codes {
+		assert.Equal(t, http.StatusUnauthorized, code)
+	}
+}
+
+func TestBruteForce_unknownOutcomeIsReleased(t *testing.T) {
+	store := NewBruteForceMemoryStore(0)
+	e := echo.New()
+	e.POST("/login", func(c echo.Context) error {
+		return echo.ErrBadRequest
+	}, BruteForce(store))
+
+	assert.Equal(t, http.StatusBadRequest, bruteForceLogin(e, "alice", "x").Code)
+
+	count, _, err := store.Failures("192.0.2.1|alice")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestBruteForce_keyIgnoresForwardedHeaders(t *testing.T) {
+	e := newBruteForceTestEcho(BruteForceConfig{
+		Store:     NewBruteForceMemoryStore(0),
+		Threshold: 1,
+		BaseDelay: time.Hour,
+	})
+
+	codes := make([]int, 0, 3)
+	for i := 0; i < 3; i++ {
+		form := url.Values{"username": {"alice"}, "password": {"x"}}
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		req.Header.Set(echo.HeaderXForwardedFor, "10.0.0."+strconv.Itoa(i))
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		codes = append(codes, rec.Code)
+	}
+
+	assert.Equal(t, []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}, codes)
+}
+
+func TestBruteForceMemoryStore_expires(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewBruteForceMemoryStore(time.Minute)
+	store.timeNow = func() time.Time { return now }
+	store.lastCleanup = now
+	noDelay := func(int) time.Duration { return 0 }
+
+	wait, err := store.Reserve("a", now, noDelay)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), wait)
+	_, _ = store.Reserve("a", now, noDelay)
+	count, _, _ := store.Failures("a")
+	assert.Equal(t, 2, count)
+
+	now = now.Add(2 * time.Minute)
+	count, _, _ = store.Failures("a")
+	assert.Equal(t, 0, count)
+
+	_, _ = store.Reserve("b", now, noDelay) // triggers cleanup of expired keys
+	assert.Len(t, store.failures, 1)
+}
+
+func TestBruteForceMemoryStore_Reserve(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewBruteForceMemoryStore(time.Hour)
+	store.timeNow = func() time.Time { return now }
+	store.lastCleanup = now
+	delay := func(failures int) time.Duration { return time.Duration(failures) * time.Minute }
+
+	wait, err := store.Reserve("a", now, delay)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), wait)
+
+	wait, err = store.Reserve("a", now.Add(30*time.Second), delay)
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, wait)
+	count, _, _ := store.Failures("a")
+	assert.Equal(t, 1, count) // attempt that has to wait is not counted
+
+	assert.NoError(t, store.Release("a"))
+	count, _, _ = store.Failures("a")
+	assert.Equal(t, 0, count)
+	assert.NoError(t, store.Release("a"))
+}
+
+func TestBruteForce_panicsWithoutStore(t *testing.T) {
+	assert.Panics(t, func() {
+		BruteForce(nil)
+	})
+}
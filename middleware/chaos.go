@@ -0,0 +1,316 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ChaosConfig defines the config for Chaos middleware.
+type ChaosConfig struct {
+	// Skipper defines a function to skip middleware.
+	Skipper Skipper
+
+	// Enabled must be set to true for the middleware to inject any faults.
+	// Required.
+	Enabled bool
+
+	// Guard is called once when the middleware is created and must return true for the middleware to be activated.
+	// Use it to check that the application is not running in production (ala `os.Getenv("ENV") != "production"`).
+	// Middleware with nil Guard or Guard returning false passes all requests through unchanged.
+	// Required.
+	Guard func() bool
+
+	// Rules are the fault injection rules. The first rule matching the request is applied. Rules can be replaced
+	// while the server is running with `Chaos.SetRules`.
+	// Optional.
+	Rules []ChaosRule
+
+	// BypassHeader is the request header that skips fault injection when its value equals BypassSecret.
+	// Optional. Default value "X-Chaos-Bypass".
+	BypassHeader string
+
+	// BypassSecret is the shared secret of BypassHeader.
+	// Optional. Default value "" disables bypassing.
+	BypassSecret string
+}
+
+// ChaosRule defines which requests faults are injected into and what faults are injected. Faults are applied in order:
+// latency, dropped connection, aborted request, truncated response.
+type ChaosRule struct {
+	// Routes are route paths (ala `/users/:id`, as returned by `c.Path()`) the rule applies to.
+	// Optional. Default value (empty) matches all routes.
+	Routes []string
+
+	// Header is name of the request header that must be present for the rule to apply.
+	// Optional.
+	Header string
+
+	// HeaderValue is the value Header must have for the rule to apply.
+	// Optional. Default value "" means that any value is accepted.
+	HeaderValue string
+
+	// Percentage is percentage (0-100) of matching requests that faults are injected into.
+	// Required. Default value 0 never injects faults.
+	Percentage float64
+
+	// Latency is delay added before the request is handled.
+	// Optional.
+	Latency time.Duration
+
+	// Jitter is maximum random delay added to Latency.
+	// Optional.
+	Jitter time.Duration
+
+	// AbortStatus is status code the request is aborted with without calling the handler.
+	// Optional. Default value 0 does not abort.
+	AbortStatus int
+
+	// TruncateAfter is number of response body bytes sent before the connection is closed.
+	// Optional. Default value 0 does not truncate.
+	TruncateAfter int
+
+	// Drop closes the connection without sending any response.
+	// Optional.
+	Drop bool
+}
+
+// Chaos injects faults into requests for resilience testing.
+type Chaos struct {
+	config ChaosConfig
+	active bool
+	rules  atomic.Pointer[[]ChaosRule]
+}
+
+// ErrChaosFault is internal error of errors returned for requests aborted by Chaos middleware.
+var ErrChaosFault = errors.New("chaos fault injected")
+
+// DefaultChaosConfig is the default Chaos middleware config.
+var DefaultChaosConfig = ChaosConfig{
+	Skipper:      DefaultSkipper,
+	BypassHeader: "X-Chaos-Bypass",
+}
+
+// ChaosWithConfig returns a Chaos middleware with config.
+// See: `NewChaos()`.
+func ChaosWithConfig(config ChaosConfig) echo.MiddlewareFunc {
+	return NewChaos(config).Middleware()
+}
+
+// NewChaos creates Chaos with config. Use it instead of `ChaosWithConfig` when rules need to be updated while the
+// server is running. Panics when rules are invalid.
+//
+// Example:
+//
+//	chaos := middleware.NewChaos(middleware.ChaosConfig{
+//		Enabled: true,
+//		Guard: func() bool {
+//			return os.Getenv("ENV") == "staging"
+//		},
+//		BypassSecret: os.Getenv("CHAOS_BYPASS_SECRET"),
+//		Rules: []middleware.ChaosRule{
+//			{Routes: []string{"/orders"}, Percentage: 10, AbortStatus: http.StatusServiceUnavailable},
+//		},
+//	})
+//	e.Use(chaos.Middleware())
+func NewChaos(config ChaosConfig) *Chaos {
+	if config.Skipper == nil {
+		config.Skipper = DefaultChaosConfig.Skipper
+	}
+	if config.BypassHeader == "" {
+		config.BypassHeader = DefaultChaosConfig.BypassHeader
+	}
+	ch := &Chaos{
+		config: config,
+		active: config.Enabled && config.Guard != nil && config.Guard(),
+	}
+	if err := ch.SetRules(config.Rules); err != nil {
+		panic(err)
+	}
+	return ch
+}
+
+// Active returns true when Enabled is set and Guard allowed the middleware to inject faults.
+func (ch *Chaos) Active() bool {
+	return ch.active
+}
+
+// Rules returns rules currently in use.
+func (ch *Chaos) Rules() []ChaosRule {
+	return *ch.rules.Load()
+}
+
+// SetRules replaces rules of the middleware. Requests already being handled keep using the previous rules. Returns
+// error and keeps the previous rules when any of the rules is invalid.
+func (ch *Chaos) SetRules(rules []ChaosRule) error {
+	for i, r := range rules {
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("echo: chaos middleware rule %d is invalid: %w", i, err)
+		}
+	}
+	rules = append([]ChaosRule(nil), rules...)
+	ch.rules.Store(&rules)
+	return nil
+}
+
+func (r ChaosRule) validate() error {
+	switch {
+	case r.Percentage < 0 || r.Percentage > 100:
+		return errors.New("percentage must be between 0 and 100")
+	case r.Latency < 0 || r.Jitter < 0:
+		return errors.New("latency and jitter can not be negative")
+	case r.AbortStatus != 0 && (r.AbortStatus < 100 || r.AbortStatus > 599):
+		return errors.New("abort status must be valid HTTP status code")
+	case r.TruncateAfter < 0:
+		return errors.New("truncate after can not be negative")
+	}
+	return nil
+}
+
+func (r ChaosRule) matches(c echo.Context) bool {
+	if len(r.Routes) > 0 {
+		path := c.Path()
+		found := false
+		for _, route := range r.Routes {
+			if route == path {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if r.Header != "" {
+		values, ok := c.Request().Header[http.CanonicalHeaderKey(r.Header)]
+		if !ok || (r.HeaderValue != "" && values[0] != r.HeaderValue) {
+			return false
+		}
+	}
+	return r.Percentage > 0 && rand.Float64()*100 < r.Percentage
+}
+
+// Middleware returns middleware function of the Chaos. Middleware of inactive Chaos calls next handler directly.
+func (ch *Chaos) Middleware() echo.MiddlewareFunc {
+	config := ch.config
+	if !ch.active {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return next
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+			if config.BypassSecret != "" {
+				bypass := c.Request().Header.Get(config.BypassHeader)
+				if subtle.ConstantTimeCompare([]byte(bypass), []byte(config.BypassSecret)) == 1 {
+					return next(c)
+				}
+			}
+
+			var rule *ChaosRule
+			rules := *ch.rules.Load()
+			for i := range rules {
+				if rules[i].matches(c) {
+					rule = &rules[i]
+					break
+				}
+			}
+			if rule == nil {
+				return next(c)
+			}
+			return injectFaults(c, next, rule)
+		}
+	}
+}
+
+func injectFaults(c echo.Context, next echo.HandlerFunc, rule *ChaosRule) error {
+	delay := rule.Latency
+	if rule.Jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(rule.Jitter) + 1))
+	}
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-c.Request().Context().Done():
+			// client is gone, there is nobody to respond to
+			timer.Stop()
+			return nil
+		}
+	}
+
+	if rule.Drop {
+		closeConnection(c.Response().Writer)
+		return nil
+	}
+	if rule.AbortStatus != 0 {
+		return echo.NewHTTPError(rule.AbortStatus).SetInternal(ErrChaosFault)
+	}
+	if rule.TruncateAfter == 0 {
+		return next(c)
+	}
+
+	res := c.Response()
+	writer := res.Writer
+	res.Writer = &truncateResponseWriter{ResponseWriter: writer, remaining: rule.TruncateAfter}
+	defer func() {
+		res.Writer = writer
+	}()
+	// error response is truncated too, so it is written before the original writer is restored
+	err := next(c)
+	if err != nil {
+		c.Error(err)
+	}
+	_ = http.NewResponseController(writer).Flush()
+	closeConnection(writer)
+	return err
+}
+
+// closeConnection closes the connection of the request. When the connection can not be hijacked (ala HTTP/2) the
+// handler is aborted with `http.ErrAbortHandler` panic, which makes the server reset the stream.
+func closeConnection(w http.ResponseWriter) {
+	conn, _, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	_ = conn.Close()
+}
+
+// truncateResponseWriter discards response body after given number of bytes.
+type truncateResponseWriter struct {
+	http.ResponseWriter
+	remaining int
+}
+
+func (w *truncateResponseWriter) Write(b []byte) (int, error) {
+	if w.remaining <= 0 {
+		return len(b), nil
+	}
+	n := len(b)
+	if n > w.remaining {
+		n = w.remaining
+	}
+	written, err := w.ResponseWriter.Write(b[:n])
+	w.remaining -= written
+	if err != nil {
+		return written, err
+	}
+	return len(b), nil
+}
+
+func (w *truncateResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,263 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChaos(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		givenConfig  ChaosConfig
+		whenURL      string
+		whenHeader   http.Header
+		expectStatus int
+		expectBody   string
+		expectError  bool
+	}{
+		{
+			name: "ok, not enabled",
+			givenConfig: ChaosConfig{
+				Guard: func() bool { return true },
+				Rules: []ChaosRule{{Percentage: 100, AbortStatus: http.StatusServiceUnavailable}},
+			},
+			whenURL:      "/users/1",
+			expectStatus: http.StatusOK,
+			expectBody:   "hello world",
+		},
+		{
+			name: "ok, guard does not pass",
+			givenConfig: ChaosConfig{
+				Enabled: true,
+				Guard:   func() bool { return false },
+				Rules:   []ChaosRule{{Percentage: 100, AbortStatus: http.StatusServiceUnavailable}},
+			},
+			whenURL:      "/users/1",
+			expectStatus: http.StatusOK,
+			expectBody:   "hello world",
+		},
+		{
+			name: "ok, abort",
+			givenConfig: ChaosConfig{
+				Rules: []ChaosRule{{Routes: []string{"/users/:id"}, Percentage: 100, AbortStatus: http.StatusServiceUnavailable}},
+			},
+			whenURL:      "/users/1",
+			expectStatus: http.StatusServiceUnavailable,
+			expectBody:   `{"message":"Service Unavailable"}` + "\n",
+		},
+		{
+			name: "ok, route does not match",
+			givenConfig: ChaosConfig{
+				Rules: []ChaosRule{{Routes: []string{"/orders"}, Percentage: 100, AbortStatus: http.StatusServiceUnavailable}},
+			},
+			whenURL:      "/users/1",
+			expectStatus: http.StatusOK,
+			expectBody:   "hello world",
+		},
+		{
+			name: "ok, header matches",
+			givenConfig: ChaosConfig{
+				Rules: []ChaosRule{{Header: "X-Fault", HeaderValue: "abort", Percentage: 100, AbortStatus: http.StatusTeapot}},
+			},
+			whenURL:      "/users/1",
+			whenHeader:   http.Header{"X-Fault": []string{"abort"}},
+			expectStatus: http.StatusTeapot,
+			expectBody:   `{"message":"I'm a teapot"}` + "\n",
+		},
+		{
+			name: "ok, header does not match",
+			givenConfig: ChaosConfig{
+				Rules: []ChaosRule{{Header: "X-Fault", HeaderValue: "abort", Percentage: 100, AbortStatus: http.StatusTeapot}},
+			},
+			whenURL:      "/users/1",
+			whenHeader:   http.Header{"X-Fault": []string{"other"}},
+			expectStatus: http.StatusOK,
+			expectBody:   "hello world",
+		},
+		{
+			name: "ok, zero percentage",
+			givenConfig: ChaosConfig{
+				Rules: []ChaosRule{{AbortStatus: http.StatusServiceUnavailable}},
+			},
+			whenURL:      "/users/1",
+			expectStatus: http.StatusOK,
+			expectBody:   "hello world",
+		},
+		{
+			name: "ok, bypass with secret",
+			givenConfig: ChaosConfig{
+				BypassSecret: "s3cr3t",
+				Rules:        []ChaosRule{{Percentage: 100, AbortStatus: http.StatusServiceUnavailable}},
+			},
+			whenURL:      "/users/1",
+			whenHeader:   http.Header{"X-Chaos-Bypass": []string{"s3cr3t"}},
+			expectStatus: http.StatusOK,
+			expectBody:   "hello world",
+		},
+		{
+			name: "ok, bypass with wrong secret",
+			givenConfig: ChaosConfig{
+				BypassSecret: "s3cr3t",
+				Rules:        []ChaosRule{{Percentage: 100, AbortStatus: http.StatusServiceUnavailable}},
+			},
+			whenURL:      "/users/1",
+			whenHeader:   http.Header{"X-Chaos-Bypass": []string{"guess"}},
+			expectStatus: http.StatusServiceUnavailable,
+			expectBody:   `{"message":"Service Unavailable"}` + "\n",
+		},
+		{
+			name: "ok, truncate",
+			givenConfig: ChaosConfig{
+				Rules: []ChaosRule{{Percentage: 100, TruncateAfter: 5}},
+			},
+			whenURL:      "/users/1",
+			expectStatus: http.StatusOK,
+			expectBody:   "hello",
+			expectError:  true,
+		},
+		{
+			name: "ok, drop",
+			givenConfig: ChaosConfig{
+				Rules: []ChaosRule{{Percentage: 100, Drop: true}},
+			},
+			whenURL:     "/users/1",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := tc.givenConfig
+			if config.Guard == nil {
+				config.Enabled = true
+				config.Guard = func() bool { return true }
+			}
+			e := echo.New()
+			e.Use(ChaosWithConfig(config))
+			e.GET("/users/:id", func(c echo.Context) error {
+				c.Response().Header().Set(echo.HeaderContentLength, "11")
+				return c.String(http.StatusOK, "hello world")
+			})
+			server := httptest.NewServer(e)
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodGet, server.URL+tc.whenURL, nil)
+			assert.NoError(t, err)
+			for k, v := range tc.whenHeader {
+				req.Header[k] = v
+			}
+			res, err := http.DefaultClient.Do(req)
+			if tc.expectError && tc.expectStatus == 0 {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			defer res.Body.Close()
+			body, err := io.ReadAll(res.Body)
+			if tc.expectError {
+				assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.expectStatus, res.StatusCode)
+			assert.Equal(t, tc.expectBody, string(body))
+		})
+	}
+}
+
+func TestChaos_truncateReturnsHandlerError(t *testing.T) {
+	e := echo.New()
+	outerErr := make(chan error, 1)
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+			outerErr <- err
+			return err
+		}
+	})
+	e.Use(ChaosWithConfig(ChaosConfig{
+		Enabled: true,
+		Guard:   func() bool { return true },
+		Rules:   []ChaosRule{{Percentage: 100, TruncateAfter: 5}},
+	}))
+	e.GET("/", func(c echo.Context) error {
+		return echo.ErrForbidden
+	})
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	assert.Equal(t, `{"mes`, string(body))
+	assert.Equal(t, echo.ErrForbidden, <-outerErr)
+}
+
+func TestChaos_latency(t *testing.T) {
+	ch := NewChaos(ChaosConfig{
+		Enabled: true,
+		Guard:   func() bool { return true },
+		Rules:   []ChaosRule{{Percentage: 100, Latency: 20 * time.Millisecond, Jitter: 10 * time.Millisecond}},
+	})
+	h := ch.Middleware()(func(c echo.Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	start := time.Now()
+	err := h(c)
+
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Equal(t, "OK", rec.Body.String())
+}
+
+func TestChaos_SetRules(t *testing.T) {
+	ch := NewChaos(ChaosConfig{
+		Enabled: true,
+		Guard:   func() bool { return true },
+	})
+	assert.True(t, ch.Active())
+	h := ch.Middleware()(func(c echo.Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+	serve := func() error {
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", strings.NewReader("")), httptest.NewRecorder())
+		return h(c)
+	}
+
+	assert.NoError(t, serve())
+
+	rules := []ChaosRule{{Percentage: 100, AbortStatus: http.StatusBadGateway}}
+	assert.NoError(t, ch.SetRules(rules))
+	err := serve()
+	assert.ErrorIs(t, err, ErrChaosFault)
+	assert.Equal(t, http.StatusBadGateway, err.(*echo.HTTPError).Code)
+
+	err = ch.SetRules([]ChaosRule{{Percentage: 150}})
+	assert.EqualError(t, err, "echo: chaos middleware rule 0 is invalid: percentage must be between 0 and 100")
+	assert.Equal(t, rules, ch.Rules())
+}
+
+func TestNewChaos_invalidRulesPanics(t *testing.T) {
+	assert.PanicsWithError(t, "echo: chaos middleware rule 0 is invalid: abort status must be valid HTTP status code", func() {
+		NewChaos(ChaosConfig{Rules: []ChaosRule{{Percentage: 1, AbortStatus: 42}}})
+	})
+}
@@ -48,6 +48,10 @@ type gzipResponseWriter struct {
 	minLengthExceeded bool
 	buffer            *bytes.Buffer
 	code              int
+	// passthrough is set when handler has already encoded the response (ala pre-compressed file with
+	// `Content-Encoding` header) or has disabled compression, and response is written as is.
+	passthrough bool
+	response    *echo.Response
 }
 
 const (
@@ -104,12 +108,15 @@ func GzipWithConfig(config GzipConfig) echo.MiddlewareFunc {
 				buf := bpool.Get().(*bytes.Buffer)
 				buf.Reset()
 
-				grw := &gzipResponseWriter{Writer: w, ResponseWriter: rw, minLength: config.MinLength, buffer: buf}
+				grw := &gzipResponseWriter{Writer: w, ResponseWriter: rw, minLength: config.MinLength, buffer: buf, response: res}
 				defer func() {
 					// There are different reasons for cases when we have not yet written response to the client and now need to do so.
 					// a) handler response had only response code and no response body (ala 404 or redirects etc). Response code need to be written now.
 					// b) body is shorter than our minimum length threshold and being buffered currently and needs to be written
-					if !grw.wroteBody {
+					// Response already encoded by handler (passthrough) has been written as is.
+					if grw.passthrough {
+						res.Writer = rw
+					} else if !grw.wroteBody {
 						if res.Header().Get(echo.HeaderContentEncoding) == gzipScheme {
 							res.Header().Del(echo.HeaderContentEncoding)
 						}
@@ -141,7 +148,28 @@ func GzipWithConfig(config GzipConfig) echo.MiddlewareFunc {
 	}
 }
 
+// startPassthrough switches writer to write response as is when handler has set `Content-Encoding` header itself
+// or has set `Response#DisableCompression`.
+func (w *gzipResponseWriter) startPassthrough() bool {
+	if w.passthrough {
+		return true
+	}
+	if w.wroteHeader || w.wroteBody {
+		return false
+	}
+	if w.Header().Get(echo.HeaderContentEncoding) == "" && !w.response.DisableCompression {
+		return false
+	}
+	w.passthrough = true
+	w.Writer.(*gzip.Writer).Reset(io.Discard)
+	return true
+}
+
 func (w *gzipResponseWriter) WriteHeader(code int) {
+	if w.startPassthrough() {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
 	w.Header().Del(echo.HeaderContentLength) // Issue #444
 
 	w.wroteHeader = true
@@ -151,6 +179,9 @@ func (w *gzipResponseWriter) WriteHeader(code int) {
 }
 
 func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	if w.startPassthrough() {
+		return w.ResponseWriter.Write(b)
+	}
 	if w.Header().Get(echo.HeaderContentType) == "" {
 		w.Header().Set(echo.HeaderContentType, http.DetectContentType(b))
 	}
@@ -178,6 +209,10 @@ func (w *gzipResponseWriter) Write(b []byte) (int, error) {
 }
 
 func (w *gzipResponseWriter) Flush() {
+	if w.passthrough {
+		_ = http.NewResponseController(w.ResponseWriter).Flush()
+		return
+	}
 	if !w.minLengthExceeded {
 		// Enforce compression because we will not know how much more data will come
 		w.minLengthExceeded = true
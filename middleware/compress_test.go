@@ -365,3 +365,48 @@ func BenchmarkGzip(b *testing.B) {
 		h(c)
 	}
 }
+
+func TestGzip_passthroughEncodedResponse(t *testing.T) {
+	e := echo.New()
+	e.Use(Gzip())
+	e.GET("/", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderContentEncoding, "br")
+		c.Response().Header().Set(echo.HeaderContentLength, "6")
+		return c.Blob(http.StatusOK, "text/plain", []byte("brotli"))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip, br")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "brotli", rec.Body.String())
+	assert.Equal(t, "br", rec.Header().Get(echo.HeaderContentEncoding))
+	assert.Equal(t, "6", rec.Header().Get(echo.HeaderContentLength))
+}
+
+func TestGzip_disableCompression(t *testing.T) {
+	e := echo.New()
+	e.Use(Gzip())
+	e.GET("/", func(c echo.Context) error {
+		pw := c.MultipartStream("frame")
+		w, err := pw.NextPart(nil)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte("frame")); err != nil {
+			return err
+		}
+		return pw.Close()
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, gzipScheme)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "", rec.Header().Get(echo.HeaderContentEncoding))
+	assert.Equal(t, "--frame\r\n\r\nframe\r\n--frame--\r\n", rec.Body.String())
+}
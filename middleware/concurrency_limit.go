@@ -0,0 +1,261 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ConcurrencyLimitConfig defines the config for ConcurrencyLimit middleware.
+type ConcurrencyLimitConfig struct {
+	// Skipper defines a function to skip middleware.
+	Skipper Skipper
+
+	// MaxConcurrent is maximum number of requests (per key) that are allowed to be processed at the same time.
+	// Required.
+	MaxConcurrent int
+
+	// QueueSize is maximum number of requests (per key) that are allowed to wait for a free slot. Requests that arrive
+	// when the queue is full are rejected immediately.
+	// Optional. Default value 0 (no queueing, requests over MaxConcurrent are rejected immediately).
+	QueueSize int
+
+	// MaxWait is maximum duration a request is allowed to wait in the queue before it is rejected.
+	// Optional. Default value 0 (request waits until slot is freed or request context is cancelled).
+	MaxWait time.Duration
+
+	// KeyFunc resolves the key that requests are limited by. Each key has its own slots and queue. For example returning
+	// `c.Path()` would limit every route separately.
+	// Optional. Default value returns empty string for all requests (single global limit).
+	KeyFunc func(c echo.Context) string
+
+	// Limits overrides MaxConcurrent for specific keys returned by KeyFunc.
+	// Optional.
+	Limits map[string]int
+
+	// RetryAfter is value (in seconds) sent with `Retry-After` header when request is rejected.
+	// Optional. Default value 1 second.
+	RetryAfter time.Duration
+
+	// DenyHandler is called when request is rejected. Returned error is handled by the global error handler.
+	// Optional. Default value returns ErrConcurrencyLimitExceeded.
+	DenyHandler func(c echo.Context, key string, err error) error
+}
+
+// ConcurrencyLimitStats contains current state of ConcurrencyLimiter. Values are useful as autoscaling signals.
+type ConcurrencyLimitStats struct {
+	// InFlight is number of requests currently being processed.
+	InFlight int64 `json:"in_flight"`
+	// Queued is number of requests currently waiting for a free slot.
+	Queued int64 `json:"queued"`
+	// Rejected is total number of requests rejected since limiter was created.
+	Rejected uint64 `json:"rejected"`
+}
+
+// ConcurrencyLimiter limits number of concurrently processed requests.
+type ConcurrencyLimiter struct {
+	config ConcurrencyLimitConfig
+
+	mutex sync.Mutex
+	// buckets has buckets of keys that have requests in flight or waiting. Bucket is removed when its last request
+	// is done so the map does not grow with every key ever seen.
+	buckets map[string]*concurrencyBucket
+
+	inFlight int64
+	queued   int64
+	rejected uint64
+}
+
+type concurrencyBucket struct {
+	slots   chan struct{}
+	waiting int64
+	// refs is number of requests using the bucket. Guarded by ConcurrencyLimiter.mutex.
+	refs int
+}
+
+var (
+	// ErrConcurrencyLimitExceeded denotes an error raised when request is rejected by ConcurrencyLimit middleware.
+	ErrConcurrencyLimitExceeded = echo.NewHTTPError(http.StatusServiceUnavailable, "concurrency limit exceeded")
+
+	errConcurrencyQueueFull    = errors.New("concurrency limit queue is full")
+	errConcurrencyWaitExceeded = errors.New("concurrency limit max wait exceeded")
+)
+
+// DefaultConcurrencyLimitConfig is the default ConcurrencyLimit middleware config.
+var DefaultConcurrencyLimitConfig = ConcurrencyLimitConfig{
+	Skipper:    DefaultSkipper,
+	RetryAfter: 1 * time.Second,
+	KeyFunc: func(c echo.Context) string {
+		return ""
+	},
+	DenyHandler: func(c echo.Context, key string, err error) error {
+		return ErrConcurrencyLimitExceeded.WithInternal(err)
+	},
+}
+
+// ConcurrencyLimit returns a middleware that limits number of concurrently processed requests to `max`. Requests over
+// the limit are rejected with "503 - Service Unavailable" response.
+func ConcurrencyLimit(max int) echo.MiddlewareFunc {
+	c := DefaultConcurrencyLimitConfig
+	c.MaxConcurrent = max
+	return ConcurrencyLimitWithConfig(c)
+}
+
+// ConcurrencyLimitWithConfig returns a ConcurrencyLimit middleware with config.
+// See: `ConcurrencyLimit()`.
+func ConcurrencyLimitWithConfig(config ConcurrencyLimitConfig) echo.MiddlewareFunc {
+	return NewConcurrencyLimiter(config).Middleware()
+}
+
+// NewConcurrencyLimiter creates ConcurrencyLimiter with config. Use it instead of `ConcurrencyLimitWithConfig` when
+// access to limiter statistics is needed.
+//
+// Example:
+//
+//	limiter := middleware.NewConcurrencyLimiter(middleware.ConcurrencyLimitConfig{
+//		MaxConcurrent: 100,
+//		QueueSize:     50,
+//		MaxWait:       2 * time.Second,
+//	})
+//	e.Use(limiter.Middleware())
+//	e.GET("/stats", func(c echo.Context) error { return c.JSON(http.StatusOK, limiter.Stats()) })
+func NewConcurrencyLimiter(config ConcurrencyLimitConfig) *ConcurrencyLimiter {
+	if config.Skipper == nil {
+		config.Skipper = DefaultConcurrencyLimitConfig.Skipper
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = DefaultConcurrencyLimitConfig.KeyFunc
+	}
+	if config.DenyHandler == nil {
+		config.DenyHandler = DefaultConcurrencyLimitConfig.DenyHandler
+	}
+	if config.RetryAfter == 0 {
+		config.RetryAfter = DefaultConcurrencyLimitConfig.RetryAfter
+	}
+	if config.MaxConcurrent <= 0 {
+		panic("echo: concurrency limit middleware requires MaxConcurrent to be greater than zero")
+	}
+	return &ConcurrencyLimiter{
+		config:  config,
+		buckets: map[string]*concurrencyBucket{},
+	}
+}
+
+// Stats returns current statistics of the limiter.
+func (l *ConcurrencyLimiter) Stats() ConcurrencyLimitStats {
+	return ConcurrencyLimitStats{
+		InFlight: atomic.LoadInt64(&l.inFlight),
+		Queued:   atomic.LoadInt64(&l.queued),
+		Rejected: atomic.LoadUint64(&l.rejected),
+	}
+}
+
+// Middleware returns middleware function of the limiter.
+func (l *ConcurrencyLimiter) Middleware() echo.MiddlewareFunc {
+	config := l.config
+	retryAfter := strconv.FormatInt(int64(config.RetryAfter/time.Second), 10)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			key := config.KeyFunc(c)
+			bucket := l.bucket(key)
+			defer l.putBucket(key, bucket)
+			if err := l.acquire(c, bucket); err != nil {
+				atomic.AddUint64(&l.rejected, 1)
+				c.Response().Header().Set(echo.HeaderRetryAfter, retryAfter)
+				return config.DenyHandler(c, key, err)
+			}
+			defer l.release(bucket)
+
+			// slot must be held until response is fully written, so error response is written while the slot is
+			// held. Error is still returned for outer middlewares (ala logger), handling it again is no-op.
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+			return err
+		}
+	}
+}
+
+// bucket returns bucket of the key and marks it used by the request. Request must return the bucket with putBucket.
+func (l *ConcurrencyLimiter) bucket(key string) *concurrencyBucket {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		limit := l.config.MaxConcurrent
+		if keyLimit, ok := l.config.Limits[key]; ok && keyLimit > 0 {
+			limit = keyLimit
+		}
+		b = &concurrencyBucket{slots: make(chan struct{}, limit)}
+		l.buckets[key] = b
+	}
+	b.refs++
+	return b
+}
+
+// putBucket marks bucket no longer used by the request and removes the bucket when no request uses it.
+func (l *ConcurrencyLimiter) putBucket(key string, b *concurrencyBucket) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	b.refs--
+	if b.refs == 0 {
+		delete(l.buckets, key)
+	}
+}
+
+func (l *ConcurrencyLimiter) acquire(c echo.Context, b *concurrencyBucket) error {
+	select {
+	case b.slots <- struct{}{}:
+		atomic.AddInt64(&l.inFlight, 1)
+		return nil
+	default:
+	}
+
+	if atomic.AddInt64(&b.waiting, 1) > int64(l.config.QueueSize) {
+		atomic.AddInt64(&b.waiting, -1)
+		return errConcurrencyQueueFull
+	}
+	atomic.AddInt64(&l.queued, 1)
+	defer func() {
+		atomic.AddInt64(&b.waiting, -1)
+		atomic.AddInt64(&l.queued, -1)
+	}()
+
+	var timeout <-chan time.Time
+	if l.config.MaxWait > 0 {
+		timer := time.NewTimer(l.config.MaxWait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case b.slots <- struct{}{}:
+		atomic.AddInt64(&l.inFlight, 1)
+		return nil
+	case <-timeout:
+		return errConcurrencyWaitExceeded
+	case <-c.Request().Context().Done():
+		return c.Request().Context().Err()
+	}
+}
+
+func (l *ConcurrencyLimiter) release(b *concurrencyBucket) {
+	atomic.AddInt64(&l.inFlight, -1)
+	<-b.slots
+}
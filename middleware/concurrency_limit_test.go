@@ -0,0 +1,235 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrencyLimit_rejectsOverLimit(t *testing.T) {
+	e := echo.New()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	limiter := NewConcurrencyLimiter(ConcurrencyLimitConfig{MaxConcurrent: 1})
+	e.Use(limiter.Middleware())
+	e.GET("/", func(c echo.Context) error {
+		close(started)
+		<-release
+		return c.String(http.StatusOK, "OK")
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	firstRec := httptest.NewRecorder()
+	go func() {
+		defer wg.Done()
+		e.ServeHTTP(firstRec, httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+	<-started
+
+	assert.Equal(t, ConcurrencyLimitStats{InFlight: 1}, limiter.Stats())
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get(echo.HeaderRetryAfter))
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, http.StatusOK, firstRec.Code)
+	assert.Equal(t, ConcurrencyLimitStats{Rejected: 1}, limiter.Stats())
+}
+
+func TestConcurrencyLimit_queue(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		whenMaxWait  time.Duration
+		releaseAfter time.Duration
+		expectCode   int
+	}{
+		{
+			name:         "ok, waits in queue for free slot",
+			whenMaxWait:  time.Second,
+			releaseAfter: 10 * time.Millisecond,
+			expectCode:   http.StatusOK,
+		},
+		{
+			name:         "nok, max wait exceeded",
+			whenMaxWait:  10 * time.Millisecond,
+			releaseAfter: 100 * time.Millisecond,
+			expectCode:   http.StatusServiceUnavailable,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			started := make(chan struct{}, 2)
+			release := make(chan struct{})
+			limiter := NewConcurrencyLimiter(ConcurrencyLimitConfig{
+				MaxConcurrent: 1,
+				QueueSize:     1,
+				MaxWait:       tc.whenMaxWait,
+				RetryAfter:    5 * time.Second,
+			})
+			e.Use(limiter.Middleware())
+			e.GET("/", func(c echo.Context) error {
+				started <- struct{}{}
+				if c.QueryParam("block") != "" {
+					<-release
+				}
+				return c.String(http.StatusOK, "OK")
+			})
+
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?block=1", nil))
+			}()
+			<-started
+
+			go func() {
+				time.Sleep(tc.releaseAfter)
+				close(release)
+			}()
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			wg.Wait()
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			if tc.expectCode == http.StatusServiceUnavailable {
+				assert.Equal(t, "5", rec.Header().Get(echo.HeaderRetryAfter))
+			}
+			assert.Equal(t, int64(0), limiter.Stats().Queued)
+		})
+	}
+}
+
+func TestConcurrencyLimit_perKeyLimits(t *testing.T) {
+	e := echo.New()
+
+	limiter := NewConcurrencyLimiter(ConcurrencyLimitConfig{
+		MaxConcurrent: 1,
+		KeyFunc: func(c echo.Context) string {
+			return c.Path()
+		},
+		Limits: map[string]int{"/wide": 2},
+	})
+	e.Use(limiter.Middleware())
+
+	started := make(chan struct{}, 3)
+	release := make(chan struct{})
+	handler := func(c echo.Context) error {
+		started <- struct{}{}
+		<-release
+		return c.String(http.StatusOK, "OK")
+	}
+	e.GET("/narrow", handler)
+	e.GET("/wide", handler)
+
+	var wg sync.WaitGroup
+	for _, path := range []string{"/narrow", "/wide", "/wide"} {
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		}(path)
+	}
+	for i := 0; i < 3; i++ {
+		<-started
+	}
+	assert.Equal(t, int64(3), limiter.Stats().InFlight)
+	assert.Len(t, limiter.buckets, 2)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wide", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	close(release)
+	wg.Wait()
+	assert.Len(t, limiter.buckets, 0)
+}
+
+func TestConcurrencyLimit_bucketsAreRemovedWhenIdle(t *testing.T) {
+	e := echo.New()
+
+	limiter := NewConcurrencyLimiter(ConcurrencyLimitConfig{
+		MaxConcurrent: 1,
+		QueueSize:     10,
+		KeyFunc: func(c echo.Context) string {
+			return c.QueryParam("client")
+		},
+	})
+	e.Use(limiter.Middleware())
+	e.GET("/", func(c echo.Context) error {
+		time.Sleep(time.Millisecond)
+		return c.String(http.StatusOK, "OK")
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// requests of the same client queue behind each other in the same bucket
+			url := "/?client=" + strconv.Itoa(i%5)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Len(t, limiter.buckets, 0)
+	assert.Equal(t, ConcurrencyLimitStats{}, limiter.Stats())
+}
+
+func TestConcurrencyLimit_slotHeldUntilErrorIsWritten(t *testing.T) {
+	e := echo.New()
+
+	limiter := NewConcurrencyLimiter(ConcurrencyLimitConfig{MaxConcurrent: 1})
+	var inFlightOnError int64
+	e.HTTPErrorHandler = func(err error, c echo.Context) {
+		inFlightOnError = limiter.Stats().InFlight
+		e.DefaultHTTPErrorHandler(err, c)
+	}
+	var outerErr error
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			outerErr = next(c)
+			return outerErr
+		}
+	})
+	e.Use(limiter.Middleware())
+	errFail := errors.New("fail")
+	e.GET("/", func(c echo.Context) error {
+		return errFail
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, errFail, outerErr)
+	assert.Equal(t, int64(1), inFlightOnError)
+	assert.Equal(t, int64(0), limiter.Stats().InFlight)
+}
+
+func TestConcurrencyLimit_panicsOnInvalidConfig(t *testing.T) {
+	assert.Panics(t, func() {
+		ConcurrencyLimitWithConfig(ConcurrencyLimitConfig{})
+	})
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// When returns middleware that executes mw only for requests for which predicate returns true. Other requests are
+// passed to the next handler directly. The wrapped middleware is created (and its config validated) by the caller so
+// When does not change when invalid config is reported.
+//
+// Example:
+//
+//	e.Use(middleware.When(func(c echo.Context) bool {
+//		return c.Request().Method != http.MethodGet
+//	}, middleware.CSRF()))
+func When(predicate func(c echo.Context) bool, mw echo.MiddlewareFunc) echo.MiddlewareFunc {
+	if predicate == nil {
+		panic("echo: when middleware requires predicate")
+	}
+	if mw == nil {
+		panic("echo: when middleware requires middleware")
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		h := mw(next)
+		return func(c echo.Context) error {
+			if predicate(c) {
+				return h(c)
+			}
+			return next(c)
+		}
+	}
+}
+
+// Unless returns middleware that executes mw for all requests except those for which predicate returns true. It is
+// the same as Skipper field of middleware config.
+//
+// Example:
+//
+//	e.Use(middleware.Unless(func(c echo.Context) bool {
+//		return c.Request().URL.Path == "/health"
+//	}, middleware.Logger()))
+func Unless(predicate func(c echo.Context) bool, mw echo.MiddlewareFunc) echo.MiddlewareFunc {
+	if predicate == nil {
+		panic("echo: unless middleware requires predicate")
+	}
+	return When(func(c echo.Context) bool { return !predicate(c) }, mw)
+}
+
+// ForRoutes returns middleware that executes mw only for requests matched to routes with given names (`Route.Name`).
+// Route name is known only after routing so the returned middleware panics when added with `Echo#Pre`.
+//
+// Example:
+//
+//	e.POST("/users", createUser).Name = "createUser"
+//	e.Use(middleware.ForRoutes(middleware.BodyLimit("1M"), "createUser"))
+func ForRoutes(mw echo.MiddlewareFunc, routeNames ...string) echo.MiddlewareFunc {
+	if len(routeNames) == 0 {
+		panic("echo: for routes middleware requires route names")
+	}
+	names := make(map[string]struct{}, len(routeNames))
+	for _, name := range routeNames {
+		names[name] = struct{}{}
+	}
+	when := When(func(c echo.Context) bool {
+		_, ok := names[c.RouteName()]
+		return ok
+	}, mw)
+	return echo.RequireRoute(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return when(next)
+	})
+}
+
+// ForPathPrefix returns middleware that executes mw only for requests whose path starts with any of the prefixes.
+// Prefix matches whole path segments so `/api` matches `/api` and `/api/users` but not `/apis`. Request path is used
+// so the returned middleware can be added with `Echo#Pre`.
+//
+// Example:
+//
+//	e.Use(middleware.ForPathPrefix(middleware.KeyAuth(validator), "/api", "/admin"))
+func ForPathPrefix(mw echo.MiddlewareFunc, prefixes ...string) echo.MiddlewareFunc {
+	if len(prefixes) == 0 {
+		panic("echo: for path prefix middleware requires prefixes")
+	}
+	trimmed := make([]string, len(prefixes))
+	for i, prefix := range prefixes {
+		trimmed[i] = strings.TrimSuffix(prefix, "/")
+	}
+	return When(func(c echo.Context) bool {
+		path := c.Request().URL.Path
+		for _, prefix := range trimmed {
+			if strings.HasPrefix(path, prefix) && (len(path) == len(prefix) || path[len(prefix)] == '/') {
+				return true
+			}
+		}
+		return false
+	}, mw)
+}
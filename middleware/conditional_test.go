@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func markingMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		c.Response().Header().Set("X-Marked", "true")
+		return next(c)
+	}
+}
+
+func isGet(c echo.Context) bool {
+	return c.Request().Method == http.MethodGet
+}
+
+func TestConditionalMiddlewares(t *testing.T) {
+	var testCases = []struct {
+		name         string
+		givenMW      echo.MiddlewareFunc
+		whenMethod   string
+		whenURL      string
+		expectMarked bool
+	}{
+		{
+			name:         "When, predicate true",
+			givenMW:      When(isGet, markingMiddleware),
+			whenMethod:   http.MethodGet,
+			whenURL:      "/api/users",
+			expectMarked: true,
+		},
+		{
+			name:       "When, predicate false",
+			givenMW:    When(isGet, markingMiddleware),
+			whenMethod: http.MethodPost,
+			whenURL:    "/api/users",
+		},
+		{
+			name:       "Unless, predicate true",
+			givenMW:    Unless(isGet, markingMiddleware),
+			whenMethod: http.MethodGet,
+			whenURL:    "/api/users",
+		},
+		{
+			name:         "Unless, predicate false",
+			givenMW:      Unless(isGet, markingMiddleware),
+			whenMethod:   http.MethodPost,
+			whenURL:      "/api/users",
+			expectMarked: true,
+		},
+		{
+			name:         "ForRoutes, route matches",
+			givenMW:      ForRoutes(markingMiddleware, "other", "users"),
+			whenMethod:   http.MethodGet,
+			whenURL:      "/api/users",
+			expectMarked: true,
+		},
+		{
+			name:       "ForRoutes, route does not match",
+			givenMW:    ForRoutes(markingMiddleware, "users"),
+			whenMethod: http.MethodPost,
+			whenURL:    "/api/users",
+		},
+		{
+			name:       "ForRoutes, no route",
+			givenMW:    ForRoutes(markingMiddleware, "users"),
+			whenMethod: http.MethodGet,
+			whenURL:    "/nope",
+		},
+		{
+			name:         "ForPathPrefix, exact path",
+			givenMW:      ForPathPrefix(markingMiddleware, "/admin", "/api/"),
+			whenMethod:   http.MethodGet,
+			whenURL:      "/api",
+			expectMarked: true,
+		},
+		{
+			name:         "ForPathPrefix, sub path",
+			givenMW:      ForPathPrefix(markingMiddleware, "/api"),
+			whenMethod:   http.MethodPost,
+			whenURL:      "/api/users",
+			expectMarked: true,
+		},
+		{
+			name:       "ForPathPrefix, partial segment",
+			givenMW:    ForPathPrefix(markingMiddleware, "/api"),
+			whenMethod: http.MethodGet,
+			whenURL:    "/apis",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(tc.givenMW)
+			e.GET("/api/users", echo.NotFoundHandler).Name = "users"
+			e.POST("/api/users", echo.NotFoundHandler).Name = "createUser"
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(tc.whenMethod, tc.whenURL, nil))
+
+			assert.Equal(t, tc.expectMarked, rec.Header().Get("X-Marked") == "true")
+		})
+	}
+}
+
+func TestForRoutes_panicsWithPre(t *testing.T) {
+	e := echo.New()
+	assert.PanicsWithError(t, "echo: middleware middleware.ForRoutes requires matched route and can not be added with Echo#Pre", func() {
+		e.Pre(ForRoutes(markingMiddleware, "users"))
+	})
+
+	e.Use(ForRoutes(markingMiddleware, "users"))
+	e.GET("/", echo.NotFoundHandler).Name = "users"
+	assert.Equal(t, []echo.MiddlewareInfo{
+		{Name: "middleware.ForRoutes", Level: echo.MiddlewareLevelGlobal},
+	}, e.MiddlewareChain("users"))
+}
+
+func TestForPathPrefix_pre(t *testing.T) {
+	e := echo.New()
+	e.Pre(ForPathPrefix(markingMiddleware, "/api"))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/nope", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "true", rec.Header().Get("X-Marked"))
+}
+
+func TestConditionalMiddlewares_panics(t *testing.T) {
+	assert.PanicsWithValue(t, "echo: when middleware requires predicate", func() {
+		When(nil, markingMiddleware)
+	})
+	assert.PanicsWithValue(t, "echo: when middleware requires middleware", func() {
+		When(isGet, nil)
+	})
+	assert.PanicsWithValue(t, "echo: unless middleware requires predicate", func() {
+		Unless(nil, markingMiddleware)
+	})
+	assert.PanicsWithValue(t, "echo: for routes middleware requires route names", func() {
+		ForRoutes(markingMiddleware)
+	})
+	assert.PanicsWithValue(t, "echo: for path prefix middleware requires prefixes", func() {
+		ForPathPrefix(markingMiddleware)
+	})
+}
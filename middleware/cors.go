@@ -4,10 +4,13 @@
 package middleware
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/labstack/echo/v4"
 )
@@ -19,8 +22,10 @@ type CORSConfig struct {
 
 	// AllowOrigins determines the value of the Access-Control-Allow-Origin
 	// response header.  This header defines a list of origins that may access the
-	// resource.  The wildcard characters '*' and '?' are supported and are
-	// converted to regex fragments '.*' and '.' accordingly.
+	// resource.  Origins are matched with OriginMatcher so `https://*.example.com`
+	// subdomain and `https://example.com:*` port wildcards are supported. Other
+	// patterns with wildcard characters '*' and '?' are converted to regex
+	// fragments '.*' and '.' accordingly.
 	//
 	// Security: use extreme caution when handling the origin, and carefully
 	// validate any logic. Remember that attackers may register hostile domain names.
@@ -107,8 +112,19 @@ type CORSConfig struct {
 	//
 	// See also: https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Access-Control-Max-Age
 	MaxAge int `yaml:"max_age"`
+
+	// PanicOnInvalidConfig makes CORSWithConfig panic when `CORSConfig.Validate` reports problems in the config so
+	// broken policies are caught at startup. By default, problems are logged as warning with `Echo#Logger` when the
+	// first request is served.
+	//
+	// Optional. Default value false.
+	PanicOnInvalidConfig bool `yaml:"panic_on_invalid_config"`
 }
 
+// corsMaxAgeLimit is the biggest Access-Control-Max-Age value honored by browsers (Firefox caps at 24 hours,
+// Chromium at 2 hours).
+const corsMaxAgeLimit = 86400
+
 // DefaultCORSConfig is the default CORS middleware config.
 var DefaultCORSConfig = CORSConfig{
 	Skipper:      DefaultSkipper,
@@ -116,6 +132,112 @@ var DefaultCORSConfig = CORSConfig{
 	AllowMethods: []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPatch, http.MethodPost, http.MethodDelete},
 }
 
+// Validate performs static checks of the config and returns all found problems joined into single error:
+//   - wildcard origin combined with AllowCredentials (browsers reject such responses)
+//   - duplicate or malformed origins
+//   - AllowOrigins that are ignored because AllowOriginFunc is set
+//   - MaxAge bigger than 24 hours
+//   - methods that are not upper case
+//   - empty or malformed header names
+func (config CORSConfig) Validate() error {
+	var errs []error
+	origins := config.AllowOrigins
+	if len(origins) == 0 {
+		origins = DefaultCORSConfig.AllowOrigins
+	}
+	seen := make(map[string]struct{}, len(origins))
+	for _, origin := range origins {
+		key := strings.ToLower(origin)
+		if _, ok := seen[key]; ok {
+			errs = append(errs, fmt.Errorf("duplicate origin %q", origin))
+			continue
+		}
+		seen[key] = struct{}{}
+
+		if origin == "*" {
+			if config.AllowCredentials && !config.UnsafeWildcardOriginWithAllowCredentials && config.AllowOriginFunc == nil {
+				errs = append(errs, errors.New("wildcard origin can not be used with AllowCredentials"))
+			}
+			continue
+		}
+		if _, err := parseOriginPattern(origin); err == nil {
+			continue
+		}
+		if strings.ContainsAny(origin, "*?") {
+			continue // legacy wildcard pattern converted to regexp
+		}
+		errs = append(errs, fmt.Errorf("malformed origin %q, expected scheme://host[:port]", origin))
+	}
+	if config.AllowOriginFunc != nil && len(config.AllowOrigins) > 0 {
+		errs = append(errs, errors.New("AllowOrigins is ignored when AllowOriginFunc is set"))
+	}
+	if config.MaxAge > corsMaxAgeLimit {
+		errs = append(errs, fmt.Errorf("MaxAge %d is out of range, browsers cap it at %d seconds", config.MaxAge, corsMaxAgeLimit))
+	}
+	for _, m := range config.AllowMethods {
+		if m == "" || m != strings.ToUpper(m) {
+			errs = append(errs, fmt.Errorf("method %q is not upper case", m))
+		}
+	}
+	for _, headers := range [][]string{config.AllowHeaders, config.ExposeHeaders} {
+		for _, h := range headers {
+			if h == "*" {
+				continue
+			}
+			if h == "" || strings.ContainsAny(h, " \t\r\n,:;\"") {
+				errs = append(errs, fmt.Errorf("malformed header name %q", h))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CORSSimulate returns response headers the CORS middleware with given config would send for preflight request from
+// origin asking for method and headers. Use it in tests to lock in CORS policies:
+//
+//	h := middleware.CORSSimulate(cfg, "https://app.example.com", http.MethodPut, []string{"Authorization"})
+//	assert.Equal(t, "Authorization", h.Get(echo.HeaderAccessControlAllowHeaders))
+//
+// Origin that is not allowed results headers without `Access-Control-Allow-Origin`. Methods from the Router `Allow`
+// header are not known so AllowMethods (or its default) is used.
+func CORSSimulate(config CORSConfig, origin, method string, headers []string) http.Header {
+	config.PanicOnInvalidConfig = false
+	req, _ := http.NewRequest(http.MethodOptions, "/", nil)
+	if origin != "" {
+		req.Header.Set(echo.HeaderOrigin, origin)
+	}
+	if method != "" {
+		req.Header.Set(echo.HeaderAccessControlRequestMethod, method)
+	}
+	if len(headers) > 0 {
+		req.Header.Set(echo.HeaderAccessControlRequestHeaders, strings.Join(headers, ","))
+	}
+	rec := &corsSimulationWriter{header: http.Header{}}
+	c := echo.New().NewContext(req, rec)
+	h := newCORS(config, false)(func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+	if err := h(c); err != nil {
+		c.Error(err)
+	}
+	return rec.header
+}
+
+// corsSimulationWriter is response writer that only keeps headers of the simulated response.
+type corsSimulationWriter struct {
+	header http.Header
+}
+
+func (w *corsSimulationWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *corsSimulationWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *corsSimulationWriter) WriteHeader(int) {}
+
 // CORS returns a Cross-Origin Resource Sharing (CORS) middleware.
 // See also [MDN: Cross-Origin Resource Sharing (CORS)].
 //
@@ -134,6 +256,22 @@ func CORS() echo.MiddlewareFunc {
 // CORSWithConfig returns a CORS middleware with config.
 // See: [CORS].
 func CORSWithConfig(config CORSConfig) echo.MiddlewareFunc {
+	return newCORS(config, true)
+}
+
+func newCORS(config CORSConfig, validate bool) echo.MiddlewareFunc {
+	var configErr error
+	if validate {
+		if err := config.Validate(); err != nil {
+			if config.PanicOnInvalidConfig {
+				panic(fmt.Errorf("echo: invalid CORS config: %w", err))
+			}
+			configErr = err
+		}
+	}
+	// Echo logger is not known before the first request
+	var warnOnce sync.Once
+
 	// Defaults
 	if config.Skipper == nil {
 		config.Skipper = DefaultCORSConfig.Skipper
@@ -147,11 +285,16 @@ func CORSWithConfig(config CORSConfig) echo.MiddlewareFunc {
 		config.AllowMethods = DefaultCORSConfig.AllowMethods
 	}
 
+	originMatcher := &OriginMatcher{}
 	allowOriginPatterns := make([]*regexp.Regexp, 0, len(config.AllowOrigins))
 	for _, origin := range config.AllowOrigins {
 		if origin == "*" {
 			continue // "*" is handled differently and does not need regexp
 		}
+		if p, err := parseOriginPattern(origin); err == nil {
+			originMatcher.patterns = append(originMatcher.patterns, p)
+			continue
+		}
 		pattern := regexp.QuoteMeta(origin)
 		pattern = strings.ReplaceAll(pattern, "\\*", ".*")
 		pattern = strings.ReplaceAll(pattern, "\\?", ".")
@@ -179,6 +322,11 @@ func CORSWithConfig(config CORSConfig) echo.MiddlewareFunc {
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			if configErr != nil {
+				warnOnce.Do(func() {
+					c.Logger().Warnf("echo: invalid CORS config: %v", configErr)
+				})
+			}
 			if config.Skipper(c) {
 				return next(c)
 			}
@@ -236,10 +384,9 @@ func CORSWithConfig(config CORSConfig) echo.MiddlewareFunc {
 						allowOrigin = o
 						break
 					}
-					if matchSubdomain(origin, o) {
-						allowOrigin = origin
-						break
-					}
+				}
+				if allowOrigin == "" && originMatcher.Match(origin) {
+					allowOrigin = origin
 				}
 
 				checkPatterns := false
@@ -4,12 +4,15 @@
 package middleware
 
 import (
+	"bytes"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -223,6 +226,24 @@ func TestCORS(t *testing.T) {
 			whenHeaders:   map[string]string{echo.HeaderOrigin: "http://bbb.example.com"},
 			expectHeaders: map[string]string{echo.HeaderAccessControlAllowOrigin: "http://bbb.example.com"},
 		},
+		{
+			name: "ok, preflight request with `AllowOrigins` which allow all subdomains with any port",
+			givenMW: CORSWithConfig(CORSConfig{
+				AllowOrigins: []string{"http://*.example.com:*"},
+			}),
+			whenMethod:    http.MethodOptions,
+			whenHeaders:   map[string]string{echo.HeaderOrigin: "http://aaa.example.com:8080"},
+			expectHeaders: map[string]string{echo.HeaderAccessControlAllowOrigin: "http://aaa.example.com:8080"},
+		},
+		{
+			name: "nok, preflight request with `AllowOrigins` subdomain pattern and suffix confused origin",
+			givenMW: CORSWithConfig(CORSConfig{
+				AllowOrigins: []string{"http://*.example.com"},
+			}),
+			whenMethod:       http.MethodOptions,
+			whenHeaders:      map[string]string{echo.HeaderOrigin: "http://evilexample.com"},
+			notExpectHeaders: map[string]string{echo.HeaderAccessControlAllowOrigin: ""},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -683,3 +704,216 @@ func Test_allowOriginFunc(t *testing.T) {
 		}
 	}
 }
+
+func TestCORS_groupLevelPreflight(t *testing.T) {
+	e := echo.New()
+	public := e.Group("/public", CORS())
+	public.GET("/users", func(c echo.Context) error { return c.String(http.StatusOK, "public") })
+	public.POST("/users", func(c echo.Context) error { return c.String(http.StatusOK, "public") })
+
+	internal := e.Group("/internal", CORSWithConfig(CORSConfig{AllowOrigins: []string{"https://admin.example.com"}}))
+	internal.DELETE("/users/:id", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })
+
+	var testCases = []struct {
+		name               string
+		whenURL            string
+		whenOrigin         string
+		expectAllowOrigin  string
+		expectAllowMethods string
+	}{
+		{
+			name:               "ok, permissive group with default methods",
+			whenURL:            "/public/users",
+			whenOrigin:         "https://any.example.com",
+			expectAllowOrigin:  "*",
+			expectAllowMethods: "GET,HEAD,PUT,PATCH,POST,DELETE",
+		},
+		{
+			name:               "ok, strict group allows pinned origin and uses methods of the route path",
+			whenURL:            "/internal/users/1",
+			whenOrigin:         "https://admin.example.com",
+			expectAllowOrigin:  "https://admin.example.com",
+			expectAllowMethods: "OPTIONS, DELETE",
+		},
+		{
+			name:              "nok, strict group rejects other origins",
+			whenURL:           "/internal/users/1",
+			whenOrigin:        "https://any.example.com",
+			expectAllowOrigin: "",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, tc.whenURL, nil)
+			req.Header.Set(echo.HeaderOrigin, tc.whenOrigin)
+			req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodGet)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusNoContent, rec.Code)
+			assert.Equal(t, tc.expectAllowOrigin, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+			assert.Equal(t, tc.expectAllowMethods, rec.Header().Get(echo.HeaderAccessControlAllowMethods))
+		})
+	}
+}
+
+func TestCORSConfig_Validate(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenConfig CORSConfig
+		expectErr   string
+	}{
+		{
+			name:        "ok, default config",
+			givenConfig: DefaultCORSConfig,
+		},
+		{
+			name: "ok, valid config",
+			givenConfig: CORSConfig{
+				AllowOrigins:     []string{"https://example.com", "https://*.example.com", "http://localhost:*", "https://*.legacy-?.com"},
+				AllowMethods:     []string{http.MethodGet, http.MethodPost},
+				AllowHeaders:     []string{echo.HeaderAuthorization, echo.HeaderContentType},
+				ExposeHeaders:    []string{"X-Total-Count"},
+				AllowCredentials: true,
+				MaxAge:           3600,
+			},
+		},
+		{
+			name:        "ok, unsafe wildcard with credentials is explicit choice",
+			givenConfig: CORSConfig{AllowOrigins: []string{"*"}, AllowCredentials: true, UnsafeWildcardOriginWithAllowCredentials: true},
+		},
+		{
+			name:        "nok, wildcard with credentials",
+			givenConfig: CORSConfig{AllowOrigins: []string{"*"}, AllowCredentials: true},
+			expectErr:   "wildcard origin can not be used with AllowCredentials",
+		},
+		{
+			name:        "nok, duplicate origins",
+			givenConfig: CORSConfig{AllowOrigins: []string{"https://example.com", "https://EXAMPLE.com"}},
+			expectErr:   `duplicate origin "https://EXAMPLE.com"`,
+		},
+		{
+			name:        "nok, malformed origins",
+			givenConfig: CORSConfig{AllowOrigins: []string{"example.com", "https://example.com/", "https://example.com/path"}},
+			expectErr: `malformed origin "example.com", expected scheme://host[:port]` + "\n" +
+				`malformed origin "https://example.com/", expected scheme://host[:port]` + "\n" +
+				`malformed origin "https://example.com/path", expected scheme://host[:port]`,
+		},
+		{
+			name: "nok, origins ignored with AllowOriginFunc",
+			givenConfig: CORSConfig{
+				AllowOrigins:    []string{"https://example.com"},
+				AllowOriginFunc: func(origin string) (bool, error) { return true, nil },
+			},
+			expectErr: "AllowOrigins is ignored when AllowOriginFunc is set",
+		},
+		{
+			name:        "nok, MaxAge out of range",
+			givenConfig: CORSConfig{MaxAge: 86401},
+			expectErr:   "MaxAge 86401 is out of range, browsers cap it at 86400 seconds",
+		},
+		{
+			name:        "nok, methods not upper case",
+			givenConfig: CORSConfig{AllowMethods: []string{"GET", "post", ""}},
+			expectErr:   `method "post" is not upper case` + "\n" + `method "" is not upper case`,
+		},
+		{
+			name:        "nok, malformed header names",
+			givenConfig: CORSConfig{AllowHeaders: []string{"Authorization, Content-Type"}, ExposeHeaders: []string{""}},
+			expectErr:   `malformed header name "Authorization, Content-Type"` + "\n" + `malformed header name ""`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.givenConfig.Validate()
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCORSWithConfig_panicOnInvalidConfig(t *testing.T) {
+	assert.PanicsWithError(t, "echo: invalid CORS config: wildcard origin can not be used with AllowCredentials", func() {
+		CORSWithConfig(CORSConfig{AllowCredentials: true, PanicOnInvalidConfig: true})
+	})
+	assert.NotPanics(t, func() {
+		CORSWithConfig(CORSConfig{AllowCredentials: true})
+	})
+	assert.NotPanics(t, func() {
+		CORSWithConfig(CORSConfig{AllowOrigins: []string{"https://example.com"}, AllowCredentials: true, PanicOnInvalidConfig: true})
+	})
+}
+
+func TestCORSWithConfig_invalidConfigLoggedWithEchoLogger(t *testing.T) {
+	e := echo.New()
+	buf := new(bytes.Buffer)
+	e.Logger.SetOutput(buf)
+	e.Logger.SetLevel(log.WARN)
+
+	mw := CORSWithConfig(CORSConfig{AllowCredentials: true})
+	h := mw(func(c echo.Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		err := h(e.NewContext(req, rec))
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 1, strings.Count(buf.String(), "echo: invalid CORS config: wildcard origin can not be used with AllowCredentials"))
+}
+
+func TestCORSSimulate(t *testing.T) {
+	config := CORSConfig{
+		AllowOrigins:     []string{"https://app.example.com"},
+		AllowMethods:     []string{http.MethodGet, http.MethodPut},
+		AllowHeaders:     []string{echo.HeaderAuthorization, echo.HeaderContentType},
+		AllowCredentials: true,
+		MaxAge:           600,
+	}
+
+	var testCases = []struct {
+		name         string
+		whenOrigin   string
+		whenMethod   string
+		whenHeaders  []string
+		expectHeader http.Header
+	}{
+		{
+			name:        "ok, allowed origin",
+			whenOrigin:  "https://app.example.com",
+			whenMethod:  http.MethodPut,
+			whenHeaders: []string{echo.HeaderAuthorization},
+			expectHeader: http.Header{
+				echo.HeaderVary:                          {echo.HeaderOrigin, echo.HeaderAccessControlRequestMethod, echo.HeaderAccessControlRequestHeaders},
+				echo.HeaderAccessControlAllowOrigin:      {"https://app.example.com"},
+				echo.HeaderAccessControlAllowCredentials: {"true"},
+				echo.HeaderAccessControlAllowMethods:     {"GET,PUT"},
+				echo.HeaderAccessControlAllowHeaders:     {"Authorization,Content-Type"},
+				echo.HeaderAccessControlMaxAge:           {"600"},
+			},
+		},
+		{
+			name:       "nok, origin not allowed",
+			whenOrigin: "https://evil.example.com",
+			whenMethod: http.MethodPut,
+			expectHeader: http.Header{
+				echo.HeaderVary: {echo.HeaderOrigin},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			header := CORSSimulate(config, tc.whenOrigin, tc.whenMethod, tc.whenHeaders)
+
+			assert.Equal(t, tc.expectHeader, header)
+		})
+	}
+}
@@ -65,6 +65,26 @@ type CSRFConfig struct {
 
 	// ErrorHandler defines a function which is executed for returning custom errors.
 	ErrorHandler CSRFErrorHandler
+
+	// TokenStore generates, validates and invalidates tokens. Use `SessionCSRFTokenStore` for synchronizer token
+	// pattern with tokens stored in server-side session.
+	// Optional. Default value stores token in cookie configured with Cookie* fields (double submit cookie).
+	TokenStore CSRFTokenStore
+
+	// RegenerateToken makes the middleware replace token with new one after state changing request (not GET, HEAD,
+	// OPTIONS or TRACE) was validated successfully. New token is stored in context under ContextKey.
+	// Optional. Default value false.
+	RegenerateToken bool
+}
+
+// CSRFTokenStore stores CSRF tokens of clients.
+type CSRFTokenStore interface {
+	// Generate returns token of the client. New token is generated and stored when the client does not have one.
+	Generate(c echo.Context) (string, error)
+	// Validate reports whether token sent by the client matches the stored token.
+	Validate(c echo.Context, token string) (bool, error)
+	// Invalidate removes token of the client so next Generate call creates new one.
+	Invalidate(c echo.Context) error
 }
 
 // CSRFErrorHandler is a function which is executed for creating custom errors.
@@ -118,6 +138,13 @@ func CSRFWithConfig(config CSRFConfig) echo.MiddlewareFunc {
 		config.CookieSecure = true
 	}
 
+	if config.TokenStore == nil {
+		config.TokenStore = &cookieCSRFTokenStore{config: config}
+	}
+	if s, ok := config.TokenStore.(*SessionCSRFTokenStore); ok && s.Session == nil {
+		panic("echo: session csrf token store requires Session")
+	}
+
 	extractors, cErr := CreateExtractors(config.TokenLookup)
 	if cErr != nil {
 		panic(cErr)
@@ -129,13 +156,6 @@ func CSRFWithConfig(config CSRFConfig) echo.MiddlewareFunc {
 				return next(c)
 			}
 
-			token := ""
-			if k, err := c.Cookie(config.CookieName); err != nil {
-				token = randomString(config.TokenLength)
-			} else {
-				token = k.Value // Reuse token
-			}
-
 			switch c.Request().Method {
 			case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
 			default:
@@ -151,7 +171,11 @@ func CSRFWithConfig(config CSRFConfig) echo.MiddlewareFunc {
 					}
 
 					for _, clientToken := range clientTokens {
-						if validateCSRFToken(token, clientToken) {
+						valid, err := config.TokenStore.Validate(c, clientToken)
+						if err != nil {
+							return err
+						}
+						if valid {
 							lastTokenErr = nil
 							lastExtractorErr = nil
 							break outer
@@ -182,25 +206,18 @@ func CSRFWithConfig(config CSRFConfig) echo.MiddlewareFunc {
 					}
 					return finalErr
 				}
-			}
 
-			// Set CSRF cookie
-			cookie := new(http.Cookie)
-			cookie.Name = config.CookieName
-			cookie.Value = token
-			if config.CookiePath != "" {
-				cookie.Path = config.CookiePath
-			}
-			if config.CookieDomain != "" {
-				cookie.Domain = config.CookieDomain
+				if config.RegenerateToken {
+					if err := config.TokenStore.Invalidate(c); err != nil {
+						return err
+					}
+				}
 			}
-			if config.CookieSameSite != http.SameSiteDefaultMode {
-				cookie.SameSite = config.CookieSameSite
+
+			token, err := config.TokenStore.Generate(c)
+			if err != nil {
+				return err
 			}
-			cookie.Expires = time.Now().Add(time.Duration(config.CookieMaxAge) * time.Second)
-			cookie.Secure = config.CookieSecure
-			cookie.HttpOnly = config.CookieHTTPOnly
-			c.SetCookie(cookie)
 
 			// Store token in the context
 			c.Set(config.ContextKey, token)
@@ -213,6 +230,155 @@ func CSRFWithConfig(config CSRFConfig) echo.MiddlewareFunc {
 	}
 }
 
+// cookieCSRFTokenStore stores token in cookie sent to the client (double submit cookie).
+type cookieCSRFTokenStore struct {
+	config CSRFConfig
+}
+
+// csrfCookieInvalidatedKey is context key marking that token of the request cookie has been invalidated.
+const csrfCookieInvalidatedKey = "_csrf_cookie_invalidated"
+
+func (s *cookieCSRFTokenStore) token(c echo.Context) string {
+	if invalidated, _ := c.Get(csrfCookieInvalidatedKey).(bool); invalidated {
+		return ""
+	}
+	if k, err := c.Cookie(s.config.CookieName); err == nil {
+		return k.Value
+	}
+	return ""
+}
+
+// Generate returns token of the request cookie or generates new one. Cookie is (re)sent with every response.
+func (s *cookieCSRFTokenStore) Generate(c echo.Context) (string, error) {
+	token := s.token(c)
+	if token == "" {
+		token = randomString(s.config.TokenLength)
+	}
+	c.Set(csrfCookieInvalidatedKey, false)
+	s.setCookie(c, token, time.Now().Add(time.Duration(s.config.CookieMaxAge)*time.Second))
+	return token, nil
+}
+
+// Validate compares token with token of the request cookie.
+func (s *cookieCSRFTokenStore) Validate(c echo.Context, token string) (bool, error) {
+	stored := s.token(c)
+	return stored != "" && validateCSRFToken(stored, token), nil
+}
+
+// Invalidate makes the token of the request cookie unusable for the rest of the request.
+func (s *cookieCSRFTokenStore) Invalidate(c echo.Context) error {
+	c.Set(csrfCookieInvalidatedKey, true)
+	return nil
+}
+
+func (s *cookieCSRFTokenStore) setCookie(c echo.Context, token string, expires time.Time) {
+	config := s.config
+	cookie := new(http.Cookie)
+	cookie.Name = config.CookieName
+	cookie.Value = token
+	if config.CookiePath != "" {
+		cookie.Path = config.CookiePath
+	}
+	if config.CookieDomain != "" {
+		cookie.Domain = config.CookieDomain
+	}
+	if config.CookieSameSite != http.SameSiteDefaultMode {
+		cookie.SameSite = config.CookieSameSite
+	}
+	cookie.Expires = expires
+	cookie.Secure = config.CookieSecure
+	cookie.HttpOnly = config.CookieHTTPOnly
+	c.SetCookie(cookie)
+}
+
+// CSRFSession is server-side session of the client used by SessionCSRFTokenStore. Implement it as adapter of the
+// session library in use.
+type CSRFSession interface {
+	// Get returns value stored under key.
+	Get(key string) (string, bool)
+	// Set stores value under key.
+	Set(key string, value string) error
+	// Delete removes value stored under key.
+	Delete(key string) error
+}
+
+// SessionCSRFTokenStore stores tokens in server-side session of the client (synchronizer token pattern).
+//
+// Example:
+//
+//	e.Use(middleware.CSRFWithConfig(middleware.CSRFConfig{
+//		TokenStore: &middleware.SessionCSRFTokenStore{
+//			Session: func(c echo.Context) (middleware.CSRFSession, error) {
+//				return sessionAdapter{c}, nil
+//			},
+//		},
+//		RegenerateToken: true,
+//	}))
+type SessionCSRFTokenStore struct {
+	// Session returns session of the request.
+	// Required.
+	Session func(c echo.Context) (CSRFSession, error)
+
+	// Key is the session key the token is stored under.
+	// Optional. Default value "csrf_token".
+	Key string
+
+	// TokenLength is the length of the generated token.
+	// Optional. Default value 32.
+	TokenLength uint8
+}
+
+func (s *SessionCSRFTokenStore) session(c echo.Context) (CSRFSession, string, error) {
+	if s.Session == nil {
+		panic("echo: session csrf token store requires Session")
+	}
+	key := s.Key
+	if key == "" {
+		key = "csrf_token"
+	}
+	session, err := s.Session(c)
+	return session, key, err
+}
+
+// Generate returns token stored in session or generates and stores new one.
+func (s *SessionCSRFTokenStore) Generate(c echo.Context) (string, error) {
+	session, key, err := s.session(c)
+	if err != nil {
+		return "", err
+	}
+	if token, ok := session.Get(key); ok && token != "" {
+		return token, nil
+	}
+	length := s.TokenLength
+	if length == 0 {
+		length = DefaultCSRFConfig.TokenLength
+	}
+	token := randomString(length)
+	if err := session.Set(key, token); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Validate compares token with token stored in session.
+func (s *SessionCSRFTokenStore) Validate(c echo.Context, token string) (bool, error) {
+	session, key, err := s.session(c)
+	if err != nil {
+		return false, err
+	}
+	stored, ok := session.Get(key)
+	return ok && stored != "" && validateCSRFToken(stored, token), nil
+}
+
+// Invalidate removes token from session.
+func (s *SessionCSRFTokenStore) Invalidate(c echo.Context) error {
+	session, key, err := s.session(c)
+	if err != nil {
+		return err
+	}
+	return session.Delete(key)
+}
+
 func validateCSRFToken(token, clientToken string) bool {
 	return subtle.ConstantTimeCompare([]byte(token), []byte(clientToken)) == 1
 }
@@ -382,3 +382,105 @@ func TestCSRFErrorHandling(t *testing.T) {
 	assert.Equal(t, http.StatusTeapot, res.Code)
 	assert.Equal(t, "{\"message\":\"error_handler_executed\"}\n", res.Body.String())
 }
+
+type memoryCSRFSession map[string]string
+
+func (s memoryCSRFSession) Get(key string) (string, bool) {
+	v, ok := s[key]
+	return v, ok
+}
+
+func (s memoryCSRFSession) Set(key string, value string) error {
+	s[key] = value
+	return nil
+}
+
+func (s memoryCSRFSession) Delete(key string) error {
+	delete(s, key)
+	return nil
+}
+
+func TestCSRF_sessionTokenStore(t *testing.T) {
+	sessions := map[string]memoryCSRFSession{"abc": {}}
+	e := echo.New()
+	e.Use(CSRFWithConfig(CSRFConfig{
+		TokenStore: &SessionCSRFTokenStore{
+			Session: func(c echo.Context) (CSRFSession, error) {
+				cookie, err := c.Cookie("session")
+				if err != nil {
+					return nil, echo.ErrUnauthorized
+				}
+				return sessions[cookie.Value], nil
+			},
+		},
+		RegenerateToken: true,
+	}))
+	handler := func(c echo.Context) error {
+		return c.String(http.StatusOK, c.Get("csrf").(string))
+	}
+	e.GET("/", handler)
+	e.POST("/", handler)
+
+	serve := func(method string, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/", nil)
+		req.Header.Set(echo.HeaderCookie, "session=abc")
+		if token != "" {
+			req.Header.Set(echo.HeaderXCSRFToken, token)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	token := rec.Body.String()
+	assert.Len(t, token, 32)
+	assert.Equal(t, token, sessions["abc"]["csrf_token"])
+	assert.Empty(t, rec.Header().Get(echo.HeaderSetCookie))
+	assert.Equal(t, token, serve(http.MethodGet, "").Body.String())
+
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, "wrong").Code)
+
+	rec = serve(http.MethodPost, token)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	newToken := rec.Body.String()
+	assert.NotEqual(t, token, newToken)
+	assert.Equal(t, newToken, sessions["abc"]["csrf_token"])
+
+	// old token can not be reused
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, token).Code)
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, newToken).Code)
+
+	// session errors are returned as is
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestCSRF_regenerateCookieToken(t *testing.T) {
+	e := echo.New()
+	h := CSRFWithConfig(CSRFConfig{RegenerateToken: true})(func(c echo.Context) error {
+		return c.String(http.StatusOK, c.Get("csrf").(string))
+	})
+
+	token := randomString(32)
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set(echo.HeaderCookie, "_csrf="+token)
+	req.Header.Set(echo.HeaderXCSRFToken, token)
+	rec := httptest.NewRecorder()
+
+	err := h(e.NewContext(req, rec))
+
+	assert.NoError(t, err)
+	newToken := rec.Body.String()
+	assert.NotEqual(t, token, newToken)
+	assert.Contains(t, rec.Header().Get(echo.HeaderSetCookie), "_csrf="+newToken)
+}
+
+func TestCSRF_sessionTokenStoreRequiresSession(t *testing.T) {
+	assert.PanicsWithValue(t, "echo: session csrf token store requires Session", func() {
+		CSRFWithConfig(CSRFConfig{TokenStore: &SessionCSRFTokenStore{}})
+	})
+}
@@ -192,7 +192,7 @@ func valuesFromCookie(name string) ValuesExtractor {
 func valuesFromForm(name string) ValuesExtractor {
 	return func(c echo.Context) ([]string, error) {
 		if c.Request().Form == nil {
-			_ = c.Request().ParseMultipartForm(32 << 20) // same what `c.Request().FormValue(name)` does
+			_, _ = c.FormParams() // parses with configured multipart memory limit, errors are ignored as in `FormValue`
 		}
 		values := c.Request().Form[name]
 		if len(values) == 0 {
@@ -262,6 +262,7 @@ func TestValuesFromQuery(t *testing.T) {
 	var testCases = []struct {
 		name           string
 		givenQueryPart string
+		givenMode      echo.QueryParseMode
 		whenName       string
 		expectValues   []string
 		expectError    string
@@ -278,6 +279,19 @@ func TestValuesFromQuery(t *testing.T) {
 			whenName:       "id",
 			expectValues:   []string{"123", "456"},
 		},
+		{
+			name:           "ok, semicolon separated values",
+			givenQueryPart: "?id=123;name=test;id=456",
+			givenMode:      echo.QueryParseSemicolon,
+			whenName:       "id",
+			expectValues:   []string{"123", "456"},
+		},
+		{
+			name:           "nok, semicolon separated values in default mode",
+			givenQueryPart: "?id=123;name=test",
+			whenName:       "id",
+			expectError:    errQueryExtractorValueMissing.Error(),
+		},
 		{
 			name:           "nok, missing value",
 			givenQueryPart: "?id=123&name=test",
@@ -301,6 +315,7 @@ func TestValuesFromQuery(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			e := echo.New()
+			e.QueryParseMode = tc.givenMode
 
 			req := httptest.NewRequest(http.MethodGet, "/"+tc.givenQueryPart, nil)
 			rec := httptest.NewRecorder()
@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"context"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+// FeatureFlagsConfig defines the config for FeatureFlags middleware.
+type FeatureFlagsConfig struct {
+	// Skipper defines a function to skip middleware.
+	Skipper Skipper
+
+	// Provider evaluates flags for the subject of the request.
+	// Required.
+	Provider FeatureFlagProvider
+
+	// Subject resolves subject (ala user or tenant ID) flags are evaluated for.
+	// Optional. Default value returns empty string for all requests.
+	Subject func(c echo.Context) string
+
+	// Prefetch are flags evaluated with single Provider call before the handler is called. Other flags are evaluated
+	// lazily on first `FlagEnabled` call and cached for the rest of the request.
+	// Optional.
+	Prefetch []string
+
+	// Defaults are values of flags used when Provider returns an error or does not return value for the flag.
+	// Optional. Flags missing from Defaults are disabled.
+	Defaults map[string]bool
+
+	// OnError is called when Provider returns an error. Request is handled with Defaults regardless.
+	// Optional.
+	OnError func(c echo.Context, err error)
+}
+
+// FeatureFlagProvider evaluates feature flags. Result should contain value for each of the flags, flags missing from
+// the result get their default value.
+type FeatureFlagProvider interface {
+	Evaluate(ctx context.Context, subject string, flags []string) (map[string]bool, error)
+}
+
+// StaticFeatureFlags is FeatureFlagProvider with fixed values of flags. Useful for tests and local development.
+type StaticFeatureFlags struct {
+	// Flags are values of flags for all subjects.
+	Flags map[string]bool
+	// Subjects overrides values of Flags for specific subjects.
+	Subjects map[string]map[string]bool
+}
+
+// Evaluate returns values of flags for subject.
+func (s StaticFeatureFlags) Evaluate(_ context.Context, subject string, flags []string) (map[string]bool, error) {
+	result := make(map[string]bool, len(flags))
+	overrides := s.Subjects[subject]
+	for _, flag := range flags {
+		if v, ok := overrides[flag]; ok {
+			result[flag] = v
+		} else if v, ok := s.Flags[flag]; ok {
+			result[flag] = v
+		}
+	}
+	return result, nil
+}
+
+const featureFlagsContextKey = "_feature_flags"
+
+// DefaultFeatureFlagsConfig is the default FeatureFlags middleware config.
+var DefaultFeatureFlagsConfig = FeatureFlagsConfig{
+	Skipper: DefaultSkipper,
+	Subject: func(c echo.Context) string {
+		return ""
+	},
+}
+
+// FeatureFlags returns a FeatureFlags middleware that evaluates flags with provider once per request.
+//
+// Example:
+//
+//	e.Use(middleware.FeatureFlagsWithConfig(middleware.FeatureFlagsConfig{
+//		Provider: flagsClient,
+//		Subject:  middleware.TenantIDFromContext,
+//		Prefetch: []string{"new-checkout"},
+//	}))
+//	e.GET("/checkout", func(c echo.Context) error {
+//		if middleware.FlagEnabled(c, "new-checkout") {
+//			return newCheckout(c)
+//		}
+//		return checkout(c)
+//	})
+func FeatureFlags(provider FeatureFlagProvider, prefetch ...string) echo.MiddlewareFunc {
+	c := DefaultFeatureFlagsConfig
+	c.Provider = provider
+	c.Prefetch = prefetch
+	return FeatureFlagsWithConfig(c)
+}
+
+// FeatureFlagsWithConfig returns a FeatureFlags middleware with config.
+// See: `FeatureFlags()`.
+func FeatureFlagsWithConfig(config FeatureFlagsConfig) echo.MiddlewareFunc {
+	if config.Provider == nil {
+		panic("echo: feature flags middleware requires a provider")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultFeatureFlagsConfig.Skipper
+	}
+	if config.Subject == nil {
+		config.Subject = DefaultFeatureFlagsConfig.Subject
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+			flags := &featureFlags{
+				config:  &config,
+				c:       c,
+				subject: config.Subject(c),
+				values:  make(map[string]bool, len(config.Prefetch)),
+			}
+			if len(config.Prefetch) > 0 {
+				flags.evaluate(config.Prefetch)
+			}
+			c.Set(featureFlagsContextKey, flags)
+			return next(c)
+		}
+	}
+}
+
+// FlagEnabled returns value of the feature flag for the request. Flags that were not prefetched are evaluated on first
+// call and cached for the rest of the request. Returns false when FeatureFlags middleware was not executed for the
+// request.
+func FlagEnabled(c echo.Store, flag string) bool {
+	flags, ok := c.Get(featureFlagsContextKey).(*featureFlags)
+	if !ok {
+		return false
+	}
+	flags.mutex.Lock()
+	defer flags.mutex.Unlock()
+	if v, ok := flags.values[flag]; ok {
+		return v
+	}
+	flags.evaluate([]string{flag})
+	return flags.values[flag]
+}
+
+// featureFlags holds flags evaluated for the request.
+type featureFlags struct {
+	config  *FeatureFlagsConfig
+	c       echo.Context
+	subject string
+
+	mutex  sync.Mutex
+	values map[string]bool
+}
+
+// evaluate evaluates flags with the provider and stores the results. Flags without value (and all flags when provider
+// fails) get their default values so provider is not called for them again during the request.
+func (f *featureFlags) evaluate(flags []string) {
+	result, err := f.config.Provider.Evaluate(f.c.Request().Context(), f.subject, flags)
+	if err != nil && f.config.OnError != nil {
+		f.config.OnError(f.c, err)
+	}
+	for _, flag := range flags {
+		v, ok := result[flag]
+		if err != nil || !ok {
+			v = f.config.Defaults[flag]
+		}
+		f.values[flag] = v
+	}
+}
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingFlagProvider struct {
+	provider FeatureFlagProvider
+	err      error
+	calls    [][]string
+	subjects []string
+}
+
+func (p *countingFlagProvider) Evaluate(ctx context.Context, subject string, flags []string) (map[string]bool, error) {
+	p.calls = append(p.calls, flags)
+	p.subjects = append(p.subjects, subject)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.provider.Evaluate(ctx, subject, flags)
+}
+
+func TestFeatureFlags(t *testing.T) {
+	provider := &countingFlagProvider{provider: StaticFeatureFlags{
+		Flags:    map[string]bool{"new-checkout": false, "dark-mode": true},
+		Subjects: map[string]map[string]bool{"acme": {"new-checkout": true}},
+	}}
+	e := echo.New()
+	e.Use(FeatureFlagsWithConfig(FeatureFlagsConfig{
+		Provider: provider,
+		Subject: func(c echo.Context) string {
+			return c.Request().Header.Get("X-Tenant-ID")
+		},
+		Prefetch: []string{"new-checkout", "dark-mode"},
+	}))
+	e.GET("/", func(c echo.Context) error {
+		result := map[string]bool{
+			"new-checkout": FlagEnabled(c, "new-checkout"),
+			"dark-mode":    FlagEnabled(c, "dark-mode"),
+			"beta":         FlagEnabled(c, "beta"),
+		}
+		FlagEnabled(c, "beta") // lazily evaluated flag is cached
+		return c.JSON(http.StatusOK, result)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Tenant-ID", "acme")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"new-checkout":true,"dark-mode":true,"beta":false}`, rec.Body.String())
+	assert.Equal(t, [][]string{{"new-checkout", "dark-mode"}, {"beta"}}, provider.calls)
+	assert.Equal(t, []string{"acme", "acme"}, provider.subjects)
+}
+
+func TestFeatureFlags_providerError(t *testing.T) {
+	errProvider := errors.New("provider is down")
+	provider := &countingFlagProvider{err: errProvider}
+	var onError []error
+	e := echo.New()
+	e.Use(FeatureFlagsWithConfig(FeatureFlagsConfig{
+		Provider: provider,
+		Prefetch: []string{"new-checkout"},
+		Defaults: map[string]bool{"new-checkout": true, "beta": true},
+		OnError: func(c echo.Context, err error) {
+			onError = append(onError, err)
+		},
+	}))
+	e.GET("/", func(c echo.Context) error {
+		result := map[string]bool{
+			"new-checkout": FlagEnabled(c, "new-checkout"),
+			"beta":         FlagEnabled(c, "beta"),
+			"other":        FlagEnabled(c, "other"),
+		}
+		FlagEnabled(c, "other") // failed evaluation is cached too
+		return c.JSON(http.StatusOK, result)
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"new-checkout":true,"beta":true,"other":false}`, rec.Body.String())
+	assert.Len(t, provider.calls, 3)
+	assert.Equal(t, []error{errProvider, errProvider, errProvider}, onError)
+}
+
+func TestFeatureFlags_withoutMiddleware(t *testing.T) {
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	assert.False(t, FlagEnabled(c, "new-checkout"))
+}
+
+func TestFeatureFlags_panicsWithoutProvider(t *testing.T) {
+	assert.PanicsWithValue(t, "echo: feature flags middleware requires a provider", func() {
+		FeatureFlags(nil)
+	})
+}
+
+func TestStaticFeatureFlags_Evaluate(t *testing.T) {
+	provider := StaticFeatureFlags{
+		Flags:    map[string]bool{"a": true, "b": false},
+		Subjects: map[string]map[string]bool{"user-1": {"b": true}},
+	}
+
+	result, err := provider.Evaluate(context.Background(), "user-1", []string{"a", "b", "c"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"a": true, "b": true}, result)
+
+	result, err = provider.Evaluate(context.Background(), "user-2", []string{"b"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"b": false}, result)
+}
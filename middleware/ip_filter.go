@@ -0,0 +1,364 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"bufio"
+	stdContext "context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// IPFilterConfig defines the config for IPFilter middleware.
+type IPFilterConfig struct {
+	// Skipper defines a function to skip middleware.
+	Skipper Skipper
+
+	// Rules are the allow and deny lists of the filter. Rules can be replaced while the server is running with
+	// `IPFilter.SetRules`.
+	// Optional. Default value (empty) allows all requests.
+	Rules IPFilterRules
+
+	// HideDenied responds "404 - Not Found" instead of "403 - Forbidden" to rejected requests so the existence of the
+	// route is not disclosed.
+	// Optional.
+	HideDenied bool
+}
+
+// IPFilterAction is action taken for client IP address that is not in allow or deny list.
+type IPFilterAction int
+
+const (
+	// IPFilterDefault denies addresses when the allow list is not empty and allows them otherwise.
+	IPFilterDefault IPFilterAction = iota
+	// IPFilterAllow allows addresses that are not in the deny list.
+	IPFilterAllow
+	// IPFilterDeny denies addresses that are not in the allow list.
+	IPFilterDeny
+)
+
+// IPFilterRules defines which client IP addresses are allowed. Deny list is evaluated first, then allow list and then
+// DefaultAction is taken. IPv4-mapped IPv6 prefixes (ala `::ffff:10.0.0.0/104`) match the same addresses as their
+// IPv4 form.
+type IPFilterRules struct {
+	// Allow is list of prefixes that are allowed.
+	Allow []netip.Prefix
+	// Deny is list of prefixes that are denied.
+	Deny []netip.Prefix
+	// DefaultAction is action taken for addresses that are in neither list.
+	DefaultAction IPFilterAction
+}
+
+// IPFilter allows or denies requests by client IP address resolved with `Context#RealIPAddr` when `Echo#IPExtractor`
+// is set (so the extractor decides which proxies are trusted). Without IPExtractor the address of the direct peer is
+// used, as `X-Forwarded-For` and `X-Real-IP` headers can be set by any client.
+type IPFilter struct {
+	// Logger is used to log results of reloads done by `Watch`. Optional.
+	Logger echo.Logger
+
+	config IPFilterConfig
+	rules  atomic.Pointer[IPFilterRules]
+
+	file    string
+	mutex   sync.Mutex // serializes reloads
+	modTime time.Time
+}
+
+// DefaultIPFilterConfig is the default IPFilter middleware config.
+var DefaultIPFilterConfig = IPFilterConfig{
+	Skipper: DefaultSkipper,
+}
+
+// IPFilterWithConfig returns an IPFilter middleware with config.
+// See: `NewIPFilter()`.
+func IPFilterWithConfig(config IPFilterConfig) echo.MiddlewareFunc {
+	return NewIPFilter(config).Middleware()
+}
+
+// NewIPFilter creates IPFilter with config. Use it instead of `IPFilterWithConfig` when rules need to be updated while
+// the server is running. Panics when rules are invalid.
+//
+// Example:
+//
+//	filter := middleware.NewIPFilter(middleware.IPFilterConfig{
+//		Rules: middleware.IPFilterRules{
+//			Allow: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+//			Deny:  []netip.Prefix{netip.MustParsePrefix("10.6.0.0/16")},
+//		},
+//		HideDenied: true,
+//	})
+//	admin := e.Group("/admin", filter.Middleware())
+func NewIPFilter(config IPFilterConfig) *IPFilter {
+	if config.Skipper == nil {
+		config.Skipper = DefaultIPFilterConfig.Skipper
+	}
+	f := &IPFilter{config: config}
+	if err := f.SetRules(config.Rules); err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// NewIPFilterFromFile creates IPFilter with rules loaded from file. Rules of the config are ignored. Each line of the
+// file is `allow <prefix or address>`, `deny <prefix or address>` or `default allow|deny`. Empty lines and lines
+// starting with `#` are ignored. Use `IPFilter.Watch` to reload the file when it changes.
+//
+// Example file:
+//
+//	# office network, except guest wifi
+//	allow 10.0.0.0/8
+//	deny 10.6.0.0/16
+//	allow 2001:db8::/32
+//	default deny
+func NewIPFilterFromFile(file string, config IPFilterConfig) (*IPFilter, error) {
+	if config.Skipper == nil {
+		config.Skipper = DefaultIPFilterConfig.Skipper
+	}
+	f := &IPFilter{config: config, file: file}
+	if err := f.Reload(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Rules returns rules currently in use.
+func (f *IPFilter) Rules() IPFilterRules {
+	return *f.rules.Load()
+}
+
+// SetRules replaces rules of the filter. Requests already being handled keep using the previous rules. Returns error
+// and keeps the previous rules when any of the prefixes is invalid.
+func (f *IPFilter) SetRules(rules IPFilterRules) error {
+	allow, err := normalizeIPFilterPrefixes(rules.Allow)
+	if err != nil {
+		return err
+	}
+	deny, err := normalizeIPFilterPrefixes(rules.Deny)
+	if err != nil {
+		return err
+	}
+	action := rules.DefaultAction
+	if action == IPFilterDefault {
+		action = IPFilterAllow
+		if len(allow) > 0 {
+			action = IPFilterDeny
+		}
+	}
+	f.rules.Store(&IPFilterRules{Allow: allow, Deny: deny, DefaultAction: action})
+	return nil
+}
+
+func normalizeIPFilterPrefixes(prefixes []netip.Prefix) ([]netip.Prefix, error) {
+	result := make([]netip.Prefix, 0, len(prefixes))
+	for _, p := range prefixes {
+		if !p.IsValid() {
+			return nil, fmt.Errorf("echo: ip filter prefix %q is invalid", p.String())
+		}
+		result = append(result, normalizeIPFilterPrefix(p))
+	}
+	return result, nil
+}
+
+// normalizeIPFilterPrefix converts IPv4-mapped IPv6 prefix to IPv4 prefix because client addresses are compared in
+// their unmapped form.
+func normalizeIPFilterPrefix(p netip.Prefix) netip.Prefix {
+	if p.Addr().Is4In6() && p.Bits() >= 96 {
+		p = netip.PrefixFrom(p.Addr().Unmap(), p.Bits()-96)
+	}
+	return p.Masked()
+}
+
+// Allowed reports whether requests from the address are allowed by the current rules.
+func (f *IPFilter) Allowed(addr netip.Addr) bool {
+	rules := f.rules.Load()
+	// prefixes have no zone, zoned address (ala `fe80::1%eth0`) would not match any of them
+	addr = addr.Unmap().WithZone("")
+	if containsIPAddr(rules.Deny, addr) {
+		return false
+	}
+	if containsIPAddr(rules.Allow, addr) {
+		return true
+	}
+	return rules.DefaultAction == IPFilterAllow
+}
+
+func containsIPAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, p := range prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware returns middleware function of the filter.
+func (f *IPFilter) Middleware() echo.MiddlewareFunc {
+	config := f.config
+	deniedErr := echo.ErrForbidden
+	if config.HideDenied {
+		deniedErr = echo.ErrNotFound
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+			addr, err := ipFilterClientAddr(c)
+			if err != nil {
+				return deniedErr.WithInternal(err)
+			}
+			if !f.Allowed(addr) {
+				return deniedErr.WithInternal(fmt.Errorf("client ip %s is not allowed", addr))
+			}
+			return next(c)
+		}
+	}
+}
+
+// ipFilterClientAddr returns client address resolved by `Echo#IPExtractor` or address of the direct peer when
+// IPExtractor is not set.
+func ipFilterClientAddr(c echo.Context) (netip.Addr, error) {
+	if e := c.Echo(); e != nil && e.IPExtractor != nil {
+		return c.RealIPAddr()
+	}
+	return netip.ParseAddr(echo.ExtractIPDirect()(c.Request()))
+}
+
+// Reload reads rules from the file of filter created with `NewIPFilterFromFile`. Invalid file is not applied and the
+// previous rules are kept in use.
+func (f *IPFilter) Reload() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.reload()
+}
+
+func (f *IPFilter) reload() error {
+	if f.file == "" {
+		return errors.New("echo: ip filter was not created from file")
+	}
+	info, err := os.Stat(f.file)
+	if err != nil {
+		return err
+	}
+	rules, err := readIPFilterRules(f.file)
+	if err != nil {
+		return err
+	}
+	if err := f.SetRules(rules); err != nil {
+		return err
+	}
+	f.modTime = info.ModTime()
+	return nil
+}
+
+// reloadIfChanged reloads rules when modification time of the file has changed.
+func (f *IPFilter) reloadIfChanged() (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	info, err := os.Stat(f.file)
+	if err != nil {
+		return false, err
+	}
+	if info.ModTime().Equal(f.modTime) {
+		return false, nil
+	}
+	return true, f.reload()
+}
+
+// Watch reloads rules when modification time of the file changes (checked every checkInterval). Watch blocks until
+// the context is cancelled.
+//
+// Example: `go filter.Watch(ctx, 10*time.Second)`
+func (f *IPFilter) Watch(ctx stdContext.Context, checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			f.logResult(f.reloadIfChanged())
+		}
+	}
+}
+
+func (f *IPFilter) logResult(reloaded bool, err error) {
+	if f.Logger == nil {
+		return
+	}
+	if err != nil {
+		f.Logger.Errorf("echo: failed to reload ip filter rules %s: %v", f.file, err)
+		return
+	}
+	if reloaded {
+		f.Logger.Infof("echo: reloaded ip filter rules %s", f.file)
+	}
+}
+
+func readIPFilterRules(file string) (IPFilterRules, error) {
+	rules := IPFilterRules{}
+	fh, err := os.Open(file)
+	if err != nil {
+		return rules, err
+	}
+	defer fh.Close()
+
+	scanner := bufio.NewScanner(fh)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return rules, fmt.Errorf("echo: ip filter file %s line %d: expected `<allow|deny|default> <value>`", file, line)
+		}
+		if fields[0] == "default" {
+			switch fields[1] {
+			case "allow":
+				rules.DefaultAction = IPFilterAllow
+			case "deny":
+				rules.DefaultAction = IPFilterDeny
+			default:
+				return rules, fmt.Errorf("echo: ip filter file %s line %d: invalid default action %q", file, line, fields[1])
+			}
+			continue
+		}
+		prefix, err := parseIPFilterPrefix(fields[1])
+		if err != nil {
+			return rules, fmt.Errorf("echo: ip filter file %s line %d: %w", file, line, err)
+		}
+		switch fields[0] {
+		case "allow":
+			rules.Allow = append(rules.Allow, prefix)
+		case "deny":
+			rules.Deny = append(rules.Deny, prefix)
+		default:
+			return rules, fmt.Errorf("echo: ip filter file %s line %d: invalid action %q", file, line, fields[0])
+		}
+	}
+	return rules, scanner.Err()
+}
+
+// parseIPFilterPrefix parses prefix or single address (as prefix of full length).
+func parseIPFilterPrefix(s string) (netip.Prefix, error) {
+	if strings.IndexByte(s, '/') != -1 {
+		return netip.ParsePrefix(s)
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
@@ -0,0 +1,336 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	stdContext "context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPFilter_Allowed(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenRules  IPFilterRules
+		whenAddr    string
+		expectAllow bool
+	}{
+		{
+			name:        "ok, allowed",
+			givenRules:  IPFilterRules{Allow: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}},
+			whenAddr:    "10.1.2.3",
+			expectAllow: true,
+		},
+		{
+			name:       "nok, not in allow list",
+			givenRules: IPFilterRules{Allow: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}},
+			whenAddr:   "192.0.2.1",
+		},
+		{
+			name: "nok, deny is evaluated first",
+			givenRules: IPFilterRules{
+				Allow: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+				Deny:  []netip.Prefix{netip.MustParsePrefix("10.6.0.0/16")},
+			},
+			whenAddr: "10.6.0.1",
+		},
+		{
+			name:        "ok, default allows when allow list is empty",
+			givenRules:  IPFilterRules{Deny: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}},
+			whenAddr:    "192.0.2.1",
+			expectAllow: true,
+		},
+		{
+			name: "nok, explicit default deny",
+			givenRules: IPFilterRules{
+				Deny:          []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+				DefaultAction: IPFilterDeny,
+			},
+			whenAddr: "192.0.2.1",
+		},
+		{
+			name: "ok, explicit default allow",
+			givenRules: IPFilterRules{
+				Allow:         []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+				DefaultAction: IPFilterAllow,
+			},
+			whenAddr:    "192.0.2.1",
+			expectAllow: true,
+		},
+		{
+			name:        "ok, mapped address matches IPv4 prefix",
+			givenRules:  IPFilterRules{Allow: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}},
+			whenAddr:    "::ffff:10.1.2.3",
+			expectAllow: true,
+		},
+		{
+			name:        "ok, IPv4 address matches mapped prefix",
+			givenRules:  IPFilterRules{Allow: []netip.Prefix{netip.MustParsePrefix("::ffff:10.0.0.0/104")}},
+			whenAddr:    "10.1.2.3",
+			expectAllow: true,
+		},
+		{
+			name:       "nok, mapped prefix does not match other IPv4",
+			givenRules: IPFilterRules{Allow: []netip.Prefix{netip.MustParsePrefix("::ffff:10.0.0.0/104")}},
+			whenAddr:   "11.1.2.3",
+		},
+		{
+			name:       "nok, mapped address is denied by IPv4 prefix",
+			givenRules: IPFilterRules{Deny: []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")}},
+			whenAddr:   "::ffff:192.0.2.10",
+		},
+		{
+			name:        "ok, IPv6",
+			givenRules:  IPFilterRules{Allow: []netip.Prefix{netip.MustParsePrefix("2001:db8::/32")}},
+			whenAddr:    "2001:db8::1",
+			expectAllow: true,
+		},
+		{
+			name:       "nok, zoned address is denied by prefix",
+			givenRules: IPFilterRules{Deny: []netip.Prefix{netip.MustParsePrefix("fe80::/10")}},
+			whenAddr:   "fe80::1%eth0",
+		},
+		{
+			name:        "ok, zoned address is allowed by prefix",
+			givenRules:  IPFilterRules{Allow: []netip.Prefix{netip.MustParsePrefix("fe80::/10")}},
+			whenAddr:    "fe80::1%eth0",
+			expectAllow: true,
+		},
+		{
+			name:       "nok, IPv6 does not match IPv4 prefix",
+			givenRules: IPFilterRules{Allow: []netip.Prefix{netip.MustParsePrefix("0.0.0.0/0")}},
+			whenAddr:   "2001:db8::1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewIPFilter(IPFilterConfig{Rules: tc.givenRules})
+			assert.Equal(t, tc.expectAllow, f.Allowed(netip.MustParseAddr(tc.whenAddr)))
+		})
+	}
+}
+
+func TestIPFilter_Middleware(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		givenHide      bool
+		whenRemoteAddr string
+		whenXFF        string
+		expectCode     int
+	}{
+		{
+			name:           "ok, allowed client behind trusted proxy",
+			whenRemoteAddr: "127.0.0.1:1234",
+			whenXFF:        "10.1.2.3",
+			expectCode:     http.StatusOK,
+		},
+		{
+			name:           "nok, spoofed header from untrusted client",
+			whenRemoteAddr: "192.0.2.1:1234",
+			whenXFF:        "10.1.2.3",
+			expectCode:     http.StatusForbidden,
+		},
+		{
+			name:           "ok, mapped remote address",
+			whenRemoteAddr: "[::ffff:10.1.2.3]:1234",
+			expectCode:     http.StatusOK,
+		},
+		{
+			name:           "nok, hidden",
+			givenHide:      true,
+			whenRemoteAddr: "192.0.2.1:1234",
+			expectCode:     http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.IPExtractor = echo.ExtractIPFromXFFHeader()
+			admin := e.Group("/admin", IPFilterWithConfig(IPFilterConfig{
+				Rules:      IPFilterRules{Allow: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}},
+				HideDenied: tc.givenHide,
+			}))
+			admin.GET("", func(c echo.Context) error {
+				return c.String(http.StatusOK, "OK")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			req.RemoteAddr = tc.whenRemoteAddr
+			if tc.whenXFF != "" {
+				req.Header.Set(echo.HeaderXForwardedFor, tc.whenXFF)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+		})
+	}
+}
+
+func TestIPFilter_MiddlewareWithoutIPExtractor(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		whenRemoteAddr string
+		whenXFF        string
+		whenXRealIP    string
+		expectCode     int
+	}{
+		{
+			name:           "ok, allowed peer",
+			whenRemoteAddr: "10.1.2.3:1234",
+			expectCode:     http.StatusOK,
+		},
+		{
+			name:           "nok, spoofed X-Forwarded-For",
+			whenRemoteAddr: "192.0.2.1:1234",
+			whenXFF:        "10.1.2.3",
+			expectCode:     http.StatusForbidden,
+		},
+		{
+			name:           "nok, spoofed X-Real-IP",
+			whenRemoteAddr: "192.0.2.1:1234",
+			whenXRealIP:    "10.1.2.3",
+			expectCode:     http.StatusForbidden,
+		},
+		{
+			name:           "nok, invalid remote address",
+			whenRemoteAddr: "pipe",
+			whenXFF:        "10.1.2.3",
+			expectCode:     http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(IPFilterWithConfig(IPFilterConfig{
+				Rules: IPFilterRules{Allow: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}},
+			}))
+			e.GET("/", func(c echo.Context) error {
+				return c.String(http.StatusOK, "OK")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tc.whenRemoteAddr
+			if tc.whenXFF != "" {
+				req.Header.Set(echo.HeaderXForwardedFor, tc.whenXFF)
+			}
+			if tc.whenXRealIP != "" {
+				req.Header.Set(echo.HeaderXRealIP, tc.whenXRealIP)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+		})
+	}
+}
+
+func TestIPFilter_SetRules(t *testing.T) {
+	f := NewIPFilter(IPFilterConfig{})
+	assert.True(t, f.Allowed(netip.MustParseAddr("192.0.2.1")))
+
+	err := f.SetRules(IPFilterRules{Allow: []netip.Prefix{{}}})
+	assert.EqualError(t, err, `echo: ip filter prefix "invalid Prefix" is invalid`)
+	assert.True(t, f.Allowed(netip.MustParseAddr("192.0.2.1")))
+
+	assert.NoError(t, f.SetRules(IPFilterRules{Allow: []netip.Prefix{netip.MustParsePrefix("::ffff:10.1.2.3/120")}}))
+	assert.False(t, f.Allowed(netip.MustParseAddr("192.0.2.1")))
+	assert.Equal(t, IPFilterRules{
+		Allow:         []netip.Prefix{netip.MustParsePrefix("10.1.2.0/24")},
+		Deny:          []netip.Prefix{},
+		DefaultAction: IPFilterDeny,
+	}, f.Rules())
+
+	assert.Panics(t, func() {
+		NewIPFilter(IPFilterConfig{Rules: IPFilterRules{Deny: []netip.Prefix{{}}}})
+	})
+}
+
+func TestNewIPFilterFromFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "ips.txt")
+	assert.NoError(t, os.WriteFile(file, []byte("# office\nallow 10.0.0.0/8\ndeny 10.6.0.0/16\n\nallow ::ffff:192.0.2.1\ndefault deny\n"), 0o600))
+
+	f, err := NewIPFilterFromFile(file, IPFilterConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, IPFilterRules{
+		Allow:         []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.0.2.1/32")},
+		Deny:          []netip.Prefix{netip.MustParsePrefix("10.6.0.0/16")},
+		DefaultAction: IPFilterDeny,
+	}, f.Rules())
+	assert.True(t, f.Allowed(netip.MustParseAddr("192.0.2.1")))
+
+	ctx, cancel := stdContext.WithCancel(stdContext.Background())
+	done := make(chan struct{})
+	go func() {
+		f.Watch(ctx, 5*time.Millisecond)
+		close(done)
+	}()
+
+	// invalid file keeps previous rules
+	assert.NoError(t, os.WriteFile(file, []byte("allow nope\n"), 0o600))
+	assert.NoError(t, os.Chtimes(file, time.Now(), time.Now().Add(time.Minute)))
+	time.Sleep(30 * time.Millisecond)
+	assert.True(t, f.Allowed(netip.MustParseAddr("192.0.2.1")))
+
+	assert.NoError(t, os.WriteFile(file, []byte("deny 192.0.2.0/24\n"), 0o600))
+	assert.NoError(t, os.Chtimes(file, time.Now(), time.Now().Add(2*time.Minute)))
+	assert.Eventually(t, func() bool {
+		return !f.Allowed(netip.MustParseAddr("192.0.2.1"))
+	}, time.Second, 5*time.Millisecond)
+	assert.True(t, f.Allowed(netip.MustParseAddr("10.6.0.1")))
+
+	cancel()
+	<-done
+}
+
+func TestNewIPFilterFromFile_invalid(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenContent string
+		expectError string
+	}{
+		{
+			name:        "missing value",
+			whenContent: "allow\n",
+			expectError: "line 1: expected `<allow|deny|default> <value>`",
+		},
+		{
+			name:        "invalid action",
+			whenContent: "# comment\npermit 10.0.0.0/8\n",
+			expectError: `line 2: invalid action "permit"`,
+		},
+		{
+			name:        "invalid default",
+			whenContent: "default maybe\n",
+			expectError: `line 1: invalid default action "maybe"`,
+		},
+		{
+			name:        "invalid prefix",
+			whenContent: "deny 10.0.0.0/33\n",
+			expectError: `line 1: netip.ParsePrefix("10.0.0.0/33"): prefix length out of range`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "ips.txt")
+			assert.NoError(t, os.WriteFile(file, []byte(tc.whenContent), 0o600))
+
+			f, err := NewIPFilterFromFile(file, IPFilterConfig{})
+			assert.Nil(t, f)
+			assert.EqualError(t, err, "echo: ip filter file "+file+" "+tc.expectError)
+		})
+	}
+}
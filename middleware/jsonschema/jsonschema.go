@@ -0,0 +1,261 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+// Package jsonschema provides middleware that validates JSON request bodies against JSON Schema documents before the
+// handler binds them. It is separate Go module so JSON Schema implementation is not dependency of Echo:
+//
+//	go get github.com/labstack/echo/v4/middleware/jsonschema
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	schemalib "github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// Config defines the config for JSON Schema validation middleware.
+type Config struct {
+	// Skipper defines a function to skip middleware.
+	Skipper middleware.Skipper
+
+	// FS is filesystem schema documents are loaded from. Relative `$ref` references in schemas are resolved against
+	// the same filesystem.
+	// Required.
+	FS fs.FS
+
+	// Schemas maps route names to paths of schema documents in FS. Requests to routes without schema are not
+	// validated.
+	// Optional. Use `Validator#Schema` to attach schema to single route instead.
+	Schemas map[string]string
+
+	// MaxBodySize is maximum size of the request body that is read for validation. Bigger bodies result
+	// "413 - Request Entity Too Large" error.
+	// Optional. Default value 1MB.
+	MaxBodySize int64
+}
+
+// FieldError describes single violation of the schema.
+type FieldError struct {
+	// Path is JSON pointer to the invalid value in the request body (ala `/user/email`).
+	Path string `json:"path"`
+	// Message describes the violation.
+	Message string `json:"message"`
+}
+
+// ValidationError is returned (wrapped into `echo.HTTPError.Internal`) when request body does not match the schema.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Error returns violations as single string.
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		parts[i] = fe.Path + ": " + fe.Message
+	}
+	return "jsonschema: request body does not match schema: " + strings.Join(parts, "; ")
+}
+
+// Validator holds compiled schemas and creates validation middlewares.
+type Validator struct {
+	config   Config
+	compiler *schemalib.Compiler
+	byName   map[string]*schemalib.Schema
+
+	routes sync.Map // method+path of matched route -> *schemalib.Schema (nil when route has no schema)
+}
+
+const schemaURLPrefix = "echo-fs:///"
+
+// DefaultConfig is the default JSON Schema validation middleware config.
+var DefaultConfig = Config{
+	Skipper:     middleware.DefaultSkipper,
+	MaxBodySize: 1024 * 1024,
+}
+
+// New loads and compiles schemas from config. Invalid or missing schemas result error so they are detected on
+// startup.
+func New(config Config) (*Validator, error) {
+	if config.FS == nil {
+		return nil, errors.New("echo: jsonschema middleware requires filesystem")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultConfig.Skipper
+	}
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = DefaultConfig.MaxBodySize
+	}
+
+	compiler := schemalib.NewCompiler()
+	compiler.LoadURL = func(url string) (io.ReadCloser, error) {
+		if !strings.HasPrefix(url, schemaURLPrefix) {
+			return nil, fmt.Errorf("jsonschema: loading of remote schema %s is not allowed", url)
+		}
+		return config.FS.Open(strings.TrimPrefix(url, schemaURLPrefix))
+	}
+	v := &Validator{
+		config:   config,
+		compiler: compiler,
+		byName:   make(map[string]*schemalib.Schema, len(config.Schemas)),
+	}
+	for routeName, path := range config.Schemas {
+		schema, err := v.compile(path)
+		if err != nil {
+			return nil, err
+		}
+		v.byName[routeName] = schema
+	}
+	return v, nil
+}
+
+// WithConfig returns a middleware that validates JSON request bodies of routes listed in `Config.Schemas`. Panics
+// when schemas can not be compiled.
+func WithConfig(config Config) echo.MiddlewareFunc {
+	v, err := New(config)
+	if err != nil {
+		panic(err)
+	}
+	return v.Middleware()
+}
+
+func (v *Validator) compile(path string) (*schemalib.Schema, error) {
+	return v.compiler.Compile(schemaURLPrefix + strings.TrimPrefix(path, "/"))
+}
+
+// Middleware returns middleware that validates JSON request bodies of routes listed in `Config.Schemas`.
+func (v *Validator) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if v.config.Skipper(c) {
+				return next(c)
+			}
+			schema := v.routeSchema(c)
+			if schema == nil {
+				return next(c)
+			}
+			if err := v.validate(c, schema); err != nil {
+				return err
+			}
+			return next(c)
+		}
+	}
+}
+
+// Schema returns middleware that validates JSON request body against schema at given path in FS. Use it to attach
+// schema to single route. Panics when schema can not be compiled.
+//
+// Example: `e.POST("/users", createUser, validator.Schema("schemas/user.json"))`
+func (v *Validator) Schema(path string) echo.MiddlewareFunc {
+	schema, err := v.compile(path)
+	if err != nil {
+		panic(err)
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if v.config.Skipper(c) {
+				return next(c)
+			}
+			if err := v.validate(c, schema); err != nil {
+				return err
+			}
+			return next(c)
+		}
+	}
+}
+
+// routeSchema returns schema of the matched route by its name.
+func (v *Validator) routeSchema(c echo.Context) *schemalib.Schema {
+	if len(v.byName) == 0 {
+		return nil
+	}
+	key := c.Request().Method + c.Path()
+	if schema, ok := v.routes.Load(key); ok {
+		return schema.(*schemalib.Schema)
+	}
+	var schema *schemalib.Schema
+	for _, r := range c.Echo().Routes() {
+		if r.Method == c.Request().Method && r.Path == c.Path() {
+			schema = v.byName[r.Name]
+			break
+		}
+	}
+	v.routes.Store(key, schema)
+	return schema
+}
+
+func (v *Validator) validate(c echo.Context, schema *schemalib.Schema) error {
+	req := c.Request()
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType))
+	if mediaType != echo.MIMEApplicationJSON {
+		return echo.ErrUnsupportedMediaType
+	}
+	if req.Body == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "request body is required")
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, v.config.MaxBodySize+1))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(err)
+	}
+	if int64(len(body)) > v.config.MaxBodySize {
+		return echo.ErrStatusRequestEntityTooLarge
+	}
+	// handler is still able to bind the body
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "request body is required")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+
+	err = schema.Validate(value)
+	if err == nil {
+		return nil
+	}
+	var schemaErr *schemalib.ValidationError
+	if !errors.As(err, &schemaErr) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	validationErr := &ValidationError{Errors: fieldErrors(schemaErr)}
+	return echo.NewHTTPError(http.StatusBadRequest, echo.Map{
+		"message": "request body does not match schema",
+		"errors":  validationErr.Errors,
+	}).SetInternal(validationErr)
+}
+
+// fieldErrors flattens validation error tree to list of leaf errors sorted by path.
+func fieldErrors(err *schemalib.ValidationError) []FieldError {
+	var result []FieldError
+	var walk func(e *schemalib.ValidationError)
+	walk = func(e *schemalib.ValidationError) {
+		if len(e.Causes) == 0 {
+			result = append(result, FieldError{Path: e.InstanceLocation, Message: e.Message})
+			return
+		}
+		for _, cause := range e.Causes {
+			walk(cause)
+		}
+	}
+	walk(err)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+	return result
+}
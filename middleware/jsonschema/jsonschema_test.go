@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package jsonschema
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var testSchemas = fstest.MapFS{
+	"schemas/user.json": {Data: []byte(`{
+		"type": "object",
+		"required": ["name", "address"],
+		"properties": {
+			"name": {"type": "string", "minLength": 2},
+			"age": {"type": "integer", "minimum": 0},
+			"address": {"$ref": "address.json"}
+		}
+	}`)},
+	"schemas/address.json": {Data: []byte(`{
+		"type": "object",
+		"required": ["city"],
+		"properties": {"city": {"type": "string"}}
+	}`)},
+	"schemas/invalid.json": {Data: []byte(`{"type": 1}`)},
+}
+
+type testUser struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func newTestEcho(t *testing.T) *echo.Echo {
+	v, err := New(Config{FS: testSchemas, Schemas: map[string]string{"createUser": "schemas/user.json"}})
+	assert.NoError(t, err)
+
+	e := echo.New()
+	e.Use(v.Middleware())
+	handler := func(c echo.Context) error {
+		u := new(testUser)
+		if err := c.Bind(u); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusCreated, u)
+	}
+	e.POST("/users", handler).Name = "createUser"
+	e.POST("/other", handler)
+	e.PUT("/users/:id", handler, v.Schema("schemas/user.json"))
+	return e
+}
+
+func TestValidator(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		whenMethod string
+		whenURL    string
+		whenBody   string
+		expectCode int
+		expectBody string
+	}{
+		{
+			name:       "ok, valid body is bound by handler",
+			whenMethod: http.MethodPost,
+			whenURL:    "/users",
+			whenBody:   `{"name":"Jon","age":30,"address":{"city":"Tallinn"}}`,
+			expectCode: http.StatusCreated,
+			expectBody: `{"name":"Jon","age":30}` + "\n",
+		},
+		{
+			name:       "nok, violations are listed with JSON pointers",
+			whenMethod: http.MethodPost,
+			whenURL:    "/users",
+			whenBody:   `{"name":"J","age":-1,"address":{}}`,
+			expectCode: http.StatusBadRequest,
+			expectBody: `{"errors":[{"path":"/address","message":"missing properties: 'city'"},{"path":"/age","message":"must be \u003e= 0 but found -1"},{"path":"/name","message":"length must be \u003e= 2, but got 1"}],"message":"request body does not match schema"}` + "\n",
+		},
+		{
+			name:       "ok, route without schema is not validated",
+			whenMethod: http.MethodPost,
+			whenURL:    "/other",
+			whenBody:   `{"name":"J"}`,
+			expectCode: http.StatusCreated,
+			expectBody: `{"name":"J","age":0}` + "\n",
+		},
+		{
+			name:       "nok, schema attached to route",
+			whenMethod: http.MethodPut,
+			whenURL:    "/users/1",
+			whenBody:   `{"name":"Jon"}`,
+			expectCode: http.StatusBadRequest,
+			expectBody: `{"errors":[{"path":"","message":"missing properties: 'address'"}],"message":"request body does not match schema"}` + "\n",
+		},
+		{
+			name:       "nok, invalid JSON",
+			whenMethod: http.MethodPost,
+			whenURL:    "/users",
+			whenBody:   `{"name":`,
+			expectCode: http.StatusBadRequest,
+			expectBody: `{"message":"unexpected EOF"}` + "\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := newTestEcho(t)
+			req := httptest.NewRequest(tc.whenMethod, tc.whenURL, strings.NewReader(tc.whenBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+		})
+	}
+}
+
+func TestValidator_validationErrorIsInternal(t *testing.T) {
+	v, err := New(Config{FS: testSchemas})
+	assert.NoError(t, err)
+
+	h := v.Schema("schemas/address.json")(func(c echo.Context) error { return nil })
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"city":1}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	err = h(echo.New().NewContext(req, httptest.NewRecorder()))
+
+	var validationErr *ValidationError
+	if assert.True(t, errors.As(err, &validationErr)) {
+		assert.Equal(t, []FieldError{{Path: "/city", Message: "expected string, but got number"}}, validationErr.Errors)
+	}
+}
+
+func TestNew_compileErrors(t *testing.T) {
+	_, err := New(Config{FS: testSchemas, Schemas: map[string]string{"a": "schemas/missing.json"}})
+	assert.Error(t, err)
+
+	_, err = New(Config{FS: testSchemas, Schemas: map[string]string{"a": "schemas/invalid.json"}})
+	assert.Error(t, err)
+
+	v, err := New(Config{FS: testSchemas})
+	assert.NoError(t, err)
+	assert.Panics(t, func() {
+		v.Schema("schemas/invalid.json")
+	})
+}
@@ -0,0 +1,305 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// LoadSheddingConfig defines the config for LoadShedding middleware.
+type LoadSheddingConfig struct {
+	// Skipper defines a function to skip middleware.
+	Skipper Skipper
+
+	// Classes are priority classes requests are classified into. Each class is shed independently when load crosses
+	// its threshold so classes with lower thresholds are shed first.
+	// Required.
+	Classes []LoadShedClass
+
+	// DefaultClass is name of the class used for requests that are not classified otherwise.
+	// Optional. Default value is name of the first class.
+	DefaultClass string
+
+	// PriorityHeader is request header that holds the class name of the request. Requests with unknown class name
+	// are classified by RouteClasses and DefaultClass. Note that the header is sent by client so it should be set or
+	// removed by trusted proxy in front of the server.
+	// Optional. Default value "X-Request-Priority".
+	PriorityHeader string
+
+	// RouteClasses maps route paths (ala `/users/:id`, as returned by `c.Path()`) to class names. Route is known only
+	// after routing so middleware with RouteClasses panics when added with `Echo#Pre`.
+	// Optional.
+	RouteClasses map[string]string
+
+	// ClassFunc resolves class name of the request. Unknown names are replaced by DefaultClass.
+	// Optional. Default value uses PriorityHeader, RouteClasses and DefaultClass in that order.
+	ClassFunc func(c echo.Context) string
+
+	// LoadFunc returns current load of the server (ala heap size from `runtime/metrics` or CPU utilization) that is
+	// compared against class thresholds.
+	// Optional. Default value returns number of requests currently processed by the middleware.
+	LoadFunc func() float64
+
+	// LoadSampleInterval is minimum time between calls to LoadFunc. Load is cached in between so expensive probes are
+	// not called for every request.
+	// Optional. Default value 0 calls LoadFunc for every request.
+	LoadSampleInterval time.Duration
+
+	// RetryAfter is value (in seconds) sent with `Retry-After` header when request is shed.
+	// Optional. Default value 1 second.
+	RetryAfter time.Duration
+
+	// DenyHandler is called when request is shed. Returned error is handled by the global error handler.
+	// Optional. Default value returns ErrLoadShed.
+	DenyHandler func(c echo.Context, class string) error
+}
+
+// LoadShedClass defines priority class of LoadShedding middleware.
+type LoadShedClass struct {
+	// Name is name of the class as sent in PriorityHeader (ala `low`, `critical`).
+	// Required.
+	Name string
+
+	// ShedAbove is load at which requests of the class start to be shed.
+	// Optional. Default value 0 never sheds requests of the class.
+	ShedAbove float64
+
+	// ResumeBelow is load under which requests of the class stop being shed. Gap between ShedAbove and ResumeBelow
+	// prevents shedding from flapping when load hovers around the threshold.
+	// Optional. Default value 90% of ShedAbove.
+	ResumeBelow float64
+}
+
+// LoadSheddingStats contains current state of LoadShedder.
+type LoadSheddingStats struct {
+	// Load is the most recent load value.
+	Load float64 `json:"load"`
+	// InFlight is number of requests currently being processed.
+	InFlight int64 `json:"in_flight"`
+	// Classes are statistics of the classes in the order of config.
+	Classes []LoadShedClassStats `json:"classes"`
+}
+
+// LoadShedClassStats contains current state of single priority class.
+type LoadShedClassStats struct {
+	// Name is name of the class.
+	Name string `json:"name"`
+	// Shedding is true when requests of the class are currently shed.
+	Shedding bool `json:"shedding"`
+	// Shed is total number of requests of the class shed since the shedder was created.
+	Shed uint64 `json:"shed"`
+}
+
+// LoadShedder sheds requests of lower priority classes when server is overloaded.
+type LoadShedder struct {
+	config  LoadSheddingConfig
+	classes []*loadShedClass
+	byName  map[string]*loadShedClass
+	dflt    *loadShedClass
+
+	inFlight   int64
+	load       atomic.Uint64 // math.Float64bits of last sampled load
+	nextSample atomic.Int64  // unix nano time after which load is sampled again
+}
+
+type loadShedClass struct {
+	LoadShedClass
+	shedding atomic.Bool
+	shed     atomic.Uint64
+}
+
+// ErrLoadShed denotes an error raised when request is shed by LoadShedding middleware.
+var ErrLoadShed = echo.NewHTTPError(http.StatusServiceUnavailable, "server is overloaded")
+
+// DefaultLoadSheddingConfig is the default LoadShedding middleware config.
+var DefaultLoadSheddingConfig = LoadSheddingConfig{
+	Skipper:        DefaultSkipper,
+	PriorityHeader: "X-Request-Priority",
+	RetryAfter:     1 * time.Second,
+	DenyHandler: func(c echo.Context, class string) error {
+		return ErrLoadShed
+	},
+}
+
+// LoadSheddingWithConfig returns a LoadShedding middleware with config.
+// See: `NewLoadShedder()`.
+func LoadSheddingWithConfig(config LoadSheddingConfig) echo.MiddlewareFunc {
+	return NewLoadShedder(config).Middleware()
+}
+
+// NewLoadShedder creates LoadShedder with config. Use it instead of `LoadSheddingWithConfig` when access to shedder
+// statistics is needed. Shedding decision does not depend on request body so the middleware should be added before
+// middlewares that read the body.
+//
+// Example:
+//
+//	shedder := middleware.NewLoadShedder(middleware.LoadSheddingConfig{
+//		Classes: []middleware.LoadShedClass{
+//			{Name: "low", ShedAbove: 200, ResumeBelow: 150},
+//			{Name: "normal", ShedAbove: 400, ResumeBelow: 300},
+//			{Name: "critical"},
+//		},
+//		DefaultClass: "normal",
+//		RouteClasses: map[string]string{"/health": "critical"},
+//	})
+//	e.Use(shedder.Middleware())
+//	e.GET("/stats", func(c echo.Context) error { return c.JSON(http.StatusOK, shedder.Stats()) })
+func NewLoadShedder(config LoadSheddingConfig) *LoadShedder {
+	if config.Skipper == nil {
+		config.Skipper = DefaultLoadSheddingConfig.Skipper
+	}
+	if config.PriorityHeader == "" {
+		config.PriorityHeader = DefaultLoadSheddingConfig.PriorityHeader
+	}
+	if config.RetryAfter == 0 {
+		config.RetryAfter = DefaultLoadSheddingConfig.RetryAfter
+	}
+	if config.DenyHandler == nil {
+		config.DenyHandler = DefaultLoadSheddingConfig.DenyHandler
+	}
+	if len(config.Classes) == 0 {
+		panic("echo: load shedding middleware requires Classes")
+	}
+
+	s := &LoadShedder{
+		config: config,
+		byName: make(map[string]*loadShedClass, len(config.Classes)),
+	}
+	for _, class := range config.Classes {
+		if class.ResumeBelow == 0 {
+			class.ResumeBelow = class.ShedAbove * 0.9
+		}
+		switch {
+		case class.Name == "":
+			panic("echo: load shedding middleware requires class Name")
+		case s.byName[class.Name] != nil:
+			panic(fmt.Sprintf("echo: load shedding middleware class %q is defined more than once", class.Name))
+		case class.ShedAbove < 0 || class.ResumeBelow < 0 || class.ResumeBelow > class.ShedAbove:
+			panic(fmt.Sprintf("echo: load shedding middleware class %q requires 0 <= ResumeBelow <= ShedAbove", class.Name))
+		}
+		c := &loadShedClass{LoadShedClass: class}
+		s.classes = append(s.classes, c)
+		s.byName[class.Name] = c
+	}
+
+	s.dflt = s.classes[0]
+	if config.DefaultClass != "" {
+		if s.dflt = s.byName[config.DefaultClass]; s.dflt == nil {
+			panic(fmt.Sprintf("echo: load shedding middleware default class %q is not defined", config.DefaultClass))
+		}
+	}
+	for path, name := range config.RouteClasses {
+		if s.byName[name] == nil {
+			panic(fmt.Sprintf("echo: load shedding middleware class %q of route %s is not defined", name, path))
+		}
+	}
+	return s
+}
+
+// Stats returns current statistics of the shedder.
+func (s *LoadShedder) Stats() LoadSheddingStats {
+	stats := LoadSheddingStats{
+		Load:     math.Float64frombits(s.load.Load()),
+		InFlight: atomic.LoadInt64(&s.inFlight),
+		Classes:  make([]LoadShedClassStats, len(s.classes)),
+	}
+	for i, c := range s.classes {
+		stats.Classes[i] = LoadShedClassStats{Name: c.Name, Shedding: c.shedding.Load(), Shed: c.shed.Load()}
+	}
+	return stats
+}
+
+// Middleware returns middleware function of the shedder.
+func (s *LoadShedder) Middleware() echo.MiddlewareFunc {
+	config := s.config
+	retryAfter := strconv.FormatInt(int64(config.RetryAfter/time.Second), 10)
+
+	mw := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			class := s.classify(c)
+			if class.shouldShed(s.currentLoad()) {
+				class.shed.Add(1)
+				c.Response().Header().Set(echo.HeaderRetryAfter, retryAfter)
+				return config.DenyHandler(c, class.Name)
+			}
+
+			atomic.AddInt64(&s.inFlight, 1)
+			defer atomic.AddInt64(&s.inFlight, -1)
+			// error response is part of the load, it is written while the request is counted as in flight
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+			return err
+		}
+	}
+	if len(config.RouteClasses) > 0 {
+		return echo.RequireRoute(mw)
+	}
+	return mw
+}
+
+func (s *LoadShedder) classify(c echo.Context) *loadShedClass {
+	if s.config.ClassFunc != nil {
+		if class := s.byName[s.config.ClassFunc(c)]; class != nil {
+			return class
+		}
+		return s.dflt
+	}
+	if class := s.byName[c.Request().Header.Get(s.config.PriorityHeader)]; class != nil {
+		return class
+	}
+	if class := s.byName[s.config.RouteClasses[c.Path()]]; class != nil {
+		return class
+	}
+	return s.dflt
+}
+
+func (s *LoadShedder) currentLoad() float64 {
+	if s.config.LoadFunc == nil {
+		load := float64(atomic.LoadInt64(&s.inFlight))
+		s.load.Store(math.Float64bits(load))
+		return load
+	}
+	if s.config.LoadSampleInterval > 0 {
+		now := time.Now().UnixNano()
+		next := s.nextSample.Load()
+		if now < next || !s.nextSample.CompareAndSwap(next, now+int64(s.config.LoadSampleInterval)) {
+			return math.Float64frombits(s.load.Load())
+		}
+	}
+	load := s.config.LoadFunc()
+	s.load.Store(math.Float64bits(load))
+	return load
+}
+
+// shouldShed updates shedding state of the class with current load and reports whether request should be shed.
+func (c *loadShedClass) shouldShed(load float64) bool {
+	if c.ShedAbove <= 0 {
+		return false
+	}
+	if c.shedding.Load() {
+		if load < c.ResumeBelow {
+			c.shedding.Store(false)
+			return false
+		}
+		return true
+	}
+	if load >= c.ShedAbove {
+		c.shedding.Store(true)
+		return true
+	}
+	return false
+}
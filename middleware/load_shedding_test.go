@@ -0,0 +1,248 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadShedding_hysteresis(t *testing.T) {
+	load := 0.0
+	shedder := NewLoadShedder(LoadSheddingConfig{
+		Classes: []LoadShedClass{
+			{Name: "low", ShedAbove: 100, ResumeBelow: 50},
+			{Name: "normal", ShedAbove: 200},
+			{Name: "critical"},
+		},
+		DefaultClass: "normal",
+		LoadFunc:     func() float64 { return load },
+		RetryAfter:   5 * time.Second,
+	})
+	e := echo.New()
+	e.Use(shedder.Middleware())
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+
+	request := func(priority string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if priority != "" {
+			req.Header.Set("X-Request-Priority", priority)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	var testCases = []struct {
+		name         string
+		whenLoad     float64
+		whenPriority string
+		expectCode   int
+	}{
+		{name: "low under threshold", whenLoad: 99, whenPriority: "low", expectCode: http.StatusOK},
+		{name: "low at threshold", whenLoad: 100, whenPriority: "low", expectCode: http.StatusServiceUnavailable},
+		{name: "low still shed above resume", whenLoad: 60, whenPriority: "low", expectCode: http.StatusServiceUnavailable},
+		{name: "default class not shed", whenLoad: 60, expectCode: http.StatusOK},
+		{name: "unknown class is default", whenLoad: 199, whenPriority: "nope", expectCode: http.StatusOK},
+		{name: "low resumes", whenLoad: 49, whenPriority: "low", expectCode: http.StatusOK},
+		{name: "normal at threshold", whenLoad: 200, expectCode: http.StatusServiceUnavailable},
+		{name: "normal still shed above default resume", whenLoad: 181, whenPriority: "normal", expectCode: http.StatusServiceUnavailable},
+		{name: "critical never shed", whenLoad: 1000, whenPriority: "critical", expectCode: http.StatusOK},
+		{name: "normal resumes", whenLoad: 179, whenPriority: "normal", expectCode: http.StatusOK},
+	}
+	for _, tc := range testCases {
+		load = tc.whenLoad
+		rec := request(tc.whenPriority)
+		assert.Equal(t, tc.expectCode, rec.Code, tc.name)
+		if tc.expectCode == http.StatusServiceUnavailable {
+			assert.Equal(t, "5", rec.Header().Get(echo.HeaderRetryAfter), tc.name)
+		}
+	}
+
+	assert.Equal(t, LoadSheddingStats{
+		Load: 179,
+		Classes: []LoadShedClassStats{
+			{Name: "low", Shed: 2},
+			{Name: "normal", Shed: 2},
+			{Name: "critical"},
+		},
+	}, shedder.Stats())
+}
+
+func TestLoadShedding_routeClasses(t *testing.T) {
+	e := echo.New()
+	e.Use(LoadSheddingWithConfig(LoadSheddingConfig{
+		Classes: []LoadShedClass{
+			{Name: "low", ShedAbove: 1},
+			{Name: "critical"},
+		},
+		RouteClasses: map[string]string{"/health": "critical"},
+		LoadFunc:     func() float64 { return 10 },
+	}))
+	e.GET("/health", func(c echo.Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+	e.GET("/users", func(c echo.Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	assert.Panics(t, func() {
+		e.Pre(LoadSheddingWithConfig(LoadSheddingConfig{
+			Classes:      []LoadShedClass{{Name: "critical"}},
+			RouteClasses: map[string]string{"/health": "critical"},
+		}))
+	})
+}
+
+func TestLoadShedding_inFlightLoad(t *testing.T) {
+	shedder := NewLoadShedder(LoadSheddingConfig{
+		Classes: []LoadShedClass{{Name: "low", ShedAbove: 1}},
+	})
+	e := echo.New()
+	e.Use(shedder.Middleware())
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	e.GET("/", func(c echo.Context) error {
+		if c.QueryParam("block") != "" {
+			close(started)
+			<-release
+		}
+		return c.String(http.StatusOK, "OK")
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?block=1", nil))
+	}()
+	<-started
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, int64(1), shedder.Stats().InFlight)
+
+	close(release)
+	wg.Wait()
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestLoadShedding_returnsHandlerError(t *testing.T) {
+	shedder := NewLoadShedder(LoadSheddingConfig{
+		Classes: []LoadShedClass{{Name: "low", ShedAbove: 10}},
+	})
+	e := echo.New()
+	var outerErr error
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			outerErr = next(c)
+			return outerErr
+		}
+	})
+	e.Use(shedder.Middleware())
+	e.GET("/", func(c echo.Context) error {
+		return echo.ErrForbidden
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, echo.ErrForbidden, outerErr)
+	assert.Equal(t, int64(0), shedder.Stats().InFlight)
+}
+
+func TestLoadShedding_loadSampleInterval(t *testing.T) {
+	calls := 0
+	shedder := NewLoadShedder(LoadSheddingConfig{
+		Classes: []LoadShedClass{{Name: "low", ShedAbove: 100}},
+		LoadFunc: func() float64 {
+			calls++
+			return 42
+		},
+		LoadSampleInterval: time.Hour,
+	})
+	e := echo.New()
+	e.Use(shedder.Middleware())
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+
+	for i := 0; i < 3; i++ {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 42.0, shedder.Stats().Load)
+}
+
+func TestNewLoadShedder_panics(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenConfig LoadSheddingConfig
+		expectPanic string
+	}{
+		{
+			name:        "no classes",
+			givenConfig: LoadSheddingConfig{},
+			expectPanic: "echo: load shedding middleware requires Classes",
+		},
+		{
+			name:        "no class name",
+			givenConfig: LoadSheddingConfig{Classes: []LoadShedClass{{ShedAbove: 1}}},
+			expectPanic: "echo: load shedding middleware requires class Name",
+		},
+		{
+			name:        "duplicate class",
+			givenConfig: LoadSheddingConfig{Classes: []LoadShedClass{{Name: "low"}, {Name: "low"}}},
+			expectPanic: `echo: load shedding middleware class "low" is defined more than once`,
+		},
+		{
+			name:        "resume above shed",
+			givenConfig: LoadSheddingConfig{Classes: []LoadShedClass{{Name: "low", ShedAbove: 1, ResumeBelow: 2}}},
+			expectPanic: `echo: load shedding middleware class "low" requires 0 <= ResumeBelow <= ShedAbove`,
+		},
+		{
+			name:        "unknown default class",
+			givenConfig: LoadSheddingConfig{Classes: []LoadShedClass{{Name: "low"}}, DefaultClass: "high"},
+			expectPanic: `echo: load shedding middleware default class "high" is not defined`,
+		},
+		{
+			name: "unknown route class",
+			givenConfig: LoadSheddingConfig{
+				Classes:      []LoadShedClass{{Name: "low"}},
+				RouteClasses: map[string]string{"/": "high"},
+			},
+			expectPanic: `echo: load shedding middleware class "high" of route / is not defined`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.PanicsWithValue(t, tc.expectPanic, func() {
+				NewLoadShedder(tc.givenConfig)
+			})
+		})
+	}
+}
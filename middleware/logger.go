@@ -5,8 +5,11 @@ package middleware
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"io"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
@@ -32,6 +35,7 @@ type LoggerConfig struct {
 	// - time_rfc3339_nano
 	// - time_custom
 	// - id (Request ID)
+	// - tenant_id (Tenant ID resolved by Tenant middleware)
 	// - remote_ip
 	// - uri
 	// - host
@@ -43,14 +47,19 @@ type LoggerConfig struct {
 	// - user_agent
 	// - status
 	// - error
+	// - error_type (Type name of error returned by handler ala `*echo.HTTPError`)
 	// - latency (In nanoseconds)
 	// - latency_human (Human readable)
 	// - bytes_in (Bytes received)
 	// - bytes_out (Bytes sent)
 	// - header:<NAME>
+	// - header_out:<NAME> (Response header)
 	// - query:<NAME>
 	// - form:<NAME>
+	// - cookie:<NAME>
+	// - custom:<KEY> (Value stored in context with `c.Set(KEY, value)`)
 	// - custom (see CustomTagFunc field)
+	// - any other tag (see UnknownTagFunc field)
 	//
 	// Example "${remote_ip} ${status}"
 	//
@@ -65,10 +74,29 @@ type LoggerConfig struct {
 	// Optional.
 	CustomTagFunc func(c echo.Context, buf *bytes.Buffer) (int, error)
 
-	// Output is a writer where logs in JSON format are written.
+	// UnknownTagFunc is function called for tags that are not listed above (ala `${user_role}`) to output user
+	// implemented text by writing it to buf. Make sure that outputted text creates valid JSON string with other logged
+	// tags.
+	// Optional. Default value outputs nothing for unknown tags.
+	UnknownTagFunc func(c echo.Context, tag string, buf *bytes.Buffer) (int, error)
+
+	// Output is a writer where logs in JSON format are written. Use ReopenableWriter to be able to rotate log files
+	// without restart.
 	// Optional. Default value os.Stdout.
 	Output io.Writer
 
+	// AsyncBufferSize makes the middleware write log lines to Output from a background goroutine through a buffer of
+	// this many lines (see AsyncWriter) so slow Output does not add to request latency. Buffered lines are written
+	// when `Echo#Shutdown` is called. Each line is written whole, but lines of concurrent requests may be written in
+	// different order than the requests finished. To read the counter of dropped lines, create AsyncWriter with
+	// `NewAsyncWriter` and set it as Output instead.
+	// Optional. Default value 0 means that lines are written synchronously.
+	AsyncBufferSize int
+
+	// AsyncDropPolicy defines what happens to log lines when the async buffer is full.
+	// Optional. Default value LogBlock.
+	AsyncDropPolicy LogDropPolicy
+
 	template *fasttemplate.Template
 	colorer  *color.Color
 	pool     *sync.Pool
@@ -113,11 +141,19 @@ func LoggerWithConfig(config LoggerConfig) echo.MiddlewareFunc {
 		},
 	}
 
+	var asyncOnce sync.Once
+	var async *AsyncWriter
+
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) (err error) {
 			if config.Skipper(c) {
 				return next(c)
 			}
+			if config.AsyncBufferSize > 0 {
+				asyncOnce.Do(func() {
+					async = newLoggerAsyncWriter(c.Echo(), config)
+				})
+			}
 
 			req := c.Request()
 			res := c.Response()
@@ -159,6 +195,8 @@ func LoggerWithConfig(config LoggerConfig) echo.MiddlewareFunc {
 						id = res.Header().Get(echo.HeaderXRequestID)
 					}
 					return buf.WriteString(id)
+				case "tenant_id":
+					return buf.WriteString(TenantIDFromContext(c))
 				case "remote_ip":
 					return buf.WriteString(c.RealIP())
 				case "host":
@@ -200,6 +238,10 @@ func LoggerWithConfig(config LoggerConfig) echo.MiddlewareFunc {
 						b = b[1 : len(b)-1]
 						return buf.Write(b)
 					}
+				case "error_type":
+					if err != nil {
+						return buf.WriteString(reflect.TypeOf(err).String())
+					}
 				case "latency":
 					l := stop.Sub(start)
 					return buf.WriteString(strconv.FormatInt(int64(l), 10))
@@ -217,6 +259,8 @@ func LoggerWithConfig(config LoggerConfig) echo.MiddlewareFunc {
 					switch {
 					case strings.HasPrefix(tag, "header:"):
 						return buf.Write([]byte(c.Request().Header.Get(tag[7:])))
+					case strings.HasPrefix(tag, "header_out:"):
+						return buf.WriteString(res.Header().Get(tag[11:]))
 					case strings.HasPrefix(tag, "query:"):
 						return buf.Write([]byte(c.QueryParam(tag[6:])))
 					case strings.HasPrefix(tag, "form:"):
@@ -226,6 +270,10 @@ func LoggerWithConfig(config LoggerConfig) echo.MiddlewareFunc {
 						if err == nil {
 							return buf.Write([]byte(cookie.Value))
 						}
+					case strings.HasPrefix(tag, "custom:"):
+						return writeLogValue(buf, c.Get(tag[7:]))
+					case config.UnknownTagFunc != nil:
+						return config.UnknownTagFunc(c, tag, buf)
 					}
 				}
 				return 0, nil
@@ -233,6 +281,10 @@ func LoggerWithConfig(config LoggerConfig) echo.MiddlewareFunc {
 				return
 			}
 
+			if async != nil {
+				_, err = async.Write(buf.Bytes())
+				return
+			}
 			if config.Output == nil {
 				_, err = c.Logger().Output().Write(buf.Bytes())
 				return
@@ -242,3 +294,42 @@ func LoggerWithConfig(config LoggerConfig) echo.MiddlewareFunc {
 		}
 	}
 }
+
+// writeLogValue writes value stored in context to buf. Common types are written without allocating.
+func writeLogValue(buf *bytes.Buffer, v interface{}) (int, error) {
+	var num [24]byte
+	switch tv := v.(type) {
+	case nil:
+		return 0, nil
+	case string:
+		return buf.WriteString(tv)
+	case []byte:
+		return buf.Write(tv)
+	case int:
+		return buf.Write(strconv.AppendInt(num[:0], int64(tv), 10))
+	case int64:
+		return buf.Write(strconv.AppendInt(num[:0], tv, 10))
+	case uint64:
+		return buf.Write(strconv.AppendUint(num[:0], tv, 10))
+	case bool:
+		return buf.Write(strconv.AppendBool(num[:0], tv))
+	case fmt.Stringer:
+		return buf.WriteString(tv.String())
+	case error:
+		return buf.WriteString(tv.Error())
+	}
+	return fmt.Fprint(buf, v)
+}
+
+// newLoggerAsyncWriter creates AsyncWriter for Output (or Echo logger output) that is closed by `Echo#Shutdown`.
+func newLoggerAsyncWriter(e *echo.Echo, config LoggerConfig) *AsyncWriter {
+	output := config.Output
+	if output == nil {
+		output = e.Logger.Output()
+	}
+	async := NewAsyncWriter(output, config.AsyncBufferSize, config.AsyncDropPolicy)
+	e.OnShutdown(func(ctx context.Context) error {
+		return async.Close()
+	})
+	return async
+}
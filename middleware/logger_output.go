@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// LogDropPolicy defines what AsyncWriter does with a line when its buffer is full.
+type LogDropPolicy int
+
+const (
+	// LogBlock makes Write wait until there is room in the buffer.
+	LogBlock LogDropPolicy = iota
+	// LogDropOldest discards the oldest buffered line to make room for the new one.
+	LogDropOldest
+	// LogDropNew discards the line being written.
+	LogDropNew
+)
+
+// defaultAsyncWriterSize is number of lines AsyncWriter buffers when size is not set.
+const defaultAsyncWriterSize = 1024
+
+// ErrAsyncWriterClosed is returned by `AsyncWriter#Write` after the writer has been closed.
+var ErrAsyncWriterClosed = errors.New("async writer is closed")
+
+// AsyncWriter is io.Writer that buffers writes in bounded ring buffer and writes them to underlying writer from single
+// background goroutine so slow writers (ala network sinks) do not add to request latency. Each Write is kept whole
+// and written with single Write call to the underlying writer. Writes are written in the order they were made, but as
+// writing is delayed, lines of concurrent requests may interleave differently with output of other writers.
+type AsyncWriter struct {
+	w      io.Writer
+	policy LogDropPolicy
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	idle     *sync.Cond
+	lines    [][]byte
+	head     int
+	count    int
+	writing  bool
+	closed   bool
+	done     chan struct{}
+
+	dropped uint64
+	failed  uint64
+}
+
+// NewAsyncWriter creates AsyncWriter that buffers up to size writes (default 1024 when size <= 0) and starts its
+// writer goroutine. Call Close to write buffered lines and stop the goroutine.
+func NewAsyncWriter(w io.Writer, size int, policy LogDropPolicy) *AsyncWriter {
+	if size <= 0 {
+		size = defaultAsyncWriterSize
+	}
+	a := &AsyncWriter{
+		w:      w,
+		policy: policy,
+		lines:  make([][]byte, size),
+		done:   make(chan struct{}),
+	}
+	a.notEmpty = sync.NewCond(&a.mu)
+	a.notFull = sync.NewCond(&a.mu)
+	a.idle = sync.NewCond(&a.mu)
+	go a.run()
+	return a
+}
+
+// Write buffers copy of p. Returned error is ErrAsyncWriterClosed after Close; errors of the underlying writer are
+// only counted (see `AsyncWriter#Failed`).
+func (a *AsyncWriter) Write(p []byte) (int, error) {
+	line := make([]byte, len(p))
+	copy(line, p)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for !a.closed && a.count == len(a.lines) {
+		switch a.policy {
+		case LogDropOldest:
+			a.lines[a.head] = nil
+			a.head = (a.head + 1) % len(a.lines)
+			a.count--
+			atomic.AddUint64(&a.dropped, 1)
+		case LogDropNew:
+			atomic.AddUint64(&a.dropped, 1)
+			return len(p), nil
+		default:
+			a.notFull.Wait()
+		}
+	}
+	if a.closed {
+		return 0, ErrAsyncWriterClosed
+	}
+	a.lines[(a.head+a.count)%len(a.lines)] = line
+	a.count++
+	a.notEmpty.Signal()
+	return len(p), nil
+}
+
+func (a *AsyncWriter) run() {
+	defer close(a.done)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for {
+		for a.count == 0 && !a.closed {
+			a.notEmpty.Wait()
+		}
+		if a.count == 0 {
+			return
+		}
+		line := a.lines[a.head]
+		a.lines[a.head] = nil
+		a.head = (a.head + 1) % len(a.lines)
+		a.count--
+		a.writing = true
+		a.notFull.Signal()
+		a.mu.Unlock()
+
+		if _, err := a.w.Write(line); err != nil {
+			atomic.AddUint64(&a.failed, 1)
+		}
+
+		a.mu.Lock()
+		a.writing = false
+		if a.count == 0 {
+			a.idle.Broadcast()
+		}
+	}
+}
+
+// Flush waits until all buffered lines have been written to the underlying writer.
+func (a *AsyncWriter) Flush() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for a.count > 0 || a.writing {
+		a.idle.Wait()
+	}
+}
+
+// Close stops accepting writes, waits until buffered lines have been written and stops the writer goroutine. The
+// underlying writer is not closed.
+func (a *AsyncWriter) Close() error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		a.notEmpty.Broadcast()
+		a.notFull.Broadcast()
+	}
+	a.mu.Unlock()
+	<-a.done
+	return nil
+}
+
+// Dropped returns number of lines discarded because the buffer was full.
+func (a *AsyncWriter) Dropped() uint64 {
+	return atomic.LoadUint64(&a.dropped)
+}
+
+// Failed returns number of lines the underlying writer failed to write.
+func (a *AsyncWriter) Failed() uint64 {
+	return atomic.LoadUint64(&a.failed)
+}
+
+// ReopenableWriter is io.Writer that can replace its underlying writer without restart. It is used for log rotation:
+// after the log file has been moved by logrotate (or similar), call Reopen (ala on SIGHUP) to continue writing to a
+// new file with the original name.
+//
+// Example:
+//
+//	w, err := middleware.NewReopenableFileWriter("/var/log/app/access.log")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	go func() {
+//		hup := make(chan os.Signal, 1)
+//		signal.Notify(hup, syscall.SIGHUP)
+//		for range hup {
+//			if err := w.Reopen(); err != nil {
+//				e.Logger.Error(err)
+//			}
+//		}
+//	}()
+//	e.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{Output: w}))
+type ReopenableWriter struct {
+	open func() (io.WriteCloser, error)
+
+	mu sync.Mutex
+	w  io.WriteCloser
+}
+
+// NewReopenableWriter creates ReopenableWriter that gets its writer from the open function. Open is called
+// immediately and on every Reopen.
+func NewReopenableWriter(open func() (io.WriteCloser, error)) (*ReopenableWriter, error) {
+	w, err := open()
+	if err != nil {
+		return nil, err
+	}
+	return &ReopenableWriter{open: open, w: w}, nil
+}
+
+// NewReopenableFileWriter creates ReopenableWriter that appends to file at path. File is created when it does not
+// exist.
+func NewReopenableFileWriter(path string) (*ReopenableWriter, error) {
+	return NewReopenableWriter(func() (io.WriteCloser, error) {
+		return os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	})
+}
+
+// Write writes p to the current writer.
+func (r *ReopenableWriter) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.w.Write(p)
+}
+
+// Reopen opens new writer and closes the previous one. When opening fails the previous writer is kept.
+func (r *ReopenableWriter) Reopen() error {
+	w, err := r.open()
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	old := r.w
+	r.w = w
+	r.mu.Unlock()
+	return old.Close()
+}
+
+// Close closes the current writer.
+func (r *ReopenableWriter) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.w.Close()
+}
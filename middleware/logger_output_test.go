@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingWriter blocks each Write until release is closed.
+type blockingWriter struct {
+	started chan struct{}
+	release chan struct{}
+	once    sync.Once
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func newBlockingWriter() *blockingWriter {
+	return &blockingWriter{started: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { close(w.started) })
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *blockingWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestAsyncWriter_dropPolicy(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		givenPolicy   LogDropPolicy
+		expect        string
+		expectDropped uint64
+	}{
+		{
+			name:          "drop oldest",
+			givenPolicy:   LogDropOldest,
+			expect:        "1\n3\n4\n",
+			expectDropped: 1,
+		},
+		{
+			name:          "drop new",
+			givenPolicy:   LogDropNew,
+			expect:        "1\n2\n3\n",
+			expectDropped: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := newBlockingWriter()
+			a := NewAsyncWriter(w, 2, tc.givenPolicy)
+
+			_, _ = a.Write([]byte("1\n"))
+			<-w.started // writer goroutine holds line 1, buffer is empty
+			for _, line := range []string{"2\n", "3\n", "4\n"} {
+				n, err := a.Write([]byte(line))
+				assert.NoError(t, err)
+				assert.Equal(t, len(line), n)
+			}
+			close(w.release)
+			assert.NoError(t, a.Close())
+
+			assert.Equal(t, tc.expect, w.String())
+			assert.Equal(t, tc.expectDropped, a.Dropped())
+		})
+	}
+}
+
+func TestAsyncWriter_blockPolicy(t *testing.T) {
+	w := newBlockingWriter()
+	a := NewAsyncWriter(w, 1, LogBlock)
+
+	_, _ = a.Write([]byte("1\n"))
+	<-w.started
+	_, _ = a.Write([]byte("2\n"))
+	written := make(chan struct{})
+	go func() {
+		_, _ = a.Write([]byte("3\n"))
+		close(written)
+	}()
+
+	select {
+	case <-written:
+		t.Fatal("write must block while buffer is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(w.release)
+	<-written
+	a.Flush()
+
+	assert.Equal(t, "1\n2\n3\n", w.String())
+	assert.Equal(t, uint64(0), a.Dropped())
+	assert.NoError(t, a.Close())
+	_, err := a.Write([]byte("4\n"))
+	assert.ErrorIs(t, err, ErrAsyncWriterClosed)
+}
+
+func TestAsyncWriter_copiesLine(t *testing.T) {
+	w := newBlockingWriter()
+	a := NewAsyncWriter(w, 0, LogBlock)
+
+	line := []byte("abc\n")
+	_, _ = a.Write(line)
+	copy(line, "xyz\n")
+	close(w.release)
+	assert.NoError(t, a.Close())
+
+	assert.Equal(t, "abc\n", w.String())
+}
+
+func TestReopenableFileWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	w, err := NewReopenableFileWriter(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = w.Write([]byte("first\n"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.Rename(path, path+".1")) // rotated by logrotate
+	assert.NoError(t, w.Reopen())
+	_, err = w.Write([]byte("second\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	rotated, _ := os.ReadFile(path + ".1")
+	assert.Equal(t, "first\n", string(rotated))
+	current, _ := os.ReadFile(path)
+	assert.Equal(t, "second\n", string(current))
+}
+
+func TestLoggerAsync(t *testing.T) {
+	e := echo.New()
+	w := newBlockingWriter()
+	e.Use(LoggerWithConfig(LoggerConfig{
+		Format:          "${method} ${uri} ${status}\n",
+		Output:          w,
+		AsyncBufferSize: 10,
+	}))
+	e.GET("/*", func(c echo.Context) error {
+		return c.String(http.StatusOK, "OK")
+	})
+
+	for _, uri := range []string{"/a", "/b"} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, uri, nil))
+		assert.Equal(t, http.StatusOK, rec.Code, "response must not wait for the log writer")
+	}
+	assert.Equal(t, "", w.String())
+
+	close(w.release)
+	assert.NoError(t, e.Shutdown(context.Background()))
+
+	lines := strings.Split(strings.TrimSpace(w.String()), "\n")
+	assert.Equal(t, []string{"GET /a 200", "GET /b 200"}, lines)
+}
@@ -199,6 +199,56 @@ func TestLoggerCustomTagFunc(t *testing.T) {
 	assert.Equal(t, `{"method":"GET","tag":"my-value"}`+"\n", buf.String())
 }
 
+func TestLoggerContextAndResponseTags(t *testing.T) {
+	var testCases = []struct {
+		name    string
+		whenURL string
+		expect  string
+	}{
+		{
+			name:    "ok, handler succeeds",
+			whenURL: "/users/1",
+			expect:  `{"route":"/users/:id","tenant":"acme","n":42,"cache":"HIT","role":"admin","err_type":"","missing":""}` + "\n",
+		},
+		{
+			name:    "ok, handler errors",
+			whenURL: "/users/0",
+			expect:  `{"route":"/users/:id","tenant":"acme","n":42,"cache":"","role":"admin","err_type":"*echo.HTTPError","missing":""}` + "\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			buf := new(bytes.Buffer)
+			e.Use(LoggerWithConfig(LoggerConfig{
+				Format: `{"route":"${route}","tenant":"${custom:tenant_id}","n":${custom:n},"cache":"${header_out:X-Cache}",` +
+					`"role":"${user_role}","err_type":"${error_type}","missing":"${custom:missing}"}` + "\n",
+				UnknownTagFunc: func(c echo.Context, tag string, buf *bytes.Buffer) (int, error) {
+					if tag == "user_role" {
+						return buf.WriteString("admin")
+					}
+					return 0, nil
+				},
+				Output: buf,
+			}))
+			e.GET("/users/:id", func(c echo.Context) error {
+				c.Set("tenant_id", "acme")
+				c.Set("n", 42)
+				if c.Param("id") == "0" {
+					return echo.ErrNotFound
+				}
+				c.Response().Header().Set("X-Cache", "HIT")
+				return c.String(http.StatusOK, "OK")
+			})
+
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tc.whenURL, nil))
+
+			assert.Equal(t, tc.expect, buf.String())
+		})
+	}
+}
+
 func BenchmarkLoggerWithConfig_withoutMapFields(b *testing.B) {
 	e := echo.New()
 
@@ -12,6 +12,9 @@ import (
 	"net/http/httptest"
 	"regexp"
 	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
 )
 
 func TestRewriteURL(t *testing.T) {
@@ -139,3 +142,36 @@ type testResponseWriterUnwrapperHijack struct {
 func (w *testResponseWriterUnwrapperHijack) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	return nil, nil, errors.New("can hijack")
 }
+
+func TestResponseController_throughMiddlewareStack(t *testing.T) {
+	e := echo.New()
+	e.Use(Gzip())
+	e.Use(BodyDump(func(c echo.Context, reqBody, resBody []byte) {}))
+	e.Use(ContextTimeout(time.Minute))
+
+	e.GET("/", func(c echo.Context) error {
+		rc := http.NewResponseController(c.Response())
+		if err := rc.SetWriteDeadline(time.Now().Add(time.Hour)); err != nil {
+			return err
+		}
+		if err := rc.SetReadDeadline(time.Now().Add(time.Hour)); err != nil {
+			return err
+		}
+		if err := rc.EnableFullDuplex(); err != nil {
+			return err
+		}
+		return c.String(http.StatusOK, "OK")
+	})
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	res, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
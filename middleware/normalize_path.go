@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// NormalizePathConfig defines the config for NormalizePath middleware.
+type NormalizePathConfig struct {
+	// Skipper defines a function to skip middleware.
+	Skipper Skipper
+
+	// CollapseSlashes replaces consecutive slashes in the path with single slash (`/api//users` becomes `/api/users`).
+	// Optional. Default value true when config is created with `NormalizePath()`.
+	CollapseSlashes bool `yaml:"collapse_slashes"`
+
+	// RemoveDotSegments resolves `.` and `..` segments of the path (`/api/./users/../groups` becomes `/api/groups`).
+	// Requests with `..` segments that would escape the root are rejected with "400 - Bad Request".
+	// Optional. Default value true when config is created with `NormalizePath()`.
+	RemoveDotSegments bool `yaml:"remove_dot_segments"`
+
+	// RedirectCode is status code used to redirect request to the normalized path. Use http.StatusPermanentRedirect
+	// (308) so clients keep the method and the body of the request.
+	// Optional. Default value 0 rewrites the request path silently without redirect.
+	RedirectCode int `yaml:"redirect_code"`
+}
+
+// DefaultNormalizePathConfig is the default NormalizePath middleware config.
+var DefaultNormalizePathConfig = NormalizePathConfig{
+	Skipper:           DefaultSkipper,
+	CollapseSlashes:   true,
+	RemoveDotSegments: true,
+}
+
+// errPathEscapesRoot is internal error of request with `..` segments escaping the root.
+var errPathEscapesRoot = errors.New("path escapes root with dot segments")
+
+// NormalizePath returns a root level (before router) middleware which collapses duplicate slashes and resolves dot
+// segments of the request path so `/api//users/./1` matches route `/api/users/1`.
+//
+// Normalization is done on the escaped path (`URL#EscapedPath()`) so encoded characters are not decoded twice and an
+// encoded slash (`%2F`) is never treated as path separator, which keeps `Echo#UseEncodedPath` path parameters
+// intact. Segments `%2E` and `%2E%2E` are treated as dot segments.
+//
+// Usage `Echo#Pre(NormalizePath())`
+func NormalizePath() echo.MiddlewareFunc {
+	return NormalizePathWithConfig(DefaultNormalizePathConfig)
+}
+
+// NormalizePathWithConfig returns a NormalizePath middleware with config.
+// See `NormalizePath()`.
+func NormalizePathWithConfig(config NormalizePathConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = DefaultNormalizePathConfig.Skipper
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			req := c.Request()
+			u := req.URL
+			original := u.EscapedPath()
+			escapedPath, err := normalizeEscapedPath(original, config.CollapseSlashes, config.RemoveDotSegments)
+			if err != nil {
+				return echo.ErrBadRequest.WithInternal(err)
+			}
+			if escapedPath == original {
+				return next(c)
+			}
+			path, err := url.PathUnescape(escapedPath)
+			if err != nil {
+				return next(c)
+			}
+			uri := escapedPath
+			if u.RawQuery != "" || u.ForceQuery {
+				uri += "?" + u.RawQuery
+			}
+
+			if config.RedirectCode != 0 {
+				return c.Redirect(config.RedirectCode, sanitizeEscapedURI(uri))
+			}
+
+			req.RequestURI = uri
+			u.Path = path
+			u.RawPath = ""
+			if u.EscapedPath() != escapedPath {
+				u.RawPath = escapedPath
+			}
+			return next(c)
+		}
+	}
+}
+
+// normalizeEscapedPath collapses empty segments and/or resolves dot segments of escaped path. Trailing slash is kept
+// and path ending with dot segment gets trailing slash (`/a/b/..` becomes `/a/`) as described in RFC 3986 5.2.4.
+func normalizeEscapedPath(escapedPath string, collapseSlashes bool, removeDotSegments bool) (string, error) {
+	if !strings.HasPrefix(escapedPath, "/") || (!collapseSlashes && !removeDotSegments) {
+		return escapedPath, nil
+	}
+	segments := strings.Split(escapedPath[1:], "/")
+	result := make([]string, 0, len(segments))
+	for i, segment := range segments {
+		isLast := i == len(segments)-1
+		switch {
+		case segment == "" && collapseSlashes && !isLast:
+			continue
+		case removeDotSegments && isDotSegment(segment):
+		case removeDotSegments && isDotDotSegment(segment):
+			if len(result) == 0 {
+				return "", errPathEscapesRoot
+			}
+			result = result[:len(result)-1]
+		default:
+			result = append(result, segment)
+			continue
+		}
+		if isLast {
+			result = append(result, "")
+		}
+	}
+	return "/" + strings.Join(result, "/"), nil
+}
+
+func isDotSegment(segment string) bool {
+	return segment == "." || strings.EqualFold(segment, "%2E")
+}
+
+func isDotDotSegment(segment string) bool {
+	switch len(segment) {
+	case 2:
+		return segment == ".."
+	case 4:
+		return strings.EqualFold(segment, ".%2E") || strings.EqualFold(segment, "%2E.")
+	case 6:
+		return strings.EqualFold(segment, "%2E%2E")
+	}
+	return false
+}
@@ -0,0 +1,222 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePath(t *testing.T) {
+	var testCases = []struct {
+		name          string
+		givenConfig   *NormalizePathConfig
+		whenURL       string
+		expectCode    int
+		expectBody    string
+		expectPath    string
+		expectRawPath string
+		expectURI     string
+	}{
+		{
+			name:       "ok, duplicate slashes and dot segments",
+			whenURL:    "/api//users/./1",
+			expectCode: http.StatusOK,
+			expectBody: "/api/users/:id",
+			expectPath: "/api/users/1",
+			expectURI:  "/api/users/1",
+		},
+		{
+			name:       "ok, dot dot segment with query",
+			whenURL:    "/api/groups/../users/1?x=1",
+			expectCode: http.StatusOK,
+			expectBody: "/api/users/:id",
+			expectPath: "/api/users/1",
+			expectURI:  "/api/users/1?x=1",
+		},
+		{
+			name:       "ok, encoded dot segments",
+			whenURL:    "/api/%2e/users/x/%2E%2e/1",
+			expectCode: http.StatusOK,
+			expectBody: "/api/users/:id",
+			expectPath: "/api/users/1",
+			expectURI:  "/api/users/1",
+		},
+		{
+			name:       "ok, encoded characters are not decoded twice",
+			whenURL:    "/api//users/a%252Fb",
+			expectCode: http.StatusOK,
+			expectBody: "/api/users/:id",
+			expectPath: "/api/users/a%2Fb",
+			expectURI:  "/api/users/a%252Fb",
+		},
+		{
+			name:          "ok, encoded slash is not collapsed",
+			whenURL:       "/api//users/a%2F%2Fb",
+			expectCode:    http.StatusOK,
+			expectBody:    "/api/users/:id",
+			expectPath:    "/api/users/a//b",
+			expectRawPath: "/api/users/a%2F%2Fb",
+			expectURI:     "/api/users/a%2F%2Fb",
+		},
+		{
+			name:       "ok, trailing slash is kept",
+			whenURL:    "/api//users/",
+			expectCode: http.StatusOK,
+			expectBody: "/api/users/",
+			expectPath: "/api/users/",
+			expectURI:  "/api/users/",
+		},
+		{
+			name:       "ok, path ending with dot segment gets trailing slash",
+			whenURL:    "/api/users/1/..",
+			expectCode: http.StatusOK,
+			expectBody: "/api/users/",
+			expectPath: "/api/users/",
+			expectURI:  "/api/users/",
+		},
+		{
+			name:       "ok, normalized path is not changed",
+			whenURL:    "/api/users/1",
+			expectCode: http.StatusOK,
+			expectBody: "/api/users/:id",
+			expectPath: "/api/users/1",
+			expectURI:  "/api/users/1",
+		},
+		{
+			name:       "nok, escaping root",
+			whenURL:    "/api/../../etc/passwd",
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:       "nok, escaping root with encoded dots",
+			whenURL:    "/%2e%2e/etc/passwd",
+			expectCode: http.StatusBadRequest,
+		},
+		{
+			name:        "ok, only collapse slashes",
+			givenConfig: &NormalizePathConfig{CollapseSlashes: true},
+			whenURL:     "/api//users/./1",
+			expectCode:  http.StatusOK,
+			expectBody:  "/api/users/:id",
+			expectPath:  "/api/users/./1",
+			expectURI:   "/api/users/./1",
+		},
+		{
+			name:        "ok, only remove dot segments",
+			givenConfig: &NormalizePathConfig{RemoveDotSegments: true},
+			whenURL:     "/api/./users/1",
+			expectCode:  http.StatusOK,
+			expectBody:  "/api/users/:id",
+			expectPath:  "/api/users/1",
+			expectURI:   "/api/users/1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			mw := NormalizePath()
+			if tc.givenConfig != nil {
+				mw = NormalizePathWithConfig(*tc.givenConfig)
+			}
+			e.Pre(mw)
+			var req *http.Request
+			handler := func(c echo.Context) error {
+				req = c.Request()
+				return c.String(http.StatusOK, c.Path())
+			}
+			e.GET("/api/users/:id", handler)
+			e.GET("/api/users/", handler)
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.whenURL, nil))
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			if tc.expectCode != http.StatusOK {
+				return
+			}
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+			assert.Equal(t, tc.expectPath, req.URL.Path)
+			assert.Equal(t, tc.expectRawPath, req.URL.RawPath)
+			assert.Equal(t, tc.expectURI, req.RequestURI)
+		})
+	}
+}
+
+func TestNormalizePath_redirect(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		whenMethod     string
+		whenURL        string
+		expectCode     int
+		expectLocation string
+	}{
+		{
+			name:           "ok, redirect with query",
+			whenMethod:     http.MethodGet,
+			whenURL:        "/api//users/./1?x=1",
+			expectCode:     http.StatusPermanentRedirect,
+			expectLocation: "/api/users/1?x=1",
+		},
+		{
+			name:           "ok, redirect POST",
+			whenMethod:     http.MethodPost,
+			whenURL:        "/api/users/2/../1",
+			expectCode:     http.StatusPermanentRedirect,
+			expectLocation: "/api/users/1",
+		},
+		{
+			name:       "ok, normalized path is not redirected",
+			whenMethod: http.MethodGet,
+			whenURL:    "/api/users/1",
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "nok, escaping root is not redirected",
+			whenMethod: http.MethodGet,
+			whenURL:    "/..",
+			expectCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Pre(NormalizePathWithConfig(NormalizePathConfig{
+				CollapseSlashes:   true,
+				RemoveDotSegments: true,
+				RedirectCode:      http.StatusPermanentRedirect,
+			}))
+			e.Any("/api/users/:id", func(c echo.Context) error {
+				return c.String(http.StatusOK, c.Param("id"))
+			})
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(tc.whenMethod, tc.whenURL, nil))
+
+			assert.Equal(t, tc.expectCode, rec.Code)
+			assert.Equal(t, tc.expectLocation, rec.Header().Get(echo.HeaderLocation))
+		})
+	}
+}
+
+func TestNormalizePath_encodedPath(t *testing.T) {
+	e := echo.New()
+	e.UseEncodedPath = true
+	e.Pre(NormalizePath())
+	e.GET("/files/:name/meta", func(c echo.Context) error {
+		return c.String(http.StatusOK, c.Param("name"))
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/files//./a%2Fb/meta", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "a/b", rec.Body.String())
+}
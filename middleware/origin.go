@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Origin is parsed value of the `Origin` header or an allowed origin pattern.
+type Origin struct {
+	// Scheme is lower case scheme of the origin (ala `https`)
+	Scheme string
+	// Host is lower case host of the origin without trailing dot. IPv6 addresses do not have brackets.
+	Host string
+	// Port is explicit port of the origin or default port for `http` and `https` schemes. Empty when the port is
+	// unknown. For patterns `*` means any port.
+	Port string
+}
+
+// maxOriginLength limits origin length we are willing to parse (253 is domain name max limit)
+const maxOriginLength = 253 + len("https://") + len(":65535")
+
+var errInvalidOrigin = errors.New("invalid origin")
+
+// ParseOrigin parses origin in form of `scheme://host[:port]`. Scheme and host are compared case-insensitively so
+// they are converted to lower case and trailing dot is removed from the host. Missing port is filled with the default
+// port of `http` and `https` schemes.
+func ParseOrigin(origin string) (Origin, error) {
+	if len(origin) > maxOriginLength {
+		return Origin{}, errInvalidOrigin
+	}
+	scheme, authority, ok := strings.Cut(origin, "://")
+	if !ok || scheme == "" || authority == "" || strings.ContainsAny(authority, "/?#@\\ ") {
+		return Origin{}, errInvalidOrigin
+	}
+
+	host, port := authority, ""
+	if i := strings.LastIndexByte(authority, ':'); i != -1 && !strings.HasSuffix(authority, "]") {
+		h, p, err := net.SplitHostPort(authority)
+		if err != nil || p == "" {
+			return Origin{}, errInvalidOrigin
+		}
+		host, port = h, p
+	} else if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		host = host[1 : len(host)-1]
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" {
+		return Origin{}, errInvalidOrigin
+	}
+
+	o := Origin{Scheme: strings.ToLower(scheme), Host: host, Port: port}
+	if o.Port == "" {
+		o.Port = defaultPort(o.Scheme)
+	}
+	return o, nil
+}
+
+func defaultPort(scheme string) string {
+	switch scheme {
+	case "http", "ws":
+		return "80"
+	case "https", "wss":
+		return "443"
+	}
+	return ""
+}
+
+// originPattern is single allowed origin parsed from `scheme://host[:port]`, `scheme://*.domain[:port]` or
+// `scheme://host:*` form.
+type originPattern struct {
+	Origin
+	// subdomainOf is set for `*.domain` patterns and contains `.domain` suffix that host must end with.
+	subdomainOf string
+}
+
+func parseOriginPattern(pattern string) (originPattern, error) {
+	anyPort := false
+	if strings.HasSuffix(pattern, ":*") {
+		anyPort = true
+		pattern = pattern[:len(pattern)-2]
+	}
+	subdomain := false
+	if scheme, rest, ok := strings.Cut(pattern, "://*."); ok {
+		subdomain = true
+		pattern = scheme + "://" + rest
+	}
+	o, err := ParseOrigin(pattern)
+	if err != nil || strings.ContainsAny(o.Host, "*?") {
+		return originPattern{}, fmt.Errorf("invalid origin pattern: %v", pattern)
+	}
+	if anyPort {
+		o.Port = "*"
+	}
+	p := originPattern{Origin: o}
+	if subdomain {
+		p.subdomainOf = "." + o.Host
+	}
+	return p, nil
+}
+
+func (p originPattern) match(o Origin) bool {
+	if p.Scheme != o.Scheme {
+		return false
+	}
+	if p.Port != "*" && p.Port != o.Port {
+		return false
+	}
+	if p.subdomainOf == "" {
+		return p.Host == o.Host
+	}
+	// host must have at least one label in front of the domain so `evilexample.com` or `example.com` does not match
+	// `*.example.com`
+	return len(o.Host) > len(p.subdomainOf) && strings.HasSuffix(o.Host, p.subdomainOf)
+}
+
+// OriginMatcher checks origins against list of allowed origins. Supported forms of allowed origins are:
+//   - `*` matches any origin
+//   - `https://example.com` matches exactly that origin (port defaults to scheme default port)
+//   - `https://*.example.com` matches any subdomain of `example.com` but not `example.com` itself
+//   - `https://*.example.com:8443` or `https://example.com:*` for explicit or any port
+//
+// Scheme and host are compared case-insensitively and trailing dot of the host is ignored.
+type OriginMatcher struct {
+	patterns []originPattern
+	any      bool
+}
+
+// NewOriginMatcher creates OriginMatcher for given allowed origins. Error is returned for origins that can not be
+// parsed.
+func NewOriginMatcher(allowOrigins []string) (*OriginMatcher, error) {
+	m := &OriginMatcher{patterns: make([]originPattern, 0, len(allowOrigins))}
+	for _, origin := range allowOrigins {
+		if origin == "*" {
+			m.any = true
+			continue
+		}
+		p, err := parseOriginPattern(origin)
+		if err != nil {
+			return nil, err
+		}
+		m.patterns = append(m.patterns, p)
+	}
+	return m, nil
+}
+
+// Match returns true when origin matches any of the allowed origins.
+func (m *OriginMatcher) Match(origin string) bool {
+	if m.any {
+		return true
+	}
+	o, err := ParseOrigin(origin)
+	if err != nil {
+		return false
+	}
+	for _, p := range m.patterns {
+		if p.match(o) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOrigin(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenOrigin  string
+		expect      Origin
+		expectError bool
+	}{
+		{
+			name:       "ok, default port",
+			whenOrigin: "HTTPS://Example.COM",
+			expect:     Origin{Scheme: "https", Host: "example.com", Port: "443"},
+		},
+		{
+			name:       "ok, explicit port and trailing dot",
+			whenOrigin: "http://example.com.:8080",
+			expect:     Origin{Scheme: "http", Host: "example.com", Port: "8080"},
+		},
+		{
+			name:       "ok, ipv6",
+			whenOrigin: "http://[::1]:8080",
+			expect:     Origin{Scheme: "http", Host: "::1", Port: "8080"},
+		},
+		{
+			name:       "ok, ipv6 without port",
+			whenOrigin: "http://[::1]",
+			expect:     Origin{Scheme: "http", Host: "::1", Port: "80"},
+		},
+		{
+			name:        "nok, no scheme",
+			whenOrigin:  "example.com",
+			expectError: true,
+		},
+		{
+			name:        "nok, path",
+			whenOrigin:  "http://example.com/path",
+			expectError: true,
+		},
+		{
+			name:        "nok, userinfo",
+			whenOrigin:  "http://example.com@evil.com",
+			expectError: true,
+		},
+		{
+			name:        "nok, empty port",
+			whenOrigin:  "http://example.com:",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			o, err := ParseOrigin(tc.whenOrigin)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, o)
+		})
+	}
+}
+
+func TestOriginMatcher_Match(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		givenAllow []string
+		whenOrigin string
+		expect     bool
+	}{
+		{name: "ok, any", givenAllow: []string{"*"}, whenOrigin: "http://example.com", expect: true},
+		{name: "ok, exact", givenAllow: []string{"https://example.com"}, whenOrigin: "https://example.com", expect: true},
+		{name: "ok, exact case insensitive", givenAllow: []string{"https://example.com"}, whenOrigin: "https://EXAMPLE.com", expect: true},
+		{name: "ok, exact with default port", givenAllow: []string{"https://example.com"}, whenOrigin: "https://example.com:443", expect: true},
+		{name: "ok, exact with trailing dot", givenAllow: []string{"https://example.com"}, whenOrigin: "https://example.com.", expect: true},
+		{name: "nok, exact other scheme", givenAllow: []string{"https://example.com"}, whenOrigin: "http://example.com", expect: false},
+		{name: "nok, exact other port", givenAllow: []string{"https://example.com"}, whenOrigin: "https://example.com:8443", expect: false},
+		{name: "ok, subdomain", givenAllow: []string{"https://*.example.com"}, whenOrigin: "https://api.example.com", expect: true},
+		{name: "ok, nested subdomain", givenAllow: []string{"https://*.example.com"}, whenOrigin: "https://a.b.example.com", expect: true},
+		{name: "ok, subdomain with explicit port", givenAllow: []string{"https://*.example.com:8443"}, whenOrigin: "https://api.example.com:8443", expect: true},
+		{name: "ok, subdomain with any port", givenAllow: []string{"https://*.example.com:*"}, whenOrigin: "https://api.example.com:9000", expect: true},
+		{name: "ok, any port", givenAllow: []string{"http://localhost:*"}, whenOrigin: "http://localhost:3000", expect: true},
+		{name: "nok, subdomain with other port", givenAllow: []string{"https://*.example.com"}, whenOrigin: "https://api.example.com:8443", expect: false},
+		{name: "nok, subdomain pattern does not match domain itself", givenAllow: []string{"https://*.example.com"}, whenOrigin: "https://example.com", expect: false},
+		{name: "nok, suffix confusion", givenAllow: []string{"https://*.example.com"}, whenOrigin: "https://evilexample.com", expect: false},
+		{name: "nok, suffix confusion with exact", givenAllow: []string{"https://example.com"}, whenOrigin: "https://evilexample.com", expect: false},
+		{name: "nok, domain as subdomain of evil", givenAllow: []string{"https://*.example.com"}, whenOrigin: "https://example.com.evil.com", expect: false},
+		{name: "nok, userinfo trick", givenAllow: []string{"https://*.example.com"}, whenOrigin: "https://evil.com@a.example.com", expect: false},
+		{name: "nok, path trick", givenAllow: []string{"https://*.example.com"}, whenOrigin: "https://evil.com/.example.com", expect: false},
+		{name: "nok, invalid origin", givenAllow: []string{"https://*.example.com"}, whenOrigin: "null", expect: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := NewOriginMatcher(tc.givenAllow)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, m.Match(tc.whenOrigin))
+		})
+	}
+}
+
+func TestNewOriginMatcher_invalidPattern(t *testing.T) {
+	_, err := NewOriginMatcher([]string{"https://a?.example.com"})
+	assert.EqualError(t, err, "invalid origin pattern: https://a?.example.com")
+}
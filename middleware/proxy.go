@@ -84,6 +84,11 @@ type ProxyConfig struct {
 
 	// ModifyResponse defines function to modify response from ProxyTarget.
 	ModifyResponse func(*http.Response) error
+
+	// Hedge enables request hedging: when the target has not responded within the delay, the request is sent also to
+	// the next target and the first response wins. See ProxyHedgeConfig.
+	// Optional. Default value nil (no hedging).
+	Hedge *ProxyHedgeConfig
 }
 
 // ProxyTarget defines the upstream target.
@@ -311,6 +316,21 @@ func ProxyWithConfig(config ProxyConfig) echo.MiddlewareFunc {
 
 	provider, isTargetProvider := config.Balancer.(TargetProvider)
 
+	var hedge ProxyHedgeConfig
+	if config.Hedge != nil {
+		hedge = config.Hedge.withDefaults()
+	}
+	nextTarget := func(c echo.Context) *ProxyTarget {
+		if isTargetProvider {
+			tgt, err := provider.NextTarget(c)
+			if err != nil {
+				return nil
+			}
+			return tgt
+		}
+		return config.Balancer.Next(c)
+	}
+
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			if config.Skipper(c) {
@@ -336,6 +356,14 @@ func ProxyWithConfig(config ProxyConfig) echo.MiddlewareFunc {
 				req.Header.Set(echo.HeaderXForwardedFor, c.RealIP())
 			}
 
+			hedging := false
+			if config.Hedge != nil {
+				var err error
+				if hedging, err = hedge.prepareHedging(c); err != nil {
+					return config.ErrorHandler(c, echo.NewHTTPError(http.StatusBadRequest, "failed to read request body").SetInternal(err))
+				}
+			}
+
 			retries := config.RetryCount
 			for {
 				var tgt *ProxyTarget
@@ -366,6 +394,14 @@ func ProxyWithConfig(config ProxyConfig) echo.MiddlewareFunc {
 				switch {
 				case c.IsWebSocket():
 					proxyRaw(tgt, c).ServeHTTP(res, req)
+				case hedging:
+					proxy := proxyHTTP(tgt, c, config)
+					base := config.Transport
+					if base == nil {
+						base = http.DefaultTransport
+					}
+					proxy.Transport = &hedgingTransport{base: base, config: hedge, c: c, nextTarget: nextTarget}
+					proxy.ServeHTTP(res, req)
 				default: // even SSE requests
 					proxyHTTP(tgt, c, config).ServeHTTP(res, req)
 				}
@@ -393,7 +429,7 @@ func ProxyWithConfig(config ProxyConfig) echo.MiddlewareFunc {
 // 499 too instead of the more problematic 5xx, which does not allow to detect this situation
 const StatusCodeContextCanceled = 499
 
-func proxyHTTP(tgt *ProxyTarget, c echo.Context, config ProxyConfig) http.Handler {
+func proxyHTTP(tgt *ProxyTarget, c echo.Context, config ProxyConfig) *httputil.ReverseProxy {
 	proxy := httputil.NewSingleHostReverseProxy(tgt.URL)
 	proxy.ErrorHandler = func(resp http.ResponseWriter, req *http.Request, err error) {
 		desc := tgt.URL.String()
@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ProxyHedgeConfig defines request hedging of Proxy middleware. When the upstream has not responded within Delay, the
+// same request is sent to the next target of the balancer and the first response wins. Requests of losing attempts are
+// cancelled. Hedging is meant for idempotent requests only as the upstream may process the request more than once.
+type ProxyHedgeConfig struct {
+	// Delay is time to wait for the response before firing the next hedged request.
+	// Required.
+	Delay time.Duration
+
+	// MaxHedges is maximum number of hedged requests fired in addition to the original request.
+	// Optional. Default value 1.
+	MaxHedges int
+
+	// Idempotent returns true when request can be hedged.
+	// Optional. Default value allows GET, HEAD and OPTIONS requests.
+	Idempotent func(c echo.Context) bool
+
+	// MaxBodySize is maximum size of request body buffered so it can be replayed for hedged requests. Requests with
+	// bigger bodies or bodies of unknown length (ala chunked uploads) are not hedged.
+	// Optional. Default value 64KB.
+	MaxBodySize int64
+
+	// Header is response header set to "won" when hedged request won and to "lost" when hedged request was fired but
+	// the original request won. Header is not set when no hedged request was fired. Same value is stored in context
+	// key ProxyHedgeContextKey for request logging.
+	// Optional. Default value "X-Proxy-Hedge". Use "-" to not set the header.
+	Header string
+
+	// OnHedge is called once for every request where hedged request was fired with won set to true when hedged
+	// request won. Use it to collect hedge fired/won metrics.
+	// Optional.
+	OnHedge func(c echo.Context, won bool)
+}
+
+// ProxyHedgeContextKey is the context key where Proxy middleware stores outcome ("won" or "lost") of the hedged request.
+const ProxyHedgeContextKey = "proxy_hedge"
+
+// DefaultProxyHedgeConfig is the default request hedging config of Proxy middleware.
+var DefaultProxyHedgeConfig = ProxyHedgeConfig{
+	MaxHedges:   1,
+	Idempotent:  isSafeMethod,
+	MaxBodySize: 64 * 1024,
+	Header:      "X-Proxy-Hedge",
+}
+
+func isSafeMethod(c echo.Context) bool {
+	switch c.Request().Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func (config ProxyHedgeConfig) withDefaults() ProxyHedgeConfig {
+	if config.Delay <= 0 {
+		panic("echo: proxy middleware hedging requires delay")
+	}
+	if config.MaxHedges <= 0 {
+		config.MaxHedges = DefaultProxyHedgeConfig.MaxHedges
+	}
+	if config.Idempotent == nil {
+		config.Idempotent = DefaultProxyHedgeConfig.Idempotent
+	}
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = DefaultProxyHedgeConfig.MaxBodySize
+	}
+	if config.Header == "" {
+		config.Header = DefaultProxyHedgeConfig.Header
+	}
+	return config
+}
+
+// prepareHedging returns true when request can be hedged. Request body is buffered so it can be replayed for every
+// attempt.
+func (config ProxyHedgeConfig) prepareHedging(c echo.Context) (bool, error) {
+	if c.IsWebSocket() || !config.Idempotent(c) {
+		return false, nil
+	}
+	req := c.Request()
+	if req.Body == nil || req.Body == http.NoBody || req.ContentLength == 0 {
+		return true, nil
+	}
+	if req.ContentLength < 0 || req.ContentLength > config.MaxBodySize {
+		return false, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(req.Body, req.ContentLength))
+	if err != nil {
+		return false, err
+	}
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return true, nil
+}
+
+// hedgingTransport sends the request with base transport and fires hedged requests to next targets when response has
+// not arrived within the delay. RoundTrip is called from the handler goroutine so it can use echo.Context and balancer.
+type hedgingTransport struct {
+	base       http.RoundTripper
+	config     ProxyHedgeConfig
+	c          echo.Context
+	nextTarget func(c echo.Context) *ProxyTarget
+}
+
+type hedgeResult struct {
+	resp    *http.Response
+	err     error
+	attempt int
+}
+
+func (t *hedgingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	results := make(chan hedgeResult, t.config.MaxHedges+1)
+	var cancels []context.CancelFunc
+	cancelAll := func(except int) {
+		for i, cancel := range cancels {
+			if i != except {
+				cancel()
+			}
+		}
+	}
+	start := func(r *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		attempt := len(cancels)
+		cancels = append(cancels, cancel)
+		r = r.WithContext(ctx)
+		if req.GetBody != nil {
+			r.Body, _ = req.GetBody()
+		}
+		go func() {
+			resp, err := t.base.RoundTrip(r)
+			results <- hedgeResult{resp: resp, err: err, attempt: attempt}
+		}()
+	}
+
+	start(req)
+	timer := time.NewTimer(t.config.Delay)
+	defer timer.Stop()
+
+	pending, fired := 1, 0
+	var lastErr error
+	for {
+		select {
+		case <-timer.C:
+			fired++
+			if hedged := t.hedgedRequest(req); hedged != nil {
+				start(hedged)
+				pending++
+			}
+			if fired < t.config.MaxHedges {
+				timer.Reset(t.config.Delay)
+			}
+		case r := <-results:
+			pending--
+			if r.err != nil {
+				lastErr = r.err
+				if pending == 0 {
+					cancelAll(-1)
+					return nil, lastErr
+				}
+				continue
+			}
+			cancelAll(r.attempt)
+			go discardHedgeResults(results, pending)
+			r.resp.Body = &cancelOnCloseBody{ReadCloser: r.resp.Body, cancel: cancels[r.attempt]}
+			if len(cancels) > 1 {
+				t.annotate(r.resp, r.attempt > 0)
+			}
+			return r.resp, nil
+		}
+	}
+}
+
+// hedgedRequest returns copy of the outgoing request sent to the next target of the balancer. Returns nil when the
+// balancer has no target.
+func (t *hedgingTransport) hedgedRequest(req *http.Request) *http.Request {
+	tgt := t.nextTarget(t.c)
+	if tgt == nil {
+		return nil
+	}
+	// outgoing URL is created same way as the Director of proxyHTTP does it for the original request
+	tmp := t.c.Request().Clone(req.Context())
+	httputil.NewSingleHostReverseProxy(tgt.URL).Director(tmp)
+
+	hedged := req.Clone(req.Context())
+	hedged.URL = tmp.URL
+	return hedged
+}
+
+func (t *hedgingTransport) annotate(resp *http.Response, won bool) {
+	outcome := "lost"
+	if won {
+		outcome = "won"
+	}
+	if t.config.Header != "-" {
+		resp.Header.Set(t.config.Header, outcome)
+	}
+	t.c.Set(ProxyHedgeContextKey, outcome)
+	if t.config.OnHedge != nil {
+		t.config.OnHedge(t.c, won)
+	}
+}
+
+// discardHedgeResults closes responses of losing attempts that arrive after the winner.
+func discardHedgeResults(results <-chan hedgeResult, pending int) {
+	for ; pending > 0; pending-- {
+		if r := <-results; r.resp != nil {
+			r.resp.Body.Close()
+		}
+	}
+}
+
+// cancelOnCloseBody cancels context of the winning attempt when its response body has been read.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,220 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newHedgeTestTargets(t *testing.T) (slow *ProxyTarget, fast *ProxyTarget, slowCancelled chan struct{}) {
+	slowCancelled = make(chan struct{}, 10)
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// server notices closed connection only after the body has been read
+		body, _ := io.ReadAll(r.Body)
+		select {
+		case <-r.Context().Done():
+			slowCancelled <- struct{}{}
+		case <-time.After(2 * time.Second):
+			_, _ = w.Write([]byte("slow:" + string(body)))
+		}
+	}))
+	t.Cleanup(slowServer.Close)
+	fastServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write([]byte("fast:" + r.URL.Path + ":" + string(body)))
+	}))
+	t.Cleanup(fastServer.Close)
+
+	slowURL, _ := url.Parse(slowServer.URL)
+	fastURL, _ := url.Parse(fastServer.URL + "/base")
+	return &ProxyTarget{Name: "slow", URL: slowURL}, &ProxyTarget{Name: "fast", URL: fastURL}, slowCancelled
+}
+
+func TestProxyHedge(t *testing.T) {
+	var testCases = []struct {
+		name            string
+		whenMethod      string
+		whenBody        string
+		whenChunked     bool
+		whenMaxBodySize int64
+		whenFastFirst   bool
+		expectBody      string
+		expectHeader    string
+		expectCancelled bool
+		expectOnHedge   []bool
+	}{
+		{
+			name:            "ok, hedged request wins and original is cancelled",
+			whenMethod:      http.MethodGet,
+			expectBody:      "fast:/base/api/users:",
+			expectHeader:    "won",
+			expectCancelled: true,
+			expectOnHedge:   []bool{true},
+		},
+		{
+			name:          "ok, original request responds before delay",
+			whenMethod:    http.MethodGet,
+			whenFastFirst: true,
+			expectBody:    "fast:/base/api/users:",
+			expectHeader:  "",
+		},
+		{
+			name:            "ok, body is replayed for hedged request",
+			whenMethod:      http.MethodPut,
+			whenBody:        `{"name":"Jon"}`,
+			expectBody:      `fast:/base/api/users:{"name":"Jon"}`,
+			expectHeader:    "won",
+			expectCancelled: true,
+			expectOnHedge:   []bool{true},
+		},
+		{
+			name:         "ok, non idempotent request is not hedged",
+			whenMethod:   http.MethodPost,
+			whenBody:     `{"name":"Jon"}`,
+			expectBody:   `slow:{"name":"Jon"}`,
+			expectHeader: "",
+		},
+		{
+			name:            "ok, request with body bigger than MaxBodySize is not hedged",
+			whenMethod:      http.MethodPut,
+			whenBody:        `{"name":"Jon"}`,
+			whenMaxBodySize: 5,
+			expectBody:      `slow:{"name":"Jon"}`,
+			expectHeader:    "",
+		},
+		{
+			name:         "ok, request with body of unknown length is not hedged",
+			whenMethod:   http.MethodPut,
+			whenBody:     `{"name":"Jon"}`,
+			whenChunked:  true,
+			expectBody:   `slow:{"name":"Jon"}`,
+			expectHeader: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			slow, fast, slowCancelled := newHedgeTestTargets(t)
+			targets := []*ProxyTarget{slow, fast}
+			if tc.whenFastFirst {
+				targets = []*ProxyTarget{fast, slow}
+			}
+
+			var onHedge []bool
+			e := echo.New()
+			e.Use(ProxyWithConfig(ProxyConfig{
+				Balancer: NewRoundRobinBalancer(targets),
+				Hedge: &ProxyHedgeConfig{
+					Delay:       50 * time.Millisecond,
+					MaxBodySize: tc.whenMaxBodySize,
+					Idempotent: func(c echo.Context) bool {
+						return c.Request().Method != http.MethodPost
+					},
+					OnHedge: func(c echo.Context, won bool) {
+						onHedge = append(onHedge, won)
+					},
+				},
+			}))
+
+			req := httptest.NewRequest(tc.whenMethod, "/api/users", strings.NewReader(tc.whenBody))
+			if tc.whenChunked {
+				req.ContentLength = -1
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+			assert.Equal(t, tc.expectHeader, rec.Header().Get("X-Proxy-Hedge"))
+			assert.Equal(t, tc.expectOnHedge, onHedge)
+			if tc.expectCancelled {
+				select {
+				case <-slowCancelled:
+				case <-time.After(time.Second):
+					t.Fatal("original request was not cancelled")
+				}
+			}
+		})
+	}
+}
+
+func TestProxyHedge_originalWins(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(80 * time.Millisecond)
+		_, _ = w.Write([]byte("first"))
+	}))
+	defer server.Close()
+	hedgeTarget := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer hedgeTarget.Close()
+
+	u1, _ := url.Parse(server.URL)
+	u2, _ := url.Parse(hedgeTarget.URL)
+	var onHedge []bool
+	var contextOutcome interface{}
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+			contextOutcome = c.Get(ProxyHedgeContextKey)
+			return err
+		}
+	})
+	e.Use(ProxyWithConfig(ProxyConfig{
+		Balancer: NewRoundRobinBalancer([]*ProxyTarget{{Name: "1", URL: u1}, {Name: "2", URL: u2}}),
+		Hedge: &ProxyHedgeConfig{
+			Delay: 20 * time.Millisecond,
+			OnHedge: func(c echo.Context, won bool) {
+				onHedge = append(onHedge, won)
+			},
+		},
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "first", rec.Body.String())
+	assert.Equal(t, "lost", rec.Header().Get("X-Proxy-Hedge"))
+	assert.Equal(t, "lost", contextOutcome)
+	assert.Equal(t, []bool{false}, onHedge)
+}
+
+func TestProxyHedge_allAttemptsFail(t *testing.T) {
+	e := echo.New()
+	u1, _ := url.Parse("http://127.0.0.1:27121")
+	u2, _ := url.Parse("http://127.0.0.1:27122")
+	e.Use(ProxyWithConfig(ProxyConfig{
+		Balancer: NewRoundRobinBalancer([]*ProxyTarget{{Name: "1", URL: u1}, {Name: "2", URL: u2}}),
+		Hedge:    &ProxyHedgeConfig{Delay: time.Millisecond},
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	assert.Equal(t, "", rec.Header().Get("X-Proxy-Hedge"))
+}
+
+func TestProxyHedge_requiresDelay(t *testing.T) {
+	u, _ := url.Parse("http://127.0.0.1:27121")
+	assert.PanicsWithValue(t, "echo: proxy middleware hedging requires delay", func() {
+		ProxyWithConfig(ProxyConfig{
+			Balancer: NewRoundRobinBalancer([]*ProxyTarget{{Name: "1", URL: u}}),
+			Hedge:    &ProxyHedgeConfig{},
+		})
+	})
+}
@@ -46,6 +46,18 @@ type RecoverConfig struct {
 	// The recovered error is then passed back to upstream middleware, instead of swallowing the error.
 	// Optional. Default value false.
 	DisableErrorHandler bool `yaml:"disable_error_handler"`
+
+	// IncidentID makes the middleware pass "500 - Internal Server Error" with incident ID in the message
+	// (`{"error":"internal server error","incident_id":"..."}`) to the centralized HTTPErrorHandler and add the
+	// incident ID to the log entry with the stack trace. Request ID (`X-Request-ID` header set by RequestID middleware
+	// or sent by the client) is used as incident ID when present. Error passed to LogErrorFunc is that HTTPError with
+	// the recovered error as Internal.
+	// Optional. Default value false.
+	IncidentID bool `yaml:"incident_id"`
+
+	// IncidentIDGenerator generates incident ID when request has no request ID.
+	// Optional. Default value generates random string of length 32.
+	IncidentIDGenerator func() string
 }
 
 // DefaultRecoverConfig is the default Recover middleware config.
@@ -57,6 +69,8 @@ var DefaultRecoverConfig = RecoverConfig{
 	LogLevel:            0,
 	LogErrorFunc:        nil,
 	DisableErrorHandler: false,
+	IncidentID:          false,
+	IncidentIDGenerator: generator,
 }
 
 // Recover returns a middleware which recovers from panics anywhere in the chain
@@ -75,6 +89,9 @@ func RecoverWithConfig(config RecoverConfig) echo.MiddlewareFunc {
 	if config.StackSize == 0 {
 		config.StackSize = DefaultRecoverConfig.StackSize
 	}
+	if config.IncidentIDGenerator == nil {
+		config.IncidentIDGenerator = DefaultRecoverConfig.IncidentIDGenerator
+	}
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) (returnErr error) {
@@ -91,6 +108,15 @@ func RecoverWithConfig(config RecoverConfig) echo.MiddlewareFunc {
 					if !ok {
 						err = fmt.Errorf("%v", r)
 					}
+					panicErr := err
+					incidentID := ""
+					if config.IncidentID {
+						incidentID = recoverIncidentID(c, config.IncidentIDGenerator)
+						err = echo.NewHTTPError(http.StatusInternalServerError, echo.Map{
+							"error":       "internal server error",
+							"incident_id": incidentID,
+						}).SetInternal(panicErr)
+					}
 					var stack []byte
 					var length int
 
@@ -103,7 +129,10 @@ func RecoverWithConfig(config RecoverConfig) echo.MiddlewareFunc {
 					if config.LogErrorFunc != nil {
 						err = config.LogErrorFunc(c, err, stack)
 					} else if !config.DisablePrintStack {
-						msg := fmt.Sprintf("[PANIC RECOVER] %v %s\n", err, stack[:length])
+						msg := fmt.Sprintf("[PANIC RECOVER] %v %s\n", panicErr, stack[:length])
+						if incidentID != "" {
+							msg = fmt.Sprintf("[PANIC RECOVER] incident_id=%s %v %s\n", incidentID, panicErr, stack[:length])
+						}
 						switch config.LogLevel {
 						case log.DEBUG:
 							c.Logger().Debug(msg)
@@ -131,3 +160,14 @@ func RecoverWithConfig(config RecoverConfig) echo.MiddlewareFunc {
 		}
 	}
 }
+
+// recoverIncidentID returns request ID of the request or generates new incident ID.
+func recoverIncidentID(c echo.Context, generate func() string) string {
+	if id := c.Response().Header().Get(echo.HeaderXRequestID); id != "" {
+		return id
+	}
+	if id := c.Request().Header.Get(echo.HeaderXRequestID); id != "" {
+		return id
+	}
+	return generate()
+}
@@ -187,3 +187,87 @@ func TestRecoverWithDisabled_ErrorHandler(t *testing.T) {
 	assert.Contains(t, buf.String(), "PANIC RECOVER")
 	assert.EqualError(t, err, "test")
 }
+
+func TestRecoverWithConfig_IncidentID(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		givenRequestID   string
+		whenMiddleware   echo.MiddlewareFunc
+		expectIncidentID string
+	}{
+		{
+			name:             "ok, generated",
+			expectIncidentID: "generated-id",
+		},
+		{
+			name:             "ok, request ID sent by client",
+			givenRequestID:   "client-id",
+			expectIncidentID: "client-id",
+		},
+		{
+			name: "ok, request ID set by RequestID middleware",
+			whenMiddleware: RequestIDWithConfig(RequestIDConfig{Generator: func() string {
+				return "request-id"
+			}}),
+			expectIncidentID: "request-id",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			buf := new(bytes.Buffer)
+			e.Logger.SetOutput(buf)
+
+			if tc.whenMiddleware != nil {
+				e.Use(tc.whenMiddleware)
+			}
+			e.Use(RecoverWithConfig(RecoverConfig{
+				IncidentID:          true,
+				IncidentIDGenerator: func() string { return "generated-id" },
+			}))
+			e.GET("/", func(c echo.Context) error {
+				panic("test")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.givenRequestID != "" {
+				req.Header.Set(echo.HeaderXRequestID, tc.givenRequestID)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusInternalServerError, rec.Code)
+			assert.Equal(t, `{"error":"internal server error","incident_id":"`+tc.expectIncidentID+`"}`+"\n", rec.Body.String())
+			assert.Contains(t, buf.String(), "[PANIC RECOVER] incident_id="+tc.expectIncidentID+" test")
+		})
+	}
+}
+
+func TestRecoverWithConfig_IncidentIDWithLogErrorFunc(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderXRequestID, "abc")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var logged error
+	h := RecoverWithConfig(RecoverConfig{
+		IncidentID: true,
+		LogErrorFunc: func(c echo.Context, err error, stack []byte) error {
+			logged = err
+			return err
+		},
+	})(func(c echo.Context) error {
+		panic("test")
+	})
+
+	assert.NoError(t, h(c))
+
+	var he *echo.HTTPError
+	if assert.ErrorAs(t, logged, &he) {
+		assert.Equal(t, echo.Map{"error": "internal server error", "incident_id": "abc"}, he.Message)
+		assert.EqualError(t, he.Internal, "test")
+	}
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
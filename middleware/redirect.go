@@ -4,10 +4,12 @@
 package middleware
 
 import (
+	"net"
 	"net/http"
 	"strings"
 
 	"github.com/labstack/echo/v4"
+	"golang.org/x/net/idna"
 )
 
 // RedirectConfig defines the config for Redirect middleware.
@@ -64,8 +66,12 @@ func HTTPSWWWRedirect() echo.MiddlewareFunc {
 // See `HTTPSWWWRedirect()`.
 func HTTPSWWWRedirectWithConfig(config RedirectConfig) echo.MiddlewareFunc {
 	return redirect(config, func(scheme, host, uri string) (bool, string) {
-		if scheme != "https" && !strings.HasPrefix(host, www) {
-			return true, "https://www." + host + uri
+		name, port, ok := splitRedirectHost(host)
+		if !ok {
+			return false, ""
+		}
+		if scheme != "https" && !strings.HasPrefix(name, www) {
+			return true, "https://" + joinRedirectHost(www+name, port) + uri
 		}
 		return false, ""
 	})
@@ -84,7 +90,9 @@ func HTTPSNonWWWRedirect() echo.MiddlewareFunc {
 func HTTPSNonWWWRedirectWithConfig(config RedirectConfig) echo.MiddlewareFunc {
 	return redirect(config, func(scheme, host, uri string) (ok bool, url string) {
 		if scheme != "https" {
-			host = strings.TrimPrefix(host, www)
+			if name, port, ok := splitRedirectHost(host); ok && len(name) > len(www) {
+				host = joinRedirectHost(strings.TrimPrefix(name, www), port)
+			}
 			return true, "https://" + host + uri
 		}
 		return false, ""
@@ -103,8 +111,9 @@ func WWWRedirect() echo.MiddlewareFunc {
 // See `WWWRedirect()`.
 func WWWRedirectWithConfig(config RedirectConfig) echo.MiddlewareFunc {
 	return redirect(config, func(scheme, host, uri string) (bool, string) {
-		if !strings.HasPrefix(host, www) {
-			return true, scheme + "://www." + host + uri
+		name, port, ok := splitRedirectHost(host)
+		if ok && !strings.HasPrefix(name, www) {
+			return true, scheme + "://" + joinRedirectHost(www+name, port) + uri
 		}
 		return false, ""
 	})
@@ -122,13 +131,42 @@ func NonWWWRedirect() echo.MiddlewareFunc {
 // See `NonWWWRedirect()`.
 func NonWWWRedirectWithConfig(config RedirectConfig) echo.MiddlewareFunc {
 	return redirect(config, func(scheme, host, uri string) (bool, string) {
-		if strings.HasPrefix(host, www) {
-			return true, scheme + "://" + host[4:] + uri
+		name, port, ok := splitRedirectHost(host)
+		if ok && strings.HasPrefix(name, www) && len(name) > len(www) {
+			return true, scheme + "://" + joinRedirectHost(name[len(www):], port) + uri
 		}
 		return false, ""
 	})
 }
 
+// splitRedirectHost splits host (with optional port) of the request into hostname and port. Hostname is lowercased and
+// internationalized domain names are converted to punycode (ala `Bücher.example` to `xn--bcher-kva.example`) so www
+// prefix is compared consistently. Returns false for IP literals (ala `[::1]:8080`) and empty host as www prefix makes
+// no sense for them.
+func splitRedirectHost(host string) (name string, port string, ok bool) {
+	name = host
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		name, port = h, p
+	}
+	if name == "" || strings.HasPrefix(name, "[") || net.ParseIP(name) != nil {
+		return "", "", false
+	}
+	if ascii, err := idna.Lookup.ToASCII(name); err == nil {
+		name = ascii
+	} else {
+		// hostnames not valid by IDNA rules (ala containing underscore) are only lowercased
+		name = strings.ToLower(name)
+	}
+	return name, port, true
+}
+
+func joinRedirectHost(name string, port string) string {
+	if port == "" {
+		return name
+	}
+	return net.JoinHostPort(name, port)
+}
+
 func redirect(config RedirectConfig, cb redirectLogic) echo.MiddlewareFunc {
 	if config.Skipper == nil {
 		config.Skipper = DefaultRedirectConfig.Skipper
@@ -264,6 +264,119 @@ func TestNonWWWRedirectWithConfig(t *testing.T) {
 	}
 }
 
+func TestRedirectWWW_hostsWithPortsAndIDN(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		givenMiddleware  middlewareGenerator
+		whenHost         string
+		whenHeader       http.Header
+		expectLocation   string
+		expectStatusCode int
+	}{
+		{
+			name:             "WWWRedirect keeps custom port",
+			givenMiddleware:  WWWRedirect,
+			whenHost:         "labstack.com:8080",
+			expectLocation:   "http://www.labstack.com:8080/",
+			expectStatusCode: http.StatusMovedPermanently,
+		},
+		{
+			name:             "WWWRedirect does not redirect www host with port",
+			givenMiddleware:  WWWRedirect,
+			whenHost:         "www.labstack.com:8080",
+			expectStatusCode: http.StatusOK,
+		},
+		{
+			name:             "WWWRedirect does not redirect uppercase www host",
+			givenMiddleware:  WWWRedirect,
+			whenHost:         "WWW.LabStack.com",
+			expectStatusCode: http.StatusOK,
+		},
+		{
+			name:             "WWWRedirect converts IDN host to punycode",
+			givenMiddleware:  WWWRedirect,
+			whenHost:         "Bücher.example:8443",
+			whenHeader:       map[string][]string{echo.HeaderXForwardedProto: {"https"}},
+			expectLocation:   "https://www.xn--bcher-kva.example:8443/",
+			expectStatusCode: http.StatusMovedPermanently,
+		},
+		{
+			name:             "WWWRedirect leaves IPv6 literal alone",
+			givenMiddleware:  WWWRedirect,
+			whenHost:         "[::1]:8080",
+			expectStatusCode: http.StatusOK,
+		},
+		{
+			name:             "WWWRedirect leaves IPv4 literal alone",
+			givenMiddleware:  WWWRedirect,
+			whenHost:         "127.0.0.1:8080",
+			expectStatusCode: http.StatusOK,
+		},
+		{
+			name:             "NonWWWRedirect keeps custom port",
+			givenMiddleware:  NonWWWRedirect,
+			whenHost:         "www.labstack.com:8080",
+			expectLocation:   "http://labstack.com:8080/",
+			expectStatusCode: http.StatusMovedPermanently,
+		},
+		{
+			name:             "NonWWWRedirect handles uppercase punycode host",
+			givenMiddleware:  NonWWWRedirect,
+			whenHost:         "WWW.XN--BCHER-KVA.EXAMPLE",
+			expectLocation:   "http://xn--bcher-kva.example/",
+			expectStatusCode: http.StatusMovedPermanently,
+		},
+		{
+			name:             "NonWWWRedirect leaves IPv6 literal alone",
+			givenMiddleware:  NonWWWRedirect,
+			whenHost:         "[2001:db8::1]",
+			expectStatusCode: http.StatusOK,
+		},
+		{
+			name:             "NonWWWRedirect does not redirect bare www host",
+			givenMiddleware:  NonWWWRedirect,
+			whenHost:         "www.",
+			expectStatusCode: http.StatusOK,
+		},
+		{
+			name:             "HTTPSWWWRedirect keeps custom port",
+			givenMiddleware:  HTTPSWWWRedirect,
+			whenHost:         "labstack.com:8443",
+			expectLocation:   "https://www.labstack.com:8443/",
+			expectStatusCode: http.StatusMovedPermanently,
+		},
+		{
+			name:             "HTTPSWWWRedirect leaves IPv6 literal alone",
+			givenMiddleware:  HTTPSWWWRedirect,
+			whenHost:         "[::1]:8080",
+			expectStatusCode: http.StatusOK,
+		},
+		{
+			name:             "HTTPSNonWWWRedirect keeps custom port of IDN host",
+			givenMiddleware:  HTTPSNonWWWRedirect,
+			whenHost:         "www.Bücher.example:8443",
+			expectLocation:   "https://xn--bcher-kva.example:8443/",
+			expectStatusCode: http.StatusMovedPermanently,
+		},
+		{
+			name:             "HTTPSNonWWWRedirect keeps IPv6 literal as is",
+			givenMiddleware:  HTTPSNonWWWRedirect,
+			whenHost:         "[::1]:8080",
+			expectLocation:   "https://[::1]:8080/",
+			expectStatusCode: http.StatusMovedPermanently,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := redirectTest(tc.givenMiddleware, tc.whenHost, tc.whenHeader)
+
+			assert.Equal(t, tc.expectStatusCode, res.Code)
+			assert.Equal(t, tc.expectLocation, res.Header().Get(echo.HeaderLocation))
+		})
+	}
+}
+
 func redirectTest(fn middlewareGenerator, host string, header http.Header) *httptest.ResponseRecorder {
 	e := echo.New()
 	next := func(c echo.Context) (err error) {
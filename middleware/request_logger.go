@@ -159,6 +159,8 @@ type RequestLoggerConfig struct {
 	LogRoutePath bool
 	// LogRequestID instructs logger to extract request ID from request `X-Request-ID` header or response if request did not have value.
 	LogRequestID bool
+	// LogTenantID instructs logger to extract tenant ID resolved by Tenant middleware.
+	LogTenantID bool
 	// LogReferer instructs logger to extract request referer values.
 	LogReferer bool
 	// LogUserAgent instructs logger to extract request user agent values.
@@ -212,6 +214,8 @@ type RequestLoggerValues struct {
 	RoutePath string
 	// RequestID is request ID from request `X-Request-ID` header or response if request did not have value.
 	RequestID string
+	// TenantID is tenant ID resolved by Tenant middleware.
+	TenantID string
 	// Referer is request referer values.
 	Referer string
 	// UserAgent is request user agent values.
@@ -326,6 +330,9 @@ func (config RequestLoggerConfig) ToMiddleware() (echo.MiddlewareFunc, error) {
 				}
 				v.RequestID = id
 			}
+			if config.LogTenantID {
+				v.TenantID = TenantIDFromContext(c)
+			}
 			if config.LogReferer {
 				v.Referer = req.Referer()
 			}
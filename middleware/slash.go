@@ -4,6 +4,8 @@
 package middleware
 
 import (
+	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/labstack/echo/v4"
@@ -17,11 +19,23 @@ type TrailingSlashConfig struct {
 	// Status code to be used when redirecting the request.
 	// Optional, but when provided the request is redirected using this code.
 	RedirectCode int `yaml:"redirect_code"`
+
+	// NonGETRedirectCode is status code used instead of RedirectCode when redirecting requests with methods other than
+	// GET and HEAD. Clients change method of 301 and 302 redirected POST requests to GET and drop the body, 307 and 308
+	// keep the method and the body.
+	// Optional. Default value http.StatusPermanentRedirect (308). Used only when RedirectCode is set.
+	NonGETRedirectCode int `yaml:"non_get_redirect_code"`
+
+	// GETAndHEADOnly makes middleware add/remove trailing slash only for GET and HEAD requests. Requests with other
+	// methods are left untouched.
+	// Optional. Default value false.
+	GETAndHEADOnly bool `yaml:"get_and_head_only"`
 }
 
 // DefaultTrailingSlashConfig is the default TrailingSlash middleware config.
 var DefaultTrailingSlashConfig = TrailingSlashConfig{
-	Skipper: DefaultSkipper,
+	Skipper:            DefaultSkipper,
+	NonGETRedirectCode: http.StatusPermanentRedirect,
 }
 
 // AddTrailingSlash returns a root level (before router) middleware which adds a
@@ -35,40 +49,12 @@ func AddTrailingSlash() echo.MiddlewareFunc {
 // AddTrailingSlashWithConfig returns an AddTrailingSlash middleware with config.
 // See `AddTrailingSlash()`.
 func AddTrailingSlashWithConfig(config TrailingSlashConfig) echo.MiddlewareFunc {
-	// Defaults
-	if config.Skipper == nil {
-		config.Skipper = DefaultTrailingSlashConfig.Skipper
-	}
-
-	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(c echo.Context) error {
-			if config.Skipper(c) {
-				return next(c)
-			}
-
-			req := c.Request()
-			url := req.URL
-			path := url.Path
-			qs := c.QueryString()
-			if !strings.HasSuffix(path, "/") {
-				path += "/"
-				uri := path
-				if qs != "" {
-					uri += "?" + qs
-				}
-
-				// Redirect
-				if config.RedirectCode != 0 {
-					return c.Redirect(config.RedirectCode, sanitizeURI(uri))
-				}
-
-				// Forward
-				req.RequestURI = uri
-				url.Path = path
-			}
-			return next(c)
+	return trailingSlash(config, func(path string) (string, bool) {
+		if strings.HasSuffix(path, "/") {
+			return path, false
 		}
-	}
+		return path + "/", true
+	})
 }
 
 // RemoveTrailingSlash returns a root level (before router) middleware which removes
@@ -82,10 +68,23 @@ func RemoveTrailingSlash() echo.MiddlewareFunc {
 // RemoveTrailingSlashWithConfig returns a RemoveTrailingSlash middleware with config.
 // See `RemoveTrailingSlash()`.
 func RemoveTrailingSlashWithConfig(config TrailingSlashConfig) echo.MiddlewareFunc {
-	// Defaults
+	return trailingSlash(config, func(path string) (string, bool) {
+		if len(path) <= 1 || !strings.HasSuffix(path, "/") {
+			return path, false
+		}
+		return path[:len(path)-1], true
+	})
+}
+
+// trailingSlash returns middleware that changes escaped path of the request URL with fix. fix returns false when the
+// path does not need change.
+func trailingSlash(config TrailingSlashConfig, fix func(path string) (string, bool)) echo.MiddlewareFunc {
 	if config.Skipper == nil {
 		config.Skipper = DefaultTrailingSlashConfig.Skipper
 	}
+	if config.NonGETRedirectCode == 0 {
+		config.NonGETRedirectCode = DefaultTrailingSlashConfig.NonGETRedirectCode
+	}
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -94,36 +93,64 @@ func RemoveTrailingSlashWithConfig(config TrailingSlashConfig) echo.MiddlewareFu
 			}
 
 			req := c.Request()
-			url := req.URL
-			path := url.Path
-			qs := c.QueryString()
-			l := len(path) - 1
-			if l > 0 && strings.HasSuffix(path, "/") {
-				path = path[:l]
-				uri := path
-				if qs != "" {
-					uri += "?" + qs
-				}
+			isGETOrHEAD := req.Method == http.MethodGet || req.Method == http.MethodHead
+			if config.GETAndHEADOnly && !isGETOrHEAD {
+				return next(c)
+			}
+			// escaped path is used so encoded characters (ala `%2F`, `%3F` or `%23`) are not mistaken for path
+			// separators, query or fragment. Query is appended as is.
+			u := req.URL
+			escapedPath, ok := fix(u.EscapedPath())
+			if !ok {
+				return next(c)
+			}
+			path, err := url.PathUnescape(escapedPath)
+			if err != nil {
+				return next(c)
+			}
+			uri := escapedPath
+			if u.RawQuery != "" || u.ForceQuery {
+				uri += "?" + u.RawQuery
+			}
 
-				// Redirect
-				if config.RedirectCode != 0 {
-					return c.Redirect(config.RedirectCode, sanitizeURI(uri))
+			// Redirect
+			if config.RedirectCode != 0 {
+				code := config.RedirectCode
+				if !isGETOrHEAD {
+					code = config.NonGETRedirectCode
 				}
+				return c.Redirect(code, sanitizeEscapedURI(uri))
+			}
 
-				// Forward
-				req.RequestURI = uri
-				url.Path = path
+			// Forward
+			req.RequestURI = uri
+			if u.RawPath != "" {
+				u.RawPath = escapedPath
 			}
+			u.Path = path
 			return next(c)
 		}
 	}
 }
 
-func sanitizeURI(uri string) string {
-	// double slash `\\`, `//` or even `\/` is absolute uri for browsers and by redirecting request to that uri
-	// we are vulnerable to open redirect attack. so replace all slashes from the beginning with single slash
-	if len(uri) > 1 && (uri[0] == '\\' || uri[0] == '/') && (uri[1] == '\\' || uri[1] == '/') {
-		uri = "/" + strings.TrimLeft(uri, `/\`)
+// sanitizeEscapedURI replaces slashes and backslashes (also percent-encoded ones) at the beginning of escaped uri with
+// single slash. Double slash `\\`, `//` or even `\/` is absolute uri for browsers and by redirecting request to that
+// uri we are vulnerable to open redirect attack.
+func sanitizeEscapedURI(uri string) string {
+	rest := uri
+	slashes := 0
+	for {
+		switch {
+		case strings.HasPrefix(rest, "/") || strings.HasPrefix(rest, "\\"):
+			rest = rest[1:]
+		case len(rest) >= 3 && (strings.EqualFold(rest[:3], "%5C") || strings.EqualFold(rest[:3], "%2F")):
+			rest = rest[3:]
+		default:
+			if slashes < 2 {
+				return uri
+			}
+			return "/" + rest
+		}
+		slashes++
 	}
-	return uri
 }
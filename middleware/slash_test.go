@@ -279,3 +279,214 @@ func TestRemoveTrailingSlash(t *testing.T) {
 		})
 	}
 }
+
+func TestTrailingSlash_encodedPath(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		whenMiddleware   echo.MiddlewareFunc
+		whenUseEncoded   bool
+		whenURL          string
+		expectParam      string
+		expectEscapedURL string
+	}{
+		{
+			name:             "ok, remove keeps encoded slash in raw path",
+			whenMiddleware:   RemoveTrailingSlash(),
+			whenURL:          "/files/a%2Fb/",
+			expectParam:      "a%2Fb",
+			expectEscapedURL: "/files/a%2Fb",
+		},
+		{
+			name:             "ok, remove with UseEncodedPath",
+			whenMiddleware:   RemoveTrailingSlash(),
+			whenUseEncoded:   true,
+			whenURL:          "/files/a%2Fb/",
+			expectParam:      "a/b",
+			expectEscapedURL: "/files/a%2Fb",
+		},
+		{
+			name:             "ok, add with UseEncodedPath",
+			whenMiddleware:   AddTrailingSlash(),
+			whenUseEncoded:   true,
+			whenURL:          "/files/a%2Fb",
+			expectParam:      "a/b",
+			expectEscapedURL: "/files/a%2Fb/",
+		},
+		{
+			name:             "ok, remove with UseEncodedPath and encoded percent",
+			whenMiddleware:   RemoveTrailingSlash(),
+			whenUseEncoded:   true,
+			whenURL:          "/files/a%25b/",
+			expectParam:      "a%b",
+			expectEscapedURL: "/files/a%25b",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.UseEncodedPath = tc.whenUseEncoded
+			e.Pre(tc.whenMiddleware)
+
+			var param, escaped string
+			handler := func(c echo.Context) error {
+				param = c.Param("name")
+				escaped = c.Request().URL.EscapedPath()
+				return c.NoContent(http.StatusOK)
+			}
+			e.GET("/files/:name", handler)
+			e.GET("/files/:name/", handler)
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.whenURL, nil))
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tc.expectParam, param)
+			assert.Equal(t, tc.expectEscapedURL, escaped)
+		})
+	}
+}
+
+func TestTrailingSlash_queryAndMethods(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		givenConfig    TrailingSlashConfig
+		givenRemove    bool
+		whenMethod     string
+		whenURL        string
+		expectStatus   int
+		expectLocation string
+		expectURI      string
+		expectPath     string
+	}{
+		{
+			name:           "redirect keeps raw query with semicolons and encoded characters",
+			givenConfig:    TrailingSlashConfig{RedirectCode: http.StatusMovedPermanently},
+			whenMethod:     http.MethodGet,
+			whenURL:        "/docs?a=1;b=2&c=%26%3D%20x&d=%3B",
+			expectStatus:   http.StatusMovedPermanently,
+			expectLocation: "/docs/?a=1;b=2&c=%26%3D%20x&d=%3B",
+		},
+		{
+			name:           "redirect keeps fragment-like encoded data in path",
+			givenConfig:    TrailingSlashConfig{RedirectCode: http.StatusMovedPermanently},
+			givenRemove:    true,
+			whenMethod:     http.MethodGet,
+			whenURL:        "/docs/a%23b%3Fc/?q=%23x",
+			expectStatus:   http.StatusMovedPermanently,
+			expectLocation: "/docs/a%23b%3Fc?q=%23x",
+		},
+		{
+			name:         "forward keeps raw query and encoded path",
+			whenMethod:   http.MethodGet,
+			whenURL:      "/docs/a%23b?a=1;b=2&c=%2F",
+			expectStatus: http.StatusOK,
+			expectURI:    "/docs/a%23b/?a=1;b=2&c=%2F",
+			expectPath:   "/docs/a#b/",
+		},
+		{
+			name:         "forward keeps empty query",
+			givenRemove:  true,
+			whenMethod:   http.MethodGet,
+			whenURL:      "/docs/?",
+			expectStatus: http.StatusOK,
+			expectURI:    "/docs?",
+			expectPath:   "/docs",
+		},
+		{
+			name:         "forward keeps double slashes inside path",
+			givenRemove:  true,
+			whenMethod:   http.MethodGet,
+			whenURL:      "/a//b//?x=1",
+			expectStatus: http.StatusOK,
+			expectURI:    "/a//b/?x=1",
+			expectPath:   "/a//b/",
+		},
+		{
+			name:           "redirect with double slashes is sanitized",
+			givenConfig:    TrailingSlashConfig{RedirectCode: http.StatusMovedPermanently},
+			whenMethod:     http.MethodGet,
+			whenURL:        "//a//b?x=1",
+			expectStatus:   http.StatusMovedPermanently,
+			expectLocation: "/a//b/?x=1",
+		},
+		{
+			name:         "encoded trailing slash is not removed",
+			givenRemove:  true,
+			whenMethod:   http.MethodGet,
+			whenURL:      "/files/a%2F",
+			expectStatus: http.StatusOK,
+			expectURI:    "/files/a%2F",
+			expectPath:   "/files/a/",
+		},
+		{
+			name:           "POST is redirected with 308 by default",
+			givenConfig:    TrailingSlashConfig{RedirectCode: http.StatusMovedPermanently},
+			whenMethod:     http.MethodPost,
+			whenURL:        "/users?x=1",
+			expectStatus:   http.StatusPermanentRedirect,
+			expectLocation: "/users/?x=1",
+		},
+		{
+			name:           "HEAD is redirected with RedirectCode",
+			givenConfig:    TrailingSlashConfig{RedirectCode: http.StatusFound},
+			whenMethod:     http.MethodHead,
+			whenURL:        "/users",
+			expectStatus:   http.StatusFound,
+			expectLocation: "/users/",
+		},
+		{
+			name:           "non GET redirect code is configurable",
+			givenConfig:    TrailingSlashConfig{RedirectCode: http.StatusMovedPermanently, NonGETRedirectCode: http.StatusTemporaryRedirect},
+			givenRemove:    true,
+			whenMethod:     http.MethodPut,
+			whenURL:        "/users/1/",
+			expectStatus:   http.StatusTemporaryRedirect,
+			expectLocation: "/users/1",
+		},
+		{
+			name:         "GETAndHEADOnly leaves POST untouched",
+			givenConfig:  TrailingSlashConfig{RedirectCode: http.StatusMovedPermanently, GETAndHEADOnly: true},
+			whenMethod:   http.MethodPost,
+			whenURL:      "/users?x=1",
+			expectStatus: http.StatusOK,
+			expectURI:    "/users?x=1",
+			expectPath:   "/users",
+		},
+		{
+			name:           "GETAndHEADOnly redirects GET",
+			givenConfig:    TrailingSlashConfig{RedirectCode: http.StatusMovedPermanently, GETAndHEADOnly: true},
+			whenMethod:     http.MethodGet,
+			whenURL:        "/users?x=1",
+			expectStatus:   http.StatusMovedPermanently,
+			expectLocation: "/users/?x=1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			mw := AddTrailingSlashWithConfig(tc.givenConfig)
+			if tc.givenRemove {
+				mw = RemoveTrailingSlashWithConfig(tc.givenConfig)
+			}
+			h := mw(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.whenMethod, tc.whenURL, nil)
+			c := e.NewContext(req, rec)
+
+			err := h(c)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.expectStatus, rec.Code)
+			assert.Equal(t, tc.expectLocation, rec.Header().Get(echo.HeaderLocation))
+			if tc.expectURI != "" {
+				assert.Equal(t, tc.expectURI, req.RequestURI)
+				assert.Equal(t, tc.expectPath, req.URL.Path)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"html/template"
+	"io/fs"
 	"net/http"
 	"net/url"
 	"os"
@@ -48,6 +49,11 @@ type StaticConfig struct {
 	// Filesystem provides access to the static content.
 	// Optional. Defaults to http.Dir(config.Root)
 	Filesystem http.FileSystem `yaml:"-"`
+
+	// Precompressed enables serving of pre-compressed file variants (`app.js.br`, `app.js.gz`) when client accepts
+	// their encoding. See `echo.ServePrecompressedFile`.
+	// Optional. Default value false.
+	Precompressed bool `yaml:"precompressed"`
 }
 
 const html = `
@@ -202,7 +208,8 @@ func StaticWithConfig(config StaticConfig) echo.MiddlewareFunc {
 					return err
 				}
 
-				file, err = config.Filesystem.Open(path.Join(config.Root, config.Index))
+				name = path.Join(config.Root, config.Index)
+				file, err = config.Filesystem.Open(name)
 				if err != nil {
 					return err
 				}
@@ -216,7 +223,8 @@ func StaticWithConfig(config StaticConfig) echo.MiddlewareFunc {
 			}
 
 			if info.IsDir() {
-				index, err := config.Filesystem.Open(path.Join(name, config.Index))
+				indexName := path.Join(name, config.Index)
+				index, err := config.Filesystem.Open(indexName)
 				if err != nil {
 					if config.Browse {
 						return listDir(t, name, file, c.Response())
@@ -232,15 +240,24 @@ func StaticWithConfig(config StaticConfig) echo.MiddlewareFunc {
 					return err
 				}
 
-				return serveFile(c, index, info)
+				return serveFile(c, config, indexName, index, info)
 			}
 
-			return serveFile(c, file, info)
+			return serveFile(c, config, name, file, info)
 		}
 	}
 }
 
-func serveFile(c echo.Context, file http.File, info os.FileInfo) error {
+func serveFile(c echo.Context, config StaticConfig, name string, file http.File, info os.FileInfo) error {
+	if config.Precompressed {
+		open := func(name string) (fs.File, error) {
+			return config.Filesystem.Open(name)
+		}
+		if ok, err := echo.ServePrecompressedFile(c, open, name); ok || err != nil {
+			return err
+		}
+	}
+	echo.SetContentTypeByExtension(c, info.Name())
 	http.ServeContent(c.Response(), c.Request(), info.Name(), info.ModTime(), file)
 	return nil
 }
@@ -412,3 +412,52 @@ func TestStatic_CustomFS(t *testing.T) {
 		})
 	}
 }
+
+func TestStatic_precompressedWithGzip(t *testing.T) {
+	filesystem := fstest.MapFS{
+		"app.js":    &fstest.MapFile{Data: []byte("console.log('original')")},
+		"app.js.gz": &fstest.MapFile{Data: []byte("pre-compressed")},
+	}
+
+	e := echo.New()
+	e.Use(Gzip())
+	e.Use(StaticWithConfig(StaticConfig{
+		Root:          ".",
+		Filesystem:    http.FS(filesystem),
+		Precompressed: true,
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/app.js", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "pre-compressed", rec.Body.String()) // not compressed again by Gzip middleware
+	assert.Equal(t, "gzip", rec.Header().Get(echo.HeaderContentEncoding))
+	assert.Equal(t, "text/javascript; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "14", rec.Header().Get(echo.HeaderContentLength))
+	assert.Equal(t, []string{echo.HeaderAcceptEncoding}, rec.Header().Values(echo.HeaderVary))
+}
+
+func TestStatic_contentType(t *testing.T) {
+	filesystem := fstest.MapFS{
+		"app.wasm": &fstest.MapFile{Data: []byte("\x00asm")},
+		"data.foo": &fstest.MapFile{Data: []byte("foo")},
+	}
+
+	e := echo.New()
+	e.MIMETypes[".foo"] = "application/foo"
+	e.Use(StaticWithConfig(StaticConfig{
+		Root:       ".",
+		Filesystem: http.FS(filesystem),
+	}))
+
+	for file, expect := range map[string]string{"/app.wasm": "application/wasm", "/data.foo": "application/foo"} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, file, nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, expect, rec.Header().Get(echo.HeaderContentType))
+	}
+}
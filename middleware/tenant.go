@@ -0,0 +1,234 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TenantConfig defines the config for Tenant middleware.
+type TenantConfig struct {
+	// Skipper defines a function to skip middleware.
+	Skipper Skipper
+
+	// Resolvers are tried in order to resolve tenant ID of the request. First non-empty ID is used.
+	// Required.
+	Resolvers []TenantResolver
+
+	// Loader loads tenant (configuration) for the tenant ID. Return ErrTenantNotFound for unknown tenants. Other
+	// errors result "503 - Service Unavailable" response.
+	// Required.
+	Loader func(ctx context.Context, tenantID string) (interface{}, error)
+
+	// CacheTTL is how long loaded tenants are cached across requests. Failed loads are not cached.
+	// Optional. Default value 0 means that tenant is loaded once per request.
+	CacheTTL time.Duration
+
+	// UnknownTenantStatus is status code of the response for requests without tenant ID or with unknown tenant.
+	// Optional. Default value http.StatusNotFound.
+	UnknownTenantStatus int
+}
+
+// TenantResolver resolves tenant ID from the request. Returns empty string when request does not contain tenant ID.
+type TenantResolver func(c echo.Context) string
+
+// ErrTenantNotFound is returned by `TenantConfig.Loader` for unknown tenants.
+var ErrTenantNotFound = errors.New("tenant not found")
+
+const (
+	// TenantIDContextKey is the context key that holds tenant ID of the request. Logger (`${tenant_id}` tag) and
+	// RequestLogger (`LogTenantID`) read it from there.
+	TenantIDContextKey = "tenant_id"
+
+	tenantContextKey = "_tenant"
+)
+
+// DefaultTenantConfig is the default Tenant middleware config.
+var DefaultTenantConfig = TenantConfig{
+	Skipper:             DefaultSkipper,
+	UnknownTenantStatus: http.StatusNotFound,
+}
+
+// TenantFromHostLabel returns resolver that uses label of the request host at index (from left) as tenant ID. For
+// index 0 host `acme.example.com` resolves to `acme`. Label must be followed by at least two labels (domain and TLD)
+// so apex domain (ala `example.com` for index 0) and IP addresses do not resolve.
+func TenantFromHostLabel(index int) TenantResolver {
+	return func(c echo.Context) string {
+		host := c.Request().Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if net.ParseIP(strings.Trim(host, "[]")) != nil {
+			return ""
+		}
+		labels := strings.Split(host, ".")
+		if index < 0 || index >= len(labels)-2 {
+			return ""
+		}
+		return labels[index]
+	}
+}
+
+// TenantFromHeader returns resolver that uses value of the request header as tenant ID (ala `X-Tenant-ID`).
+func TenantFromHeader(name string) TenantResolver {
+	return func(c echo.Context) string {
+		return c.Request().Header.Get(name)
+	}
+}
+
+// TenantFromQuery returns resolver that uses value of the query param as tenant ID.
+func TenantFromQuery(name string) TenantResolver {
+	return func(c echo.Context) string {
+		return c.QueryParam(name)
+	}
+}
+
+// TenantFromParam returns resolver that uses value of the path param as tenant ID.
+func TenantFromParam(name string) TenantResolver {
+	return func(c echo.Context) string {
+		return c.Param(name)
+	}
+}
+
+// Tenant returns a Tenant middleware that resolves tenant ID with resolvers and loads the tenant with loader.
+//
+// Example:
+//
+//	e.Use(middleware.Tenant(loadTenant, middleware.TenantFromHostLabel(0), middleware.TenantFromHeader("X-Tenant-ID")))
+//	e.GET("/settings", func(c echo.Context) error {
+//		account, _ := middleware.TenantFromContext[*Account](c)
+//		return c.JSON(http.StatusOK, account.Settings)
+//	})
+func Tenant(loader func(ctx context.Context, tenantID string) (interface{}, error), resolvers ...TenantResolver) echo.MiddlewareFunc {
+	c := DefaultTenantConfig
+	c.Loader = loader
+	c.Resolvers = resolvers
+	return TenantWithConfig(c)
+}
+
+// TenantWithConfig returns a Tenant middleware with config.
+// See: `Tenant()`.
+func TenantWithConfig(config TenantConfig) echo.MiddlewareFunc {
+	if config.Loader == nil {
+		panic("echo: tenant middleware requires a loader")
+	}
+	if len(config.Resolvers) == 0 {
+		panic("echo: tenant middleware requires at least one resolver")
+	}
+	if config.Skipper == nil {
+		config.Skipper = DefaultTenantConfig.Skipper
+	}
+	if config.UnknownTenantStatus == 0 {
+		config.UnknownTenantStatus = DefaultTenantConfig.UnknownTenantStatus
+	}
+	var cache *tenantCache
+	if config.CacheTTL > 0 {
+		cache = &tenantCache{ttl: config.CacheTTL, entries: map[string]tenantCacheEntry{}, now: time.Now}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			tenantID := ""
+			for _, resolve := range config.Resolvers {
+				if tenantID = resolve(c); tenantID != "" {
+					break
+				}
+			}
+			if tenantID == "" {
+				return echo.NewHTTPError(config.UnknownTenantStatus, "missing tenant")
+			}
+			c.Set(TenantIDContextKey, tenantID)
+
+			tenant, err := config.load(c.Request().Context(), cache, tenantID)
+			if errors.Is(err, ErrTenantNotFound) {
+				return echo.NewHTTPError(config.UnknownTenantStatus, "unknown tenant").SetInternal(err)
+			}
+			if err != nil {
+				return echo.NewHTTPError(http.StatusServiceUnavailable, "tenant is unavailable").SetInternal(err)
+			}
+			c.Set(tenantContextKey, tenant)
+			return next(c)
+		}
+	}
+}
+
+func (config TenantConfig) load(ctx context.Context, cache *tenantCache, tenantID string) (interface{}, error) {
+	if cache == nil {
+		return config.Loader(ctx, tenantID)
+	}
+	if tenant, ok := cache.get(tenantID); ok {
+		return tenant, nil
+	}
+	tenant, err := config.Loader(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	cache.set(tenantID, tenant)
+	return tenant, nil
+}
+
+// TenantFromContext returns tenant loaded by Tenant middleware. Returns false when there is no tenant or it is not of
+// type T.
+func TenantFromContext[T any](c echo.Context) (T, bool) {
+	tenant, ok := c.Get(tenantContextKey).(T)
+	return tenant, ok
+}
+
+// TenantIDFromContext returns tenant ID resolved by Tenant middleware.
+func TenantIDFromContext(c echo.Context) string {
+	id, _ := c.Get(TenantIDContextKey).(string)
+	return id
+}
+
+type tenantCacheEntry struct {
+	tenant  interface{}
+	expires time.Time
+}
+
+// tenantCache caches loaded tenants for TTL. Expired entries are removed when the cache is written to.
+type tenantCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu          sync.Mutex
+	entries     map[string]tenantCacheEntry
+	lastCleanup time.Time
+}
+
+func (tc *tenantCache) get(tenantID string) (interface{}, bool) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	entry, ok := tc.entries[tenantID]
+	if !ok || !tc.now().Before(entry.expires) {
+		return nil, false
+	}
+	return entry.tenant, true
+}
+
+func (tc *tenantCache) set(tenantID string, tenant interface{}) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	now := tc.now()
+	if now.Sub(tc.lastCleanup) > tc.ttl {
+		for id, entry := range tc.entries {
+			if !now.Before(entry.expires) {
+				delete(tc.entries, id)
+			}
+		}
+		tc.lastCleanup = now
+	}
+	tc.entries[tenantID] = tenantCacheEntry{tenant: tenant, expires: now.Add(tc.ttl)}
+}
@@ -0,0 +1,243 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type testTenant struct {
+	Name string
+}
+
+func testTenantLoader(calls *int) func(ctx context.Context, tenantID string) (interface{}, error) {
+	return func(ctx context.Context, tenantID string) (interface{}, error) {
+		*calls++
+		switch tenantID {
+		case "acme", "globex":
+			return &testTenant{Name: tenantID}, nil
+		case "broken":
+			return nil, errors.New("database is down")
+		}
+		return nil, ErrTenantNotFound
+	}
+}
+
+func TestTenant(t *testing.T) {
+	var testCases = []struct {
+		name               string
+		givenUnknownStatus int
+		whenHost           string
+		whenHeader         string
+		whenURL            string
+		expectStatus       int
+		expectBody         string
+	}{
+		{
+			name:         "ok, from host label",
+			whenHost:     "acme.example.com",
+			whenURL:      "/",
+			expectStatus: http.StatusOK,
+			expectBody:   "acme",
+		},
+		{
+			name:         "ok, from header when host has no tenant label",
+			whenHost:     "example.com",
+			whenHeader:   "globex",
+			whenURL:      "/",
+			expectStatus: http.StatusOK,
+			expectBody:   "globex",
+		},
+		{
+			name:         "ok, from query param",
+			whenHost:     "127.0.0.1:8080",
+			whenURL:      "/?tenant=acme",
+			expectStatus: http.StatusOK,
+			expectBody:   "acme",
+		},
+		{
+			name:         "ok, from path param",
+			whenHost:     "example.com",
+			whenURL:      "/t/globex",
+			expectStatus: http.StatusOK,
+			expectBody:   "globex",
+		},
+		{
+			name:         "nok, missing tenant",
+			whenHost:     "example.com",
+			whenURL:      "/",
+			expectStatus: http.StatusNotFound,
+			expectBody:   `{"message":"missing tenant"}` + "\n",
+		},
+		{
+			name:         "nok, unknown tenant",
+			whenHost:     "initech.example.com",
+			whenURL:      "/",
+			expectStatus: http.StatusNotFound,
+			expectBody:   `{"message":"unknown tenant"}` + "\n",
+		},
+		{
+			name:               "nok, unknown tenant with custom status",
+			givenUnknownStatus: http.StatusForbidden,
+			whenHost:           "initech.example.com",
+			whenURL:            "/",
+			expectStatus:       http.StatusForbidden,
+			expectBody:         `{"message":"unknown tenant"}` + "\n",
+		},
+		{
+			name:         "nok, loader fails",
+			whenHost:     "broken.example.com",
+			whenURL:      "/",
+			expectStatus: http.StatusServiceUnavailable,
+			expectBody:   `{"message":"tenant is unavailable"}` + "\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			calls := 0
+			mw := TenantWithConfig(TenantConfig{
+				Resolvers: []TenantResolver{
+					TenantFromHostLabel(0),
+					TenantFromHeader("X-Tenant-ID"),
+					TenantFromQuery("tenant"),
+					TenantFromParam("tenant"),
+				},
+				Loader:              testTenantLoader(&calls),
+				UnknownTenantStatus: tc.givenUnknownStatus,
+			})
+			h := func(c echo.Context) error {
+				tenant, ok := TenantFromContext[*testTenant](c)
+				if !ok {
+					return errors.New("no tenant")
+				}
+				return c.String(http.StatusOK, tenant.Name)
+			}
+			e.GET("/", h, mw)
+			e.GET("/t/:tenant", h, mw)
+
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			req.Host = tc.whenHost
+			if tc.whenHeader != "" {
+				req.Header.Set("X-Tenant-ID", tc.whenHeader)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectStatus, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+		})
+	}
+}
+
+func TestTenant_cacheTTL(t *testing.T) {
+	calls := 0
+	mw := TenantWithConfig(TenantConfig{
+		Resolvers: []TenantResolver{TenantFromHeader("X-Tenant-ID")},
+		Loader:    testTenantLoader(&calls),
+		CacheTTL:  time.Minute,
+	})
+	h := mw(func(c echo.Context) error {
+		return c.String(http.StatusOK, TenantIDFromContext(c))
+	})
+
+	e := echo.New()
+	for _, tenantID := range []string{"acme", "acme", "globex", "unknown", "unknown"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Tenant-ID", tenantID)
+		c := e.NewContext(req, httptest.NewRecorder())
+		_ = h(c)
+	}
+
+	assert.Equal(t, 4, calls, "acme must be loaded once, failed loads are not cached")
+}
+
+func TestTenantCache_expiry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := &tenantCache{ttl: time.Minute, entries: map[string]tenantCacheEntry{}, now: func() time.Time { return now }}
+
+	cache.set("acme", "a")
+	tenant, ok := cache.get("acme")
+	assert.True(t, ok)
+	assert.Equal(t, "a", tenant)
+
+	now = now.Add(time.Minute)
+	_, ok = cache.get("acme")
+	assert.False(t, ok)
+
+	now = now.Add(time.Minute)
+	cache.set("globex", "g")
+	assert.Len(t, cache.entries, 1, "expired entries are removed on write")
+}
+
+func TestTenant_loggerField(t *testing.T) {
+	e := echo.New()
+	buf := new(bytes.Buffer)
+	var logged RequestLoggerValues
+	e.Use(LoggerWithConfig(LoggerConfig{Format: "${tenant_id}\n", Output: buf}))
+	e.Use(RequestLoggerWithConfig(RequestLoggerConfig{
+		LogTenantID: true,
+		LogValuesFunc: func(c echo.Context, v RequestLoggerValues) error {
+			logged = v
+			return nil
+		},
+	}))
+	calls := 0
+	e.Use(Tenant(testTenantLoader(&calls), TenantFromHeader("X-Tenant-ID")))
+	e.GET("/", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Tenant-ID", "acme")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "acme\n", buf.String())
+	assert.Equal(t, "acme", logged.TenantID)
+}
+
+func TestTenantFromHostLabel(t *testing.T) {
+	var testCases = []struct {
+		whenIndex int
+		whenHost  string
+		expect    string
+	}{
+		{whenIndex: 0, whenHost: "acme.example.com", expect: "acme"},
+		{whenIndex: 0, whenHost: "acme.example.com:8080", expect: "acme"},
+		{whenIndex: 1, whenHost: "eu.acme.example.com", expect: "acme"},
+		{whenIndex: 0, whenHost: "localhost", expect: ""},
+		{whenIndex: 2, whenHost: "acme.example.com", expect: ""},
+		{whenIndex: 0, whenHost: "10.0.0.1", expect: ""},
+		{whenIndex: 0, whenHost: "[::1]:8080", expect: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.whenHost, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Host = tc.whenHost
+			c := echo.New().NewContext(req, nil)
+
+			assert.Equal(t, tc.expect, TenantFromHostLabel(tc.whenIndex)(c))
+		})
+	}
+}
+
+func TestTenantWithConfig_panics(t *testing.T) {
+	assert.PanicsWithValue(t, "echo: tenant middleware requires a loader", func() {
+		TenantWithConfig(TenantConfig{Resolvers: []TenantResolver{TenantFromHeader("X-Tenant-ID")}})
+	})
+	assert.PanicsWithValue(t, "echo: tenant middleware requires at least one resolver", func() {
+		Tenant(func(ctx context.Context, tenantID string) (interface{}, error) { return nil, nil })
+	})
+}
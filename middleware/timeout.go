@@ -16,6 +16,8 @@ import (
 // WARNING: Timeout middleware causes more problems than it solves.
 // WARNING: This middleware should be first middleware as it messes with request Writer and could cause data race if
 // 					it is in other position
+// WARNING: Handlers can not use http.ResponseController (SetWriteDeadline, EnableFullDuplex etc.) through this
+// 					middleware as writer of http.TimeoutHandler does not support unwrapping.
 //
 // Depending on out requirements you could be better of setting timeout to context and
 // check its deadline from handler.
@@ -219,3 +221,10 @@ func (w *ignorableWriter) Write(b []byte) (int, error) {
 	}
 	return w.ResponseWriter.Write(b)
 }
+
+// Unwrap returns the original http.ResponseWriter.
+// ResponseController can be used to access the original http.ResponseWriter.
+// See [https://go.dev/blog/go1.20]
+func (w *ignorableWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -490,3 +490,12 @@ func startServer(e *echo.Echo) (*http.Server, string, error) {
 		return nil, "", err
 	}
 }
+
+func TestIgnorableWriter_Unwrap(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := &ignorableWriter{ResponseWriter: rec}
+
+	assert.Same(t, rec, w.Unwrap())
+	assert.NoError(t, http.NewResponseController(w).Flush())
+	assert.True(t, rec.Flushed)
+}
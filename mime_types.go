@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"mime"
+	"strings"
+)
+
+// builtinMIMETypes are content types of common web file extensions. They are consulted before the system MIME
+// database (`mime.TypeByExtension`) as it is incomplete or wrong on some systems (ala `.js` as `text/plain` in Windows
+// registry or missing `.wasm` that breaks WebAssembly streaming compilation).
+var builtinMIMETypes = map[string]string{
+	".avif":        "image/avif",
+	".css":         "text/css; charset=utf-8",
+	".csv":         "text/csv; charset=utf-8",
+	".gif":         "image/gif",
+	".htm":         "text/html; charset=utf-8",
+	".html":        "text/html; charset=utf-8",
+	".ico":         "image/x-icon",
+	".jpeg":        "image/jpeg",
+	".jpg":         "image/jpeg",
+	".js":          "text/javascript; charset=utf-8",
+	".json":        "application/json",
+	".map":         "application/json",
+	".md":          "text/markdown; charset=utf-8",
+	".mjs":         "text/javascript; charset=utf-8",
+	".mp4":         "video/mp4",
+	".otf":         "font/otf",
+	".pdf":         "application/pdf",
+	".png":         "image/png",
+	".svg":         "image/svg+xml",
+	".ttf":         "font/ttf",
+	".txt":         "text/plain; charset=utf-8",
+	".wasm":        "application/wasm",
+	".webm":        "video/webm",
+	".webmanifest": "application/manifest+json",
+	".webp":        "image/webp",
+	".woff":        "font/woff",
+	".woff2":       "font/woff2",
+	".xml":         "text/xml; charset=utf-8",
+}
+
+// ContentTypeByExtension returns content type of file extension (with leading dot, ala `.wasm`) from the built-in
+// table of common web types or, when extension is not in the table, from the system MIME database. Text types get
+// `charset=utf-8` parameter when they have no charset. Returns empty string for unknown extensions.
+func ContentTypeByExtension(ext string) string {
+	ext = strings.ToLower(ext)
+	if ct, ok := builtinMIMETypes[ext]; ok {
+		return ct
+	}
+	return withTextCharset(mime.TypeByExtension(ext))
+}
+
+// ContentTypeByExtension returns content type of file extension using `Echo#MIMETypes` overrides first and then
+// package level `ContentTypeByExtension`. Used when files are served with `Context#File`, `Context#FileFS`, Static
+// middleware and cached file handlers.
+func (e *Echo) ContentTypeByExtension(ext string) string {
+	if ct := e.mimeTypeOverride(ext); ct != "" {
+		return ct
+	}
+	return ContentTypeByExtension(ext)
+}
+
+func (e *Echo) mimeTypeOverride(ext string) string {
+	if len(e.MIMETypes) == 0 || ext == "" {
+		return ""
+	}
+	ct, ok := e.MIMETypes[ext]
+	if !ok {
+		ct = e.MIMETypes[strings.ToLower(ext)]
+	}
+	return withTextCharset(ct)
+}
+
+// withTextCharset adds `charset=utf-8` parameter to `text/*` content type without charset.
+func withTextCharset(contentType string) string {
+	if !strings.HasPrefix(contentType, "text/") || strings.Contains(strings.ToLower(contentType), "charset=") {
+		return contentType
+	}
+	return contentType + "; charset=utf-8"
+}
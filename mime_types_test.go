@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentTypeByExtension(t *testing.T) {
+	var testCases = []struct {
+		name           string
+		givenMIMETypes map[string]string
+		whenExt        string
+		expect         string
+	}{
+		{name: "ok, wasm", whenExt: ".wasm", expect: "application/wasm"},
+		{name: "ok, mjs", whenExt: ".mjs", expect: "text/javascript; charset=utf-8"},
+		{name: "ok, avif", whenExt: ".avif", expect: "image/avif"},
+		{name: "ok, webmanifest", whenExt: ".webmanifest", expect: "application/manifest+json"},
+		{name: "ok, upper case extension", whenExt: ".WASM", expect: "application/wasm"},
+		{name: "ok, unknown extension", whenExt: ".echo-unknown", expect: ""},
+		{name: "ok, empty extension", whenExt: "", expect: ""},
+		{
+			name:           "ok, override",
+			givenMIMETypes: map[string]string{".foo": "application/foo"},
+			whenExt:        ".foo",
+			expect:         "application/foo",
+		},
+		{
+			name:           "ok, override wins over built-in type",
+			givenMIMETypes: map[string]string{".js": "application/javascript"},
+			whenExt:        ".JS",
+			expect:         "application/javascript",
+		},
+		{
+			name:           "ok, override of text type gets charset",
+			givenMIMETypes: map[string]string{".adoc": "text/asciidoc"},
+			whenExt:        ".adoc",
+			expect:         "text/asciidoc; charset=utf-8",
+		},
+		{
+			name:           "ok, override with charset is kept",
+			givenMIMETypes: map[string]string{".txt": "text/plain; charset=iso-8859-1"},
+			whenExt:        ".txt",
+			expect:         "text/plain; charset=iso-8859-1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			for k, v := range tc.givenMIMETypes {
+				e.MIMETypes[k] = v
+			}
+
+			assert.Equal(t, tc.expect, e.ContentTypeByExtension(tc.whenExt))
+		})
+	}
+}
+
+func TestContext_FileFS_contentType(t *testing.T) {
+	filesystem := fstest.MapFS{
+		"app.wasm":          &fstest.MapFile{Data: []byte("\x00asm")},
+		"site.webmanifest":  &fstest.MapFile{Data: []byte(`{}`)},
+		"data.foo":          &fstest.MapFile{Data: []byte("foo")},
+		"page.echo-unknown": &fstest.MapFile{Data: []byte("<html><body>hi</body></html>")},
+	}
+
+	var testCases = []struct {
+		name            string
+		whenFile        string
+		whenContentType string
+		expect          string
+	}{
+		{name: "ok, built-in type", whenFile: "app.wasm", expect: "application/wasm"},
+		{name: "ok, webmanifest", whenFile: "site.webmanifest", expect: "application/manifest+json"},
+		{name: "ok, Echo override", whenFile: "data.foo", expect: "application/foo"},
+		{name: "ok, sniffed when extension is unknown", whenFile: "page.echo-unknown", expect: "text/html; charset=utf-8"},
+		{name: "ok, content type set by handler is kept", whenFile: "app.wasm", whenContentType: "application/x-custom", expect: "application/x-custom"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.MIMETypes[".foo"] = "application/foo"
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+			if tc.whenContentType != "" {
+				c.Response().Header().Set(HeaderContentType, tc.whenContentType)
+			}
+
+			err := c.(*context).FileFS(tc.whenFile, filesystem)
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tc.expect, rec.Header().Get(HeaderContentType))
+		})
+	}
+}
+
+func TestCachedFileHandler_contentTypeOverride(t *testing.T) {
+	filesystem := fstest.MapFS{"data.foo": &fstest.MapFile{Data: []byte("foo")}}
+	h, err := CachedFileHandler(filesystem, "data.foo", DefaultCachedFileConfig)
+	assert.NoError(t, err)
+
+	e := New()
+	e.MIMETypes[".foo"] = "application/foo"
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	assert.NoError(t, h(c))
+	assert.Equal(t, "application/foo", rec.Header().Get(HeaderContentType))
+}
@@ -0,0 +1,266 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PaginationStyle is the style of query params used for pagination.
+type PaginationStyle int
+
+const (
+	// PaginationPageNumber paginates with 1-based page number (ala `?page=2&limit=20`).
+	PaginationPageNumber PaginationStyle = iota
+	// PaginationOffset paginates with 0-based offset of the first item (ala `?offset=40&limit=20`).
+	PaginationOffset
+	// PaginationCursor paginates with opaque base64url cursor (ala `?cursor=eyJpZCI6NDJ9&limit=20`). See EncodeCursor.
+	PaginationCursor
+)
+
+// PaginationConfig defines how BindPagination parses pagination query params.
+type PaginationConfig struct {
+	// Style is the style of pagination query params.
+	// Optional. Default value PaginationPageNumber.
+	Style PaginationStyle
+
+	// DefaultLimit is number of items per page when the request has no limit param.
+	// Optional. Default value 20.
+	DefaultLimit int
+
+	// MaxLimit is maximum number of items per page the request can ask for. Bigger limits result
+	// "400 - Bad Request" error.
+	// Optional. Default value 100.
+	MaxLimit int
+
+	// PageParam, OffsetParam, CursorParam and LimitParam are names of the query params.
+	// Optional. Default values "page", "offset", "cursor" and "limit".
+	PageParam   string
+	OffsetParam string
+	CursorParam string
+	LimitParam  string
+
+	// BasePath is prefix added to the request path in links written by `Pagination#WriteLinkHeaders`. Use it when
+	// Echo runs behind a proxy that strips path prefix (ala `/api`) before forwarding the request.
+	// Optional.
+	BasePath string
+}
+
+// DefaultPaginationConfig is the default PaginationConfig.
+var DefaultPaginationConfig = PaginationConfig{
+	Style:        PaginationPageNumber,
+	DefaultLimit: 20,
+	MaxLimit:     100,
+	PageParam:    "page",
+	OffsetParam:  "offset",
+	CursorParam:  "cursor",
+	LimitParam:   "limit",
+}
+
+// Pagination holds normalized pagination values of the request returned by BindPagination.
+type Pagination struct {
+	// Page is 1-based page number. Set for PaginationPageNumber style.
+	Page int
+	// Offset is 0-based index of the first item. Set for PaginationPageNumber (calculated from Page) and
+	// PaginationOffset styles.
+	Offset int
+	// Limit is number of items per page.
+	Limit int
+	// Cursor is decoded cursor of the request. Nil for the first page. Set for PaginationCursor style.
+	Cursor []byte
+	// NextCursor is cursor of the next page, set by the handler for PaginationCursor style. `next` link is written
+	// only when it is not nil.
+	NextCursor []byte
+	// PrevCursor is cursor of the previous page, set by the handler for PaginationCursor style. `prev` link is written
+	// only when it is not nil.
+	PrevCursor []byte
+
+	config PaginationConfig
+}
+
+// EncodeCursor encodes cursor value to opaque base64url (without padding) string.
+func EncodeCursor(cursor []byte) string {
+	return base64.RawURLEncoding.EncodeToString(cursor)
+}
+
+// DecodeCursor decodes cursor encoded with EncodeCursor. Padded base64url is accepted.
+func DecodeCursor(cursor string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(cursor, "="))
+}
+
+// BindPagination parses pagination query params of the request. Invalid values (ala non-numeric, page below 1, limit
+// above `PaginationConfig.MaxLimit` or malformed cursor) result "400 - Bad Request" error. Zero values of the config
+// fields are replaced with values from DefaultPaginationConfig.
+//
+// Example:
+//
+//	p, err := echo.BindPagination(c, echo.PaginationConfig{DefaultLimit: 20, MaxLimit: 100})
+//	if err != nil {
+//		return err
+//	}
+//	users, total := store.ListUsers(p.Offset, p.Limit)
+//	p.WriteLinkHeaders(c, total)
+//	return c.JSON(http.StatusOK, users)
+func BindPagination(c Context, config PaginationConfig) (*Pagination, error) {
+	config = config.withDefaults()
+	p := &Pagination{config: config}
+
+	query := c.QueryParams()
+	limit, err := paginationInt(query, config.LimitParam, config.DefaultLimit, 1, config.MaxLimit)
+	if err != nil {
+		return nil, err
+	}
+	p.Limit = limit
+
+	switch config.Style {
+	case PaginationOffset:
+		if p.Offset, err = paginationInt(query, config.OffsetParam, 0, 0, -1); err != nil {
+			return nil, err
+		}
+	case PaginationCursor:
+		if raw := query.Get(config.CursorParam); raw != "" {
+			if p.Cursor, err = DecodeCursor(raw); err != nil {
+				return nil, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid query param %q: malformed cursor", config.CursorParam)).SetInternal(err)
+			}
+		}
+	default:
+		if p.Page, err = paginationInt(query, config.PageParam, 1, 1, -1); err != nil {
+			return nil, err
+		}
+		if p.Page-1 > math.MaxInt/p.Limit {
+			return nil, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid query param %q: out of range", config.PageParam))
+		}
+		p.Offset = (p.Page - 1) * p.Limit
+	}
+	return p, nil
+}
+
+func (config PaginationConfig) withDefaults() PaginationConfig {
+	if config.DefaultLimit <= 0 {
+		config.DefaultLimit = DefaultPaginationConfig.DefaultLimit
+	}
+	if config.MaxLimit <= 0 {
+		config.MaxLimit = DefaultPaginationConfig.MaxLimit
+	}
+	if config.DefaultLimit > config.MaxLimit {
+		config.DefaultLimit = config.MaxLimit
+	}
+	if config.PageParam == "" {
+		config.PageParam = DefaultPaginationConfig.PageParam
+	}
+	if config.OffsetParam == "" {
+		config.OffsetParam = DefaultPaginationConfig.OffsetParam
+	}
+	if config.CursorParam == "" {
+		config.CursorParam = DefaultPaginationConfig.CursorParam
+	}
+	if config.LimitParam == "" {
+		config.LimitParam = DefaultPaginationConfig.LimitParam
+	}
+	config.BasePath = strings.TrimSuffix(config.BasePath, "/")
+	return config
+}
+
+// paginationInt parses query param as integer in range [lower, upper]. Negative upper means no upper bound.
+func paginationInt(query url.Values, name string, defaultValue int, lower int, upper int) (int, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return defaultValue, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid query param %q: not an integer", name)).SetInternal(err)
+	}
+	if upper < 0 && v < lower {
+		return 0, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid query param %q: must be at least %d", name, lower))
+	}
+	if upper >= 0 && (v < lower || v > upper) {
+		return 0, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid query param %q: must be between %d and %d", name, lower, upper))
+	}
+	return v, nil
+}
+
+// WriteLinkHeaders adds RFC 8288 `Link` header with `first`, `prev`, `next` and `last` links and sets `X-Total-Count`
+// header. Links point to the request path (prefixed with `PaginationConfig.BasePath`) and keep other query params of
+// the request. Negative total means that total is unknown: `last` link and `X-Total-Count` are omitted and `next` link
+// is always written. For PaginationCursor style `prev` and `next` links are written from PrevCursor and NextCursor and
+// there is no `last` link.
+func (p *Pagination) WriteLinkHeaders(c Context, total int) {
+	config := p.config
+	var links []string
+	add := func(rel string, params map[string]string) {
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, p.linkURL(c, params), rel))
+	}
+
+	switch config.Style {
+	case PaginationCursor:
+		add("first", map[string]string{config.CursorParam: ""})
+		if p.PrevCursor != nil {
+			add("prev", map[string]string{config.CursorParam: EncodeCursor(p.PrevCursor)})
+		}
+		if p.NextCursor != nil {
+			add("next", map[string]string{config.CursorParam: EncodeCursor(p.NextCursor)})
+		}
+	default:
+		offsetLink := func(offset int) map[string]string {
+			if config.Style == PaginationOffset {
+				return map[string]string{config.OffsetParam: strconv.Itoa(offset)}
+			}
+			return map[string]string{config.PageParam: strconv.Itoa(offset/p.Limit + 1)}
+		}
+		add("first", offsetLink(0))
+		if p.Offset > 0 {
+			prev := p.Offset - p.Limit
+			if prev < 0 {
+				prev = 0
+			}
+			add("prev", offsetLink(prev))
+		}
+		if total < 0 || p.Offset+p.Limit < total {
+			add("next", offsetLink(p.Offset+p.Limit))
+		}
+		if total >= 0 {
+			last := 0
+			if total > 0 {
+				last = (total - 1) / p.Limit * p.Limit
+			}
+			add("last", offsetLink(last))
+		}
+	}
+
+	header := c.Response().Header()
+	header.Add(HeaderLink, strings.Join(links, ", "))
+	if total >= 0 {
+		header.Set(HeaderXTotalCount, strconv.Itoa(total))
+	}
+}
+
+// linkURL returns path and query of the request with given query params replaced. Empty value removes the param.
+func (p *Pagination) linkURL(c Context, params map[string]string) string {
+	u := c.Request().URL
+	query := url.Values{}
+	for name, values := range c.QueryParams() {
+		query[name] = append([]string(nil), values...)
+	}
+	for name, value := range params {
+		if value == "" {
+			query.Del(name)
+		} else {
+			query.Set(name, value)
+		}
+	}
+	query.Set(p.config.LimitParam, strconv.Itoa(p.Limit))
+
+	link := p.config.BasePath + u.EscapedPath()
+	if encoded := query.Encode(); encoded != "" {
+		link += "?" + encoded
+	}
+	return link
+}
@@ -0,0 +1,195 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindPagination(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		givenConfig PaginationConfig
+		whenQuery   string
+		expect      Pagination
+		expectErr   string
+	}{
+		{
+			name:   "ok, page number defaults",
+			expect: Pagination{Page: 1, Offset: 0, Limit: 20},
+		},
+		{
+			name:        "ok, page number",
+			givenConfig: PaginationConfig{DefaultLimit: 10, MaxLimit: 50},
+			whenQuery:   "page=3&limit=25",
+			expect:      Pagination{Page: 3, Offset: 50, Limit: 25},
+		},
+		{
+			name:        "ok, offset",
+			givenConfig: PaginationConfig{Style: PaginationOffset},
+			whenQuery:   "offset=15",
+			expect:      Pagination{Offset: 15, Limit: 20},
+		},
+		{
+			name:        "ok, cursor",
+			givenConfig: PaginationConfig{Style: PaginationCursor},
+			whenQuery:   "cursor=" + EncodeCursor([]byte(`{"id":42}`)) + "&limit=5",
+			expect:      Pagination{Cursor: []byte(`{"id":42}`), Limit: 5},
+		},
+		{
+			name:        "ok, custom param names",
+			givenConfig: PaginationConfig{PageParam: "p", LimitParam: "per_page"},
+			whenQuery:   "p=2&per_page=5&page=9",
+			expect:      Pagination{Page: 2, Offset: 5, Limit: 5},
+		},
+		{
+			name:      "nok, limit above max",
+			whenQuery: "limit=101",
+			expectErr: `code=400, message=invalid query param "limit": must be between 1 and 100`,
+		},
+		{
+			name:      "nok, zero limit",
+			whenQuery: "limit=0",
+			expectErr: `code=400, message=invalid query param "limit": must be between 1 and 100`,
+		},
+		{
+			name:      "nok, page below 1",
+			whenQuery: "page=0",
+			expectErr: `code=400, message=invalid query param "page": must be at least 1`,
+		},
+		{
+			name:      "nok, page not an integer",
+			whenQuery: "page=two",
+			expectErr: `code=400, message=invalid query param "page": not an integer, internal=strconv.Atoi: parsing "two": invalid syntax`,
+		},
+		{
+			name:      "nok, page overflows offset",
+			whenQuery: "page=9223372036854775807&limit=100",
+			expectErr: `code=400, message=invalid query param "page": out of range`,
+		},
+		{
+			name:        "nok, negative offset",
+			givenConfig: PaginationConfig{Style: PaginationOffset},
+			whenQuery:   "offset=-1",
+			expectErr:   `code=400, message=invalid query param "offset": must be at least 0`,
+		},
+		{
+			name:        "nok, malformed cursor",
+			givenConfig: PaginationConfig{Style: PaginationCursor},
+			whenQuery:   "cursor=%2A%2A",
+			expectErr:   `code=400, message=invalid query param "cursor": malformed cursor, internal=illegal base64 data at input byte 0`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/users?"+tc.whenQuery, nil), httptest.NewRecorder())
+
+			p, err := BindPagination(c, tc.givenConfig)
+
+			if tc.expectErr != "" {
+				assert.EqualError(t, err, tc.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			p.config = PaginationConfig{}
+			assert.Equal(t, tc.expect, *p)
+		})
+	}
+}
+
+func TestPagination_WriteLinkHeaders(t *testing.T) {
+	var testCases = []struct {
+		name             string
+		givenConfig      PaginationConfig
+		givenNextCursor  []byte
+		whenURL          string
+		whenTotal        int
+		expectLink       string
+		expectTotalCount string
+	}{
+		{
+			name:             "ok, middle page keeps other query params",
+			whenURL:          "/users?page=2&limit=10&sort=name",
+			whenTotal:        35,
+			expectLink:       `</users?limit=10&page=1&sort=name>; rel="first", </users?limit=10&page=1&sort=name>; rel="prev", </users?limit=10&page=3&sort=name>; rel="next", </users?limit=10&page=4&sort=name>; rel="last"`,
+			expectTotalCount: "35",
+		},
+		{
+			name:             "ok, first page behind proxy prefix",
+			givenConfig:      PaginationConfig{BasePath: "/api/v1/"},
+			whenURL:          "/users/a%2Fb/items?limit=10",
+			whenTotal:        20,
+			expectLink:       `</api/v1/users/a%2Fb/items?limit=10&page=1>; rel="first", </api/v1/users/a%2Fb/items?limit=10&page=2>; rel="next", </api/v1/users/a%2Fb/items?limit=10&page=2>; rel="last"`,
+			expectTotalCount: "20",
+		},
+		{
+			name:             "ok, offset last page",
+			givenConfig:      PaginationConfig{Style: PaginationOffset},
+			whenURL:          "/users?offset=25&limit=10",
+			whenTotal:        35,
+			expectLink:       `</users?limit=10&offset=0>; rel="first", </users?limit=10&offset=15>; rel="prev", </users?limit=10&offset=30>; rel="last"`,
+			expectTotalCount: "35",
+		},
+		{
+			name:       "ok, unknown total",
+			whenURL:    "/users",
+			whenTotal:  -1,
+			expectLink: `</users?limit=20&page=1>; rel="first", </users?limit=20&page=2>; rel="next"`,
+		},
+		{
+			name:             "ok, empty result",
+			whenURL:          "/users",
+			whenTotal:        0,
+			expectLink:       `</users?limit=20&page=1>; rel="first", </users?limit=20&page=1>; rel="last"`,
+			expectTotalCount: "0",
+		},
+		{
+			name:            "ok, cursor",
+			givenConfig:     PaginationConfig{Style: PaginationCursor, BasePath: "/api"},
+			givenNextCursor: []byte("43"),
+			whenURL:         "/users?cursor=NDI&q=x",
+			whenTotal:       -1,
+			expectLink:      `</api/users?limit=20&q=x>; rel="first", </api/users?cursor=NDM&limit=20&q=x>; rel="next"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			handler := func(c Context) error {
+				p, err := BindPagination(c, tc.givenConfig)
+				if err != nil {
+					return err
+				}
+				p.NextCursor = tc.givenNextCursor
+				p.WriteLinkHeaders(c, tc.whenTotal)
+				return c.NoContent(http.StatusOK)
+			}
+			e.GET("/users", handler)
+			e.GET("/users/:id/items", handler)
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.whenURL, nil))
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tc.expectLink, rec.Header().Get(HeaderLink))
+			assert.Equal(t, tc.expectTotalCount, rec.Header().Get(HeaderXTotalCount))
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	for _, cursor := range []string{"e30", "e30="} {
+		b, err := DecodeCursor(cursor)
+		assert.NoError(t, err)
+		assert.Equal(t, "{}", string(b))
+	}
+	assert.Equal(t, "e30", EncodeCursor([]byte("{}")))
+}
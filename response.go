@@ -6,10 +6,16 @@ package echo
 import (
 	"bufio"
 	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"sync/atomic"
 )
 
+// ErrResponseReleased is returned when response is written after the request has been completed (ala by goroutine
+// that leaked echo.Context). See `Echo#GuardReleasedResponse`.
+var ErrResponseReleased = errors.New("echo: response written after request has been completed")
+
 // Response wraps an http.ResponseWriter and implements its interface to be used
 // by an HTTP handler to construct an HTTP response.
 // See: https://golang.org/pkg/net/http/#ResponseWriter
@@ -18,9 +24,21 @@ type Response struct {
 	echo        *Echo
 	beforeFuncs []func()
 	afterFuncs  []func()
+	statusFuncs []func(status int) int
 	Status      int
 	Size        int64
 	Committed   bool
+	// DisableCompression tells compression middlewares (ala Gzip) to write the response as is. Must be set before
+	// the response is committed.
+	DisableCompression bool
+
+	// fullDuplex is set by EnableFullDuplex. Unread request body of full duplex response is not drained.
+	fullDuplex bool
+
+	// released is set when the request has been completed and writes must fail. See `Echo#GuardReleasedResponse`.
+	released      atomic.Bool
+	releasedPanic bool
+	releasedRoute string
 }
 
 // NewResponse creates a new instance of Response.
@@ -43,6 +61,22 @@ func (r *Response) Before(fn func()) {
 	r.beforeFuncs = append(r.beforeFuncs, fn)
 }
 
+// BeforeWriteHeader registers a function which can change the status code when WriteHeader is called, before the
+// status is sent to the client and before functions registered with `Before` are called (so they see the final
+// status). Functions are called once in registration order and each receives status returned by the previous one.
+//
+// Example: map 404 of the proxied backend to 204 without buffering the response
+//
+//	c.Response().BeforeWriteHeader(func(status int) int {
+//		if status == http.StatusNotFound {
+//			return http.StatusNoContent
+//		}
+//		return status
+//	})
+func (r *Response) BeforeWriteHeader(fn func(status int) int) {
+	r.statusFuncs = append(r.statusFuncs, fn)
+}
+
 // After registers a function which is called just after the response is written.
 // If the `Content-Length` is unknown, none of the after function is executed.
 func (r *Response) After(fn func()) {
@@ -54,11 +88,26 @@ func (r *Response) After(fn func()) {
 // WriteHeader(http.StatusOK). Thus explicit calls to WriteHeader are mainly
 // used to send error codes.
 func (r *Response) WriteHeader(code int) {
+	if r.released.Load() {
+		r.misuse()
+		return
+	}
 	if r.Committed {
 		r.echo.Logger.Warn("response already committed")
 		return
 	}
 	r.Status = code
+	if len(r.statusFuncs) > 0 {
+		funcs := r.statusFuncs
+		r.statusFuncs = nil // functions must not run twice even when they write the response themselves
+		for _, fn := range funcs {
+			status := fn(r.Status)
+			if r.Committed {
+				return // function has written the response itself
+			}
+			r.Status = status
+		}
+	}
 	for _, fn := range r.beforeFuncs {
 		fn()
 	}
@@ -68,6 +117,10 @@ func (r *Response) WriteHeader(code int) {
 
 // Write writes the data to the connection as part of an HTTP reply.
 func (r *Response) Write(b []byte) (n int, err error) {
+	if r.released.Load() {
+		r.misuse()
+		return 0, ErrResponseReleased
+	}
 	if !r.Committed {
 		if r.Status == 0 {
 			r.Status = http.StatusOK
@@ -86,6 +139,10 @@ func (r *Response) Write(b []byte) (n int, err error) {
 // buffered data to the client.
 // See [http.Flusher](https://golang.org/pkg/net/http/#Flusher)
 func (r *Response) Flush() {
+	if r.released.Load() {
+		r.misuse()
+		return
+	}
 	err := http.NewResponseController(r.Writer).Flush()
 	if err != nil && errors.Is(err, http.ErrNotSupported) {
 		panic(errors.New("response writer flushing is not supported"))
@@ -99,6 +156,27 @@ func (r *Response) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	return http.NewResponseController(r.Writer).Hijack()
 }
 
+// EnableFullDuplex enables full duplex mode of the underlying response writer so request body can be read after the
+// response has started (see `http.ResponseController#EnableFullDuplex`). Echo does not drain unread request body of
+// full duplex responses (see `Echo#MaxBodyDrain`).
+func (r *Response) EnableFullDuplex() error {
+	w := r.Writer
+	for {
+		switch t := w.(type) {
+		case interface{ EnableFullDuplex() error }:
+			if err := t.EnableFullDuplex(); err != nil {
+				return err
+			}
+			r.fullDuplex = true
+			return nil
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = t.Unwrap()
+		default:
+			return http.ErrNotSupported
+		}
+	}
+}
+
 // Unwrap returns the original http.ResponseWriter.
 // ResponseController can be used to access the original http.ResponseWriter.
 // See [https://go.dev/blog/go1.20]
@@ -109,8 +187,27 @@ func (r *Response) Unwrap() http.ResponseWriter {
 func (r *Response) reset(w http.ResponseWriter) {
 	r.beforeFuncs = nil
 	r.afterFuncs = nil
+	r.statusFuncs = nil
 	r.Writer = w
 	r.Size = 0
 	r.Status = http.StatusOK
 	r.Committed = false
+	r.DisableCompression = false
+	r.fullDuplex = false
+	r.released.Store(false)
+}
+
+// release marks the response of completed request so following writes panic (when panics is true) or fail with
+// ErrResponseReleased.
+func (r *Response) release(route string, panics bool) {
+	r.releasedRoute = route
+	r.releasedPanic = panics
+	r.released.Store(true)
+}
+
+func (r *Response) misuse() {
+	if r.releasedPanic {
+		panic(fmt.Sprintf("echo: response written after request to route %s has been completed, "+
+			"handler leaked echo.Context (use Context#Clone for work that outlives the request)", r.releasedRoute))
+	}
 }
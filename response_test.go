@@ -101,6 +101,76 @@ func TestResponse_ChangeStatusCodeBeforeWrite(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 }
 
+func TestResponse_BeforeWriteHeader(t *testing.T) {
+	e := New()
+	rec := httptest.NewRecorder()
+	res := &Response{echo: e, Writer: rec}
+
+	var calls []int
+	res.BeforeWriteHeader(func(status int) int {
+		calls = append(calls, status)
+		if status == http.StatusNotFound {
+			return http.StatusServiceUnavailable
+		}
+		return status
+	})
+	res.BeforeWriteHeader(func(status int) int {
+		calls = append(calls, status)
+		return status
+	})
+	res.Before(func() {
+		if res.Status == http.StatusServiceUnavailable {
+			res.Header().Set(HeaderRetryAfter, "30")
+		}
+	})
+
+	res.WriteHeader(http.StatusNotFound)
+	res.WriteHeader(http.StatusNotFound) // already committed, hooks must not run again
+	_, _ = res.Write([]byte("test"))
+
+	assert.Equal(t, []int{http.StatusNotFound, http.StatusServiceUnavailable}, calls)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, http.StatusServiceUnavailable, res.Status)
+	assert.Equal(t, "30", rec.Header().Get(HeaderRetryAfter))
+}
+
+func TestResponse_BeforeWriteHeader_hookWritesResponse(t *testing.T) {
+	e := New()
+	rec := httptest.NewRecorder()
+	res := &Response{echo: e, Writer: rec}
+
+	calls := 0
+	res.BeforeWriteHeader(func(status int) int {
+		calls++
+		if status == http.StatusNotFound {
+			res.WriteHeader(http.StatusNoContent)
+		}
+		return status
+	})
+
+	res.WriteHeader(http.StatusNotFound)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, http.StatusNoContent, res.Status)
+}
+
+func TestResponse_BeforeWriteHeader_implicitStatus(t *testing.T) {
+	e := New()
+	e.GET("/", func(c Context) error {
+		c.Response().BeforeWriteHeader(func(status int) int {
+			return http.StatusAccepted
+		})
+		return c.String(http.StatusOK, "ok")
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, "ok", rec.Body.String())
+}
+
 func TestResponse_Unwrap(t *testing.T) {
 	e := New()
 	rec := httptest.NewRecorder()
@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"encoding/json"
+	"reflect"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// routeMeta holds settings of route options and documentation of the route added to the router. It is kept apart
+// from Route so Route stays plain method, path and name.
+type routeMeta struct {
+	router *Router
+
+	bodyLimit          int64
+	timeout            time.Duration
+	slo                time.Duration
+	bodyReadTimeout    time.Duration
+	maxMultipartMemory int64
+	maxParamSegments   int
+	scopes             []string
+
+	description        string
+	deprecated         bool
+	deprecationMessage string
+	sunset             *time.Time
+}
+
+// routeMetas maps address of the route added to the router to its *routeMeta. Entry is removed by finalizer of the
+// route so routes of discarded routers (ala replaced by route with the same method and path) do not leak.
+var routeMetas sync.Map
+
+func routeKey(r *Route) uintptr {
+	return reflect.ValueOf(r).Pointer()
+}
+
+// registerRouteMeta creates meta of the route added to the router with settings of route options.
+func registerRouteMeta(route *Route, router *Router, options *routeOptions) {
+	m := &routeMeta{router: router}
+	if options != nil {
+		m.bodyLimit = options.bodyLimit
+		m.timeout = options.timeout
+		m.slo = options.slo
+		m.bodyReadTimeout = options.bodyReadTimeout
+		m.maxMultipartMemory = options.maxMultipartMemory
+		m.maxParamSegments = options.maxParamSegments
+		m.scopes = options.scopes
+	}
+	routeMetas.Store(routeKey(route), m)
+	runtime.SetFinalizer(route, func(r *Route) {
+		routeMetas.Delete(routeKey(r))
+	})
+}
+
+// meta returns meta of the route. Returns nil for routes that have not been added to the router (ala copies).
+func (r *Route) meta() *routeMeta {
+	if r == nil {
+		return nil
+	}
+	if m, ok := routeMetas.Load(routeKey(r)); ok {
+		return m.(*routeMeta)
+	}
+	return nil
+}
+
+// BodyLimit returns maximum allowed request body size in bytes set with `RouteBodyLimit` route option.
+func (r *Route) BodyLimit() int64 {
+	if m := r.meta(); m != nil {
+		return m.bodyLimit
+	}
+	return 0
+}
+
+// Timeout returns request context timeout set with `RouteTimeout` route option.
+func (r *Route) Timeout() time.Duration {
+	if m := r.meta(); m != nil {
+		return m.timeout
+	}
+	return 0
+}
+
+// SLO returns latency budget set with `RouteSLO` route option.
+func (r *Route) SLO() time.Duration {
+	if m := r.meta(); m != nil {
+		return m.slo
+	}
+	return 0
+}
+
+// BodyReadTimeout returns request body read timeout set with `RouteBodyReadTimeout` route option.
+func (r *Route) BodyReadTimeout() time.Duration {
+	if m := r.meta(); m != nil {
+		return m.bodyReadTimeout
+	}
+	return 0
+}
+
+// MaxMultipartMemory returns multipart form memory limit set with `RouteMaxMultipartMemory` route option.
+func (r *Route) MaxMultipartMemory() int64 {
+	if m := r.meta(); m != nil {
+		return m.maxMultipartMemory
+	}
+	return 0
+}
+
+// MaxParamSegments returns maximum number of wildcard segments set with `RouteMaxParamSegments` route option.
+func (r *Route) MaxParamSegments() int {
+	if m := r.meta(); m != nil {
+		return m.maxParamSegments
+	}
+	return 0
+}
+
+// Scopes returns access scopes required by the route set with `RequireScopes` route option.
+func (r *Route) Scopes() []string {
+	if m := r.meta(); m != nil {
+		return m.scopes
+	}
+	return nil
+}
+
+// Description returns human readable description of the route set with `Route#SetDescription`.
+func (r *Route) Description() string {
+	if m := r.meta(); m != nil {
+		return m.description
+	}
+	return ""
+}
+
+// Deprecated reports whether route is marked with `Route#SetDeprecated` or `Route#SetSunset`.
+func (r *Route) Deprecated() bool {
+	if m := r.meta(); m != nil {
+		return m.deprecated
+	}
+	return false
+}
+
+// DeprecationMessage returns message set with `Route#SetDeprecated` that tells clients what to use instead.
+func (r *Route) DeprecationMessage() string {
+	if m := r.meta(); m != nil {
+		return m.deprecationMessage
+	}
+	return ""
+}
+
+// Sunset returns time after which deprecated route is expected to be removed, set with `Route#SetSunset`.
+func (r *Route) Sunset() *time.Time {
+	if m := r.meta(); m != nil {
+		return m.sunset
+	}
+	return nil
+}
+
+// MarshalJSON encodes route with settings of its route options and documentation.
+func (r *Route) MarshalJSON() ([]byte, error) {
+	type routeJSON struct {
+		Method             string        `json:"method"`
+		Path               string        `json:"path"`
+		Name               string        `json:"name"`
+		BodyLimit          int64         `json:"body_limit,omitempty"`
+		Timeout            time.Duration `json:"timeout,omitempty"`
+		SLO                time.Duration `json:"slo,omitempty"`
+		BodyReadTimeout    time.Duration `json:"body_read_timeout,omitempty"`
+		MaxMultipartMemory int64         `json:"max_multipart_memory,omitempty"`
+		MaxParamSegments   int           `json:"max_param_segments,omitempty"`
+		Scopes             []string      `json:"scopes,omitempty"`
+		Description        string        `json:"description,omitempty"`
+		Deprecated         bool          `json:"deprecated,omitempty"`
+		DeprecationMessage string        `json:"deprecation_message,omitempty"`
+		Sunset             *time.Time    `json:"sunset,omitempty"`
+	}
+	result := routeJSON{Method: r.Method, Path: r.Path, Name: r.Name}
+	if m := r.meta(); m != nil {
+		result.BodyLimit = m.bodyLimit
+		result.Timeout = m.timeout
+		result.SLO = m.slo
+		result.BodyReadTimeout = m.bodyReadTimeout
+		result.MaxMultipartMemory = m.maxMultipartMemory
+		result.MaxParamSegments = m.maxParamSegments
+		result.Scopes = m.scopes
+		result.Description = m.description
+		result.Deprecated = m.deprecated
+		result.DeprecationMessage = m.deprecationMessage
+		result.Sunset = m.sunset
+	}
+	return json.Marshal(result)
+}
@@ -0,0 +1,382 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	stdContext "context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/gommon/bytes"
+	"github.com/labstack/gommon/log"
+)
+
+// routeOptions holds settings attached to route at registration time. Route options are enforced by Echo right after
+// the route has been matched. Routes without options (and outside of groups with middlewares) have nil routeOptions
+// and cost nothing.
+type routeOptions struct {
+	bodyLimit int64
+	timeout   time.Duration
+	slo       time.Duration
+	// bodyReadTimeout overrides `Echo#BodyReadTimeout` for the route
+	bodyReadTimeout time.Duration
+	// maxMultipartMemory overrides `Echo#MaxMultipartMemory` for the route
+	maxMultipartMemory int64
+	// maxParamSegments is maximum number of segments of the wildcard path parameter
+	maxParamSegments int
+	// scopes are access scopes required by the route (see `Context#RouteScopes`)
+	scopes []string
+	// headers are response headers set by RouteResponseHeaders options. Inner-most (route) value wins.
+	headers map[string]routeHeader
+	// groupMiddlewares are middlewares of the group the route belongs to. Automatic OPTIONS responses of the route
+	// path are served through them so group level middlewares (ala CORS) can answer preflight requests.
+	groupMiddlewares []MiddlewareFunc
+}
+
+// RouteOption is setting of the route (ala body limit or timeout) that is enforced by Echo right after the route has
+// been matched. Route options are given as route or group middlewares (or with `Echo#WithRouteOptions` and
+// `Group#WithRouteOptions`) but are not executed as middlewares. Adding route option with `Echo#Use` or `Echo#Pre`
+// panics.
+//
+// Example: `e.POST("/upload", handler, echo.RouteBodyLimit("100M"), echo.RouteTimeout(5*time.Minute))`
+type RouteOption = MiddlewareFunc
+
+// newRouteOption creates route option that applies its setting with apply. Name is reported by `Echo#MiddlewareChain`
+// and is given only to options that process the response.
+func newRouteOption(name string, apply func(o *routeOptions)) RouteOption {
+	return markedMiddleware(&middlewareMark{name: name, routeOption: apply}, nil)
+}
+
+// routeOptionOf returns function applying setting of the route option. Returns nil when m is not route option.
+func routeOptionOf(m MiddlewareFunc) func(o *routeOptions) {
+	if mark := markOf(m); mark != nil {
+		return mark.routeOption
+	}
+	return nil
+}
+
+// splitRouteOptions moves route options from middlewares to options. Given slices are not modified.
+func splitRouteOptions(options []RouteOption, middlewares []MiddlewareFunc) ([]RouteOption, []MiddlewareFunc) {
+	hasOptions := false
+	for _, m := range middlewares {
+		if routeOptionOf(m) != nil {
+			hasOptions = true
+			break
+		}
+	}
+	if !hasOptions {
+		return options, middlewares
+	}
+	resultOptions := append(make([]RouteOption, 0, len(options)+len(middlewares)), options...)
+	rest := make([]MiddlewareFunc, 0, len(middlewares))
+	for _, m := range middlewares {
+		if routeOptionOf(m) != nil {
+			resultOptions = append(resultOptions, m)
+		} else {
+			rest = append(rest, m)
+		}
+	}
+	return resultOptions, rest
+}
+
+// newRouteOptions applies options in order. Returns nil when there are no options. Panics when option is not route
+// option.
+func newRouteOptions(options []RouteOption) *routeOptions {
+	var result *routeOptions
+	for _, option := range options {
+		apply := routeOptionOf(option)
+		if apply == nil {
+			panic(fmt.Errorf("echo: %s is not a route option", middlewareName(option)))
+		}
+		if result == nil {
+			result = new(routeOptions)
+		}
+		apply(result)
+	}
+	return result
+}
+
+// RouteBodyLimit returns route option that sets the maximum allowed size for a request body of the route. Limit can be
+// specified as `4x` or `4xB`, where x is one of the multiple from K, M, G, T or P. Requests with bigger body result
+// "413 - Request Entity Too Large" error. When multiple body limits apply (ala BodyLimit middleware) the stricter one wins.
+//
+// Example: `e.POST("/upload", handler, echo.RouteBodyLimit("100M"))`
+func RouteBodyLimit(limit string) RouteOption {
+	l, err := bytes.Parse(limit)
+	if err != nil {
+		panic(fmt.Errorf("echo: invalid route body limit=%s", limit))
+	}
+	return newRouteOption("", func(o *routeOptions) {
+		if o.bodyLimit == 0 || l < o.bodyLimit {
+			o.bodyLimit = l
+		}
+	})
+}
+
+// RouteTimeout returns route option that sets timeout to request context of the route. Handler must respect context
+// cancellation. Errors wrapping `context.DeadlineExceeded` returned by the route handler are converted to
+// "503 - Service Unavailable" error. When multiple timeouts apply the stricter one wins.
+//
+// Example: `e.POST("/upload", handler, echo.RouteTimeout(5*time.Minute))`
+func RouteTimeout(timeout time.Duration) RouteOption {
+	if timeout <= 0 {
+		panic(fmt.Errorf("echo: invalid route timeout=%v", timeout))
+	}
+	return newRouteOption("", func(o *routeOptions) {
+		if o.timeout == 0 || timeout < o.timeout {
+			o.timeout = timeout
+		}
+	})
+}
+
+// RouteSLO returns route option that sets latency budget of the route. After the response has been written, requests
+// that took longer than the budget are reported with `Echo#OnSLOExceeded` (by default warning is logged). Response is
+// not affected. When multiple budgets apply (ala group and route) the last one (most specific) wins.
+// Routes without budget use `Echo#DefaultRouteSLO`.
+//
+// Example: `e.GET("/search", handler, echo.RouteSLO(300*time.Millisecond))`
+func RouteSLO(budget time.Duration) RouteOption {
+	if budget <= 0 {
+		panic(fmt.Errorf("echo: invalid route SLO=%v", budget))
+	}
+	return newRouteOption("", func(o *routeOptions) {
+		o.slo = budget
+	})
+}
+
+// RouteBodyReadTimeout returns route option that overrides `Echo#BodyReadTimeout` for the route. When multiple
+// timeouts apply (ala group and route) the last one (most specific) wins.
+//
+// Example: `e.POST("/upload", handler, echo.RouteBodyReadTimeout(5*time.Minute))`
+func RouteBodyReadTimeout(timeout time.Duration) RouteOption {
+	if timeout <= 0 {
+		panic(fmt.Errorf("echo: invalid route body read timeout=%v", timeout))
+	}
+	return newRouteOption("", func(o *routeOptions) {
+		o.bodyReadTimeout = timeout
+	})
+}
+
+// RouteMaxMultipartMemory returns route option that overrides `Echo#MaxMultipartMemory` for the route. Size can be
+// specified as `4x` or `4xB`, where x is one of the multiple from K, M, G, T or P. When multiple values apply (ala group
+// and route) the last one (most specific) wins.
+//
+// Example: `e.POST("/upload", handler, echo.RouteMaxMultipartMemory("1M"))`
+func RouteMaxMultipartMemory(size string) RouteOption {
+	l, err := bytes.Parse(size)
+	if err != nil || l <= 0 {
+		panic(fmt.Errorf("echo: invalid route max multipart memory=%s", size))
+	}
+	return newRouteOption("", func(o *routeOptions) {
+		o.maxMultipartMemory = l
+	})
+}
+
+// RouteMaxParamSegments returns route option that limits number of segments (see `Context#ParamSegments`) of the
+// wildcard path parameter of the route. Requests with more segments result "404 - Not Found" error. When multiple
+// values apply (ala group and route) the last one (most specific) wins.
+//
+// Example: `e.GET("/files/*", handler, echo.RouteMaxParamSegments(10))`
+func RouteMaxParamSegments(max int) RouteOption {
+	if max <= 0 {
+		panic(fmt.Errorf("echo: invalid route max param segments=%d", max))
+	}
+	return newRouteOption("", func(o *routeOptions) {
+		o.maxParamSegments = max
+	})
+}
+
+// RequireScopes returns route option that declares access scopes (ala `users:write`) required by the route. Scopes are
+// enforced by authorization middleware (see `middleware.Authorize`) that reads them with `Context#RouteScopes`. Scopes
+// of group and route are combined. Empty scope or no scopes panic.
+//
+// Example: `e.POST("/users", handler, echo.RequireScopes("users:write"))`
+func RequireScopes(scopes ...string) RouteOption {
+	if len(scopes) == 0 {
+		panic(errors.New("echo: invalid route scopes, at least one scope is required"))
+	}
+	for _, scope := range scopes {
+		if scope == "" || strings.ContainsAny(scope, " \t\r\n") {
+			panic(fmt.Errorf("echo: invalid route scope=%q", scope))
+		}
+	}
+	scopes = append([]string(nil), scopes...)
+	return newRouteOption("", func(o *routeOptions) {
+	next:
+		for _, scope := range scopes {
+			for _, existing := range o.scopes {
+				if existing == scope {
+					continue next
+				}
+			}
+			o.scopes = append(o.scopes, scope)
+		}
+	})
+}
+
+// routeHeader is response header value set by RouteResponseHeaders option.
+type routeHeader struct {
+	value    string
+	override bool
+}
+
+// RouteResponseHeaders returns route option that adds headers to the response of the route. Headers are set just
+// before the response is written and only when the handler (or middleware) has not set the header itself. Headers of
+// group (see `Group#ResponseHeaders`) and route are merged and the inner-most value wins. Invalid header names or
+// values containing newlines panic.
+//
+// Example:
+//
+//	static := g.WithRouteOptions(echo.RouteResponseHeaders(map[string]string{"Cache-Control": "max-age=3600"}))
+//	static.GET("/static/*", handler)
+func RouteResponseHeaders(headers map[string]string) RouteOption {
+	return newRouteHeadersOption(headers, false)
+}
+
+// RouteResponseHeadersOverride returns route option that works as `RouteResponseHeaders` but replaces values set by
+// the handler.
+func RouteResponseHeadersOverride(headers map[string]string) RouteOption {
+	return newRouteHeadersOption(headers, true)
+}
+
+func newRouteHeadersOption(headers map[string]string, override bool) RouteOption {
+	canonical := make(map[string]routeHeader, len(headers))
+	for name, value := range headers {
+		if !isValidHeaderName(name) {
+			panic(fmt.Errorf("echo: invalid response header name=%q", name))
+		}
+		if strings.ContainsAny(value, "\r\n\x00") {
+			panic(fmt.Errorf("echo: invalid value of response header=%s", name))
+		}
+		canonical[http.CanonicalHeaderKey(name)] = routeHeader{value: value, override: override}
+	}
+	return newRouteOption("echo.RouteResponseHeaders", func(o *routeOptions) {
+		if o.headers == nil {
+			o.headers = make(map[string]routeHeader, len(canonical))
+		}
+		for name, h := range canonical {
+			o.headers[name] = h
+		}
+	})
+}
+
+// isValidHeaderName reports whether name is non-empty and consists of RFC 7230 token characters.
+func isValidHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		ch := name[i]
+		switch {
+		case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9':
+		case strings.IndexByte("!#$%&'*+-.^_`|~", ch) != -1:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// applyRouteOptions enforces options of the matched route.
+func (c *context) applyRouteOptions() {
+	options := c.routeOptions
+	if options == nil {
+		return
+	}
+	if len(options.headers) > 0 {
+		res := c.response
+		res.Before(func() {
+			header := res.Header()
+			for name, h := range options.headers {
+				if h.override || header.Get(name) == "" {
+					header.Set(name, h.value)
+				}
+			}
+		})
+	}
+	if options.maxParamSegments > 0 {
+		if wildcard := c.RawParam("*"); wildcard != "" && strings.Count(wildcard, "/") >= options.maxParamSegments {
+			c.handler = func(Context) error {
+				return ErrNotFound
+			}
+			return
+		}
+	}
+	req := c.request
+	if options.bodyLimit > 0 {
+		if req.ContentLength > options.bodyLimit {
+			c.handler = func(Context) error {
+				return ErrStatusRequestEntityTooLarge
+			}
+			return
+		}
+		if req.Body != nil {
+			req.Body = &limitedBody{ReadCloser: req.Body, limit: options.bodyLimit}
+		}
+	}
+	if options.timeout > 0 {
+		ctx, cancel := stdContext.WithTimeout(req.Context(), options.timeout)
+		c.cancelRoute = cancel
+		c.SetRequest(req.WithContext(ctx))
+
+		h := c.handler
+		c.handler = func(c Context) error {
+			err := h(c)
+			if err != nil && errors.Is(err, stdContext.DeadlineExceeded) {
+				return ErrServiceUnavailable.WithInternal(err)
+			}
+			return err
+		}
+	}
+}
+
+// limitedBody is request body reader that fails when more than limit bytes are read.
+type limitedBody struct {
+	io.ReadCloser
+	limit int64
+	read  int64
+}
+
+func (b *limitedBody) Read(p []byte) (n int, err error) {
+	n, err = b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if b.read > b.limit {
+		return n, ErrStatusRequestEntityTooLarge
+	}
+	return n, err
+}
+
+// checkSLO reports request that exceeded latency budget of the matched route.
+func (e *Echo) checkSLO(c *context, start time.Time) {
+	budget := e.DefaultRouteSLO
+	if c.routeOptions != nil && c.routeOptions.slo > 0 {
+		budget = c.routeOptions.slo
+	}
+	if budget <= 0 {
+		return
+	}
+	if latency := time.Since(start); latency > budget && e.OnSLOExceeded != nil {
+		e.OnSLOExceeded(c, budget, latency)
+	}
+}
+
+// DefaultSLOExceededHandler logs warning with route, latency, budget and request ID of the request that exceeded
+// latency budget of the route.
+func DefaultSLOExceededHandler(c Context, budget, latency time.Duration) {
+	requestID := c.Response().Header().Get(HeaderXRequestID)
+	if requestID == "" {
+		requestID = c.Request().Header.Get(HeaderXRequestID)
+	}
+	c.Logger().Warnj(log.JSON{
+		"message":    "request exceeded latency budget",
+		"route":      c.Path(),
+		"latency":    latency.String(),
+		"budget":     budget.String(),
+		"request_id": requestID,
+	})
+}
@@ -0,0 +1,435 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteBodyLimit(t *testing.T) {
+	var testCases = []struct {
+		name              string
+		givenBody         string
+		whenContentLength int64
+		expectStatus      int
+		expectBody        string
+	}{
+		{
+			name:         "ok, body within limit",
+			givenBody:    "1234",
+			expectStatus: http.StatusOK,
+			expectBody:   "1234",
+		},
+		{
+			name:         "nok, content length over limit",
+			givenBody:    "123456",
+			expectStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:              "nok, body over limit with unknown content length",
+			givenBody:         "123456",
+			whenContentLength: -1,
+			expectStatus:      http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.WithRouteOptions(RouteBodyLimit("5B")).POST("/", func(c Context) error {
+				b, err := io.ReadAll(c.Request().Body)
+				if err != nil {
+					return err
+				}
+				return c.String(http.StatusOK, string(b))
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.givenBody))
+			if tc.whenContentLength != 0 {
+				req.ContentLength = tc.whenContentLength
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expectStatus, rec.Code)
+			if tc.expectBody != "" {
+				assert.Equal(t, tc.expectBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRouteOptions_asRouteMiddlewares(t *testing.T) {
+	e := New()
+	api := e.Group("/api", RouteTimeout(time.Minute))
+	route := api.POST("/upload", func(c Context) error {
+		b, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+		return c.String(http.StatusOK, c.Get("mw").(string)+":"+string(b))
+	}, RouteBodyLimit("5B"), func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			c.Set("mw", "route")
+			return next(c)
+		}
+	}, RouteTimeout(time.Second))
+
+	assert.Equal(t, int64(5), route.BodyLimit())
+	assert.Equal(t, time.Second, route.Timeout())
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/upload", strings.NewReader("1234")))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "route:1234", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/upload", strings.NewReader("123456")))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestRouteOptions_globalMiddlewarePanics(t *testing.T) {
+	e := New()
+	assert.PanicsWithError(t, "echo: route option can not be added with Echo#Use, add it to the route or group", func() {
+		e.Use(RouteBodyLimit("1K"))
+	})
+	assert.PanicsWithError(t, "echo: route option can not be added with Echo#Pre, add it to the route or group", func() {
+		e.Pre(RouteTimeout(time.Second))
+	})
+	assert.PanicsWithError(t, "echo: auth is not a route option", func() {
+		e.WithRouteOptions(NamedMiddleware("auth", testChainMiddleware)).GET("/", handlerFunc)
+	})
+}
+
+func TestRouteOptions_routeJSON(t *testing.T) {
+	e := New()
+	route := e.POST("/upload", handlerFunc, RouteBodyLimit("1K")).SetDescription("upload")
+	route.Name = "upload"
+
+	b, err := json.Marshal(e.Routes())
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"method":"POST","path":"/upload","name":"upload","body_limit":1000,"description":"upload"}]`, string(b))
+
+	// copy of the route is not part of the router
+	detached := *route
+	detached.SetDescription("changed")
+	assert.Equal(t, "", detached.Description())
+	assert.Equal(t, "upload", route.Description())
+}
+
+func TestRouteTimeout(t *testing.T) {
+	e := New()
+	e.WithRouteOptions(RouteTimeout(10*time.Millisecond)).GET("/", func(c Context) error {
+		<-c.Request().Context().Done()
+		return c.Request().Context().Err()
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestRouteOptions_stricterWins(t *testing.T) {
+	e := New()
+	g := e.Group("/api").WithRouteOptions(RouteBodyLimit("1K"), RouteTimeout(time.Minute))
+	r := g.WithRouteOptions(RouteBodyLimit("10K"), RouteTimeout(time.Second)).POST("/upload", func(c Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	assert.Equal(t, int64(1000), r.BodyLimit())
+	assert.Equal(t, time.Second, r.Timeout())
+
+	for _, route := range e.Routes() {
+		if route.Path == "/api/upload" && route.Method == http.MethodPost {
+			assert.Equal(t, int64(1000), route.BodyLimit())
+			assert.Equal(t, time.Second, route.Timeout())
+		}
+	}
+}
+
+func TestRouteOptions_middlewareChainIsKept(t *testing.T) {
+	e := New()
+	g := e.Group("", func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			c.Set("mw", "group")
+			return next(c)
+		}
+	})
+	g.WithRouteOptions(RouteBodyLimit("1K")).GET("/", func(c Context) error {
+		return c.String(http.StatusOK, c.Get("mw").(string)+","+c.Get("route").(string))
+	}, func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			c.Set("route", "route")
+			return next(c)
+		}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "group,route", rec.Body.String())
+}
+
+func TestRouteOptions_doNotLeakToParentGroup(t *testing.T) {
+	e := New()
+	api := e.Group("/api").WithRouteOptions(RouteTimeout(time.Minute))
+	withLimit := api.WithRouteOptions(RouteBodyLimit("1K"))
+	sub := api.Group("/v1")
+
+	limited := withLimit.POST("/upload", handlerFunc)
+	plain := api.POST("/plain", handlerFunc)
+	nested := sub.POST("/nested", handlerFunc)
+	root := e.POST("/root", handlerFunc)
+
+	assert.Equal(t, int64(1000), limited.BodyLimit())
+	assert.Equal(t, time.Minute, limited.Timeout())
+	assert.Equal(t, int64(0), plain.BodyLimit())
+	assert.Equal(t, time.Minute, plain.Timeout())
+	assert.Equal(t, "/api/v1/nested", nested.Path)
+	assert.Equal(t, time.Minute, nested.Timeout())
+	assert.Equal(t, time.Duration(0), root.Timeout())
+}
+
+func TestRouteOptions_invalidValuesPanic(t *testing.T) {
+	assert.Panics(t, func() { RouteBodyLimit("x") })
+	assert.Panics(t, func() { RouteTimeout(0) })
+	assert.Panics(t, func() { RouteBodyReadTimeout(-time.Second) })
+	assert.Panics(t, func() { RouteMaxMultipartMemory("0") })
+	assert.Panics(t, func() { RouteMaxParamSegments(0) })
+}
+
+func TestRouteMaxParamSegments(t *testing.T) {
+	e := New()
+	route := e.WithRouteOptions(RouteMaxParamSegments(2)).GET("/files/*", func(c Context) error {
+		return c.String(http.StatusOK, strings.Join(c.ParamSegments("*"), ","))
+	})
+	assert.Equal(t, 2, route.MaxParamSegments())
+
+	for path, expect := range map[string]int{
+		"/files/":        http.StatusOK,
+		"/files/a/b":     http.StatusOK,
+		"/files/a%2Fb/c": http.StatusOK,
+		"/files/a/b/":    http.StatusNotFound,
+		"/files/a/b/c":   http.StatusNotFound,
+	} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, expect, rec.Code, path)
+	}
+}
+
+func TestRequireScopes(t *testing.T) {
+	e := New()
+	handler := func(c Context) error {
+		return c.String(http.StatusOK, strings.Join(c.RouteScopes(), ","))
+	}
+	g := e.Group("/admin").WithRouteOptions(RequireScopes("admin"))
+	route := g.WithRouteOptions(RequireScopes("users:read", "admin")).GET("/users", handler)
+	assert.Equal(t, []string{"admin", "users:read"}, route.Scopes())
+	e.GET("/public", handler)
+
+	for path, expect := range map[string]string{"/admin/users": "admin,users:read", "/public": ""} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+		assert.Equal(t, expect, rec.Body.String(), path)
+	}
+
+	assert.Panics(t, func() { RequireScopes() })
+	assert.Panics(t, func() { RequireScopes("users:read", "") })
+	assert.Panics(t, func() { RequireScopes("users:read users:write") })
+}
+
+func TestRouteMaxMultipartMemory(t *testing.T) {
+	e := New()
+	handler := func(c Context) error {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			return err
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		defer c.Request().MultipartForm.RemoveAll()
+		_, isFile := f.(*os.File)
+		return c.String(http.StatusOK, strconv.FormatBool(isFile))
+	}
+	e.POST("/default", handler)
+	route := e.WithRouteOptions(RouteMaxMultipartMemory("1B")).POST("/small", handler)
+	assert.Equal(t, int64(1), route.MaxMultipartMemory())
+
+	for path, expectOnDisk := range map[string]string{"/default": "false", "/small": "true"} {
+		buf := new(bytes.Buffer)
+		mw := multipart.NewWriter(buf)
+		w, err := mw.CreateFormFile("file", "test.txt")
+		assert.NoError(t, err)
+		w.Write([]byte("This is a test file"))
+		mw.Close()
+
+		req := httptest.NewRequest(http.MethodPost, path, buf)
+		req.Header.Set(HeaderContentType, mw.FormDataContentType())
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, expectOnDisk, rec.Body.String(), path)
+	}
+}
+
+func TestRouteResponseHeaders(t *testing.T) {
+	e := New()
+	api := e.Group("/api")
+	api.ResponseHeaders(map[string]string{"X-API-Version": "1", "cache-control": "no-store", "X-Frame-Options": "DENY"})
+	v2 := api.Group("/v2")
+	v2.ResponseHeaders(map[string]string{"X-API-Version": "2"})
+
+	v2.WithRouteOptions(RouteResponseHeaders(map[string]string{"Cache-Control": "max-age=60"})).GET("/users", func(c Context) error {
+		return c.String(http.StatusOK, "users")
+	})
+	v2.GET("/handler-wins", func(c Context) error {
+		c.Response().Header().Set(HeaderCacheControl, "private")
+		return c.String(http.StatusOK, "ok")
+	})
+	v2.WithRouteOptions(RouteResponseHeadersOverride(map[string]string{"X-Frame-Options": "DENY"})).GET("/override", func(c Context) error {
+		c.Response().Header().Set("X-Frame-Options", "SAMEORIGIN")
+		return c.String(http.StatusOK, "ok")
+	})
+	v2.GET("/error", func(c Context) error {
+		return ErrForbidden
+	})
+	e.GET("/plain", func(c Context) error {
+		return c.String(http.StatusOK, "plain")
+	})
+
+	var testCases = []struct {
+		whenURL      string
+		expectHeader http.Header
+	}{
+		{
+			whenURL: "/api/v2/users",
+			expectHeader: http.Header{
+				"X-Api-Version":   {"2"},
+				"Cache-Control":   {"max-age=60"},
+				"X-Frame-Options": {"DENY"},
+			},
+		},
+		{
+			whenURL: "/api/v2/handler-wins",
+			expectHeader: http.Header{
+				"X-Api-Version": {"2"},
+				"Cache-Control": {"private"},
+			},
+		},
+		{
+			whenURL:      "/api/v2/override",
+			expectHeader: http.Header{"X-Frame-Options": {"DENY"}},
+		},
+		{
+			whenURL:      "/api/v2/error",
+			expectHeader: http.Header{"X-Api-Version": {"2"}, "Cache-Control": {"no-store"}},
+		},
+		{
+			whenURL:      "/api/unknown",
+			expectHeader: http.Header{"X-Api-Version": {"1"}},
+		},
+		{
+			whenURL:      "/plain",
+			expectHeader: http.Header{"X-Api-Version": nil, "Cache-Control": nil},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.whenURL, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.whenURL, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			for name, values := range tc.expectHeader {
+				assert.Equal(t, values, rec.Header().Values(name), name)
+			}
+		})
+	}
+}
+
+func TestRouteResponseHeaders_invalidPanics(t *testing.T) {
+	assert.Panics(t, func() { RouteResponseHeaders(map[string]string{"X-Test": "a\r\nSet-Cookie: x=1"}) })
+	assert.Panics(t, func() { RouteResponseHeaders(map[string]string{"X-Test": "a\nb"}) })
+	assert.Panics(t, func() { RouteResponseHeaders(map[string]string{"X Test": "a"}) })
+	assert.Panics(t, func() { RouteResponseHeaders(map[string]string{"": "a"}) })
+	assert.Panics(t, func() { New().Group("/api").ResponseHeaders(map[string]string{"X-Test": "a\r"}) })
+	assert.NotPanics(t, func() { RouteResponseHeaders(map[string]string{"X-Test": "a\tb"}) })
+}
+
+func TestRouteSLO(t *testing.T) {
+	e := New()
+	type report struct {
+		route  string
+		budget time.Duration
+	}
+	var reports []report
+	e.OnSLOExceeded = func(c Context, budget, latency time.Duration) {
+		assert.Greater(t, latency, budget)
+		reports = append(reports, report{route: c.Path(), budget: budget})
+	}
+	e.DefaultRouteSLO = time.Hour
+
+	slow := func(c Context) error {
+		time.Sleep(5 * time.Millisecond)
+		return c.NoContent(http.StatusOK)
+	}
+	g := e.Group("/api").WithRouteOptions(RouteSLO(time.Millisecond))
+	g.GET("/search", slow)
+	route := g.WithRouteOptions(RouteSLO(time.Minute)).GET("/report", slow)
+	e.GET("/slow", slow)
+
+	for _, u := range []string{"/api/search", "/api/report", "/slow"} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, u, nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	assert.Equal(t, []report{{route: "/api/search", budget: time.Millisecond}}, reports)
+	assert.Equal(t, time.Minute, route.SLO())
+}
+
+func TestDefaultSLOExceededHandler(t *testing.T) {
+	e := New()
+	buf := new(bytes.Buffer)
+	e.Logger.SetOutput(buf)
+	e.Logger.SetLevel(log.WARN)
+	e.WithRouteOptions(RouteSLO(time.Millisecond)).GET("/", func(c Context) error {
+		time.Sleep(2 * time.Millisecond)
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderXRequestID, "rid")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Contains(t, buf.String(), `"level":"WARN"`)
+	assert.Contains(t, buf.String(), `"message":"request exceeded latency budget"`)
+	assert.Contains(t, buf.String(), `"budget":"1ms"`)
+	assert.Contains(t, buf.String(), `"request_id":"rid"`)
+}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"fmt"
 	"net/http"
+	"sync/atomic"
 )
 
 // Router is the registry of all registered routes for an `Echo` instance for
@@ -15,6 +16,9 @@ type Router struct {
 	tree   *node
 	routes map[string]*Route
 	echo   *Echo
+	// hasDeprecatedRoutes is set when any route of the router has been marked deprecated so requests to routers
+	// without deprecated routes skip the route lookup.
+	hasDeprecatedRoutes atomic.Bool
 }
 
 type node struct {
@@ -41,6 +45,7 @@ type children []*node
 
 type routeMethod struct {
 	handler HandlerFunc
+	options *routeOptions
 	ppath   string
 	pnames  []string
 }
@@ -86,6 +91,21 @@ func (m *routeMethods) isHandler() bool {
 	// RouteNotFound/404 is not considered as a handler
 }
 
+// groupMiddlewares returns group middlewares of the first route of the node that was added to a Group with middlewares.
+func (m *routeMethods) groupMiddlewares() []MiddlewareFunc {
+	for _, rm := range []*routeMethod{m.get, m.post, m.put, m.patch, m.delete, m.head, m.connect, m.propfind, m.trace, m.report} {
+		if rm != nil && rm.options != nil && len(rm.options.groupMiddlewares) > 0 {
+			return rm.options.groupMiddlewares
+		}
+	}
+	for _, rm := range m.anyOther {
+		if rm.options != nil && len(rm.options.groupMiddlewares) > 0 {
+			return rm.options.groupMiddlewares
+		}
+	}
+	return nil
+}
+
 func (m *routeMethods) updateAllowHeader() {
 	buf := new(bytes.Buffer)
 	buf.WriteString(http.MethodOptions)
@@ -184,6 +204,21 @@ func (r *Router) Reverse(name string, params ...interface{}) string {
 	return uri.String()
 }
 
+// matchedRoute returns registered route that was matched for the request. HEAD requests fall back to GET route.
+// Returns nil when no route matched.
+func (r *Router) matchedRoute(c *context) *Route {
+	if c.path == "" {
+		return nil
+	}
+	if route, ok := r.routes[c.request.Method+c.path]; ok {
+		return route
+	}
+	if c.request.Method == http.MethodHead {
+		return r.routes[http.MethodGet+c.path]
+	}
+	return nil
+}
+
 func normalizePathSlash(path string) string {
 	if path == "" {
 		path = "/"
@@ -194,25 +229,33 @@ func normalizePathSlash(path string) string {
 }
 
 func (r *Router) add(method, path, name string, h HandlerFunc) *Route {
+	return r.addWithOptions(method, path, name, h, nil)
+}
+
+func (r *Router) addWithOptions(method, path, name string, h HandlerFunc, options *routeOptions) *Route {
 	path = normalizePathSlash(path)
-	r.insert(method, path, h)
+	r.insert(method, path, h, options)
 
 	route := &Route{
 		Method: method,
 		Path:   path,
 		Name:   name,
 	}
+	registerRouteMeta(route, r, options)
 	r.routes[method+path] = route
 	return route
 }
 
 // Add registers a new route for method and path with matching handler.
 func (r *Router) Add(method, path string, h HandlerFunc) {
-	r.insert(method, normalizePathSlash(path), h)
+	r.insert(method, normalizePathSlash(path), h, nil)
 }
 
-func (r *Router) insert(method, path string, h HandlerFunc) {
+func (r *Router) insert(method, path string, h HandlerFunc, options *routeOptions) {
 	path = normalizePathSlash(path)
+	if name, ok := duplicateParamName(path); ok {
+		panic(fmt.Errorf("echo: invalid route %s %s: path param %q is declared more than once", method, path, name))
+	}
 	pnames := []string{} // Param names
 	ppath := path        // Pristine path
 
@@ -241,18 +284,53 @@ func (r *Router) insert(method, path string, h HandlerFunc) {
 
 			if i == lcpIndex {
 				// path node is last fragment of route path. ie. `/users/:id`
-				r.insertNode(method, path[:i], paramKind, routeMethod{ppath: ppath, pnames: pnames, handler: h})
+				r.insertNode(method, path[:i], paramKind, routeMethod{ppath: ppath, pnames: pnames, handler: h, options: options})
 			} else {
 				r.insertNode(method, path[:i], paramKind, routeMethod{})
 			}
 		} else if path[i] == '*' {
 			r.insertNode(method, path[:i], staticKind, routeMethod{})
 			pnames = append(pnames, "*")
-			r.insertNode(method, path[:i+1], anyKind, routeMethod{ppath: ppath, pnames: pnames, handler: h})
+			r.insertNode(method, path[:i+1], anyKind, routeMethod{ppath: ppath, pnames: pnames, handler: h, options: options})
+		}
+	}
+
+	r.insertNode(method, path, staticKind, routeMethod{ppath: ppath, pnames: pnames, handler: h, options: options})
+}
+
+// routeParamNames returns names of path params declared in route path in the same order as Router assigns values to
+// them. Wildcard is named `*`.
+func routeParamNames(path string) []string {
+	var names []string
+	for i := 0; i < len(path); i++ {
+		switch {
+		case path[i] == ':' && (i == 0 || path[i-1] != '\\'):
+			j := i + 1
+			for i < len(path) && path[i] != '/' {
+				i++
+			}
+			names = append(names, path[j:i])
+		case path[i] == '*':
+			names = append(names, "*")
 		}
 	}
+	return names
+}
 
-	r.insertNode(method, path, staticKind, routeMethod{ppath: ppath, pnames: pnames, handler: h})
+// duplicateParamName returns name of the path param that is declared more than once in the route path (ala
+// `/users/:id/files/:id` created by group prefix and route path having the same param).
+func duplicateParamName(path string) (string, bool) {
+	seen := map[string]bool{}
+	for _, name := range routeParamNames(path) {
+		if name == "*" {
+			continue
+		}
+		if seen[name] {
+			return name, true
+		}
+		seen[name] = true
+	}
+	return "", false
 }
 
 func (r *Router) insertNode(method, path string, t kind, rm routeMethod) {
@@ -531,6 +609,14 @@ func optionsMethodHandler(allowMethods string) func(c Context) error {
 	}
 }
 
+// markRouteNotFound marks (in debug mode) that request did not match any route so error handler can suggest similar
+// routes.
+func (r *Router) markRouteNotFound(ctx *context) {
+	if r.echo != nil && r.echo.Debug {
+		ctx.Set(contextKeyRouteNotFound, true)
+	}
+}
+
 // Find lookup a handler registered for method and path. It also parses URL for path
 // parameters and load them into context.
 //
@@ -540,7 +626,10 @@ func optionsMethodHandler(allowMethods string) func(c Context) error {
 // - Reset it `Context#Reset()`
 // - Return it `Echo#ReleaseContext()`.
 func (r *Router) Find(method, path string, c Context) {
-	ctx := c.(*context)
+	ctx, ok := unwrapContext(c)
+	if !ok {
+		panic("echo: Router#Find requires context created by Echo")
+	}
 	currentNode := r.tree // Current node as root
 
 	var (
@@ -618,6 +707,7 @@ func (r *Router) Find(method, path string, c Context) {
 			// No matching prefix, let's backtrack to the first possible alternative node of the decision path
 			nk, ok := backtrackToNextNodeKind(staticKind)
 			if !ok {
+				r.markRouteNotFound(ctx)
 				return // No other possibilities on the decision path, handler will be whatever context is reset to.
 			} else if nk == paramKind {
 				goto Param
@@ -724,9 +814,17 @@ func (r *Router) Find(method, path string, c Context) {
 	}
 
 	if currentNode == nil && previousBestMatchNode == nil {
+		r.markRouteNotFound(ctx)
 		return // nothing matched at all
 	}
 
+	// OPTIONS request to path that has routes is answered by automatic OPTIONS handler (with `Allow` header) instead of
+	// RouteNotFound handler of the parent any node (ala catch-all route of Group with middlewares)
+	if method == http.MethodOptions && previousBestMatchNode != nil && previousBestMatchNode.isHandler &&
+		matchedRouteMethod != nil && currentNode != nil && matchedRouteMethod == currentNode.notFoundHandler {
+		matchedRouteMethod = nil
+	}
+
 	// matchedHandler could be method+path handler that we matched or notFoundHandler from node with matching path
 	// user provided not found (404) handler has priority over generic method not found (405) handler or global 404 handler
 	var rPath string
@@ -735,6 +833,7 @@ func (r *Router) Find(method, path string, c Context) {
 		rPath = matchedRouteMethod.ppath
 		rPNames = matchedRouteMethod.pnames
 		ctx.handler = matchedRouteMethod.handler
+		ctx.routeOptions = matchedRouteMethod.options
 	} else {
 		// use previous match as basis. although we have no matching handler we have path match.
 		// so we can send http.StatusMethodNotAllowed (405) instead of http.StatusNotFound (404)
@@ -747,12 +846,19 @@ func (r *Router) Find(method, path string, c Context) {
 			rPath = currentNode.notFoundHandler.ppath
 			rPNames = currentNode.notFoundHandler.pnames
 			ctx.handler = currentNode.notFoundHandler.handler
+			ctx.routeOptions = currentNode.notFoundHandler.options
 		} else if currentNode.isHandler {
 			ctx.Set(ContextKeyHeaderAllow, currentNode.methods.allowHeader)
 			ctx.handler = MethodNotAllowedHandler
 			if method == http.MethodOptions {
 				ctx.handler = optionsMethodHandler(currentNode.methods.allowHeader)
+				if middlewares := currentNode.methods.groupMiddlewares(); len(middlewares) > 0 {
+					// let group level middlewares (ala CORS) answer preflight requests to routes of the group
+					ctx.handler = applyMiddleware(ctx.handler, middlewares...)
+				}
 			}
+		} else {
+			r.markRouteNotFound(ctx)
 		}
 	}
 	ctx.path = rPath
@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxRouteSuggestions is maximum number of routes suggested for request that did not match any route.
+const maxRouteSuggestions = 3
+
+// maxFuzzySegmentLength is maximum length of path segment that is compared with edit distance.
+const maxFuzzySegmentLength = 32
+
+// contextKeyRouteNotFound is set by Router (in debug mode) when request did not match any route so the default error
+// handler can tell router 404 from 404 returned by a handler.
+const contextKeyRouteNotFound = "echo_route_not_found"
+
+type routeSuggestion struct {
+	route string
+	score int
+}
+
+// suggestRoutes returns up to 3 registered routes (formatted as "METHOD /path") that are most similar to the request
+// path. Paths are compared segment by segment: equal static segment scores 3, segment matched by a path param 2,
+// static segment with a small typo (ala `usres` for `users`) 1. Every missing or extra segment costs 1 and route
+// registered for the request method gets a bonus point. Routes without positive score are not suggested.
+func (r *Router) suggestRoutes(method, path string) []string {
+	reqSegments := strings.Split(strings.Trim(path, "/"), "/")
+
+	suggestions := make([]routeSuggestion, 0, maxRouteSuggestions+1)
+	for _, route := range r.routes {
+		if route.Method == RouteNotFound {
+			continue
+		}
+		score := routeSimilarity(reqSegments, strings.Split(strings.Trim(route.Path, "/"), "/"))
+		if score <= 0 {
+			continue
+		}
+		if route.Method == method {
+			score++
+		}
+		suggestions = append(suggestions, routeSuggestion{route: route.Method + " " + route.Path, score: score})
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].score != suggestions[j].score {
+			return suggestions[i].score > suggestions[j].score
+		}
+		return suggestions[i].route < suggestions[j].route
+	})
+
+	result := make([]string, 0, maxRouteSuggestions)
+	for i := 0; i < len(suggestions) && i < maxRouteSuggestions; i++ {
+		result = append(result, suggestions[i].route)
+	}
+	return result
+}
+
+func routeSimilarity(reqSegments []string, routeSegments []string) int {
+	score := 0
+	matched := 0
+	wildcard := false
+	for i, routeSegment := range routeSegments {
+		if strings.HasPrefix(routeSegment, "*") {
+			// wildcard matches rest of the path
+			if i < len(reqSegments) {
+				score += 2
+			}
+			wildcard = true
+			break
+		}
+		if i >= len(reqSegments) {
+			score--
+			continue
+		}
+		reqSegment := reqSegments[i]
+		switch {
+		case routeSegment == reqSegment:
+			score += 3
+			matched++
+		case strings.HasPrefix(routeSegment, ":") && reqSegment != "":
+			score += 2
+		case isSegmentTypo(reqSegment, routeSegment):
+			score++
+			matched++
+		}
+	}
+	if matched == 0 {
+		// routes consisting only of params and wildcards (ala `/:id`) match anything and are not helpful suggestions
+		return 0
+	}
+	if !wildcard && len(reqSegments) > len(routeSegments) {
+		score -= len(reqSegments) - len(routeSegments)
+	}
+	return score
+}
+
+// isSegmentTypo returns true when segments differ by at most 1 edit (2 for segments of 5 or more characters).
+func isSegmentTypo(a, b string) bool {
+	if a == "" || b == "" || len(a) > maxFuzzySegmentLength || len(b) > maxFuzzySegmentLength {
+		return false
+	}
+	maxDistance := 1
+	if len(b) >= 5 {
+		maxDistance = 2
+	}
+	if len(a)-len(b) > maxDistance || len(b)-len(a) > maxDistance {
+		return false
+	}
+	return editDistance(strings.ToLower(a), strings.ToLower(b)) <= maxDistance
+}
+
+// editDistance returns Levenshtein distance of two strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j-1] + cost
+			if prev[j]+1 < curr[j] {
+				curr[j] = prev[j] + 1
+			}
+			if curr[j-1]+1 < curr[j] {
+				curr[j] = curr[j-1] + 1
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
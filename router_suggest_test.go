@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter_suggestRoutes(t *testing.T) {
+	e := New()
+	h := func(c Context) error { return nil }
+	e.GET("/", h)
+	e.GET("/users", h)
+	e.GET("/users/:id", h)
+	e.PUT("/users/:id", h)
+	e.GET("/users/:id/orders", h)
+	e.GET("/orders/:id", h)
+	e.GET("/static/*", h)
+	e.GET("/:slug", h)
+	e.RouteNotFound("/*", h)
+
+	var testCases = []struct {
+		name       string
+		whenMethod string
+		whenPath   string
+		expect     []string
+	}{
+		{
+			name:       "typo in static segment",
+			whenMethod: http.MethodGet,
+			whenPath:   "/usres/1",
+			expect:     []string{"GET /users/:id", "GET /users/:id/orders", "PUT /users/:id"},
+		},
+		{
+			name:       "extra segment",
+			whenMethod: http.MethodGet,
+			whenPath:   "/users/1/order",
+			expect:     []string{"GET /users/:id/orders", "GET /users/:id", "PUT /users/:id"},
+		},
+		{
+			name:       "request method is preferred",
+			whenMethod: http.MethodPut,
+			whenPath:   "/user/1",
+			expect:     []string{"PUT /users/:id", "GET /users/:id", "GET /users/:id/orders"},
+		},
+		{
+			name:       "wildcard",
+			whenMethod: http.MethodGet,
+			whenPath:   "/statics/app.js",
+			expect:     []string{"GET /static/*"},
+		},
+		{
+			name:       "nothing similar",
+			whenMethod: http.MethodGet,
+			whenPath:   "/completely/different/path",
+			expect:     []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, e.router.suggestRoutes(tc.whenMethod, tc.whenPath))
+		})
+	}
+}
+
+func TestEcho_notFoundSuggestions(t *testing.T) {
+	var testCases = []struct {
+		name       string
+		givenDebug bool
+		whenURL    string
+		expectBody string
+	}{
+		{
+			name:       "debug, router 404 has suggestions",
+			givenDebug: true,
+			whenURL:    "/usres/1",
+			expectBody: "{\n  \"error\": \"code=404, message=Not Found\",\n  \"message\": \"Not Found\",\n  \"suggestions\": [\n    \"GET /users/:id\"\n  ]\n}\n",
+		},
+		{
+			name:       "debug, handler 404 has no suggestions",
+			givenDebug: true,
+			whenURL:    "/users/404",
+			expectBody: "{\n  \"error\": \"code=404, message=Not Found\",\n  \"message\": \"Not Found\"\n}\n",
+		},
+		{
+			name:       "production, router 404 is unchanged",
+			givenDebug: false,
+			whenURL:    "/usres/1",
+			expectBody: `{"message":"Not Found"}` + "\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := New()
+			e.Debug = tc.givenDebug
+			e.GET("/users/:id", func(c Context) error {
+				return ErrNotFound
+			})
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.whenURL, nil))
+
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+			assert.Equal(t, tc.expectBody, rec.Body.String())
+		})
+	}
+}
+
+func TestEcho_methodNotAllowedHasAllowedMethods(t *testing.T) {
+	e := New()
+	h := func(c Context) error { return nil }
+	e.GET("/users", h)
+	e.POST("/users", h)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/users", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "OPTIONS, GET, POST", rec.Header().Get(HeaderAllow))
+	assert.Equal(t, `{"allowed_methods":["OPTIONS","GET","POST"],"message":"Method Not Allowed"}`+"\n", rec.Body.String())
+}
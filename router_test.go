@@ -2778,6 +2778,20 @@ func TestRouter_addEmptyPathToSlashReverse(t *testing.T) {
 	assert.Equal(t, "/", r.Reverse("empty"))
 }
 
+func TestRouter_addDuplicateParamNamePanics(t *testing.T) {
+	e := New()
+	g := e.Group("/users/:id")
+
+	assert.PanicsWithError(t, `echo: invalid route GET /users/:id/files/:id: path param "id" is declared more than once`, func() {
+		g.GET("/files/:id", handlerFunc)
+	})
+	assert.NotPanics(t, func() {
+		g.GET("/files/:fileID", handlerFunc)
+		e.GET("/escaped/:id/\\:id", handlerFunc)
+		e.GET("/users/*/action*", handlerFunc)
+	})
+}
+
 func TestRouter_ReverseNotFound(t *testing.T) {
 	e := New()
 	r := e.router
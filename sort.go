@@ -0,0 +1,215 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// SortField is single field of SortParams.
+type SortField struct {
+	// Field is name of the field to sort by.
+	Field string
+	// Desc is true for descending order.
+	Desc bool
+}
+
+// SortParams is list of fields to sort by parsed from comma separated list of field names with optional `-`
+// (descending) or `+` (ascending) prefix (ala `?sort=-created_at,+name`). Field names can contain letters, digits,
+// `_` and `.`.
+//
+// Allowed fields are checked with `SortParams#Validate` or, when bound by DefaultBinder, against comma separated list
+// of fields in `sort` struct tag of the field. Fields that are not allowed result "400 - Bad Request" error.
+//
+// Example:
+//
+//	type listUsersRequest struct {
+//		Sort echo.SortParams `query:"sort" sort:"created_at,name"`
+//	}
+//
+//	// in handler
+//	orderBy, err := req.Sort.OrderBy(map[string]string{"created_at": "u.created_at", "name": "u.name"})
+type SortParams []SortField
+
+// SortFieldsError is returned when sort fields are not allowed.
+type SortFieldsError struct {
+	// Fields are names of the fields that are not allowed.
+	Fields []string
+}
+
+// Error returns message naming fields that are not allowed.
+func (e *SortFieldsError) Error() string {
+	return fmt.Sprintf("sorting by %s is not allowed", quoteJoin(e.Fields))
+}
+
+// UnmarshalParam parses comma separated list of sort fields. Leading space is treated as `+` prefix because `+` of
+// unescaped query decodes to space.
+func (s *SortParams) UnmarshalParam(param string) error {
+	result := SortParams{}
+	seen := map[string]bool{}
+	var invalid []string
+	for _, part := range strings.Split(param, ",") {
+		part = strings.TrimSpace(part)
+		f := SortField{}
+		switch {
+		case strings.HasPrefix(part, "-"):
+			f.Desc = true
+			part = part[1:]
+		case strings.HasPrefix(part, "+"):
+			part = part[1:]
+		}
+		if !isSortFieldName(part) {
+			invalid = append(invalid, part)
+			continue
+		}
+		if seen[part] {
+			return fmt.Errorf("sort field %q is given more than once", part)
+		}
+		seen[part] = true
+		f.Field = part
+		result = append(result, f)
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid sort field name %s", quoteJoin(invalid))
+	}
+	*s = result
+	return nil
+}
+
+// String returns canonical form of sort params (ala `-created_at,name`) that can be used as query param value and is
+// parsed back to the same SortParams by UnmarshalParam.
+func (s SortParams) String() string {
+	var sb strings.Builder
+	for i, f := range s {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		if f.Desc {
+			sb.WriteByte('-')
+		}
+		sb.WriteString(f.Field)
+	}
+	return sb.String()
+}
+
+// Validate checks that all fields are in allowed list. Returns "400 - Bad Request" error with `*SortFieldsError` as
+// internal error naming the fields that are not allowed.
+func (s SortParams) Validate(allowed ...string) error {
+	var invalid []string
+	for _, f := range s {
+		if !containsString(allowed, f.Field) {
+			invalid = append(invalid, f.Field)
+		}
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	err := &SortFieldsError{Fields: invalid}
+	return NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+}
+
+// OrderBy returns SQL `ORDER BY` clause content (ala `u.created_at DESC, u.name ASC`) with columns mapped from sort
+// fields. Only columns from the map are used so the result is safe to be concatenated into SQL query. Fields missing
+// from columns result error. Empty SortParams result empty string.
+func (s SortParams) OrderBy(columns map[string]string) (string, error) {
+	var sb strings.Builder
+	var invalid []string
+	for i, f := range s {
+		column, ok := columns[f.Field]
+		if !ok {
+			invalid = append(invalid, f.Field)
+			continue
+		}
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(column)
+		if f.Desc {
+			sb.WriteString(" DESC")
+		} else {
+			sb.WriteString(" ASC")
+		}
+	}
+	if len(invalid) > 0 {
+		return "", &SortFieldsError{Fields: invalid}
+	}
+	return sb.String(), nil
+}
+
+var sortParamsType = reflect.TypeOf(SortParams{})
+
+// validateSortFields validates SortParams fields of destination, that are bound with tag, against allowed fields
+// listed in their `sort` struct tag. SortParams fields without `sort` tag are not validated.
+func validateSortFields(destination interface{}, tag string, data map[string][]string) error {
+	if destination == nil {
+		return nil
+	}
+	val := reflect.ValueOf(destination)
+	if val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	val = val.Elem()
+	for i := 0; i < val.NumField(); i++ {
+		typeField := val.Type().Field(i)
+		if typeField.Type != sortParamsType {
+			continue
+		}
+		allowed, ok := typeField.Tag.Lookup("sort")
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(typeField.Tag.Get(tag), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		if strings.IndexByte(name, '|') != -1 {
+			// report the alternative name the value was bound from
+			names := strings.Split(name, "|")
+			name = names[0]
+			for _, alias := range names {
+				if _, ok := data[alias]; ok {
+					name = alias
+					break
+				}
+			}
+		}
+		if err := val.Field(i).Interface().(SortParams).Validate(strings.Split(allowed, ",")...); err != nil {
+			return newBindFieldError(tag, name, strings.Join(data[name], ","), err.(*HTTPError).Internal)
+		}
+	}
+	return nil
+}
+
+func isSortFieldName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteJoin(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: MIT
+// SPDX-FileCopyrightText: © 2015 LabStack LLC and Echo contributors
+
+package echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortParams_UnmarshalParam(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenParam   string
+		expect      SortParams
+		expectError string
+	}{
+		{
+			name:      "ok",
+			whenParam: "-created_at,+name,user.id",
+			expect:    SortParams{{Field: "created_at", Desc: true}, {Field: "name"}, {Field: "user.id"}},
+		},
+		{
+			name:      "ok, plus decoded to space",
+			whenParam: " name, -id",
+			expect:    SortParams{{Field: "name"}, {Field: "id", Desc: true}},
+		},
+		{
+			name:        "nok, invalid names",
+			whenParam:   "name,,id;drop table",
+			expectError: `invalid sort field name "", "id;drop table"`,
+		},
+		{
+			name:        "nok, duplicate field",
+			whenParam:   "name,-name",
+			expectError: `sort field "name" is given more than once`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var s SortParams
+			err := s.UnmarshalParam(tc.whenParam)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, s)
+
+			var roundTrip SortParams
+			assert.NoError(t, roundTrip.UnmarshalParam(s.String()))
+			assert.Equal(t, s, roundTrip)
+		})
+	}
+}
+
+func TestSortParams_String(t *testing.T) {
+	s := SortParams{{Field: "created_at", Desc: true}, {Field: "name"}}
+	assert.Equal(t, "-created_at,name", s.String())
+	assert.Equal(t, "", SortParams{}.String())
+}
+
+func TestSortParams_Validate(t *testing.T) {
+	s := SortParams{{Field: "created_at", Desc: true}, {Field: "password"}, {Field: "secret"}}
+
+	assert.NoError(t, s[:1].Validate("created_at", "name"))
+
+	err := s.Validate("created_at", "name")
+	var sortErr *SortFieldsError
+	assert.ErrorAs(t, err, &sortErr)
+	assert.Equal(t, []string{"password", "secret"}, sortErr.Fields)
+	assert.Equal(t, http.StatusBadRequest, err.(*HTTPError).Code)
+	assert.Equal(t, `sorting by "password", "secret" is not allowed`, err.(*HTTPError).Message)
+}
+
+func TestSortParams_OrderBy(t *testing.T) {
+	columns := map[string]string{"created_at": "u.created_at", "name": "u.name"}
+
+	orderBy, err := SortParams{{Field: "created_at", Desc: true}, {Field: "name"}}.OrderBy(columns)
+	assert.NoError(t, err)
+	assert.Equal(t, "u.created_at DESC, u.name ASC", orderBy)
+
+	orderBy, err = SortParams{}.OrderBy(columns)
+	assert.NoError(t, err)
+	assert.Equal(t, "", orderBy)
+
+	_, err = SortParams{{Field: "name"}, {Field: "id"}}.OrderBy(columns)
+	assert.EqualError(t, err, `sorting by "id" is not allowed`)
+}
+
+type sortTestRequest struct {
+	Sort SortParams `query:"sort" sort:"created_at,name"`
+	Page int        `query:"page"`
+}
+
+func TestDefaultBinder_bindSortParams(t *testing.T) {
+	var testCases = []struct {
+		name        string
+		whenURL     string
+		expect      SortParams
+		expectError string
+	}{
+		{
+			name:    "ok",
+			whenURL: "/?sort=-created_at,%2Bname&page=2",
+			expect:  SortParams{{Field: "created_at", Desc: true}, {Field: "name"}},
+		},
+		{
+			name:    "ok, without sort",
+			whenURL: "/?page=2",
+		},
+		{
+			name:        "nok, not allowed field",
+			whenURL:     "/?sort=name,password",
+			expectError: `code=400, message=sorting by "password" is not allowed, internal=sorting by "password" is not allowed`,
+		},
+		{
+			name:        "nok, invalid field",
+			whenURL:     "/?sort=name%21",
+			expectError: `code=400, message=invalid sort field name "name!", internal=invalid sort field name "name!"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New().NewContext(httptest.NewRequest(http.MethodGet, tc.whenURL, nil), httptest.NewRecorder())
+
+			var req sortTestRequest
+			err := c.Bind(&req)
+			if tc.expectError != "" {
+				assert.EqualError(t, err, tc.expectError)
+				var fieldErr *BindFieldError
+				assert.ErrorAs(t, err, &fieldErr)
+				assert.Equal(t, "sort", fieldErr.Name)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, req.Sort)
+			assert.Equal(t, 2, req.Page)
+		})
+	}
+}
+
+func TestDefaultBinder_bindSortParams_withoutSortTag(t *testing.T) {
+	c := New().NewContext(httptest.NewRequest(http.MethodGet, "/?sort=-password", nil), httptest.NewRecorder())
+
+	var req struct {
+		Sort SortParams `query:"sort"`
+	}
+	err := c.Bind(&req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, SortParams{{Field: "password", Desc: true}}, req.Sort)
+}